@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/Smana/scai/internal/analyzer"
+	"github.com/Smana/scai/internal/console"
+	"github.com/Smana/scai/internal/llm"
+	"github.com/Smana/scai/internal/types"
+)
+
+var analyzeCmd = &cobra.Command{
+	Use:   "analyze <repository_url_zip_or_directory>",
+	Short: "Show what SCAI detects about a repository, without deploying it",
+	Long: `Analyze a repository and print everything detected about the application (framework,
+language, package manager, dependencies, port, start command, environment variables, Docker
+files) with the deployment strategy the rules, the LLM or the heuristics recommend, and why.
+
+Nothing is deployed and no AWS call is made: use it to check a detection before deploying.
+Without an available LLM, the strategy comes from the deployment rules or the heuristics.
+
+Example:
+  scia analyze https://github.com/user/flask-app
+  scia analyze ./api --app-dir services/api --json
+  scia analyze --prompt "Deploy on Kubernetes" https://github.com/user/flask-app`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAnalyze,
+}
+
+func init() {
+	rootCmd.AddCommand(analyzeCmd)
+
+	analyzeCmd.Flags().Bool("json", false, "Output as JSON")
+	analyzeCmd.Flags().String("prompt", "", "Deployment request the strategy is recommended for, as given to deploy")
+	analyzeCmd.Flags().String("app-dir", "", "Application directory to analyze in a monorepo (relative to the repository root)")
+	addRulesFlags(analyzeCmd)
+}
+
+// analyzeResult is the JSON output of analyze
+type analyzeResult struct {
+	Analysis *types.Analysis
+	Strategy *llm.StrategyDecision
+}
+
+func runAnalyze(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+	repoSource := args[0]
+	verbose := viper.GetBool("verbose")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+	userPrompt, _ := cmd.Flags().GetString("prompt")
+	ctx := cmd.Context()
+
+	if err := analyzer.ValidateSource(ctx, repoSource); err != nil {
+		return err
+	}
+
+	// The LLM is optional: the rules and the heuristics decide without it
+	providerManager, providerConfig, err := initializeLLMProvider(ctx, verbose)
+	if err != nil {
+		providerManager, providerConfig = nil, nil
+		if !jsonOutput {
+			fmt.Fprintln(console.Stdout, "⚠️  No LLM available, the strategy is recommended by the deployment rules or the heuristics")
+			if verbose {
+				fmt.Fprintf(console.Stdout, "   %v\n", err)
+			}
+		}
+	}
+	llmClient := llm.NewClientWithManager(providerManager, providerConfig)
+	if err := applyCustomRules(cmd, llmClient, verbose); err != nil {
+		return err
+	}
+
+	workDir := viper.GetString("workdir.path")
+	if err := os.MkdirAll(workDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create work directory: %w", err)
+	}
+	if !jsonOutput {
+		fmt.Fprintln(console.Stdout, "📊 Analyzing repository...")
+	}
+	repoAnalyzer := analyzer.NewAnalyzer(workDir, verbose)
+	repoAnalyzer.SetMaxDepth(viper.GetInt("analyzer.max_depth"))
+	repoAnalyzer.SetContext(ctx)
+	repoAnalyzer.SetIgnoreDirs(viper.GetStringSlice("analyzer.ignore_dirs"))
+	repoAnalyzer.SetZipLimits(zipLimits())
+	if appDir, _ := cmd.Flags().GetString("app-dir"); appDir != "" {
+		repoAnalyzer.SetAppDir(appDir)
+	}
+	analysis, err := repoAnalyzer.Analyze(repoSource)
+	if err != nil {
+		return fmt.Errorf("repository analysis failed: %w", err)
+	}
+
+	decision, err := llmClient.StrategyDecider().DecideStrategy(ctx, userPrompt, analysis)
+	if err != nil {
+		return fmt.Errorf("failed to determine strategy: %w", err)
+	}
+
+	if jsonOutput {
+		data, err := json.MarshalIndent(analyzeResult{Analysis: analysis, Strategy: decision}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Fprintln(console.Stdout, string(data))
+		return nil
+	}
+
+	fmt.Fprintln(console.Stdout)
+	if err := pterm.DefaultTable.WithHasHeader().WithData(analysisTable(analysis)).Render(); err != nil {
+		return err
+	}
+	if analysis.BindsLocalhost {
+		pterm.Warning.Println("The application listens on localhost only: it will not be reachable once deployed")
+	}
+
+	pterm.DefaultSection.Println("Recommended strategy")
+	pterm.Printf("   Strategy:   %s\n", decision.Strategy)
+	pterm.Printf("   Source:     %s (%.0f%% confidence)\n", decision.Source, decision.Confidence*100)
+	if decision.RuleName != "" {
+		pterm.Printf("   Rule:       %s\n", decision.RuleName)
+	}
+	if decision.Reason != "" {
+		pterm.Printf("   Reason:     %s\n", decision.Reason)
+	}
+	return nil
+}
+
+// analysisTable returns the rows of the analyze table: every field of analysis
+func analysisTable(analysis *types.Analysis) pterm.TableData {
+	orNone := func(value string) string {
+		if value == "" {
+			return "-"
+		}
+		return value
+	}
+	yesNo := func(value bool) string {
+		if value {
+			return "yes"
+		}
+		return "no"
+	}
+
+	port := strconv.Itoa(analysis.Port)
+	if !analysis.PortDetected {
+		port += " (framework default)"
+	}
+	envVars := make([]string, 0, len(analysis.EnvVars))
+	for name := range analysis.EnvVars {
+		envVars = append(envVars, name)
+	}
+	slices.Sort(envVars)
+	composePorts := make([]string, len(analysis.ComposePorts))
+	for i, composePort := range analysis.ComposePorts {
+		composePorts[i] = strconv.Itoa(composePort)
+	}
+	dockerfilePorts := make([]string, len(analysis.DockerfilePorts))
+	for i, dockerfilePort := range analysis.DockerfilePorts {
+		dockerfilePorts[i] = strconv.Itoa(dockerfilePort)
+	}
+
+	return pterm.TableData{
+		{"Field", "Detected"},
+		{"Repository", orNone(analysis.RepoURL)},
+		{"Commit", orNone(analysis.CommitSHA)},
+		{"App directory", orNone(analysis.AppDir)},
+		{"Framework", orNone(llm.DescribeFramework(analysis))},
+		{"Language", orNone(analysis.Language)},
+		{"Package manager", orNone(analysis.PackageManager)},
+		{"Dependencies", orNone(strings.Join(analysis.Dependencies, ", "))},
+		{"Port", port},
+		{"Start command", orNone(analysis.StartCommand)},
+		{"Build command", orNone(analysis.BuildCommand)},
+		{"Entry point", orNone(analysis.EntryPoint)},
+		{"Health check", orNone(analysis.HealthCheckPath)},
+		{"Environment variables", orNone(strings.Join(envVars, ", "))},
+		{"Dockerfile", yesNo(analysis.HasDockerfile)},
+		{"Dockerfile ports", orNone(strings.Join(dockerfilePorts, ", "))},
+		{"docker-compose", yesNo(analysis.HasDockerCompose)},
+		{"Compose services", orNone(strings.Join(analysis.ComposeServices, ", "))},
+		{"Compose ports", orNone(strings.Join(composePorts, ", "))},
+		{"Database", yesNo(analysis.RequiresDatabase)},
+		{"Cache", yesNo(analysis.RequiresCache)},
+	}
+}
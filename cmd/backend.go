@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/Smana/scai/internal/backend"
+	"github.com/Smana/scai/internal/cloud"
+	"github.com/Smana/scai/internal/console"
+	"github.com/Smana/scai/internal/pulumi"
+	"github.com/Smana/scai/internal/store"
+	"github.com/Smana/scai/internal/terraform"
+)
+
+var backendCmd = &cobra.Command{
+	Use:   "backend",
+	Short: "Manage the Terraform state backend of deployments",
+}
+
+var backendMigrateCmd = &cobra.Command{
+	Use:   "migrate <deployment-id>",
+	Short: "Move the Terraform state of a deployment to the configured S3 backend",
+	Long: `Regenerate the backend.tf of a deployment from the current configuration
+(terraform.backend.s3_bucket and s3_region of ~/.scai.yaml) and run terraform init -migrate-state
+to copy its state to the new backend, e.g. after moving the state bucket.
+
+The state of the previous backend is copied, not deleted. If the migration fails, the previous
+backend.tf is restored.
+
+Example:
+  scia backend migrate abc123de-f456-7890-abcd-ef1234567890
+  scia backend migrate abc123de --key apps/web/terraform.tfstate --yes`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBackendMigrate,
+}
+
+func init() {
+	rootCmd.AddCommand(backendCmd)
+	backendCmd.AddCommand(backendMigrateCmd)
+
+	// Migrate-specific flags
+	backendMigrateCmd.Flags().BoolP("yes", "y", false, "Auto-approve the migration without confirmation prompt")
+	backendMigrateCmd.Flags().String("key", "", "State key in the new bucket (default: the current key of the deployment)")
+}
+
+func runBackendMigrate(cmd *cobra.Command, args []string) error {
+	if globalStore == nil {
+		return fmt.Errorf("database not initialized")
+	}
+
+	ctx := context.Background()
+	deploymentID := args[0]
+	verbose := viper.GetBool("verbose")
+
+	deployment, err := globalStore.Get(ctx, deploymentID)
+	if err != nil {
+		return fmt.Errorf("failed to get deployment: %w", err)
+	}
+	switch deployment.Status {
+	case store.DeploymentStatusDestroyed:
+		return fmt.Errorf("deployment %s is destroyed: it has no state to migrate", deploymentID)
+	case store.DeploymentStatusPlanned:
+		return fmt.Errorf("deployment %s was exported with --plan-out and never applied by scai: its backend is managed in %s", deploymentID, deployment.PlanOutDir)
+	}
+	if pulumiDeployment(deployment) {
+		return fmt.Errorf("deployment %s is deployed with Pulumi: its state is in the Pulumi backend (PULUMI_BACKEND_URL, default %s)", deploymentID, pulumi.DefaultBackendURL)
+	}
+	if deployment.TerraformDir == "" {
+		return fmt.Errorf("terraform directory not found in deployment record")
+	}
+	if _, err := os.Stat(deployment.TerraformDir); err != nil {
+		return fmt.Errorf("terraform directory of deployment %s is missing: %w", deploymentID, err)
+	}
+
+	// The state cannot be migrated while a deploy or destroy is writing it
+	if err := globalStore.Lock(ctx, deploymentID, store.LockOwner()); err != nil {
+		return err
+	}
+	defer func() { _ = globalStore.Unlock(ctx, deploymentID, store.LockOwner()) }()
+
+	target := backend.BackendTFConfig{
+		BucketName: viper.GetString("terraform.backend.s3_bucket"),
+		Region:     viper.GetString("terraform.backend.s3_region"),
+		Key:        deployment.TerraformStateKey,
+		RoleARN:    cloud.RoleARN(),
+	}
+	if key, _ := cmd.Flags().GetString("key"); key != "" {
+		target.Key = key
+	}
+	if target.BucketName == "" || target.Region == "" {
+		return fmt.Errorf("S3 backend not configured: set terraform.backend.s3_bucket and s3_region (or run 'scia init')")
+	}
+	if target.Key == "" {
+		target.Key = fmt.Sprintf("deployments/%s/terraform.tfstate", deploymentID)
+	}
+
+	current, hasBackend, err := backend.ReadBackendTF(deployment.TerraformDir)
+	if err != nil {
+		return err
+	}
+	if hasBackend && current == target {
+		pterm.Info.Printf("Deployment %s already uses s3://%s/%s (%s)\n", deploymentID, target.BucketName, target.Key, target.Region)
+		return nil
+	}
+
+	// Display the migration
+	from := "local state (terraform.tfstate)"
+	if hasBackend {
+		from = fmt.Sprintf("s3://%s/%s (%s)", current.BucketName, current.Key, current.Region)
+	}
+	fmt.Fprintln(console.Stdout)
+	fmt.Fprintf(console.Stdout, "   Deployment:   %s (%s)\n", deployment.ID, deployment.AppName)
+	fmt.Fprintf(console.Stdout, "   From:         %s\n", from)
+	fmt.Fprintf(console.Stdout, "   To:           s3://%s/%s (%s)\n", target.BucketName, target.Key, target.Region)
+	fmt.Fprintln(console.Stdout)
+
+	// Get confirmation unless --yes flag is set
+	autoApprove, _ := cmd.Flags().GetBool("yes")
+	if !autoApprove {
+		pterm.Warning.Println("Moving Terraform state is risky: make sure no other terraform run uses this deployment")
+		pterm.Println()
+
+		response, err := pterm.DefaultInteractiveTextInput.
+			WithDefaultText("Type 'yes' to confirm").
+			Show()
+		if err != nil {
+			return fmt.Errorf("failed to read input: %w", err)
+		}
+
+		if strings.ToLower(strings.TrimSpace(response)) != "yes" {
+			pterm.Info.Println("Migration canceled")
+			return nil
+		}
+		pterm.Println()
+	}
+
+	// Keep the previous backend.tf to restore it if the migration fails
+	backendFile := backend.BackendTFPath(deployment.TerraformDir)
+	previous, err := os.ReadFile(backendFile)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read backend.tf: %w", err)
+	}
+	restore := func() {
+		if previous == nil {
+			_ = os.Remove(backendFile)
+			return
+		}
+		_ = os.WriteFile(backendFile, previous, 0o600)
+	}
+
+	if _, err := backend.WriteBackendTF(deployment.TerraformDir, target); err != nil {
+		return err
+	}
+
+	pterm.Info.Println("Migrating Terraform state...")
+	executor, err := terraform.NewExecutor(deployment.TerraformDir, viper.GetString("terraform.bin"), verbose)
+	if err != nil {
+		restore()
+		return fmt.Errorf("failed to create terraform executor: %w", err)
+	}
+	if err := executor.InitMigrateState(); err != nil {
+		restore()
+		return fmt.Errorf("terraform init -migrate-state failed (previous backend.tf restored): %w", err)
+	}
+
+	deployment.TerraformStateKey = target.Key
+	if err := globalStore.Update(ctx, deployment); err != nil {
+		return fmt.Errorf("state migrated but failed to update deployment record: %w", err)
+	}
+	message := fmt.Sprintf("Backend migrated to s3://%s/%s", target.BucketName, target.Key)
+	if err := globalStore.AddEvent(ctx, deploymentID, deployment.Status, message); err != nil && verbose {
+		pterm.Warning.Printf("Failed to record deployment event: %v\n", err)
+	}
+
+	pterm.Success.Printf("State of deployment %s migrated to s3://%s/%s\n", deploymentID, target.BucketName, target.Key)
+	if hasBackend {
+		pterm.Info.Printf("The previous state is still in s3://%s/%s: delete it once the migration is verified\n", current.BucketName, current.Key)
+	}
+	return nil
+}
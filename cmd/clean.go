@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/Smana/scai/internal/deployer"
+	"github.com/Smana/scai/internal/store"
+)
+
+var cleanCmd = &cobra.Command{
+	Use:   "clean",
+	Short: "Remove the work directory files scai no longer needs",
+	Long: `Remove the Terraform directories of the destroyed deployments (and of the ones exported
+with --plan-out) and the repositories cloned or extracted in the work directory.
+
+The Terraform directories of the other deployments are kept: destroy runs in them.
+
+Example:
+  scia clean`,
+	Args: cobra.NoArgs,
+	RunE: runClean,
+}
+
+func init() {
+	rootCmd.AddCommand(cleanCmd)
+}
+
+func runClean(cmd *cobra.Command, args []string) error {
+	if globalStore == nil {
+		return fmt.Errorf("database not initialized")
+	}
+
+	ctx := context.Background()
+	verbose := viper.GetBool("verbose")
+
+	deployments, err := globalStore.List(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to list deployments: %w", err)
+	}
+
+	count := 0
+	for _, deployment := range deployments {
+		if deployment.Status != store.DeploymentStatusDestroyed && deployment.Status != store.DeploymentStatusPlanned {
+			continue
+		}
+		removed, err := deployer.RemoveTerraformDir(deployment)
+		if err != nil {
+			return err
+		}
+		if removed {
+			count++
+			if verbose {
+				pterm.Debug.Printf("Removed %s (%s)\n", deployment.TerraformDir, deployment.Status)
+			}
+		}
+	}
+
+	sources, err := deployer.CleanupSources(viper.GetString("workdir.path"))
+	if err != nil {
+		return err
+	}
+	if verbose {
+		for _, dir := range sources {
+			pterm.Debug.Printf("Removed %s\n", dir)
+		}
+	}
+
+	if count == 0 && len(sources) == 0 {
+		pterm.Info.Println("Nothing to clean")
+		return nil
+	}
+	pterm.Success.Printf("Removed %d Terraform and %d source directories\n", count, len(sources))
+	return nil
+}
@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/huh"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+
+	"github.com/Smana/scai/internal/config"
+	"github.com/Smana/scai/internal/console"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage the SCAI configuration file",
+}
+
+var configEncryptCmd = &cobra.Command{
+	Use:   "encrypt",
+	Short: "Protect the API keys of the configuration file",
+	Long: `Protect the Gemini and OpenAI API keys of ~/.scai.yaml (including profiles).
+
+With --method keyring (default), the keys are moved to the OS keyring (macOS Keychain,
+Windows Credential Manager, Secret Service on Linux) and the file only references them.
+With --method passphrase, the keys are encrypted in the file with AES-256-GCM and a key
+derived from a passphrase, read from SCAI_CONFIG_PASSPHRASE or prompted for.
+
+Protected keys are resolved transparently when deploying.
+
+Example:
+  scia config encrypt
+  scia config encrypt --method passphrase`,
+	Args: cobra.NoArgs,
+	RunE: runConfigEncrypt,
+}
+
+var configDecryptCmd = &cobra.Command{
+	Use:   "decrypt",
+	Short: "Store the API keys of the configuration file in cleartext again",
+	Long: `Replace the protected API keys of ~/.scai.yaml (keyring references or encrypted
+values) with their cleartext.
+
+Example:
+  scia config decrypt`,
+	Args: cobra.NoArgs,
+	RunE: runConfigDecrypt,
+}
+
+var configSchemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print the JSON Schema of the configuration file",
+	Long: `Print a JSON Schema of ~/.scai.yaml, generated from the configuration structure, for
+editors to validate and autocomplete the file when editing it by hand.
+
+With the YAML language server (VS Code YAML extension, Neovim, Helix...), save the schema
+and reference it from the first line of the configuration file:
+  # yaml-language-server: $schema=/path/to/scai.schema.json
+
+Example:
+  scia config schema > ~/.scai.schema.json`,
+	Args: cobra.NoArgs,
+	RunE: runConfigSchema,
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configEncryptCmd, configDecryptCmd, configSchemaCmd)
+
+	configEncryptCmd.Flags().String("method", config.SecretMethodKeyring, "How API keys are protected: keyring or passphrase")
+}
+
+func runConfigEncrypt(cmd *cobra.Command, args []string) error {
+	method, _ := cmd.Flags().GetString("method")
+
+	cfg, err := config.ReadConfig()
+	if err != nil {
+		return err
+	}
+
+	count, err := config.EncryptSecrets(cfg, method, configPassphrase(true))
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		pterm.Info.Println("No cleartext API keys to protect")
+		return nil
+	}
+
+	if err := config.WriteConfig(cfg); err != nil {
+		return fmt.Errorf("failed to write configuration: %w", err)
+	}
+	pterm.Success.Printf("Protected %d API key(s) with %s\n", count, method)
+	return nil
+}
+
+func runConfigDecrypt(cmd *cobra.Command, args []string) error {
+	cfg, err := config.ReadConfig()
+	if err != nil {
+		return err
+	}
+
+	count, err := config.DecryptSecrets(cfg, configPassphrase(false))
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		pterm.Info.Println("No protected API keys to decrypt")
+		return nil
+	}
+
+	if err := config.WriteConfig(cfg); err != nil {
+		return fmt.Errorf("failed to write configuration: %w", err)
+	}
+	pterm.Success.Printf("Stored %d API key(s) in cleartext\n", count)
+	return nil
+}
+
+func runConfigSchema(cmd *cobra.Command, args []string) error {
+	schema, err := json.MarshalIndent(config.JSONSchema(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode the configuration schema: %w", err)
+	}
+	fmt.Fprintln(console.Stdout, string(schema))
+	return nil
+}
+
+// configPassphrase returns the passphrase of encrypted API keys from SCAI_CONFIG_PASSPHRASE,
+// or prompts for it (twice when confirm is set, to avoid encrypting with a typo)
+func configPassphrase(confirm bool) config.PassphraseFunc {
+	return func() (string, error) {
+		if passphrase := os.Getenv(config.PassphraseEnv); passphrase != "" {
+			return passphrase, nil
+		}
+
+		var passphrase, confirmation string
+		fields := []huh.Field{
+			huh.NewInput().
+				Title("Config passphrase").
+				Description("Set " + config.PassphraseEnv + " to skip this prompt").
+				Value(&passphrase).
+				EchoMode(huh.EchoModePassword).
+				Validate(func(s string) error {
+					if s == "" {
+						return fmt.Errorf("passphrase is required")
+					}
+					return nil
+				}),
+		}
+		if confirm {
+			fields = append(fields, huh.NewInput().
+				Title("Confirm passphrase").
+				Value(&confirmation).
+				EchoMode(huh.EchoModePassword))
+		}
+
+		if err := huh.NewForm(huh.NewGroup(fields...)).Run(); err != nil {
+			return "", fmt.Errorf("failed to read passphrase: %w", err)
+		}
+		if confirm && passphrase != confirmation {
+			return "", fmt.Errorf("passphrases do not match")
+		}
+		return passphrase, nil
+	}
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+
+	"github.com/Smana/scai/internal/console"
+	"github.com/Smana/scai/internal/store"
+)
+
+var deleteCmd = &cobra.Command{
+	Use:   "delete <deployment-id> [deployment-id...]",
+	Short: "Delete deployment records from the local database",
+	Long: `Delete deployment records from the local database.
+This does NOT destroy any AWS resources: use 'scia destroy' for that first.
+
+Only destroyed or failed deployments can be deleted, unless --force is given
+(deleting the record of a live deployment leaves its infrastructure untracked).
+Records imported by 'scia reconcile' have no Terraform directory to destroy from
+and can always be deleted, once their resources are cleaned up manually.
+
+Example:
+  scia delete abc123de-f456-7890-abcd-ef1234567890
+  scia delete abc123de-f456-7890-abcd-ef1234567890 0f1e2d3c-b4a5-6789-0abc-def123456789 --yes`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runDelete,
+}
+
+func init() {
+	rootCmd.AddCommand(deleteCmd)
+
+	// Delete-specific flags
+	deleteCmd.Flags().BoolP("yes", "y", false, "Auto-approve delete without confirmation prompt")
+	deleteCmd.Flags().Bool("force", false, "Also delete records of deployments that are not destroyed or failed")
+}
+
+func runDelete(cmd *cobra.Command, args []string) error {
+	if globalStore == nil {
+		return fmt.Errorf("database not initialized")
+	}
+
+	ctx := context.Background()
+	force, _ := cmd.Flags().GetBool("force")
+
+	// Check every record before deleting any
+	deployments := make([]*store.Deployment, 0, len(args))
+	defer func() {
+		for _, deployment := range deployments {
+			_ = globalStore.Unlock(ctx, deployment.ID, store.LockOwner())
+		}
+	}()
+	for _, id := range args {
+		deployment, err := globalStore.Get(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get deployment %s: %w", id, err)
+		}
+
+		// Records without a Terraform directory (imported by reconcile) cannot be destroyed by scai
+		if !force && deployment.Status != store.DeploymentStatusDestroyed && deployment.Status != store.DeploymentStatusFailed &&
+			deployment.Status != store.DeploymentStatusPlanned && deployment.TerraformDir != "" {
+			return fmt.Errorf("deployment %s is %s: destroy it first with 'scia destroy %s', or use --force to delete the record anyway (its AWS resources will no longer be tracked)",
+				id, deployment.Status, id)
+		}
+
+		// A record still used by a deploy or destroy cannot be deleted
+		if err := globalStore.Lock(ctx, id, store.LockOwner()); err != nil {
+			return err
+		}
+		deployments = append(deployments, deployment)
+	}
+
+	// Display the records to delete
+	fmt.Fprintln(console.Stdout)
+	tableData := pterm.TableData{{"ID", "App Name", "Strategy", "Region", "Status"}}
+	for _, deployment := range deployments {
+		tableData = append(tableData, []string{
+			deployment.ID,
+			deployment.AppName,
+			deployment.Strategy,
+			deployment.Region,
+			string(deployment.Status),
+		})
+	}
+	if err := pterm.DefaultTable.WithHasHeader().WithData(tableData).Render(); err != nil {
+		return fmt.Errorf("failed to render table: %w", err)
+	}
+	fmt.Fprintln(console.Stdout)
+	pterm.Info.Println("Only the local records are deleted: AWS resources are not touched")
+	for _, deployment := range deployments {
+		if deployment.TerraformDir == "" && deployment.Status != store.DeploymentStatusDestroyed && deployment.Status != store.DeploymentStatusPlanned {
+			pterm.Warning.Printf("%s was imported by 'scia reconcile': delete its resources manually (ARNs listed by 'scia show %s')\n", deployment.ID, deployment.ID)
+		}
+	}
+
+	// Get confirmation unless --yes flag is set
+	autoApprove, _ := cmd.Flags().GetBool("yes")
+	if !autoApprove {
+		pterm.Println()
+		response, err := pterm.DefaultInteractiveTextInput.
+			WithDefaultText(fmt.Sprintf("Type 'yes' to delete %d record(s)", len(deployments))).
+			Show()
+		if err != nil {
+			return fmt.Errorf("failed to read input: %w", err)
+		}
+
+		if strings.ToLower(strings.TrimSpace(response)) != "yes" {
+			pterm.Info.Println("Delete canceled")
+			return nil
+		}
+		pterm.Println()
+	}
+
+	for _, deployment := range deployments {
+		if err := globalStore.Delete(ctx, deployment.ID); err != nil {
+			return fmt.Errorf("failed to delete deployment %s: %w", deployment.ID, err)
+		}
+		pterm.Success.Printf("Deleted deployment record %s (%s)\n", deployment.ID, deployment.AppName)
+	}
+
+	return nil
+}
@@ -2,17 +2,26 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
+	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
 	"github.com/Smana/scai/internal/analyzer"
+	"github.com/Smana/scai/internal/cloud"
+	"github.com/Smana/scai/internal/config"
+	"github.com/Smana/scai/internal/console"
 	"github.com/Smana/scai/internal/deployer"
 	"github.com/Smana/scai/internal/llm"
 	"github.com/Smana/scai/internal/parser"
+	"github.com/Smana/scai/internal/store"
+	"github.com/Smana/scai/internal/terraform"
+	"github.com/Smana/scai/internal/types"
 	"github.com/Smana/scai/internal/ui"
 )
 
@@ -25,7 +34,7 @@ const (
 )
 
 var deployCmd = &cobra.Command{
-	Use:   "deploy [prompt] [repository_url_or_zip]",
+	Use:   "deploy [prompt] [repository_url_zip_or_directory]",
 	Short: "Deploy an application to AWS",
 	Long: `SCAI (Smart Cloud Infrastructure Automation) analyzes code repositories,
 determines optimal deployment strategies using AI, and automatically provisions
@@ -33,8 +42,12 @@ infrastructure using Terraform.
 
 Example:
   scai deploy "Deploy this Flask app on AWS" https://github.com/user/flask-app
-  scai deploy "Deploy microservices" /path/to/app.zip`,
-	Args: cobra.ExactArgs(2),
+  scai deploy "Deploy microservices" /path/to/app.zip
+  scai deploy "Deploy my working copy" ./my-app
+  scai deploy -y --plan-out ./infra "Deploy this Flask app on AWS" https://github.com/user/flask-app
+
+Run without arguments in a terminal to be asked for the repository and the description.`,
+	Args: deployArgs,
 	RunE: runDeploy,
 }
 
@@ -45,15 +58,42 @@ func init() {
 	deployCmd.Flags().String("strategy", "", "Force deployment strategy (vm, kubernetes, serverless)")
 	deployCmd.Flags().String("region", "", "AWS region (overrides config)")
 	deployCmd.Flags().BoolP("yes", "y", false, "Auto-approve deployment without confirmation prompt")
+	deployCmd.Flags().StringArray("set", nil, "Override a plan parameter as key=value, e.g. ec2_instance_type=t3.large (repeatable)")
+	deployCmd.Flags().StringArray("tag", nil, "Tag applied to all AWS resources as key=value (repeatable)")
+	deployCmd.Flags().String("domain", "", "Custom domain served over HTTPS (requires a Route53 hosted zone)")
+	deployCmd.Flags().String("app-dir", "", "Application directory to deploy in a monorepo (relative to the repository root)")
+	deployCmd.Flags().Bool("strict-prompt", false, "Stop when the LLM misparses the prompt configuration (invalid response, stated values dropped) instead of ignoring it, offering to re-prompt the model")
+	deployCmd.Flags().Bool("strict-budget", false, "Refuse to deploy when the estimated monthly cost exceeds the budget given in the prompt (e.g. \"under $50/month\")")
+	deployCmd.Flags().Bool("destroy-on-failure", false, "Destroy the partially created resources when terraform apply fails (rollback, e.g. in CI)")
+	deployCmd.Flags().Int("apply-retries", -1, "Re-runs of terraform apply failing on a transient AWS error, e.g. an IAM role not propagated yet (default: 2 for kubernetes, 1 otherwise)")
+	deployCmd.Flags().Bool("keep-workdir", false, "Keep the cloned repository in the work directory after a successful deploy (default: workdir.retain)")
+	deployCmd.Flags().Bool("clean-workdir", false, "Remove the cloned repository from the work directory after a successful deploy (default: unless workdir.retain)")
+	deployCmd.Flags().Duration("timeout", 0, "Stop the deployment after this duration, e.g. 45m (default: no limit)")
+	deployCmd.MarkFlagsMutuallyExclusive("keep-workdir", "clean-workdir")
+	addRulesFlags(deployCmd)
+	deployCmd.Flags().String("plan-format", ui.PlanFormatTable, "Plan output: table, or json printed to stdout (other output on stderr) and deployed only with --yes")
+	deployCmd.Flags().String("plan-out", "", "Export the generated Terraform files (backend.tf included) to this directory instead of applying them")
+	deployCmd.Flags().String("aws-provider-version", "", "AWS provider version constraint written to versions.tf, e.g. \"~> 6.12\" (default: terraform.aws_provider_version or "+terraform.DefaultAWSProviderVersion+")")
 
 	// EC2 sizing parameters
-	deployCmd.Flags().String("ec2-instance-type", "", "EC2 instance type (default: t3.micro)")
+	deployCmd.Flags().String("ec2-instance-type", "", "EC2 instance type (default: defaults.ec2_instance_type or t3.micro)")
 	deployCmd.Flags().Int("ec2-volume-size", 30, "EC2 root volume size in GB")
 
+	// SSH access to the EC2 instances (none by default: SSM Session Manager only)
+	deployCmd.Flags().String("key-name", "", "Existing EC2 key pair allowed to SSH into the instances (vm only)")
+	deployCmd.Flags().Bool("generate-key", false, "Create a key pair for SSH access, the private key saved to ~/.scai/keys/<deployment-id>.pem (vm only)")
+	deployCmd.MarkFlagsMutuallyExclusive("key-name", "generate-key")
+
 	// Lambda sizing parameters
 	deployCmd.Flags().Int("lambda-memory", 512, "Lambda memory in MB (128-10240)")
 	deployCmd.Flags().Int("lambda-timeout", 30, "Lambda timeout in seconds (1-900)")
 	deployCmd.Flags().Int("lambda-reserved-concurrency", 0, "Lambda reserved concurrent executions (0 = unreserved)")
+	deployCmd.Flags().String("lambda-arch", "x86_64", "Lambda architecture (x86_64 or arm64)")
+	deployCmd.Flags().Bool("lambda-container", false, "Deploy Lambda as a container image built and pushed to ECR instead of a zip")
+	deployCmd.Flags().StringSlice("cors-origin", nil, "API Gateway CORS allowed origins, comma-separated, e.g. https://app.example.com (default: terraform.api_gateway.cors_allow_origins or *, serverless only)")
+	deployCmd.Flags().StringSlice("cors-method", nil, "API Gateway CORS allowed methods, comma-separated (default: terraform.api_gateway.cors_allow_methods or *, serverless only)")
+	deployCmd.Flags().StringSlice("cors-header", nil, "API Gateway CORS allowed headers, comma-separated (default: terraform.api_gateway.cors_allow_headers or *, serverless only)")
+	deployCmd.Flags().String("authorizer-arn", "", "Lambda authorizer of the API Gateway routes (default: terraform.api_gateway.authorizer_arn, serverless only)")
 
 	// EKS sizing parameters
 	deployCmd.Flags().String("eks-node-type", "t3.medium", "EKS node instance type")
@@ -61,56 +101,181 @@ func init() {
 	deployCmd.Flags().Int("eks-max-nodes", 3, "EKS maximum number of nodes")
 	deployCmd.Flags().Int("eks-desired-nodes", 2, "EKS desired number of nodes")
 	deployCmd.Flags().Int("eks-node-volume-size", 30, "EKS node volume size in GB")
+	deployCmd.Flags().Bool("eks-fargate", false, "Run EKS pods on a Fargate profile instead of a managed node group")
+	deployCmd.Flags().String("eks-version", "", "EKS Kubernetes version (default: terraform.eks.version or "+terraform.DefaultEKSVersion+")")
+	deployCmd.Flags().StringSlice("eks-addons", terraform.DefaultEKSAddons, "EKS managed add-ons, comma-separated (aws-ebs-csi-driver also installs eks-pod-identity-agent)")
+	deployCmd.Flags().StringArray("eks-addon-version", nil, "Pin an EKS add-on version as name=version, e.g. coredns=v1.12.1-eksbuild.2 (repeatable, default: most recent)")
+	deployCmd.Flags().String("ami", "", "Custom AMI of the instances, e.g. a hardened golden image, instead of the latest Amazon Linux 2023 (vm only)")
+	deployCmd.Flags().String("user-data", "", "File with the user-data script of the instances, replacing the generated bootstrap (vm only)")
+	deployCmd.Flags().String("vpc-id", "", "Existing VPC to deploy into instead of the default VPC (vm) or a new VPC (kubernetes)")
+	deployCmd.Flags().StringSlice("subnet-ids", nil, "Existing subnets of --vpc-id, comma-separated (default: all the subnets of the VPC)")
+	deployCmd.Flags().StringArray("app-policy-arn", nil, "IAM policy of the application pods, bound to their service account with EKS Pod Identity (IRSA on Fargate) (repeatable, kubernetes only)")
+	deployCmd.Flags().StringArray("eks-admin-arn", nil, "IAM user or role granted cluster admin with an EKS access entry, besides the identity deploying (repeatable, kubernetes only)")
+	deployCmd.Flags().String("nat-gateway", terraform.NATGatewaySingle, "EKS VPC NAT gateways: single (cheapest), per-az (no single point of failure) or none (nodes in public subnets)")
+	addK8sResourceFlags(deployCmd)
+
+	// RDS database parameters
+	deployCmd.Flags().String("with-database", "", "Provision an RDS database (postgres, mysql)")
+	deployCmd.Flags().String("db-instance-class", "db.t3.micro", "RDS instance class")
+	deployCmd.Flags().Int("db-storage", 20, "RDS allocated storage in GB")
+
+	// CloudWatch Logs
+	deployCmd.Flags().Int("log-retention-days", 0, "Retention in days of the CloudWatch log groups (serverless and kubernetes): 1, 3, 5, 7, 14, 30, 60, 90, 180, 365, ... (default: 7 for Lambda, 90 for the EKS control plane)")
+
+	// Sizing defaults can be set in the config file; flags still override them
+	for key, flag := range sizingDefaultFlags {
+		_ = viper.BindPFlag("defaults."+key, deployCmd.Flags().Lookup(flag))
+	}
 }
 
-func runDeploy(cmd *cobra.Command, args []string) error {
-	userPrompt := args[0]
-	repoSource := args[1]
+// sizingDefaultFlags maps the defaults.* config keys to the deploy flags they provide
+// defaults for (the flag defaults are the built-in fallbacks)
+var sizingDefaultFlags = map[string]string{
+	"ec2_instance_type":           "ec2-instance-type",
+	"ec2_volume_size":             "ec2-volume-size",
+	"lambda_memory":               "lambda-memory",
+	"lambda_timeout":              "lambda-timeout",
+	"lambda_reserved_concurrency": "lambda-reserved-concurrency",
+	"lambda_architecture":         "lambda-arch",
+	"eks_node_type":               "eks-node-type",
+	"eks_min_nodes":               "eks-min-nodes",
+	"eks_max_nodes":               "eks-max-nodes",
+	"eks_desired_nodes":           "eks-desired-nodes",
+	"eks_node_volume_size":        "eks-node-volume-size",
+	"db_instance_class":           "db-instance-class",
+	"db_storage":                  "db-storage",
+	"log_retention_days":          "log-retention-days",
+}
+
+func runDeploy(cmd *cobra.Command, args []string) (err error) {
+	var userPrompt, repoSource string
+	if len(args) == 2 {
+		userPrompt, repoSource = args[0], args[1]
+	} else if userPrompt, repoSource, err = promptDeployArgs(args); err != nil {
+		return err
+	}
 
 	// Get configuration
 	verbose := viper.GetBool("verbose")
 
+	// JSON events replace the interactive plan confirmation
+	if autoApprove, _ := cmd.Flags().GetBool("yes"); console.JSON() && !autoApprove {
+		return fmt.Errorf("--log-format json requires --yes (the plan cannot be confirmed interactively)")
+	}
+
+	// A JSON plan keeps stdout to itself
+	planFormat, _ := cmd.Flags().GetString("plan-format")
+	switch planFormat {
+	case ui.PlanFormatTable:
+	case ui.PlanFormatJSON:
+		if console.JSON() {
+			return fmt.Errorf("--plan-format json cannot be combined with --log-format json: both write to stdout")
+		}
+		console.SetStderr(true)
+	default:
+		return fmt.Errorf("invalid --plan-format %q: expected table or json", planFormat)
+	}
+
+	// Ctrl-C, SIGTERM and --timeout stop the deployment, terraform included
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	ctx, stop := operationContext(timeout)
+	defer stop()
+
+	// Report the phase that failed as a JSON event
+	phase := "setup"
+	var d *deployer.Deployer
+	defer func() {
+		err = canceledError(ctx, err)
+		if err != nil {
+			event := console.Event{Phase: phase, Status: console.StatusFailed, Message: err.Error()}
+			if d != nil {
+				event.DeploymentID = d.DeploymentID()
+			}
+			console.Emit(event)
+		}
+	}()
+
+	// Fail fast on a repository that cannot be fetched, before any LLM or AWS work
+	if verbose {
+		fmt.Fprintf(console.Stdout, "🔍 Checking repository %s...\n", repoSource)
+	}
+	if err := analyzer.ValidateSource(ctx, repoSource); err != nil {
+		return err
+	}
+
 	// Initialize LLM provider
-	providerManager, providerConfig, err := initializeLLMProvider(verbose)
+	providerManager, providerConfig, err := initializeLLMProvider(ctx, verbose)
 	if err != nil {
 		return err
 	}
 
 	// Create LLM client from the configured provider manager
 	llmClient := llm.NewClientWithManager(providerManager, providerConfig)
+	if err := applyCustomRules(cmd, llmClient, verbose); err != nil {
+		return err
+	}
 
 	// Parse natural language prompt for configuration using LLM
 	var parsedConfig *parser.DeploymentConfig
-	parsedConfig, err = parser.ParseConfigFromPrompt(llmClient, userPrompt)
-	if err != nil && verbose {
-		fmt.Printf("Warning: Could not parse prompt configuration: %v\n", err)
+	parsedConfig, err = parsePromptConfig(ctx, cmd, llmClient, userPrompt)
+	if err != nil {
+		return err
 	}
 
 	if verbose && parsedConfig != nil {
-		fmt.Println("🔍 Detected configuration from prompt:")
+		fmt.Fprintln(console.Stdout, "🔍 Detected configuration from prompt:")
 		if parsedConfig.Strategy != "" {
-			fmt.Printf("   Strategy: %s\n", parsedConfig.Strategy)
+			fmt.Fprintf(console.Stdout, "   Strategy: %s\n", parsedConfig.Strategy)
 		}
 		if parsedConfig.Region != "" {
-			fmt.Printf("   Region: %s\n", parsedConfig.Region)
+			fmt.Fprintf(console.Stdout, "   Region: %s\n", parsedConfig.Region)
 		}
 		if parsedConfig.EC2InstanceType != "" {
-			fmt.Printf("   EC2 Instance: %s\n", parsedConfig.EC2InstanceType)
+			fmt.Fprintf(console.Stdout, "   EC2 Instance: %s\n", parsedConfig.EC2InstanceType)
 		}
 		if parsedConfig.EKSNodeType != "" {
-			fmt.Printf("   EKS Node Type: %s\n", parsedConfig.EKSNodeType)
+			fmt.Fprintf(console.Stdout, "   EKS Node Type: %s\n", parsedConfig.EKSNodeType)
+		}
+		if parsedConfig.EKSFargate {
+			fmt.Fprintf(console.Stdout, "   EKS Compute: Fargate\n")
 		}
 		if parsedConfig.EKSDesiredNodes > 0 {
-			fmt.Printf("   EKS Nodes: %d (min: %d, max: %d)\n", parsedConfig.EKSDesiredNodes, parsedConfig.EKSMinNodes, parsedConfig.EKSMaxNodes)
+			fmt.Fprintf(console.Stdout, "   EKS Nodes: %d (min: %d, max: %d)\n", parsedConfig.EKSDesiredNodes, parsedConfig.EKSMinNodes, parsedConfig.EKSMaxNodes)
+		}
+		if parsedConfig.Replicas > 0 {
+			fmt.Fprintf(console.Stdout, "   Replicas: %d\n", parsedConfig.Replicas)
+		}
+		if parsedConfig.AutoscaleTargetCPU > 0 {
+			fmt.Fprintf(console.Stdout, "   Autoscaling: %d%% CPU\n", parsedConfig.AutoscaleTargetCPU)
+		}
+		if parsedConfig.BudgetUSD > 0 {
+			fmt.Fprintf(console.Stdout, "   Budget: $%.0f/month\n", parsedConfig.BudgetUSD)
 		}
-		fmt.Println()
+		if parsedConfig.SizingHint != "" {
+			fmt.Fprintf(console.Stdout, "   Sizing: %s\n", parsedConfig.SizingHint)
+		}
+		fmt.Fprintln(console.Stdout)
 	}
 
 	// Get remaining configuration
-	workDir := viper.GetString("workdir")
+	workDir := viper.GetString("workdir.path")
 	awsRegion := viper.GetString("cloud.default_region")
 	tfBin := viper.GetString("terraform.bin")
 
+	// Resource tags: config defaults first, --tag flags override
+	tagFlags, _ := cmd.Flags().GetStringArray("tag")
+	tags, err := parseTags(viper.GetStringMapString("cloud.default_tags"), tagFlags)
+	if err != nil {
+		return err
+	}
+
+	// Deterministic overrides from --set (same parameter names the LLM extracts)
+	setPairs, _ := cmd.Flags().GetStringArray("set")
+	setConfig, err := parser.ParseSetOverrides(setPairs)
+	if err != nil {
+		return err
+	}
+
 	// Override with parsed config (natural language takes precedence)
 	if parsedConfig.Region != "" {
 		awsRegion = parsedConfig.Region
@@ -120,15 +285,35 @@ func runDeploy(cmd *cobra.Command, args []string) error {
 	if region, _ := cmd.Flags().GetString("region"); region != "" {
 		awsRegion = region
 	}
+	if setConfig.Region != "" {
+		awsRegion = setConfig.Region
+	}
 
 	if verbose {
-		fmt.Printf("🚀 SCAI Deployment Starting...\n")
-		fmt.Printf("   User Prompt: %s\n", userPrompt)
-		fmt.Printf("   Repository: %s\n", repoSource)
-		fmt.Printf("   Work Directory: %s\n", workDir)
-		fmt.Printf("   AWS Region: %s\n", awsRegion)
-		fmt.Printf("   Terraform Binary: %s\n", tfBin)
-		fmt.Println()
+		fmt.Fprintf(console.Stdout, "🚀 SCAI Deployment Starting...\n")
+		fmt.Fprintf(console.Stdout, "   User Prompt: %s\n", userPrompt)
+		fmt.Fprintf(console.Stdout, "   Repository: %s\n", repoSource)
+		fmt.Fprintf(console.Stdout, "   Work Directory: %s\n", workDir)
+		fmt.Fprintf(console.Stdout, "   AWS Region: %s\n", awsRegion)
+		fmt.Fprintf(console.Stdout, "   Terraform Binary: %s\n", tfBin)
+		// The state bucket can be in another region than the deployment
+		if viper.GetString("terraform.backend.type") == "s3" {
+			fmt.Fprintf(console.Stdout, "   State Bucket: %s (%s)\n",
+				viper.GetString("terraform.backend.s3_bucket"), viper.GetString("terraform.backend.s3_region"))
+		}
+		fmt.Fprintln(console.Stdout)
+	}
+
+	// One AWS client for the pre-flight checks: without credentials they are skipped, and
+	// Terraform reports the errors instead
+	awsClient, awsClientErr := cloud.NewAWSClient(ctx)
+	if awsClientErr != nil && verbose {
+		fmt.Fprintf(console.Stdout, "Warning: Could not create AWS client, AWS checks are skipped: %v\n", awsClientErr)
+	}
+
+	// Fail early on a region typo (e.g. eu-west-33) or a region not enabled for the account
+	if err := validateRegion(ctx, awsClient, awsRegion, verbose); err != nil {
+		return err
 	}
 
 	// Create work directory
@@ -137,73 +322,138 @@ func runDeploy(cmd *cobra.Command, args []string) error {
 	}
 
 	// Step 1: Analyze repository
-	fmt.Println("📊 Analyzing repository...")
+	phase = "analyze"
+	console.Emit(console.Event{Phase: phase, Status: console.StatusStarted, Message: repoSource})
+	fmt.Fprintln(console.Stdout, "📊 Analyzing repository...")
 	analyzer := analyzer.NewAnalyzer(workDir, verbose)
+	analyzer.SetMaxDepth(viper.GetInt("analyzer.max_depth"))
+	analyzer.SetContext(ctx)
+	analyzer.SetIgnoreDirs(viper.GetStringSlice("analyzer.ignore_dirs"))
+	analyzer.SetZipLimits(zipLimits())
+
+	// Monorepos: use --app-dir, otherwise prompt when several apps are found (unless --yes)
+	if appDir, _ := cmd.Flags().GetString("app-dir"); appDir != "" {
+		analyzer.SetAppDir(appDir)
+	} else if autoApprove, _ := cmd.Flags().GetBool("yes"); !autoApprove {
+		analyzer.SetAppSelector(ui.SelectApp)
+	}
+
 	analysis, err := analyzer.Analyze(repoSource)
 	if err != nil {
 		return fmt.Errorf("repository analysis failed: %w", err)
 	}
 
 	if verbose {
-		fmt.Printf("   Framework: %s\n", analysis.Framework)
-		fmt.Printf("   Language: %s\n", analysis.Language)
-		fmt.Printf("   Port: %d\n", analysis.Port)
-		fmt.Printf("   Dependencies: %d\n", len(analysis.Dependencies))
-		fmt.Printf("   Docker: %v\n", analysis.HasDockerfile)
-		fmt.Println()
+		fmt.Fprintf(console.Stdout, "   Framework: %s\n", llm.DescribeFramework(analysis))
+		fmt.Fprintf(console.Stdout, "   App Directory: %s\n", analysis.AppDir)
+		fmt.Fprintf(console.Stdout, "   Language: %s\n", analysis.Language)
+		fmt.Fprintf(console.Stdout, "   Port: %d\n", analysis.Port)
+		fmt.Fprintf(console.Stdout, "   Health Check: %s\n", analysis.HealthCheckPath)
+		fmt.Fprintf(console.Stdout, "   Dependencies: %d\n", len(analysis.Dependencies))
+		fmt.Fprintf(console.Stdout, "   Docker: %v\n", analysis.HasDockerfile)
+		if len(analysis.ComposeServices) > 0 {
+			fmt.Fprintf(console.Stdout, "   Compose Services: %s\n", strings.Join(analysis.ComposeServices, ", "))
+		}
+		fmt.Fprintln(console.Stdout)
 	}
 
+	console.Emit(console.Event{Phase: phase, Status: console.StatusSucceeded, Data: map[string]any{
+		"framework":         analysis.Framework,
+		"framework_version": analysis.FrameworkVersion,
+		"language":          analysis.Language,
+		"port":              analysis.Port,
+		"health_check_path": analysis.HealthCheckPath,
+		"app_dir":           analysis.AppDir,
+	}})
+
 	// Step 2: Determine deployment strategy
-	fmt.Println("🤖 Determining deployment strategy...")
+	phase = "strategy"
+	fmt.Fprintln(console.Stdout, "🤖 Determining deployment strategy...")
 
 	var strategy string
+	var decision *llm.StrategyDecision
 	forcedStrategy, _ := cmd.Flags().GetString("strategy")
+	if setConfig.Strategy != "" {
+		forcedStrategy = setConfig.Strategy
+	}
 
 	// Check if strategy was specified in natural language
 	if parsedConfig != nil && parsedConfig.Strategy != "" && forcedStrategy == "" {
 		strategy = parsedConfig.Strategy
-		fmt.Printf("   Strategy from prompt: %s\n", strategy)
+		fmt.Fprintf(console.Stdout, "   Strategy from prompt: %s\n", strategy)
 	} else if forcedStrategy != "" {
 		strategy = forcedStrategy
-		fmt.Printf("   Using forced strategy: %s\n", strategy)
+		fmt.Fprintf(console.Stdout, "   Using forced strategy: %s\n", strategy)
 	} else {
-		// Use LLM client to determine strategy based on code analysis
-		strategy, err = llmClient.DetermineStrategy(parsedConfig.CleanedPrompt, analysis)
+		// Rules, then the LLM, then heuristics decide based on code analysis
+		decision, err = decideStrategy(ctx, llmClient.StrategyDecider(), parsedConfig.CleanedPrompt, analysis)
 		if err != nil {
-			return fmt.Errorf("failed to determine strategy: %w", err)
+			return err
 		}
-		fmt.Printf("   Recommended strategy: %s\n", strategy)
+		strategy = decision.Strategy
+	}
+	fmt.Fprintln(console.Stdout)
+
+	strategyData := map[string]any{"strategy": strategy}
+	if decision != nil {
+		strategyData["source"] = decision.Source
+		strategyData["confidence"] = decision.Confidence
 	}
-	fmt.Println()
+	console.Emit(console.Event{Phase: phase, Status: console.StatusSucceeded, Data: strategyData})
 
 	// Extract app name for deployment plan
+	phase = "plan"
 	appName := extractAppName(repoSource)
 
 	// Step 2.5: Build deployment plan and get confirmation
-	fmt.Println("📋 Preparing deployment plan...")
-	fmt.Println()
-
-	// Extract sizing parameters from flags
-	ec2InstanceType, _ := cmd.Flags().GetString("ec2-instance-type")
-	ec2VolumeSize, _ := cmd.Flags().GetInt("ec2-volume-size")
-	lambdaMemory, _ := cmd.Flags().GetInt("lambda-memory")
-	lambdaTimeout, _ := cmd.Flags().GetInt("lambda-timeout")
-	lambdaReservedConcurrency, _ := cmd.Flags().GetInt("lambda-reserved-concurrency")
-	eksNodeType, _ := cmd.Flags().GetString("eks-node-type")
-	eksMinNodes, _ := cmd.Flags().GetInt("eks-min-nodes")
-	eksMaxNodes, _ := cmd.Flags().GetInt("eks-max-nodes")
-	eksDesiredNodes, _ := cmd.Flags().GetInt("eks-desired-nodes")
-	eksNodeVolumeSize, _ := cmd.Flags().GetInt("eks-node-volume-size")
+	fmt.Fprintln(console.Stdout, "📋 Preparing deployment plan...")
+	fmt.Fprintln(console.Stdout)
+
+	// Extract sizing parameters (flags, then defaults.* from config, then built-in defaults)
+	ec2InstanceType := viper.GetString("defaults.ec2_instance_type")
+	ec2VolumeSize := viper.GetInt("defaults.ec2_volume_size")
+	lambdaMemory := viper.GetInt("defaults.lambda_memory")
+	lambdaTimeout := viper.GetInt("defaults.lambda_timeout")
+	lambdaReservedConcurrency := viper.GetInt("defaults.lambda_reserved_concurrency")
+	lambdaArch := viper.GetString("defaults.lambda_architecture")
+	lambdaContainer, _ := cmd.Flags().GetBool("lambda-container")
+	eksNodeType := viper.GetString("defaults.eks_node_type")
+	eksMinNodes := viper.GetInt("defaults.eks_min_nodes")
+	eksMaxNodes := viper.GetInt("defaults.eks_max_nodes")
+	eksDesiredNodes := viper.GetInt("defaults.eks_desired_nodes")
+	eksNodeVolumeSize := viper.GetInt("defaults.eks_node_volume_size")
+	eksFargate, _ := cmd.Flags().GetBool("eks-fargate")
+	eksVersion, _ := cmd.Flags().GetString("eks-version")
+	if eksVersion == "" {
+		eksVersion = viper.GetString("terraform.eks.version")
+	}
+	awsProviderVersion, _ := cmd.Flags().GetString("aws-provider-version")
+	if awsProviderVersion == "" {
+		awsProviderVersion = viper.GetString("terraform.aws_provider_version")
+	}
+
+	eksNATGateway, _ := cmd.Flags().GetString("nat-gateway")
+	eksAddonNames, _ := cmd.Flags().GetStringSlice("eks-addons")
+	eksAddonVersions, _ := cmd.Flags().GetStringArray("eks-addon-version")
+	eksAddons, err := parseEKSAddons(eksAddonNames, eksAddonVersions)
+	if err != nil {
+		return err
+	}
+
+	// Autoscaling policy only when asked for in the prompt (or with --set autoscale_target_cpu)
+	autoscaleTargetCPU := 0
+	budgetUSD := 0.0
+	replicas := 0
 
 	// Apply parsed config from natural language (if not overridden by flags)
 	if parsedConfig != nil {
-		if ec2InstanceType == "" && parsedConfig.EC2InstanceType != "" {
+		if !cmd.Flags().Changed("ec2-instance-type") && parsedConfig.EC2InstanceType != "" {
 			ec2InstanceType = parsedConfig.EC2InstanceType
 		}
 		if parsedConfig.EC2VolumeSize > 0 {
 			ec2VolumeSize = parsedConfig.EC2VolumeSize
 		}
-		if eksNodeType == "t3.medium" && parsedConfig.EKSNodeType != "" {
+		if !cmd.Flags().Changed("eks-node-type") && parsedConfig.EKSNodeType != "" {
 			eksNodeType = parsedConfig.EKSNodeType
 		}
 		if parsedConfig.EKSMinNodes > 0 {
@@ -221,8 +471,23 @@ func runDeploy(cmd *cobra.Command, args []string) error {
 		if parsedConfig.LambdaTimeout > 0 {
 			lambdaTimeout = parsedConfig.LambdaTimeout
 		}
+		if parsedConfig.EKSFargate {
+			eksFargate = true
+		}
+		autoscaleTargetCPU = parsedConfig.AutoscaleTargetCPU
+		budgetUSD = parsedConfig.BudgetUSD
+		replicas = parsedConfig.Replicas
 	}
 
+	// Optional RDS database
+	databaseEngine, _ := cmd.Flags().GetString("with-database")
+	databaseInstanceClass := viper.GetString("defaults.db_instance_class")
+	databaseStorage := viper.GetInt("defaults.db_storage")
+
+	// Custom domain
+	domain, _ := cmd.Flags().GetString("domain")
+	domain = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+
 	// Create temporary config for plan building
 	planConfig := &deployer.DeployConfig{
 		Strategy:                  strategy,
@@ -233,96 +498,343 @@ func runDeploy(cmd *cobra.Command, args []string) error {
 		LambdaMemory:              lambdaMemory,
 		LambdaTimeout:             lambdaTimeout,
 		LambdaReservedConcurrency: lambdaReservedConcurrency,
+		LambdaContainer:           lambdaContainer,
 		EKSNodeType:               eksNodeType,
 		EKSMinNodes:               eksMinNodes,
 		EKSMaxNodes:               eksMaxNodes,
 		EKSDesiredNodes:           eksDesiredNodes,
 		EKSNodeVolumeSize:         eksNodeVolumeSize,
+		EKSFargate:                eksFargate,
+		EKSVersion:                eksVersion,
+		EKSNATGateway:             eksNATGateway,
+		AWSProviderVersion:        awsProviderVersion,
+		EKSAddons:                 eksAddons,
+		Replicas:                  replicas,
+		AutoscaleTargetCPU:        autoscaleTargetCPU,
+		BudgetUSD:                 budgetUSD,
+		Domain:                    domain,
+		DatabaseEngine:            databaseEngine,
+		DatabaseInstanceClass:     databaseInstanceClass,
+		DatabaseStorage:           databaseStorage,
+		Tags:                      tags,
+	}
+
+	// --set overrides take precedence over the prompt and the sizing flags
+	parser.ApplyConfig(planConfig, setConfig)
+	planConfig.K8sResources = k8sResourcesFromFlags(cmd, analysis)
+	planConfig.SSHKeyName, _ = cmd.Flags().GetString("key-name")
+	planConfig.SSHGenerateKey, _ = cmd.Flags().GetBool("generate-key")
+	if (planConfig.SSHKeyName != "" || planConfig.SSHGenerateKey) && strategy != "vm" {
+		return fmt.Errorf("--key-name and --generate-key only apply to vm deployments: %s has no EC2 instances to SSH into", strategy)
+	}
+	planConfig.AppPolicyARNs, _ = cmd.Flags().GetStringArray("app-policy-arn")
+	if len(planConfig.AppPolicyARNs) > 0 && strategy != "kubernetes" {
+		return fmt.Errorf("--app-policy-arn only applies to kubernetes deployments: %s has no pod service account to bind", strategy)
+	}
+	for _, arn := range planConfig.AppPolicyARNs {
+		if err := terraform.ValidatePolicyARN(arn); err != nil {
+			return fmt.Errorf("invalid --app-policy-arn: %w", err)
+		}
+	}
+	if err := eksAdminsFromFlags(cmd, planConfig); err != nil {
+		return err
+	}
+	if err := apiGatewayFromFlags(cmd, planConfig); err != nil {
+		return err
+	}
+	if err := ec2ImageFromFlags(ctx, awsClient, cmd, planConfig, verbose); err != nil {
+		return err
+	}
+	if err := validateVMSource(planConfig); err != nil {
+		return err
+	}
+	if err := networkFromFlags(ctx, awsClient, cmd, planConfig, verbose); err != nil {
+		return err
+	}
+	planConfig.IacEngine = viper.GetString("iac.engine")
+	if err := deployer.CheckEngine(planConfig); err != nil {
+		return err
+	}
+	planConfig.AWSRoleARN = cloud.RoleARN()
+	planConfig.LogRetentionDays = viper.GetInt("defaults.log_retention_days")
+	if planConfig.LogRetentionDays != 0 {
+		if err := terraform.ValidateLogRetentionDays(planConfig.LogRetentionDays); err != nil {
+			return fmt.Errorf("invalid --log-retention-days: %w", err)
+		}
+	}
+	planConfig.StrictBudget, _ = cmd.Flags().GetBool("strict-budget")
+	if planConfig.StrictBudget && planConfig.BudgetUSD == 0 {
+		return fmt.Errorf("--strict-budget requires a budget in the prompt (e.g. \"keep it under $50/month\") or --set budget_usd=50")
+	}
+
+	// Fail early if the chosen instance types are not offered in the region
+	if err := validateInstanceTypes(ctx, awsClient, planConfig, verbose); err != nil {
+		return err
+	}
+
+	// Custom domain: the hosted zone must exist before planning
+	if domain != "" {
+		if awsClient == nil {
+			return fmt.Errorf("failed to create AWS client: %w", awsClientErr)
+		}
+		planConfig.HostedZoneID, planConfig.CertificateARN, err = resolveDomain(ctx, awsClient, awsRegion, domain, verbose)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := validateDatabase(strategy, databaseEngine); err != nil {
+		return err
+	}
+	if err := terraform.ValidateProviderVersion(planConfig.AWSProviderVersion); err != nil {
+		return fmt.Errorf("invalid --aws-provider-version: %w", err)
+	}
+	if strategy == "kubernetes" {
+		if err := terraform.ValidateEKSVersion(planConfig.EKSVersion); err != nil {
+			return fmt.Errorf("invalid --eks-version: %w", err)
+		}
+		if err := validateNATGateway(planConfig); err != nil {
+			return err
+		}
+		if err := terraform.ValidateK8sResources(planConfig.K8sResources); err != nil {
+			return fmt.Errorf("invalid container resources: %w", err)
+		}
+	}
+	// Lambda sizing from flags, defaults.*, --set or the prompt must be within the AWS limits
+	if strategy == "serverless" {
+		if err := terraform.ValidateLambdaSizing(planConfig.LambdaMemory, planConfig.LambdaTimeout); err != nil {
+			return fmt.Errorf("invalid Lambda sizing: %w", err)
+		}
+	}
+	if planConfig.LambdaArchitecture, err = normalizeLambdaArchitecture(lambdaArch); err != nil {
+		return err
+	}
+	if databaseEngine == "" && analysis.RequiresDatabase && strategy != "serverless" {
+		fmt.Fprintln(console.Stdout, "💡 Database dependency detected - use --with-database postgres|mysql to provision RDS")
+		fmt.Fprintln(console.Stdout)
 	}
 
 	// Build deployment plan
+	planConfig.DeployHistory = deployHistory(ctx)
 	plan := ui.BuildDeploymentPlan(strategy, awsRegion, appName, analysis, planConfig)
+	plan.Warnings = checkQuotas(ctx, awsClient, awsRegion, strategy, verbose)
+	if strategy == "kubernetes" && planConfig.EKSNATGateway == terraform.NATGatewayNone && planConfig.VPCID == "" {
+		plan.Warnings = append(plan.Warnings, "No NAT gateway: private subnets have no outbound internet access (nodes run in the public subnets with public IPs)")
+	}
+	if strategy == "serverless" && terraform.CORSAllOrigins(planConfig.CORSAllowOrigins) {
+		plan.Warnings = append(plan.Warnings, "CORS allows all origins: any website can call the API from a browser, restrict it with --cors-origin for production")
+	}
+	if planConfig.StrictBudget && plan.OverBudget() {
+		return budgetError(plan)
+	}
 
 	// Get --yes flag
 	autoApprove, _ := cmd.Flags().GetBool("yes")
 
-	// Show plan and get confirmation (with interactive modification support)
-	confirmed, updatedConfig, err := ui.ConfirmOrModify(plan, analysis, planConfig, llmClient, autoApprove)
-	if err != nil {
+	// Show plan and get confirmation (with interactive modification support), or print it
+	// as JSON: automations review it, then deploy it with --yes
+	confirmed, updatedConfig := true, planConfig
+	if planFormat == ui.PlanFormatJSON {
+		if err := ui.WritePlanJSON(os.Stdout, plan); err != nil {
+			return err
+		}
+		if !autoApprove {
+			pterm.Info.Println("Plan printed as JSON, nothing deployed: run again with --yes to deploy it")
+			return nil
+		}
+	} else if confirmed, updatedConfig, err = ui.ConfirmOrModify(plan, analysis, planConfig, llmClient, autoApprove); err != nil {
 		return fmt.Errorf("deployment confirmation failed: %w", err)
 	}
 
 	if !confirmed {
-		fmt.Println()
-		fmt.Println("❌ Deployment canceled by user")
+		fmt.Fprintln(console.Stdout)
+		fmt.Fprintln(console.Stdout, "❌ Deployment canceled by user")
 		return nil
 	}
 
 	// Use updated config from modification loop
 	planConfig = updatedConfig
+	if err := validateVMSource(planConfig); err != nil {
+		return err
+	}
+	// The modification loop can change the instance types
+	if err := validateInstanceTypes(ctx, awsClient, planConfig, verbose); err != nil {
+		return err
+	}
 
-	fmt.Println()
+	resourceTypes := make([]string, 0, len(plan.Resources))
+	for _, resource := range plan.Resources {
+		resourceTypes = append(resourceTypes, resource.Type)
+	}
+	console.Emit(console.Event{Phase: phase, Status: console.StatusSucceeded, Data: map[string]any{
+		"strategy":                 planConfig.Strategy,
+		"region":                   planConfig.AWSRegion,
+		"resources":                resourceTypes,
+		"warnings":                 plan.Warnings,
+		"estimated_monthly_usd":    plan.Cost.MonthlyUSD(),
+		"estimated_deploy_seconds": plan.Duration.Seconds,
+	}})
+
+	fmt.Fprintln(console.Stdout)
 
 	// Step 3: Deploy infrastructure (extend planConfig)
 	planConfig.UserPrompt = userPrompt
 	planConfig.WorkDir = workDir
 	planConfig.TerraformBin = tfBin
+	planConfig.PulumiBin = viper.GetString("iac.pulumi.bin")
 	planConfig.Verbose = verbose
 	planConfig.LLMProvider = providerConfig.Type
 	planConfig.LLMModel = getLLMModel(providerConfig)
 
+	planConfig.PlanOutDir, _ = cmd.Flags().GetString("plan-out")
+	planConfig.DestroyOnFailure, _ = cmd.Flags().GetBool("destroy-on-failure")
+	planConfig.ApplyRetries, _ = cmd.Flags().GetInt("apply-retries")
+
 	deployConfig := planConfig
 
-	d := deployer.NewDeployer(deployConfig, globalStore)
+	phase = "apply"
+	if deployConfig.PlanOutDir != "" {
+		phase = "export"
+	}
+	d = deployer.NewDeployer(deployConfig, globalStore)
 	d.SetLLMClient(llmClient)
+	d.SetContext(ctx)
 	result, err := d.Deploy()
 	if err != nil {
 		return fmt.Errorf("deployment failed: %w", err)
 	}
+	cleanupWorkDir(cmd, workDir, result, verbose)
+
+	if result.PlanOutDir != "" {
+		console.Emit(console.Event{Phase: phase, Status: console.StatusSucceeded, DeploymentID: result.DeploymentID, Data: map[string]any{
+			"plan_out_dir": result.PlanOutDir,
+		}})
+		displayPlanOut(result, tfBin)
+		return nil
+	}
+	console.Emit(console.Event{Phase: phase, Status: console.StatusSucceeded, DeploymentID: result.DeploymentID})
+	console.Emit(console.Event{Phase: "outputs", Status: console.StatusSucceeded, DeploymentID: result.DeploymentID, Data: map[string]any{
+		"outputs":       result.Outputs,
+		"warnings":      result.Warnings,
+		"terraform_dir": result.TerraformDir,
+	}})
 
 	// Step 4: Display results
-	fmt.Println()
-	fmt.Println("✅ Deployment Complete!")
-	fmt.Println()
-	fmt.Println("📋 Deployment Summary:")
-	fmt.Printf("   Strategy: %s\n", result.Strategy)
-	fmt.Printf("   Region: %s\n", result.Region)
+	fmt.Fprintln(console.Stdout)
+	fmt.Fprintln(console.Stdout, "✅ Deployment Complete!")
+	fmt.Fprintln(console.Stdout)
+	fmt.Fprintln(console.Stdout, "📋 Deployment Summary:")
+	fmt.Fprintf(console.Stdout, "   Strategy: %s\n", result.Strategy)
+	fmt.Fprintf(console.Stdout, "   Region: %s\n", result.Region)
+
+	if appURL := result.Outputs[terraform.AppURLOutput]; appURL != "" {
+		fmt.Fprintf(console.Stdout, "   Application URL: %s\n", appURL)
+	}
 
 	if len(result.Outputs) > 0 {
-		fmt.Println()
-		fmt.Println("🔗 Access URLs:")
+		fmt.Fprintln(console.Stdout)
+		fmt.Fprintln(console.Stdout, "🔗 Access URLs:")
 		for key, value := range result.Outputs {
-			fmt.Printf("   %s: %s\n", key, value)
+			fmt.Fprintf(console.Stdout, "   %s: %s\n", key, value)
 		}
 	}
 
 	if len(result.Warnings) > 0 {
-		fmt.Println()
-		fmt.Println("⚠️  Warnings:")
+		fmt.Fprintln(console.Stdout)
+		fmt.Fprintln(console.Stdout, "⚠️  Warnings:")
 		for _, warning := range result.Warnings {
-			fmt.Printf("   %s\n", warning)
+			fmt.Fprintf(console.Stdout, "   %s\n", warning)
 		}
 	}
 
 	if len(result.Optimizations) > 0 {
-		fmt.Println()
-		fmt.Println("💡 Optimization Suggestions:")
+		fmt.Fprintln(console.Stdout)
+		fmt.Fprintln(console.Stdout, "💡 Optimization Suggestions:")
 		for _, opt := range result.Optimizations {
-			fmt.Printf("   %s\n", opt)
+			fmt.Fprintf(console.Stdout, "   %s\n", opt)
 		}
 	}
 
 	if result.TerraformDir != "" {
-		fmt.Println()
-		fmt.Printf("📁 Terraform files: %s\n", result.TerraformDir)
+		fmt.Fprintln(console.Stdout)
+		fmt.Fprintf(console.Stdout, "📁 Terraform files: %s\n", result.TerraformDir)
+	}
+
+	if deployConfig.SSHGenerateKey && result.Strategy == "vm" {
+		if keyPath, err := deployer.SSHKeyPath(result.DeploymentID); err == nil {
+			fmt.Fprintf(console.Stdout, "🔑 SSH private key: %s (ssh -i %s ec2-user@<instance-ip>)\n", keyPath, keyPath)
+		}
 	}
 
-	fmt.Println()
-	fmt.Println("🎉 Success! Your application is now deployed.")
+	fmt.Fprintln(console.Stdout)
+	fmt.Fprintln(console.Stdout, "🎉 Success! Your application is now deployed.")
 
 	return nil
 }
 
+// zipLimits returns the extraction limits of zip archives configured in the analyzer section
+func zipLimits() analyzer.ZipLimits {
+	return analyzer.ZipLimits{
+		MaxSize:     viper.GetInt64("analyzer.zip_max_size_mb") << 20,
+		MaxFileSize: viper.GetInt64("analyzer.zip_max_file_size_mb") << 20,
+		MaxFiles:    viper.GetInt("analyzer.zip_max_files"),
+	}
+}
+
+// decideStrategy asks decider for the deployment strategy of analysis and shows the decision
+func decideStrategy(ctx context.Context, decider llm.StrategyDecider, userPrompt string, analysis *types.Analysis) (*llm.StrategyDecision, error) {
+	decision, err := decider.DecideStrategy(ctx, userPrompt, analysis)
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine strategy: %w", err)
+	}
+
+	fmt.Fprintf(console.Stdout, "   Recommended strategy: %s (%s, %.0f%% confidence)\n", decision.Strategy, decision.Source, decision.Confidence*100)
+	if decision.Reason != "" {
+		fmt.Fprintf(console.Stdout, "   Reason: %s\n", decision.Reason)
+	}
+	return decision, nil
+}
+
+// cleanupWorkDir removes the files a successful deployment no longer needs from workDir, unless
+// --keep-workdir or workdir.retain; failing to remove them doesn't fail the deployment
+func cleanupWorkDir(cmd *cobra.Command, workDir string, result *types.DeploymentResult, verbose bool) {
+	retain := viper.GetBool("workdir.retain")
+	if keep, _ := cmd.Flags().GetBool("keep-workdir"); keep {
+		retain = true
+	}
+	if clean, _ := cmd.Flags().GetBool("clean-workdir"); clean {
+		retain = false
+	}
+	if retain {
+		return
+	}
+
+	removed, err := deployer.CleanupWorkDir(workDir, result)
+	if err != nil {
+		fmt.Fprintf(console.Stdout, "⚠️  Failed to clean up the work directory: %v\n", err)
+		return
+	}
+	if verbose {
+		for _, dir := range removed {
+			fmt.Fprintf(console.Stdout, "   Removed %s\n", dir)
+		}
+	}
+}
+
+// displayPlanOut shows where the Terraform of a --plan-out run was exported and how to apply it
+func displayPlanOut(result *types.DeploymentResult, tfBin string) {
+	fmt.Fprintln(console.Stdout)
+	fmt.Fprintln(console.Stdout, "📝 Terraform Exported (not applied)")
+	fmt.Fprintln(console.Stdout)
+	fmt.Fprintf(console.Stdout, "   Deployment ID: %s\n", result.DeploymentID)
+	fmt.Fprintf(console.Stdout, "   Strategy: %s\n", result.Strategy)
+	fmt.Fprintf(console.Stdout, "   Region: %s\n", result.Region)
+	fmt.Fprintf(console.Stdout, "   Terraform files: %s\n", result.PlanOutDir)
+	fmt.Fprintln(console.Stdout)
+	fmt.Fprintln(console.Stdout, "💡 Review or commit the files, then apply them with:")
+	fmt.Fprintf(console.Stdout, "   cd %s && %s init && %s apply\n", result.PlanOutDir, filepath.Base(tfBin), filepath.Base(tfBin))
+}
+
 // extractAppName extracts application name from repository URL or path
 func extractAppName(repoSource string) string {
 	// Remove .git suffix if present
@@ -346,11 +858,491 @@ func extractAppName(repoSource string) string {
 	return "scia-app"
 }
 
+// parseTags merges default tags with key=value pairs from --tag flags
+func parseTags(defaults map[string]string, pairs []string) (map[string]string, error) {
+	tags := make(map[string]string, len(defaults)+len(pairs))
+	for key, value := range defaults {
+		tags[key] = value
+	}
+
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid tag %q: expected key=value", pair)
+		}
+		if strings.HasPrefix(key, "scia:") {
+			return nil, fmt.Errorf("invalid tag %q: the 'scia:' prefix is reserved", pair)
+		}
+		tags[key] = strings.TrimSpace(value)
+	}
+
+	return tags, nil
+}
+
+// eksAdminsFromFlags sets the additional cluster administrators of --eks-admin-arn. The identity
+// deploying is granted admin as the cluster creator: listing it again would create a duplicate
+// access entry, rejected by EKS.
+func eksAdminsFromFlags(cmd *cobra.Command, planConfig *deployer.DeployConfig) error {
+	arns, _ := cmd.Flags().GetStringArray("eks-admin-arn")
+	if len(arns) > 0 && planConfig.Strategy != "kubernetes" {
+		return fmt.Errorf("--eks-admin-arn only applies to kubernetes deployments: %s has no cluster to administer", planConfig.Strategy)
+	}
+
+	seen := make(map[string]bool, len(arns))
+	for _, arn := range arns {
+		if err := terraform.ValidatePrincipalARN(arn); err != nil {
+			return fmt.Errorf("invalid --eks-admin-arn: %w", err)
+		}
+		if arn == cloud.RoleARN() {
+			return fmt.Errorf("invalid --eks-admin-arn: %s deploys the cluster (--aws-role-arn) and is already its admin", arn)
+		}
+		if seen[arn] {
+			continue
+		}
+		seen[arn] = true
+		planConfig.EKSAdminARNs = append(planConfig.EKSAdminARNs, arn)
+	}
+	return nil
+}
+
+// deployHistory returns the provisioning times of the past successful deployments, refining
+// the estimated deploy time of the plan (none without the deployment database)
+func deployHistory(ctx context.Context) []deployer.PastDeploy {
+	if globalStore == nil {
+		return nil
+	}
+	deployments, err := globalStore.List(ctx, nil)
+	if err != nil {
+		return nil
+	}
+
+	var history []deployer.PastDeploy
+	for _, dep := range deployments {
+		// Destroyed deployments succeeded first (re-imported ones have no provisioning time)
+		if dep.DeployedAt == nil || (dep.Status != store.DeploymentStatusSucceeded && dep.Status != store.DeploymentStatusDestroyed) {
+			continue
+		}
+		history = append(history, deployer.PastDeploy{
+			Strategy: dep.Strategy,
+			Database: dep.Config != nil && dep.Config.DatabaseEngine != "",
+			Duration: dep.DeployedAt.Sub(dep.CreatedAt),
+		})
+	}
+	return history
+}
+
+// budgetError explains why a plan estimated above its budget is refused (--strict-budget)
+func budgetError(plan *ui.DeploymentPlan) error {
+	if len(plan.Cheaper) == 0 {
+		return fmt.Errorf("estimated cost ~$%.0f/month exceeds the budget of $%.0f/month (--strict-budget)", plan.Cost.MonthlyUSD(), plan.BudgetUSD)
+	}
+	return fmt.Errorf("estimated cost ~$%.0f/month exceeds the budget of $%.0f/month (--strict-budget), cheaper options: %s",
+		plan.Cost.MonthlyUSD(), plan.BudgetUSD, strings.Join(plan.Cheaper, "; "))
+}
+
+// apiGatewayFromFlags sets the API Gateway CORS and authorizer of serverless deployments from
+// the flags, or terraform.api_gateway.* of the configuration
+func apiGatewayFromFlags(cmd *cobra.Command, config *deployer.DeployConfig) error {
+	flags := []string{"cors-origin", "cors-method", "cors-header", "authorizer-arn"}
+	if config.Strategy != "serverless" {
+		for _, flag := range flags {
+			if cmd.Flags().Changed(flag) {
+				return fmt.Errorf("--%s only applies to serverless deployments: %s has no API Gateway", flag, config.Strategy)
+			}
+		}
+		return nil
+	}
+
+	setting := func(flag, key string) []string {
+		if values, _ := cmd.Flags().GetStringSlice(flag); len(values) > 0 {
+			return values
+		}
+		return viper.GetStringSlice("terraform.api_gateway." + key)
+	}
+	config.CORSAllowOrigins = setting("cors-origin", "cors_allow_origins")
+	config.CORSAllowMethods = setting("cors-method", "cors_allow_methods")
+	config.CORSAllowHeaders = setting("cors-header", "cors_allow_headers")
+	config.AuthorizerARN, _ = cmd.Flags().GetString("authorizer-arn")
+	if config.AuthorizerARN == "" {
+		config.AuthorizerARN = viper.GetString("terraform.api_gateway.authorizer_arn")
+	}
+
+	for _, origin := range config.CORSAllowOrigins {
+		if err := terraform.ValidateCORSOrigin(origin); err != nil {
+			return fmt.Errorf("invalid --cors-origin: %w", err)
+		}
+	}
+	for _, method := range config.CORSAllowMethods {
+		if err := terraform.ValidateCORSMethod(method); err != nil {
+			return fmt.Errorf("invalid --cors-method: %w", err)
+		}
+	}
+	if config.AuthorizerARN != "" {
+		if err := terraform.ValidateLambdaARN(config.AuthorizerARN); err != nil {
+			return fmt.Errorf("invalid --authorizer-arn: %w", err)
+		}
+	}
+	return nil
+}
+
+// parseEKSAddons returns the EKS add-ons to install, mapped to their pinned version
+// ("" for the most recent) from --eks-addon-version name=version pairs
+func parseEKSAddons(names, versions []string) (map[string]string, error) {
+	addons := make(map[string]string, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if err := terraform.ValidateEKSAddon(name); err != nil {
+			return nil, fmt.Errorf("invalid --eks-addons: %w", err)
+		}
+		addons[name] = ""
+	}
+
+	for _, pair := range versions {
+		name, version, ok := strings.Cut(pair, "=")
+		name, version = strings.TrimSpace(name), strings.TrimSpace(version)
+		if !ok || name == "" || version == "" {
+			return nil, fmt.Errorf("invalid --eks-addon-version %q: expected name=version", pair)
+		}
+		if _, enabled := addons[name]; !enabled {
+			return nil, fmt.Errorf("invalid --eks-addon-version %q: %s is not in --eks-addons", pair, name)
+		}
+		addons[name] = version
+	}
+
+	return addons, nil
+}
+
+// validateRegion checks that the deployment region exists and is enabled for the account.
+// AWS lookup failures are not fatal: Terraform will still report an invalid region.
+func validateRegion(ctx context.Context, awsClient *cloud.AWSClient, region string, verbose bool) error {
+	if region == "" {
+		return fmt.Errorf("no AWS region: set cloud.default_region in ~/.scai.yaml or use --region")
+	}
+	if awsClient == nil {
+		return nil
+	}
+
+	valid, err := awsClient.ValidateRegion(ctx, region)
+	if err != nil {
+		if verbose {
+			fmt.Fprintf(console.Stdout, "Warning: Could not validate region: %v\n", err)
+		}
+		return nil
+	}
+	if valid {
+		return nil
+	}
+
+	regions, err := awsClient.EnabledRegions(ctx)
+	if err != nil {
+		return fmt.Errorf("region %s is not an AWS region enabled for this account", region)
+	}
+	return fmt.Errorf("region %s is not an AWS region enabled for this account (opt-in regions must be enabled first); valid regions: %s",
+		region, strings.Join(regions, ", "))
+}
+
+// validateInstanceTypes checks that the instance type used by the strategy is offered in the region.
+// AWS lookup failures are not fatal: Terraform will still report an invalid type.
+func validateInstanceTypes(ctx context.Context, awsClient *cloud.AWSClient, config *deployer.DeployConfig, verbose bool) error {
+	var instanceType, flag string
+	switch config.Strategy {
+	case "vm":
+		instanceType, flag = config.EC2InstanceType, "--ec2-instance-type"
+	case "kubernetes":
+		// Fargate has no nodes, so there is no node type to validate
+		if !config.EKSFargate {
+			instanceType, flag = config.EKSNodeType, "--eks-node-type"
+		}
+	}
+	if instanceType == "" || awsClient == nil {
+		return nil
+	}
+	region := config.AWSRegion
+
+	available, err := awsClient.IsInstanceTypeAvailable(ctx, region, instanceType)
+	if err != nil {
+		if verbose {
+			fmt.Fprintf(console.Stdout, "Warning: Could not validate instance type: %v\n", err)
+		}
+		return nil
+	}
+	if available {
+		return nil
+	}
+
+	suggestion, _ := awsClient.SuggestInstanceType(ctx, region, instanceType)
+	if suggestion == "" {
+		return fmt.Errorf("instance type %s is not offered in region %s", instanceType, region)
+	}
+
+	return fmt.Errorf("instance type %s is not offered in region %s (try %s %s)", instanceType, region, flag, suggestion)
+}
+
+// ec2ImageFromFlags sets the custom AMI (--ami) and user-data (--user-data) of a vm deployment
+func ec2ImageFromFlags(ctx context.Context, awsClient *cloud.AWSClient, cmd *cobra.Command, config *deployer.DeployConfig, verbose bool) error {
+	ami, _ := cmd.Flags().GetString("ami")
+	userDataPath, _ := cmd.Flags().GetString("user-data")
+	if ami == "" && userDataPath == "" {
+		return nil
+	}
+	if config.Strategy != "vm" {
+		return fmt.Errorf("--ami and --user-data only apply to vm deployments: %s has no EC2 instances to boot", config.Strategy)
+	}
+
+	if userDataPath != "" {
+		content, err := os.ReadFile(userDataPath) // #nosec G304 -- user-provided file path
+		if err != nil {
+			return fmt.Errorf("failed to read --user-data: %w", err)
+		}
+		if err := terraform.ValidateUserData(string(content)); err != nil {
+			return fmt.Errorf("invalid --user-data %s: %w", userDataPath, err)
+		}
+		config.UserData, config.UserDataPath = string(content), userDataPath
+	}
+
+	if ami == "" {
+		return nil
+	}
+	if err := terraform.ValidateAMIID(ami); err != nil {
+		return fmt.Errorf("invalid --ami: %w", err)
+	}
+	name, err := resolveAMI(ctx, awsClient, config.AWSRegion, ami, verbose)
+	if err != nil {
+		return err
+	}
+	config.AMI, config.AMIName = ami, name
+	return nil
+}
+
+// validateVMSource rejects vm deployments of zip archives and local directories: the instances
+// clone the repository when they boot, and a path of this machine cannot be cloned there.
+// A custom --user-data script fetches the application itself.
+func validateVMSource(config *deployer.DeployConfig) error {
+	if config.Strategy != "vm" || config.UserData != "" || !config.Analysis.LocalSource {
+		return nil
+	}
+	return fmt.Errorf("vm deployments clone the repository on the instances, %s is not a Git URL: push it to a Git repository, deploy it with --strategy kubernetes or serverless, or bootstrap the instances with --user-data", config.Analysis.RepoURL)
+}
+
+// resolveAMI checks that an AMI exists in the deployment region and returns its name.
+// AWS lookup failures are not fatal: Terraform will still report a missing AMI.
+func resolveAMI(ctx context.Context, awsClient *cloud.AWSClient, region, ami string, verbose bool) (string, error) {
+	if awsClient == nil {
+		return "", nil
+	}
+
+	image, err := awsClient.FindImage(ctx, region, ami)
+	if err != nil {
+		if verbose {
+			fmt.Fprintf(console.Stdout, "Warning: Could not validate AMI: %v\n", err)
+		}
+		return "", nil
+	}
+	if image == nil {
+		return "", fmt.Errorf("AMI %s not found in region %s (AMIs are regional: copy it to %s or share it with this account)", ami, region, region)
+	}
+	if !image.Available() {
+		return "", fmt.Errorf("AMI %s in region %s is %s, not available", ami, region, image.State)
+	}
+	return image.Name, nil
+}
+
+// networkFromFlags sets the existing VPC (--vpc-id) and subnets (--subnet-ids) of a vm or
+// kubernetes deployment
+func networkFromFlags(ctx context.Context, awsClient *cloud.AWSClient, cmd *cobra.Command, config *deployer.DeployConfig, verbose bool) error {
+	vpcID, _ := cmd.Flags().GetString("vpc-id")
+	subnetIDs, _ := cmd.Flags().GetStringSlice("subnet-ids")
+	if vpcID == "" {
+		if len(subnetIDs) > 0 {
+			return fmt.Errorf("--subnet-ids requires --vpc-id, the VPC of the subnets")
+		}
+		return nil
+	}
+	if config.Strategy == "serverless" {
+		return fmt.Errorf("--vpc-id and --subnet-ids only apply to vm and kubernetes deployments: serverless functions do not run in a VPC")
+	}
+
+	if err := terraform.ValidateVPCID(vpcID); err != nil {
+		return fmt.Errorf("invalid --vpc-id: %w", err)
+	}
+	for _, id := range subnetIDs {
+		if err := terraform.ValidateSubnetID(id); err != nil {
+			return fmt.Errorf("invalid --subnet-ids: %w", err)
+		}
+	}
+	if err := resolveNetwork(ctx, awsClient, config, vpcID, subnetIDs, verbose); err != nil {
+		return err
+	}
+	config.VPCID, config.SubnetIDs = vpcID, subnetIDs
+	return nil
+}
+
+// resolveNetwork checks that a VPC and its subnets exist in the deployment region, and that
+// the subnets of an EKS cluster or RDS database span two availability zones. AWS lookup
+// failures are not fatal: Terraform will still report a missing VPC or subnet.
+func resolveNetwork(ctx context.Context, awsClient *cloud.AWSClient, config *deployer.DeployConfig, vpcID string, subnetIDs []string, verbose bool) error {
+	if awsClient == nil {
+		return nil
+	}
+	region := config.AWSRegion
+
+	vpc, err := awsClient.FindVPC(ctx, region, vpcID)
+	if err != nil {
+		if verbose {
+			fmt.Fprintf(console.Stdout, "Warning: Could not validate VPC: %v\n", err)
+		}
+		return nil
+	}
+	if vpc == nil {
+		return fmt.Errorf("VPC %s not found in region %s", vpcID, region)
+	}
+
+	subnets, err := awsClient.FindSubnets(ctx, region, vpcID, subnetIDs)
+	if err != nil {
+		if verbose {
+			fmt.Fprintf(console.Stdout, "Warning: Could not validate subnets: %v\n", err)
+		}
+		return nil
+	}
+	if len(subnets) == 0 {
+		return fmt.Errorf("VPC %s has no subnets in region %s", vpcID, region)
+	}
+	found := make(map[string]bool, len(subnets))
+	zones := make(map[string]bool)
+	for _, subnet := range subnets {
+		found[subnet.ID], zones[subnet.AvailabilityZone] = true, true
+	}
+	for _, id := range subnetIDs {
+		if !found[id] {
+			return fmt.Errorf("subnet %s not found in VPC %s (region %s)", id, vpcID, region)
+		}
+	}
+
+	if (config.Strategy == "kubernetes" || config.DatabaseEngine != "") && len(zones) < 2 {
+		return fmt.Errorf("the subnets of VPC %s are in a single availability zone: EKS clusters and RDS databases need subnets in at least two", vpcID)
+	}
+	return nil
+}
+
+// validateDatabase checks the --with-database engine and that the strategy can reach an RDS instance
+func validateDatabase(strategy, engine string) error {
+	switch engine {
+	case "":
+		return nil
+	case "postgres", "mysql":
+	default:
+		return fmt.Errorf("invalid --with-database %q: expected postgres or mysql", engine)
+	}
+
+	if strategy == "serverless" {
+		return fmt.Errorf("--with-database is not supported for serverless deployments (Lambda does not run in a VPC)")
+	}
+	return nil
+}
+
+// normalizeLambdaArchitecture validates --lambda-arch and returns the Lambda architecture name
+func normalizeLambdaArchitecture(arch string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(arch)) {
+	case "x86_64", "amd64":
+		return "x86_64", nil
+	case "arm64", "aarch64":
+		return "arm64", nil
+	default:
+		return "", fmt.Errorf("invalid --lambda-arch %q: expected x86_64 or arm64", arch)
+	}
+}
+
+// addK8sResourceFlags adds the Kubernetes container requests and limits flags to cmd
+func addK8sResourceFlags(cmd *cobra.Command) {
+	cmd.Flags().String("k8s-cpu-request", "", "Kubernetes container CPU request, e.g. 250m (default: estimated from the framework)")
+	cmd.Flags().String("k8s-cpu-limit", "", "Kubernetes container CPU limit, e.g. 1 (default: estimated from the framework)")
+	cmd.Flags().String("k8s-memory-request", "", "Kubernetes container memory request, e.g. 256Mi (default: estimated from the framework)")
+	cmd.Flags().String("k8s-memory-limit", "", "Kubernetes container memory limit, e.g. 1Gi (default: estimated from the framework)")
+}
+
+// k8sResourcesFromFlags returns the container requests and limits: the --k8s-* flags,
+// or the ones estimated from the framework's memory usage
+func k8sResourcesFromFlags(cmd *cobra.Command, analysis *types.Analysis) terraform.K8sResources {
+	minMB, maxMB := llm.EstimateMemoryMB(analysis)
+	resources := terraform.K8sResourcesForMemory(minMB, maxMB)
+
+	if value, _ := cmd.Flags().GetString("k8s-cpu-request"); value != "" {
+		resources.CPURequest = value
+	}
+	if value, _ := cmd.Flags().GetString("k8s-cpu-limit"); value != "" {
+		resources.CPULimit = value
+	}
+	if value, _ := cmd.Flags().GetString("k8s-memory-request"); value != "" {
+		resources.MemoryRequest = value
+	}
+	if value, _ := cmd.Flags().GetString("k8s-memory-limit"); value != "" {
+		resources.MemoryLimit = value
+	}
+	return resources
+}
+
+// validateNATGateway validates --nat-gateway for the EKS VPC
+func validateNATGateway(config *deployer.DeployConfig) error {
+	if err := terraform.ValidateNATGateway(config.EKSNATGateway); err != nil {
+		return fmt.Errorf("invalid --nat-gateway: %w", err)
+	}
+	// Fargate profiles only accept private subnets, which need a NAT gateway to pull images
+	if config.EKSNATGateway == terraform.NATGatewayNone && config.EKSFargate {
+		return fmt.Errorf("--nat-gateway none is not supported with Fargate: Fargate pods run in private subnets and need a NAT gateway")
+	}
+	return nil
+}
+
+// resolveDomain finds the Route53 hosted zone for a custom domain and an existing
+// ACM certificate covering it. A missing hosted zone is fatal; when no certificate
+// exists, an empty ARN is returned and Terraform requests a new one.
+func resolveDomain(ctx context.Context, awsClient *cloud.AWSClient, region, domain string, verbose bool) (hostedZoneID, certificateARN string, err error) {
+	if strings.Contains(domain, "://") || !strings.Contains(domain, ".") {
+		return "", "", fmt.Errorf("invalid domain %q: expected a host name such as app.example.com", domain)
+	}
+
+	zone, err := awsClient.FindHostedZone(ctx, domain)
+	if err != nil {
+		return "", "", fmt.Errorf("cannot use domain %s: %w", domain, err)
+	}
+
+	certificateARN, err = awsClient.FindCertificate(ctx, region, domain)
+	if err != nil && verbose {
+		fmt.Fprintf(console.Stdout, "Warning: Could not look up ACM certificates, a new one will be requested: %v\n", err)
+	}
+
+	if verbose {
+		fmt.Fprintf(console.Stdout, "   Hosted Zone: %s (%s)\n", zone.Name, zone.ID)
+		if certificateARN != "" {
+			fmt.Fprintf(console.Stdout, "   Certificate: %s\n", certificateARN)
+		}
+	}
+
+	return zone.ID, certificateARN, nil
+}
+
+// checkQuotas returns service quota warnings for the plan.
+// Quota checks are advisory: failures are only reported in verbose mode.
+func checkQuotas(ctx context.Context, awsClient *cloud.AWSClient, region, strategy string, verbose bool) []string {
+	if awsClient == nil {
+		return nil
+	}
+
+	warnings, err := awsClient.CheckQuotas(ctx, region, strategy)
+	if err != nil && verbose {
+		fmt.Fprintf(console.Stdout, "Warning: Could not check service quotas: %v\n", err)
+	}
+
+	return warnings
+}
+
 // initializeLLMProvider initializes the LLM provider based on configuration
 // Returns the ProviderManager and its config for creating a Client
-func initializeLLMProvider(verbose bool) (*llm.ProviderManager, *llm.ProviderConfig, error) {
-	ctx := context.Background()
-
+func initializeLLMProvider(ctx context.Context, verbose bool) (*llm.ProviderManager, *llm.ProviderConfig, error) {
 	// Get provider type from config
 	providerType := viper.GetString("llm.provider")
 	if providerType == "" {
@@ -372,56 +1364,37 @@ func initializeLLMProvider(verbose bool) (*llm.ProviderManager, *llm.ProviderCon
 		// OpenAI configuration
 		OpenAIAPIKey: viper.GetString("llm.openai.api_key"),
 		OpenAIModel:  viper.GetString("llm.openai.model"),
-	}
 
-	// Special handling for Ollama - ensure it's available
-	if providerType == providerTypeOllama {
-		useDocker := viper.GetBool("llm.ollama.use_docker")
-		configuredURL := providerConfig.OllamaURL
-
-		// Priority 1: Check if remote/configured URL is accessible
-		if configuredURL != defaultOllamaURL {
-			if verbose {
-				fmt.Printf("🔍 Checking remote Ollama at %s...\n", configuredURL)
-			}
-			if llm.IsOllamaAccessible(configuredURL) {
-				if verbose {
-					fmt.Printf("✓ Connected to remote Ollama\n\n")
-				}
-			} else {
-				return nil, nil, fmt.Errorf(`❌ Ollama not available at configured URL: %s
+		// Providers tried in order when the configured one fails
+		Fallback: viper.GetStringSlice("llm.fallback"),
+	}
 
-Please ensure Ollama is running or update your configuration with 'scia init'`, configuredURL)
-			}
-		} else {
-			// Priority 2: Try Docker (if enabled)
-			if useDocker && llm.IsDockerAvailable() {
-				if verbose {
-					fmt.Println("🐳 Checking Docker Ollama...")
-				}
+	var err error
+	if providerConfig.OllamaKeepAlive, err = llm.ParseOllamaKeepAlive(viper.GetString("llm.ollama.keep_alive")); err != nil {
+		return nil, nil, fmt.Errorf("invalid llm.ollama.keep_alive: %w", err)
+	}
 
-				url, err := llm.SetupOllamaDocker(providerConfig.OllamaModel, verbose)
-				if err == nil {
-					providerConfig.OllamaURL = url
-					if verbose {
-						fmt.Println()
-					}
-				} else if verbose {
-					fmt.Printf("Warning: Docker setup failed: %v\n", err)
-				}
-			} else if llm.IsOllamaAccessible(defaultOllamaURL) {
-				// Priority 3: Try localhost
-				if verbose {
-					fmt.Println("🔍 Checking local Ollama...")
-					fmt.Printf("✓ Connected to local Ollama\n\n")
-				}
-			} else {
-				return nil, nil, fmt.Errorf(`❌ Ollama LLM is not available!
+	// API keys may be OS keyring references or encrypted (scia config encrypt)
+	for _, keyProvider := range append([]string{providerType}, providerConfig.Fallback...) {
+		switch keyProvider {
+		case providerTypeGemini:
+			providerConfig.GeminiAPIKey, err = config.ResolveSecret(providerConfig.GeminiAPIKey, configPassphrase(false))
+		case providerTypeOpenAI:
+			providerConfig.OpenAIAPIKey, err = config.ResolveSecret(providerConfig.OpenAIAPIKey, configPassphrase(false))
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read %s API key: %w", keyProvider, err)
+		}
+	}
+	llm.AddDebugSecrets(providerConfig.GeminiAPIKey, providerConfig.OpenAIAPIKey)
 
-Run 'scia init' to configure an LLM provider, or start Ollama:
-  docker run -d --name scia-ollama -p 11434:11434 -v ollama-data:/root/.ollama ollama/ollama
-  docker exec scia-ollama ollama pull %s`, providerConfig.OllamaModel)
+	// Special handling for Ollama - ensure it's available, unless a fallback provider can take over
+	if providerType == providerTypeOllama {
+		if err := ensureOllama(providerConfig, verbose); err != nil {
+			if len(providerConfig.Fallback) == 0 {
+				return nil, nil, err
 			}
+			fmt.Fprintf(console.Stdout, "⚠️  Ollama is not available, falling back to %s\n\n", strings.Join(providerConfig.Fallback, ", "))
 		}
 	}
 
@@ -439,19 +1412,162 @@ Run 'scia init' to configure an LLM provider, or start Ollama:
 No accessible LLM providers found. Run 'scia init' to configure a provider.`, providerType)
 	}
 
-	if !bestProvider.IsAvailable(ctx) {
-		return nil, nil, fmt.Errorf(`❌ LLM provider '%s' is not available!
-
-Run 'scia init' to configure a different LLM provider.`, providerType)
+	// Check the configured model now rather than failing on the first prompt
+	if err := ensureModel(ctx, bestProvider); err != nil {
+		return nil, nil, err
 	}
 
 	if verbose {
-		fmt.Printf("✓ Using LLM provider: %s\n\n", providerType)
+		fmt.Fprintf(console.Stdout, "✓ Using LLM provider: %s\n", bestProvider.Name())
+		if len(providerConfig.Fallback) > 0 {
+			fmt.Fprintf(console.Stdout, "  Fallback: %s\n", strings.Join(providerConfig.Fallback, ", "))
+		}
+		fmt.Fprintln(console.Stdout)
+	}
+
+	// Load the Ollama model while the repository is analyzed, rather than on the first prompt
+	if providerType == providerTypeOllama && viper.GetBool("llm.ollama.preload") {
+		go func() {
+			if err := providerManager.Preload(ctx); err != nil && verbose {
+				fmt.Fprintf(console.Stdout, "Warning: %v\n", err)
+			}
+		}()
 	}
 
 	return providerManager, providerConfig, nil
 }
 
+// ensureOllama makes sure Ollama is reachable: at the configured URL, or else in Docker
+// (when enabled) or on localhost, updating providerConfig.OllamaURL to the one found (and
+// OllamaModel to a model of the Docker container reused instead of pulling it)
+func ensureOllama(providerConfig *llm.ProviderConfig, verbose bool) error {
+	useDocker := viper.GetBool("llm.ollama.use_docker")
+	configuredURL := providerConfig.OllamaURL
+
+	// Priority 1: Check if remote/configured URL is accessible
+	if configuredURL != defaultOllamaURL {
+		if verbose {
+			fmt.Fprintf(console.Stdout, "🔍 Checking remote Ollama at %s...\n", configuredURL)
+		}
+		if llm.IsOllamaAccessible(configuredURL) {
+			if verbose {
+				fmt.Fprintf(console.Stdout, "✓ Connected to remote Ollama\n\n")
+			}
+		} else {
+			return fmt.Errorf(`❌ Ollama not available at configured URL: %s
+
+Please ensure Ollama is running or update your configuration with 'scia init'`, configuredURL)
+		}
+	} else {
+		// Priority 2: Try Docker (if enabled)
+		if useDocker && llm.IsDockerAvailable() {
+			if verbose {
+				fmt.Fprintln(console.Stdout, "🐳 Checking Docker Ollama...")
+			}
+
+			// Offer to reuse a model already pulled in the container rather than pulling
+			// the configured one, when there is a terminal to ask
+			var chooseModel llm.ModelChooser
+			if interactiveTerminal() {
+				chooseModel = ui.SelectOllamaModel
+			}
+			url, model, err := llm.SetupOllamaDocker(providerConfig.OllamaModel, chooseModel, verbose)
+			if err == nil {
+				providerConfig.OllamaURL = url
+				providerConfig.OllamaModel = model
+				if verbose {
+					fmt.Fprintln(console.Stdout)
+				}
+			} else if verbose {
+				fmt.Fprintf(console.Stdout, "Warning: Docker setup failed: %v\n", err)
+			}
+		} else if llm.IsOllamaAccessible(defaultOllamaURL) {
+			// Priority 3: Try localhost
+			if verbose {
+				fmt.Fprintln(console.Stdout, "🔍 Checking local Ollama...")
+				fmt.Fprintf(console.Stdout, "✓ Connected to local Ollama\n\n")
+			}
+		} else {
+			return fmt.Errorf(`❌ Ollama LLM is not available!
+
+Run 'scia init' to configure an LLM provider, or start Ollama:
+  docker run -d --name scia-ollama -p 11434:11434 -v ollama-data:/root/.ollama ollama/ollama
+  docker exec scia-ollama ollama pull %s`, providerConfig.OllamaModel)
+		}
+	}
+	return nil
+}
+
+// ensureModel checks that provider has its configured model, offering to pull a missing model
+// when the provider can (Ollama); without a terminal to ask, a missing model is an error
+func ensureModel(ctx context.Context, provider llm.Provider) error {
+	err := llm.CheckModel(ctx, provider)
+	var notAvailable *llm.ModelNotAvailableError
+	if !errors.As(err, &notAvailable) {
+		return err
+	}
+
+	puller, canPull := provider.(llm.ModelPuller)
+	if !canPull {
+		return fmt.Errorf("❌ %w\n\nSet an available model with 'scia init' or in ~/.scai.yaml", err)
+	}
+	if !interactiveTerminal() {
+		return fmt.Errorf("❌ %w\n\nPull it first (ollama pull %s) or set an available model with 'scia init'", err, notAvailable.Model)
+	}
+
+	confirmed, promptErr := pterm.DefaultInteractiveConfirm.
+		WithDefaultText(fmt.Sprintf("Model %s is not available on %s. Pull it now?", notAvailable.Model, notAvailable.Provider)).
+		WithDefaultValue(true).
+		Show()
+	if promptErr != nil {
+		return fmt.Errorf("confirmation prompt failed: %w", promptErr)
+	}
+	if !confirmed {
+		return fmt.Errorf("❌ %w", err)
+	}
+
+	fmt.Fprintf(console.Stdout, "⬇️  Pulling %s (this may take several minutes)...\n", notAvailable.Model)
+	if err := puller.PullModel(ctx, notAvailable.Model); err != nil {
+		return err
+	}
+	fmt.Fprintf(console.Stdout, "✓ Model %s pulled\n\n", notAvailable.Model)
+	return nil
+}
+
+// parsePromptConfig extracts the configuration of userPrompt with the LLM. With --strict-prompt, a
+// response that cannot be parsed or that drops values stated in the prompt is an error rather than
+// ignored: the user is warned and offered to re-prompt the model with a repair instruction, and
+// without a terminal to ask, it fails.
+func parsePromptConfig(ctx context.Context, cmd *cobra.Command, llmClient *llm.Client, userPrompt string) (*parser.DeploymentConfig, error) {
+	if strict, _ := cmd.Flags().GetBool("strict-prompt"); !strict {
+		return parser.ParseConfigFromPrompt(ctx, llmClient, userPrompt)
+	}
+
+	config, err := parser.ParseConfigFromPromptStrict(ctx, llmClient, userPrompt)
+	for {
+		var parseErr *parser.PromptParseError
+		if !errors.As(err, &parseErr) {
+			return config, err
+		}
+		pterm.Warning.Printf("Prompt configuration: %v\n", parseErr)
+
+		if !interactiveTerminal() {
+			return nil, fmt.Errorf("❌ %w\n\nRephrase the prompt or set the values with --set (e.g. --set ec2_instance_type=t3.large)", err)
+		}
+		confirmed, promptErr := pterm.DefaultInteractiveConfirm.
+			WithDefaultText("Re-prompt the model to fix its answer?").
+			WithDefaultValue(true).
+			Show()
+		if promptErr != nil {
+			return nil, fmt.Errorf("confirmation prompt failed: %w", promptErr)
+		}
+		if !confirmed {
+			return nil, fmt.Errorf("❌ %w", err)
+		}
+		config, err = parser.RepairConfigFromPrompt(ctx, llmClient, userPrompt, parseErr)
+	}
+}
+
 // getLLMModel returns the active model name based on provider type
 func getLLMModel(config *llm.ProviderConfig) string {
 	switch config.Type {
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/Smana/scai/internal/console"
+)
+
+// deployArgs requires the prompt and the repository, unless they can be asked for
+// interactively: stdin is a terminal and JSON events are not requested
+func deployArgs(cmd *cobra.Command, args []string) error {
+	if len(args) < 2 && interactiveTerminal() {
+		return nil
+	}
+	return cobra.ExactArgs(2)(cmd, args)
+}
+
+// interactiveTerminal reports whether the user can be prompted
+func interactiveTerminal() bool {
+	return !console.JSON() && term.IsTerminal(int(os.Stdin.Fd()))
+}
+
+// promptDeployArgs asks for the arguments missing from the command line: the repository
+// source and the deployment description (a single argument is the description)
+func promptDeployArgs(args []string) (userPrompt, repoSource string, err error) {
+	if len(args) > 0 {
+		userPrompt = args[0]
+	}
+
+	fmt.Fprintln(console.Stdout, "🚀 Let's deploy your application")
+	fmt.Fprintln(console.Stdout)
+
+	required := func(field string) func(string) error {
+		return func(s string) error {
+			if strings.TrimSpace(s) == "" {
+				return fmt.Errorf("%s is required", field)
+			}
+			return nil
+		}
+	}
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Repository").
+				Description("Git URL, .zip archive or local directory of the application").
+				Placeholder("https://github.com/user/flask-app").
+				Value(&repoSource).
+				Validate(required("repository")),
+			huh.NewText().
+				Title("Deployment description").
+				Description("Describe the deployment in your own words (strategy, sizing, region...)").
+				Placeholder("Deploy this Flask app on AWS with a t3.small instance").
+				Value(&userPrompt).
+				Validate(required("description")),
+		),
+	)
+
+	if err := form.Run(); err != nil {
+		return "", "", err
+	}
+
+	fmt.Fprintln(console.Stdout)
+	return strings.TrimSpace(userPrompt), strings.TrimSpace(repoSource), nil
+}
@@ -3,12 +3,16 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	"github.com/Smana/scai/internal/console"
+	"github.com/Smana/scai/internal/deployer"
+	"github.com/Smana/scai/internal/pulumi"
 	"github.com/Smana/scai/internal/store"
 	"github.com/Smana/scai/internal/terraform"
 )
@@ -21,7 +25,8 @@ This will remove all AWS resources created for the deployment.
 
 Example:
   scia destroy abc123de-f456-7890-abcd-ef1234567890
-  scia destroy abc123de --yes`,
+  scia destroy abc123de --yes
+  scia destroy abc123de --plan   # list the resources to delete before confirming`,
 	Args: cobra.ExactArgs(1),
 	RunE: runDestroy,
 }
@@ -31,6 +36,8 @@ func init() {
 
 	// Destroy-specific flags
 	destroyCmd.Flags().BoolP("yes", "y", false, "Auto-approve destroy without confirmation prompt")
+	destroyCmd.Flags().Bool("plan", false, "Run terraform plan -destroy and show the resources to delete before confirming")
+	destroyCmd.Flags().Duration("timeout", 0, "Stop the destroy after this duration, e.g. 30m (default: no limit)")
 }
 
 func runDestroy(cmd *cobra.Command, args []string) error {
@@ -42,6 +49,11 @@ func runDestroy(cmd *cobra.Command, args []string) error {
 	deploymentID := args[0]
 	verbose := viper.GetBool("verbose")
 
+	// Ctrl-C, SIGTERM and --timeout interrupt terraform (the deployment record is still updated)
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	opCtx, stop := operationContext(timeout)
+	defer stop()
+
 	// Get deployment
 	deployment, err := globalStore.Get(ctx, deploymentID)
 	if err != nil {
@@ -50,22 +62,52 @@ func runDestroy(cmd *cobra.Command, args []string) error {
 
 	// Check if already destroyed
 	if deployment.Status == store.DeploymentStatusDestroyed {
-		fmt.Printf("⚠️  Deployment %s is already destroyed\n", deploymentID)
+		fmt.Fprintf(console.Stdout, "⚠️  Deployment %s is already destroyed\n", deploymentID)
 		return nil
 	}
 
+	// Exported Terraform is applied (and destroyed) outside of scai
+	if deployment.Status == store.DeploymentStatusPlanned {
+		return fmt.Errorf("deployment %s was exported to %s with --plan-out and never applied by scai: run destroy from that directory, and 'scia delete %s' to remove the record",
+			deploymentID, deployment.PlanOutDir, deploymentID)
+	}
+
+	// Records imported by reconcile have no Terraform directory to destroy from: fail before
+	// asking for confirmation
+	if deployment.TerraformDir == "" {
+		return fmt.Errorf("deployment %s has no Terraform directory (imported by 'scia reconcile'): scai cannot destroy it. "+
+			"Delete its resources manually (ARNs listed by 'scia show %s'), then remove the record with 'scia delete %s'",
+			deploymentID, deploymentID, deploymentID)
+	}
+
+	// Refuse to run alongside another deploy or destroy of the deployment
+	if err := globalStore.Lock(ctx, deploymentID, store.LockOwner()); err != nil {
+		return err
+	}
+	defer func() { _ = globalStore.Unlock(ctx, deploymentID, store.LockOwner()) }()
+
 	// Display deployment information
-	fmt.Println()
-	fmt.Println("═══════════════════════════════════════════════════════════════")
-	fmt.Printf("  DESTROY DEPLOYMENT: %s\n", deployment.AppName)
-	fmt.Println("═══════════════════════════════════════════════════════════════")
-	fmt.Println()
-	fmt.Printf("   ID:           %s\n", deployment.ID)
-	fmt.Printf("   App Name:     %s\n", deployment.AppName)
-	fmt.Printf("   Strategy:     %s\n", deployment.Strategy)
-	fmt.Printf("   Region:       %s\n", deployment.Region)
-	fmt.Printf("   Status:       %s\n", deployment.Status)
-	fmt.Println()
+	fmt.Fprintln(console.Stdout)
+	fmt.Fprintln(console.Stdout, "═══════════════════════════════════════════════════════════════")
+	fmt.Fprintf(console.Stdout, "  DESTROY DEPLOYMENT: %s\n", deployment.AppName)
+	fmt.Fprintln(console.Stdout, "═══════════════════════════════════════════════════════════════")
+	fmt.Fprintln(console.Stdout)
+	fmt.Fprintf(console.Stdout, "   ID:           %s\n", deployment.ID)
+	fmt.Fprintf(console.Stdout, "   App Name:     %s\n", deployment.AppName)
+	fmt.Fprintf(console.Stdout, "   Strategy:     %s\n", deployment.Strategy)
+	fmt.Fprintf(console.Stdout, "   Region:       %s\n", deployment.Region)
+	fmt.Fprintf(console.Stdout, "   Status:       %s\n", deployment.Status)
+	fmt.Fprintln(console.Stdout)
+
+	// Preview what terraform would delete before asking for confirmation
+	if showPlan, _ := cmd.Flags().GetBool("plan"); showPlan {
+		if pulumiDeployment(deployment) {
+			return fmt.Errorf("--plan is not supported for Pulumi deployments: run 'pulumi preview --destroy --stack %s' in %s", pulumi.StackName(deployment.ID), deployment.TerraformDir)
+		}
+		if err := previewDestroy(opCtx, deployment.TerraformDir, verbose); err != nil {
+			return canceledError(opCtx, err)
+		}
+	}
 
 	// Get confirmation unless --yes flag is set
 	autoApprove, _ := cmd.Flags().GetBool("yes")
@@ -89,11 +131,6 @@ func runDestroy(cmd *cobra.Command, args []string) error {
 		pterm.Success.Println("Auto-confirmed with --yes flag")
 	}
 
-	// Check if terraform directory exists
-	if deployment.TerraformDir == "" {
-		return fmt.Errorf("terraform directory not found in deployment record")
-	}
-
 	// Execute terraform destroy
 	pterm.Info.Println("Destroying infrastructure...")
 	if verbose {
@@ -102,19 +139,22 @@ func runDestroy(cmd *cobra.Command, args []string) error {
 	pterm.Info.Println("This may take several minutes...")
 	pterm.Println()
 
-	tfBin := viper.GetString("terraform.bin")
 	// Always use verbose for destroy to show progress
-	executor, err := terraform.NewExecutor(deployment.TerraformDir, tfBin, true)
+	executor, err := newDeploymentExecutor(deployment, true)
 	if err != nil {
-		return fmt.Errorf("failed to create terraform executor: %w", err)
+		return err
 	}
+	executor.SetContext(opCtx)
 
-	// Run terraform destroy
+	// Run terraform destroy (the audit log keeps the status the destroy started from)
+	if err := globalStore.AddEvent(ctx, deploymentID, deployment.Status, "Destroy started"); err != nil && verbose {
+		pterm.Warning.Printf("Failed to record deployment event: %v\n", err)
+	}
 	if err := executor.Destroy(); err != nil {
 		// Update deployment status to failed
-		_ = globalStore.UpdateStatus(ctx, deploymentID, store.DeploymentStatusFailed,
-			fmt.Sprintf("terraform destroy failed: %v", err))
-		return fmt.Errorf("terraform destroy failed: %w", err)
+		err = canceledError(opCtx, fmt.Errorf("terraform destroy failed: %w", err))
+		_ = globalStore.UpdateStatus(ctx, deploymentID, store.DeploymentStatusFailed, err.Error())
+		return err
 	}
 
 	// Update deployment status to destroyed
@@ -132,3 +172,68 @@ func runDestroy(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// pulumiDeployment reports whether deployment was deployed with the pulumi engine
+func pulumiDeployment(deployment *store.Deployment) bool {
+	return deployment.Config != nil && deployment.Config.IacEngine == deployer.EnginePulumi
+}
+
+// newDeploymentExecutor returns the executor of the engine deployment was deployed with, in
+// its Terraform (or Pulumi) directory
+func newDeploymentExecutor(deployment *store.Deployment, verbose bool) (deployer.IacExecutor, error) {
+	if pulumiDeployment(deployment) {
+		executor, err := deployer.NewIacExecutor(deployer.EnginePulumi, deployment.TerraformDir, viper.GetString("iac.pulumi.bin"), deployment.ID, verbose)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create pulumi executor: %w", err)
+		}
+		return executor, nil
+	}
+
+	executor, err := deployer.NewIacExecutor(deployer.EngineTerraform, deployment.TerraformDir, viper.GetString("terraform.bin"), deployment.ID, verbose)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create terraform executor: %w", err)
+	}
+	return executor, nil
+}
+
+// previewDestroy runs terraform plan -destroy in tfDir and lists the resources it would delete
+func previewDestroy(ctx context.Context, tfDir string, verbose bool) error {
+	if tfDir == "" {
+		return fmt.Errorf("terraform directory not found in deployment record")
+	}
+
+	pterm.Info.Println("Planning destroy...")
+	executor, err := terraform.NewExecutor(tfDir, viper.GetString("terraform.bin"), verbose)
+	if err != nil {
+		return fmt.Errorf("failed to create terraform executor: %w", err)
+	}
+	executor.SetContext(ctx)
+	changes, err := executor.PlanDestroy()
+	if err != nil {
+		return fmt.Errorf("terraform plan -destroy failed: %w", err)
+	}
+
+	fmt.Fprintln(console.Stdout)
+	if len(changes) == 0 {
+		pterm.Info.Println("The Terraform state has no resources to destroy")
+		fmt.Fprintln(console.Stdout)
+		return nil
+	}
+
+	fmt.Fprintf(console.Stdout, "   %d resource(s) will be destroyed:\n", len(changes))
+	for _, count := range terraform.CountByType(changes) {
+		fmt.Fprintf(console.Stdout, "     - %s\n", count)
+	}
+	if verbose {
+		fmt.Fprintln(console.Stdout)
+		for _, change := range changes {
+			fmt.Fprintf(console.Stdout, "     %s\n", change.Address)
+		}
+	}
+	fmt.Fprintln(console.Stdout)
+
+	if slices.ContainsFunc(changes, func(c terraform.ResourceChange) bool { return c.Type == "aws_db_instance" }) {
+		pterm.Warning.Println("The RDS database and its data will be deleted")
+	}
+	return nil
+}
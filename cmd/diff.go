@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/Smana/scai/internal/analyzer"
+	"github.com/Smana/scai/internal/console"
+	"github.com/Smana/scai/internal/store"
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <deployment-id> [other-deployment-id]",
+	Short: "Compare two deployments, or a deployment with its repository",
+	Long: `Compare the strategy, region, analysis and configuration (instance sizing, ports,
+start command, ...) of two deployment records field by field.
+
+With a single deployment ID, the repository is analyzed again and compared with the
+analysis stored for the deployment, to see what a redeploy would change.
+
+Example:
+  scia diff abc123de-f456-7890-abcd-ef1234567890 0f1e2d3c-b4a5-6789-0abc-def123456789
+  scia diff abc123de-f456-7890-abcd-ef1234567890`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runDiff,
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	if globalStore == nil {
+		return fmt.Errorf("database not initialized")
+	}
+
+	ctx := context.Background()
+
+	from, err := globalStore.Get(ctx, args[0])
+	if err != nil {
+		return fmt.Errorf("failed to get deployment: %w", err)
+	}
+
+	var to *store.Deployment
+	fromLabel, toLabel := shortID(from.ID), "current repo"
+	if len(args) == 2 {
+		to, err = globalStore.Get(ctx, args[1])
+		if err != nil {
+			return fmt.Errorf("failed to get deployment: %w", err)
+		}
+		toLabel = shortID(to.ID)
+	} else {
+		to, err = analyzeCurrentRepo(from)
+		if err != nil {
+			return err
+		}
+	}
+
+	diffs := store.Diff(from, to)
+
+	pterm.Println()
+	pterm.DefaultHeader.WithFullWidth().Printf("DIFF: %s (%s) → %s", from.AppName, fromLabel, toLabel)
+	pterm.Println()
+
+	if len(diffs) == 0 {
+		pterm.Success.Println("No differences")
+		return nil
+	}
+
+	tableData := pterm.TableData{{"Field", fromLabel, toLabel}}
+	for _, diff := range diffs {
+		tableData = append(tableData, []string{diff.Field, diff.Old, diff.New})
+	}
+	if err := pterm.DefaultTable.WithHasHeader().WithData(tableData).Render(); err != nil {
+		return fmt.Errorf("failed to render table: %w", err)
+	}
+	fmt.Fprintln(console.Stdout)
+	pterm.Info.Printf("%d field(s) differ\n", len(diffs))
+
+	return nil
+}
+
+// analyzeCurrentRepo analyzes the deployment's repository again and returns a copy of the
+// deployment with the fresh analysis and the configuration fields derived from it
+func analyzeCurrentRepo(deployment *store.Deployment) (*store.Deployment, error) {
+	if deployment.RepoURL == "" {
+		return nil, fmt.Errorf("deployment %s has no repository to analyze", deployment.ID)
+	}
+
+	workDir := viper.GetString("workdir.path")
+	if err := os.MkdirAll(workDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create work directory: %w", err)
+	}
+
+	a := analyzer.NewAnalyzer(workDir, viper.GetBool("verbose"))
+	a.SetMaxDepth(viper.GetInt("analyzer.max_depth"))
+	a.SetIgnoreDirs(viper.GetStringSlice("analyzer.ignore_dirs"))
+	a.SetZipLimits(zipLimits())
+	if deployment.Analysis != nil && deployment.Analysis.AppDir != "" && deployment.Analysis.AppDir != "." {
+		// Analyze the same app of a monorepo
+		a.SetAppDir(deployment.Analysis.AppDir)
+	}
+
+	pterm.Info.Printf("Analyzing %s...\n", deployment.RepoURL)
+	analysis, err := a.Analyze(deployment.RepoURL)
+	if err != nil {
+		return nil, fmt.Errorf("repository analysis failed: %w", err)
+	}
+
+	current := *deployment
+	current.Analysis = analysis
+	current.RepoCommitSHA = analysis.CommitSHA
+	if deployment.Config != nil {
+		config := *deployment.Config
+		config.Framework = analysis.Framework
+		config.Language = analysis.Language
+		config.Port = analysis.Port
+		config.AppDir = analysis.AppDir
+		config.StartCommand = analysis.StartCommand
+		config.BuildCommand = analysis.BuildCommand
+		config.EnvVars = analysis.EnvVars
+		config.HealthCheckPath = analysis.HealthCheckPath
+		current.Config = &config
+	}
+
+	return &current, nil
+}
+
+// shortID returns the first 8 characters of a deployment ID
+func shortID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
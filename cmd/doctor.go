@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/Smana/scai/internal/config"
+	"github.com/Smana/scai/internal/requirements"
+	"github.com/Smana/scai/internal/terraform"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the tools SCAI depends on",
+	Long: `Check that the tools SCAI depends on are installed: OpenTofu (or Terraform),
+the AWS CLI, and Docker or Ollama for the Ollama LLM provider.
+
+With --fix, a missing OpenTofu is downloaded to ~/.scai/bin (release checksum verified)
+and terraform.bin is set to its path in ~/.scai.yaml.
+
+Example:
+  scia doctor
+  scia doctor --fix`,
+	Args: cobra.NoArgs,
+	RunE: runDoctor,
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+
+	doctorCmd.Flags().Bool("fix", false, "Install missing OpenTofu to ~/.scai/bin and update terraform.bin")
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+	ctx := context.Background()
+	fix, _ := cmd.Flags().GetBool("fix")
+
+	provider := viper.GetString("llm.provider")
+	useDocker := provider == providerOllama && viper.GetBool("llm.ollama.use_docker")
+	reqs, err := requirements.CheckRequirements(provider, useDocker, viper.GetString("terraform.bin"))
+	if err != nil {
+		return err
+	}
+
+	pterm.DefaultSection.Println("System requirements")
+	for _, req := range reqs {
+		pterm.Println("  " + requirements.FormatRequirementStatus(req))
+	}
+
+	if fix && !reqs[0].Installed {
+		pterm.Println()
+		binPath, err := installOpenTofu(ctx)
+		if err != nil {
+			return err
+		}
+		if err := setTerraformBin(binPath); err != nil {
+			return err
+		}
+		reqs[0].Installed = true
+	}
+
+	if missing := requirements.GetMissingRequired(reqs); len(missing) > 0 {
+		pterm.Println()
+		if !reqs[0].Installed {
+			pterm.Info.Println("Run 'scia doctor --fix' to install OpenTofu")
+		}
+		return fmt.Errorf("missing required dependencies: %s", strings.Join(missing, ", "))
+	}
+
+	pterm.Println()
+	pterm.Success.Println("All required dependencies are installed")
+	return nil
+}
+
+// installOpenTofu installs OpenTofu to ~/.scai/bin and checks the installed binary runs
+func installOpenTofu(ctx context.Context) (string, error) {
+	installDir, err := requirements.InstallDir()
+	if err != nil {
+		return "", err
+	}
+
+	spinner, _ := pterm.DefaultSpinner.Start(fmt.Sprintf("Installing OpenTofu %s to %s...", requirements.OpenTofuVersion, installDir))
+	binPath, err := requirements.InstallOpenTofu(ctx, installDir)
+	if err != nil {
+		spinner.Fail("OpenTofu installation failed")
+		return "", fmt.Errorf("failed to install OpenTofu: %w", err)
+	}
+
+	executor, err := terraform.NewExecutor(installDir, binPath, false)
+	if err != nil {
+		spinner.Fail("OpenTofu installation failed")
+		return "", err
+	}
+	version, err := executor.Version(ctx)
+	if err != nil {
+		spinner.Fail("OpenTofu installation failed")
+		return "", fmt.Errorf("installed OpenTofu does not run: %w", err)
+	}
+
+	spinner.Success(fmt.Sprintf("Installed %s: %s", strings.SplitN(strings.TrimSpace(version), "\n", 2)[0], binPath))
+	return binPath, nil
+}
+
+// setTerraformBin sets terraform.bin in the configuration file (in the active profile, if any)
+func setTerraformBin(binPath string) error {
+	if !config.ConfigExists() {
+		pterm.Info.Printfln("No configuration file: run 'scia init' or set terraform.bin to %s", binPath)
+		return nil
+	}
+
+	cfg, err := config.ReadConfig()
+	if err != nil {
+		return err
+	}
+
+	target := cfg
+	if profileName := viper.GetString("profile"); profileName != "" {
+		if profile, ok := cfg.Profiles[profileName]; ok {
+			target = profile
+		}
+	}
+	target.Terraform.Binary = binPath
+
+	if err := config.WriteConfig(cfg); err != nil {
+		return fmt.Errorf("failed to write configuration: %w", err)
+	}
+	pterm.Success.Printfln("terraform.bin set to %s", binPath)
+	return nil
+}
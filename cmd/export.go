@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+
+	"github.com/Smana/scai/internal/store"
+)
+
+const (
+	exportFormatJSON = "json"
+	exportFormatCSV  = "csv"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export deployment history to a file",
+	Long: `Export deployments from the local database, including configuration and outputs,
+to a portable JSON or CSV file. Use 'scia import' to load the file on another machine.
+
+Example:
+  scia export --output deployments.json
+  scia export --format csv --output deployments.csv
+  scia export --region us-east-1 --status succeeded --output backup.json`,
+	RunE: runExport,
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+
+	// Export-specific flags
+	exportCmd.Flags().String("format", exportFormatJSON, "Export format (json, csv)")
+	exportCmd.Flags().StringP("output", "o", "", "Output file (default: stdout)")
+	addDeploymentFilterFlags(exportCmd)
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	if globalStore == nil {
+		return fmt.Errorf("database not initialized")
+	}
+
+	ctx := context.Background()
+
+	format, _ := cmd.Flags().GetString("format")
+	if format != exportFormatJSON && format != exportFormatCSV {
+		return fmt.Errorf("unsupported export format: %s (use json or csv)", format)
+	}
+
+	deployments, err := globalStore.List(ctx, deploymentFilterFromFlags(cmd))
+	if err != nil {
+		return fmt.Errorf("failed to list deployments: %w", err)
+	}
+
+	// Write to stdout unless an output file is given
+	var w io.Writer = os.Stdout
+	output, _ := cmd.Flags().GetString("output")
+	if output != "" {
+		file, err := os.Create(output) // #nosec G304 -- output path is provided by the user
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer func() { _ = file.Close() }()
+		w = file
+	}
+
+	if format == exportFormatCSV {
+		err = store.WriteCSV(w, deployments)
+	} else {
+		err = store.WriteJSON(w, deployments)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to export deployments: %w", err)
+	}
+
+	if output != "" {
+		pterm.Success.Printf("Exported %d deployment(s) to %s\n", len(deployments), output)
+	}
+
+	return nil
+}
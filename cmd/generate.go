@@ -0,0 +1,245 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/Smana/scai/internal/analyzer"
+	"github.com/Smana/scai/internal/cloud"
+	"github.com/Smana/scai/internal/console"
+	"github.com/Smana/scai/internal/deployer"
+	"github.com/Smana/scai/internal/llm"
+	"github.com/Smana/scai/internal/parser"
+	"github.com/Smana/scai/internal/terraform"
+)
+
+var generateCmd = &cobra.Command{
+	Use:   "generate [prompt] [repository_url_zip_or_directory]",
+	Short: "Generate the Terraform of a deployment without deploying it",
+	Long: `Analyze a repository, choose a deployment strategy and write the generated Terraform
+to a directory, to run Terraform yourself (e.g. in a CI pipeline).
+
+Nothing is deployed and no AWS call is made: no credential or region check, no state
+bucket (Terraform keeps its state locally unless you add a backend) and no deployment
+record. Without an available LLM, the strategy must be given in the prompt or with --strategy.
+
+Example:
+  scia generate "Deploy this Flask app on AWS" https://github.com/user/flask-app --out ./infra
+  scia generate --strategy kubernetes --set eks_desired_nodes=3 "Deploy the API" ./api --out ./infra`,
+	Args: cobra.ExactArgs(2),
+	RunE: runGenerate,
+}
+
+func init() {
+	rootCmd.AddCommand(generateCmd)
+
+	generateCmd.Flags().String("out", "", "Directory the Terraform files are written to (required)")
+	generateCmd.Flags().String("strategy", "", "Force deployment strategy (vm, kubernetes, serverless)")
+	generateCmd.Flags().String("region", "", "AWS region (overrides config)")
+	generateCmd.Flags().Bool("strict-prompt", false, "Stop when the LLM misparses the prompt configuration instead of ignoring it, offering to re-prompt the model")
+	generateCmd.Flags().StringArray("set", nil, "Override a plan parameter as key=value, e.g. ec2_instance_type=t3.large (repeatable)")
+	generateCmd.Flags().StringArray("tag", nil, "Tag applied to all AWS resources as key=value (repeatable)")
+	generateCmd.Flags().String("app-dir", "", "Application directory to deploy in a monorepo (relative to the repository root)")
+	generateCmd.Flags().String("with-database", "", "Provision an RDS database (postgres, mysql)")
+	addK8sResourceFlags(generateCmd)
+	addRulesFlags(generateCmd)
+	_ = generateCmd.MarkFlagRequired("out")
+}
+
+func runGenerate(cmd *cobra.Command, args []string) error {
+	userPrompt, repoSource := args[0], args[1]
+	verbose := viper.GetBool("verbose")
+	outDir, _ := cmd.Flags().GetString("out")
+	ctx := cmd.Context()
+
+	if err := analyzer.ValidateSource(ctx, repoSource); err != nil {
+		return err
+	}
+
+	// The LLM is optional: without it, only the deterministic prompt patterns apply
+	var llmClient *llm.Client
+	providerManager, providerConfig, err := initializeLLMProvider(ctx, verbose)
+	if err != nil {
+		fmt.Fprintln(console.Stdout, "⚠️  No LLM available, using the prompt patterns only")
+		if verbose {
+			fmt.Fprintf(console.Stdout, "   %v\n", err)
+		}
+		fmt.Fprintln(console.Stdout)
+	} else {
+		llmClient = llm.NewClientWithManager(providerManager, providerConfig)
+	}
+	if err := applyCustomRules(cmd, llmClient, verbose); err != nil {
+		return err
+	}
+
+	parsedConfig := parser.ParsePrompt(userPrompt)
+	if llmClient != nil {
+		if parsedConfig, err = parsePromptConfig(ctx, cmd, llmClient, userPrompt); err != nil {
+			return err
+		}
+	}
+
+	setPairs, _ := cmd.Flags().GetStringArray("set")
+	setConfig, err := parser.ParseSetOverrides(setPairs)
+	if err != nil {
+		return err
+	}
+
+	tagFlags, _ := cmd.Flags().GetStringArray("tag")
+	tags, err := parseTags(viper.GetStringMapString("cloud.default_tags"), tagFlags)
+	if err != nil {
+		return err
+	}
+
+	workDir := viper.GetString("workdir.path")
+	if err := os.MkdirAll(workDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create work directory: %w", err)
+	}
+
+	fmt.Fprintln(console.Stdout, "📊 Analyzing repository...")
+	repoAnalyzer := analyzer.NewAnalyzer(workDir, verbose)
+	repoAnalyzer.SetMaxDepth(viper.GetInt("analyzer.max_depth"))
+	repoAnalyzer.SetIgnoreDirs(viper.GetStringSlice("analyzer.ignore_dirs"))
+	repoAnalyzer.SetZipLimits(zipLimits())
+	if appDir, _ := cmd.Flags().GetString("app-dir"); appDir != "" {
+		repoAnalyzer.SetAppDir(appDir)
+	}
+	analysis, err := repoAnalyzer.Analyze(repoSource)
+	if err != nil {
+		return fmt.Errorf("repository analysis failed: %w", err)
+	}
+
+	// Sizing from defaults.* (or the built-in defaults), then the prompt, then --set
+	genConfig := &deployer.DeployConfig{
+		Analysis:                  analysis,
+		UserPrompt:                userPrompt,
+		WorkDir:                   workDir,
+		AWSRegion:                 viper.GetString("cloud.default_region"),
+		Verbose:                   verbose,
+		Tags:                      tags,
+		EC2InstanceType:           viper.GetString("defaults.ec2_instance_type"),
+		EC2VolumeSize:             viper.GetInt("defaults.ec2_volume_size"),
+		LambdaMemory:              viper.GetInt("defaults.lambda_memory"),
+		LambdaTimeout:             viper.GetInt("defaults.lambda_timeout"),
+		LambdaReservedConcurrency: viper.GetInt("defaults.lambda_reserved_concurrency"),
+		EKSNodeType:               viper.GetString("defaults.eks_node_type"),
+		EKSMinNodes:               viper.GetInt("defaults.eks_min_nodes"),
+		EKSMaxNodes:               viper.GetInt("defaults.eks_max_nodes"),
+		EKSDesiredNodes:           viper.GetInt("defaults.eks_desired_nodes"),
+		EKSNodeVolumeSize:         viper.GetInt("defaults.eks_node_volume_size"),
+		EKSVersion:                viper.GetString("terraform.eks.version"),
+		AWSProviderVersion:        viper.GetString("terraform.aws_provider_version"),
+		DatabaseInstanceClass:     viper.GetString("defaults.db_instance_class"),
+		DatabaseStorage:           viper.GetInt("defaults.db_storage"),
+		LogRetentionDays:          viper.GetInt("defaults.log_retention_days"),
+		AWSRoleARN:                cloud.RoleARN(),
+	}
+	genConfig.DatabaseEngine, _ = cmd.Flags().GetString("with-database")
+	genConfig.K8sResources = k8sResourcesFromFlags(cmd, analysis)
+	parser.ApplyConfig(genConfig, parsedConfig)
+	parser.ApplyConfig(genConfig, setConfig)
+
+	if region, _ := cmd.Flags().GetString("region"); region != "" && setConfig.Region == "" {
+		genConfig.AWSRegion = region
+	}
+	if genConfig.AWSRegion == "" {
+		return fmt.Errorf("no AWS region: set cloud.default_region in ~/.scai.yaml or use --region")
+	}
+
+	// Strategy: --set, --strategy, the prompt, then the LLM recommendation
+	if strategy, _ := cmd.Flags().GetString("strategy"); strategy != "" && setConfig.Strategy == "" {
+		genConfig.Strategy = strategy
+	}
+	if genConfig.Strategy == "" {
+		if llmClient == nil {
+			return fmt.Errorf("no deployment strategy: use --strategy (vm, kubernetes, serverless) or name it in the prompt")
+		}
+		fmt.Fprintln(console.Stdout, "🤖 Determining deployment strategy...")
+		decision, err := decideStrategy(ctx, llmClient.StrategyDecider(), parsedConfig.CleanedPrompt, analysis)
+		if err != nil {
+			return err
+		}
+		genConfig.Strategy = decision.Strategy
+	}
+
+	if err := validateDatabase(genConfig.Strategy, genConfig.DatabaseEngine); err != nil {
+		return err
+	}
+	if err := validateVMSource(genConfig); err != nil {
+		return err
+	}
+	genConfig.IacEngine = viper.GetString("iac.engine")
+	if err := deployer.CheckEngine(genConfig); err != nil {
+		return err
+	}
+	if genConfig.LogRetentionDays != 0 {
+		if err := terraform.ValidateLogRetentionDays(genConfig.LogRetentionDays); err != nil {
+			return fmt.Errorf("invalid defaults.log_retention_days: %w", err)
+		}
+	}
+	if err := terraform.ValidateProviderVersion(genConfig.AWSProviderVersion); err != nil {
+		return fmt.Errorf("invalid terraform.aws_provider_version: %w", err)
+	}
+	switch genConfig.Strategy {
+	case "kubernetes":
+		if err := terraform.ValidateEKSVersion(genConfig.EKSVersion); err != nil {
+			return fmt.Errorf("invalid terraform.eks.version: %w", err)
+		}
+		if err := validateNATGateway(genConfig); err != nil {
+			return err
+		}
+		if err := terraform.ValidateK8sResources(genConfig.K8sResources); err != nil {
+			return fmt.Errorf("invalid container resources: %w", err)
+		}
+	case "serverless":
+		if err := terraform.ValidateLambdaSizing(genConfig.LambdaMemory, genConfig.LambdaTimeout); err != nil {
+			return fmt.Errorf("invalid Lambda sizing: %w", err)
+		}
+	}
+	if genConfig.LambdaArchitecture, err = normalizeLambdaArchitecture(viper.GetString("defaults.lambda_architecture")); err != nil {
+		return err
+	}
+
+	absOutDir, err := filepath.Abs(outDir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", outDir, err)
+	}
+
+	generated := "Terraform"
+	if genConfig.IacEngine == deployer.EnginePulumi {
+		generated = "Pulumi program"
+	}
+	fmt.Fprintf(console.Stdout, "📝 Generating %s (%s, %s)...\n", generated, genConfig.Strategy, genConfig.AWSRegion)
+	d := deployer.NewDeployer(genConfig, nil)
+	d.SetLLMClient(llmClient)
+	if _, err := d.Generate(absOutDir); err != nil {
+		return err
+	}
+
+	console.Emit(console.Event{Phase: "generate", Status: console.StatusSucceeded, Data: map[string]any{
+		"strategy": genConfig.Strategy,
+		"region":   genConfig.AWSRegion,
+		"out_dir":  absOutDir,
+	}})
+
+	if genConfig.IacEngine == deployer.EnginePulumi {
+		fmt.Fprintln(console.Stdout)
+		fmt.Fprintf(console.Stdout, "✅ Pulumi program written to %s\n", absOutDir)
+		fmt.Fprintln(console.Stdout)
+		fmt.Fprintln(console.Stdout, "💡 Nothing was deployed. Run:")
+		fmt.Fprintf(console.Stdout, "   cd %s && npm install && pulumi stack init && pulumi up\n", absOutDir)
+		return nil
+	}
+
+	tfBin := filepath.Base(viper.GetString("terraform.bin"))
+	fmt.Fprintln(console.Stdout)
+	fmt.Fprintf(console.Stdout, "✅ Terraform written to %s\n", absOutDir)
+	fmt.Fprintln(console.Stdout)
+	fmt.Fprintln(console.Stdout, "💡 Nothing was deployed. Add a backend block to keep the state remotely, then run:")
+	fmt.Fprintf(console.Stdout, "   cd %s && %s init && %s apply\n", absOutDir, tfBin, tfBin)
+	return nil
+}
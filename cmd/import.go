@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+
+	"github.com/Smana/scai/internal/store"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import [file]",
+	Short: "Import deployment history from a file",
+	Long: `Import deployments from a file created by 'scia export' into the local database.
+Deployments whose ID already exists are skipped.
+
+The format is detected from the file extension (.json or .csv) unless --format is set.
+
+Example:
+  scia import deployments.json
+  scia import backup.txt --format csv`,
+	Args: cobra.ExactArgs(1),
+	RunE: runImport,
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+
+	// Import-specific flags
+	importCmd.Flags().String("format", "", "Import format (json, csv; default: from file extension)")
+}
+
+func runImport(cmd *cobra.Command, args []string) error {
+	if globalStore == nil {
+		return fmt.Errorf("database not initialized")
+	}
+
+	ctx := context.Background()
+	path := args[0]
+
+	format, _ := cmd.Flags().GetString("format")
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	}
+	if format != exportFormatJSON && format != exportFormatCSV {
+		return fmt.Errorf("unsupported import format: %q (use --format json or csv)", format)
+	}
+
+	file, err := os.Open(path) // #nosec G304 -- input path is provided by the user
+	if err != nil {
+		return fmt.Errorf("failed to open import file: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	var deployments []*store.Deployment
+	if format == exportFormatCSV {
+		deployments, err = store.ReadCSV(file)
+	} else {
+		deployments, err = store.ReadJSON(file)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read import file: %w", err)
+	}
+
+	// Index existing deployments to skip duplicates
+	existing, err := globalStore.List(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to list deployments: %w", err)
+	}
+	known := make(map[string]bool, len(existing))
+	for _, dep := range existing {
+		known[dep.ID] = true
+	}
+
+	imported, skipped := 0, 0
+	for _, deployment := range deployments {
+		if deployment.ID == "" || known[deployment.ID] {
+			skipped++
+			continue
+		}
+
+		if err := globalStore.Create(ctx, deployment); err != nil {
+			pterm.Warning.Printf("Failed to import %s: %v\n", deployment.ID, err)
+			skipped++
+			continue
+		}
+		known[deployment.ID] = true
+		imported++
+	}
+
+	pterm.Success.Printf("Imported %d deployment(s), skipped %d\n", imported, skipped)
+
+	return nil
+}
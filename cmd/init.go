@@ -2,11 +2,13 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 
 	"github.com/charmbracelet/huh"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 
 	"github.com/Smana/scai/internal/backend"
 	"github.com/Smana/scai/internal/cloud"
@@ -19,7 +21,7 @@ const (
 	providerOllama = "ollama"
 	providerGemini = "gemini"
 	providerOpenAI = "openai"
-	regionUSEast1  = "us-east-1"
+	providerGCP    = "gcp"
 )
 
 var initCmd = &cobra.Command{
@@ -28,16 +30,36 @@ var initCmd = &cobra.Command{
 	Long: `Interactive wizard to help onboard new users by configuring:
 - LLM provider (Ollama, Gemini, or OpenAI)
 - Cloud provider (AWS or GCP)
-- Default region
+- Default region (opt-in regions not enabled for the account are hidden;
+  --include-opt-in lists them, marked "requires opt-in")
 - Terraform backend (S3 bucket)
-- Requirements check (OpenTofu, Docker, etc.)
-
-The configuration will be saved to ~/.scai.yaml`,
+- Requirements check (OpenTofu, Docker, etc.); with --install-deps, a missing
+  OpenTofu is downloaded to ~/.scai/bin and set as terraform.bin
+
+The configuration will be saved to ~/.scai.yaml. With --profile, it is saved as
+a named profile of that file instead (created or updated), leaving the other
+settings and profiles unchanged.
+
+Example:
+  scia init
+  scia init --profile prod
+  scia init --install-deps
+  scia init --include-opt-in`,
 	RunE: runInit,
 }
 
+var (
+	refreshRegions bool
+	includeOptIn   bool
+	installDeps    bool
+)
+
 func init() {
 	rootCmd.AddCommand(initCmd)
+
+	initCmd.Flags().BoolVar(&refreshRegions, "refresh-regions", false, "Ignore the cached AWS region list and fetch it again")
+	initCmd.Flags().BoolVar(&includeOptIn, "include-opt-in", false, "List opt-in AWS regions not enabled for the account in region selection")
+	initCmd.Flags().BoolVar(&installDeps, "install-deps", false, "Install OpenTofu to ~/.scai/bin when neither tofu nor terraform is found")
 }
 
 func runInit(cmd *cobra.Command, args []string) error {
@@ -47,13 +69,34 @@ func runInit(cmd *cobra.Command, args []string) error {
 	fmt.Println("This wizard will help you set up SCAI for the first time.")
 	fmt.Println()
 
-	// Check if config already exists
-	if config.ConfigExists() {
+	profileName := viper.GetString("profile")
+
+	// Base configuration the wizard result is written to (as a whole, or as a profile)
+	var fileCfg *config.Config
+	exists := config.ConfigExists()
+	if profileName != "" {
+		fileCfg = config.DefaultConfig()
+		if exists {
+			var err error
+			if fileCfg, err = config.ReadConfig(); err != nil {
+				return err
+			}
+		}
+		_, exists = fileCfg.Profiles[profileName]
+	}
+
+	// Check if config (or profile) already exists
+	if exists {
+		title := "Configuration file already exists"
+		if profileName != "" {
+			title = fmt.Sprintf("Profile %q already exists", profileName)
+		}
+
 		var overwrite bool
 		form := huh.NewForm(
 			huh.NewGroup(
 				huh.NewConfirm().
-					Title("Configuration file already exists").
+					Title(title).
 					Description("Do you want to overwrite it?").
 					Value(&overwrite),
 			),
@@ -82,28 +125,38 @@ func runInit(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("cloud configuration failed: %w", err)
 	}
 
-	// Step 3: Terraform Backend Configuration
-	if err := configureTerraformBackend(ctx, cfg); err != nil {
-		return fmt.Errorf("terraform backend configuration failed: %w", err)
+	// Step 3: Terraform Backend Configuration (S3, AWS only)
+	if cfg.Cloud.Provider != providerGCP {
+		if err := configureTerraformBackend(ctx, cfg); err != nil {
+			return fmt.Errorf("terraform backend configuration failed: %w", err)
+		}
 	}
 
 	// Step 4: Requirements Check
-	if err := checkRequirements(cfg); err != nil {
+	if err := checkRequirements(ctx, cfg); err != nil {
 		return fmt.Errorf("requirements check failed: %w", err)
 	}
 
 	// Validate configuration
 	if err := config.ValidateConfig(cfg); err != nil {
+		if profileName != "" {
+			return fmt.Errorf("profile %q validation failed: %w", profileName, err)
+		}
 		return fmt.Errorf("configuration validation failed: %w", err)
 	}
 
 	// Write configuration
-	if err := config.WriteConfig(cfg); err != nil {
+	if profileName != "" {
+		fileCfg.SetProfile(profileName, cfg)
+	} else {
+		fileCfg = cfg
+	}
+	if err := config.WriteConfig(fileCfg); err != nil {
 		return fmt.Errorf("failed to write configuration: %w", err)
 	}
 
 	// Display summary
-	displaySummary(cfg)
+	displaySummary(cfg, profileName)
 
 	return nil
 }
@@ -321,7 +374,7 @@ func configureCloudProvider(ctx context.Context, cfg *config.Config) error {
 				Description("Choose your cloud platform").
 				Options(
 					huh.NewOption("AWS", "aws"),
-					huh.NewOption("GCP (Coming Soon)", "gcp"),
+					huh.NewOption("GCP (configuration only, deployments coming soon)", providerGCP),
 				).
 				Value(&provider),
 		),
@@ -331,13 +384,12 @@ func configureCloudProvider(ctx context.Context, cfg *config.Config) error {
 		return err
 	}
 
-	if provider == "gcp" {
-		fmt.Println("\n⚠️  GCP support is not yet implemented. Please choose AWS.")
-		return fmt.Errorf("GCP not yet supported")
-	}
-
 	cfg.Cloud.Provider = provider
 
+	if provider == providerGCP {
+		return configureGCP(cfg)
+	}
+
 	// AWS Region Selection - MANDATORY
 	fmt.Println("\n🔐 Checking AWS credentials...")
 	awsClient, err := cloud.NewAWSClient(ctx)
@@ -354,6 +406,8 @@ func configureCloudProvider(ctx context.Context, cfg *config.Config) error {
 
 	fmt.Println("✓ AWS credentials verified")
 	fmt.Println("\n🌍 Fetching available AWS regions...")
+	awsClient.SetRefreshRegions(refreshRegions)
+	awsClient.SetIncludeOptIn(includeOptIn)
 	regionOpts, err := awsClient.GetRegionForSelect(ctx)
 	if err != nil {
 		fmt.Printf("\n❌ Error: Could not fetch AWS regions: %v\n\n", err)
@@ -369,7 +423,11 @@ func configureCloudProvider(ctx context.Context, cfg *config.Config) error {
 	// Build region options for huh select
 	regionOptions := make([]huh.Option[string], 0, len(regionOpts))
 	for _, region := range regionOpts {
-		regionOptions = append(regionOptions, huh.NewOption(region.Code, region.Code))
+		label := region.Code
+		if region.RequiresOptIn() {
+			label += " (requires opt-in)"
+		}
+		regionOptions = append(regionOptions, huh.NewOption(label, region.Code))
 	}
 
 	var selectedRegion string
@@ -394,6 +452,55 @@ func configureCloudProvider(ctx context.Context, cfg *config.Config) error {
 
 	cfg.Cloud.DefaultRegion = selectedRegion
 	fmt.Printf("\n✓ Region set to: %s\n", selectedRegion)
+	for _, region := range regionOpts {
+		if region.Code == selectedRegion && region.RequiresOptIn() {
+			fmt.Printf("⚠️  %s is not enabled for this account: enable it in the AWS console (Account > AWS Regions) before deploying\n", selectedRegion)
+		}
+	}
+
+	return nil
+}
+
+// configureGCP collects the GCP project, region and credentials. GCP deployments are not
+// supported yet, but the configuration is ready for them.
+func configureGCP(cfg *config.Config) error {
+	fmt.Println("\n⚠️  GCP deployments are not supported yet: the configuration is saved for when they are.")
+	fmt.Println()
+
+	projectID := ""
+	region := "us-central1"
+	credentialsFile := ""
+	gcpForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("GCP Project ID").
+				Description("The project resources are deployed to (e.g. my-project-123456)").
+				Value(&projectID).
+				Validate(func(s string) error {
+					if s == "" {
+						return fmt.Errorf("project ID is required")
+					}
+					return nil
+				}),
+			huh.NewInput().
+				Title("GCP Region").
+				Description("Default region (e.g. us-central1, europe-west1)").
+				Value(&region),
+			huh.NewInput().
+				Title("Service Account Key File (optional)").
+				Description("Leave empty to use Application Default Credentials (gcloud auth application-default login)").
+				Value(&credentialsFile),
+		),
+	)
+
+	if err := gcpForm.Run(); err != nil {
+		return err
+	}
+
+	cfg.Cloud.ProjectID = projectID
+	cfg.Cloud.DefaultRegion = region
+	cfg.Cloud.CredentialsFile = credentialsFile
+	fmt.Printf("\n✓ Project set to: %s (%s)\n", projectID, region)
 
 	return nil
 }
@@ -428,7 +535,7 @@ func configureTerraformBackend(ctx context.Context, cfg *config.Config) error {
 		// List existing S3 buckets
 		fmt.Println("\n🪣 Fetching S3 buckets...")
 
-		// Create S3 manager (region doesn't matter for ListBuckets)
+		// Create S3 manager (region doesn't matter for ListBuckets and GetBucketLocation)
 		s3Manager, err := backend.NewS3Manager(ctx, bucketRegion)
 		if err != nil {
 			return fmt.Errorf("failed to connect to S3: %w\nPlease ensure your AWS credentials are configured correctly", err)
@@ -479,15 +586,7 @@ func configureTerraformBackend(ctx context.Context, cfg *config.Config) error {
 		// Note: ListBuckets doesn't return region, so we need to query it
 		fmt.Println("\n🔍 Determining bucket region...")
 
-		// Try to get bucket location
-		// We need to create a new S3 manager without region specified
-		tempManager, err := backend.NewS3Manager(ctx, regionUSEast1) // us-east-1 works globally
-		if err != nil {
-			return fmt.Errorf("failed to connect to S3: %w", err)
-		}
-
-		// Get bucket location
-		locationResp, err := tempManager.GetBucketLocation(ctx, bucketName)
+		locationResp, err := s3Manager.GetBucketLocation(ctx, bucketName)
 		if err != nil {
 			return fmt.Errorf("failed to get bucket location: %w\nPlease ensure you have access to bucket '%s'", err, bucketName)
 		}
@@ -532,6 +631,9 @@ func configureTerraformBackend(ctx context.Context, cfg *config.Config) error {
 		}
 
 		exists, err := s3Manager.BucketExists(ctx, bucketName)
+		if errors.Is(err, backend.ErrBucketAccessDenied) {
+			return fmt.Errorf("bucket '%s' already exists but you don't have access to it\nBucket names are global: it may belong to another AWS account, choose another name or check your s3:ListBucket permission", bucketName)
+		}
 		if err != nil {
 			fmt.Printf("\n⚠️  Warning: Could not check bucket: %v\n", err)
 			cfg.Terraform.Backend.S3Bucket = bucketName
@@ -585,12 +687,12 @@ func configureTerraformBackend(ctx context.Context, cfg *config.Config) error {
 	return nil
 }
 
-func checkRequirements(cfg *config.Config) error {
+func checkRequirements(ctx context.Context, cfg *config.Config) error {
 	fmt.Println("\n📋 Step 4: Requirements Check")
 	fmt.Println()
 
 	useDocker := cfg.LLM.Provider == providerOllama && cfg.LLM.Ollama.UseDocker
-	reqs, err := requirements.CheckRequirements(cfg.LLM.Provider, useDocker)
+	reqs, err := requirements.CheckRequirements(cfg.LLM.Provider, useDocker, cfg.Terraform.Binary)
 	if err != nil {
 		return err
 	}
@@ -600,6 +702,21 @@ func checkRequirements(cfg *config.Config) error {
 		fmt.Printf("  %s\n", requirements.FormatRequirementStatus(req))
 	}
 
+	// OpenTofu/Terraform comes first: install OpenTofu when asked to
+	if !reqs[0].Installed {
+		if installDeps {
+			fmt.Println()
+			binPath, err := installOpenTofu(ctx)
+			if err != nil {
+				return err
+			}
+			cfg.Terraform.Binary = binPath
+			reqs[0].Installed = true
+		} else {
+			fmt.Println("\n💡 Run 'scia init --install-deps' (or 'scia doctor --fix') to install OpenTofu")
+		}
+	}
+
 	missing := requirements.GetMissingRequired(reqs)
 	if len(missing) > 0 {
 		fmt.Println("\n⚠️  Missing required dependencies:")
@@ -612,7 +729,7 @@ func checkRequirements(cfg *config.Config) error {
 	return nil
 }
 
-func displaySummary(cfg *config.Config) {
+func displaySummary(cfg *config.Config, profileName string) {
 	fmt.Println("\n" + "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 	fmt.Println("✅ Configuration Complete!")
 	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
@@ -636,17 +753,30 @@ func displaySummary(cfg *config.Config) {
 
 	fmt.Printf("\n  Cloud Provider: %s\n", cfg.Cloud.Provider)
 	fmt.Printf("    Default Region: %s\n", cfg.Cloud.DefaultRegion)
-
-	fmt.Printf("\n  Terraform Backend:\n")
-	fmt.Printf("    Type: %s\n", cfg.Terraform.Backend.Type)
-	fmt.Printf("    S3 Bucket: %s\n", cfg.Terraform.Backend.S3Bucket)
-	fmt.Printf("    S3 Region: %s\n", cfg.Terraform.Backend.S3Region)
+	if cfg.Cloud.Provider == providerGCP {
+		fmt.Printf("    Project ID: %s\n", cfg.Cloud.ProjectID)
+		if cfg.Cloud.CredentialsFile != "" {
+			fmt.Printf("    Credentials File: %s\n", cfg.Cloud.CredentialsFile)
+		}
+	} else {
+		fmt.Printf("\n  Terraform Backend:\n")
+		fmt.Printf("    Type: %s\n", cfg.Terraform.Backend.Type)
+		fmt.Printf("    S3 Bucket: %s\n", cfg.Terraform.Backend.S3Bucket)
+		fmt.Printf("    S3 Region: %s\n", cfg.Terraform.Backend.S3Region)
+	}
 
 	home, _ := os.UserHomeDir()
 	fmt.Printf("\n📁 Configuration saved to: %s/.scai.yaml\n", home)
+	if profileName != "" {
+		fmt.Printf("    Profile: %s\n", profileName)
+	}
 
 	fmt.Println("\n🎉 Next Steps:")
-	fmt.Println("  1. Run 'scia deploy' to deploy your first application")
+	if profileName != "" {
+		fmt.Printf("  1. Run 'scia deploy --profile %s' to deploy with this profile\n", profileName)
+	} else {
+		fmt.Println("  1. Run 'scia deploy' to deploy your first application")
+	}
 	fmt.Println("  2. Use --verbose flag for detailed output")
 	fmt.Println("  3. Check the documentation for more examples")
 	fmt.Println()
@@ -3,7 +3,10 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/mattn/go-runewidth"
 	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
 
@@ -20,7 +23,8 @@ Example:
   scia list --region us-east-1
   scia list --strategy vm
   scia list --status succeeded
-  scia list --app hello-world`,
+  scia list --app hello-world
+  scia list --wide --status failed`,
 	RunE: runList,
 }
 
@@ -28,20 +32,20 @@ func init() {
 	rootCmd.AddCommand(listCmd)
 
 	// List-specific flags
-	listCmd.Flags().String("region", "", "Filter by AWS region")
-	listCmd.Flags().String("strategy", "", "Filter by deployment strategy (vm, kubernetes, serverless)")
-	listCmd.Flags().String("status", "", "Filter by deployment status (pending, running, succeeded, failed, destroyed)")
-	listCmd.Flags().String("app", "", "Filter by application name")
+	addDeploymentFilterFlags(listCmd)
+	listCmd.Flags().Bool("wide", false, "Show LLM, commit and duration columns, without truncating app names")
 }
 
-func runList(cmd *cobra.Command, args []string) error {
-	if globalStore == nil {
-		return fmt.Errorf("database not initialized")
-	}
-
-	ctx := context.Background()
+// addDeploymentFilterFlags registers the flags read by deploymentFilterFromFlags
+func addDeploymentFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().String("region", "", "Filter by AWS region")
+	cmd.Flags().String("strategy", "", "Filter by deployment strategy (vm, kubernetes, serverless)")
+	cmd.Flags().String("status", "", "Filter by deployment status (pending, running, succeeded, failed, destroyed, planned)")
+	cmd.Flags().String("app", "", "Filter by application name")
+}
 
-	// Build filter from flags
+// deploymentFilterFromFlags builds a deployment filter from the command flags
+func deploymentFilterFromFlags(cmd *cobra.Command) *store.DeploymentFilter {
 	filter := &store.DeploymentFilter{}
 
 	if region, _ := cmd.Flags().GetString("region"); region != "" {
@@ -57,8 +61,18 @@ func runList(cmd *cobra.Command, args []string) error {
 		filter.AppName = app
 	}
 
+	return filter
+}
+
+func runList(cmd *cobra.Command, args []string) error {
+	if globalStore == nil {
+		return fmt.Errorf("database not initialized")
+	}
+
+	ctx := context.Background()
+
 	// Query deployments
-	deployments, err := globalStore.List(ctx, filter)
+	deployments, err := globalStore.List(ctx, deploymentFilterFromFlags(cmd))
 	if err != nil {
 		return fmt.Errorf("failed to list deployments: %w", err)
 	}
@@ -72,38 +86,54 @@ func runList(cmd *cobra.Command, args []string) error {
 	pterm.DefaultHeader.WithFullWidth().Printf("Found %d deployment(s)", len(deployments))
 	pterm.Println()
 
+	wide, _ := cmd.Flags().GetBool("wide")
+
 	// Prepare table data
-	tableData := pterm.TableData{
-		{"ID", "APP NAME", "STRATEGY", "REGION", "STATUS", "CREATED"},
+	header := []string{"ID", "APP NAME", "STRATEGY", "REGION", "STATUS", "CREATED"}
+	if wide {
+		header = append(header, "LLM", "COMMIT", "DURATION")
 	}
+	tableData := pterm.TableData{header}
 
 	for _, dep := range deployments {
 		// Format creation time
 		createdTime := dep.CreatedAt.Format("2006-01-02 15:04")
 
-		// Truncate app name if too long
+		// Truncate app name if too long (full name in wide output)
 		appName := dep.AppName
-		if len(appName) > 20 {
+		if !wide && len(appName) > 20 {
 			appName = appName[:17] + "..."
 		}
 
 		// Add status indicator
 		statusIcon := getStatusIcon(dep.Status)
 
-		tableData = append(tableData, []string{
+		row := []string{
 			dep.ID,
 			appName,
 			dep.Strategy,
 			dep.Region,
 			fmt.Sprintf("%s %s", statusIcon, dep.Status),
 			createdTime,
-		})
+		}
+		if wide {
+			row = append(row, llmLabel(dep), shortSHA(dep.RepoCommitSHA), deploymentDuration(dep))
+		}
+		tableData = append(tableData, row)
 	}
 
 	// Render table
-	if err := pterm.DefaultTable.WithHasHeader().WithData(tableData).Render(); err != nil {
+	table, err := pterm.DefaultTable.WithHasHeader().WithData(tableData).Srender()
+	if err != nil {
 		return fmt.Errorf("failed to render table: %w", err)
 	}
+	pterm.Println(table)
+
+	// Lines wider than the terminal wrap and garble the table: suggest a pager that scrolls
+	if width, _, err := pterm.GetTerminalSize(); err == nil && tableWidth(table) > width {
+		pterm.Println()
+		pterm.Info.Println("The table is wider than the terminal: pipe it to 'less -S' to scroll horizontally")
+	}
 
 	pterm.Println()
 	pterm.Info.Println("Use 'scia show <deployment-id>' to see detailed information")
@@ -111,6 +141,54 @@ func runList(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// llmLabel returns the LLM provider and model that generated a deployment ("-" if unknown)
+func llmLabel(dep *store.Deployment) string {
+	switch {
+	case dep.LLMProvider == "":
+		return "-"
+	case dep.LLMModel == "":
+		return dep.LLMProvider
+	default:
+		return dep.LLMProvider + "/" + dep.LLMModel
+	}
+}
+
+// shortSHA returns the abbreviated commit SHA ("-" if unknown)
+func shortSHA(sha string) string {
+	if sha == "" {
+		return "-"
+	}
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+// deploymentDuration returns how long a deployment took, or has been running so far ("-" if unknown)
+func deploymentDuration(dep *store.Deployment) string {
+	var elapsed time.Duration
+	switch {
+	case dep.DeployedAt != nil:
+		elapsed = dep.DeployedAt.Sub(dep.CreatedAt)
+	case dep.Status == store.DeploymentStatusFailed:
+		elapsed = dep.UpdatedAt.Sub(dep.CreatedAt)
+	case dep.Status == store.DeploymentStatusPending, dep.Status == store.DeploymentStatusRunning:
+		elapsed = time.Since(dep.CreatedAt)
+	default:
+		return "-"
+	}
+	return elapsed.Round(time.Second).String()
+}
+
+// tableWidth returns the display width of the widest line of a rendered table
+func tableWidth(table string) int {
+	width := 0
+	for _, line := range strings.Split(pterm.RemoveColorFromString(table), "\n") {
+		width = max(width, runewidth.StringWidth(line))
+	}
+	return width
+}
+
 // getStatusIcon returns an emoji icon for the deployment status
 func getStatusIcon(status store.DeploymentStatus) string {
 	switch status {
@@ -124,6 +202,8 @@ func getStatusIcon(status store.DeploymentStatus) string {
 		return "❌"
 	case store.DeploymentStatusDestroyed:
 		return "🗑️"
+	case store.DeploymentStatusPlanned:
+		return "📝"
 	default:
 		return "❓"
 	}
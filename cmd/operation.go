@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/pterm/pterm"
+)
+
+// errCanceled is the cause of an operation stopped by a signal or by --timeout
+var errCanceled = errors.New("canceled")
+
+// operationContext returns the context of a deploy or destroy, canceled on the first SIGINT or
+// SIGTERM (a second one exits immediately, with the default signal handling restored) or once
+// timeout has elapsed (no limit when 0). Its cause wraps errCanceled. stop releases the signal
+// handler and the timer.
+func operationContext(timeout time.Duration) (ctx context.Context, stop func()) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case sig := <-signals:
+			signal.Stop(signals)
+			pterm.Warning.Printfln("%s received, stopping (press Ctrl-C again to exit immediately)...", sig)
+			cancel(fmt.Errorf("%w: %s received", errCanceled, sig))
+		case <-ctx.Done():
+		}
+	}()
+
+	cancelTimeout := context.CancelFunc(func() {})
+	if timeout > 0 {
+		ctx, cancelTimeout = context.WithTimeoutCause(ctx, timeout,
+			fmt.Errorf("%w: timed out after %s (--timeout)", errCanceled, timeout))
+	}
+
+	return ctx, func() {
+		cancelTimeout()
+		signal.Stop(signals)
+		cancel(nil)
+	}
+}
+
+// canceledError wraps err with the cause of ctx when the operation was canceled
+func canceledError(ctx context.Context, err error) error {
+	if err == nil || ctx.Err() == nil || errors.Is(err, errCanceled) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", context.Cause(ctx), err)
+}
@@ -7,6 +7,8 @@ import (
 
 	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
+
+	"github.com/Smana/scai/internal/store"
 )
 
 var outputsCmd = &cobra.Command{
@@ -48,8 +50,12 @@ func runOutputs(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	// Check if JSON output requested
 	jsonOutput, _ := cmd.Flags().GetBool("json")
+	return printOutputs(deployment, jsonOutput)
+}
+
+// printOutputs displays the outputs of a deployment, as JSON when jsonOutput is set
+func printOutputs(deployment *store.Deployment, jsonOutput bool) error {
 	if jsonOutput {
 		// Output as JSON
 		data, err := json.MarshalIndent(deployment.Outputs, "", "  ")
@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/Smana/scai/internal/cloud"
+	"github.com/Smana/scai/internal/store"
+)
+
+var reconcileCmd = &cobra.Command{
+	Use:   "reconcile",
+	Short: "Find AWS resources missing from the local deployment database",
+	Long: `Scan AWS for resources tagged with a SCAI deployment ID (scia:deployment-id)
+that are not tracked in the local database, for example after deployments.db was
+lost or when switching machines.
+
+Orphaned deployments can be re-imported as deployment records or listed so that
+the resources can be destroyed manually.
+
+Example:
+  scia reconcile
+  scia reconcile --region eu-west-3
+  scia reconcile --import --yes`,
+	RunE: runReconcile,
+}
+
+func init() {
+	rootCmd.AddCommand(reconcileCmd)
+
+	// Reconcile-specific flags
+	reconcileCmd.Flags().String("region", "", "Only scan this AWS region (default: all regions)")
+	reconcileCmd.Flags().Bool("import", false, "Re-import orphaned deployments as deployment records")
+	reconcileCmd.Flags().BoolP("yes", "y", false, "Auto-approve import without confirmation prompt")
+}
+
+// orphanDeployment groups the untracked resources of a single deployment ID
+type orphanDeployment struct {
+	ID        string
+	AppName   string
+	Region    string
+	Resources []cloud.TaggedResource
+}
+
+func runReconcile(cmd *cobra.Command, args []string) error {
+	if globalStore == nil {
+		return fmt.Errorf("database not initialized")
+	}
+
+	ctx := context.Background()
+	verbose := viper.GetBool("verbose")
+
+	awsClient, err := cloud.NewAWSClient(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect to AWS: %w", err)
+	}
+
+	// Determine regions to scan
+	var regions []string
+	if region, _ := cmd.Flags().GetString("region"); region != "" {
+		regions = []string{region}
+	} else {
+		regions, err = awsClient.GetAllRegions(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list regions: %w", err)
+		}
+	}
+
+	// Index known deployments
+	deployments, err := globalStore.List(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to list deployments: %w", err)
+	}
+	known := make(map[string]bool, len(deployments))
+	for _, dep := range deployments {
+		known[dep.ID] = true
+	}
+
+	// Scan regions for tagged resources
+	spinner, _ := pterm.DefaultSpinner.Start(fmt.Sprintf("Scanning %d region(s) for SCAI resources...", len(regions)))
+	orphans := map[string]*orphanDeployment{}
+	for _, region := range regions {
+		resources, err := awsClient.FindTaggedResources(ctx, region)
+		if err != nil {
+			// Regions that are not enabled for the account reject the call
+			if verbose {
+				pterm.Warning.Printf("Skipping %s: %v\n", region, err)
+			}
+			continue
+		}
+
+		for _, resource := range resources {
+			if resource.DeploymentID == "" || known[resource.DeploymentID] {
+				continue
+			}
+
+			orphan, ok := orphans[resource.DeploymentID]
+			if !ok {
+				orphan = &orphanDeployment{
+					ID:      resource.DeploymentID,
+					AppName: resource.AppName,
+					Region:  region,
+				}
+				orphans[resource.DeploymentID] = orphan
+			}
+			orphan.Resources = append(orphan.Resources, resource)
+		}
+	}
+	if spinner != nil {
+		_ = spinner.Stop()
+	}
+
+	if len(orphans) == 0 {
+		pterm.Success.Println("No orphaned resources found - all tagged deployments are tracked.")
+		return nil
+	}
+
+	// Sort for stable output
+	ids := make([]string, 0, len(orphans))
+	for id := range orphans {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	pterm.DefaultHeader.WithFullWidth().Printf("Found %d untracked deployment(s)", len(orphans))
+	pterm.Println()
+
+	for _, id := range ids {
+		orphan := orphans[id]
+		pterm.DefaultSection.Printf("%s (%s, %s)", orphan.ID, orphan.AppName, orphan.Region)
+		for _, resource := range orphan.Resources {
+			pterm.Printf("   • %s\n", resource.ARN)
+		}
+		pterm.Println()
+	}
+
+	// Decide whether to import
+	doImport, _ := cmd.Flags().GetBool("import")
+	autoApprove, _ := cmd.Flags().GetBool("yes")
+	if !doImport {
+		pterm.Info.Println("Run 'scia reconcile --import' to re-import these deployments,")
+		pterm.Info.Println("or delete the resources listed above manually.")
+		return nil
+	}
+
+	if !autoApprove {
+		confirmed, err := pterm.DefaultInteractiveConfirm.
+			WithDefaultText(fmt.Sprintf("Re-import %d deployment(s) into the local database?", len(orphans))).
+			WithDefaultValue(false).
+			Show()
+		if err != nil {
+			return fmt.Errorf("confirmation prompt failed: %w", err)
+		}
+		if !confirmed {
+			pterm.Info.Println("Import canceled")
+			return nil
+		}
+	}
+
+	imported := 0
+	for _, id := range ids {
+		deployment := buildImportedDeployment(orphans[id])
+		if err := globalStore.Create(ctx, deployment); err != nil {
+			pterm.Warning.Printf("Failed to import %s: %v\n", id, err)
+			continue
+		}
+		imported++
+	}
+
+	pterm.Success.Printf("Imported %d deployment(s)\n", imported)
+	pterm.Info.Println("Imported records have no local Terraform directory - use the listed ARNs to clean up resources.")
+
+	return nil
+}
+
+// buildImportedDeployment creates a deployment record from orphaned resources
+func buildImportedDeployment(orphan *orphanDeployment) *store.Deployment {
+	appName := orphan.AppName
+	if appName == "" {
+		appName = "unknown"
+	}
+
+	now := time.Now()
+	outputs := make(map[string]string, len(orphan.Resources))
+	for i, resource := range orphan.Resources {
+		outputs[fmt.Sprintf("resource_%d", i+1)] = resource.ARN
+	}
+
+	return &store.Deployment{
+		ID:                orphan.ID,
+		AppName:           appName,
+		Strategy:          cloud.InferStrategy(orphan.Resources),
+		Region:            orphan.Region,
+		Status:            store.DeploymentStatusSucceeded,
+		TerraformStateKey: fmt.Sprintf("deployments/%s/terraform.tfstate", orphan.ID),
+		Outputs:           outputs,
+		Warnings:          []string{"Re-imported by 'scia reconcile' from AWS resource tags"},
+		Optimizations:     []string{},
+		CreatedAt:         now,
+		UpdatedAt:         now,
+		DeployedAt:        &now,
+	}
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/Smana/scai/internal/deployer"
+	"github.com/Smana/scai/internal/store"
+)
+
+var refreshOutputsCmd = &cobra.Command{
+	Use:   "refresh-outputs <deployment-id>",
+	Short: "Re-read the Terraform outputs of a deployment",
+	Long: `Run terraform output in the Terraform directory of a deployment, save the outputs in the
+deployment record and display them: after the terminal output of a deploy was lost, or once
+the infrastructure was fixed or replaced outside of scai. The URL of a vm deployment is
+looked up again from its Auto Scaling Group.
+
+Example:
+  scia refresh-outputs abc123de-f456-7890-abcd-ef1234567890
+  scia refresh-outputs abc123de --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRefreshOutputs,
+}
+
+func init() {
+	rootCmd.AddCommand(refreshOutputsCmd)
+
+	refreshOutputsCmd.Flags().Bool("json", false, "Output as JSON")
+}
+
+func runRefreshOutputs(cmd *cobra.Command, args []string) error {
+	if globalStore == nil {
+		return fmt.Errorf("database not initialized")
+	}
+
+	ctx := context.Background()
+	deploymentID := args[0]
+	verbose := viper.GetBool("verbose")
+
+	deployment, err := globalStore.Get(ctx, deploymentID)
+	if err != nil {
+		return fmt.Errorf("failed to get deployment: %w", err)
+	}
+	switch deployment.Status {
+	case store.DeploymentStatusDestroyed:
+		return fmt.Errorf("deployment %s is destroyed: it has no outputs to refresh", deploymentID)
+	case store.DeploymentStatusPlanned:
+		return fmt.Errorf("deployment %s was exported with --plan-out and never applied by scai: run terraform output in %s", deploymentID, deployment.PlanOutDir)
+	}
+	if deployment.TerraformDir == "" {
+		return fmt.Errorf("terraform directory not found in deployment record")
+	}
+	if _, err := os.Stat(deployment.TerraformDir); err != nil {
+		return fmt.Errorf("terraform directory %s of deployment %s no longer exists: regenerate it with 'scia generate --out %s' "+
+			"from the same prompt and repository, with a backend block pointing to its S3 state (key %s); a local state was removed with the directory",
+			deployment.TerraformDir, deploymentID, deployment.TerraformDir, deployment.TerraformStateKey)
+	}
+
+	// Outputs written by a deploy or destroy still running would be overwritten
+	if err := globalStore.Lock(ctx, deploymentID, store.LockOwner()); err != nil {
+		return err
+	}
+	defer func() { _ = globalStore.Unlock(ctx, deploymentID, store.LockOwner()) }()
+
+	executor, err := newDeploymentExecutor(deployment, verbose)
+	if err != nil {
+		return err
+	}
+	outputs, err := executor.Outputs()
+	if err != nil {
+		return fmt.Errorf("failed to get terraform outputs: %w", err)
+	}
+	if deployment.Strategy == "vm" {
+		healthCheckPath := ""
+		if deployment.Analysis != nil {
+			healthCheckPath = deployment.Analysis.HealthCheckPath
+		}
+		deployer.AddApplicationURL(ctx, outputs, deployment.Region, healthCheckPath, verbose)
+	}
+
+	deployment.Outputs = outputs
+	if err := globalStore.Update(ctx, deployment); err != nil {
+		return fmt.Errorf("failed to update deployment record: %w", err)
+	}
+	if err := globalStore.AddEvent(ctx, deploymentID, deployment.Status, "Outputs refreshed"); err != nil && verbose {
+		pterm.Warning.Printf("Failed to record deployment event: %v\n", err)
+	}
+
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+	if !jsonOutput {
+		pterm.Success.Printf("Refreshed %d output(s) of deployment %s\n", len(outputs), deploymentID)
+	}
+	return printOutputs(deployment, jsonOutput)
+}
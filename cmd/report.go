@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+
+	"github.com/Smana/scai/internal/console"
+	"github.com/Smana/scai/internal/cost"
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Report the estimated cost of the deployments over a period",
+	Long: `Estimate what the deployments cost over a period, grouped by app, strategy and region.
+
+Each deployment is billed the monthly cost estimated in its deployment plan (static on-demand
+prices) prorated to the hours it was active in the period: from its creation until it was destroyed.
+Usage-based charges (Lambda invocations, API Gateway requests, data transfer) are not included.
+
+Example:
+  scia report                  # last 30 days
+  scia report --since 7d
+  scia report --since 2025-01-01 --json`,
+	Args: cobra.NoArgs,
+	RunE: runReport,
+}
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+
+	// Report-specific flags
+	reportCmd.Flags().String("since", "30d", "Start of the period: a duration back from now (30d, 2w, 12h) or a date (2025-01-01)")
+	reportCmd.Flags().Bool("json", false, "Output as JSON")
+}
+
+func runReport(cmd *cobra.Command, args []string) error {
+	if globalStore == nil {
+		return fmt.Errorf("database not initialized")
+	}
+
+	ctx := context.Background()
+	now := time.Now()
+
+	sinceFlag, _ := cmd.Flags().GetString("since")
+	since, err := parseSince(sinceFlag, now)
+	if err != nil {
+		return err
+	}
+
+	deployments, err := globalStore.List(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to list deployments: %w", err)
+	}
+	report := cost.BuildReport(deployments, since, now)
+
+	if jsonOutput, _ := cmd.Flags().GetBool("json"); jsonOutput {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Fprintln(console.Stdout, string(data))
+		return nil
+	}
+
+	pterm.Println()
+	pterm.Info.Printf("Estimated cost from %s to %s\n", since.Format("2006-01-02 15:04"), now.Format("2006-01-02 15:04"))
+	if len(report.Lines) == 0 {
+		pterm.Info.Println("No deployments active during the period.")
+		return nil
+	}
+
+	tableData := pterm.TableData{{"App Name", "Strategy", "Region", "Deployments", "Active Hours", "Cost"}}
+	for _, line := range report.Lines {
+		costCell := fmt.Sprintf("$%.2f", line.CostUSD)
+		if line.UsageBased {
+			costCell = "usage-based"
+		}
+		tableData = append(tableData, []string{
+			line.AppName,
+			line.Strategy,
+			line.Region,
+			strconv.Itoa(line.Deployments),
+			fmt.Sprintf("%.0f", line.ActiveHours),
+			costCell,
+		})
+	}
+	tableData = append(tableData, []string{"Total", "", "", "", "", fmt.Sprintf("$%.2f", report.TotalUSD)})
+
+	if err := pterm.DefaultTable.WithHasHeader().WithData(tableData).Render(); err != nil {
+		return fmt.Errorf("failed to render table: %w", err)
+	}
+	pterm.Println()
+
+	if len(report.Unpriced) > 0 {
+		pterm.Warning.Printf("No known price for %s: not included in the total\n", strings.Join(report.Unpriced, ", "))
+	}
+	return nil
+}
+
+// parseSince returns the start of a report period: a duration back from now, in days (30d),
+// weeks (2w) or any Go duration (12h), or a date (2025-01-01)
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if date, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return date, nil
+	}
+
+	var duration time.Duration
+	switch {
+	case strings.HasSuffix(value, "d"), strings.HasSuffix(value, "w"):
+		count, err := strconv.Atoi(value[:len(value)-1])
+		if err != nil || count <= 0 {
+			return time.Time{}, fmt.Errorf("invalid --since %q: expected e.g. 30d, 2w, 12h or 2025-01-01", value)
+		}
+		duration = time.Duration(count) * 24 * time.Hour
+		if strings.HasSuffix(value, "w") {
+			duration *= 7
+		}
+	default:
+		var err error
+		if duration, err = time.ParseDuration(value); err != nil || duration <= 0 {
+			return time.Time{}, fmt.Errorf("invalid --since %q: expected e.g. 30d, 2w, 12h or 2025-01-01", value)
+		}
+	}
+	return now.Add(-duration), nil
+}
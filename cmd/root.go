@@ -9,14 +9,29 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
 
+	"github.com/Smana/scai/internal/analyzer"
+	"github.com/Smana/scai/internal/cloud"
+	"github.com/Smana/scai/internal/config"
+	"github.com/Smana/scai/internal/console"
+	"github.com/Smana/scai/internal/llm"
+	"github.com/Smana/scai/internal/network"
 	"github.com/Smana/scai/internal/store"
+	"github.com/Smana/scai/internal/terraform"
 )
 
 var (
-	cfgFile string
-	workDir string
-	verbose bool
+	cfgFile   string
+	profile   string
+	workDir   string
+	verbose   bool
+	noColor   bool
+	logFormat string
+	debugLLM  string
+
+	// debugLLMFile is the file the LLM exchanges are logged to with --debug-llm <path>
+	debugLLMFile *os.File
 
 	// Version information set by main package
 	version string
@@ -59,16 +74,71 @@ func Execute() {
 }
 
 func init() {
-	cobra.OnInitialize(initConfig, initDatabase)
+	cobra.OnInitialize(initOutput, initDebugLLM, initConfig, initDatabase)
+	cobra.OnFinalize(closeDatabase, closeDebugLLM)
 
 	// Global flags
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default: $HOME/.scai.yaml)")
+	rootCmd.PersistentFlags().StringVar(&profile, "profile", "", "config profile to use (also set by SCAI_PROFILE)")
 	rootCmd.PersistentFlags().StringVar(&workDir, "work-dir", "/tmp/scai", "working directory")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose output")
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "disable colors, styling and emoji in output (also set by NO_COLOR)")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", console.LogFormatText, "output format: text or json (JSON lines progress events for CI)")
+	rootCmd.PersistentFlags().StringVar(&debugLLM, "debug-llm", "", "log every LLM prompt and raw response, secrets redacted, to stderr or to the given file")
+	rootCmd.PersistentFlags().Lookup("debug-llm").NoOptDefVal = "stderr"
+	rootCmd.PersistentFlags().String("aws-profile", "", "AWS named profile of the shared config files (overrides config and AWS_PROFILE)")
+	rootCmd.PersistentFlags().String("aws-role-arn", "", "IAM role assumed by SCAI, Terraform and Pulumi for the whole command (overrides config)")
 
 	// Bind flags to Viper
-	_ = viper.BindPFlag("workdir", rootCmd.PersistentFlags().Lookup("work-dir"))
+	_ = viper.BindPFlag("workdir.path", rootCmd.PersistentFlags().Lookup("work-dir"))
 	_ = viper.BindPFlag("verbose", rootCmd.PersistentFlags().Lookup("verbose"))
+	_ = viper.BindPFlag("profile", rootCmd.PersistentFlags().Lookup("profile"))
+	_ = viper.BindPFlag("cloud.aws_profile", rootCmd.PersistentFlags().Lookup("aws-profile"))
+	_ = viper.BindPFlag("cloud.aws_role_arn", rootCmd.PersistentFlags().Lookup("aws-role-arn"))
+}
+
+// initOutput switches to plain output with --no-color or a non-empty NO_COLOR (https://no-color.org)
+// and to JSON lines events with --log-format json
+func initOutput() {
+	if noColor || os.Getenv("NO_COLOR") != "" {
+		console.SetPlain(true)
+	}
+
+	switch logFormat {
+	case console.LogFormatText:
+	case console.LogFormatJSON:
+		console.SetJSON(true)
+	default:
+		cobra.CheckErr(fmt.Errorf("invalid --log-format %q: expected text or json", logFormat))
+	}
+}
+
+// initDebugLLM logs the LLM prompts and responses with --debug-llm: to stderr without a value
+// (or with "-"), to a file otherwise
+func initDebugLLM() {
+	switch debugLLM {
+	case "":
+		return
+	case "stderr", "-":
+		llm.SetDebugOutput(os.Stderr)
+	default:
+		file, err := os.OpenFile(debugLLM, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600) // #nosec G304 - path given by the user
+		if err != nil {
+			cobra.CheckErr(fmt.Errorf("failed to open --debug-llm file: %w", err))
+		}
+		debugLLMFile = file
+		llm.SetDebugOutput(file)
+	}
+}
+
+// closeDebugLLM closes the --debug-llm file
+func closeDebugLLM() {
+	llm.SetDebugOutput(nil)
+	if debugLLMFile == nil {
+		return
+	}
+	_ = debugLLMFile.Close()
+	debugLLMFile = nil
 }
 
 // initDatabase initializes the SQLite database for deployment tracking
@@ -88,7 +158,7 @@ func initDatabase() {
 	if err := os.MkdirAll(sciaDir, 0o755); err != nil {
 		// Fail silently - database is optional
 		if verbose {
-			fmt.Printf("Warning: failed to create .scai directory: %v\n", err)
+			fmt.Fprintf(console.Stdout, "Warning: failed to create .scai directory: %v\n", err)
 		}
 		return
 	}
@@ -98,7 +168,7 @@ func initDatabase() {
 	if err != nil {
 		// Fail silently - database is optional
 		if verbose {
-			fmt.Printf("Warning: failed to create database: %v\n", err)
+			fmt.Fprintf(console.Stdout, "Warning: failed to create database: %v\n", err)
 		}
 		return
 	}
@@ -108,7 +178,7 @@ func initDatabase() {
 	if err := sqliteStore.Initialize(ctx); err != nil {
 		// Fail silently - database is optional
 		if verbose {
-			fmt.Printf("Warning: failed to initialize database: %v\n", err)
+			fmt.Fprintf(console.Stdout, "Warning: failed to initialize database: %v\n", err)
 		}
 		_ = sqliteStore.Close()
 		return
@@ -118,10 +188,22 @@ func initDatabase() {
 	globalStore = sqliteStore
 
 	if verbose {
-		fmt.Printf("✓ Database initialized: %s\n", dbPath)
+		fmt.Fprintf(console.Stdout, "✓ Database initialized: %s\n", dbPath)
 	}
 }
 
+// closeDatabase closes the deployment database once the command completes (successfully or not)
+func closeDatabase() {
+	if globalStore == nil {
+		return
+	}
+
+	if err := globalStore.Close(); err != nil && verbose {
+		fmt.Fprintf(console.Stdout, "Warning: failed to close database: %v\n", err)
+	}
+	globalStore = nil
+}
+
 func initConfig() {
 	if cfgFile != "" {
 		// Use config file from flag
@@ -146,7 +228,17 @@ func initConfig() {
 	// Read config file if exists
 	if err := viper.ReadInConfig(); err == nil {
 		if verbose {
-			fmt.Println("Using config file:", viper.ConfigFileUsed())
+			fmt.Fprintln(console.Stdout, "Using config file:", viper.ConfigFileUsed())
+		}
+
+		// Upgrade YAML config files written by older versions
+		if ext := filepath.Ext(viper.ConfigFileUsed()); ext == ".yaml" || ext == ".yml" {
+			backupPath, err := config.MigrateConfig(viper.ConfigFileUsed())
+			cobra.CheckErr(err)
+			if backupPath != "" {
+				fmt.Fprintf(console.Stdout, "Migrated config file to version %d (backup: %s)\n", config.CurrentVersion, backupPath)
+				cobra.CheckErr(viper.ReadInConfig())
+			}
 		}
 	}
 
@@ -168,4 +260,62 @@ func initConfig() {
 	viper.SetDefault("terraform.bin", "tofu")
 	viper.SetDefault("terraform.backend.type", "s3")
 	viper.SetDefault("terraform.backend.s3_key", "terraform.tfstate")
+	viper.SetDefault("terraform.eks.version", terraform.DefaultEKSVersion)
+	viper.SetDefault("terraform.aws_provider_version", terraform.DefaultAWSProviderVersion)
+
+	// Infrastructure as code engine: terraform, or pulumi (vm strategy only)
+	viper.SetDefault("iac.engine", "terraform")
+	viper.SetDefault("iac.pulumi.bin", "pulumi")
+
+	// Analyzer configuration
+	viper.SetDefault("analyzer.max_depth", analyzer.DefaultMaxDepth)
+	viper.SetDefault("analyzer.ignore_dirs", analyzer.DefaultIgnoreDirs)
+	viper.SetDefault("analyzer.zip_max_size_mb", analyzer.DefaultZipMaxSizeMB)
+	viper.SetDefault("analyzer.zip_max_file_size_mb", analyzer.DefaultZipMaxFileSizeMB)
+	viper.SetDefault("analyzer.zip_max_files", analyzer.DefaultZipMaxFiles)
+
+	// Apply the selected profile (scai init creates missing profiles); after the defaults,
+	// which complete the merged settings it is validated against
+	if err := applyProfile(viper.GetString("profile")); err != nil && initCmd.CalledAs() == "" {
+		cobra.CheckErr(err)
+	}
+
+	// Outbound connections through network.proxy instead of HTTP_PROXY/HTTPS_PROXY
+	cobra.CheckErr(network.SetProxy(viper.GetString("network.proxy")))
+
+	// AWS identity of the SDK clients, Terraform and Pulumi instead of the default credential chain
+	cobra.CheckErr(cloud.SetIdentity(viper.GetString("cloud.aws_profile"), viper.GetString("cloud.aws_role_arn")))
+}
+
+// applyProfile merges the settings of the named profile over the top-level settings
+func applyProfile(name string) error {
+	if name == "" {
+		return nil
+	}
+
+	settings := viper.GetStringMap("profiles." + name)
+	if len(settings) == 0 {
+		return fmt.Errorf("profile %q not found in config file (create it with: scia init --profile %s)", name, name)
+	}
+	if err := viper.MergeConfigMap(settings); err != nil {
+		return fmt.Errorf("failed to apply profile %q: %w", name, err)
+	}
+
+	// Profiles override single settings: the merged result must still be a valid configuration
+	merged, err := yaml.Marshal(viper.AllSettings())
+	if err != nil {
+		return fmt.Errorf("failed to apply profile %q: %w", name, err)
+	}
+	var cfg config.Config
+	if err := yaml.Unmarshal(merged, &cfg); err != nil {
+		return fmt.Errorf("failed to apply profile %q: %w", name, err)
+	}
+	if err := config.ValidateConfig(&cfg); err != nil {
+		return fmt.Errorf("profile %q validation failed: %w", name, err)
+	}
+
+	if verbose {
+		fmt.Fprintln(console.Stdout, "Using profile:", name)
+	}
+	return nil
 }
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+// profilesConfig is a complete configuration with a valid and an invalid profile
+const profilesConfig = `
+llm:
+  provider: ollama
+  ollama:
+    url: http://localhost:11434
+    model: qwen2.5-coder:7b
+cloud:
+  provider: aws
+  default_region: eu-west-3
+terraform:
+  bin: tofu
+  backend:
+    type: s3
+    s3_bucket: scai-state
+    s3_region: eu-west-3
+    s3_key: terraform.tfstate
+profiles:
+  staging:
+    cloud:
+      default_region: us-east-1
+    terraform:
+      backend:
+        s3_bucket: scai-staging-state
+  broken:
+    cloud:
+      default_region: mars-1
+`
+
+// readProfilesConfig loads profilesConfig into a fresh viper instance
+func readProfilesConfig(t *testing.T) {
+	t.Helper()
+
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	viper.SetConfigType("yaml")
+	if err := viper.ReadConfig(strings.NewReader(profilesConfig)); err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+}
+
+func TestApplyProfileMergesSettings(t *testing.T) {
+	readProfilesConfig(t)
+
+	if err := applyProfile("staging"); err != nil {
+		t.Fatalf("applyProfile() error = %v", err)
+	}
+
+	if got := viper.GetString("cloud.default_region"); got != "us-east-1" {
+		t.Errorf("expected the profile region us-east-1, got %s", got)
+	}
+	if got := viper.GetString("terraform.backend.s3_bucket"); got != "scai-staging-state" {
+		t.Errorf("expected the profile bucket scai-staging-state, got %s", got)
+	}
+	// Settings the profile does not override are kept
+	if got := viper.GetString("terraform.backend.s3_region"); got != "eu-west-3" {
+		t.Errorf("expected the top-level bucket region eu-west-3, got %s", got)
+	}
+	if got := viper.GetString("llm.provider"); got != "ollama" {
+		t.Errorf("expected the top-level LLM provider ollama, got %s", got)
+	}
+}
+
+func TestApplyProfileErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile string
+		wantErr string
+	}{
+		{"not found", "production", `profile "production" not found in config file (create it with: scia init --profile production)`},
+		{"invalid merged settings", "broken", `profile "broken" validation failed`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			readProfilesConfig(t)
+
+			err := applyProfile(tt.profile)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("applyProfile(%q) error = %v, want %q", tt.profile, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestApplyProfileNone(t *testing.T) {
+	readProfilesConfig(t)
+
+	if err := applyProfile(""); err != nil {
+		t.Fatalf("applyProfile() error = %v", err)
+	}
+	if got := viper.GetString("cloud.default_region"); got != "eu-west-3" {
+		t.Errorf("expected the top-level region eu-west-3, got %s", got)
+	}
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/Smana/scai/internal/console"
+	"github.com/Smana/scai/internal/llm"
+	"github.com/Smana/scai/internal/rules"
+)
+
+var rulesCmd = &cobra.Command{
+	Use:   "rules",
+	Short: "Manage the deployment decision rules",
+}
+
+var rulesValidateCmd = &cobra.Command{
+	Use:   "validate <file>",
+	Short: "Check a deployment rules YAML file",
+	Long: `Load a deployment rules file and report the misconfigurations that would otherwise be
+silently ignored: unknown recommendations (vm, kubernetes or serverless expected), duplicate
+names, rules with the same priority recommending different strategies, and conditions that can
+never match (e.g. min_dependencies above max_dependencies, or a rule always preceded by a
+higher-priority one matching the same apps).
+
+Errors make the command fail; warnings are only reported.
+
+Example:
+  scia rules validate configs/deployment_rules.yaml`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRulesValidate,
+}
+
+func init() {
+	rootCmd.AddCommand(rulesCmd)
+	rulesCmd.AddCommand(rulesValidateCmd)
+}
+
+func runRulesValidate(cmd *cobra.Command, args []string) error {
+	path := args[0]
+
+	deploymentRules, err := rules.LoadRules(path)
+	if err != nil {
+		return err
+	}
+
+	errorCount := 0
+	for _, issue := range rules.Lint(deploymentRules) {
+		switch issue.Severity {
+		case rules.SeverityError:
+			errorCount++
+			pterm.Error.Println(issue.Message + ruleSuffix(issue.Rule))
+		default:
+			pterm.Warning.Println(issue.Message + ruleSuffix(issue.Rule))
+		}
+	}
+
+	if errorCount > 0 {
+		cmd.SilenceUsage = true // Invalid rules are not a usage error
+		return fmt.Errorf("%s: %d error(s) in the rules", path, errorCount)
+	}
+	pterm.Success.Printf("%s: %d rule(s) are valid\n", path, len(deploymentRules.Rules))
+	return nil
+}
+
+// ruleSuffix names the rule an issue is about, if any
+func ruleSuffix(rule string) string {
+	if rule == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (rule %s)", rule)
+}
+
+// addRulesFlags adds the custom deployment rules flags to cmd
+func addRulesFlags(cmd *cobra.Command) {
+	cmd.Flags().String("rules", "", "Deployment rules YAML replacing the built-in rules (default: rules.path)")
+	cmd.Flags().Bool("rules-merge", false, "Add the custom rules to the built-in ones (same-name rules are replaced) instead of replacing them all")
+}
+
+// applyCustomRules makes the LLM client evaluate the --rules (or rules.path) file instead
+// of the built-in rules; an invalid file is an error rather than a silent fallback
+func applyCustomRules(cmd *cobra.Command, llmClient *llm.Client, verbose bool) error {
+	path, _ := cmd.Flags().GetString("rules")
+	if path == "" {
+		path = viper.GetString("rules.path")
+	}
+	if path == "" {
+		return nil
+	}
+	merge, _ := cmd.Flags().GetBool("rules-merge")
+
+	deploymentRules, err := rules.LoadCustomRules(path, rules.DefaultRulesPath, merge)
+	if err != nil {
+		return err
+	}
+	if llmClient != nil {
+		llmClient.SetRules(deploymentRules)
+	}
+
+	if verbose {
+		fmt.Fprintf(console.Stdout, "Using %d deployment rule(s) from %s\n", len(deploymentRules.Rules), path)
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 
 	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
@@ -13,7 +14,7 @@ var showCmd = &cobra.Command{
 	Use:   "show <deployment-id>",
 	Short: "Show detailed deployment information",
 	Long: `Display detailed information about a specific deployment, including configuration,
-outputs, warnings, and optimizations.
+outputs, warnings, optimizations, and a timeline of its status transitions.
 
 Example:
   scia show abc123de-f456-7890-abcd-ef1234567890
@@ -100,6 +101,9 @@ func runShow(cmd *cobra.Command, args []string) error {
 	if deployment.TerraformDir != "" {
 		pterm.Printf("   Directory:    %s\n", deployment.TerraformDir)
 	}
+	if deployment.PlanOutDir != "" {
+		pterm.Printf("   Exported to:  %s\n", deployment.PlanOutDir)
+	}
 	pterm.Println()
 
 	// Configuration
@@ -111,9 +115,29 @@ func runShow(cmd *cobra.Command, args []string) error {
 		if deployment.Config.InstanceType != "" {
 			pterm.Printf("   Instance:     %s\n", deployment.Config.InstanceType)
 		}
+		if deployment.Config.AMI != "" {
+			pterm.Printf("   AMI:          %s\n", deployment.Config.AMI)
+		}
+		if deployment.Config.VPCID != "" {
+			pterm.Printf("   VPC:          %s (existing)\n", deployment.Config.VPCID)
+		}
+		if deployment.Config.AWSProviderVersion != "" {
+			pterm.Printf("   AWS Provider: %s\n", deployment.Config.AWSProviderVersion)
+		}
 		if deployment.Config.StartCommand != "" {
 			pterm.Printf("   Start Cmd:    %s\n", deployment.Config.StartCommand)
 		}
+		if deployment.Config.BuildCommand != "" {
+			pterm.Printf("   Build Cmd:    %s\n", deployment.Config.BuildCommand)
+		}
+		tagKeys := make([]string, 0, len(deployment.Config.Tags))
+		for key := range deployment.Config.Tags {
+			tagKeys = append(tagKeys, key)
+		}
+		sort.Strings(tagKeys) // Deterministic output
+		for _, key := range tagKeys {
+			pterm.Printf("   Tag:          %s=%s\n", key, deployment.Config.Tags[key])
+		}
 		pterm.Println()
 	}
 
@@ -163,5 +187,22 @@ func runShow(cmd *cobra.Command, args []string) error {
 	}
 	pterm.Println()
 
+	// Timeline (audit log of status transitions and actions)
+	events, err := globalStore.ListEvents(ctx, deployment.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get deployment events: %w", err)
+	}
+	if len(events) > 0 {
+		pterm.DefaultSection.Println("📜 Timeline")
+		for _, event := range events {
+			line := fmt.Sprintf("   %s  %s %s", event.CreatedAt.Format("2006-01-02 15:04:05"), getStatusIcon(event.Status), event.Status)
+			if event.Message != "" {
+				line += ": " + event.Message
+			}
+			pterm.Println(line)
+		}
+		pterm.Println()
+	}
+
 	return nil
 }
@@ -3,9 +3,16 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
+
+	"github.com/Smana/scai/internal/store"
 )
 
 var statusCmd = &cobra.Command{
@@ -20,14 +27,22 @@ Status values:
   - failed: Deployment failed
   - destroyed: Deployment has been destroyed
 
+With --watch, the status is refreshed in place until the deployment succeeds, fails
+or is destroyed (exiting with an error if it failed).
+
 Example:
-  scia status abc123de-f456-7890-abcd-ef1234567890`,
+  scia status abc123de-f456-7890-abcd-ef1234567890
+  scia status abc123de-f456-7890-abcd-ef1234567890 --watch`,
 	Args: cobra.ExactArgs(1),
 	RunE: runStatus,
 }
 
 func init() {
 	rootCmd.AddCommand(statusCmd)
+
+	// Status-specific flags
+	statusCmd.Flags().BoolP("watch", "w", false, "Refresh the status until the deployment succeeds, fails or is destroyed")
+	statusCmd.Flags().Duration("interval", 2*time.Second, "Refresh interval with --watch")
 }
 
 func runStatus(cmd *cobra.Command, args []string) error {
@@ -37,6 +52,7 @@ func runStatus(cmd *cobra.Command, args []string) error {
 
 	ctx := context.Background()
 	deploymentID := args[0]
+	watch, _ := cmd.Flags().GetBool("watch")
 
 	// Get deployment
 	deployment, err := globalStore.Get(ctx, deploymentID)
@@ -44,35 +60,109 @@ func runStatus(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to get deployment: %w", err)
 	}
 
+	if watch {
+		interval, _ := cmd.Flags().GetDuration("interval")
+		cmd.SilenceUsage = true // A failed deployment is not a usage error
+		return watchStatus(deployment, interval)
+	}
+
 	// Display status
 	pterm.Println()
-	pterm.DefaultHeader.WithFullWidth().Printf("Status: %s", deployment.AppName)
+	pterm.Print(formatStatus(deployment))
 	pterm.Println()
 
-	pterm.Printf("Deployment: %s\n", deployment.AppName)
-	pterm.Printf("ID:         %s\n", deployment.ID)
-	pterm.Printf("Status:     %s %s\n", getStatusIcon(deployment.Status), deployment.Status)
-	pterm.Printf("Strategy:   %s\n", deployment.Strategy)
-	pterm.Printf("Region:     %s\n", deployment.Region)
-	pterm.Println()
+	return nil
+}
+
+// watchStatus redraws the status of a deployment every interval until it reaches a terminal
+// status or Ctrl-C is pressed, and fails if the deployment failed
+func watchStatus(deployment *store.Deployment, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("invalid --interval %s: must be positive", interval)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	area, err := pterm.DefaultArea.Start()
+	if err != nil {
+		return fmt.Errorf("failed to start live display: %w", err)
+	}
+	defer func() { _ = area.Stop() }()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		footer := pterm.Sprintf("Watching every %s, press Ctrl-C to stop (updated %s)\n", interval, time.Now().Format("15:04:05"))
+		if isTerminalStatus(deployment.Status) {
+			footer = ""
+		}
+		area.Update("\n" + formatStatus(deployment) + "\n" + footer)
+
+		if isTerminalStatus(deployment.Status) {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		if deployment, err = globalStore.Get(ctx, deployment.ID); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to get deployment: %w", err)
+		}
+	}
+
+	if deployment.Status == store.DeploymentStatusFailed {
+		return fmt.Errorf("deployment %s failed", deployment.ID)
+	}
+	return nil
+}
+
+// isTerminalStatus reports whether a deployment with this status can no longer change on its own
+func isTerminalStatus(status store.DeploymentStatus) bool {
+	switch status {
+	case store.DeploymentStatusSucceeded, store.DeploymentStatusFailed, store.DeploymentStatusDestroyed, store.DeploymentStatusPlanned:
+		return true
+	default:
+		return false
+	}
+}
+
+// formatStatus renders the status of a deployment
+func formatStatus(deployment *store.Deployment) string {
+	var b strings.Builder
+
+	b.WriteString(pterm.DefaultHeader.WithFullWidth().Sprintf("Status: %s", deployment.AppName))
+	b.WriteString("\n\n")
+
+	fmt.Fprintf(&b, "Deployment: %s\n", deployment.AppName)
+	fmt.Fprintf(&b, "ID:         %s\n", deployment.ID)
+	fmt.Fprintf(&b, "Status:     %s %s\n", getStatusIcon(deployment.Status), deployment.Status)
+	fmt.Fprintf(&b, "Strategy:   %s\n", deployment.Strategy)
+	fmt.Fprintf(&b, "Region:     %s\n", deployment.Region)
+	b.WriteString("\n")
 
 	// Display timestamps
-	pterm.Printf("Created:    %s\n", deployment.CreatedAt.Format("2006-01-02 15:04:05 MST"))
+	fmt.Fprintf(&b, "Created:    %s\n", deployment.CreatedAt.Format("2006-01-02 15:04:05 MST"))
 	if deployment.DeployedAt != nil {
-		pterm.Printf("Deployed:   %s\n", deployment.DeployedAt.Format("2006-01-02 15:04:05 MST"))
+		fmt.Fprintf(&b, "Deployed:   %s\n", deployment.DeployedAt.Format("2006-01-02 15:04:05 MST"))
 	}
 	if deployment.DestroyedAt != nil {
-		pterm.Printf("Destroyed:  %s\n", deployment.DestroyedAt.Format("2006-01-02 15:04:05 MST"))
+		fmt.Fprintf(&b, "Destroyed:  %s\n", deployment.DestroyedAt.Format("2006-01-02 15:04:05 MST"))
 	}
 
 	// Display error if failed
 	if deployment.ErrorMessage != "" {
-		pterm.Println()
-		pterm.Error.Println("Error:")
-		pterm.Printf("  %s\n", deployment.ErrorMessage)
+		b.WriteString("\n")
+		b.WriteString(pterm.Error.Sprintln("Error:"))
+		fmt.Fprintf(&b, "  %s\n", deployment.ErrorMessage)
 	}
 
-	pterm.Println()
-
-	return nil
+	return b.String()
 }
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+
+	"github.com/Smana/scai/internal/release"
+)
+
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Print the version of SCAI",
+	Long: `Print the version of SCAI, its commit and build date.
+
+With --check, the latest release is looked up on GitHub (cached for an hour, to stay within
+the GitHub API rate limit) and compared with the running version.
+
+Example:
+  scia version
+  scia version --check`,
+	Args: cobra.NoArgs,
+	RunE: runVersion,
+}
+
+func init() {
+	rootCmd.AddCommand(versionCmd)
+
+	versionCmd.Flags().Bool("check", false, "Check whether a newer release is available")
+}
+
+func runVersion(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+	pterm.Printf("scai %s\n", rootCmd.Version)
+
+	check, _ := cmd.Flags().GetBool("check")
+	if !check {
+		return nil
+	}
+
+	latest, err := release.Latest(context.Background())
+	if err != nil {
+		return err
+	}
+	newer, err := release.Newer(version, latest.Version)
+	if err != nil {
+		pterm.Info.Printf("Latest release is %s (%s): cannot compare with this build: %v\n", latest.Version, latest.URL, err)
+		return nil
+	}
+	if !newer {
+		pterm.Success.Printf("scai is up to date (latest release: %s)\n", latest.Version)
+		return nil
+	}
+	pterm.Warning.Printf("A newer release is available: %s (running %s)\n%s\n", latest.Version, version, latest.URL)
+	return nil
+}
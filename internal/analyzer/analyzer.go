@@ -1,25 +1,72 @@
 package analyzer
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strconv"
+	"strings"
 
 	"github.com/Smana/scai/internal/types"
 )
 
+// DefaultMaxDepth is the default directory depth searched for project files
+const DefaultMaxDepth = 4
+
+// DefaultIgnoreDirs lists directories skipped during file discovery
+// (VCS metadata, dependency caches, virtualenvs and build artifacts): manifests committed
+// in them are not the application's
+var DefaultIgnoreDirs = []string{
+	".git", ".hg", ".svn", "node_modules", "bower_components", "jspm_packages",
+	"venv", ".venv", "env", "__pycache__", ".tox", ".nox", ".pytest_cache", ".mypy_cache", "site-packages",
+	"vendor", "third_party", "target", "dist", "build", "out", ".gradle", ".m2", ".bundle",
+	".next", ".nuxt", ".output", ".svelte-kit", ".serverless", ".aws-sam", ".terraform", ".cache", "coverage",
+}
+
 // Analyzer handles repository analysis
 type Analyzer struct {
-	workDir string
-	verbose bool
+	workDir    string
+	verbose    bool
+	maxDepth   int
+	ignoreDirs map[string]bool
+	appDir     string      // Forced app directory (monorepos)
+	selectApp  AppSelector // Chooses between several detected apps
+	index      *fileIndex  // Files of the application being analyzed
+	zipLimits  ZipLimits   // Extraction limits of zip archives
+	ctx        context.Context
 }
 
 // NewAnalyzer creates a new Analyzer instance
 func NewAnalyzer(workDir string, verbose bool) *Analyzer {
-	return &Analyzer{
-		workDir: workDir,
-		verbose: verbose,
+	a := &Analyzer{
+		workDir:   workDir,
+		verbose:   verbose,
+		maxDepth:  DefaultMaxDepth,
+		zipLimits: DefaultZipLimits(),
+		ctx:       context.Background(),
+	}
+	a.SetIgnoreDirs(DefaultIgnoreDirs)
+	return a
+}
+
+// SetMaxDepth sets how many directory levels file discovery descends (ignored if not positive)
+func (a *Analyzer) SetMaxDepth(depth int) {
+	if depth > 0 {
+		a.maxDepth = depth
+	}
+}
+
+// SetContext sets the context canceling the repository clone (interrupt, --timeout)
+func (a *Analyzer) SetContext(ctx context.Context) {
+	a.ctx = ctx
+}
+
+// SetIgnoreDirs replaces the directory names skipped during file discovery
+func (a *Analyzer) SetIgnoreDirs(dirs []string) {
+	a.ignoreDirs = make(map[string]bool, len(dirs))
+	for _, dir := range dirs {
+		a.ignoreDirs[dir] = true
 	}
 }
 
@@ -30,6 +77,11 @@ func (a *Analyzer) Analyze(repoURL string) (*types.Analysis, error) {
 		return a.AnalyzeFromZip(repoURL)
 	}
 
+	// Check if it's a local directory
+	if IsLocalDirectory(repoURL) {
+		return a.AnalyzeFromDirectory(repoURL)
+	}
+
 	// Clone Git repository
 	repoDir := filepath.Join(a.workDir, "repo")
 
@@ -37,7 +89,7 @@ func (a *Analyzer) Analyze(repoURL string) (*types.Analysis, error) {
 		println("Cloning repository:", repoURL)
 	}
 
-	commitSHA, err := CloneRepository(repoURL, repoDir)
+	commitSHA, err := CloneRepository(a.ctx, repoURL, repoDir, a.verbose)
 	if err != nil {
 		return nil, err
 	}
@@ -55,63 +107,122 @@ func (a *Analyzer) analyzeDirectory(repoPath, repoURL, commitSHA string) (*types
 		Verbose:   a.verbose,
 	}
 
-	// Detect framework and app directory
-	framework, appDir, err := a.detectFramework(repoPath)
+	// Resolve the application to analyze (monorepos may contain several)
+	appRoot, err := a.resolveAppRoot(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	appPrefix, _ := filepath.Rel(repoPath, appRoot)
+
+	// Walk the application tree once: detectors look files up in the index
+	a.index = a.buildFileIndex(appRoot)
+	defer func() { a.index = nil }()
+
+	// Detect framework and app directory (relative to the repository root)
+	framework, appDir, err := a.detectFramework(appRoot)
 	if err != nil {
 		return nil, err
 	}
+	appDir = filepath.Join(appPrefix, appDir)
 	analysis.Framework = framework
 	analysis.AppDir = appDir
+	analysis.FrameworkVersion = detectFrameworkVersion(filepath.Join(repoPath, appDir), framework)
 
 	// Detect language
-	language := a.detectLanguage(repoPath)
+	language := a.detectLanguage(appRoot)
 	analysis.Language = language
 
 	// Detect package manager
-	packageManager := a.detectPackageManager(repoPath, language)
+	packageManager := a.detectPackageManager(appRoot, language)
 	analysis.PackageManager = packageManager
 
 	// Extract dependencies
-	deps, err := a.extractDependencies(repoPath, language)
+	deps, err := a.extractDependencies(appRoot, language)
 	if err != nil {
 		return nil, err
 	}
 	analysis.Dependencies = deps
 
-	// Detect start command (use app directory and package manager for accurate detection)
-	startCmd := a.detectStartCommand(repoPath, framework, appDir, packageManager)
+	// Detect the Python entry module (app object or __main__ block) instead of guessing file names
+	var entry *pythonEntry
+	if framework == "flask" || framework == "fastapi" {
+		if entry = a.detectPythonEntry(filepath.Join(repoPath, appDir)); entry != nil {
+			analysis.EntryPoint = entry.String()
+		}
+	}
+
+	// Detect start command (use app directory, package manager and entry for accurate detection)
+	startCmd := a.detectStartCommand(repoPath, framework, appDir, packageManager, entry)
 	analysis.StartCommand = startCmd
+	analysis.BuildCommand = a.detectBuildCommand(repoPath, framework, appDir, packageManager)
 
 	// Detect port (scan actual code files)
-	port := a.detectPort(repoPath, framework, appDir)
-	analysis.Port = port
+	analysis.Port, analysis.PortDetected = a.detectPort(repoPath, framework, appDir)
+
+	// Detect health check endpoint (scan route definitions)
+	analysis.HealthCheckPath = a.detectHealthCheckPath(appRoot)
+
+	// Detect listen calls restricted to localhost (scan code files)
+	analysis.BindsLocalhost = a.detectLocalhostBinding(appRoot)
 
 	// Extract environment variables
-	envVars := a.extractEnvVars(repoPath)
+	envVars := a.extractEnvVars(appRoot)
 	analysis.EnvVars = envVars
 
-	// Check for special files
-	analysis.HasDockerfile = fileExists(filepath.Join(repoPath, "Dockerfile"))
+	// Check for special files (app directory first, then repository root)
+	analysis.HasDockerfile = fileExists(filepath.Join(appRoot, "Dockerfile")) ||
+		fileExists(filepath.Join(repoPath, "Dockerfile"))
+
+	// The port exposed by the Dockerfile prevails over the framework default
+	if analysis.HasDockerfile {
+		dockerfile := filepath.Join(appRoot, "Dockerfile")
+		if !fileExists(dockerfile) {
+			dockerfile = filepath.Join(repoPath, "Dockerfile")
+		}
+		analysis.DockerfilePorts = parseDockerfilePorts(dockerfile)
+		if port := dockerfilePort(analysis.DockerfilePorts); port > 0 && !analysis.PortDetected {
+			analysis.Port, analysis.PortDetected = port, true
+		}
+	}
 	analysis.HasDockerCompose = fileExists(filepath.Join(repoPath, "docker-compose.yml")) ||
 		fileExists(filepath.Join(repoPath, "docker-compose.yaml"))
 
+	// Parse docker-compose services
+	compose := parseCompose(appRoot)
+	if compose == nil {
+		compose = parseCompose(repoPath)
+	}
+	if compose != nil {
+		analysis.HasDockerCompose = true
+		analysis.ComposeServices = compose.Services
+		analysis.ComposeImages = compose.Images
+		analysis.ComposePorts = compose.Ports
+	}
+
+	// Detect backing services (databases, caches)
+	analysis.RequiresDatabase, analysis.RequiresCache = detectDataStores(deps, analysis.ComposeImages)
+
 	return analysis, nil
 }
 
 // detectFramework detects the application framework and returns the framework name and app directory
 func (a *Analyzer) detectFramework(repoPath string) (string, string, error) {
+	// Only the manifests of the detected language count: a root go.mod is not overridden
+	// by a requirements.txt nested in a scripts directory
+	language := a.detectLanguage(repoPath)
+
 	// Check for Python frameworks (multiple package managers)
 	// Priority: Poetry > uv > requirements.txt > Pipfile
 
 	// Poetry projects (pyproject.toml + poetry.lock)
-	if pyprojectPath, foundPyproject := findFileRecursive(repoPath, "pyproject.toml"); foundPyproject {
+	if pyprojectPath, foundPyproject := a.findFileRecursive(repoPath, "pyproject.toml"); foundPyproject && language == "python" {
 		appDir := filepath.Dir(pyprojectPath)
 		relAppDir, _ := filepath.Rel(repoPath, appDir)
 
 		// Check if it's a Poetry project (has poetry.lock)
 		poetryLockPath := filepath.Join(appDir, "poetry.lock")
 		if fileExists(poetryLockPath) {
-			if _, djangoFound := findFileRecursive(repoPath, "manage.py"); djangoFound {
+			if _, djangoFound := a.findFileRecursive(repoPath, "manage.py"); djangoFound {
 				return "django", relAppDir, nil
 			}
 
@@ -136,7 +247,7 @@ func (a *Analyzer) detectFramework(repoPath string) (string, string, error) {
 		// Check if it's a uv project (has uv.lock)
 		uvLockPath := filepath.Join(appDir, "uv.lock")
 		if fileExists(uvLockPath) {
-			if _, djangoFound := findFileRecursive(repoPath, "manage.py"); djangoFound {
+			if _, djangoFound := a.findFileRecursive(repoPath, "manage.py"); djangoFound {
 				return "django", relAppDir, nil
 			}
 
@@ -160,13 +271,13 @@ func (a *Analyzer) detectFramework(repoPath string) (string, string, error) {
 	}
 
 	// Traditional requirements.txt
-	if reqPath, found := findFileRecursive(repoPath, "requirements.txt"); found {
+	if reqPath, found := a.findFileRecursive(repoPath, "requirements.txt"); found && language == "python" {
 		appDir := filepath.Dir(reqPath)
 		// Make appDir relative to repoPath
 		relAppDir, _ := filepath.Rel(repoPath, appDir)
 
 		// Python framework detection
-		if _, djangoFound := findFileRecursive(repoPath, "manage.py"); djangoFound {
+		if _, djangoFound := a.findFileRecursive(repoPath, "manage.py"); djangoFound {
 			return "django", relAppDir, nil
 		}
 
@@ -189,11 +300,11 @@ func (a *Analyzer) detectFramework(repoPath string) (string, string, error) {
 	}
 
 	// Pipfile (Pipenv)
-	if pipfilePath, found := findFileRecursive(repoPath, "Pipfile"); found {
+	if pipfilePath, found := a.findFileRecursive(repoPath, "Pipfile"); found && language == "python" {
 		appDir := filepath.Dir(pipfilePath)
 		relAppDir, _ := filepath.Rel(repoPath, appDir)
 
-		if _, djangoFound := findFileRecursive(repoPath, "manage.py"); djangoFound {
+		if _, djangoFound := a.findFileRecursive(repoPath, "manage.py"); djangoFound {
 			return "django", relAppDir, nil
 		}
 
@@ -215,7 +326,7 @@ func (a *Analyzer) detectFramework(repoPath string) (string, string, error) {
 		return "flask", relAppDir, nil
 	}
 
-	if pkgPath, found := findFileRecursive(repoPath, "package.json"); found {
+	if pkgPath, found := a.findFileRecursive(repoPath, "package.json"); found && language == "javascript" {
 		appDir := filepath.Dir(pkgPath)
 		relAppDir, _ := filepath.Rel(repoPath, appDir)
 		// JavaScript/TypeScript framework detection
@@ -223,16 +334,22 @@ func (a *Analyzer) detectFramework(repoPath string) (string, string, error) {
 		return "express", relAppDir, nil
 	}
 
-	if goModPath, found := findFileRecursive(repoPath, "go.mod"); found {
+	if goModPath, found := a.findFileRecursive(repoPath, "go.mod"); found && language == "go" {
 		appDir := filepath.Dir(goModPath)
 		relAppDir, _ := filepath.Rel(repoPath, appDir)
 		return "go", relAppDir, nil
 	}
 
-	if gemfilePath, found := findFileRecursive(repoPath, "Gemfile"); found {
+	if gemfilePath, found := a.findFileRecursive(repoPath, "Gemfile"); found && language == "ruby" {
 		appDir := filepath.Dir(gemfilePath)
 		relAppDir, _ := filepath.Rel(repoPath, appDir)
-		return "rails", relAppDir, nil
+		return detectRubyFramework(appDir), relAppDir, nil
+	}
+
+	if buildFilePath, found := a.findJavaBuildFile(repoPath); found && language == "java" {
+		appDir := filepath.Dir(buildFilePath)
+		relAppDir, _ := filepath.Rel(repoPath, appDir)
+		return detectJavaFramework(buildFilePath), relAppDir, nil
 	}
 
 	return "unknown", ".", nil
@@ -243,7 +360,7 @@ func (a *Analyzer) detectPackageManager(repoPath, language string) string {
 	switch language {
 	case "python":
 		// Check for Poetry (pyproject.toml + poetry.lock)
-		if pyprojectPath, found := findFileRecursive(repoPath, "pyproject.toml"); found {
+		if pyprojectPath, found := a.findFileRecursive(repoPath, "pyproject.toml"); found {
 			appDir := filepath.Dir(pyprojectPath)
 			if fileExists(filepath.Join(appDir, "poetry.lock")) {
 				return "poetry"
@@ -254,26 +371,26 @@ func (a *Analyzer) detectPackageManager(repoPath, language string) string {
 			}
 		}
 		// Check for Pipenv (Pipfile)
-		if _, found := findFileRecursive(repoPath, "Pipfile"); found {
+		if _, found := a.findFileRecursive(repoPath, "Pipfile"); found {
 			return "pipenv"
 		}
 		// Default to pip (requirements.txt)
-		if _, found := findFileRecursive(repoPath, "requirements.txt"); found {
+		if _, found := a.findFileRecursive(repoPath, "requirements.txt"); found {
 			return "pip"
 		}
 		return "pip" // Default for Python
 
 	case "javascript":
 		// Check for yarn.lock
-		if _, found := findFileRecursive(repoPath, "yarn.lock"); found {
+		if _, found := a.findFileRecursive(repoPath, "yarn.lock"); found {
 			return "yarn"
 		}
 		// Check for pnpm-lock.yaml
-		if _, found := findFileRecursive(repoPath, "pnpm-lock.yaml"); found {
+		if _, found := a.findFileRecursive(repoPath, "pnpm-lock.yaml"); found {
 			return "pnpm"
 		}
 		// Default to npm
-		if _, found := findFileRecursive(repoPath, "package.json"); found {
+		if _, found := a.findFileRecursive(repoPath, "package.json"); found {
 			return "npm"
 		}
 		return "npm"
@@ -284,52 +401,64 @@ func (a *Analyzer) detectPackageManager(repoPath, language string) string {
 	case "ruby":
 		return "bundler"
 
+	case "java":
+		if buildFilePath, found := a.findJavaBuildFile(repoPath); found && filepath.Base(buildFilePath) != "pom.xml" {
+			return "gradle"
+		}
+		return "maven"
+
 	default:
 		return "unknown"
 	}
 }
 
-// detectLanguage detects the primary programming language
+// detectLanguage detects the primary programming language from the shallowest language
+// indicator file: a root go.mod wins over a package.json nested in a tool directory. Between
+// indicators of the same depth, the order below decides.
 func (a *Analyzer) detectLanguage(repoPath string) string {
-	// Search recursively for language indicator files
-	// Python: Check multiple package managers
-	if _, reqFound := findFileRecursive(repoPath, "requirements.txt"); reqFound {
-		return "python"
-	}
-	if _, setupFound := findFileRecursive(repoPath, "setup.py"); setupFound {
-		return "python"
-	}
-	if _, pipFound := findFileRecursive(repoPath, "Pipfile"); pipFound {
-		return "python"
-	}
-	if pyprojectPath, found := findFileRecursive(repoPath, "pyproject.toml"); found {
-		// Check if it's a Python project (has poetry.lock or uv.lock)
-		appDir := filepath.Dir(pyprojectPath)
-		if fileExists(filepath.Join(appDir, "poetry.lock")) || fileExists(filepath.Join(appDir, "uv.lock")) {
-			return "python"
+	indicators := []struct {
+		language string
+		find     func() (string, bool)
+	}{
+		// Python: Check multiple package managers
+		{"python", func() (string, bool) { return a.findFileRecursive(repoPath, "requirements.txt") }},
+		{"python", func() (string, bool) { return a.findFileRecursive(repoPath, "setup.py") }},
+		{"python", func() (string, bool) { return a.findFileRecursive(repoPath, "Pipfile") }},
+		{"python", func() (string, bool) {
+			// Check if it's a Python project (has poetry.lock or uv.lock)
+			pyprojectPath, found := a.findFileRecursive(repoPath, "pyproject.toml")
+			if !found {
+				return "", false
+			}
+			appDir := filepath.Dir(pyprojectPath)
+			return pyprojectPath, fileExists(filepath.Join(appDir, "poetry.lock")) || fileExists(filepath.Join(appDir, "uv.lock"))
+		}},
+		{"javascript", func() (string, bool) { return a.findFileRecursive(repoPath, "package.json") }},
+		{"go", func() (string, bool) { return a.findFileRecursive(repoPath, "go.mod") }},
+		{"ruby", func() (string, bool) { return a.findFileRecursive(repoPath, "Gemfile") }},
+		{"java", func() (string, bool) { return a.findJavaBuildFile(repoPath) }},
+	}
+
+	language, languageDepth := "unknown", -1
+	for _, indicator := range indicators {
+		path, found := indicator.find()
+		if !found {
+			continue
+		}
+		if depth := pathDepth(repoPath, path); languageDepth < 0 || depth < languageDepth {
+			language, languageDepth = indicator.language, depth
 		}
 	}
+	return language
+}
 
-	if _, found := findFileRecursive(repoPath, "package.json"); found {
-		return "javascript"
-	}
-
-	if _, found := findFileRecursive(repoPath, "go.mod"); found {
-		return "go"
-	}
-
-	if _, found := findFileRecursive(repoPath, "Gemfile"); found {
-		return "ruby"
-	}
-
-	if _, pomFound := findFileRecursive(repoPath, "pom.xml"); pomFound {
-		return "java"
-	}
-	if _, gradleFound := findFileRecursive(repoPath, "build.gradle"); gradleFound {
-		return "java"
+// pathDepth returns how many directories path is nested below dir (0 for a file of dir)
+func pathDepth(dir, path string) int {
+	rel, err := filepath.Rel(dir, filepath.Dir(path))
+	if err != nil || rel == "." {
+		return 0
 	}
-
-	return "unknown"
+	return strings.Count(rel, string(filepath.Separator)) + 1
 }
 
 // extractDependencies extracts project dependencies
@@ -346,55 +475,39 @@ func (a *Analyzer) extractDependencies(repoPath, language string) ([]string, err
 	case "go":
 		// TODO: Parse go.mod
 		deps = []string{} // Placeholder
+	case "ruby":
+		if gemfilePath, found := a.findFileRecursive(repoPath, "Gemfile"); found {
+			deps = parseGemfile(gemfilePath)
+		}
 	}
 
 	return deps, nil
 }
 
 // detectStartCommand detects the application start command (without cd, as that's handled by the generator)
-func (a *Analyzer) detectStartCommand(repoPath, framework, appDir, packageManager string) string {
+func (a *Analyzer) detectStartCommand(repoPath, framework, appDir, packageManager string, entry *pythonEntry) string {
 	switch framework {
 	case "fastapi":
-		// FastAPI typically uses uvicorn
+		// FastAPI typically uses uvicorn, serving the detected app object
 		entryPoint := "main:app"
-		if fileExists(filepath.Join(repoPath, appDir, "main.py")) {
-			entryPoint = "main:app"
-		} else if fileExists(filepath.Join(repoPath, appDir, "app.py")) {
+		if entry != nil && entry.object != "" {
+			entryPoint = entry.String()
+		} else if !fileExists(filepath.Join(repoPath, appDir, "main.py")) && fileExists(filepath.Join(repoPath, appDir, "app.py")) {
 			entryPoint = "app:app"
 		}
+		return pythonToolCommand(packageManager, "uvicorn "+entryPoint+" --host 0.0.0.0 --port 8000")
 
-		// Use package manager-specific command
-		switch packageManager {
-		case "poetry":
-			return "poetry run uvicorn " + entryPoint + " --host 0.0.0.0 --port 8000"
-		case "uv":
-			return "uv run uvicorn " + entryPoint + " --host 0.0.0.0 --port 8000"
-		case "pipenv":
-			return "pipenv run uvicorn " + entryPoint + " --host 0.0.0.0 --port 8000"
-		default: // pip
-			return "uvicorn " + entryPoint + " --host 0.0.0.0 --port 8000"
+	case "flask":
+		if entry != nil {
+			return flaskStartCommand(entry, packageManager)
 		}
 
-	case "flask":
-		// Determine the Python entry point
+		// No app object found: guess the Python entry point
 		entryPoint := "app.py"
-		if fileExists(filepath.Join(repoPath, appDir, "app.py")) {
-			entryPoint = "app.py"
-		} else if fileExists(filepath.Join(repoPath, appDir, "main.py")) {
+		if !fileExists(filepath.Join(repoPath, appDir, "app.py")) && fileExists(filepath.Join(repoPath, appDir, "main.py")) {
 			entryPoint = "main.py"
 		}
-
-		// Use package manager-specific command
-		switch packageManager {
-		case "poetry":
-			return "poetry run python " + entryPoint
-		case "uv":
-			return "uv run " + entryPoint
-		case "pipenv":
-			return "pipenv run python " + entryPoint
-		default: // pip
-			return "python3 " + entryPoint
-		}
+		return pythonScriptCommand(packageManager, entryPoint)
 
 	case "django":
 		// Use package manager-specific command for Django
@@ -410,26 +523,48 @@ func (a *Analyzer) detectStartCommand(repoPath, framework, appDir, packageManage
 		}
 
 	case "express":
-		// Use JavaScript package manager-specific command
-		switch packageManager {
-		case "yarn":
-			return "yarn start"
-		case "pnpm":
-			return "pnpm start"
-		default: // npm
-			return "npm start"
-		}
+		// start script of package.json, or node running the main file
+		_, start := nodeCommands(filepath.Join(repoPath, appDir), packageManager)
+		return start
 
 	case "go":
 		return "go run ."
 
+	case "rails":
+		return "bundle exec rails server -b 0.0.0.0 -p 3000"
+
+	case "sinatra":
+		// Sinatra listens on localhost only unless told otherwise
+		entryPoint := "app.rb"
+		if !fileExists(filepath.Join(repoPath, appDir, "app.rb")) && fileExists(filepath.Join(repoPath, appDir, "main.rb")) {
+			entryPoint = "main.rb"
+		}
+		return "bundle exec ruby " + entryPoint + " -o 0.0.0.0"
+
+	case "rack":
+		return "bundle exec rackup -o 0.0.0.0 -p 9292"
+
+	case "spring-boot", "java":
+		return javaStartCommand(filepath.Join(repoPath, appDir), framework, packageManager)
+
 	default:
 		return "unknown"
 	}
 }
 
-// detectPort detects the application port by scanning code files
-func (a *Analyzer) detectPort(repoPath, framework, appDir string) int {
+// detectBuildCommand detects the command building the application before it starts, empty
+// when it runs from its sources
+func (a *Analyzer) detectBuildCommand(repoPath, framework, appDir, packageManager string) string {
+	if framework != "express" {
+		return ""
+	}
+	build, _ := nodeCommands(filepath.Join(repoPath, appDir), packageManager)
+	return build
+}
+
+// detectPort detects the application port by scanning code files, and reports whether it was
+// found there rather than defaulted from the framework
+func (a *Analyzer) detectPort(repoPath, framework, appDir string) (int, bool) {
 	// Try to scan code files for port numbers
 	appPath := filepath.Join(repoPath, appDir)
 
@@ -437,28 +572,37 @@ func (a *Analyzer) detectPort(repoPath, framework, appDir string) int {
 	case "fastapi", "flask", "django":
 		// Scan Python files for port=XXXX
 		if port := a.scanPythonFilesForPort(appPath); port > 0 {
-			return port
+			return port, true
 		}
 		// Fallback to framework defaults
 		if framework == "flask" {
-			return 5000
+			return 5000, false
 		}
 		// FastAPI and Django default to 8000
-		return 8000
+		return 8000, false
 
 	case "express":
 		// TODO: Scan JavaScript files for port
-		return 3000
+		return 3000, false
 
 	case "rails":
-		return 3000
+		return 3000, false
+
+	case "sinatra":
+		return 4567, false
+
+	case "rack":
+		return 9292, false
+
+	case "spring-boot":
+		return 8080, false
 
 	case "go":
 		// TODO: Scan Go files for port
-		return 8080
+		return 8080, false
 
 	default:
-		return 8080
+		return 8080, false
 	}
 }
 
@@ -512,36 +656,42 @@ func fileExists(path string) bool {
 	return err == nil
 }
 
-// findFileRecursive searches for a file recursively in a directory (max depth 3)
-func findFileRecursive(dir, filename string) (string, bool) {
-	return findFileRecursiveWithDepth(dir, filename, 0, 3)
-}
-
-// findFileRecursiveWithDepth searches for a file recursively with depth limit
-func findFileRecursiveWithDepth(dir, filename string, currentDepth, maxDepth int) (string, bool) {
-	if currentDepth > maxDepth {
-		return "", false
-	}
-
-	// Check current directory
-	targetPath := filepath.Join(dir, filename)
-	if fileExists(targetPath) {
-		return targetPath, true
+// findFileRecursive searches for a file recursively in a directory (up to the configured depth),
+// in the file index when it covers the directory
+func (a *Analyzer) findFileRecursive(dir, filename string) (string, bool) {
+	if a.index.covers(dir, a.maxDepth) {
+		return a.index.find(filename)
 	}
+	return a.findFileRecursiveWithDepth(dir, filename, 0)
+}
 
-	// Search subdirectories
-	entries, err := os.ReadDir(dir)
-	if err != nil {
-		return "", false
-	}
+// findFileRecursiveWithDepth searches for a file breadth-first with depth limit: the
+// shallowest match wins, so a root manifest is preferred over a nested one
+func (a *Analyzer) findFileRecursiveWithDepth(dir, filename string, currentDepth int) (string, bool) {
+	level := []string{dir}
+	for depth := currentDepth; depth <= a.maxDepth && len(level) > 0; depth++ {
+		// Check the directories of the current level
+		for _, levelDir := range level {
+			targetPath := filepath.Join(levelDir, filename)
+			if fileExists(targetPath) {
+				return targetPath, true
+			}
+		}
 
-	for _, entry := range entries {
-		if entry.IsDir() && entry.Name() != ".git" && entry.Name() != "node_modules" && entry.Name() != "venv" {
-			subdirPath := filepath.Join(dir, entry.Name())
-			if found, ok := findFileRecursiveWithDepth(subdirPath, filename, currentDepth+1, maxDepth); ok {
-				return found, true
+		// Then their subdirectories
+		var next []string
+		for _, levelDir := range level {
+			entries, err := os.ReadDir(levelDir)
+			if err != nil {
+				continue
+			}
+			for _, entry := range entries {
+				if entry.IsDir() && !a.ignoreDirs[entry.Name()] {
+					next = append(next, filepath.Join(levelDir, entry.Name()))
+				}
 			}
 		}
+		level = next
 	}
 
 	return "", false
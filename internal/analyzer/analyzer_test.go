@@ -0,0 +1,1069 @@
+package analyzer
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+
+	"github.com/Smana/scai/internal/network"
+)
+
+// writeFile creates a file (and its parent directories) under root
+func writeFile(t testing.TB, root, relPath, content string) {
+	t.Helper()
+
+	path := filepath.Join(root, relPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write %s: %v", relPath, err)
+	}
+}
+
+func TestFindFileRecursiveDepthFour(t *testing.T) {
+	repo := t.TempDir()
+	writeFile(t, repo, "services/api/src/app/package.json", `{"name": "api"}`)
+
+	a := NewAnalyzer(t.TempDir(), false)
+	found, ok := a.findFileRecursive(repo, "package.json")
+	if !ok {
+		t.Fatal("Expected package.json at depth 4 to be found with default max depth")
+	}
+	if filepath.Base(filepath.Dir(found)) != "app" {
+		t.Errorf("Expected package.json in app directory, got %s", found)
+	}
+
+	a.SetMaxDepth(3)
+	if _, ok := a.findFileRecursive(repo, "package.json"); ok {
+		t.Error("Expected package.json at depth 4 NOT to be found with max depth 3")
+	}
+}
+
+func TestFindFileRecursiveIgnoreDirs(t *testing.T) {
+	repo := t.TempDir()
+	writeFile(t, repo, "node_modules/lib/package.json", `{"name": "lib"}`)
+	writeFile(t, repo, "dist/package.json", `{"name": "bundle"}`)
+
+	a := NewAnalyzer(t.TempDir(), false)
+	if found, ok := a.findFileRecursive(repo, "package.json"); ok {
+		t.Errorf("Expected default ignore list to skip build and dependency dirs, found %s", found)
+	}
+
+	// A custom ignore list replaces the defaults
+	a.SetIgnoreDirs([]string{"node_modules"})
+	found, ok := a.findFileRecursive(repo, "package.json")
+	if !ok || filepath.Base(filepath.Dir(found)) != "dist" {
+		t.Errorf("Expected package.json in dist with custom ignore list, got %q", found)
+	}
+}
+
+func TestDetectLanguagePrefersRootManifest(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{
+			name: "root go.mod wins over nested package.json",
+			files: map[string]string{
+				"go.mod":                        "module example.com/api\n",
+				"main.go":                       "package main\n",
+				"tools/web/package.json":        `{"name": "docs-site"}`,
+				"scripts/lint/requirements.txt": "ruff\n",
+			},
+			want: "go",
+		},
+		{
+			name: "committed dependencies and build output are ignored",
+			files: map[string]string{
+				"Gemfile":                           "source 'https://rubygems.org'\ngem 'sinatra'\n",
+				"vendor/bundle/gems/x/package.json": `{"name": "x"}`,
+				"bower_components/y/package.json":   `{"name": "y"}`,
+				"out/requirements.txt":              "flask\n",
+				".serverless/requirements.txt":      "flask\n",
+			},
+			want: "ruby",
+		},
+		{
+			name: "same depth keeps the indicator order",
+			files: map[string]string{
+				"package.json":     `{"name": "web"}`,
+				"requirements.txt": "flask\n",
+			},
+			want: "python",
+		},
+		{
+			name: "nested app without root manifest",
+			files: map[string]string{
+				"services/api/go.mod": "module api\n",
+				"README.md":           "# api\n",
+			},
+			want: "go",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := t.TempDir()
+			for path, content := range tt.files {
+				writeFile(t, repo, path, content)
+			}
+
+			a := NewAnalyzer(t.TempDir(), false)
+			if got := a.detectLanguage(repo); got != tt.want {
+				t.Errorf("detectLanguage() = %s, want %s", got, tt.want)
+			}
+
+			// Same result from the file index
+			a.index = a.buildFileIndex(repo)
+			if got := a.detectLanguage(repo); got != tt.want {
+				t.Errorf("detectLanguage() with file index = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	// The framework comes from the root manifest too, not from the nested requirements.txt
+	repo := t.TempDir()
+	writeFile(t, repo, "go.mod", "module example.com/api\n")
+	writeFile(t, repo, "scripts/lint/requirements.txt", "ruff\n")
+	a := NewAnalyzer(t.TempDir(), false)
+	framework, appDir, err := a.detectFramework(repo)
+	if err != nil || framework != "go" || appDir != "." {
+		t.Errorf("detectFramework() = %s, %s, %v, want go, ., nil", framework, appDir, err)
+	}
+}
+
+// writeDeepTree creates a synthetic monorepo: breadth subdirectories per level down to
+// depth, each holding source files, with dependency directories and a Python app in the
+// last branch (found after walking the rest of the tree)
+func writeDeepTree(t testing.TB, root string, breadth, depth int) {
+	t.Helper()
+
+	var write func(dir string, level int)
+	write = func(dir string, level int) {
+		writeFile(t, root, filepath.Join(dir, "README.md"), "docs\n")
+		writeFile(t, root, filepath.Join(dir, "util.js"), "module.exports = {}\n")
+		writeFile(t, root, filepath.Join(dir, "node_modules", "lib", "package.json"), `{"name": "lib"}`)
+		if level == depth {
+			return
+		}
+		for i := 0; i < breadth; i++ {
+			write(filepath.Join(dir, fmt.Sprintf("pkg%d", i)), level+1)
+		}
+	}
+	write(".", 0)
+
+	last := filepath.Join(fmt.Sprintf("pkg%d", breadth-1), fmt.Sprintf("pkg%d", breadth-1), "api")
+	writeFile(t, root, filepath.Join(last, "requirements.txt"), "fastapi\n")
+	writeFile(t, root, filepath.Join(last, "main.py"), "uvicorn.run(app, port=9000)\n")
+}
+
+func TestFileIndexMatchesRecursiveSearch(t *testing.T) {
+	repo := t.TempDir()
+	writeDeepTree(t, repo, 3, 3)
+	writeFile(t, repo, "pkg0/pkg1/pkg2/requirements.txt", "flask\n")
+	writeFile(t, repo, "pkg1/requirements.txt", "django\n")
+	writeFile(t, repo, "pkg0/pkg0/pkg0/pkg0/go.mod", "module deep\n")
+
+	a := NewAnalyzer(t.TempDir(), false)
+	names := append([]string{"README.md", "manage.py", "package.json"}, appManifests...)
+	for _, depth := range []int{DefaultMaxDepth, 2} {
+		a.SetMaxDepth(depth)
+		index := a.buildFileIndex(repo)
+		for _, name := range names {
+			wantPath, wantFound := a.findFileRecursiveWithDepth(repo, name, 0)
+			gotPath, gotFound := index.find(name)
+			if gotPath != wantPath || gotFound != wantFound {
+				t.Errorf("depth %d, %s: index found %q (%v), recursive search found %q (%v)", depth, name, gotPath, gotFound, wantPath, wantFound)
+			}
+		}
+	}
+}
+
+func TestFileIndexDuplicateNames(t *testing.T) {
+	repo := t.TempDir()
+	for _, path := range []string{
+		"a/b/c/Dockerfile", "b/Dockerfile", "z/Dockerfile",
+		"a/x/package.json", "c/package.json", "node_modules/package.json",
+		"a/go.mod", "a/a/go.mod", "b/a/a/go.mod", "a/b/Procfile",
+	} {
+		writeFile(t, repo, path, "\n")
+	}
+
+	tests := []struct {
+		maxDepth int
+		want     map[string]string // File name -> match relative to repo, empty when not found
+	}{
+		{DefaultMaxDepth, map[string]string{"Dockerfile": "b/Dockerfile", "package.json": "c/package.json", "go.mod": "a/go.mod", "Procfile": "a/b/Procfile"}},
+		{1, map[string]string{"Dockerfile": "b/Dockerfile", "package.json": "c/package.json", "go.mod": "a/go.mod", "Procfile": ""}},
+	}
+
+	a := NewAnalyzer(t.TempDir(), false)
+	for _, tt := range tests {
+		a.SetMaxDepth(tt.maxDepth)
+		index := a.buildFileIndex(repo)
+		for name, rel := range tt.want {
+			want := ""
+			if rel != "" {
+				want = filepath.Join(repo, rel)
+			}
+			walkPath, _ := a.findFileRecursiveWithDepth(repo, name, 0)
+			indexPath, _ := index.find(name)
+			if walkPath != want || indexPath != want {
+				t.Errorf("depth %d, %s: expected %q, recursive search found %q, index found %q", tt.maxDepth, name, want, walkPath, indexPath)
+			}
+		}
+	}
+}
+
+func TestDiscoverAppsMonorepo(t *testing.T) {
+	repo := t.TempDir()
+	writeFile(t, repo, "package.json", `{"workspaces": ["apps/*"]}`)
+	writeFile(t, repo, "apps/web/package.json", `{"name": "web"}`)
+	writeFile(t, repo, "services/api/requirements.txt", "fastapi\nuvicorn\n")
+	writeFile(t, repo, "services/api/docs/requirements.txt", "mkdocs\n")
+
+	a := NewAnalyzer(t.TempDir(), false)
+	candidates := a.DiscoverApps(repo)
+
+	got := make(map[string]string, len(candidates))
+	for _, candidate := range candidates {
+		got[candidate.Dir] = candidate.Framework
+	}
+
+	want := map[string]string{".": "express", "apps/web": "express", "services/api": "fastapi"}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d apps, got %v", len(want), got)
+	}
+	for dir, framework := range want {
+		if got[dir] != framework {
+			t.Errorf("Expected %s to be detected as %s, got %q", dir, framework, got[dir])
+		}
+	}
+}
+
+func TestAnalyzeDirectoryAppDir(t *testing.T) {
+	repo := t.TempDir()
+	writeFile(t, repo, "apps/web/package.json", `{"name": "web"}`)
+	writeFile(t, repo, "services/api/requirements.txt", "fastapi\n")
+
+	a := NewAnalyzer(t.TempDir(), false)
+	a.SetAppSelector(func(candidates []AppCandidate) (string, error) {
+		return "services/api", nil
+	})
+
+	analysis, err := a.analyzeDirectory(repo, repo, "")
+	if err != nil {
+		t.Fatalf("analyzeDirectory failed: %v", err)
+	}
+	if analysis.AppDir != "services/api" || analysis.Framework != "fastapi" {
+		t.Errorf("Expected selected app services/api (fastapi), got %s (%s)", analysis.AppDir, analysis.Framework)
+	}
+
+	// An explicit app dir takes precedence over the selector
+	a.SetAppDir("apps/web")
+	analysis, err = a.analyzeDirectory(repo, repo, "")
+	if err != nil {
+		t.Fatalf("analyzeDirectory failed: %v", err)
+	}
+	if analysis.AppDir != "apps/web" || analysis.Framework != "express" {
+		t.Errorf("Expected app dir apps/web (express), got %s (%s)", analysis.AppDir, analysis.Framework)
+	}
+
+	a.SetAppDir("../outside")
+	if _, err := a.analyzeDirectory(repo, repo, ""); err == nil {
+		t.Error("Expected error for app dir outside the repository")
+	}
+}
+
+func TestDetectHealthCheckPath(t *testing.T) {
+	tests := []struct {
+		name, file, content, want string
+	}{
+		{"flask", "app.py", "@app.route(\"/\")\ndef index(): ...\n@app.route('/healthz')\ndef healthz(): ...\n", "/healthz"},
+		{"fastapi", "api/main.py", "@router.get(\"/api/health\")\n", "/api/health"},
+		{"express", "server.js", "app.get(`/ping`, (req, res) => res.send('pong'))\n", "/ping"},
+		{"go", "main.go", "mux.HandleFunc(\"GET /livez\", live)\nmux.HandleFunc(\"/health\", health)\n", "/health"},
+		{"django", "urls.py", "urlpatterns = [path(\"health/\", views.health)]\n", "/health"},
+		{"rails", "config/routes.rb", "get \"up\" => \"rails/health#show\"\n", "/up"},
+		{"dict lookup", "app.py", "status = data.get(\"status\")\n", "/"},
+		{"none", "app.py", "@app.route(\"/users\")\n", "/"},
+	}
+
+	for _, tt := range tests {
+		repo := t.TempDir()
+		writeFile(t, repo, tt.file, tt.content)
+
+		a := NewAnalyzer(t.TempDir(), false)
+		if got := a.detectHealthCheckPath(repo); got != tt.want {
+			t.Errorf("%s: expected health check path %s, got %s", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestDetectLocalhostBinding(t *testing.T) {
+	tests := []struct {
+		name, file, content string
+		want                bool
+	}{
+		{"flask localhost", "app.py", "app.run(host='127.0.0.1', port=5000)\n", true},
+		{"uvicorn localhost", "main.py", "uvicorn.run(app, host=\"localhost\", port=8000)\n", true},
+		{"express localhost", "server.js", "app.listen(PORT, '127.0.0.1', () => console.log('up'))\n", true},
+		{"go localhost", "main.go", "log.Fatal(http.ListenAndServe(\"localhost:8080\", nil))\n", true},
+		{"flask all interfaces", "app.py", "app.run(host=\"0.0.0.0\", port=5000)\n", false},
+		{"express port only", "server.js", "app.listen(port, () => {})\n", false},
+		{"go all interfaces", "main.go", "http.ListenAndServe(\":8080\", nil)\n", false},
+		{"database host", "app.py", "DB_URL = \"postgres://localhost:5432/app\"\n", false},
+	}
+
+	for _, tt := range tests {
+		repo := t.TempDir()
+		writeFile(t, repo, tt.file, tt.content)
+
+		if got := NewAnalyzer(t.TempDir(), false).detectLocalhostBinding(repo); got != tt.want {
+			t.Errorf("%s: expected localhost binding %t, got %t", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestDetectPort(t *testing.T) {
+	tests := []struct {
+		name, content string
+		port          int
+		detected      bool
+	}{
+		{"in the code", "app.run(host=\"0.0.0.0\", port=8081)\n", 8081, true},
+		{"framework default", "app.run()\n", 5000, false},
+	}
+
+	for _, tt := range tests {
+		repo := t.TempDir()
+		writeFile(t, repo, "app.py", tt.content)
+
+		port, detected := NewAnalyzer(t.TempDir(), false).detectPort(repo, "flask", ".")
+		if port != tt.port || detected != tt.detected {
+			t.Errorf("%s: expected port %d (detected %t), got %d (detected %t)", tt.name, tt.port, tt.detected, port, detected)
+		}
+	}
+}
+
+func TestDetectDataStores(t *testing.T) {
+	tests := []struct {
+		name            string
+		deps, images    []string
+		database, cache bool
+	}{
+		{"python driver", []string{"flask", "psycopg2-binary"}, nil, true, false},
+		{"dependency names are case-insensitive", []string{"PyMySQL"}, nil, true, false},
+		{"node-postgres", []string{"express", "pg"}, nil, true, false},
+		{"pg is only matched whole", []string{"pgpass", "pg-boss"}, nil, false, false},
+		{"embedded SQLite", []string{"sqlite3", "better-sqlite3", "aiosqlite"}, nil, false, false},
+		{"cache client", []string{"ioredis"}, nil, false, true},
+		{"compose images", nil, []string{"postgres", "redis"}, true, true},
+		{"no data store", []string{"flask", "gunicorn"}, []string{"nginx"}, false, false},
+	}
+
+	for _, tt := range tests {
+		database, cache := detectDataStores(tt.deps, tt.images)
+		if database != tt.database || cache != tt.cache {
+			t.Errorf("%s: expected database %t and cache %t, got %t and %t", tt.name, tt.database, tt.cache, database, cache)
+		}
+	}
+}
+
+func TestContainerPort(t *testing.T) {
+	tests := []struct {
+		name  string
+		entry any
+		port  int
+		ok    bool
+	}{
+		{"container port", "80", 80, true},
+		{"host and container ports", "8080:80", 80, true},
+		{"host IP and protocol", "127.0.0.1:8080:80/tcp", 80, true},
+		{"IPv6 host IP", "[::1]:8080:80", 80, true},
+		{"protocol", "53/udp", 53, true},
+		{"range", "8000-8001:80-81", 80, true},
+		{"integer", 8080, 8080, true},
+		{"long syntax", map[string]any{"target": 80, "published": 8080}, 80, true},
+		{"long syntax with string target", map[string]any{"target": "80"}, 80, true},
+		{"long syntax without target", map[string]any{"published": 8080}, 0, false},
+		{"zero", 0, 0, false},
+		{"variable", "${PORT}", 0, false},
+		{"unsupported type", 80.5, 0, false},
+	}
+
+	for _, tt := range tests {
+		port, ok := containerPort(tt.entry)
+		if port != tt.port || ok != tt.ok {
+			t.Errorf("%s: expected port %d (ok %t), got %d (ok %t)", tt.name, tt.port, tt.ok, port, ok)
+		}
+	}
+}
+
+func TestSummarizeCompose(t *testing.T) {
+	compose := &composeFile{Services: map[string]composeService{
+		"web": {Image: "docker.io/library/nginx:1.27", Ports: []any{"8080:80"}, Expose: []any{80}},
+		"db":  {Image: "postgres:16@sha256:abc", Ports: []any{map[string]any{"target": 5432}}},
+		"app": {Ports: []any{"127.0.0.1:3000:3000/tcp"}},
+	}}
+
+	info := summarizeCompose(compose)
+	if want := []string{"app", "db", "web"}; !slices.Equal(info.Services, want) {
+		t.Errorf("expected services %v, got %v", want, info.Services)
+	}
+	if want := []string{"nginx", "postgres"}; !slices.Equal(info.Images, want) {
+		t.Errorf("expected images %v, got %v", want, info.Images)
+	}
+	if want := []int{80, 3000, 5432}; !slices.Equal(info.Ports, want) {
+		t.Errorf("expected ports %v, got %v", want, info.Ports)
+	}
+}
+
+func TestParseCompose(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  *composeInfo
+	}{
+		{
+			name: "short and long port syntax",
+			files: map[string]string{"compose.yaml": `services:
+  api:
+    build: .
+    ports:
+      - "8080:80"
+      - target: 9090
+        published: 9090
+  cache:
+    image: redis:7
+    expose:
+      - 6379
+`},
+			want: &composeInfo{Services: []string{"api", "cache"}, Images: []string{"redis"}, Ports: []int{80, 6379, 9090}},
+		},
+		{
+			name: "docker-compose.yml is checked first",
+			files: map[string]string{
+				"docker-compose.yml": "services:\n  web:\n    image: nginx\n",
+				"compose.yaml":       "services:\n  db:\n    image: postgres\n",
+			},
+			want: &composeInfo{Services: []string{"web"}, Images: []string{"nginx"}},
+		},
+		{
+			name:  "invalid YAML",
+			files: map[string]string{"docker-compose.yml": "services: [web\n"},
+		},
+		{
+			name: "no compose file",
+		},
+	}
+
+	for _, tt := range tests {
+		repo := t.TempDir()
+		for name, content := range tt.files {
+			writeFile(t, repo, name, content)
+		}
+
+		got := parseCompose(repo)
+		if tt.want == nil {
+			if got != nil {
+				t.Errorf("%s: expected no compose info, got %+v", tt.name, got)
+			}
+			continue
+		}
+		if got == nil {
+			t.Errorf("%s: expected compose info, got nil", tt.name)
+			continue
+		}
+		if !slices.Equal(got.Services, tt.want.Services) || !slices.Equal(got.Images, tt.want.Images) || !slices.Equal(got.Ports, tt.want.Ports) {
+			t.Errorf("%s: expected %+v, got %+v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestParseDockerfilePorts(t *testing.T) {
+	tests := []struct {
+		name       string
+		dockerfile string
+		ports      []int
+		port       int
+	}{
+		{"single", "FROM python:3.12-slim\nEXPOSE 8501\n", []int{8501}, 8501},
+		{"protocols", "EXPOSE 53/udp 9000/tcp\n", []int{9000}, 9000},
+		{"proxy port first", "FROM nginx\nEXPOSE 80\nexpose 3000 3000\n", []int{80, 3000}, 3000},
+		{"well-known only", "EXPOSE 443 80\n", []int{443, 80}, 443},
+		{"arg default", "ARG PORT=7000\nEXPOSE $PORT\n", []int{7000}, 7000},
+		{"env and fallback", "ENV APP_PORT 4000\nEXPOSE ${APP_PORT} \\\n  ${METRICS:-9100}\n", []int{4000, 9100}, 4000},
+		{"commented", "# EXPOSE 1234\nFROM scratch\n", nil, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := t.TempDir()
+			writeFile(t, repo, "Dockerfile", tt.dockerfile)
+
+			ports := parseDockerfilePorts(filepath.Join(repo, "Dockerfile"))
+			if fmt.Sprint(ports) != fmt.Sprint(tt.ports) {
+				t.Errorf("parseDockerfilePorts() = %v, want %v", ports, tt.ports)
+			}
+			if port := dockerfilePort(ports); port != tt.port {
+				t.Errorf("dockerfilePort(%v) = %d, want %d", ports, port, tt.port)
+			}
+		})
+	}
+}
+
+func TestAnalyzeDirectoryDockerfilePort(t *testing.T) {
+	tests := []struct {
+		name, app string
+		port      int
+	}{
+		{"replaces the framework default", "app.run()\n", 8501},
+		{"code port kept", "app.run(host=\"0.0.0.0\", port=8081)\n", 8081},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := t.TempDir()
+			writeFile(t, repo, "requirements.txt", "flask\n")
+			writeFile(t, repo, "app.py", tt.app)
+			writeFile(t, repo, "Dockerfile", "FROM python:3.12-slim\nEXPOSE 8501\n")
+
+			analysis, err := NewAnalyzer(t.TempDir(), false).analyzeDirectory(repo, repo, "")
+			if err != nil {
+				t.Fatalf("analyzeDirectory failed: %v", err)
+			}
+			if analysis.Port != tt.port || !analysis.PortDetected {
+				t.Errorf("Expected detected port %d, got %d (detected %t)", tt.port, analysis.Port, analysis.PortDetected)
+			}
+		})
+	}
+}
+
+func TestAnalyzeDirectoryPythonEntry(t *testing.T) {
+	tests := []struct {
+		name, framework     string
+		files               map[string]string
+		entry, startCommand string
+	}{
+		{
+			"flask script", "flask",
+			map[string]string{"app.py": "app = Flask(__name__)\n\nif __name__ == \"__main__\":\n    app.run(host=\"0.0.0.0\")\n"},
+			"app:app", "python3 app.py",
+		},
+		{
+			"flask wsgi", "flask",
+			map[string]string{
+				"wsgi.py":           "from myapp import create_app\n\napplication = create_app()\n",
+				"myapp/__init__.py": "def create_app():\n    return Flask(__name__)\n",
+				"tests/conftest.py": "app = Flask(__name__)\n",
+				"scripts/seed.py":   "if __name__ == '__main__':\n    seed()\n",
+			},
+			"wsgi:application", "flask --app wsgi:application run --host 0.0.0.0",
+		},
+		{
+			"flask package", "flask",
+			map[string]string{
+				"myapp/__init__.py": "app = Flask(__name__)\n",
+				"myapp/views.py":    "from myapp import app\n",
+			},
+			"myapp:app", "flask --app myapp:app run --host 0.0.0.0",
+		},
+		{
+			"flask package main", "flask",
+			map[string]string{"myapp/__main__.py": "from myapp.server import serve\n\nserve()\n"},
+			"myapp", "python3 -m myapp",
+		},
+		{
+			"fastapi module", "fastapi",
+			map[string]string{"src/api/server.py": "api = FastAPI()\n"},
+			"src.api.server:api", "uvicorn src.api.server:api --host 0.0.0.0 --port 8000",
+		},
+		{
+			"no entry", "flask",
+			map[string]string{"main.py": "from flask import Flask\n"},
+			"", "python3 main.py",
+		},
+	}
+
+	for _, tt := range tests {
+		repo := t.TempDir()
+		writeFile(t, repo, "requirements.txt", tt.framework+"\n")
+		for file, content := range tt.files {
+			writeFile(t, repo, file, content)
+		}
+
+		analysis, err := NewAnalyzer(t.TempDir(), false).analyzeDirectory(repo, repo, "")
+		if err != nil {
+			t.Fatalf("%s: analyzeDirectory failed: %v", tt.name, err)
+		}
+		if analysis.EntryPoint != tt.entry {
+			t.Errorf("%s: expected entry point %q, got %q", tt.name, tt.entry, analysis.EntryPoint)
+		}
+		if analysis.StartCommand != tt.startCommand {
+			t.Errorf("%s: expected start command %q, got %q", tt.name, tt.startCommand, analysis.StartCommand)
+		}
+	}
+}
+
+func TestAnalyzeDirectoryNode(t *testing.T) {
+	tests := []struct {
+		name                       string
+		files                      map[string]string
+		buildCommand, startCommand string
+	}{
+		{
+			"typescript with build script",
+			map[string]string{
+				"package.json":  `{"main": "dist/index.js", "scripts": {"build": "tsc", "start": "node dist/index.js"}}`,
+				"tsconfig.json": `{"compilerOptions": {"outDir": "dist"}}`,
+				"src/index.ts":  "import express from 'express'\n",
+			},
+			"npm run build", "npm start",
+		},
+		{
+			"typescript without scripts",
+			map[string]string{
+				"package.json":   `{"main": "src/server.ts"}`,
+				"tsconfig.json":  "{\n  // compiled output\n  \"compilerOptions\": {\"outDir\": \"./build\"}\n}\n",
+				"pnpm-lock.yaml": "",
+			},
+			"pnpm exec tsc", "node build/server.js",
+		},
+		{
+			"next.js with yarn",
+			map[string]string{
+				"package.json": `{"scripts": {"dev": "next dev", "build": "next build", "start": "next start"}}`,
+				"yarn.lock":    "",
+			},
+			"yarn build", "yarn start",
+		},
+		{
+			"main without scripts",
+			map[string]string{"package.json": `{"main": "server.js"}`},
+			"", "node server.js",
+		},
+	}
+
+	for _, tt := range tests {
+		repo := t.TempDir()
+		for file, content := range tt.files {
+			writeFile(t, repo, file, content)
+		}
+
+		analysis, err := NewAnalyzer(t.TempDir(), false).analyzeDirectory(repo, repo, "")
+		if err != nil {
+			t.Fatalf("%s: analyzeDirectory failed: %v", tt.name, err)
+		}
+		if analysis.BuildCommand != tt.buildCommand {
+			t.Errorf("%s: expected build command %q, got %q", tt.name, tt.buildCommand, analysis.BuildCommand)
+		}
+		if analysis.StartCommand != tt.startCommand {
+			t.Errorf("%s: expected start command %q, got %q", tt.name, tt.startCommand, analysis.StartCommand)
+		}
+	}
+}
+
+func TestAnalyzeDirectoryRuby(t *testing.T) {
+	tests := []struct {
+		name, gemfile, framework, startCommand string
+		port                                   int
+	}{
+		{"rails", "source \"https://rubygems.org\"\ngem \"rails\", \"~> 7.1\"\ngem \"pg\"\n", "rails", "bundle exec rails server -b 0.0.0.0 -p 3000", 3000},
+		{"sinatra", "source 'https://rubygems.org'\n\ngem 'sinatra'\ngem 'puma'\n", "sinatra", "bundle exec ruby app.rb -o 0.0.0.0", 4567},
+		{"rack", "source 'https://rubygems.org'\ngem 'rack'\n# gem 'rails'\n", "rack", "bundle exec rackup -o 0.0.0.0 -p 9292", 9292},
+	}
+
+	for _, tt := range tests {
+		repo := t.TempDir()
+		writeFile(t, repo, "Gemfile", tt.gemfile)
+
+		analysis, err := NewAnalyzer(t.TempDir(), false).analyzeDirectory(repo, repo, "")
+		if err != nil {
+			t.Fatalf("%s: analyzeDirectory failed: %v", tt.name, err)
+		}
+		if analysis.Framework != tt.framework || analysis.Language != "ruby" {
+			t.Errorf("%s: expected framework %s (ruby), got %s (%s)", tt.name, tt.framework, analysis.Framework, analysis.Language)
+		}
+		if analysis.Port != tt.port {
+			t.Errorf("%s: expected port %d, got %d", tt.name, tt.port, analysis.Port)
+		}
+		if analysis.StartCommand != tt.startCommand {
+			t.Errorf("%s: expected start command %q, got %q", tt.name, tt.startCommand, analysis.StartCommand)
+		}
+	}
+}
+
+func TestAnalyzeDirectorySpringBoot(t *testing.T) {
+	tests := []struct {
+		name, buildFile, content, packageManager, startCommand string
+	}{
+		{
+			"maven", "pom.xml",
+			"<project>\n  <parent>\n    <groupId>org.springframework.boot</groupId>\n    <artifactId>spring-boot-starter-parent</artifactId>\n  </parent>\n</project>\n",
+			"maven", "mvn -q -DskipTests package && java -jar $(ls target/*.jar | head -n 1)",
+		},
+		{
+			"gradle", "build.gradle",
+			"plugins {\n  id 'org.springframework.boot' version '3.3.0'\n}\ndependencies {\n  implementation 'org.springframework.boot:spring-boot-starter-web'\n}\n",
+			"gradle", "gradle bootJar -x test && java -jar $(ls build/libs/*.jar | grep -v -- -plain.jar | head -n 1)",
+		},
+	}
+
+	for _, tt := range tests {
+		repo := t.TempDir()
+		writeFile(t, repo, tt.buildFile, tt.content)
+
+		analysis, err := NewAnalyzer(t.TempDir(), false).analyzeDirectory(repo, repo, "")
+		if err != nil {
+			t.Fatalf("%s: analyzeDirectory failed: %v", tt.name, err)
+		}
+		if analysis.Framework != "spring-boot" || analysis.Language != "java" {
+			t.Errorf("%s: expected spring-boot (java), got %s (%s)", tt.name, analysis.Framework, analysis.Language)
+		}
+		if analysis.PackageManager != tt.packageManager {
+			t.Errorf("%s: expected package manager %s, got %s", tt.name, tt.packageManager, analysis.PackageManager)
+		}
+		if analysis.Port != 8080 {
+			t.Errorf("%s: expected port 8080, got %d", tt.name, analysis.Port)
+		}
+		if analysis.StartCommand != tt.startCommand {
+			t.Errorf("%s: expected start command %q, got %q", tt.name, tt.startCommand, analysis.StartCommand)
+		}
+	}
+}
+
+func TestValidateSourceZip(t *testing.T) {
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "app.zip")
+	f, err := os.Create(valid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	if _, err := zw.Create("app.py"); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	_ = f.Close()
+
+	writeFile(t, dir, "corrupt.zip", "not a zip archive")
+
+	tests := []struct {
+		source  string
+		wantErr bool
+	}{
+		{valid, false},
+		{filepath.Join(dir, "corrupt.zip"), true},
+		{filepath.Join(dir, "missing.zip"), true},
+		{filepath.Join(dir, "app.tar.gz"), true},
+	}
+	for _, tt := range tests {
+		if err := ValidateSource(context.Background(), tt.source); (err != nil) != tt.wantErr {
+			t.Errorf("ValidateSource(%s) error = %v, wantErr %v", filepath.Base(tt.source), err, tt.wantErr)
+		}
+	}
+}
+
+func TestParseGitURL(t *testing.T) {
+	tests := []struct {
+		source       string
+		wantProtocol string
+		wantErr      bool
+	}{
+		{"https://github.com/org/repo", "https", false},
+		{"http://git.internal/org/repo.git", "http", false},
+		{"git@github.com:org/repo.git", "ssh", false},
+		{"ssh://git@github.com/org/repo.git", "ssh", false},
+		{"git://git.internal/org/repo.git", "git", false},
+		{"/no/such/directory", "", true},
+		{"ftp://example.com/repo.git", "", true},
+	}
+	for _, tt := range tests {
+		endpoint, err := parseGitURL(tt.source)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseGitURL(%s) error = %v, wantErr %v", tt.source, err, tt.wantErr)
+			continue
+		}
+		if err == nil && endpoint.Protocol != tt.wantProtocol {
+			t.Errorf("parseGitURL(%s) protocol = %s, want %s", tt.source, endpoint.Protocol, tt.wantProtocol)
+		}
+	}
+}
+
+func TestValidateSourceSSH(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// The SSH URL is checked with ls-remote, which fails on the closed port, not rejected
+	err := ValidateSource(ctx, "ssh://git@127.0.0.1:1/org/repo.git")
+	if err == nil {
+		t.Fatal("Expected an unreachable repository error")
+	}
+	if strings.Contains(err.Error(), "unsupported repository source") {
+		t.Errorf("Expected SSH URLs to be supported, got %v", err)
+	}
+}
+
+func TestSetCloneProxySCP(t *testing.T) {
+	// scp-like URLs are not URLs: the proxy lookup must not parse them
+	repoURL := "git@github.com:org/repo.git"
+	endpoint, err := parseGitURL(repoURL)
+	if err != nil {
+		t.Fatalf("parseGitURL(%q) error = %v", repoURL, err)
+	}
+
+	cloneOpts := &git.CloneOptions{URL: repoURL}
+	if err := setCloneProxy(cloneOpts, endpoint); err != nil {
+		t.Fatalf("setCloneProxy() error = %v", err)
+	}
+	if cloneOpts.ProxyOptions.URL != "" {
+		t.Errorf("Expected a direct SSH connection, got proxy %s", cloneOpts.ProxyOptions.URL)
+	}
+}
+
+func TestCheckRemoteReachableProxy(t *testing.T) {
+	requests := make(chan string, 1)
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case requests <- r.URL.String():
+		default:
+		}
+		http.Error(w, "no upstream", http.StatusBadGateway)
+	}))
+	defer proxy.Close()
+
+	// Registered before t.Setenv, so it reloads the restored environment variables
+	t.Cleanup(func() { _ = network.SetProxy("") })
+	for _, env := range []string{"HTTP_PROXY", "HTTPS_PROXY", "http_proxy", "https_proxy", "NO_PROXY", "no_proxy"} {
+		t.Setenv(env, "")
+	}
+	if err := network.SetProxy(proxy.URL); err != nil {
+		t.Fatalf("SetProxy() error = %v", err)
+	}
+	// SetProxy exported the proxy to the environment: clear it, for only the proxy options of
+	// the ls-remote to route it
+	for _, env := range []string{"HTTP_PROXY", "HTTPS_PROXY", "http_proxy", "https_proxy"} {
+		t.Setenv(env, "")
+	}
+
+	proxied := map[string]string{
+		"https://github.com/org/repo.git":   proxy.URL,
+		"http://git.example.com/org/repo":   proxy.URL,
+		"git@github.com:org/repo.git":       "",
+		"ssh://git@github.com/org/repo.git": "",
+	}
+	for repoURL, want := range proxied {
+		endpoint, err := parseGitURL(repoURL)
+		if err != nil {
+			t.Fatalf("parseGitURL(%q) error = %v", repoURL, err)
+		}
+		proxyOpts, err := gitProxyOptions(endpoint)
+		if err != nil {
+			t.Fatalf("gitProxyOptions(%q) error = %v", repoURL, err)
+		}
+		if proxyOpts.URL != want {
+			t.Errorf("gitProxyOptions(%q) = %q, want %q", repoURL, proxyOpts.URL, want)
+		}
+	}
+
+	// The ls-remote goes through the proxy, which answers instead of the unreachable host
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	repoURL := "http://git.example.com/org/repo.git"
+	endpoint, err := parseGitURL(repoURL)
+	if err != nil {
+		t.Fatalf("parseGitURL(%q) error = %v", repoURL, err)
+	}
+	if err := checkRemoteReachable(ctx, repoURL, endpoint); err == nil {
+		t.Fatal("Expected the proxy error to be reported")
+	}
+	select {
+	case got := <-requests:
+		if !strings.HasPrefix(got, repoURL+"/info/refs") {
+			t.Errorf("Expected the proxy to receive the ls-remote of %s, got %s", repoURL, got)
+		}
+	default:
+		t.Error("Expected the ls-remote to go through the proxy")
+	}
+}
+
+func TestIsRetryableCloneError(t *testing.T) {
+	httpErr := func(status int) error {
+		return plumbing.NewUnexpectedError(&githttp.Err{Response: &http.Response{StatusCode: status}})
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"timeout", &net.OpError{Op: "dial", Net: "tcp", Err: os.ErrDeadlineExceeded}, true},
+		{"connection refused", fmt.Errorf("dial: %w", syscall.ECONNREFUSED), true},
+		{"connection reset", &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}, true},
+		{"truncated transfer", fmt.Errorf("reading pack: %w", io.ErrUnexpectedEOF), true},
+		{"temporary DNS failure", &net.DNSError{Err: "server misbehaving", Name: "github.com", IsTemporary: true}, true},
+		{"HTTP 503", httpErr(http.StatusServiceUnavailable), true},
+		{"HTTP 400", httpErr(http.StatusBadRequest), false},
+		{"unknown host", &net.DNSError{Err: "no such host", Name: "gihtub.com", IsNotFound: true}, false},
+		{"repository not found", transport.ErrRepositoryNotFound, false},
+		{"authentication required", transport.ErrAuthenticationRequired, false},
+		{"invalid auth method", transport.ErrInvalidAuthMethod, false},
+		{"invalid URL", &url.Error{Op: "parse", URL: "https://exa mple.com", Err: errors.New("invalid character \" \" in host name")}, false},
+		{"request timeout", &url.Error{Op: "Get", URL: "https://github.com/org/repo", Err: &net.OpError{Op: "dial", Net: "tcp", Err: os.ErrDeadlineExceeded}}, true},
+		{"missing ref", plumbing.ErrReferenceNotFound, false},
+		{"disk full", &os.PathError{Op: "write", Path: "/tmp/repo/.git/objects", Err: syscall.ENOSPC}, false},
+		{"canceled", context.Canceled, false},
+	}
+	for _, tt := range tests {
+		if got := isRetryableCloneError(tt.err); got != tt.want {
+			t.Errorf("isRetryableCloneError(%s) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestExtractZipLimits(t *testing.T) {
+	// 4 MB of zeros compress to a few KB
+	bomb := filepath.Join(t.TempDir(), "bomb.zip")
+	f, err := os.Create(bomb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	for _, name := range []string{"app.py", "data/a.bin", "data/b.bin"} {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write(make([]byte, 2<<20)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	_ = f.Close()
+
+	tests := []struct {
+		name    string
+		limits  ZipLimits
+		wantErr string
+	}{
+		{"within limits", ZipLimits{MaxSize: 8 << 20, MaxFileSize: 4 << 20, MaxFiles: 3}, ""},
+		{"total size", ZipLimits{MaxSize: 5 << 20}, "zip_max_size_mb"},
+		{"file size", ZipLimits{MaxFileSize: 1 << 20}, "zip_max_file_size_mb"},
+		{"file count", ZipLimits{MaxFiles: 2}, "zip_max_files"},
+	}
+
+	for _, tt := range tests {
+		a := NewAnalyzer(t.TempDir(), false)
+		a.SetZipLimits(tt.limits)
+
+		_, err := a.extractZip(bomb)
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("%s: extractZip failed: %v", tt.name, err)
+			}
+			continue
+		}
+		if !errors.Is(err, ErrZipLimitExceeded) || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: expected %s limit error, got %v", tt.name, tt.wantErr, err)
+		}
+		if _, statErr := os.Stat(filepath.Join(a.workDir, "repos", "bomb")); !os.IsNotExist(statErr) {
+			t.Errorf("%s: expected the partially extracted directory to be removed", tt.name)
+		}
+	}
+}
+
+func TestAnalyzeLocalDirectory(t *testing.T) {
+	src := t.TempDir()
+	writeFile(t, src, "requirements.txt", "flask\n")
+	writeFile(t, src, "app.py", "from flask import Flask\n")
+	writeFile(t, src, "node_modules/dep/index.js", "")
+	writeFile(t, src, "build/schema.py", "")
+	writeFile(t, src, "dist/static/app.css", "")
+
+	a := NewAnalyzer(t.TempDir(), false)
+	analysis, err := a.Analyze(src)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	if analysis.Framework != "flask" {
+		t.Errorf("Expected flask, got %s", analysis.Framework)
+	}
+	if analysis.RepoURL != src || analysis.RepoPath == src || analysis.CommitSHA != "" {
+		t.Errorf("Expected a copy of %s without commit, got RepoURL=%s RepoPath=%s CommitSHA=%s",
+			src, analysis.RepoURL, analysis.RepoPath, analysis.CommitSHA)
+	}
+	if !analysis.LocalSource {
+		t.Error("Expected a local source")
+	}
+	if _, err := os.Stat(filepath.Join(analysis.RepoPath, "node_modules")); !os.IsNotExist(err) {
+		t.Error("Expected dependency caches not to be copied")
+	}
+	for _, name := range []string{"build/schema.py", "dist/static/app.css"} {
+		if _, err := os.Stat(filepath.Join(analysis.RepoPath, name)); err != nil {
+			t.Errorf("Expected %s to be copied: %v", name, err)
+		}
+	}
+}
+
+func BenchmarkFileLookups(b *testing.B) {
+	repo := b.TempDir()
+	writeDeepTree(b, repo, 4, 4)
+
+	a := NewAnalyzer(b.TempDir(), false)
+	names := append([]string{"manage.py", "setup.py", "yarn.lock", "pnpm-lock.yaml"}, appManifests...)
+
+	b.Run("recursive", func(b *testing.B) {
+		for b.Loop() {
+			for _, name := range names {
+				a.findFileRecursiveWithDepth(repo, name, 0)
+			}
+		}
+	})
+	b.Run("index", func(b *testing.B) {
+		for b.Loop() {
+			index := a.buildFileIndex(repo)
+			for _, name := range names {
+				index.find(name)
+			}
+		}
+	})
+}
+
+func BenchmarkAnalyzeDirectoryDeepTree(b *testing.B) {
+	repo := b.TempDir()
+	writeDeepTree(b, repo, 4, 4)
+
+	a := NewAnalyzer(b.TempDir(), false)
+	for b.Loop() {
+		if _, err := a.analyzeDirectory(repo, repo, ""); err != nil {
+			b.Fatalf("analyzeDirectory failed: %v", err)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package analyzer
+
+import "regexp"
+
+// localhostBindPatterns match listen calls restricted to the loopback interface: Flask/uvicorn
+// run(host="127.0.0.1"), Express listen(port, "localhost") and Go ListenAndServe("127.0.0.1:8080")
+// or r.Run("localhost:8080")
+var localhostBindPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`\.run\([^)]*\bhost\s*=\s*["'](?:127\.0\.0\.1|localhost)["']`),
+	regexp.MustCompile(`\.listen\(\s*[\w.]+\s*,\s*["'` + "`" + `](?:127\.0\.0\.1|localhost)["'` + "`" + `]`),
+	regexp.MustCompile(`(?:ListenAndServe(?:TLS)?|\.Run|\.Start)\(\s*["'` + "`" + `](?:127\.0\.0\.1|localhost):\d*["'` + "`" + `]`),
+}
+
+// detectLocalhostBinding reports whether the code under appPath listens on localhost only:
+// such an app cannot be reached from the load balancer or the internet once deployed
+func (a *Analyzer) detectLocalhostBinding(appPath string) bool {
+	found := false
+	a.walkSourceFiles(appPath, 0, func(path, content string) {
+		for _, pattern := range localhostBindPatterns {
+			if !found && pattern.MatchString(content) {
+				found = true
+			}
+		}
+	})
+	return found
+}
@@ -0,0 +1,118 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// composeFileNames lists the docker-compose file names checked, in order
+var composeFileNames = []string{"docker-compose.yml", "docker-compose.yaml", "compose.yml", "compose.yaml"}
+
+// composeFile is the subset of docker-compose.yml used by the analyzer
+type composeFile struct {
+	Services map[string]composeService `yaml:"services"`
+}
+
+// composeService is a single docker-compose service
+type composeService struct {
+	Image  string `yaml:"image"`
+	Ports  []any  `yaml:"ports"`  // Short ("8080:80") or long ({target: 80}) syntax
+	Expose []any  `yaml:"expose"` // Container ports exposed to other services
+}
+
+// composeInfo summarizes the services of a docker-compose file
+type composeInfo struct {
+	Services []string // Service names, sorted
+	Images   []string // Image names without registry or tag, sorted
+	Ports    []int    // Container ports, sorted and unique
+}
+
+// parseCompose parses the repository's docker-compose file.
+// Returns nil when there is no compose file or it cannot be parsed.
+func parseCompose(repoPath string) *composeInfo {
+	for _, name := range composeFileNames {
+		// #nosec G304 -- path is inside the analyzed repository
+		data, err := os.ReadFile(filepath.Join(repoPath, name))
+		if err != nil {
+			continue
+		}
+
+		var compose composeFile
+		if err := yaml.Unmarshal(data, &compose); err != nil {
+			return nil
+		}
+
+		return summarizeCompose(&compose)
+	}
+
+	return nil
+}
+
+// summarizeCompose extracts service names, images and container ports
+func summarizeCompose(compose *composeFile) *composeInfo {
+	info := &composeInfo{}
+	seenPorts := map[int]bool{}
+
+	for name, service := range compose.Services {
+		info.Services = append(info.Services, name)
+
+		if image := imageName(service.Image); image != "" {
+			info.Images = append(info.Images, image)
+		}
+
+		for _, entry := range append(service.Ports, service.Expose...) {
+			if port, ok := containerPort(entry); ok && !seenPorts[port] {
+				seenPorts[port] = true
+				info.Ports = append(info.Ports, port)
+			}
+		}
+	}
+
+	sort.Strings(info.Services)
+	sort.Strings(info.Images)
+	sort.Ints(info.Ports)
+
+	return info
+}
+
+// imageName strips the registry, namespace and tag from an image reference
+// (e.g. "docker.io/library/postgres:16" → "postgres")
+func imageName(image string) string {
+	image = strings.ToLower(image)
+	image = image[strings.LastIndex(image, "/")+1:]
+	image, _, _ = strings.Cut(image, "@")
+	image, _, _ = strings.Cut(image, ":")
+	return image
+}
+
+// containerPort extracts the container port from a ports/expose entry.
+// Supports "80", "8080:80", "127.0.0.1:8080:80", "80/tcp", "8000-8001:80-81"
+// (first port of a range), plain integers and the long syntax {target: 80}.
+func containerPort(entry any) (int, bool) {
+	var value string
+	switch v := entry.(type) {
+	case int:
+		return v, v > 0
+	case string:
+		value = v
+	case map[string]any:
+		return containerPort(v["target"])
+	default:
+		return 0, false
+	}
+
+	value, _, _ = strings.Cut(value, "/")
+	value = value[strings.LastIndex(value, ":")+1:]
+	value, _, _ = strings.Cut(value, "-")
+
+	port, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || port <= 0 {
+		return 0, false
+	}
+	return port, true
+}
@@ -0,0 +1,49 @@
+package analyzer
+
+import "strings"
+
+// databaseMarkers are substrings of dependency names and image names that indicate a database
+// server. SQLite is left out: it is embedded in the application and needs no RDS instance.
+var databaseMarkers = []string{
+	"postgres", "psycopg", "asyncpg", "mysql", "mariadb", "mongo", "cockroach",
+}
+
+// cacheMarkers are substrings of dependency names and image names that indicate a cache
+var cacheMarkers = []string{"redis", "memcache", "valkey"}
+
+// nodePostgresDriver is the node-postgres package, too short for substring matching
+const nodePostgresDriver = "pg"
+
+// detectDataStores reports whether the application needs a database or a cache,
+// based on its dependencies and the images of its docker-compose services
+func detectDataStores(deps, composeImages []string) (requiresDatabase, requiresCache bool) {
+	names := make([]string, 0, len(deps)+len(composeImages))
+	for _, dep := range deps {
+		dep = strings.ToLower(dep)
+		if dep == nodePostgresDriver {
+			requiresDatabase = true
+		}
+		names = append(names, dep)
+	}
+	names = append(names, composeImages...)
+
+	for _, name := range names {
+		if containsAny(name, databaseMarkers) {
+			requiresDatabase = true
+		}
+		if containsAny(name, cacheMarkers) {
+			requiresCache = true
+		}
+	}
+
+	return requiresDatabase, requiresCache
+}
+
+func containsAny(s string, markers []string) bool {
+	for _, marker := range markers {
+		if strings.Contains(s, marker) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,121 @@
+package analyzer
+
+import (
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// maxWellKnownPort is the last well-known port (80 for a bundled proxy, 22 for SSH, ...): an
+// application exposing several ports listens on the first one above it
+const maxWellKnownPort = 1023
+
+// dockerfileVariable matches a variable reference of an instruction: $PORT, ${PORT} or
+// ${PORT:-8080}
+var dockerfileVariable = regexp.MustCompile(`\$\{?(\w+)(?::-([^}]*))?\}?`)
+
+// parseDockerfilePorts returns the TCP ports of the EXPOSE instructions of a Dockerfile, in
+// order and unique, nil when it cannot be read. Variables are resolved from the ARG and ENV
+// defaults declared before, or their own default (${PORT:-8080}).
+func parseDockerfilePorts(path string) []int {
+	// #nosec G304 -- path is inside the analyzed repository
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	variables := map[string]string{}
+	seen := map[int]bool{}
+	var ports []int
+	for _, line := range dockerfileInstructions(string(data)) {
+		instruction, args, _ := strings.Cut(line, " ")
+		args = strings.TrimSpace(args)
+
+		switch strings.ToUpper(instruction) {
+		case "ARG", "ENV":
+			for name, value := range dockerfileAssignments(args) {
+				variables[name] = expandDockerfileVariables(value, variables)
+			}
+		case "EXPOSE":
+			for _, field := range strings.Fields(expandDockerfileVariables(args, variables)) {
+				number, protocol, _ := strings.Cut(field, "/")
+				if protocol != "" && !strings.EqualFold(protocol, "tcp") {
+					continue
+				}
+				if port, err := strconv.Atoi(number); err == nil && port > 0 && port <= 65535 && !seen[port] {
+					seen[port] = true
+					ports = append(ports, port)
+				}
+			}
+		}
+	}
+	return ports
+}
+
+// dockerfileInstructions returns the instructions of a Dockerfile, continuation lines joined and
+// comments dropped
+func dockerfileInstructions(dockerfile string) []string {
+	var instructions []string
+	var current strings.Builder
+	for _, line := range strings.Split(dockerfile, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if continued, ok := strings.CutSuffix(line, `\`); ok {
+			current.WriteString(continued + " ")
+			continue
+		}
+		current.WriteString(line)
+		instructions = append(instructions, current.String())
+		current.Reset()
+	}
+	if current.Len() > 0 {
+		instructions = append(instructions, current.String())
+	}
+	return instructions
+}
+
+// dockerfileAssignments parses the arguments of ARG and ENV: NAME=value pairs, or the legacy
+// "ENV NAME value" form (ARG without default is skipped)
+func dockerfileAssignments(args string) map[string]string {
+	assignments := map[string]string{}
+	fields := strings.Fields(args)
+	if len(fields) > 1 && !strings.Contains(fields[0], "=") {
+		assignments[fields[0]] = strings.Join(fields[1:], " ")
+		return assignments
+	}
+	for _, field := range fields {
+		if name, value, ok := strings.Cut(field, "="); ok {
+			assignments[name] = strings.Trim(value, `"'`)
+		}
+	}
+	return assignments
+}
+
+// expandDockerfileVariables replaces the variable references of value, by their default when
+// they are not declared (empty without default)
+func expandDockerfileVariables(value string, variables map[string]string) string {
+	return dockerfileVariable.ReplaceAllStringFunc(value, func(reference string) string {
+		match := dockerfileVariable.FindStringSubmatch(reference)
+		if resolved, ok := variables[match[1]]; ok && resolved != "" {
+			return resolved
+		}
+		return match[2]
+	})
+}
+
+// dockerfilePort returns the port the application of a Dockerfile listens on among its exposed
+// ports: the first one that is not well-known, the first one otherwise
+func dockerfilePort(ports []int) int {
+	for _, port := range ports {
+		if port > maxWellKnownPort {
+			return port
+		}
+	}
+	if len(ports) > 0 {
+		return ports[0]
+	}
+	return 0
+}
@@ -0,0 +1,97 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// indexWorkers bounds the directories read concurrently while building a file index
+const indexWorkers = 8
+
+// fileIndex lists the files of a directory tree, walked once, so that detectors look
+// files up instead of walking the tree again for each manifest or lock file
+type fileIndex struct {
+	root     string
+	maxDepth int
+	paths    map[string][]string // File name -> paths, in findFileRecursive search order
+}
+
+// indexedDir is a directory read while building a file index
+type indexedDir struct {
+	path     string
+	names    []string
+	children []*indexedDir
+}
+
+// buildFileIndex walks root up to the configured depth, skipping ignored directories.
+// Directories are read concurrently; the index keeps the order of a sequential walk.
+func (a *Analyzer) buildFileIndex(root string) *fileIndex {
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, indexWorkers)
+
+	var read func(dir *indexedDir, depth int)
+	read = func(dir *indexedDir, depth int) {
+		defer wg.Done()
+
+		sem <- struct{}{}
+		entries, err := os.ReadDir(dir.path)
+		<-sem
+		if err != nil {
+			return
+		}
+
+		for _, entry := range entries {
+			path := filepath.Join(dir.path, entry.Name())
+			// Like fileExists, a dangling symlink does not count
+			if entry.Type()&os.ModeSymlink != 0 && !fileExists(path) {
+				continue
+			}
+			dir.names = append(dir.names, entry.Name())
+
+			if entry.IsDir() && !a.ignoreDirs[entry.Name()] && depth < a.maxDepth {
+				child := &indexedDir{path: path}
+				dir.children = append(dir.children, child)
+				wg.Add(1)
+				go read(child, depth+1)
+			}
+		}
+	}
+
+	rootDir := &indexedDir{path: root}
+	wg.Add(1)
+	read(rootDir, 0)
+	wg.Wait()
+
+	index := &fileIndex{root: root, maxDepth: a.maxDepth, paths: make(map[string][]string)}
+	index.add(rootDir)
+	return index
+}
+
+// add records the files of dir and its subdirectories level by level, as findFileRecursive
+// searches breadth-first
+func (idx *fileIndex) add(dir *indexedDir) {
+	for level := []*indexedDir{dir}; len(level) > 0; {
+		var next []*indexedDir
+		for _, d := range level {
+			for _, name := range d.names {
+				idx.paths[name] = append(idx.paths[name], filepath.Join(d.path, name))
+			}
+			next = append(next, d.children...)
+		}
+		level = next
+	}
+}
+
+// covers reports whether the index answers searches from dir with the given depth
+func (idx *fileIndex) covers(dir string, maxDepth int) bool {
+	return idx != nil && idx.root == dir && idx.maxDepth == maxDepth
+}
+
+// find returns the path findFileRecursive would return for filename
+func (idx *fileIndex) find(filename string) (string, bool) {
+	if paths := idx.paths[filename]; len(paths) > 0 {
+		return paths[0], true
+	}
+	return "", false
+}
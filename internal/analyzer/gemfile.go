@@ -0,0 +1,40 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+)
+
+// gemPattern matches gem declarations in a Gemfile (gem "sinatra", '~> 4.0')
+var gemPattern = regexp.MustCompile(`(?m)^\s*gem\s+["']([\w.-]+)["']`)
+
+// parseGemfile returns the gems declared in a Gemfile (nil if it cannot be read)
+func parseGemfile(gemfilePath string) []string {
+	content, err := os.ReadFile(gemfilePath)
+	if err != nil {
+		return nil
+	}
+
+	var gems []string
+	for _, match := range gemPattern.FindAllStringSubmatch(string(content), -1) {
+		gems = append(gems, match[1])
+	}
+	return gems
+}
+
+// detectRubyFramework distinguishes Rails and Sinatra apps from plain Rack apps
+// based on the gems of the Gemfile in appDir
+func detectRubyFramework(appDir string) string {
+	gems := parseGemfile(filepath.Join(appDir, "Gemfile"))
+
+	switch {
+	case slices.Contains(gems, "rails") || slices.Contains(gems, "railties"):
+		return "rails"
+	case slices.Contains(gems, "sinatra"):
+		return "sinatra"
+	default:
+		return "rack"
+	}
+}
@@ -1,61 +1,175 @@
 package analyzer
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
-	"strings"
+	"syscall"
+	"time"
 
+	"github.com/Smana/scai/internal/console"
+	"github.com/Smana/scai/internal/network"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
 )
 
-// CloneRepository clones a Git repository to the specified destination and returns the commit SHA
-func CloneRepository(repoURL, destDir string) (string, error) {
+const (
+	// cloneMaxAttempts is the number of times a clone is attempted before giving up
+	cloneMaxAttempts = 3
+
+	// cloneInitialBackoff is the delay before the first retry (doubled on each attempt)
+	cloneInitialBackoff = 2 * time.Second
+)
+
+// CloneRepository clones a Git repository to the specified destination and returns the commit SHA.
+// Transient network failures are retried with exponential backoff; other errors
+// (authentication, not found, invalid ref...) fail immediately.
+func CloneRepository(ctx context.Context, repoURL, destDir string, verbose bool) (string, error) {
 	// Validate URL
-	if !strings.HasPrefix(repoURL, "https://") && !strings.HasPrefix(repoURL, "http://") {
-		return "", fmt.Errorf("invalid repository URL: must start with https:// or http://")
+	endpoint, err := parseGitURL(repoURL)
+	if err != nil {
+		return "", err
+	}
+
+	// Clone options
+	cloneOpts := &git.CloneOptions{
+		URL:   repoURL,
+		Depth: 1, // Shallow clone - we only need the latest commit
+	}
+	if err := setCloneProxy(cloneOpts, endpoint); err != nil {
+		return "", err
 	}
 
+	// Stream the remote's sideband progress (counting/compressing/receiving objects)
+	// so large clones don't look frozen
+	if verbose {
+		cloneOpts.Progress = console.Stdout
+	}
+
+	var repo *git.Repository
+	backoff := cloneInitialBackoff
+
+	for attempt := 1; attempt <= cloneMaxAttempts; attempt++ {
+		// Start every attempt from a clean destination (removes partial clones)
+		if err := prepareCloneDir(destDir); err != nil {
+			return "", err
+		}
+
+		repo, err = git.PlainCloneContext(ctx, destDir, false, cloneOpts)
+		if err == nil {
+			break
+		}
+
+		if !isRetryableCloneError(err) || attempt == cloneMaxAttempts {
+			if verbose {
+				fmt.Fprintf(console.Stdout, "   Clone failed after %d attempt(s): %v\n", attempt, err)
+			}
+			return "", fmt.Errorf("failed to clone repository after %d attempt(s): %w", attempt, err)
+		}
+
+		if verbose {
+			fmt.Fprintf(console.Stdout, "   Clone attempt %d/%d failed: %v (retrying in %v)\n", attempt, cloneMaxAttempts, err, backoff)
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", fmt.Errorf("clone canceled: %w", ctx.Err())
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+
+	// Get commit SHA
+	ref, err := repo.Head()
+	if err != nil {
+		return "", fmt.Errorf("failed to get HEAD: %w", err)
+	}
+
+	commitSHA := ref.Hash().String()
+
+	return commitSHA, nil
+}
+
+// prepareCloneDir removes any existing (possibly partial) clone and recreates the directory
+func prepareCloneDir(destDir string) error {
 	// Check if destination already exists
 	if _, err := os.Stat(destDir); err == nil {
 		// Directory exists, remove it to allow fresh clone
 		if err := os.RemoveAll(destDir); err != nil {
-			return "", fmt.Errorf("failed to remove existing directory: %w", err)
+			return fmt.Errorf("failed to remove existing directory: %w", err)
 		}
 	}
 
 	// Create destination directory
 	if err := os.MkdirAll(destDir, 0o755); err != nil {
-		return "", fmt.Errorf("failed to create destination directory: %w", err)
+		return fmt.Errorf("failed to create destination directory: %w", err)
 	}
 
-	// Clone options
-	cloneOpts := &git.CloneOptions{
-		URL:      repoURL,
-		Progress: nil, // Can add progress output here if needed
-		Depth:    1,   // Shallow clone - we only need the latest commit
+	return nil
+}
+
+// isRetryableCloneError reports whether a clone error is transient: network errors (timeouts,
+// connection refused or reset, truncated transfers) and server errors (HTTP 5xx). Any other
+// error, such as an invalid URL, a missing ref, authentication or a full disk, is permanent.
+func isRetryableCloneError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
 	}
 
-	// Clone the repository
-	repo, err := git.PlainClone(destDir, false, cloneOpts)
-	if err != nil {
-		return "", fmt.Errorf("failed to clone repository: %w", err)
+	// Unexpected client errors of go-git do not unwrap: look at the error they carry
+	var unexpected *plumbing.UnexpectedError
+	if errors.As(err, &unexpected) {
+		return isRetryableCloneError(unexpected.Err)
+	}
+	var permanent *plumbing.PermanentError
+	if errors.As(err, &permanent) {
+		return false
 	}
 
-	// Get commit SHA
-	ref, err := repo.Head()
-	if err != nil {
-		return "", fmt.Errorf("failed to get HEAD: %w", err)
+	// Request errors carry the network error, or a URL parse error
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return isRetryableCloneError(urlErr.Err)
 	}
 
-	commitSHA := ref.Hash().String()
+	var httpErr *githttp.Err
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode() >= http.StatusInternalServerError
+	}
 
-	return commitSHA, nil
+	// Hosts that do not resolve will not resolve on retry
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return !dnsErr.IsNotFound
+	}
+
+	// Network operations (dial, read, write) and timeouts; system errors such as a full disk
+	// also implement net.Error, hence the timeout check
+	var opErr *net.OpError
+	var netErr net.Error
+	return errors.As(err, &opErr) ||
+		(errors.As(err, &netErr) && netErr.Timeout()) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
 }
 
 // CloneRepositoryWithBranch clones a specific branch of a Git repository
 func CloneRepositoryWithBranch(repoURL, branch, destDir string) error {
+	endpoint, err := parseGitURL(repoURL)
+	if err != nil {
+		return err
+	}
+
 	// Check if destination already exists
 	if _, err := os.Stat(destDir); err == nil {
 		// Directory exists, remove it to allow fresh clone
@@ -77,9 +191,12 @@ func CloneRepositoryWithBranch(repoURL, branch, destDir string) error {
 		ReferenceName: plumbing.NewBranchReferenceName(branch),
 		SingleBranch:  true,
 	}
+	if err := setCloneProxy(cloneOpts, endpoint); err != nil {
+		return err
+	}
 
 	// Clone the repository
-	_, err := git.PlainClone(destDir, false, cloneOpts)
+	_, err = git.PlainClone(destDir, false, cloneOpts)
 	if err != nil {
 		return fmt.Errorf("failed to clone repository branch '%s': %w", branch, err)
 	}
@@ -124,3 +241,28 @@ func IsGitRepository(path string) bool {
 	}
 	return info.IsDir()
 }
+
+// setCloneProxy clones through the proxy of the outbound connections (network.proxy or the
+// HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables)
+func setCloneProxy(cloneOpts *git.CloneOptions, endpoint *transport.Endpoint) error {
+	proxyOpts, err := gitProxyOptions(endpoint)
+	if err != nil {
+		return err
+	}
+	cloneOpts.ProxyOptions = proxyOpts
+	return nil
+}
+
+// gitProxyOptions returns the proxy of the connections to a Git remote. Only http(s) remotes
+// go through the HTTP proxy: SSH and git:// connections are direct, and scp-like URLs
+// (git@host:org/repo.git) are not URLs the proxy lookup could parse.
+func gitProxyOptions(endpoint *transport.Endpoint) (transport.ProxyOptions, error) {
+	if endpoint.Protocol != "http" && endpoint.Protocol != "https" {
+		return transport.ProxyOptions{}, nil
+	}
+	proxyURL, err := network.ProxyURL(endpoint.String())
+	if err != nil {
+		return transport.ProxyOptions{}, fmt.Errorf("invalid repository URL: %w", err)
+	}
+	return transport.ProxyOptions{URL: proxyURL}, nil
+}
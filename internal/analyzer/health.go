@@ -0,0 +1,88 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// defaultHealthCheckPath is used when no health endpoint is found in the code
+const defaultHealthCheckPath = "/"
+
+// healthRouteNames are the last path segments of common health endpoints, in order of preference
+var healthRouteNames = []string{
+	"health", "healthz", "healthcheck", "health-check", "health_check", "_health",
+	"livez", "readyz", "ping", "up", "status",
+}
+
+// routeSourceExts are the source files scanned for route definitions
+var routeSourceExts = map[string]bool{
+	".py": true, ".js": true, ".mjs": true, ".ts": true, ".go": true, ".rb": true,
+}
+
+// routePatterns match the path of route definitions: Flask/FastAPI decorators
+// (@app.route("/health"), @app.get(...)), Express app.get(...) and Go HandleFunc(...)/r.GET(...)
+// require a leading slash; Django path("health/", ...) and Rails get "up" do not
+var routePatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?:\.(?:route|get|GET|Get|all|Handle|HandleFunc)|\bHandleFunc)\(\s*["'` + "`" + `](?:[A-Z]+ )?(/[\w/.-]*)["'` + "`" + `]`),
+	regexp.MustCompile(`\b(?:path|re_path)\(\s*r?["']\^?([\w/.-]*?)\$?["']`),
+	regexp.MustCompile(`^\s*get\s+["'](/?[\w/.-]+)["']`),
+}
+
+// detectHealthCheckPath scans route definitions under appPath for a health endpoint
+// and returns its path, or "/" when none is found
+func (a *Analyzer) detectHealthCheckPath(appPath string) string {
+	best := -1
+	bestPath := ""
+
+	a.walkSourceFiles(appPath, 0, func(path, content string) {
+		for _, line := range strings.Split(content, "\n") {
+			for _, pattern := range routePatterns {
+				for _, match := range pattern.FindAllStringSubmatch(line, -1) {
+					path := "/" + strings.Trim(match[1], "/")
+					segment := path[strings.LastIndex(path, "/")+1:]
+					for rank, name := range healthRouteNames {
+						if segment == name && (best == -1 || rank < best) {
+							best, bestPath = rank, path
+						}
+					}
+				}
+			}
+		}
+	})
+
+	if bestPath == "" {
+		return defaultHealthCheckPath
+	}
+	return bestPath
+}
+
+// walkSourceFiles calls fn with the path and content of each route source file under dir
+// (up to the configured depth, skipping ignored directories)
+func (a *Analyzer) walkSourceFiles(dir string, depth int, fn func(path, content string)) {
+	if depth > a.maxDepth {
+		return
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		if entry.IsDir() {
+			if !a.ignoreDirs[entry.Name()] {
+				a.walkSourceFiles(path, depth+1, fn)
+			}
+			continue
+		}
+		if !routeSourceExts[filepath.Ext(entry.Name())] {
+			continue
+		}
+		if content, err := os.ReadFile(path); err == nil {
+			fn(path, string(content))
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// javaBuildFiles are the build files of Maven and Gradle projects, in order of preference
+var javaBuildFiles = []string{"pom.xml", "build.gradle", "build.gradle.kts"}
+
+// findJavaBuildFile returns the path of the first Maven or Gradle build file found
+func (a *Analyzer) findJavaBuildFile(repoPath string) (string, bool) {
+	for _, name := range javaBuildFiles {
+		if path, found := a.findFileRecursive(repoPath, name); found {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// detectJavaFramework returns "spring-boot" when the build file depends on Spring Boot
+// (starter dependencies or the Spring Boot plugin), "java" otherwise
+func detectJavaFramework(buildFilePath string) string {
+	content, err := os.ReadFile(buildFilePath)
+	if err == nil && strings.Contains(string(content), "spring-boot") {
+		return "spring-boot"
+	}
+	return "java"
+}
+
+// javaStartCommand builds the application jar with Maven or Gradle (the project's wrapper
+// when present) and runs it with java -jar
+func javaStartCommand(appPath, framework, packageManager string) string {
+	if packageManager == "gradle" {
+		gradle := "gradle"
+		if fileExists(filepath.Join(appPath, "gradlew")) {
+			gradle = "./gradlew"
+		}
+
+		// bootJar only builds the executable jar (build also produces a -plain.jar)
+		task := "jar"
+		if framework == "spring-boot" {
+			task = "bootJar"
+		}
+		return gradle + " " + task + " -x test && java -jar $(ls build/libs/*.jar | grep -v -- -plain.jar | head -n 1)"
+	}
+
+	mvn := "mvn"
+	if fileExists(filepath.Join(appPath, "mvnw")) {
+		mvn = "./mvnw"
+	}
+	return mvn + " -q -DskipTests package && java -jar $(ls target/*.jar | head -n 1)"
+}
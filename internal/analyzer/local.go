@@ -0,0 +1,140 @@
+package analyzer
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/go-git/go-git/v5"
+
+	"github.com/Smana/scai/internal/types"
+)
+
+// copyIgnoreDirs are the directories skipped when copying a local directory: VCS metadata and
+// dependency caches, which the deployment rebuilds. Directories such as build or dist may hold
+// sources and are copied.
+var copyIgnoreDirs = map[string]bool{
+	".git": true, ".hg": true, ".svn": true,
+	"node_modules": true, "bower_components": true, "jspm_packages": true,
+	"venv": true, ".venv": true, "__pycache__": true, ".tox": true, ".nox": true, ".pytest_cache": true, ".mypy_cache": true,
+	".gradle": true, ".m2": true, ".bundle": true, ".terraform": true, ".cache": true,
+}
+
+// IsLocalDirectory checks if a path is an existing local directory
+func IsLocalDirectory(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// AnalyzeFromDirectory analyzes a local directory. It is copied to the work directory first so
+// the source is never modified; the commit SHA is read when the directory is in a Git repository.
+func (a *Analyzer) AnalyzeFromDirectory(dirPath string) (*types.Analysis, error) {
+	sourceDir, err := filepath.Abs(dirPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", dirPath, err)
+	}
+
+	repoPath, err := a.copyDirectory(sourceDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy %s: %w", dirPath, err)
+	}
+
+	// Store the local path as "URL"
+	analysis, err := a.analyzeDirectory(repoPath, sourceDir, localCommitSHA(sourceDir))
+	if err != nil {
+		return nil, err
+	}
+	analysis.LocalSource = true
+	return analysis, nil
+}
+
+// copyDirectory copies sourceDir to the work directory, skipping copyIgnoreDirs and symlinks
+func (a *Analyzer) copyDirectory(sourceDir string) (string, error) {
+	copyDir := filepath.Join(a.workDir, "repos")
+	if err := os.MkdirAll(copyDir, 0o750); err != nil {
+		return "", err
+	}
+
+	targetPath, err := filepath.Abs(filepath.Join(copyDir, filepath.Base(sourceDir)))
+	if err != nil {
+		return "", err
+	}
+
+	// Remove if exists
+	if err := os.RemoveAll(targetPath); err != nil {
+		return "", err
+	}
+
+	err = filepath.WalkDir(sourceDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(sourceDir, path)
+		if err != nil {
+			return err
+		}
+		destPath := filepath.Join(targetPath, relPath)
+
+		if entry.IsDir() {
+			// Skip ignored directories, and the copy itself when the work directory is inside the source
+			if path != sourceDir && (copyIgnoreDirs[entry.Name()] || path == targetPath) {
+				return filepath.SkipDir
+			}
+			return os.MkdirAll(destPath, 0o750)
+		}
+
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		return copyFile(path, destPath)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return targetPath, nil
+}
+
+// copyFile copies a regular file, keeping its permissions
+func copyFile(src, dest string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+
+	// #nosec G304 -- src is a file of the directory being deployed
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+
+	// #nosec G304 -- dest is inside the work directory
+	out, err := os.OpenFile(dest, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// localCommitSHA returns the HEAD commit of the Git repository containing dir ("" if none)
+func localCommitSHA(dir string) string {
+	repo, err := git.PlainOpenWithOptions(dir, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return ""
+	}
+
+	ref, err := repo.Head()
+	if err != nil {
+		return ""
+	}
+	return ref.Hash().String()
+}
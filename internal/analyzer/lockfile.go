@@ -0,0 +1,198 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// frameworkPackages maps a framework to the package its version is read from
+var frameworkPackages = map[string]string{
+	"django":  "django",
+	"flask":   "flask",
+	"fastapi": "fastapi",
+	"express": "express",
+	"nextjs":  "next",
+	"rails":   "rails",
+	"sinatra": "sinatra",
+}
+
+// goDirective matches the Go version of a go.mod (go 1.22 or go 1.22.3)
+var goDirective = regexp.MustCompile(`(?m)^go\s+(\d+\.\d+(?:\.\d+)?)\s*$`)
+
+// detectFrameworkVersion returns the version of the framework of the app in appDir, read from
+// its lockfile (poetry.lock, uv.lock, package-lock.json, yarn.lock, Gemfile.lock), a pinned
+// requirements.txt, or for Go the version of go.mod; empty when none is found
+func detectFrameworkVersion(appDir, framework string) string {
+	if framework == "go" {
+		return goModVersion(filepath.Join(appDir, "go.mod"))
+	}
+
+	pkg, ok := frameworkPackages[framework]
+	if !ok {
+		return ""
+	}
+
+	lookups := []func() string{
+		func() string { return pythonLockVersion(filepath.Join(appDir, "poetry.lock"), pkg) },
+		func() string { return pythonLockVersion(filepath.Join(appDir, "uv.lock"), pkg) },
+		func() string { return requirementsVersion(filepath.Join(appDir, "requirements.txt"), pkg) },
+		func() string { return npmLockVersion(filepath.Join(appDir, "package-lock.json"), pkg) },
+		func() string { return yarnLockVersion(filepath.Join(appDir, "yarn.lock"), pkg) },
+		func() string { return gemfileLockVersion(filepath.Join(appDir, "Gemfile.lock"), pkg) },
+	}
+	for _, lookup := range lookups {
+		if version := lookup(); version != "" {
+			return version
+		}
+	}
+	return ""
+}
+
+// goModVersion returns the go directive of a go.mod
+func goModVersion(path string) string {
+	content, err := os.ReadFile(path) // #nosec G304 -- path within the analyzed repository
+	if err != nil {
+		return ""
+	}
+	if match := goDirective.FindSubmatch(content); match != nil {
+		return string(match[1])
+	}
+	return ""
+}
+
+// pythonLockVersion returns the version of pkg in a poetry.lock or uv.lock: both list the
+// resolved packages as [[package]] tables with name and version keys
+func pythonLockVersion(path, pkg string) string {
+	content, err := os.ReadFile(path) // #nosec G304 -- path within the analyzed repository
+	if err != nil {
+		return ""
+	}
+
+	for _, table := range strings.Split(string(content), "[[package]]")[1:] {
+		var name, version string
+		for _, line := range strings.Split(table, "\n") {
+			key, value, found := strings.Cut(line, "=")
+			if !found {
+				continue
+			}
+			value = strings.Trim(strings.TrimSpace(value), `"`)
+			switch strings.TrimSpace(key) {
+			case "name":
+				name = value
+			case "version":
+				version = value
+			}
+			if name != "" && version != "" {
+				break
+			}
+		}
+		if normalizePythonPackage(name) == pkg {
+			return version
+		}
+	}
+	return ""
+}
+
+// requirementsVersion returns the version pkg is pinned to (pkg==x.y.z) in a requirements.txt
+func requirementsVersion(path, pkg string) string {
+	content, err := os.ReadFile(path) // #nosec G304 -- path within the analyzed repository
+	if err != nil {
+		return ""
+	}
+
+	for _, line := range strings.Split(string(content), "\n") {
+		name, version, found := strings.Cut(strings.TrimSpace(line), "==")
+		if !found {
+			continue
+		}
+		// Extras (django[argon2]==4.2) and markers (; python_version >= "3.8") are ignored
+		name, _, _ = strings.Cut(name, "[")
+		version, _, _ = strings.Cut(version, ";")
+		if normalizePythonPackage(name) == pkg {
+			return strings.TrimSpace(version)
+		}
+	}
+	return ""
+}
+
+// normalizePythonPackage normalizes a Python package name for comparison (PEP 503)
+func normalizePythonPackage(name string) string {
+	return strings.ToLower(strings.NewReplacer("_", "-", ".", "-").Replace(strings.TrimSpace(name)))
+}
+
+// npmLockVersion returns the version of pkg in a package-lock.json: under packages
+// ("node_modules/<pkg>", lockfile v2 and v3) or dependencies (v1)
+func npmLockVersion(path, pkg string) string {
+	content, err := os.ReadFile(path) // #nosec G304 -- path within the analyzed repository
+	if err != nil {
+		return ""
+	}
+
+	type lockedPackage struct {
+		Version string `json:"version"`
+	}
+	var lock struct {
+		Packages     map[string]lockedPackage `json:"packages"`
+		Dependencies map[string]lockedPackage `json:"dependencies"`
+	}
+	if err := json.Unmarshal(content, &lock); err != nil {
+		return ""
+	}
+
+	if locked, ok := lock.Packages["node_modules/"+pkg]; ok {
+		return locked.Version
+	}
+	return lock.Dependencies[pkg].Version
+}
+
+// yarnLockVersion returns the version of pkg in a yarn.lock, whose entries start with the
+// package specifiers ("express@^4.18.0", express@^4.18.0:) followed by an indented version
+func yarnLockVersion(path, pkg string) string {
+	content, err := os.ReadFile(path) // #nosec G304 -- path within the analyzed repository
+	if err != nil {
+		return ""
+	}
+
+	inEntry := false
+	for _, line := range strings.Split(string(content), "\n") {
+		if line != "" && !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "#") {
+			// Entry header: one or more specifiers separated by commas
+			inEntry = false
+			for _, specifier := range strings.Split(strings.TrimSuffix(line, ":"), ",") {
+				specifier = strings.Trim(strings.TrimSpace(specifier), `"`)
+				if strings.HasPrefix(specifier, pkg+"@") {
+					inEntry = true
+				}
+			}
+			continue
+		}
+
+		if !inEntry {
+			continue
+		}
+		// yarn v1: version "4.18.2", yarn berry: version: 4.18.2
+		field := strings.TrimSpace(line)
+		if value, found := strings.CutPrefix(field, "version"); found {
+			return strings.Trim(strings.TrimSpace(strings.TrimPrefix(value, ":")), `"`)
+		}
+	}
+	return ""
+}
+
+// gemfileLockVersion returns the version of a gem in a Gemfile.lock, listed in the specs
+// as "    rails (7.1.3)"
+func gemfileLockVersion(path, gem string) string {
+	content, err := os.ReadFile(path) // #nosec G304 -- path within the analyzed repository
+	if err != nil {
+		return ""
+	}
+
+	pattern := regexp.MustCompile(`(?m)^    ` + regexp.QuoteMeta(gem) + ` \(([^)]+)\)`)
+	if match := pattern.FindSubmatch(content); match != nil {
+		return string(match[1])
+	}
+	return ""
+}
@@ -0,0 +1,144 @@
+package analyzer
+
+import "testing"
+
+func TestDetectFrameworkVersion(t *testing.T) {
+	tests := []struct {
+		name      string
+		framework string
+		file      string
+		content   string
+		want      string
+	}{
+		{
+			name:      "poetry.lock",
+			framework: "django",
+			file:      "poetry.lock",
+			content: `[[package]]
+name = "asgiref"
+version = "3.7.2"
+description = "ASGI specs"
+
+[[package]]
+name = "Django"
+version = "4.2.7"
+description = "A high-level Python web framework"
+
+[package.dependencies]
+asgiref = ">=3.6.0,<4"
+`,
+			want: "4.2.7",
+		},
+		{
+			name:      "uv.lock",
+			framework: "fastapi",
+			file:      "uv.lock",
+			content: `version = 1
+requires-python = ">=3.12"
+
+[[package]]
+name = "fastapi"
+version = "0.115.6"
+source = { registry = "https://pypi.org/simple" }
+`,
+			want: "0.115.6",
+		},
+		{
+			name:      "pinned requirements.txt",
+			framework: "flask",
+			file:      "requirements.txt",
+			content:   "gunicorn==22.0.0\nFlask[async]==3.0.3 ; python_version >= \"3.8\"\n",
+			want:      "3.0.3",
+		},
+		{
+			name:      "unpinned requirements.txt",
+			framework: "flask",
+			file:      "requirements.txt",
+			content:   "flask>=2\n",
+			want:      "",
+		},
+		{
+			name:      "package-lock.json v3",
+			framework: "express",
+			file:      "package-lock.json",
+			content: `{"lockfileVersion": 3, "packages": {
+  "": {"dependencies": {"express": "^4.18.0"}},
+  "node_modules/express": {"version": "4.19.2"},
+  "node_modules/body-parser/node_modules/express": {"version": "3.0.0"}
+}}`,
+			want: "4.19.2",
+		},
+		{
+			name:      "package-lock.json v1",
+			framework: "nextjs",
+			file:      "package-lock.json",
+			content:   `{"lockfileVersion": 1, "dependencies": {"next": {"version": "12.3.4"}}}`,
+			want:      "12.3.4",
+		},
+		{
+			name:      "yarn.lock v1",
+			framework: "express",
+			file:      "yarn.lock",
+			content: `# yarn lockfile v1
+
+express-session@^1.17.0:
+  version "1.17.3"
+
+"express@^4.17.0", express@^4.18.2:
+  version "4.18.2"
+  resolved "https://registry.yarnpkg.com/express/-/express-4.18.2.tgz"
+`,
+			want: "4.18.2",
+		},
+		{
+			name:      "yarn.lock berry",
+			framework: "nextjs",
+			file:      "yarn.lock",
+			content: `__metadata:
+  version: 8
+
+"next@npm:^14.1.0":
+  version: 14.1.4
+  resolution: "next@npm:14.1.4"
+`,
+			want: "14.1.4",
+		},
+		{
+			name:      "Gemfile.lock",
+			framework: "rails",
+			file:      "Gemfile.lock",
+			content: `GEM
+  remote: https://rubygems.org/
+  specs:
+    rails (7.1.3)
+      actionpack (= 7.1.3)
+    railties (7.1.3)
+`,
+			want: "7.1.3",
+		},
+		{
+			name:      "go.mod",
+			framework: "go",
+			file:      "go.mod",
+			content:   "module example.com/app\n\ngo 1.22.3\n\nrequire github.com/gin-gonic/gin v1.9.1\n",
+			want:      "1.22.3",
+		},
+		{
+			name:      "unknown framework",
+			framework: "unknown",
+			file:      "package-lock.json",
+			content:   `{"packages": {"node_modules/express": {"version": "4.19.2"}}}`,
+			want:      "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			appDir := t.TempDir()
+			writeFile(t, appDir, tt.file, tt.content)
+			if got := detectFrameworkVersion(appDir, tt.framework); got != tt.want {
+				t.Errorf("detectFrameworkVersion(%s) = %q, want %q", tt.framework, got, tt.want)
+			}
+		})
+	}
+}
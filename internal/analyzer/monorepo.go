@@ -0,0 +1,118 @@
+package analyzer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// appManifests are files that mark the root of a deployable application
+var appManifests = []string{"pyproject.toml", "requirements.txt", "Pipfile", "package.json", "go.mod", "Gemfile", "pom.xml", "build.gradle", "build.gradle.kts"}
+
+// AppCandidate is a deployable application found in a repository
+type AppCandidate struct {
+	Dir       string // Directory relative to the repository root ("." for the root)
+	Framework string
+}
+
+// AppSelector chooses one of several detected applications and returns its directory
+type AppSelector func(candidates []AppCandidate) (string, error)
+
+// SetAppDir forces analysis of a specific application directory (relative to the repository root)
+func (a *Analyzer) SetAppDir(dir string) {
+	a.appDir = dir
+}
+
+// SetAppSelector sets the callback used when a repository contains several applications.
+// Without a selector, the first detected application is analyzed.
+func (a *Analyzer) SetAppSelector(selector AppSelector) {
+	a.selectApp = selector
+}
+
+// DiscoverApps finds all directories containing an application manifest.
+// Directories below an application are not searched (except below the repository
+// root, which is often a workspace manifest in monorepos).
+func (a *Analyzer) DiscoverApps(repoPath string) []AppCandidate {
+	var candidates []AppCandidate
+	a.discoverApps(repoPath, repoPath, 0, &candidates)
+	return candidates
+}
+
+func (a *Analyzer) discoverApps(repoPath, dir string, depth int, candidates *[]AppCandidate) {
+	if depth > a.maxDepth {
+		return
+	}
+
+	if hasAppManifest(dir) {
+		relDir, _ := filepath.Rel(repoPath, dir)
+		*candidates = append(*candidates, AppCandidate{
+			Dir:       relDir,
+			Framework: a.detectFrameworkShallow(dir),
+		})
+		if depth > 0 {
+			return
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() && !a.ignoreDirs[entry.Name()] {
+			a.discoverApps(repoPath, filepath.Join(dir, entry.Name()), depth+1, candidates)
+		}
+	}
+}
+
+// detectFrameworkShallow detects the framework of an application without looking into subdirectories
+func (a *Analyzer) detectFrameworkShallow(dir string) string {
+	shallow := *a
+	shallow.maxDepth = 0
+
+	framework, _, err := shallow.detectFramework(dir)
+	if err != nil {
+		return "unknown"
+	}
+	return framework
+}
+
+// resolveAppRoot returns the directory of the application to analyze:
+// the configured app dir, the selected app in a monorepo, or the repository root
+func (a *Analyzer) resolveAppRoot(repoPath string) (string, error) {
+	if a.appDir != "" {
+		appDir := filepath.Clean(a.appDir)
+		if filepath.IsAbs(appDir) || appDir == ".." || strings.HasPrefix(appDir, ".."+string(filepath.Separator)) {
+			return "", fmt.Errorf("app directory must be relative to the repository: %s", a.appDir)
+		}
+
+		appRoot := filepath.Join(repoPath, appDir)
+		if info, err := os.Stat(appRoot); err != nil || !info.IsDir() {
+			return "", fmt.Errorf("app directory not found in repository: %s", a.appDir)
+		}
+		return appRoot, nil
+	}
+
+	candidates := a.DiscoverApps(repoPath)
+	if len(candidates) <= 1 || a.selectApp == nil {
+		return repoPath, nil
+	}
+
+	selected, err := a.selectApp(candidates)
+	if err != nil {
+		return "", fmt.Errorf("app selection failed: %w", err)
+	}
+	return filepath.Join(repoPath, selected), nil
+}
+
+// hasAppManifest checks if a directory contains an application manifest
+func hasAppManifest(dir string) bool {
+	for _, manifest := range appManifests {
+		if fileExists(filepath.Join(dir, manifest)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// tsconfigOutDir matches the output directory of the TypeScript compiler in tsconfig.json
+// (read with a regexp: tsconfig files may contain comments, which encoding/json rejects)
+var tsconfigOutDir = regexp.MustCompile(`"outDir"\s*:\s*"([^"]+)"`)
+
+// nodePackage holds the fields of package.json the start and build commands are built from
+type nodePackage struct {
+	Main    string            `json:"main"`
+	Scripts map[string]string `json:"scripts"`
+}
+
+// readNodePackage parses the package.json of appPath, nil if it is missing or invalid
+func readNodePackage(appPath string) *nodePackage {
+	content, err := os.ReadFile(filepath.Join(appPath, "package.json"))
+	if err != nil {
+		return nil
+	}
+
+	var pkg nodePackage
+	if err := json.Unmarshal(content, &pkg); err != nil {
+		return nil
+	}
+	return &pkg
+}
+
+// nodeCommands returns the build command (empty when none is needed) and the start command of
+// the Node app in appPath, from its package.json scripts: the build script when defined, else
+// the TypeScript compiler for a project with a tsconfig.json; the start script when defined,
+// else node running the main file (compiled to the tsconfig outDir for TypeScript).
+func nodeCommands(appPath, packageManager string) (build, start string) {
+	pkg := readNodePackage(appPath)
+	if pkg == nil {
+		return "", nodeScriptCommand(packageManager, "start")
+	}
+
+	typescript := fileExists(filepath.Join(appPath, "tsconfig.json"))
+	switch {
+	case pkg.Scripts["build"] != "":
+		build = nodeScriptCommand(packageManager, "build")
+	case typescript:
+		build = nodeExecCommand(packageManager, "tsc")
+	}
+
+	if pkg.Scripts["start"] != "" {
+		return build, nodeScriptCommand(packageManager, "start")
+	}
+	return build, "node " + nodeMain(appPath, pkg.Main, typescript)
+}
+
+// nodeMain returns the file node runs for an app without start script: main from package.json
+// (index.js by default), as compiled JavaScript for a TypeScript source
+func nodeMain(appPath, main string, typescript bool) string {
+	if main == "" {
+		main = "index.js"
+	}
+	if !typescript || !strings.HasSuffix(main, ".ts") {
+		return main
+	}
+
+	outDir := "dist"
+	if content, err := os.ReadFile(filepath.Join(appPath, "tsconfig.json")); err == nil {
+		if match := tsconfigOutDir.FindSubmatch(content); match != nil {
+			outDir = string(match[1])
+		}
+	}
+
+	// src/index.ts is compiled to <outDir>/index.js (the common rootDir of the sources)
+	compiled := strings.TrimSuffix(path.Base(main), ".ts") + ".js"
+	return path.Join(path.Clean(outDir), compiled)
+}
+
+// nodeScriptCommand returns the command running a package.json script
+func nodeScriptCommand(packageManager, script string) string {
+	switch packageManager {
+	case "yarn", "pnpm":
+		return packageManager + " " + script
+	default: // npm
+		if script == "start" {
+			return "npm start"
+		}
+		return "npm run " + script
+	}
+}
+
+// nodeExecCommand returns the command running a binary installed with the app's dependencies
+func nodeExecCommand(packageManager, args string) string {
+	switch packageManager {
+	case "yarn":
+		return "yarn " + args
+	case "pnpm":
+		return "pnpm exec " + args
+	default: // npm
+		return "npx " + args
+	}
+}
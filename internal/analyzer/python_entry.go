@@ -0,0 +1,153 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// pythonAppObject matches the module-level application object of Flask and FastAPI apps,
+// created directly or by an application factory: app = Flask(__name__), api = FastAPI(),
+// application = create_app()
+var pythonAppObject = regexp.MustCompile(`(?m)^(\w+)\s*=\s*(?:\w+\.)?(?:Flask|FastAPI|create_app|make_app)\(`)
+
+// pythonAppImport matches the application object imported by a wsgi.py or asgi.py module:
+// from myapp import app
+var pythonAppImport = regexp.MustCompile(`(?m)^from\s+[\w.]+\s+import\s+(?:[\w\s,]*,\s*)?(app|application)\b`)
+
+// pythonMainBlock matches the script entry of a module: if __name__ == "__main__":
+var pythonMainBlock = regexp.MustCompile(`(?m)^if\s+__name__\s*==\s*["']__main__["']\s*:`)
+
+// pythonEntry is the module a Python web app is started from
+type pythonEntry struct {
+	file   string // Path relative to the app directory (e.g. myapp/wsgi.py)
+	module string // Dotted module name (e.g. myapp.wsgi), the package for __init__.py and __main__.py
+	object string // Application object of the module, empty if none
+	main   bool   // Module has an if __name__ == "__main__" block
+}
+
+// String returns the entry as module:object, or the module alone when it has no app object
+func (e *pythonEntry) String() string {
+	if e.object == "" {
+		return e.module
+	}
+	return e.module + ":" + e.object
+}
+
+// rank orders candidate entries, lowest first: a module creating the app and running it,
+// then a wsgi.py/asgi.py module, then any module with the app object, then a package __main__.py
+func (e *pythonEntry) rank() int {
+	base := filepath.Base(e.file)
+	switch {
+	case e.object != "" && e.main:
+		return 0
+	case e.object != "" && (base == "wsgi.py" || base == "asgi.py"):
+		return 1
+	case e.object != "":
+		return 2
+	case base == "__main__.py":
+		return 3
+	default:
+		return -1
+	}
+}
+
+// detectPythonEntry inspects the Python files under appPath for the application object or a
+// __main__ entry, and returns the module the app is started from (nil if none is found).
+// Tests are skipped; on equal rank, the shallowest module wins.
+func (a *Analyzer) detectPythonEntry(appPath string) *pythonEntry {
+	var best *pythonEntry
+
+	a.walkSourceFiles(appPath, 0, func(path, content string) {
+		rel, err := filepath.Rel(appPath, path)
+		if err != nil || filepath.Ext(rel) != ".py" || isPythonTest(rel) {
+			return
+		}
+
+		entry := &pythonEntry{file: filepath.ToSlash(rel), main: pythonMainBlock.MatchString(content)}
+		entry.module = pythonModule(entry.file)
+		if match := pythonAppObject.FindStringSubmatch(content); match != nil {
+			entry.object = match[1]
+		} else if base := filepath.Base(rel); base == "wsgi.py" || base == "asgi.py" {
+			if match := pythonAppImport.FindStringSubmatch(content); match != nil {
+				entry.object = match[1]
+			}
+		}
+
+		rank := entry.rank()
+		if rank < 0 {
+			return
+		}
+		if best == nil || rank < best.rank() ||
+			(rank == best.rank() && strings.Count(entry.file, "/") < strings.Count(best.file, "/")) {
+			best = entry
+		}
+	})
+
+	return best
+}
+
+// pythonModule returns the dotted module name of a Python file (the package of __init__.py
+// and __main__.py files)
+func pythonModule(file string) string {
+	module := strings.ReplaceAll(strings.TrimSuffix(file, ".py"), "/", ".")
+	for _, suffix := range []string{".__init__", ".__main__"} {
+		module = strings.TrimSuffix(module, suffix)
+	}
+	return module
+}
+
+// isPythonTest reports whether a Python file belongs to the test suite
+func isPythonTest(file string) bool {
+	base := filepath.Base(file)
+	if strings.HasPrefix(base, "test_") || strings.HasSuffix(base, "_test.py") || base == "conftest.py" {
+		return true
+	}
+	for _, dir := range strings.Split(filepath.ToSlash(filepath.Dir(file)), "/") {
+		if dir == "tests" || dir == "test" {
+			return true
+		}
+	}
+	return false
+}
+
+// pythonScriptCommand returns the command running a Python script or module (-m module)
+// with the package manager's environment
+func pythonScriptCommand(packageManager, args string) string {
+	switch packageManager {
+	case "poetry":
+		return "poetry run python " + args
+	case "uv":
+		return "uv run " + args
+	case "pipenv":
+		return "pipenv run python " + args
+	default: // pip
+		return "python3 " + args
+	}
+}
+
+// pythonToolCommand returns the command running a tool installed with the app's dependencies
+// (uvicorn, flask) with the package manager's environment
+func pythonToolCommand(packageManager, args string) string {
+	switch packageManager {
+	case "poetry", "uv", "pipenv":
+		return packageManager + " run " + args
+	default: // pip
+		return args
+	}
+}
+
+// flaskStartCommand starts a Flask app from its entry: a top-level script with a __main__
+// block as is (it calls app.run), a package module with python -m, otherwise the flask CLI
+// serving the app object on all interfaces
+func flaskStartCommand(entry *pythonEntry, packageManager string) string {
+	script := entry.main || filepath.Base(entry.file) == "__main__.py"
+	switch {
+	case script && !strings.Contains(entry.file, "/"):
+		return pythonScriptCommand(packageManager, entry.file)
+	case script:
+		return pythonScriptCommand(packageManager, "-m "+entry.module)
+	default:
+		return pythonToolCommand(packageManager, "flask --app "+entry.String()+" run --host 0.0.0.0")
+	}
+}
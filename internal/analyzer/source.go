@@ -0,0 +1,111 @@
+package analyzer
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+// remoteCheckTimeout bounds the ls-remote done to check a Git repository is reachable
+const remoteCheckTimeout = 30 * time.Second
+
+// ValidateSource checks that a repository source can be fetched before any analysis work:
+// Git URLs must be reachable (refs are listed without cloning), zip files must exist
+// and be valid archives, and local directories must exist
+func ValidateSource(ctx context.Context, source string) error {
+	if IsZipFile(source) {
+		return validateZipFile(source)
+	}
+	if IsLocalDirectory(source) {
+		return nil
+	}
+
+	endpoint, err := parseGitURL(source)
+	if err != nil {
+		return err
+	}
+	return checkRemoteReachable(ctx, source, endpoint)
+}
+
+// gitProtocols are the remote protocols go-git clones from
+var gitProtocols = map[string]bool{"http": true, "https": true, "ssh": true, "git": true}
+
+// parseGitURL parses a remote Git URL: http(s), ssh:// and scp-like (git@github.com:org/repo.git)
+// URLs, or git://. Paths are local sources, handled before.
+func parseGitURL(source string) (*transport.Endpoint, error) {
+	endpoint, err := transport.NewEndpoint(source)
+	if err == nil && gitProtocols[endpoint.Protocol] {
+		return endpoint, nil
+	}
+	return nil, fmt.Errorf("unsupported repository source %q: expected a Git URL (https://, ssh:// or git@host:org/repo.git), a .zip file or a local directory", source)
+}
+
+// validateZipFile checks that zipPath is a readable zip archive with at least one file
+func validateZipFile(zipPath string) error {
+	info, err := os.Stat(zipPath)
+	if err != nil {
+		return fmt.Errorf("zip file not found: %s", zipPath)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("%s is a directory, not a zip file", zipPath)
+	}
+
+	reader, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return fmt.Errorf("invalid zip file %s: %w", zipPath, err)
+	}
+	defer func() { _ = reader.Close() }()
+
+	if len(reader.File) == 0 {
+		return fmt.Errorf("zip file %s is empty", zipPath)
+	}
+	return nil
+}
+
+// checkRemoteReachable lists the refs of a Git repository (git ls-remote) to confirm it
+// exists and can be read: anonymously over http(s), with the SSH agent over SSH
+func checkRemoteReachable(ctx context.Context, repoURL string, endpoint *transport.Endpoint) error {
+	ctx, cancel := context.WithTimeout(ctx, remoteCheckTimeout)
+	defer cancel()
+
+	remote := git.NewRemote(memory.NewStorage(), &gitconfig.RemoteConfig{
+		Name: "origin",
+		URLs: []string{repoURL},
+	})
+
+	// The same proxy as the clone, or the check times out where the clone would succeed
+	proxyOpts, err := gitProxyOptions(endpoint)
+	if err != nil {
+		return err
+	}
+
+	_, err = remote.ListContext(ctx, &git.ListOptions{ProxyOptions: proxyOpts})
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, transport.ErrRepositoryNotFound):
+		return fmt.Errorf("repository not found: %s (check the URL)", repoURL)
+	case errors.Is(err, transport.ErrAuthenticationRequired),
+		errors.Is(err, transport.ErrAuthorizationFailed),
+		errors.Is(err, transport.ErrInvalidAuthMethod):
+		if endpoint.Protocol == "ssh" {
+			return fmt.Errorf("repository %s not found or access denied (check the URL and that your SSH agent holds a key with access to it)", repoURL)
+		}
+		// GitHub also asks for credentials for repositories that do not exist
+		return fmt.Errorf("repository %s not found or private (check the URL; use an SSH URL such as git@host:org/repo.git for private repositories)", repoURL)
+	case errors.Is(err, transport.ErrEmptyRemoteRepository):
+		return fmt.Errorf("repository %s is empty", repoURL)
+	case errors.Is(err, context.DeadlineExceeded):
+		return fmt.Errorf("repository %s did not respond within %s", repoURL, remoteCheckTimeout)
+	default:
+		return fmt.Errorf("repository %s is not reachable: %w", repoURL, err)
+	}
+}
@@ -2,6 +2,7 @@ package analyzer
 
 import (
 	"archive/zip"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -11,6 +12,49 @@ import (
 	"github.com/Smana/scai/internal/types"
 )
 
+// Default extraction limits of zip archives, against zip bombs exhausting the disk
+const (
+	DefaultZipMaxSizeMB     = 1024 // Total uncompressed size
+	DefaultZipMaxFileSizeMB = 256  // Uncompressed size of a single file
+	DefaultZipMaxFiles      = 50000
+)
+
+// ErrZipLimitExceeded is returned when a zip archive exceeds an extraction limit
+var ErrZipLimitExceeded = errors.New("zip archive exceeds extraction limit")
+
+// errZipEntryTooLarge is returned by extractZipFile when a file exceeds the bytes it may write
+var errZipEntryTooLarge = errors.New("zip entry too large")
+
+// ZipLimits bounds what extracting a zip archive may write
+type ZipLimits struct {
+	MaxSize     int64 // Total uncompressed bytes
+	MaxFileSize int64 // Uncompressed bytes of a single file
+	MaxFiles    int   // Entries (files and directories)
+}
+
+// DefaultZipLimits returns the default extraction limits
+func DefaultZipLimits() ZipLimits {
+	return ZipLimits{
+		MaxSize:     DefaultZipMaxSizeMB << 20,
+		MaxFileSize: DefaultZipMaxFileSizeMB << 20,
+		MaxFiles:    DefaultZipMaxFiles,
+	}
+}
+
+// SetZipLimits sets the extraction limits of zip archives (limits that are not positive keep
+// their current value)
+func (a *Analyzer) SetZipLimits(limits ZipLimits) {
+	if limits.MaxSize > 0 {
+		a.zipLimits.MaxSize = limits.MaxSize
+	}
+	if limits.MaxFileSize > 0 {
+		a.zipLimits.MaxFileSize = limits.MaxFileSize
+	}
+	if limits.MaxFiles > 0 {
+		a.zipLimits.MaxFiles = limits.MaxFiles
+	}
+}
+
 // AnalyzeFromZip analyzes a zip file containing application code
 func (a *Analyzer) AnalyzeFromZip(zipPath string) (*types.Analysis, error) {
 	// Extract zip file
@@ -19,57 +63,17 @@ func (a *Analyzer) AnalyzeFromZip(zipPath string) (*types.Analysis, error) {
 		return nil, fmt.Errorf("zip extraction failed: %w", err)
 	}
 
-	// Use regular analysis on extracted directory
-	analysis := &types.Analysis{
-		RepoURL:   zipPath, // Store zip path as "URL"
-		RepoPath:  repoPath,
-		CommitSHA: "", // No commit SHA for ZIP files
-	}
-
-	// Detect framework and app directory
-	framework, appDir, err := a.detectFramework(repoPath)
+	// Use regular analysis on extracted directory (store zip path as "URL", no commit SHA)
+	analysis, err := a.analyzeDirectory(repoPath, zipPath, "")
 	if err != nil {
 		return nil, err
 	}
-	analysis.Framework = framework
-	analysis.AppDir = appDir
-
-	// Detect language
-	language := a.detectLanguage(repoPath)
-	analysis.Language = language
-
-	// Detect package manager
-	packageManager := a.detectPackageManager(repoPath, language)
-	analysis.PackageManager = packageManager
-
-	// Extract dependencies
-	deps, err := a.extractDependencies(repoPath, language)
-	if err != nil {
-		return nil, err
-	}
-	analysis.Dependencies = deps
-
-	// Detect start command (use app directory and package manager for accurate detection)
-	startCmd := a.detectStartCommand(repoPath, framework, appDir, packageManager)
-	analysis.StartCommand = startCmd
-
-	// Detect port (scan actual code files)
-	port := a.detectPort(repoPath, framework, appDir)
-	analysis.Port = port
-
-	// Extract environment variables
-	envVars := a.extractEnvVars(repoPath)
-	analysis.EnvVars = envVars
-
-	// Check for special files
-	analysis.HasDockerfile = fileExists(filepath.Join(repoPath, "Dockerfile"))
-	analysis.HasDockerCompose = fileExists(filepath.Join(repoPath, "docker-compose.yml")) ||
-		fileExists(filepath.Join(repoPath, "docker-compose.yaml"))
-
+	analysis.LocalSource = true
 	return analysis, nil
 }
 
-// extractZip extracts a zip file to the work directory
+// extractZip extracts a zip file to the work directory, within the zip limits of the analyzer:
+// the partially extracted directory is removed when a limit is exceeded
 func (a *Analyzer) extractZip(zipPath string) (string, error) {
 	// Create extraction directory
 	extractDir := filepath.Join(a.workDir, "repos")
@@ -102,41 +106,67 @@ func (a *Analyzer) extractZip(zipPath string) (string, error) {
 		_ = reader.Close()
 	}()
 
-	// Extract all files
+	limits := a.zipLimits
+	if len(reader.File) > limits.MaxFiles {
+		_ = os.RemoveAll(targetPath)
+		return "", fmt.Errorf("%w: %d entries, more than the %d allowed (analyzer.zip_max_files)",
+			ErrZipLimitExceeded, len(reader.File), limits.MaxFiles)
+	}
+
+	// Extract all files, the total size counted from the bytes actually written
+	var total int64
 	for _, file := range reader.File {
-		if err := extractZipFile(file, targetPath); err != nil {
-			return "", fmt.Errorf("failed to extract %s: %w", file.Name, err)
+		maxBytes := min(limits.MaxFileSize, limits.MaxSize-total)
+		written, err := extractZipFile(file, targetPath, maxBytes)
+		if err != nil {
+			_ = os.RemoveAll(targetPath)
+			if !errors.Is(err, errZipEntryTooLarge) {
+				return "", fmt.Errorf("failed to extract %s: %w", file.Name, err)
+			}
+			if maxBytes < limits.MaxFileSize {
+				return "", fmt.Errorf("%w: %s brings the uncompressed size over %d MB (analyzer.zip_max_size_mb)",
+					ErrZipLimitExceeded, file.Name, limits.MaxSize>>20)
+			}
+			return "", fmt.Errorf("%w: %s is larger than %d MB uncompressed (analyzer.zip_max_file_size_mb)",
+				ErrZipLimitExceeded, file.Name, limits.MaxFileSize>>20)
 		}
+		total += written
 	}
 
 	return targetPath, nil
 }
 
-// extractZipFile extracts a single file from zip archive
-func extractZipFile(file *zip.File, destDir string) error {
+// extractZipFile extracts a single file from zip archive, writing at most maxBytes (it returns
+// errZipEntryTooLarge beyond) and returning the bytes written
+func extractZipFile(file *zip.File, destDir string, maxBytes int64) (int64, error) {
 	// Build destination path
 	//nolint:gosec // G305: Protected against zip slip vulnerability below
 	destPath := filepath.Join(destDir, file.Name)
 
 	// Check for zip slip vulnerability
 	if !strings.HasPrefix(destPath, filepath.Clean(destDir)+string(os.PathSeparator)) {
-		return fmt.Errorf("illegal file path: %s", file.Name)
+		return 0, fmt.Errorf("illegal file path: %s", file.Name)
 	}
 
 	// Create directory if it's a directory
 	if file.FileInfo().IsDir() {
-		return os.MkdirAll(destPath, file.Mode())
+		return 0, os.MkdirAll(destPath, file.Mode())
+	}
+
+	// The header size may lie: it only rejects early, the copy below is capped as well
+	if file.UncompressedSize64 > uint64(maxBytes) {
+		return 0, errZipEntryTooLarge
 	}
 
 	// Create parent directories
 	if err := os.MkdirAll(filepath.Dir(destPath), 0o750); err != nil {
-		return err
+		return 0, err
 	}
 
 	// Open source file
 	srcFile, err := file.Open()
 	if err != nil {
-		return err
+		return 0, err
 	}
 	defer func() {
 		_ = srcFile.Close()
@@ -146,19 +176,22 @@ func extractZipFile(file *zip.File, destDir string) error {
 	//nolint:gosec // G304: File path comes from trusted zip archive after validation
 	destFile, err := os.OpenFile(destPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, file.Mode())
 	if err != nil {
-		return err
+		return 0, err
 	}
 	defer func() {
 		_ = destFile.Close()
 	}()
 
-	// Copy contents
-	//nolint:gosec // G110: Decompression bomb protection should be added at a higher level
-	if _, err := io.Copy(destFile, srcFile); err != nil {
-		return err
+	// Copy contents, one byte past maxBytes to detect larger files
+	written, err := io.Copy(destFile, io.LimitReader(srcFile, maxBytes+1))
+	if err != nil {
+		return written, err
+	}
+	if written > maxBytes {
+		return written, errZipEntryTooLarge
 	}
 
-	return nil
+	return written, nil
 }
 
 // IsZipFile checks if a path is a zip file
@@ -2,12 +2,16 @@ package backend
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+
+	"github.com/Smana/scai/internal/cloud"
 )
 
 const (
@@ -15,6 +19,10 @@ const (
 	DefaultAWSRegion = "us-east-1"
 )
 
+// ErrBucketAccessDenied is returned when a bucket exists but the credentials cannot access it,
+// e.g. because bucket names are global and it belongs to another account
+var ErrBucketAccessDenied = errors.New("bucket exists but access is denied")
+
 // S3Manager handles S3 operations for Terraform state backend
 type S3Manager struct {
 	client *s3.Client
@@ -23,9 +31,9 @@ type S3Manager struct {
 
 // NewS3Manager creates a new S3 manager
 func NewS3Manager(ctx context.Context, region string) (*S3Manager, error) {
-	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	cfg, err := cloud.LoadAWSConfig(ctx, region)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+		return nil, err
 	}
 
 	return &S3Manager{
@@ -34,17 +42,34 @@ func NewS3Manager(ctx context.Context, region string) (*S3Manager, error) {
 	}, nil
 }
 
-// BucketExists checks if an S3 bucket exists
+// BucketExists checks if an S3 bucket exists. A bucket that exists but cannot be accessed
+// returns an error wrapping ErrBucketAccessDenied.
 func (m *S3Manager) BucketExists(ctx context.Context, bucketName string) (bool, error) {
 	_, err := m.client.HeadBucket(ctx, &s3.HeadBucketInput{
 		Bucket: aws.String(bucketName),
 	})
-	if err != nil {
-		// Check if it's a "not found" error
+	if err == nil {
+		return true, nil
+	}
+
+	var notFound *types.NotFound
+	var noSuchBucket *types.NoSuchBucket
+	if errors.As(err, &notFound) || errors.As(err, &noSuchBucket) {
 		return false, nil
 	}
 
-	return true, nil
+	// HeadBucket has no response body: other errors are only told apart by their status
+	var respErr *awshttp.ResponseError
+	if errors.As(err, &respErr) {
+		switch respErr.HTTPStatusCode() {
+		case http.StatusNotFound:
+			return false, nil
+		case http.StatusForbidden:
+			return false, fmt.Errorf("%w: %s", ErrBucketAccessDenied, bucketName)
+		}
+	}
+
+	return false, fmt.Errorf("failed to check bucket %s: %w", bucketName, err)
 }
 
 // ListBuckets returns all S3 buckets in the account
@@ -64,21 +89,33 @@ func (m *S3Manager) ListBuckets(ctx context.Context) ([]string, error) {
 	return buckets, nil
 }
 
-// GetBucketLocation returns the AWS region where a bucket is located
+// GetBucketLocation returns the AWS region where a bucket is located. The request is sent
+// to us-east-1, which answers for buckets in any region, so any regional manager can call it.
 func (m *S3Manager) GetBucketLocation(ctx context.Context, bucketName string) (string, error) {
 	result, err := m.client.GetBucketLocation(ctx, &s3.GetBucketLocationInput{
 		Bucket: aws.String(bucketName),
+	}, func(o *s3.Options) {
+		o.Region = DefaultAWSRegion
 	})
 	if err != nil {
 		return "", fmt.Errorf("failed to get bucket location: %w", err)
 	}
 
-	// AWS returns empty string for us-east-1 (legacy behavior)
-	if result.LocationConstraint == "" {
-		return DefaultAWSRegion, nil
-	}
+	return bucketRegion(result.LocationConstraint), nil
+}
 
-	return string(result.LocationConstraint), nil
+// bucketRegion converts a bucket location constraint to its region
+func bucketRegion(constraint types.BucketLocationConstraint) string {
+	switch constraint {
+	case "":
+		// AWS returns empty string for us-east-1 (legacy behavior)
+		return DefaultAWSRegion
+	case types.BucketLocationConstraintEu:
+		// Legacy name of eu-west-1
+		return "eu-west-1"
+	default:
+		return string(constraint)
+	}
 }
 
 // CreateStateBucket creates and configures an S3 bucket for Terraform state
@@ -0,0 +1,118 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// stubClient answers every S3 request with a canned status and body
+type stubClient struct {
+	status int
+	body   string
+	hosts  []string
+}
+
+func (c *stubClient) Do(req *http.Request) (*http.Response, error) {
+	c.hosts = append(c.hosts, req.URL.Host)
+	return &http.Response{
+		StatusCode: c.status,
+		Header:     http.Header{"Content-Type": []string{"application/xml"}},
+		Body:       io.NopCloser(strings.NewReader(c.body)),
+		Request:    req,
+	}, nil
+}
+
+// newStubManager returns a manager of another region than the buckets', as reused by init
+func newStubManager(httpClient *stubClient) *S3Manager {
+	return &S3Manager{
+		client: s3.New(s3.Options{
+			Region:           "ap-south-1",
+			Credentials:      aws.AnonymousCredentials{},
+			HTTPClient:       httpClient,
+			RetryMaxAttempts: 1,
+		}),
+		region: "ap-south-1",
+	}
+}
+
+func TestGetBucketLocation(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			name: "us-east-1 has an empty location constraint",
+			body: `<?xml version="1.0" encoding="UTF-8"?><LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/"/>`,
+			want: "us-east-1",
+		},
+		{
+			name: "regional bucket",
+			body: `<?xml version="1.0" encoding="UTF-8"?><LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/">eu-west-3</LocationConstraint>`,
+			want: "eu-west-3",
+		},
+		{
+			name: "legacy EU constraint",
+			body: `<?xml version="1.0" encoding="UTF-8"?><LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/">EU</LocationConstraint>`,
+			want: "eu-west-1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpClient := &stubClient{status: http.StatusOK, body: tt.body}
+			m := newStubManager(httpClient)
+
+			got, err := m.GetBucketLocation(context.Background(), "state-bucket")
+			if err != nil {
+				t.Fatalf("GetBucketLocation() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("GetBucketLocation() = %q, want %q", got, tt.want)
+			}
+			if want := []string{"state-bucket.s3.us-east-1.amazonaws.com"}; !slices.Equal(httpClient.hosts, want) {
+				t.Errorf("requests sent to %v, want %v", httpClient.hosts, want)
+			}
+		})
+	}
+}
+
+func TestBucketExists(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		want       bool
+		wantErr    bool
+		wantDenied bool
+	}{
+		{name: "exists", status: http.StatusOK, want: true},
+		{name: "available", status: http.StatusNotFound},
+		{name: "owned by another account", status: http.StatusForbidden, wantErr: true, wantDenied: true},
+		{name: "other error", status: http.StatusBadRequest, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newStubManager(&stubClient{status: tt.status})
+
+			got, err := m.BucketExists(context.Background(), "state-bucket")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("BucketExists() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if errors.Is(err, ErrBucketAccessDenied) != tt.wantDenied {
+				t.Errorf("BucketExists() error = %v, want ErrBucketAccessDenied: %v", err, tt.wantDenied)
+			}
+			if got != tt.want {
+				t.Errorf("BucketExists() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+
+	"github.com/Smana/scai/internal/cloud"
 )
 
 // BackendTFConfig represents the configuration for generating backend.tf
@@ -11,10 +14,21 @@ type BackendTFConfig struct {
 	BucketName string
 	Region     string
 	Key        string
+	RoleARN    string // IAM role assumed to access the bucket, empty for none
 }
 
 // GenerateBackendTF generates the backend.tf file content
 func GenerateBackendTF(cfg BackendTFConfig) string {
+	assumeRole := ""
+	if cfg.RoleARN != "" {
+		assumeRole = fmt.Sprintf(`
+    assume_role = {
+      role_arn     = "%s"
+      session_name = "%s"
+    }
+`, cfg.RoleARN, cloud.RoleSessionName)
+	}
+
 	return fmt.Sprintf(`# Generated by SCAI init wizard
 # This configures OpenTofu/Terraform to store state in S3 with native file locking
 # See: https://opentofu.org/docs/language/settings/backends/s3/
@@ -28,9 +42,9 @@ terraform {
 
     # OpenTofu 1.10+ native S3 locking (no DynamoDB required)
     use_lockfile = true
-  }
+%s  }
 }
-`, cfg.BucketName, cfg.Key, cfg.Region)
+`, cfg.BucketName, cfg.Key, cfg.Region, assumeRole)
 }
 
 // WriteBackendTF writes the backend.tf file to the terraform directory
@@ -46,6 +60,36 @@ func WriteBackendTF(terraformDir string, cfg BackendTFConfig) (string, error) {
 	return backendFile, nil
 }
 
+// backendAttribute matches the string attributes of the generated backend block
+var backendAttribute = regexp.MustCompile(`(?m)^\s*(bucket|key|region|role_arn)\s*=\s*"([^"]*)"`)
+
+// ReadBackendTF reads the S3 backend configured by the backend.tf of terraformDir, and reports
+// false when the directory has none (local state)
+func ReadBackendTF(terraformDir string) (BackendTFConfig, bool, error) {
+	content, err := os.ReadFile(BackendTFPath(terraformDir))
+	if os.IsNotExist(err) {
+		return BackendTFConfig{}, false, nil
+	}
+	if err != nil {
+		return BackendTFConfig{}, false, fmt.Errorf("failed to read backend.tf: %w", err)
+	}
+
+	var cfg BackendTFConfig
+	for _, match := range backendAttribute.FindAllStringSubmatch(string(content), -1) {
+		switch match[1] {
+		case "bucket":
+			cfg.BucketName = match[2]
+		case "key":
+			cfg.Key = match[2]
+		case "region":
+			cfg.Region = match[2]
+		case "role_arn":
+			cfg.RoleARN = match[2]
+		}
+	}
+	return cfg, true, nil
+}
+
 // BackendTFPath returns the path where backend.tf should be written
 func BackendTFPath(terraformDir string) string {
 	return filepath.Join(terraformDir, "backend.tf")
@@ -0,0 +1,30 @@
+package backend
+
+import "testing"
+
+func TestReadBackendTF(t *testing.T) {
+	dir := t.TempDir()
+
+	if _, found, err := ReadBackendTF(dir); err != nil || found {
+		t.Fatalf("ReadBackendTF() without backend.tf = found %v, error %v, want local state", found, err)
+	}
+
+	want := BackendTFConfig{BucketName: "state-bucket", Region: "eu-west-3", Key: "deployments/abc/terraform.tfstate"}
+	if _, err := WriteBackendTF(dir, want); err != nil {
+		t.Fatalf("WriteBackendTF() error = %v", err)
+	}
+	got, found, err := ReadBackendTF(dir)
+	if err != nil || !found || got != want {
+		t.Errorf("ReadBackendTF() = %+v (found %v, error %v), want %+v", got, found, err, want)
+	}
+
+	// The role assumed to access the bucket
+	want.RoleARN = "arn:aws:iam::123456789012:role/deployer"
+	if _, err := WriteBackendTF(dir, want); err != nil {
+		t.Fatalf("WriteBackendTF() error = %v", err)
+	}
+	got, found, err = ReadBackendTF(dir)
+	if err != nil || !found || got != want {
+		t.Errorf("ReadBackendTF() with role = %+v (found %v, error %v), want %+v", got, found, err, want)
+	}
+}
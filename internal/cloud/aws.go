@@ -6,27 +6,30 @@ import (
 	"sort"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 )
 
 // AWSClient handles AWS operations
 type AWSClient struct {
-	ec2Client *ec2.Client
+	cfg            aws.Config
+	ec2Client      *ec2.Client
+	refreshRegions bool
+	includeOptIn   bool
+	offerings      map[string]map[string]bool // instance type offerings per region
 }
 
 // NewAWSClient creates a new AWS client
 func NewAWSClient(ctx context.Context) (*AWSClient, error) {
-	// Load AWS config (uses default credential chain)
+	// Load AWS config (default credential chain, or the identity selected by SetIdentity)
 	// Use us-east-1 as default region for listing regions (the region doesn't matter for DescribeRegions)
-	cfg, err := config.LoadDefaultConfig(ctx,
-		config.WithRegion("us-east-1"),
-	)
+	cfg, err := LoadAWSConfig(ctx, "us-east-1")
 	if err != nil {
-		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+		return nil, err
 	}
 
 	return &AWSClient{
+		cfg:       cfg,
 		ec2Client: ec2.NewFromConfig(cfg),
 	}, nil
 }
@@ -56,9 +59,29 @@ func (c *AWSClient) GetAllRegions(ctx context.Context) ([]string, error) {
 	return regions, nil
 }
 
-// ValidateRegion checks if a region is valid
+// EnabledRegions returns the regions enabled for the account: regions enabled by default
+// and opted-in regions
+func (c *AWSClient) EnabledRegions(ctx context.Context) ([]string, error) {
+	// Without AllRegions, DescribeRegions only returns the enabled regions
+	result, err := c.ec2Client.DescribeRegions(ctx, &ec2.DescribeRegionsInput{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe regions: %w", err)
+	}
+
+	regions := make([]string, 0, len(result.Regions))
+	for _, region := range result.Regions {
+		if region.RegionName != nil {
+			regions = append(regions, *region.RegionName)
+		}
+	}
+	sort.Strings(regions)
+
+	return regions, nil
+}
+
+// ValidateRegion checks if a region exists and is enabled for the account
 func (c *AWSClient) ValidateRegion(ctx context.Context, region string) (bool, error) {
-	regions, err := c.GetAllRegions(ctx)
+	regions, err := c.EnabledRegions(ctx)
 	if err != nil {
 		return false, err
 	}
@@ -72,28 +95,90 @@ func (c *AWSClient) ValidateRegion(ctx context.Context, region string) (bool, er
 	return false, nil
 }
 
-// GetRegionForSelect returns regions formatted for selection (with descriptions)
+// SetRefreshRegions forces the next region lookup to bypass the on-disk cache
+func (c *AWSClient) SetRefreshRegions(refresh bool) {
+	c.refreshRegions = refresh
+}
+
+// SetIncludeOptIn makes region selection include the opt-in regions the account has not enabled
+func (c *AWSClient) SetIncludeOptIn(include bool) {
+	c.includeOptIn = include
+}
+
+// GetRegionForSelect returns regions formatted for selection (with descriptions and opt-in status).
+// Opt-in regions the account has not enabled are left out unless SetIncludeOptIn(true) was called.
+// Results are cached in ~/.scai/regions.json for RegionCacheTTL.
 func (c *AWSClient) GetRegionForSelect(ctx context.Context) ([]RegionOption, error) {
-	regions, err := c.GetAllRegions(ctx)
-	if err != nil {
-		return nil, err
+	var options []RegionOption
+	cached := false
+	if !c.refreshRegions {
+		options, cached = loadRegionCache()
 	}
 
+	if !cached {
+		// Use DescribeRegions with AllRegions=true to get the opt-in status of every region
+		result, err := c.ec2Client.DescribeRegions(ctx, &ec2.DescribeRegionsInput{
+			AllRegions: aws.Bool(true),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe regions: %w", err)
+		}
+
+		options = regionOptions(result.Regions)
+
+		// Cache is best-effort: a write failure should not block region selection
+		_ = saveRegionCache(options)
+		c.refreshRegions = false
+	}
+
+	if c.includeOptIn {
+		return options, nil
+	}
+	return enabledRegionOptions(options), nil
+}
+
+// RegionOption represents a region with description
+type RegionOption struct {
+	Code        string `json:"code"`
+	Description string `json:"description"`
+	// OptInStatus is the DescribeRegions opt-in status: opt-in-not-required, opted-in or not-opted-in
+	OptInStatus string `json:"opt_in_status"`
+}
+
+// RequiresOptIn reports whether the region must be enabled for the account before deploying to it
+func (r RegionOption) RequiresOptIn() bool {
+	return r.OptInStatus == "not-opted-in"
+}
+
+// regionOptions converts DescribeRegions results to region options, sorted by code
+func regionOptions(regions []ec2types.Region) []RegionOption {
 	options := make([]RegionOption, 0, len(regions))
 	for _, region := range regions {
+		if region.RegionName == nil {
+			continue
+		}
 		options = append(options, RegionOption{
-			Code:        region,
-			Description: getRegionDescription(region),
+			Code:        *region.RegionName,
+			Description: getRegionDescription(*region.RegionName),
+			OptInStatus: aws.ToString(region.OptInStatus),
 		})
 	}
 
-	return options, nil
+	// Sort alphabetically for better UX
+	sort.Slice(options, func(i, j int) bool { return options[i].Code < options[j].Code })
+
+	return options
 }
 
-// RegionOption represents a region with description
-type RegionOption struct {
-	Code        string
-	Description string
+// enabledRegionOptions returns the regions that do not require opting in
+func enabledRegionOptions(options []RegionOption) []RegionOption {
+	enabled := make([]RegionOption, 0, len(options))
+	for _, option := range options {
+		if !option.RequiresOptIn() {
+			enabled = append(enabled, option)
+		}
+	}
+	return enabled
 }
 
 // getRegionDescription returns a human-readable description for common regions
@@ -0,0 +1,93 @@
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	acmtypes "github.com/aws/aws-sdk-go-v2/service/acm/types"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+)
+
+// HostedZone is a public Route53 hosted zone
+type HostedZone struct {
+	ID   string // Zone ID without the "/hostedzone/" prefix
+	Name string // Zone name without the trailing dot
+}
+
+// FindHostedZone returns the most specific public hosted zone that contains domain
+// (e.g. "example.com" for "app.example.com")
+func (c *AWSClient) FindHostedZone(ctx context.Context, domain string) (*HostedZone, error) {
+	client := route53.NewFromConfig(c.cfg)
+	domain = strings.TrimSuffix(strings.ToLower(domain), ".")
+
+	labels := strings.Split(domain, ".")
+	for i := 0; i < len(labels)-1; i++ {
+		name := strings.Join(labels[i:], ".")
+
+		output, err := client.ListHostedZonesByName(ctx, &route53.ListHostedZonesByNameInput{
+			DNSName:  aws.String(name),
+			MaxItems: aws.Int32(1),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list hosted zones: %w", err)
+		}
+
+		for _, zone := range output.HostedZones {
+			zoneName := strings.TrimSuffix(aws.ToString(zone.Name), ".")
+			if zoneName != name || (zone.Config != nil && zone.Config.PrivateZone) {
+				continue
+			}
+			return &HostedZone{
+				ID:   strings.TrimPrefix(aws.ToString(zone.Id), "/hostedzone/"),
+				Name: zoneName,
+			}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no public Route53 hosted zone found for %s", domain)
+}
+
+// FindCertificate returns the ARN of an issued ACM certificate covering domain in the region,
+// or an empty string when none exists
+func (c *AWSClient) FindCertificate(ctx context.Context, region, domain string) (string, error) {
+	regionalCfg := c.cfg.Copy()
+	regionalCfg.Region = region
+	client := acm.NewFromConfig(regionalCfg)
+	domain = strings.TrimSuffix(strings.ToLower(domain), ".")
+
+	paginator := acm.NewListCertificatesPaginator(client, &acm.ListCertificatesInput{
+		CertificateStatuses: []acmtypes.CertificateStatus{acmtypes.CertificateStatusIssued},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return "", fmt.Errorf("failed to list certificates: %w", err)
+		}
+
+		for _, cert := range page.CertificateSummaryList {
+			names := append([]string{aws.ToString(cert.DomainName)}, cert.SubjectAlternativeNameSummaries...)
+			for _, name := range names {
+				if certificateCovers(strings.ToLower(name), domain) {
+					return aws.ToString(cert.CertificateArn), nil
+				}
+			}
+		}
+	}
+
+	return "", nil
+}
+
+// certificateCovers checks if a certificate name (possibly a wildcard) matches domain
+func certificateCovers(name, domain string) bool {
+	if name == domain {
+		return true
+	}
+	if parent, ok := strings.CutPrefix(name, "*."); ok {
+		_, rest, found := strings.Cut(domain, ".")
+		return found && rest == parent
+	}
+	return false
+}
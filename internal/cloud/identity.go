@@ -0,0 +1,88 @@
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// RoleSessionName is the session name of the assumed role, shown in CloudTrail
+const RoleSessionName = "scai"
+
+var (
+	identityMu sync.RWMutex
+	// roleARN is the role assumed by the AWS clients and the generated configurations
+	roleARN string
+)
+
+// roleARNPattern matches an IAM role ARN (any partition)
+var roleARNPattern = regexp.MustCompile(`^arn:aws[a-z-]*:iam::\d{12}:role/[\w+=,.@/-]+$`)
+
+// ValidateRoleARN returns an error when arn is not an IAM role ARN
+func ValidateRoleARN(arn string) error {
+	if !roleARNPattern.MatchString(arn) {
+		return fmt.Errorf("invalid role ARN %q (e.g. arn:aws:iam::123456789012:role/deployer)", arn)
+	}
+	return nil
+}
+
+// SetIdentity selects the AWS identity of the deployments instead of the default credential
+// chain: the named profile of the shared config files, and the role assumed with it. AWS_PROFILE
+// is overridden (and the static credentials of the environment dropped, as they would take
+// precedence), for the Terraform and Pulumi processes to use the profile as well. Empty values
+// keep the default chain.
+func SetIdentity(profile, role string) error {
+	if role != "" {
+		if err := ValidateRoleARN(role); err != nil {
+			return err
+		}
+	}
+
+	if profile != "" {
+		if err := os.Setenv("AWS_PROFILE", profile); err != nil {
+			return fmt.Errorf("failed to set AWS_PROFILE: %w", err)
+		}
+		for _, env := range []string{"AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY", "AWS_SESSION_TOKEN"} {
+			if err := os.Unsetenv(env); err != nil {
+				return fmt.Errorf("failed to unset %s: %w", env, err)
+			}
+		}
+	}
+
+	identityMu.Lock()
+	roleARN = role
+	identityMu.Unlock()
+	return nil
+}
+
+// RoleARN returns the role assumed by the deployments, empty when none is
+func RoleARN() string {
+	identityMu.RLock()
+	defer identityMu.RUnlock()
+	return roleARN
+}
+
+// LoadAWSConfig loads the AWS configuration of region with the identity selected by
+// SetIdentity: the credentials of the profile (default chain otherwise), exchanged for those
+// of the role when one is assumed
+func LoadAWSConfig(ctx context.Context, region string) (aws.Config, error) {
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		return aws.Config{}, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	if role := RoleARN(); role != "" {
+		provider := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), role, func(o *stscreds.AssumeRoleOptions) {
+			o.RoleSessionName = RoleSessionName
+		})
+		cfg.Credentials = aws.NewCredentialsCache(provider)
+	}
+	return cfg, nil
+}
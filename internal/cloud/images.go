@@ -0,0 +1,48 @@
+package cloud
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// Image is an AMI usable by the instances of a deployment
+type Image struct {
+	ID    string
+	Name  string
+	State string // available, pending, failed, ...
+}
+
+// Available reports whether instances can be launched from the image
+func (i *Image) Available() bool {
+	return i.State == string(ec2types.ImageStateAvailable)
+}
+
+// FindImage returns the AMI imageID in a region, or nil when it does not exist there (AMIs
+// are regional) or is not shared with the account
+func (c *AWSClient) FindImage(ctx context.Context, region, imageID string) (*Image, error) {
+	client := ec2.NewFromConfig(c.cfg, func(o *ec2.Options) {
+		o.Region = region
+	})
+
+	// A filter, unlike ImageIds, returns no image instead of an error for an unknown ID
+	output, err := client.DescribeImages(ctx, &ec2.DescribeImagesInput{
+		Filters: []ec2types.Filter{{Name: aws.String("image-id"), Values: []string{imageID}}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe AMI %s in %s: %w", imageID, region, err)
+	}
+	if len(output.Images) == 0 {
+		return nil, nil
+	}
+
+	image := output.Images[0]
+	return &Image{
+		ID:    aws.ToString(image.ImageId),
+		Name:  aws.ToString(image.Name),
+		State: string(image.State),
+	}, nil
+}
@@ -0,0 +1,157 @@
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// instanceSizes orders the common instance sizes from smallest to largest
+var instanceSizes = []string{
+	"nano", "micro", "small", "medium", "large", "xlarge",
+	"2xlarge", "4xlarge", "8xlarge", "12xlarge", "16xlarge", "24xlarge",
+}
+
+// IsInstanceTypeAvailable checks whether an instance type is offered in a region.
+// Offerings are cached per region for the lifetime of the client.
+func (c *AWSClient) IsInstanceTypeAvailable(ctx context.Context, region, instanceType string) (bool, error) {
+	offerings, err := c.getInstanceTypeOfferings(ctx, region)
+	if err != nil {
+		return false, err
+	}
+
+	return offerings[instanceType], nil
+}
+
+// SuggestInstanceType returns the available instance type closest to the requested one,
+// or an empty string when no reasonable alternative is offered in the region
+func (c *AWSClient) SuggestInstanceType(ctx context.Context, region, instanceType string) (string, error) {
+	offerings, err := c.getInstanceTypeOfferings(ctx, region)
+	if err != nil {
+		return "", err
+	}
+
+	available := make([]string, 0, len(offerings))
+	for offered := range offerings {
+		available = append(available, offered)
+	}
+
+	return nearestInstanceType(instanceType, available), nil
+}
+
+// getInstanceTypeOfferings returns the set of instance types offered in a region
+func (c *AWSClient) getInstanceTypeOfferings(ctx context.Context, region string) (map[string]bool, error) {
+	if offerings, ok := c.offerings[region]; ok {
+		return offerings, nil
+	}
+
+	client := ec2.NewFromConfig(c.cfg, func(o *ec2.Options) {
+		o.Region = region
+	})
+
+	offerings := make(map[string]bool)
+	paginator := ec2.NewDescribeInstanceTypeOfferingsPaginator(client, &ec2.DescribeInstanceTypeOfferingsInput{
+		LocationType: ec2types.LocationTypeRegion,
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe instance type offerings in %s: %w", region, err)
+		}
+
+		for _, offering := range page.InstanceTypeOfferings {
+			offerings[string(offering.InstanceType)] = true
+		}
+	}
+
+	if c.offerings == nil {
+		c.offerings = make(map[string]map[string]bool)
+	}
+	c.offerings[region] = offerings
+
+	return offerings, nil
+}
+
+// nearestInstanceType picks the candidate closest to the requested type.
+// Candidates of the same family and size win, then the same class (e.g. "t", "m")
+// with the closest generation, then the same family with the closest size.
+func nearestInstanceType(requested string, candidates []string) string {
+	family, size, ok := strings.Cut(requested, ".")
+	if !ok {
+		return ""
+	}
+	class, generation := splitFamily(family)
+	sizeRank := indexOf(instanceSizes, size)
+
+	best := ""
+	bestScore := -1
+	sort.Strings(candidates) // Deterministic tie-breaking
+	for _, candidate := range candidates {
+		candFamily, candSize, ok := strings.Cut(candidate, ".")
+		if !ok || candidate == requested {
+			continue
+		}
+		candClass, candGeneration := splitFamily(candFamily)
+		if candClass != class {
+			continue
+		}
+
+		score := 0
+		if candSize == size {
+			score += 100
+		} else if candRank := indexOf(instanceSizes, candSize); sizeRank >= 0 && candRank >= 0 {
+			score += 50 - 10*abs(candRank-sizeRank)
+		} else {
+			continue
+		}
+
+		if candFamily == family {
+			score += 50
+		} else {
+			score += 20 - 5*abs(candGeneration-generation)
+		}
+
+		if score > bestScore {
+			best = candidate
+			bestScore = score
+		}
+	}
+
+	return best
+}
+
+// splitFamily splits an instance family like "t3a" into its class ("t") and generation (3)
+func splitFamily(family string) (string, int) {
+	i := 0
+	for i < len(family) && (family[i] < '0' || family[i] > '9') {
+		i++
+	}
+	j := i
+	for j < len(family) && family[j] >= '0' && family[j] <= '9' {
+		j++
+	}
+
+	generation, _ := strconv.Atoi(family[i:j])
+	return family[:i], generation
+}
+
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,107 @@
+package cloud
+
+import "testing"
+
+func TestNearestInstanceType(t *testing.T) {
+	tests := []struct {
+		name       string
+		requested  string
+		candidates []string
+		want       string
+	}{
+		{
+			name:       "same size, closest generation of the class",
+			requested:  "t3.micro",
+			candidates: []string{"m5.large", "t2.micro", "t3a.micro"},
+			want:       "t3a.micro",
+		},
+		{
+			name:       "same size wins over same family",
+			requested:  "t3.micro",
+			candidates: []string{"t3.small", "t2.micro"},
+			want:       "t2.micro",
+		},
+		{
+			name:       "newer generation of the same size",
+			requested:  "m5.large",
+			candidates: []string{"c5.large", "m5.xlarge", "m6i.large"},
+			want:       "m6i.large",
+		},
+		{
+			name:       "same family, closest size",
+			requested:  "m5.large",
+			candidates: []string{"m5.2xlarge", "m5.xlarge"},
+			want:       "m5.xlarge",
+		},
+		{
+			name:       "ties broken alphabetically",
+			requested:  "t3.micro",
+			candidates: []string{"t4g.micro", "t2.micro"},
+			want:       "t2.micro",
+		},
+		{
+			name:       "unknown size only matches the same size",
+			requested:  "m5.metal",
+			candidates: []string{"m5.large", "m6i.metal"},
+			want:       "m6i.metal",
+		},
+		{
+			name:       "other classes are not suggested",
+			requested:  "t3.micro",
+			candidates: []string{"c5.micro", "m5.micro"},
+			want:       "",
+		},
+		{
+			name:       "requested type is not suggested",
+			requested:  "t3.micro",
+			candidates: []string{"t3.micro"},
+			want:       "",
+		},
+		{
+			name:       "malformed requested type",
+			requested:  "t3micro",
+			candidates: []string{"t3.micro"},
+			want:       "",
+		},
+		{
+			name:       "no candidates",
+			requested:  "t3.micro",
+			candidates: nil,
+			want:       "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nearestInstanceType(tt.requested, tt.candidates); got != tt.want {
+				t.Errorf("nearestInstanceType(%q, %v) = %q, want %q", tt.requested, tt.candidates, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitFamily(t *testing.T) {
+	tests := []struct {
+		family         string
+		wantClass      string
+		wantGeneration int
+	}{
+		{"t3", "t", 3},
+		{"t3a", "t", 3},
+		{"m6i", "m", 6},
+		{"c7gn", "c", 7},
+		{"x2iedn", "x", 2},
+		{"inf2", "inf", 2},
+		{"mac", "mac", 0},
+		{"", "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.family, func(t *testing.T) {
+			class, generation := splitFamily(tt.family)
+			if class != tt.wantClass || generation != tt.wantGeneration {
+				t.Errorf("splitFamily(%q) = (%q, %d), want (%q, %d)", tt.family, class, generation, tt.wantClass, tt.wantGeneration)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package cloud
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// VPC is an existing VPC a deployment runs in
+type VPC struct {
+	ID        string
+	CIDRBlock string
+}
+
+// Subnet is a subnet of an existing VPC
+type Subnet struct {
+	ID               string
+	VPCID            string
+	AvailabilityZone string
+}
+
+// FindVPC returns the VPC vpcID in a region, or nil when it does not exist there
+func (c *AWSClient) FindVPC(ctx context.Context, region, vpcID string) (*VPC, error) {
+	client := ec2.NewFromConfig(c.cfg, func(o *ec2.Options) {
+		o.Region = region
+	})
+
+	// A filter, unlike VpcIds, returns no VPC instead of an error for an unknown ID
+	output, err := client.DescribeVpcs(ctx, &ec2.DescribeVpcsInput{
+		Filters: []ec2types.Filter{{Name: aws.String("vpc-id"), Values: []string{vpcID}}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe VPC %s in %s: %w", vpcID, region, err)
+	}
+	if len(output.Vpcs) == 0 {
+		return nil, nil
+	}
+
+	return &VPC{
+		ID:        aws.ToString(output.Vpcs[0].VpcId),
+		CIDRBlock: aws.ToString(output.Vpcs[0].CidrBlock),
+	}, nil
+}
+
+// FindSubnets returns the subnets of the VPC vpcID in a region among subnetIDs, all of them
+// when subnetIDs is empty: subnets that do not exist or belong to another VPC are left out
+func (c *AWSClient) FindSubnets(ctx context.Context, region, vpcID string, subnetIDs []string) ([]Subnet, error) {
+	client := ec2.NewFromConfig(c.cfg, func(o *ec2.Options) {
+		o.Region = region
+	})
+
+	filters := []ec2types.Filter{{Name: aws.String("vpc-id"), Values: []string{vpcID}}}
+	if len(subnetIDs) > 0 {
+		filters = append(filters, ec2types.Filter{Name: aws.String("subnet-id"), Values: subnetIDs})
+	}
+
+	var subnets []Subnet
+	paginator := ec2.NewDescribeSubnetsPaginator(client, &ec2.DescribeSubnetsInput{Filters: filters})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe the subnets of VPC %s in %s: %w", vpcID, region, err)
+		}
+		for _, subnet := range page.Subnets {
+			subnets = append(subnets, Subnet{
+				ID:               aws.ToString(subnet.SubnetId),
+				VPCID:            aws.ToString(subnet.VpcId),
+				AvailabilityZone: aws.ToString(subnet.AvailabilityZone),
+			})
+		}
+	}
+	return subnets, nil
+}
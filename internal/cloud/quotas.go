@@ -0,0 +1,167 @@
+package cloud
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/servicequotas"
+)
+
+// quotaWarningRatio is the usage ratio above which a quota is reported as near its limit
+const quotaWarningRatio = 0.8
+
+// quotaCheck describes a service quota and how many units a deployment consumes
+type quotaCheck struct {
+	Name        string
+	ServiceCode string
+	QuotaCode   string
+	Required    int
+	Usage       func(ctx context.Context, client *ec2.Client) (int, error)
+}
+
+// eksQuotaChecks lists the limits consumed by the EKS template (VPC with a single NAT gateway)
+var eksQuotaChecks = []quotaCheck{
+	{Name: "VPCs per region", ServiceCode: "vpc", QuotaCode: "L-F678F1CE", Required: 1, Usage: countVPCs},
+	{Name: "Internet gateways per region", ServiceCode: "vpc", QuotaCode: "L-A4707A72", Required: 1, Usage: countInternetGateways},
+	{Name: "Elastic IP addresses", ServiceCode: "ec2", QuotaCode: "L-0263D0A3", Required: 1, Usage: countElasticIPs},
+	// Quota is per AZ; counting the whole region is a conservative approximation
+	{Name: "NAT gateways per availability zone", ServiceCode: "vpc", QuotaCode: "L-FE5A380F", Required: 1, Usage: countNATGateways},
+}
+
+// CheckQuotas returns warnings for service quotas a deployment would exceed or come close to.
+// Only strategies that create their own network resources are checked. An error is returned
+// only when no quota could be checked at all (e.g. Service Quotas API not accessible).
+func (c *AWSClient) CheckQuotas(ctx context.Context, region, strategy string) ([]string, error) {
+	var checks []quotaCheck
+	if strategy == "kubernetes" {
+		checks = eksQuotaChecks
+	}
+	if len(checks) == 0 {
+		return nil, nil
+	}
+
+	regionalCfg := c.cfg.Copy()
+	regionalCfg.Region = region
+	quotasClient := servicequotas.NewFromConfig(regionalCfg)
+	ec2Client := ec2.NewFromConfig(regionalCfg)
+
+	var warnings []string
+	var lastErr error
+	checked := 0
+	for _, check := range checks {
+		limit, err := getQuotaValue(ctx, quotasClient, check.ServiceCode, check.QuotaCode)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		used, err := check.Usage(ctx, ec2Client)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		checked++
+
+		if warning := quotaWarning(check, used, limit); warning != "" {
+			warnings = append(warnings, warning)
+		}
+	}
+
+	if checked == 0 && lastErr != nil {
+		return nil, fmt.Errorf("failed to check service quotas: %w", lastErr)
+	}
+
+	return warnings, nil
+}
+
+// quotaWarning compares the usage of a quota, plus the units the deployment needs, to its
+// limit. Returns an empty string when the deployment stays below the warning ratio.
+func quotaWarning(check quotaCheck, used int, limit float64) string {
+	needed := used + check.Required
+	switch {
+	case float64(needed) > limit:
+		return fmt.Sprintf("%s: %d/%.0f in use, deployment needs %d more - request a quota increase", check.Name, used, limit, check.Required)
+	case float64(needed) >= limit*quotaWarningRatio:
+		return fmt.Sprintf("%s: %d/%.0f in use, close to the limit", check.Name, used, limit)
+	}
+	return ""
+}
+
+// getQuotaValue returns the applied quota value, falling back to the AWS default
+func getQuotaValue(ctx context.Context, client *servicequotas.Client, serviceCode, quotaCode string) (float64, error) {
+	applied, err := client.GetServiceQuota(ctx, &servicequotas.GetServiceQuotaInput{
+		ServiceCode: aws.String(serviceCode),
+		QuotaCode:   aws.String(quotaCode),
+	})
+	if err == nil && applied.Quota != nil && applied.Quota.Value != nil {
+		return *applied.Quota.Value, nil
+	}
+
+	// Quotas that were never adjusted may only be available as AWS defaults
+	defaults, err := client.GetAWSDefaultServiceQuota(ctx, &servicequotas.GetAWSDefaultServiceQuotaInput{
+		ServiceCode: aws.String(serviceCode),
+		QuotaCode:   aws.String(quotaCode),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get quota %s/%s: %w", serviceCode, quotaCode, err)
+	}
+	if defaults.Quota == nil || defaults.Quota.Value == nil {
+		return 0, fmt.Errorf("quota %s/%s has no value", serviceCode, quotaCode)
+	}
+
+	return *defaults.Quota.Value, nil
+}
+
+func countVPCs(ctx context.Context, client *ec2.Client) (int, error) {
+	count := 0
+	paginator := ec2.NewDescribeVpcsPaginator(client, &ec2.DescribeVpcsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("failed to describe VPCs: %w", err)
+		}
+		count += len(page.Vpcs)
+	}
+	return count, nil
+}
+
+func countInternetGateways(ctx context.Context, client *ec2.Client) (int, error) {
+	count := 0
+	paginator := ec2.NewDescribeInternetGatewaysPaginator(client, &ec2.DescribeInternetGatewaysInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("failed to describe internet gateways: %w", err)
+		}
+		count += len(page.InternetGateways)
+	}
+	return count, nil
+}
+
+func countElasticIPs(ctx context.Context, client *ec2.Client) (int, error) {
+	result, err := client.DescribeAddresses(ctx, &ec2.DescribeAddressesInput{
+		Filters: []ec2types.Filter{{Name: aws.String("domain"), Values: []string{"vpc"}}},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to describe addresses: %w", err)
+	}
+	return len(result.Addresses), nil
+}
+
+func countNATGateways(ctx context.Context, client *ec2.Client) (int, error) {
+	count := 0
+	paginator := ec2.NewDescribeNatGatewaysPaginator(client, &ec2.DescribeNatGatewaysInput{
+		Filter: []ec2types.Filter{{Name: aws.String("state"), Values: []string{"pending", "available"}}},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("failed to describe NAT gateways: %w", err)
+		}
+		count += len(page.NatGateways)
+	}
+	return count, nil
+}
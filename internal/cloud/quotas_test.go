@@ -0,0 +1,29 @@
+package cloud
+
+import "testing"
+
+func TestQuotaWarning(t *testing.T) {
+	check := quotaCheck{Name: "VPCs per region", Required: 1}
+	tests := []struct {
+		name  string
+		used  int
+		limit float64
+		want  string
+	}{
+		{"well below the limit", 1, 5, ""},
+		{"just below the warning ratio", 2, 5, ""},
+		{"at the warning ratio", 3, 5, "VPCs per region: 3/5 in use, close to the limit"},
+		{"reaches the limit", 4, 5, "VPCs per region: 4/5 in use, close to the limit"},
+		{"exceeds the limit", 5, 5, "VPCs per region: 5/5 in use, deployment needs 1 more - request a quota increase"},
+		{"raised limit", 5, 100, ""},
+		{"zero limit", 0, 0, "VPCs per region: 0/0 in use, deployment needs 1 more - request a quota increase"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := quotaWarning(check, tt.used, tt.limit); got != tt.want {
+				t.Errorf("quotaWarning(%d, %.0f) = %q, want %q", tt.used, tt.limit, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package cloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// RegionCacheTTL is how long the cached region list is considered fresh
+const RegionCacheTTL = 7 * 24 * time.Hour
+
+// regionCache is the on-disk format of ~/.scai/regions.json
+type regionCache struct {
+	FetchedAt time.Time      `json:"fetched_at"`
+	Regions   []RegionOption `json:"regions"`
+}
+
+// regionCachePath returns the path of the region cache file
+func regionCachePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	return filepath.Join(home, ".scai", "regions.json"), nil
+}
+
+// loadRegionCache returns the cached regions if the cache exists and has not expired
+func loadRegionCache() ([]RegionOption, bool) {
+	path, err := regionCachePath()
+	if err != nil {
+		return nil, false
+	}
+
+	data, err := os.ReadFile(path) // #nosec G304 - path is built from the user's home directory
+	if err != nil {
+		return nil, false
+	}
+
+	var cache regionCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return nil, false
+	}
+
+	if len(cache.Regions) == 0 || time.Since(cache.FetchedAt) > RegionCacheTTL {
+		return nil, false
+	}
+
+	// Caches written before the opt-in status was recorded are refreshed
+	for _, region := range cache.Regions {
+		if region.OptInStatus == "" {
+			return nil, false
+		}
+	}
+
+	return cache.Regions, true
+}
+
+// saveRegionCache writes the region list to disk
+func saveRegionCache(regions []RegionOption) error {
+	path, err := regionCachePath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(regionCache{
+		FetchedAt: time.Now(),
+		Regions:   regions,
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal region cache: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write region cache: %w", err)
+	}
+
+	return nil
+}
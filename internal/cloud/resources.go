@@ -0,0 +1,110 @@
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi"
+	"github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi/types"
+)
+
+const (
+	// TagDeploymentID is the tag key carrying the SCAI deployment ID on every resource
+	TagDeploymentID = "scia:deployment-id"
+
+	// TagApp is the tag key carrying the application name on every resource
+	TagApp = "scia:app"
+)
+
+// TaggedResource is an AWS resource carrying SCAI deployment tags
+type TaggedResource struct {
+	ARN          string
+	Service      string // AWS service from the ARN (e.g., ec2, eks, lambda)
+	ResourceType string // Resource type from the ARN (e.g., instance, cluster, function)
+	Region       string
+	DeploymentID string
+	AppName      string
+}
+
+// FindTaggedResources returns all resources in a region tagged with a SCAI deployment ID
+func (c *AWSClient) FindTaggedResources(ctx context.Context, region string) ([]TaggedResource, error) {
+	cfg := c.cfg.Copy()
+	cfg.Region = region
+	client := resourcegroupstaggingapi.NewFromConfig(cfg)
+
+	input := &resourcegroupstaggingapi.GetResourcesInput{
+		TagFilters: []types.TagFilter{
+			{Key: aws.String(TagDeploymentID)},
+		},
+	}
+
+	var resources []TaggedResource
+	paginator := resourcegroupstaggingapi.NewGetResourcesPaginator(client, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get tagged resources in %s: %w", region, err)
+		}
+
+		for _, mapping := range page.ResourceTagMappingList {
+			if mapping.ResourceARN == nil {
+				continue
+			}
+
+			resource := TaggedResource{
+				ARN:    *mapping.ResourceARN,
+				Region: region,
+			}
+			resource.Service, resource.ResourceType = parseARN(resource.ARN)
+
+			for _, tag := range mapping.Tags {
+				if tag.Key == nil || tag.Value == nil {
+					continue
+				}
+				switch *tag.Key {
+				case TagDeploymentID:
+					resource.DeploymentID = *tag.Value
+				case TagApp:
+					resource.AppName = *tag.Value
+				}
+			}
+
+			resources = append(resources, resource)
+		}
+	}
+
+	return resources, nil
+}
+
+// parseARN extracts the service and resource type from an ARN
+// (arn:partition:service:region:account:type/id or arn:partition:service:region:account:type:id)
+func parseARN(arn string) (service, resourceType string) {
+	parts := strings.SplitN(arn, ":", 6)
+	if len(parts) < 6 {
+		return "", ""
+	}
+
+	service = parts[2]
+	resourceType = parts[5]
+	if idx := strings.IndexAny(resourceType, "/:"); idx >= 0 {
+		resourceType = resourceType[:idx]
+	}
+
+	return service, resourceType
+}
+
+// InferStrategy guesses the deployment strategy from the resources of a deployment
+func InferStrategy(resources []TaggedResource) string {
+	strategy := "vm"
+	for _, r := range resources {
+		switch r.Service {
+		case "eks":
+			return "kubernetes"
+		case "lambda", "apigateway":
+			strategy = "serverless"
+		}
+	}
+	return strategy
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+)
+
+// JSONSchemaDraft is the JSON Schema version of JSONSchema
+const JSONSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema returns a JSON Schema of the configuration file, generated from the yaml
+// tags of Config, for editors to validate and autocomplete ~/.scai.yaml.
+// Fields with an enum tag (values separated by |) only accept these values.
+func JSONSchema() map[string]any {
+	configType := reflect.TypeFor[Config]()
+
+	schema := structSchema(configType, configType)
+	schema["$schema"] = JSONSchemaDraft
+	schema["title"] = "SCAI configuration"
+	return schema
+}
+
+// typeSchema returns the schema of a Go type; root (Config) is referenced rather than
+// expanded, as profiles nest configurations
+func typeSchema(t, root reflect.Type) map[string]any {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == root {
+		return map[string]any{"$ref": "#"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": typeSchema(t.Elem(), root)}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": typeSchema(t.Elem(), root)}
+	case reflect.Struct:
+		return structSchema(t, root)
+	default:
+		return map[string]any{}
+	}
+}
+
+// structSchema returns the schema of a struct: one property per yaml-tagged field,
+// no other property allowed so that typos are reported
+func structSchema(t, root reflect.Type) map[string]any {
+	properties := make(map[string]any)
+	for _, field := range reflect.VisibleFields(t) {
+		if !field.IsExported() || field.Anonymous {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+
+		fieldSchema := typeSchema(field.Type, root)
+		if enum := field.Tag.Get("enum"); enum != "" {
+			values := strings.Split(enum, "|")
+			if items, ok := fieldSchema["items"].(map[string]any); ok {
+				items["enum"] = values
+			} else {
+				fieldSchema["enum"] = values
+			}
+		}
+		properties[name] = fieldSchema
+	}
+
+	return map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+}
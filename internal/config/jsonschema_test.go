@@ -0,0 +1,42 @@
+package config
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestJSONSchema(t *testing.T) {
+	schema := JSONSchema()
+
+	// The schema must be valid JSON
+	if _, err := json.Marshal(schema); err != nil {
+		t.Fatalf("json.Marshal(JSONSchema()) error = %v", err)
+	}
+
+	properties := schema["properties"].(map[string]any)
+	for _, key := range []string{"version", "llm", "cloud", "terraform", "analyzer", "defaults", "rules", "profiles"} {
+		if _, ok := properties[key]; !ok {
+			t.Errorf("missing property %s", key)
+		}
+	}
+
+	llm := properties["llm"].(map[string]any)["properties"].(map[string]any)
+	if enum := llm["provider"].(map[string]any)["enum"]; !slices.Equal(enum.([]string), []string{"ollama", "gemini", "openai"}) {
+		t.Errorf("llm.provider enum = %v", enum)
+	}
+	if items := llm["fallback"].(map[string]any)["items"].(map[string]any); items["enum"] == nil {
+		t.Error("llm.fallback items have no enum")
+	}
+
+	defaults := properties["defaults"].(map[string]any)["properties"].(map[string]any)
+	if got := defaults["ec2_volume_size"].(map[string]any)["type"]; got != "integer" {
+		t.Errorf("defaults.ec2_volume_size type = %v, want integer", got)
+	}
+
+	// Profiles are whole configurations
+	profiles := properties["profiles"].(map[string]any)
+	if ref := profiles["additionalProperties"].(map[string]any)["$ref"]; ref != "#" {
+		t.Errorf("profiles additionalProperties = %v, want a reference to the root", profiles["additionalProperties"])
+	}
+}
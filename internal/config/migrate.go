@@ -0,0 +1,107 @@
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CurrentVersion is the current config file schema version
+const CurrentVersion = 1
+
+// migrations upgrade a config file by one version each: migrations[i] upgrades version i
+// (0 for files written before versioning) to version i+1
+var migrations = []func(section map[string]any){
+	migrateLegacyKeys,
+}
+
+// MigrateConfig upgrades the config file at path to the current schema version: keys are
+// renamed or moved and new fields get their defaults. The original file is kept as
+// <path>.v<version>.bak, whose path is returned ("" when the file is already current).
+func MigrateConfig(path string) (string, error) {
+	// #nosec G304 -- path is the user's config file
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	raw := map[string]any{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return "", fmt.Errorf("failed to parse config file: %w", err)
+	}
+
+	version, _ := raw["version"].(int)
+	if version == CurrentVersion {
+		return "", nil
+	}
+	if version < 0 || version > CurrentVersion {
+		return "", fmt.Errorf("config file version %d is not supported (current: %d): upgrade scia", version, CurrentVersion)
+	}
+
+	for v := version; v < CurrentVersion; v++ {
+		migrations[v](raw)
+		for _, profile := range sections(raw["profiles"]) {
+			migrations[v](profile)
+		}
+	}
+
+	// Decode over the defaults so fields missing from the file get their default value
+	migrated, err := yaml.Marshal(raw)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal migrated config: %w", err)
+	}
+	cfg := DefaultConfig()
+	if err := yaml.Unmarshal(migrated, cfg); err != nil {
+		return "", fmt.Errorf("failed to parse migrated config: %w", err)
+	}
+
+	backupPath := fmt.Sprintf("%s.v%d.bak", path, version)
+	if err := os.WriteFile(backupPath, data, 0o600); err != nil {
+		return "", fmt.Errorf("failed to back up config file: %w", err)
+	}
+
+	if err := writeConfigFile(path, cfg); err != nil {
+		return "", err
+	}
+	return backupPath, nil
+}
+
+// migrateLegacyKeys moves the keys of unversioned config files: terraform.binary (written by
+// scia init but never read) becomes terraform.bin and aws.region becomes cloud.default_region
+func migrateLegacyKeys(section map[string]any) {
+	if terraform, ok := section["terraform"].(map[string]any); ok {
+		if binary, ok := terraform["binary"]; ok {
+			if _, exists := terraform["bin"]; !exists {
+				terraform["bin"] = binary
+			}
+			delete(terraform, "binary")
+		}
+	}
+
+	if aws, ok := section["aws"].(map[string]any); ok {
+		if region, ok := aws["region"]; ok {
+			cloud, ok := section["cloud"].(map[string]any)
+			if !ok {
+				cloud = map[string]any{}
+				section["cloud"] = cloud
+			}
+			if _, exists := cloud["default_region"]; !exists {
+				cloud["default_region"] = region
+			}
+		}
+		delete(section, "aws")
+	}
+}
+
+// sections returns the profile sections of a raw profiles map
+func sections(profiles any) []map[string]any {
+	m, _ := profiles.(map[string]any)
+	result := make([]map[string]any, 0, len(m))
+	for _, profile := range m {
+		if section, ok := profile.(map[string]any); ok {
+			result = append(result, section)
+		}
+	}
+	return result
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestMigrateConfigUnversioned(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".scai.yaml")
+	original := `llm:
+  provider: gemini
+  gemini:
+    api_key: key
+aws:
+  region: us-west-2
+terraform:
+  binary: terraform
+  backend:
+    type: s3
+    s3_bucket: state
+profiles:
+  prod:
+    terraform:
+      binary: tofu
+`
+	if err := os.WriteFile(path, []byte(original), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	backupPath, err := MigrateConfig(path)
+	if err != nil {
+		t.Fatalf("MigrateConfig() error = %v", err)
+	}
+	if backup, err := os.ReadFile(backupPath); err != nil || string(backup) != original {
+		t.Errorf("backup %s does not hold the original file (err: %v)", backupPath, err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Version != CurrentVersion {
+		t.Errorf("Version = %d, want %d", cfg.Version, CurrentVersion)
+	}
+	if cfg.Terraform.Binary != "terraform" || cfg.Profiles["prod"].Terraform.Binary != "tofu" {
+		t.Errorf("terraform.binary not renamed: %q, profile %q", cfg.Terraform.Binary, cfg.Profiles["prod"].Terraform.Binary)
+	}
+	if cfg.Cloud.DefaultRegion != "us-west-2" {
+		t.Errorf("Cloud.DefaultRegion = %q, want us-west-2", cfg.Cloud.DefaultRegion)
+	}
+	// Missing fields get their defaults, existing values are kept
+	if cfg.Terraform.Backend.S3Key != "terraform.tfstate" || cfg.Terraform.Backend.S3Bucket != "state" {
+		t.Errorf("Backend = %+v", cfg.Terraform.Backend)
+	}
+	if cfg.LLM.Gemini.Model != "gemini-2.0-pro-exp" || cfg.LLM.Gemini.APIKey != "key" {
+		t.Errorf("Gemini = %+v", cfg.LLM.Gemini)
+	}
+
+	// Current files are left alone
+	if backupPath, err := MigrateConfig(path); err != nil || backupPath != "" {
+		t.Errorf("MigrateConfig() on a current file = %q, %v", backupPath, err)
+	}
+}
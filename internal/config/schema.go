@@ -2,14 +2,36 @@ package config
 
 // Config represents the SCAI configuration structure
 type Config struct {
+	Version   int             `yaml:"version,omitempty"` // Schema version (CurrentVersion), unset in profiles
 	LLM       LLMConfig       `yaml:"llm"`
 	Cloud     CloudConfig     `yaml:"cloud"`
 	Terraform TerraformConfig `yaml:"terraform"`
+	Iac       IacConfig       `yaml:"iac,omitempty"`
+	Analyzer  AnalyzerConfig  `yaml:"analyzer,omitempty"`
+	Defaults  DefaultsConfig  `yaml:"defaults,omitempty"`
+	Rules     RulesConfig     `yaml:"rules,omitempty"`
+	WorkDir   WorkDirConfig   `yaml:"workdir,omitempty"`
+	Network   NetworkConfig   `yaml:"network,omitempty"`
+
+	// Named profiles selected with --profile or SCAI_PROFILE; the settings of the selected
+	// profile override the ones above
+	Profiles map[string]*Config `yaml:"profiles,omitempty"`
+}
+
+// SetProfile adds or replaces a named profile (nested profiles are dropped)
+func (c *Config) SetProfile(name string, profile *Config) {
+	if c.Profiles == nil {
+		c.Profiles = make(map[string]*Config)
+	}
+	profile.Version = 0
+	profile.Profiles = nil
+	c.Profiles[name] = profile
 }
 
 // LLMConfig holds LLM provider configuration
 type LLMConfig struct {
-	Provider string       `yaml:"provider"` // ollama, gemini, openai
+	Provider string       `yaml:"provider" enum:"ollama|gemini|openai"`           // ollama, gemini, openai
+	Fallback []string     `yaml:"fallback,omitempty" enum:"ollama|gemini|openai"` // Providers tried in order when the configured one fails
 	Ollama   OllamaConfig `yaml:"ollama,omitempty"`
 	Gemini   GeminiConfig `yaml:"gemini,omitempty"`
 	OpenAI   OpenAIConfig `yaml:"openai,omitempty"`
@@ -20,6 +42,8 @@ type OllamaConfig struct {
 	URL       string `yaml:"url,omitempty"`        // http://localhost:11434 or remote URL
 	Model     string `yaml:"model,omitempty"`      // qwen2.5-coder:7b
 	UseDocker bool   `yaml:"use_docker,omitempty"` // Whether to use Docker
+	KeepAlive string `yaml:"keep_alive,omitempty"` // How long the model stays loaded after a request (5m)
+	Preload   bool   `yaml:"preload,omitempty"`    // Load the model before the first prompt
 }
 
 // GeminiConfig holds Google Gemini configuration
@@ -36,27 +60,113 @@ type OpenAIConfig struct {
 
 // CloudConfig holds cloud provider configuration
 type CloudConfig struct {
-	Provider      string `yaml:"provider"`       // aws, gcp
-	DefaultRegion string `yaml:"default_region"` // AWS region (e.g., us-east-1)
+	Provider      string            `yaml:"provider" enum:"aws|gcp"` // aws, gcp
+	DefaultRegion string            `yaml:"default_region"`          // AWS region (e.g., us-east-1)
+	DefaultTags   map[string]string `yaml:"default_tags,omitempty"`  // Tags applied to every deployed resource
+
+	// AWS identity (default credential chain otherwise)
+	AWSProfile string `yaml:"aws_profile,omitempty"`  // Named profile of the shared config files (--aws-profile)
+	AWSRoleARN string `yaml:"aws_role_arn,omitempty"` // Role assumed for the whole deploy (--aws-role-arn)
+
+	// GCP
+	ProjectID       string `yaml:"project_id,omitempty"`       // GCP project ID, required for gcp
+	CredentialsFile string `yaml:"credentials_file,omitempty"` // Service account key file (default: Application Default Credentials)
 }
 
 // TerraformConfig holds Terraform/OpenTofu configuration
 type TerraformConfig struct {
 	Backend BackendConfig `yaml:"backend"`
-	Binary  string        `yaml:"binary"` // tofu or terraform
+	Binary  string        `yaml:"bin"` // tofu or terraform
+	EKS     EKSConfig     `yaml:"eks,omitempty"`
+
+	APIGateway APIGatewayConfig `yaml:"api_gateway,omitempty"`
+
+	AWSProviderVersion string `yaml:"aws_provider_version,omitempty"` // AWS provider version constraint (e.g. ~> 6.0)
+}
+
+// IacConfig holds the infrastructure as code engine configuration
+type IacConfig struct {
+	Engine string       `yaml:"engine,omitempty" enum:"terraform|pulumi"` // terraform (default), or pulumi (vm strategy only)
+	Pulumi PulumiConfig `yaml:"pulumi,omitempty"`
+}
+
+// PulumiConfig holds the Pulumi configuration of the pulumi engine
+type PulumiConfig struct {
+	Binary string `yaml:"bin,omitempty"` // pulumi; the state backend is PULUMI_BACKEND_URL (default: ~/.scai/pulumi)
+}
+
+// EKSConfig holds EKS cluster defaults
+type EKSConfig struct {
+	Version string `yaml:"version,omitempty"` // Kubernetes version (e.g. 1.33)
+}
+
+// APIGatewayConfig holds the API Gateway defaults of serverless deployments (deploy flags
+// override them)
+type APIGatewayConfig struct {
+	CORSAllowOrigins []string `yaml:"cors_allow_origins,omitempty"` // Allowed origins (default: * for development)
+	CORSAllowMethods []string `yaml:"cors_allow_methods,omitempty"` // Allowed methods (default: *)
+	CORSAllowHeaders []string `yaml:"cors_allow_headers,omitempty"` // Allowed headers (default: *)
+	AuthorizerARN    string   `yaml:"authorizer_arn,omitempty"`     // Lambda authorizer of the routes
 }
 
 // BackendConfig holds Terraform backend configuration
 type BackendConfig struct {
-	Type     string `yaml:"type"`      // s3
-	S3Bucket string `yaml:"s3_bucket"` // S3 bucket name for state
-	S3Region string `yaml:"s3_region"` // S3 bucket region
-	S3Key    string `yaml:"s3_key"`    // State file path in bucket
+	Type     string `yaml:"type" enum:"s3"` // s3
+	S3Bucket string `yaml:"s3_bucket"`      // S3 bucket name for state
+	S3Region string `yaml:"s3_region"`      // S3 bucket region
+	S3Key    string `yaml:"s3_key"`         // State file path in bucket
+}
+
+// AnalyzerConfig holds repository analysis configuration
+type AnalyzerConfig struct {
+	MaxDepth   int      `yaml:"max_depth,omitempty"`   // Directory depth searched for project files
+	IgnoreDirs []string `yaml:"ignore_dirs,omitempty"` // Directory names skipped during discovery (replaces defaults)
+
+	// Extraction limits of zip archives
+	ZipMaxSizeMB     int `yaml:"zip_max_size_mb,omitempty"`      // Total uncompressed size
+	ZipMaxFileSizeMB int `yaml:"zip_max_file_size_mb,omitempty"` // Uncompressed size of a single file
+	ZipMaxFiles      int `yaml:"zip_max_files,omitempty"`        // Files and directories
+}
+
+// RulesConfig holds the deployment decision rules configuration
+type RulesConfig struct {
+	Path string `yaml:"path,omitempty"` // User-authored rules YAML replacing configs/deployment_rules.yaml
+}
+
+// WorkDirConfig holds the work directory configuration
+type WorkDirConfig struct {
+	Path   string `yaml:"path,omitempty"`   // Where repositories are cloned and Terraform generated (--work-dir)
+	Retain bool   `yaml:"retain,omitempty"` // Keep the cloned repository and exported Terraform after a successful deploy
+}
+
+// NetworkConfig holds the outbound connections configuration
+type NetworkConfig struct {
+	Proxy string `yaml:"proxy,omitempty"` // HTTP(S) proxy URL overriding HTTP_PROXY and HTTPS_PROXY (NO_PROXY still applies)
+}
+
+// DefaultsConfig holds the sizing defaults of deployments (zero values keep the
+// built-in defaults; deploy flags override them)
+type DefaultsConfig struct {
+	EC2InstanceType           string `yaml:"ec2_instance_type,omitempty"`           // t3.micro
+	EC2VolumeSize             int    `yaml:"ec2_volume_size,omitempty"`             // GB, 30
+	LambdaMemory              int    `yaml:"lambda_memory,omitempty"`               // MB, 512
+	LambdaTimeout             int    `yaml:"lambda_timeout,omitempty"`              // Seconds, 30
+	LambdaReservedConcurrency int    `yaml:"lambda_reserved_concurrency,omitempty"` // 0 = unreserved
+	LambdaArchitecture        string `yaml:"lambda_architecture,omitempty"`         // x86_64 or arm64
+	EKSNodeType               string `yaml:"eks_node_type,omitempty"`               // t3.medium
+	EKSMinNodes               int    `yaml:"eks_min_nodes,omitempty"`               // 1
+	EKSMaxNodes               int    `yaml:"eks_max_nodes,omitempty"`               // 3
+	EKSDesiredNodes           int    `yaml:"eks_desired_nodes,omitempty"`           // 2
+	EKSNodeVolumeSize         int    `yaml:"eks_node_volume_size,omitempty"`        // GB, 30
+	DBInstanceClass           string `yaml:"db_instance_class,omitempty"`           // db.t3.micro
+	DBStorage                 int    `yaml:"db_storage,omitempty"`                  // GB, 20
+	LogRetentionDays          int    `yaml:"log_retention_days,omitempty"`          // CloudWatch log groups, 0 = strategy default
 }
 
 // DefaultConfig returns a configuration with sensible defaults
 func DefaultConfig() *Config {
 	return &Config{
+		Version: CurrentVersion,
 		LLM: LLMConfig{
 			Provider: "ollama",
 			Ollama: OllamaConfig{
@@ -0,0 +1,230 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/zalando/go-keyring"
+	"golang.org/x/crypto/scrypt"
+)
+
+// Methods used to protect the API keys of the config file
+const (
+	SecretMethodKeyring    = "keyring"    // Stored in the OS keyring, the file only references it
+	SecretMethodPassphrase = "passphrase" // Encrypted in the file with a passphrase-derived key
+)
+
+// PassphraseEnv holds the passphrase of encrypted API keys (prompted for when unset)
+const PassphraseEnv = "SCAI_CONFIG_PASSPHRASE"
+
+const (
+	keyringPrefix   = "keyring:"
+	encryptedPrefix = "enc:v1:" // base64(salt | nonce | AES-256-GCM ciphertext)
+	keyringService  = "scai"
+
+	saltSize = 16
+	keySize  = 32
+)
+
+// PassphraseFunc returns the passphrase of encrypted API keys; it is only called when
+// an encrypted value has to be read or written
+type PassphraseFunc func() (string, error)
+
+// IsProtectedSecret reports whether value is an encrypted API key or a keyring reference
+func IsProtectedSecret(value string) bool {
+	return strings.HasPrefix(value, keyringPrefix) || strings.HasPrefix(value, encryptedPrefix)
+}
+
+// ResolveSecret returns the cleartext of an API key from the config file: plain values
+// are returned as is, keyring references are looked up and encrypted values decrypted
+func ResolveSecret(value string, passphrase PassphraseFunc) (string, error) {
+	switch {
+	case strings.HasPrefix(value, keyringPrefix):
+		account := strings.TrimPrefix(value, keyringPrefix)
+		secret, err := keyring.Get(keyringService, account)
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s from the OS keyring: %w", account, err)
+		}
+		return secret, nil
+	case strings.HasPrefix(value, encryptedPrefix):
+		pass, err := passphrase()
+		if err != nil {
+			return "", err
+		}
+		return decryptSecret(value, pass)
+	default:
+		return value, nil
+	}
+}
+
+// EncryptSecrets protects the cleartext API keys of cfg and its profiles with the given
+// method and returns how many were protected
+func EncryptSecrets(cfg *Config, method string, passphrase PassphraseFunc) (int, error) {
+	if method != SecretMethodKeyring && method != SecretMethodPassphrase {
+		return 0, fmt.Errorf("invalid method %q: expected %s or %s", method, SecretMethodKeyring, SecretMethodPassphrase)
+	}
+
+	var pass string
+	count := 0
+	for _, field := range secretFields(cfg) {
+		if *field.value == "" || IsProtectedSecret(*field.value) {
+			continue
+		}
+
+		if method == SecretMethodKeyring {
+			if err := keyring.Set(keyringService, field.account, *field.value); err != nil {
+				return count, fmt.Errorf("failed to store %s in the OS keyring: %w", field.account, err)
+			}
+			*field.value = keyringPrefix + field.account
+		} else {
+			if pass == "" {
+				var err error
+				if pass, err = passphrase(); err != nil {
+					return count, err
+				}
+			}
+			encrypted, err := encryptSecret(*field.value, pass)
+			if err != nil {
+				return count, err
+			}
+			*field.value = encrypted
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+// DecryptSecrets replaces the protected API keys of cfg and its profiles with their
+// cleartext and returns how many were replaced
+func DecryptSecrets(cfg *Config, passphrase PassphraseFunc) (int, error) {
+	// Ask for the passphrase once for all encrypted values
+	var pass string
+	cached := func() (string, error) {
+		if pass == "" {
+			var err error
+			if pass, err = passphrase(); err != nil {
+				return "", err
+			}
+		}
+		return pass, nil
+	}
+
+	count := 0
+	for _, field := range secretFields(cfg) {
+		if !IsProtectedSecret(*field.value) {
+			continue
+		}
+
+		secret, err := ResolveSecret(*field.value, cached)
+		if err != nil {
+			return count, err
+		}
+		*field.value = secret
+		count++
+	}
+
+	return count, nil
+}
+
+// secretField is an API key of the config file and its OS keyring account name
+type secretField struct {
+	account string
+	value   *string
+}
+
+// secretFields returns the API key fields of cfg and its profiles (sorted by name)
+func secretFields(cfg *Config) []secretField {
+	fields := []secretField{
+		{"gemini", &cfg.LLM.Gemini.APIKey},
+		{"openai", &cfg.LLM.OpenAI.APIKey},
+	}
+
+	names := make([]string, 0, len(cfg.Profiles))
+	for name := range cfg.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		profile := cfg.Profiles[name]
+		if profile == nil {
+			continue
+		}
+		fields = append(fields,
+			secretField{"profiles/" + name + "/gemini", &profile.LLM.Gemini.APIKey},
+			secretField{"profiles/" + name + "/openai", &profile.LLM.OpenAI.APIKey},
+		)
+	}
+	return fields
+}
+
+// encryptSecret encrypts plaintext with AES-256-GCM and a scrypt key derived from passphrase
+func encryptSecret(plaintext, passphrase string) (string, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	gcm, err := newGCM(passphrase, salt)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	data := append(salt, nonce...)
+	data = gcm.Seal(data, nonce, []byte(plaintext), nil)
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(data), nil
+}
+
+// decryptSecret decrypts a value produced by encryptSecret
+func decryptSecret(value, passphrase string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, encryptedPrefix))
+	if err != nil || len(data) < saltSize {
+		return "", errors.New("invalid encrypted API key")
+	}
+
+	gcm, err := newGCM(passphrase, data[:saltSize])
+	if err != nil {
+		return "", err
+	}
+
+	data = data[saltSize:]
+	if len(data) < gcm.NonceSize() {
+		return "", errors.New("invalid encrypted API key")
+	}
+
+	plaintext, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", errors.New("failed to decrypt API key: wrong passphrase?")
+	}
+	return string(plaintext), nil
+}
+
+// newGCM returns an AES-256-GCM cipher keyed with scrypt(passphrase, salt)
+func newGCM(passphrase string, salt []byte) (cipher.AEAD, error) {
+	if passphrase == "" {
+		return nil, errors.New("passphrase is empty")
+	}
+
+	key, err := scrypt.Key([]byte(passphrase), salt, 1<<15, 8, 1, keySize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEncryptSecretsPassphrase(t *testing.T) {
+	passphrase := func() (string, error) { return "correct horse", nil }
+
+	cfg := DefaultConfig()
+	cfg.LLM.OpenAI.APIKey = "sk-top-level"
+	cfg.SetProfile("prod", &Config{LLM: LLMConfig{Provider: "gemini", Gemini: GeminiConfig{APIKey: "gemini-prod"}}})
+
+	count, err := EncryptSecrets(cfg, SecretMethodPassphrase, passphrase)
+	if err != nil {
+		t.Fatalf("EncryptSecrets() error = %v", err)
+	}
+	if count != 2 {
+		t.Errorf("EncryptSecrets() count = %d, want 2", count)
+	}
+	for _, value := range []string{cfg.LLM.OpenAI.APIKey, cfg.Profiles["prod"].LLM.Gemini.APIKey} {
+		if !IsProtectedSecret(value) || strings.Contains(value, "sk-") || strings.Contains(value, "gemini-") {
+			t.Errorf("API key not encrypted: %q", value)
+		}
+	}
+
+	if _, err := ResolveSecret(cfg.LLM.OpenAI.APIKey, func() (string, error) { return "wrong", nil }); err == nil {
+		t.Error("ResolveSecret() with a wrong passphrase succeeded")
+	}
+
+	if _, err := DecryptSecrets(cfg, passphrase); err != nil {
+		t.Fatalf("DecryptSecrets() error = %v", err)
+	}
+	if cfg.LLM.OpenAI.APIKey != "sk-top-level" || cfg.Profiles["prod"].LLM.Gemini.APIKey != "gemini-prod" {
+		t.Errorf("DecryptSecrets() = %q, %q", cfg.LLM.OpenAI.APIKey, cfg.Profiles["prod"].LLM.Gemini.APIKey)
+	}
+}
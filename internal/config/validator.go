@@ -2,17 +2,31 @@ package config
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
+
+	"github.com/Smana/scai/internal/terraform"
 )
 
 var (
 	// AWS region pattern (e.g., us-east-1, eu-west-3)
 	awsRegionPattern = regexp.MustCompile(`^[a-z]{2}-[a-z]+-\d$`)
 
+	// IAM role ARN pattern (e.g., arn:aws:iam::123456789012:role/deployer)
+	iamRoleARNPattern = regexp.MustCompile(`^arn:aws[a-z-]*:iam::\d{12}:role/[\w+=,.@/-]+$`)
+
 	// S3 bucket name validation
 	// Bucket names must be 3-63 characters, lowercase, no underscores
 	s3BucketPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,61}[a-z0-9]$`)
+
+	// GCP region pattern (e.g., us-central1, europe-west9)
+	gcpRegionPattern = regexp.MustCompile(`^[a-z]+-[a-z]+\d+$`)
+
+	// GCP project ID validation
+	// Project IDs must be 6-30 characters, start with a letter, lowercase, no trailing hyphen
+	gcpProjectIDPattern = regexp.MustCompile(`^[a-z][a-z0-9-]{4,28}[a-z0-9]$`)
 )
 
 // ValidateConfig validates the entire configuration
@@ -28,16 +42,24 @@ func ValidateConfig(cfg *Config) error {
 	}
 
 	// Validate Terraform configuration
-	if err := validateTerraform(&cfg.Terraform); err != nil {
+	if err := validateTerraform(&cfg.Terraform, cfg.Cloud.Provider); err != nil {
 		return fmt.Errorf("terraform config invalid: %w", err)
 	}
 
+	// Validate infrastructure as code engine
+	if err := validateIac(&cfg.Iac); err != nil {
+		return fmt.Errorf("iac config invalid: %w", err)
+	}
+
+	// Validate sizing defaults
+	if err := validateDefaults(&cfg.Defaults); err != nil {
+		return fmt.Errorf("defaults config invalid: %w", err)
+	}
+
 	return nil
 }
 
 // validateLLM validates LLM provider configuration
-//
-//nolint:gocyclo // Validation logic requires checking each provider's specific requirements
 func validateLLM(llm *LLMConfig) error {
 	// Provider must be set
 	if llm.Provider == "" {
@@ -49,9 +71,28 @@ func validateLLM(llm *LLMConfig) error {
 	if !contains(validProviders, llm.Provider) {
 		return fmt.Errorf("llm provider must be one of: %s", strings.Join(validProviders, ", "))
 	}
+	if err := validateLLMProvider(llm, llm.Provider); err != nil {
+		return err
+	}
+
+	// Fallback providers need their own settings
+	for _, provider := range llm.Fallback {
+		if !contains(validProviders, provider) {
+			return fmt.Errorf("llm fallback %q must be one of: %s", provider, strings.Join(validProviders, ", "))
+		}
+		if err := validateLLMProvider(llm, provider); err != nil {
+			return fmt.Errorf("llm fallback: %w", err)
+		}
+	}
 
-	// Provider-specific validation
-	switch llm.Provider {
+	return nil
+}
+
+// validateLLMProvider validates the settings of an LLM provider
+//
+//nolint:gocyclo // Validation logic requires checking each provider's specific requirements
+func validateLLMProvider(llm *LLMConfig, provider string) error {
+	switch provider {
 	case "ollama":
 		if llm.Ollama.URL == "" {
 			return fmt.Errorf("ollama url is required when using ollama provider")
@@ -101,28 +142,134 @@ func validateCloud(cloud *CloudConfig) error {
 		if !awsRegionPattern.MatchString(cloud.DefaultRegion) {
 			return fmt.Errorf("invalid aws region format: %s (expected format: us-east-1)", cloud.DefaultRegion)
 		}
+
+		if cloud.AWSRoleARN != "" {
+			if !iamRoleARNPattern.MatchString(cloud.AWSRoleARN) {
+				return fmt.Errorf("invalid aws_role_arn: %s (expected format: arn:aws:iam::123456789012:role/deployer)", cloud.AWSRoleARN)
+			}
+		}
+	}
+
+	// GCP-specific validation
+	if cloud.Provider == "gcp" {
+		if cloud.ProjectID == "" {
+			return fmt.Errorf("project_id is required for gcp provider")
+		}
+
+		if !gcpProjectIDPattern.MatchString(cloud.ProjectID) {
+			return fmt.Errorf("invalid gcp project id: %s (must be 6-30 lowercase letters, digits or hyphens, starting with a letter)", cloud.ProjectID)
+		}
+
+		if cloud.DefaultRegion == "" {
+			return fmt.Errorf("default_region is required for gcp provider")
+		}
+
+		// Basic format validation for GCP region
+		if !gcpRegionPattern.MatchString(cloud.DefaultRegion) {
+			return fmt.Errorf("invalid gcp region format: %s (expected format: us-central1)", cloud.DefaultRegion)
+		}
+
+		// Credentials file is optional (Application Default Credentials are used otherwise)
+		if cloud.CredentialsFile != "" {
+			if _, err := os.Stat(cloud.CredentialsFile); err != nil {
+				return fmt.Errorf("credentials_file not readable: %w", err)
+			}
+		}
 	}
 
 	return nil
 }
 
-// validateTerraform validates Terraform configuration
-func validateTerraform(tf *TerraformConfig) error {
+// validateIac validates the infrastructure as code engine configuration
+func validateIac(iac *IacConfig) error {
+	switch iac.Engine {
+	case "", "terraform", "pulumi":
+	default:
+		return fmt.Errorf("iac engine must be one of: terraform, pulumi")
+	}
+
+	if iac.Pulumi.Binary != "" && filepath.Base(iac.Pulumi.Binary) != "pulumi" {
+		return fmt.Errorf("pulumi binary must be 'pulumi'")
+	}
+	return nil
+}
+
+// validateTerraform validates Terraform configuration; the S3 state backend is only
+// configured for the aws provider
+func validateTerraform(tf *TerraformConfig, provider string) error {
 	// Binary must be set
 	if tf.Binary == "" {
 		return fmt.Errorf("terraform binary is required")
 	}
 
-	// Validate binary is either terraform or tofu
-	if tf.Binary != "terraform" && tf.Binary != "tofu" {
+	// Validate binary is either terraform or tofu (possibly an absolute path, e.g. installed by scia init --install-deps)
+	if name := filepath.Base(tf.Binary); name != "terraform" && name != "tofu" {
 		return fmt.Errorf("terraform binary must be 'terraform' or 'tofu'")
 	}
 
 	// Validate backend configuration
-	if err := validateBackend(&tf.Backend); err != nil {
-		return fmt.Errorf("backend config invalid: %w", err)
+	if provider == "aws" {
+		if err := validateBackend(&tf.Backend); err != nil {
+			return fmt.Errorf("backend config invalid: %w", err)
+		}
 	}
 
+	// EKS version is optional (defaults to terraform.DefaultEKSVersion)
+	if tf.EKS.Version != "" {
+		if err := terraform.ValidateEKSVersion(tf.EKS.Version); err != nil {
+			return fmt.Errorf("eks config invalid: %w", err)
+		}
+	}
+
+	if err := validateAPIGateway(&tf.APIGateway); err != nil {
+		return fmt.Errorf("api_gateway config invalid: %w", err)
+	}
+
+	// AWS provider version is optional (defaults to terraform.DefaultAWSProviderVersion)
+	if tf.AWSProviderVersion != "" {
+		if err := terraform.ValidateProviderVersion(tf.AWSProviderVersion); err != nil {
+			return fmt.Errorf("aws_provider_version invalid: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// validateAPIGateway validates the CORS origins and methods and the authorizer ARN
+func validateAPIGateway(apiGateway *APIGatewayConfig) error {
+	for _, origin := range apiGateway.CORSAllowOrigins {
+		if err := terraform.ValidateCORSOrigin(origin); err != nil {
+			return err
+		}
+	}
+	for _, method := range apiGateway.CORSAllowMethods {
+		if err := terraform.ValidateCORSMethod(method); err != nil {
+			return err
+		}
+	}
+	if apiGateway.AuthorizerARN != "" {
+		return terraform.ValidateLambdaARN(apiGateway.AuthorizerARN)
+	}
+	return nil
+}
+
+// validateDefaults validates the sizing defaults that have AWS limits (zero values are unset)
+func validateDefaults(defaults *DefaultsConfig) error {
+	if defaults.LambdaMemory != 0 {
+		if err := terraform.ValidateLambdaMemory(defaults.LambdaMemory); err != nil {
+			return err
+		}
+	}
+	if defaults.LambdaTimeout != 0 {
+		if err := terraform.ValidateLambdaTimeout(defaults.LambdaTimeout); err != nil {
+			return err
+		}
+	}
+	if defaults.LogRetentionDays != 0 {
+		if err := terraform.ValidateLogRetentionDays(defaults.LogRetentionDays); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -0,0 +1,115 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidateCloudGCP(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "key.json")
+	if err := os.WriteFile(keyFile, []byte("{}"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		cloud   CloudConfig
+		wantErr bool
+	}{
+		{"valid", CloudConfig{Provider: "gcp", ProjectID: "my-project-123", DefaultRegion: "europe-west1"}, false},
+		{"valid with credentials file", CloudConfig{Provider: "gcp", ProjectID: "my-project-123", DefaultRegion: "us-central1", CredentialsFile: keyFile}, false},
+		{"missing project id", CloudConfig{Provider: "gcp", DefaultRegion: "us-central1"}, true},
+		{"invalid project id", CloudConfig{Provider: "gcp", ProjectID: "My_Project", DefaultRegion: "us-central1"}, true},
+		{"aws region", CloudConfig{Provider: "gcp", ProjectID: "my-project-123", DefaultRegion: "us-east-1"}, true},
+		{"missing credentials file", CloudConfig{Provider: "gcp", ProjectID: "my-project-123", DefaultRegion: "us-central1", CredentialsFile: filepath.Join(t.TempDir(), "missing.json")}, true},
+		{"aws ignores project id", CloudConfig{Provider: "aws", DefaultRegion: "us-east-1"}, false},
+		{"aws role", CloudConfig{Provider: "aws", DefaultRegion: "us-east-1", AWSProfile: "prod", AWSRoleARN: "arn:aws:iam::123456789012:role/ci/deployer"}, false},
+		{"invalid aws role", CloudConfig{Provider: "aws", DefaultRegion: "us-east-1", AWSRoleARN: "arn:aws:iam::123456789012:user/deployer"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateCloud(&tt.cloud)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateCloud() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateLLMFallback(t *testing.T) {
+	ollama := OllamaConfig{URL: "http://localhost:11434", Model: "qwen2.5-coder:7b"}
+	gemini := GeminiConfig{APIKey: "key", Model: "gemini-2.0-pro-exp"}
+
+	tests := []struct {
+		name    string
+		llm     LLMConfig
+		wantErr bool
+	}{
+		{"no fallback", LLMConfig{Provider: "ollama", Ollama: ollama}, false},
+		{"gemini then ollama", LLMConfig{Provider: "gemini", Fallback: []string{"ollama"}, Gemini: gemini, Ollama: ollama}, false},
+		{"fallback without credentials", LLMConfig{Provider: "ollama", Fallback: []string{"openai"}, Ollama: ollama}, true},
+		{"unknown fallback", LLMConfig{Provider: "ollama", Fallback: []string{"claude"}, Ollama: ollama}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateLLM(&tt.llm)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateLLM() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateConfigGCPSkipsS3Backend(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Cloud = CloudConfig{Provider: "gcp", ProjectID: "my-project-123", DefaultRegion: "us-central1"}
+
+	if err := ValidateConfig(cfg); err != nil {
+		t.Errorf("ValidateConfig() error = %v", err)
+	}
+}
+
+func TestValidateTerraformBinaryPath(t *testing.T) {
+	tests := []struct {
+		binary  string
+		wantErr bool
+	}{
+		{"tofu", false},
+		{"terraform", false},
+		{"/home/user/.scai/bin/tofu", false},
+		{"/usr/bin/bash", true},
+		{"", true},
+	}
+
+	for _, tt := range tests {
+		tf := &TerraformConfig{Binary: tt.binary}
+		if err := validateTerraform(tf, "gcp"); (err != nil) != tt.wantErr {
+			t.Errorf("validateTerraform(%q) error = %v, wantErr %v", tt.binary, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateDefaultsLambdaLimits(t *testing.T) {
+	tests := []struct {
+		name     string
+		defaults DefaultsConfig
+		wantErr  bool
+	}{
+		{"unset", DefaultsConfig{}, false},
+		{"within limits", DefaultsConfig{LambdaMemory: 10240, LambdaTimeout: 900}, false},
+		{"memory below minimum", DefaultsConfig{LambdaMemory: 64}, true},
+		{"timeout over limit", DefaultsConfig{LambdaTimeout: 901}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateDefaults(&tt.defaults)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateDefaults() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
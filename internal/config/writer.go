@@ -10,12 +10,16 @@ import (
 
 // WriteConfig writes the configuration to ~/.scai.yaml
 func WriteConfig(cfg *Config) error {
-	home, err := os.UserHomeDir()
+	configPath, err := configFilePath()
 	if err != nil {
-		return fmt.Errorf("failed to get home directory: %w", err)
+		return err
 	}
+	return writeConfigFile(configPath, cfg)
+}
 
-	configPath := filepath.Join(home, ".scai.yaml")
+// writeConfigFile writes the configuration to configPath with the current schema version
+func writeConfigFile(configPath string, cfg *Config) error {
+	cfg.Version = CurrentVersion
 
 	// Marshal config to YAML
 	data, err := yaml.Marshal(cfg)
@@ -31,22 +35,25 @@ func WriteConfig(cfg *Config) error {
 	return nil
 }
 
-// ReadConfig reads the configuration from ~/.scai.yaml
+// ReadConfig reads the configuration from ~/.scai.yaml, migrating it to the current
+// schema version first
 func ReadConfig() (*Config, error) {
-	home, err := os.UserHomeDir()
+	configPath, err := configFilePath()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get home directory: %w", err)
+		return nil, err
 	}
 
-	configPath := filepath.Join(home, ".scai.yaml")
-
 	// Check if file exists
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
 		return nil, fmt.Errorf("config file not found at %s", configPath)
 	}
 
+	if _, err := MigrateConfig(configPath); err != nil {
+		return nil, fmt.Errorf("failed to migrate config file: %w", err)
+	}
+
 	// Read file
-	// #nosec G304 -- configPath is from GetConfigPath() which returns user's ~/.scai.yaml
+	// #nosec G304 -- configPath is from configFilePath() which returns user's ~/.scai.yaml
 	data, err := os.ReadFile(configPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
@@ -63,12 +70,20 @@ func ReadConfig() (*Config, error) {
 
 // ConfigExists checks if the configuration file exists
 func ConfigExists() bool {
-	home, err := os.UserHomeDir()
+	configPath, err := configFilePath()
 	if err != nil {
 		return false
 	}
 
-	configPath := filepath.Join(home, ".scai.yaml")
 	_, err = os.Stat(configPath)
 	return err == nil
 }
+
+// configFilePath returns the path of ~/.scai.yaml
+func configFilePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".scai.yaml"), nil
+}
@@ -0,0 +1,100 @@
+// Package console controls how command output is rendered: colors, styling and emoji.
+package console
+
+import (
+	"io"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/pterm/pterm"
+)
+
+// Stdout is the writer for command output. In plain mode it strips emoji.
+var Stdout io.Writer = os.Stdout
+
+var plain bool
+
+// output is the stream command output is written to: stdout, or stderr when stdout is
+// reserved for a machine-readable result
+var output io.Writer = os.Stdout
+
+// SetPlain disables (or re-enables) pterm colors and styling and emoji in output
+func SetPlain(enabled bool) {
+	plain = enabled
+
+	if enabled {
+		Stdout = emojiStripper{w: output}
+		pterm.DisableStyling()
+	} else {
+		Stdout = output
+		pterm.EnableStyling()
+	}
+	pterm.SetDefaultOutput(Stdout)
+}
+
+// SetStderr writes command output to stderr (or back to stdout when disabled), leaving
+// stdout to a machine-readable result such as the JSON plan of --plan-format json
+func SetStderr(enabled bool) {
+	if enabled {
+		output = os.Stderr
+	} else {
+		output = os.Stdout
+	}
+	SetPlain(plain)
+}
+
+// Plain reports whether plain output is enabled
+func Plain() bool {
+	return plain
+}
+
+// StripEmoji removes emoji (and the spaces following them) from s
+func StripEmoji(s string) string {
+	var sb strings.Builder
+	sb.Grow(len(s))
+
+	skipSpaces := false
+	for _, r := range s {
+		if isEmoji(r) {
+			skipSpaces = true
+			continue
+		}
+		if skipSpaces && r == ' ' {
+			continue
+		}
+		skipSpaces = false
+		sb.WriteRune(r)
+	}
+
+	return sb.String()
+}
+
+// isEmoji reports whether r is a pictographic symbol or an emoji modifier
+func isEmoji(r rune) bool {
+	switch {
+	case r >= 0x1F000 && r <= 0x1FAFF: // Pictographs, emoticons, transport, flags
+		return true
+	case r >= 0x2600 && r <= 0x27BF: // Miscellaneous symbols and dingbats (⚠, ✅, ✓, ❌)
+		return true
+	case r >= 0x2B00 && r <= 0x2BFF: // Arrows and symbols (⭐)
+		return true
+	case r == 0xFE0F || r == 0x200D: // Variation selector and zero-width joiner
+		return true
+	case r == 0x2139 || r == 0x23F3 || r == 0x231B: // ℹ, ⏳, ⌛
+		return true
+	}
+	return unicode.Is(unicode.Variation_Selector, r)
+}
+
+// emojiStripper is a writer that removes emoji before writing
+type emojiStripper struct {
+	w io.Writer
+}
+
+func (e emojiStripper) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(e.w, StripEmoji(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
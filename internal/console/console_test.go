@@ -0,0 +1,20 @@
+package console
+
+import "testing"
+
+func TestStripEmoji(t *testing.T) {
+	tests := map[string]string{
+		"📊 Analyzing repository...":    "Analyzing repository...",
+		"⚠️  Fargate does not run":     "Fargate does not run",
+		"   ✓ Instance is running":     "   Instance is running",
+		"Status: ✅ succeeded":          "Status: succeeded",
+		"plain text, 80 → 8080":        "plain text, 80 → 8080",
+		"🗑️ Destroying deployment abc": "Destroying deployment abc",
+	}
+
+	for input, want := range tests {
+		if got := StripEmoji(input); got != want {
+			t.Errorf("StripEmoji(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
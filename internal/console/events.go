@@ -0,0 +1,69 @@
+package console
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"time"
+
+	"github.com/pterm/pterm"
+)
+
+// Log formats accepted by --log-format
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
+// Event statuses
+const (
+	StatusStarted   = "started"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+// Event is a structured progress event, written as one JSON line in JSON mode
+type Event struct {
+	Time         time.Time      `json:"time"`
+	Phase        string         `json:"phase"` // analyze, strategy, plan, apply, outputs
+	Status       string         `json:"status"`
+	Message      string         `json:"message,omitempty"`
+	DeploymentID string         `json:"deployment_id,omitempty"`
+	Data         map[string]any `json:"data,omitempty"`
+}
+
+var (
+	jsonMode  bool
+	eventsOut io.Writer = os.Stdout
+)
+
+// SetJSON switches to JSON lines output: decorative output is discarded
+// and only events are written to stdout
+func SetJSON(enabled bool) {
+	jsonMode = enabled
+
+	if enabled {
+		Stdout = io.Discard
+		pterm.DisableStyling()
+		pterm.SetDefaultOutput(io.Discard)
+	} else {
+		SetPlain(plain)
+	}
+}
+
+// JSON reports whether JSON lines output is enabled
+func JSON() bool {
+	return jsonMode
+}
+
+// Emit writes an event in JSON mode; it is a no-op otherwise
+func Emit(event Event) {
+	if !jsonMode {
+		return
+	}
+
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+	_ = json.NewEncoder(eventsOut).Encode(event)
+}
@@ -0,0 +1,248 @@
+// Package cost estimates the monthly AWS cost of a deployment plan from static
+// on-demand prices (us-east-1, Linux), to compare it with a budget
+package cost
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/Smana/scai/internal/deployer"
+	"github.com/Smana/scai/internal/terraform"
+)
+
+// HoursPerMonth is the number of hours AWS bills per month
+const HoursPerMonth = 730
+
+// Hourly on-demand prices in USD
+var ec2HourlyPrices = map[string]float64{
+	"t2.nano": 0.0058, "t2.micro": 0.0116, "t2.small": 0.023, "t2.medium": 0.0464, "t2.large": 0.0928, "t2.xlarge": 0.1856, "t2.2xlarge": 0.3712,
+	"t3.nano": 0.0052, "t3.micro": 0.0104, "t3.small": 0.0208, "t3.medium": 0.0416, "t3.large": 0.0832, "t3.xlarge": 0.1664, "t3.2xlarge": 0.3328,
+	"t4g.nano": 0.0042, "t4g.micro": 0.0084, "t4g.small": 0.0168, "t4g.medium": 0.0336, "t4g.large": 0.0672, "t4g.xlarge": 0.1344, "t4g.2xlarge": 0.2688,
+	"m5.large": 0.096, "m5.xlarge": 0.192, "m5.2xlarge": 0.384, "m5.4xlarge": 0.768,
+	"m6i.large": 0.096, "m6i.xlarge": 0.192, "m6i.2xlarge": 0.384, "m6i.4xlarge": 0.768,
+	"c5.large": 0.085, "c5.xlarge": 0.17, "c5.2xlarge": 0.34, "c5.4xlarge": 0.68,
+	"c6i.large": 0.085, "c6i.xlarge": 0.17, "c6i.2xlarge": 0.34, "c6i.4xlarge": 0.68,
+	"r5.large": 0.126, "r5.xlarge": 0.252, "r5.2xlarge": 0.504, "r5.4xlarge": 1.008,
+	"r6i.large": 0.126, "r6i.xlarge": 0.252, "r6i.2xlarge": 0.504, "r6i.4xlarge": 1.008,
+}
+
+var rdsHourlyPrices = map[string]float64{
+	"db.t3.micro": 0.017, "db.t3.small": 0.034, "db.t3.medium": 0.068, "db.t3.large": 0.136,
+	"db.t4g.micro": 0.016, "db.t4g.small": 0.032, "db.t4g.medium": 0.065, "db.t4g.large": 0.129,
+	"db.m5.large": 0.171, "db.m6i.large": 0.171, "db.r5.large": 0.24, "db.r6i.large": 0.24,
+}
+
+const (
+	eksControlPlaneHourly = 0.10
+	natGatewayHourly      = 0.045
+	classicELBHourly      = 0.025
+	albHourly             = 0.0225
+	nlbHourly             = 0.0225
+	fargateVCPUHourly     = 0.04048
+	fargateGBHourly       = 0.004445
+	ebsGP3PerGBMonth      = 0.08
+	rdsGP3PerGBMonth      = 0.115
+)
+
+// Pods billed on Fargate besides the application replicas (CoreDNS and the AWS Load Balancer
+// Controller, two replicas each), each pod rounded up to the smallest Fargate size (0.25 vCPU, 0.5 GB)
+const (
+	fargateSystemPods = 4
+	fargatePodCPU     = 0.25
+	fargatePodGB      = 0.5
+)
+
+// Item is the monthly cost of one resource of the plan
+type Item struct {
+	Resource   string  `json:"resource"`
+	MonthlyUSD float64 `json:"monthly_usd"`
+}
+
+// Estimate is the estimated fixed monthly cost of a plan. Usage-based charges (Lambda
+// invocations, API Gateway requests, data transfer) are not included.
+type Estimate struct {
+	Items    []Item   `json:"items"`
+	Unpriced []string `json:"unpriced,omitempty"` // Instance types or classes with no known price
+}
+
+// MonthlyUSD returns the total of the estimate
+func (e Estimate) MonthlyUSD() float64 {
+	total := 0.0
+	for _, item := range e.Items {
+		total += item.MonthlyUSD
+	}
+	return total
+}
+
+// MarshalJSON adds the monthly total to the items of the estimate
+func (e Estimate) MarshalJSON() ([]byte, error) {
+	type estimate Estimate // Without the MarshalJSON method
+	return json.Marshal(struct {
+		estimate
+		MonthlyUSD float64 `json:"monthly_usd"`
+	}{estimate(e), e.MonthlyUSD()})
+}
+
+// UsageBased reports whether the plan is only billed per use (serverless without database)
+func (e Estimate) UsageBased() bool {
+	return len(e.Items) == 0 && len(e.Unpriced) == 0
+}
+
+// EstimateMonthly estimates the fixed monthly cost of the resources config provisions
+func EstimateMonthly(config *deployer.DeployConfig) Estimate {
+	var e Estimate
+
+	switch config.Strategy {
+	case "serverless":
+		// Lambda and API Gateway are billed per request
+	case "kubernetes":
+		e.add("EKS control plane", eksControlPlaneHourly*HoursPerMonth)
+		// The NAT gateways of an existing VPC are not part of the deployment
+		switch {
+		case config.VPCID != "", config.EKSNATGateway == terraform.NATGatewayNone:
+		case config.EKSNATGateway == terraform.NATGatewayPerAZ:
+			e.add("NAT gateways (2)", 2*natGatewayHourly*HoursPerMonth)
+		default:
+			e.add("NAT gateway", natGatewayHourly*HoursPerMonth)
+		}
+		if config.EKSFargate {
+			pods := terraform.Replicas(config.Replicas) + fargateSystemPods
+			podHourly := fargatePodCPU*fargateVCPUHourly + fargatePodGB*fargateGBHourly
+			e.add(fmt.Sprintf("Fargate pods (%d)", pods), float64(pods)*podHourly*HoursPerMonth)
+		} else {
+			nodes := max(config.EKSDesiredNodes, 1)
+			e.addInstances(fmt.Sprintf("EKS nodes (%d x %s)", nodes, config.EKSNodeType), config.EKSNodeType, ec2HourlyPrices, nodes)
+			e.add("EKS node volumes", float64(nodes*config.EKSNodeVolumeSize)*ebsGP3PerGBMonth)
+		}
+		if config.EKSFargate {
+			e.add("Network Load Balancer", nlbHourly*HoursPerMonth)
+		} else {
+			e.add("Load balancer", classicELBHourly*HoursPerMonth)
+		}
+	default:
+		instanceType := config.EC2InstanceType
+		if instanceType == "" {
+			instanceType = "t3.micro"
+		}
+		e.addInstances("EC2 instance ("+instanceType+")", instanceType, ec2HourlyPrices, 1)
+		e.add("EC2 volume", float64(config.EC2VolumeSize)*ebsGP3PerGBMonth)
+		if config.Domain != "" {
+			e.add("Application Load Balancer", albHourly*HoursPerMonth)
+		}
+	}
+
+	if config.DatabaseEngine != "" {
+		e.addInstances("RDS ("+config.DatabaseInstanceClass+")", config.DatabaseInstanceClass, rdsHourlyPrices, 1)
+		e.add("RDS storage", float64(config.DatabaseStorage)*rdsGP3PerGBMonth)
+	}
+
+	return e
+}
+
+// add records a priced resource, skipping free ones
+func (e *Estimate) add(resource string, monthly float64) {
+	if monthly > 0 {
+		e.Items = append(e.Items, Item{Resource: resource, MonthlyUSD: monthly})
+	}
+}
+
+// addInstances records count instances of a type from prices, or the type as unpriced
+func (e *Estimate) addInstances(resource, instanceType string, prices map[string]float64, count int) {
+	hourly, ok := prices[instanceType]
+	if !ok {
+		e.Unpriced = append(e.Unpriced, instanceType)
+		return
+	}
+	e.add(resource, float64(count)*hourly*HoursPerMonth)
+}
+
+// Suggestions lists cheaper sizing options for a plan estimated above budgetUSD,
+// the largest savings first
+func Suggestions(config *deployer.DeployConfig, budgetUSD float64) []string {
+	type suggestion struct {
+		text    string
+		monthly float64
+	}
+	var suggestions []suggestion
+
+	estimated := EstimateMonthly(config).MonthlyUSD()
+	try := func(text string, change func(c *deployer.DeployConfig)) {
+		candidate := *config
+		change(&candidate)
+		if monthly := EstimateMonthly(&candidate).MonthlyUSD(); monthly < estimated {
+			suggestions = append(suggestions, suggestion{text: text, monthly: monthly})
+		}
+	}
+
+	switch config.Strategy {
+	case "kubernetes":
+		try("--strategy vm (no EKS control plane, NAT gateway or node group)", func(c *deployer.DeployConfig) {
+			c.Strategy = "vm"
+		})
+		if config.EKSNATGateway != terraform.NATGatewayNone && !config.EKSFargate && config.VPCID == "" {
+			try("--nat-gateway none (nodes in public subnets)", func(c *deployer.DeployConfig) { c.EKSNATGateway = terraform.NATGatewayNone })
+		}
+		if !config.EKSFargate {
+			if smaller := cheaperInstanceType(config.EKSNodeType, ec2HourlyPrices); smaller != "" {
+				try("--eks-node-type "+smaller, func(c *deployer.DeployConfig) { c.EKSNodeType = smaller })
+			}
+			if config.EKSDesiredNodes > 1 {
+				try("--eks-desired-nodes 1 --eks-min-nodes 1", func(c *deployer.DeployConfig) { c.EKSDesiredNodes, c.EKSMinNodes = 1, 1 })
+			}
+		}
+	case "vm":
+		if smaller := cheaperInstanceType(config.EC2InstanceType, ec2HourlyPrices); smaller != "" {
+			try("--ec2-instance-type "+smaller, func(c *deployer.DeployConfig) { c.EC2InstanceType = smaller })
+		}
+	}
+
+	if config.DatabaseEngine != "" {
+		if smaller := cheaperInstanceType(config.DatabaseInstanceClass, rdsHourlyPrices); smaller != "" {
+			try("--db-instance-class "+smaller, func(c *deployer.DeployConfig) { c.DatabaseInstanceClass = smaller })
+		}
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool { return suggestions[i].monthly < suggestions[j].monthly })
+
+	result := make([]string, 0, len(suggestions))
+	for _, s := range suggestions {
+		fit := ""
+		if s.monthly <= budgetUSD {
+			fit = ", within budget"
+		}
+		result = append(result, fmt.Sprintf("%s: ~$%.0f/month%s", s.text, s.monthly, fit))
+	}
+	return result
+}
+
+// cheaperInstanceType returns the most expensive known type priced under instanceType
+// in the same family, e.g. t3.small for t3.medium ("" if there is none)
+func cheaperInstanceType(instanceType string, prices map[string]float64) string {
+	current, ok := prices[instanceType]
+	if !ok {
+		return ""
+	}
+	family := instanceFamily(instanceType)
+
+	best, bestPrice := "", 0.0
+	for candidate, price := range prices {
+		if instanceFamily(candidate) != family || price >= current {
+			continue
+		}
+		if price > bestPrice || (price == bestPrice && candidate < best) {
+			best, bestPrice = candidate, price
+		}
+	}
+	return best
+}
+
+// instanceFamily returns the family of an instance type or class ("db.t3" for db.t3.micro)
+func instanceFamily(instanceType string) string {
+	for i := len(instanceType) - 1; i >= 0; i-- {
+		if instanceType[i] == '.' {
+			return instanceType[:i]
+		}
+	}
+	return instanceType
+}
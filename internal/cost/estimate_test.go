@@ -0,0 +1,90 @@
+package cost
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/Smana/scai/internal/deployer"
+	"github.com/Smana/scai/internal/terraform"
+)
+
+func TestEstimateMonthly(t *testing.T) {
+	tests := []struct {
+		name   string
+		config deployer.DeployConfig
+		want   float64
+	}{
+		{
+			name:   "vm",
+			config: deployer.DeployConfig{Strategy: "vm", EC2InstanceType: "t3.small", EC2VolumeSize: 30},
+			want:   0.0208*HoursPerMonth + 30*0.08,
+		},
+		{
+			name:   "serverless",
+			config: deployer.DeployConfig{Strategy: "serverless", LambdaMemory: 512},
+			want:   0,
+		},
+		{
+			name: "kubernetes with nodes and a database",
+			config: deployer.DeployConfig{
+				Strategy: "kubernetes", EKSNodeType: "t3.medium", EKSDesiredNodes: 2, EKSNodeVolumeSize: 30,
+				EKSNATGateway: terraform.NATGatewayPerAZ, DatabaseEngine: "postgres", DatabaseInstanceClass: "db.t3.micro", DatabaseStorage: 20,
+			},
+			want: (0.10+2*0.045+2*0.0416+0.025+0.017)*HoursPerMonth + 60*0.08 + 20*0.115,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EstimateMonthly(&tt.config).MonthlyUSD(); math.Abs(got-tt.want) > 0.01 {
+				t.Errorf("EstimateMonthly() = %.2f, want %.2f", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEstimateMonthlyUnpriced(t *testing.T) {
+	estimate := EstimateMonthly(&deployer.DeployConfig{Strategy: "vm", EC2InstanceType: "x2idn.metal"})
+	if len(estimate.Unpriced) != 1 || estimate.Unpriced[0] != "x2idn.metal" {
+		t.Errorf("Unpriced = %v, want [x2idn.metal]", estimate.Unpriced)
+	}
+	if estimate.UsageBased() {
+		t.Error("an unpriced instance is not usage-based")
+	}
+}
+
+func TestEstimateMarshalJSON(t *testing.T) {
+	estimate := Estimate{Items: []Item{{Resource: "EC2", MonthlyUSD: 30}, {Resource: "EBS", MonthlyUSD: 2.5}}}
+
+	data, err := json.Marshal(estimate)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	want := `{"items":[{"resource":"EC2","monthly_usd":30},{"resource":"EBS","monthly_usd":2.5}],"monthly_usd":32.5}`
+	if string(data) != want {
+		t.Errorf("Marshal() = %s, want %s", data, want)
+	}
+}
+
+func TestSuggestions(t *testing.T) {
+	config := &deployer.DeployConfig{
+		Strategy: "kubernetes", EC2InstanceType: "t3.micro", EC2VolumeSize: 30,
+		EKSNodeType: "t3.medium", EKSDesiredNodes: 2, EKSMinNodes: 1, EKSNodeVolumeSize: 30,
+	}
+
+	suggestions := Suggestions(config, 50)
+	if len(suggestions) == 0 {
+		t.Fatal("Suggestions() returned no cheaper options")
+	}
+	// Moving off EKS saves the most, and fits the budget
+	if !strings.HasPrefix(suggestions[0], "--strategy vm") || !strings.HasSuffix(suggestions[0], "within budget") {
+		t.Errorf("first suggestion = %q, want --strategy vm within budget", suggestions[0])
+	}
+	for _, s := range suggestions {
+		if strings.HasPrefix(s, "--eks-node-type") && !strings.HasPrefix(s, "--eks-node-type t3.small") {
+			t.Errorf("node type suggestion = %q, want t3.small", s)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package cost
+
+import (
+	"sort"
+	"time"
+
+	"github.com/Smana/scai/internal/deployer"
+	"github.com/Smana/scai/internal/store"
+	"github.com/Smana/scai/internal/types"
+)
+
+// ReportLine is the estimated cost of the deployments of an app with the same strategy and
+// region over the period of a report
+type ReportLine struct {
+	AppName     string  `json:"app_name"`
+	Strategy    string  `json:"strategy"`
+	Region      string  `json:"region"`
+	Deployments int     `json:"deployments"`
+	ActiveHours float64 `json:"active_hours"`
+	CostUSD     float64 `json:"cost_usd"`
+	UsageBased  bool    `json:"usage_based,omitempty"` // Only billed per use, not estimated
+}
+
+// Report is the estimated cost of the deployments active between Since and Until, each
+// deployment billed its monthly estimate prorated to the hours it was active in the period
+type Report struct {
+	Since    time.Time    `json:"since"`
+	Until    time.Time    `json:"until"`
+	Lines    []ReportLine `json:"lines"`
+	TotalUSD float64      `json:"total_usd"`
+	Unpriced []string     `json:"unpriced,omitempty"` // Instance types or classes with no known price
+}
+
+// BuildReport estimates the cost of deployments between since and until. A deployment is
+// active from its creation until it is destroyed (or failed); exported plans are never applied.
+// Lines are sorted by decreasing cost.
+func BuildReport(deployments []*store.Deployment, since, until time.Time) Report {
+	report := Report{Since: since, Until: until, Lines: []ReportLine{}}
+
+	type key struct{ app, strategy, region string }
+	lines := make(map[key]*ReportLine)
+	unpriced := make(map[string]bool)
+
+	for _, deployment := range deployments {
+		if deployment.Status == store.DeploymentStatusPlanned || deployment.Config == nil {
+			continue
+		}
+		hours := activeHours(deployment, since, until)
+		if hours <= 0 {
+			continue
+		}
+
+		estimate := EstimateMonthly(deployConfig(deployment.Config))
+		for _, instanceType := range estimate.Unpriced {
+			unpriced[instanceType] = true
+		}
+		cost := estimate.MonthlyUSD() * hours / HoursPerMonth
+
+		k := key{deployment.AppName, deployment.Strategy, deployment.Region}
+		line, ok := lines[k]
+		if !ok {
+			line = &ReportLine{AppName: k.app, Strategy: k.strategy, Region: k.region, UsageBased: true}
+			lines[k] = line
+		}
+		line.Deployments++
+		line.ActiveHours += hours
+		line.CostUSD += cost
+		line.UsageBased = line.UsageBased && estimate.UsageBased()
+		report.TotalUSD += cost
+	}
+
+	for _, line := range lines {
+		report.Lines = append(report.Lines, *line)
+	}
+	sort.Slice(report.Lines, func(i, j int) bool {
+		a, b := report.Lines[i], report.Lines[j]
+		if a.CostUSD != b.CostUSD {
+			return a.CostUSD > b.CostUSD
+		}
+		return a.AppName+a.Strategy+a.Region < b.AppName+b.Strategy+b.Region
+	})
+
+	for instanceType := range unpriced {
+		report.Unpriced = append(report.Unpriced, instanceType)
+	}
+	sort.Strings(report.Unpriced)
+
+	return report
+}
+
+// activeHours returns the hours deployment was active between since and until
+func activeHours(deployment *store.Deployment, since, until time.Time) float64 {
+	start, end := deployment.CreatedAt, until
+	switch {
+	case deployment.DestroyedAt != nil:
+		end = *deployment.DestroyedAt
+	case deployment.Status == store.DeploymentStatusFailed:
+		end = deployment.UpdatedAt
+	}
+
+	if start.Before(since) {
+		start = since
+	}
+	if end.After(until) {
+		end = until
+	}
+	return end.Sub(start).Hours()
+}
+
+// deployConfig returns the sizing of a recorded deployment in the form EstimateMonthly expects
+func deployConfig(config *types.TerraformConfig) *deployer.DeployConfig {
+	return &deployer.DeployConfig{
+		Strategy:              config.Strategy,
+		Domain:                config.Domain,
+		DatabaseEngine:        config.DatabaseEngine,
+		DatabaseInstanceClass: config.DatabaseInstanceClass,
+		DatabaseStorage:       config.DatabaseStorage,
+		EC2InstanceType:       config.InstanceType,
+		EC2VolumeSize:         config.VolumeSize,
+		EKSNodeType:           config.EKSNodeType,
+		EKSDesiredNodes:       config.EKSDesiredNodes,
+		EKSNodeVolumeSize:     config.EKSNodeVolumeSize,
+		EKSFargate:            config.EKSFargate,
+		EKSNATGateway:         config.EKSNATGateway,
+		VPCID:                 config.VPCID,
+		Replicas:              config.Replicas,
+	}
+}
@@ -0,0 +1,89 @@
+package cost
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/Smana/scai/internal/deployer"
+	"github.com/Smana/scai/internal/store"
+	"github.com/Smana/scai/internal/types"
+)
+
+func TestBuildReport(t *testing.T) {
+	until := time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)
+	since := until.Add(-30 * 24 * time.Hour)
+	vm := &types.TerraformConfig{Strategy: "vm", InstanceType: "t3.small", VolumeSize: 30}
+	vmMonthly := 0.0208*HoursPerMonth + 30*0.08
+
+	destroyedAt := since.Add(10 * time.Hour)
+	destroyedBefore := since.Add(-time.Hour)
+	deployments := []*store.Deployment{
+		// Active the whole period
+		{AppName: "api", Strategy: "vm", Region: "eu-west-3", Status: store.DeploymentStatusSucceeded,
+			Config: vm, CreatedAt: since.Add(-48 * time.Hour)},
+		// Destroyed 10 hours into the period
+		{AppName: "api", Strategy: "vm", Region: "eu-west-3", Status: store.DeploymentStatusDestroyed,
+			Config: vm, CreatedAt: since.Add(-time.Hour), DestroyedAt: &destroyedAt},
+		// Destroyed before the period
+		{AppName: "old", Strategy: "vm", Region: "eu-west-3", Status: store.DeploymentStatusDestroyed,
+			Config: vm, CreatedAt: since.Add(-48 * time.Hour), DestroyedAt: &destroyedBefore},
+		// Never applied
+		{AppName: "planned", Strategy: "vm", Region: "eu-west-3", Status: store.DeploymentStatusPlanned,
+			Config: vm, CreatedAt: since},
+		{AppName: "fn", Strategy: "serverless", Region: "us-east-1", Status: store.DeploymentStatusSucceeded,
+			Config: &types.TerraformConfig{Strategy: "serverless"}, CreatedAt: since},
+	}
+
+	report := BuildReport(deployments, since, until)
+
+	if len(report.Lines) != 2 {
+		t.Fatalf("BuildReport() lines = %+v, want api and fn", report.Lines)
+	}
+	api := report.Lines[0]
+	if api.AppName != "api" || api.Deployments != 2 || api.ActiveHours != 30*24+10 {
+		t.Errorf("api line = %+v, want 2 deployments active %d hours", api, 30*24+10)
+	}
+	if want := vmMonthly * (30*24 + 10) / HoursPerMonth; math.Abs(api.CostUSD-want) > 0.01 {
+		t.Errorf("api cost = %.2f, want %.2f", api.CostUSD, want)
+	}
+	if fn := report.Lines[1]; fn.AppName != "fn" || !fn.UsageBased || fn.CostUSD != 0 {
+		t.Errorf("fn line = %+v, want a usage-based line", fn)
+	}
+	if math.Abs(report.TotalUSD-api.CostUSD) > 0.01 {
+		t.Errorf("TotalUSD = %.2f, want %.2f", report.TotalUSD, api.CostUSD)
+	}
+}
+
+func TestBuildReportExistingVPC(t *testing.T) {
+	until := time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)
+	since := until.Add(-30 * 24 * time.Hour)
+	eks := types.TerraformConfig{Strategy: "kubernetes", EKSNodeType: "t3.medium", EKSDesiredNodes: 2, EKSNodeVolumeSize: 20}
+	existingVPC := eks
+	existingVPC.VPCID = "vpc-0123456789abcdef0"
+
+	deployments := []*store.Deployment{
+		{AppName: "new-vpc", Strategy: "kubernetes", Region: "eu-west-3", Status: store.DeploymentStatusSucceeded,
+			Config: &eks, CreatedAt: since},
+		{AppName: "existing-vpc", Strategy: "kubernetes", Region: "eu-west-3", Status: store.DeploymentStatusSucceeded,
+			Config: &existingVPC, CreatedAt: since},
+	}
+
+	report := BuildReport(deployments, since, until)
+
+	costs := make(map[string]float64)
+	for _, line := range report.Lines {
+		costs[line.AppName] = line.CostUSD
+	}
+	// The NAT gateway of an existing VPC is not part of the deployment, as in the plan estimate
+	hours := until.Sub(since).Hours()
+	if want := costs["new-vpc"] - natGatewayHourly*hours; math.Abs(costs["existing-vpc"]-want) > 0.01 {
+		t.Errorf("existing-vpc cost = %.2f, want %.2f (new-vpc cost without the NAT gateway)", costs["existing-vpc"], want)
+	}
+	plan := &deployer.DeployConfig{Strategy: "kubernetes", EKSNodeType: "t3.medium", EKSDesiredNodes: 2, EKSNodeVolumeSize: 20,
+		VPCID: "vpc-0123456789abcdef0"}
+	planned := EstimateMonthly(plan).MonthlyUSD() * hours / HoursPerMonth
+	if math.Abs(costs["existing-vpc"]-planned) > 0.01 {
+		t.Errorf("existing-vpc cost = %.2f, want the plan estimate %.2f", costs["existing-vpc"], planned)
+	}
+}
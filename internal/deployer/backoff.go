@@ -0,0 +1,55 @@
+package deployer
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+)
+
+// Readiness polling intervals: start short to catch fast boots, back off for slow ones
+const (
+	initialPollInterval = 2 * time.Second
+	maxPollInterval     = 30 * time.Second
+	pollJitter          = 0.2 // +/- 20% of the interval
+)
+
+// backoff computes exponentially growing, jittered polling intervals bounded by a deadline
+type backoff struct {
+	interval time.Duration
+	deadline time.Time
+
+	now    func() time.Time // Clock, replaced in tests
+	random func() float64   // Jitter source in [0, 1), replaced in tests
+}
+
+func newBackoff(deadline time.Time) *backoff {
+	return &backoff{
+		interval: initialPollInterval,
+		deadline: deadline,
+		now:      time.Now,
+		random:   rand.Float64, // #nosec G404 -- jitter does not need a secure source
+	}
+}
+
+// next returns the time to wait before the next attempt (never past the deadline)
+// and doubles the interval up to maxPollInterval
+func (b *backoff) next() time.Duration {
+	wait := b.interval + time.Duration((b.random()*2-1)*pollJitter*float64(b.interval))
+
+	b.interval = min(2*b.interval, maxPollInterval)
+
+	return max(min(wait, b.deadline.Sub(b.now())), 0)
+}
+
+// wait sleeps until the next attempt, returning early with the context's error on cancellation
+func (b *backoff) wait(ctx context.Context) error {
+	timer := time.NewTimer(b.next())
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,76 @@
+package deployer
+
+import (
+	"testing"
+	"time"
+)
+
+// newTestBackoff returns a backoff with a fixed jitter and a clock advanced by the caller
+func newTestBackoff(deadline time.Duration, jitter float64) (*backoff, *time.Time) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := newBackoff(now.Add(deadline))
+	b.now = func() time.Time { return now }
+	b.random = func() float64 { return jitter }
+	return b, &now
+}
+
+func TestBackoffCappedAtMaxPollInterval(t *testing.T) {
+	b, _ := newTestBackoff(time.Hour, 0.5) // 0.5 is no jitter
+
+	want := []time.Duration{
+		2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second,
+		maxPollInterval, maxPollInterval, maxPollInterval,
+	}
+	for i, w := range want {
+		if got := b.next(); got != w {
+			t.Errorf("attempt %d: expected wait %s, got %s", i+1, w, got)
+		}
+	}
+}
+
+func TestBackoffJitter(t *testing.T) {
+	tests := []struct {
+		name   string
+		jitter float64
+		want   time.Duration
+	}{
+		{"lowest", 0, 1600 * time.Millisecond},
+		{"none", 0.5, 2 * time.Second},
+		{"highest", 0.75, 2200 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		b, _ := newTestBackoff(time.Hour, tt.jitter)
+		if got := b.next(); got != tt.want {
+			t.Errorf("%s: expected wait %s, got %s", tt.name, tt.want, got)
+		}
+	}
+
+	// Jitter applies to the capped interval too, within pollJitter of it
+	b, _ := newTestBackoff(time.Hour, 0.999)
+	for range 10 {
+		b.next()
+	}
+	if got, limit := b.next(), maxPollInterval+time.Duration(pollJitter*float64(maxPollInterval)); got > limit {
+		t.Errorf("expected wait at most %s, got %s", limit, got)
+	}
+}
+
+func TestBackoffNeverPastDeadline(t *testing.T) {
+	b, now := newTestBackoff(5*time.Second, 0.5)
+
+	want := []time.Duration{2 * time.Second, 3 * time.Second, 0, 0}
+	for i, w := range want {
+		got := b.next()
+		if got != w {
+			t.Errorf("attempt %d: expected wait %s, got %s", i+1, w, got)
+		}
+		*now = now.Add(got)
+	}
+
+	// Past the deadline, the wait is zero rather than negative
+	*now = now.Add(time.Minute)
+	if got := b.next(); got != 0 {
+		t.Errorf("expected no wait past the deadline, got %s", got)
+	}
+}
@@ -10,12 +10,17 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/Smana/scai/internal/backend"
+	"github.com/Smana/scai/internal/console"
 	"github.com/Smana/scai/internal/llm"
 	"github.com/Smana/scai/internal/store"
 	"github.com/Smana/scai/internal/terraform"
 	"github.com/Smana/scai/internal/types"
 )
 
+// applyRetryDelay is the wait before re-running a failed apply, growing with each retry,
+// long enough for IAM changes to propagate
+const applyRetryDelay = 20 * time.Second
+
 // DeployConfig contains deployment configuration
 type DeployConfig struct {
 	Strategy     string
@@ -26,18 +31,62 @@ type DeployConfig struct {
 	TerraformBin string
 	Verbose      bool
 
+	// Infrastructure as code engine (iac.engine): "terraform" (empty) or "pulumi", run with
+	// PulumiBin
+	IacEngine string
+	PulumiBin string
+
 	// LLM information
 	LLMProvider string
 	LLMModel    string
 
+	// Tags applied to all AWS resources (merged config defaults and --tag flags)
+	Tags map[string]string
+
+	// Custom domain (resolved from --domain before planning)
+	Domain         string
+	HostedZoneID   string
+	CertificateARN string
+
+	// RDS database (opt-in with --with-database)
+	DatabaseEngine        string
+	DatabaseInstanceClass string
+	DatabaseStorage       int
+
 	// EC2 sizing
 	EC2InstanceType string
 	EC2VolumeSize   int
 
+	// EC2 image and bootstrap: a custom AMI (--ami, AMIName for the plan) instead of the latest
+	// Amazon Linux 2023, and a user-data script read from UserDataPath (--user-data) instead of
+	// the generated one
+	AMI          string
+	AMIName      string
+	UserData     string
+	UserDataPath string
+
+	// Existing network (--vpc-id, --subnet-ids) instead of the default VPC (vm) or a new VPC
+	// (kubernetes)
+	VPCID     string
+	SubnetIDs []string
+
+	// SSH access to the EC2 instances: an existing key pair (--key-name), or a new one whose
+	// private key is saved to SSHKeyPath (--generate-key); none by default
+	SSHKeyName     string
+	SSHGenerateKey bool
+
 	// Lambda sizing
 	LambdaMemory              int
 	LambdaTimeout             int
 	LambdaReservedConcurrency int
+	LambdaArchitecture        string // "x86_64" or "arm64"
+	LambdaContainer           bool   // Container image in ECR instead of a zip package
+
+	// API Gateway (serverless): CORS lists default to "*" when empty
+	CORSAllowOrigins []string
+	CORSAllowMethods []string
+	CORSAllowHeaders []string
+	AuthorizerARN    string // Lambda authorizer of the routes, empty for none
 
 	// EKS sizing
 	EKSNodeType       string
@@ -45,13 +94,57 @@ type DeployConfig struct {
 	EKSMaxNodes       int
 	EKSDesiredNodes   int
 	EKSNodeVolumeSize int
+	EKSFargate        bool
+	EKSVersion        string
+	EKSNATGateway     string            // "single", "per-az" or "none"
+	EKSAddons         map[string]string // Managed add-on name -> version ("" for the most recent)
+	AppPolicyARNs     []string          // IAM policies of the application role (kubernetes only)
+	EKSAdminARNs      []string          // IAM users and roles granted cluster admin (kubernetes only)
+	Replicas          int               // Kubernetes Deployment replicas, 0 for the default
+
+	// AWS provider version constraint of versions.tf
+	AWSProviderVersion string
+
+	// Kubernetes container requests and limits, empty values for the defaults
+	K8sResources terraform.K8sResources
+
+	// Autoscaling (vm and kubernetes), 0 for no autoscaling policy
+	AutoscaleTargetCPU int
+
+	// Retention of the CloudWatch log groups (Lambda function logs, EKS control plane logs),
+	// 0 for the strategy default
+	LogRetentionDays int
+
+	// IAM role assumed by Terraform or Pulumi (provider and state backend), as by the AWS
+	// clients of the deploy; empty for the credentials of the environment
+	AWSRoleARN string
+
+	// Monthly cost budget in USD the plan is checked against (0 for none), enforced
+	// instead of warned about with --strict-budget
+	BudgetUSD    float64
+	StrictBudget bool
+
+	// Past successful deployments refining the estimated provisioning time of the plan
+	DeployHistory []PastDeploy
+
+	// Directory the generated Terraform is exported to instead of being applied (--plan-out)
+	PlanOutDir string
+
+	// Destroy the partially created resources when terraform apply fails (--destroy-on-failure)
+	DestroyOnFailure bool
+
+	// Re-runs of an apply failing on a transient error (--apply-retries), negative for the
+	// strategy default
+	ApplyRetries int
 }
 
 // Deployer orchestrates the deployment process
 type Deployer struct {
-	config    *DeployConfig
-	llmClient *llm.Client
-	store     store.Store
+	config       *DeployConfig
+	llmClient    *llm.Client
+	store        store.Store
+	deploymentID string
+	ctx          context.Context // Cancels the deployment (interrupt, --timeout)
 }
 
 // NewDeployer creates a new Deployer instance
@@ -59,20 +152,34 @@ func NewDeployer(config *DeployConfig, storeInstance store.Store) *Deployer {
 	return &Deployer{
 		config: config,
 		store:  storeInstance,
+		ctx:    context.Background(),
 	}
 }
 
+// SetContext sets the context of the deployment: once it is canceled, terraform is
+// interrupted and the deployment is recorded as failed with the cancellation cause
+func (d *Deployer) SetContext(ctx context.Context) {
+	d.ctx = ctx
+}
+
 // SetLLMClient sets the LLM client for the deployer
 func (d *Deployer) SetLLMClient(client *llm.Client) {
 	d.llmClient = client
 }
 
+// DeploymentID returns the ID of the deployment started by Deploy (empty before)
+func (d *Deployer) DeploymentID() string {
+	return d.deploymentID
+}
+
 // Deploy executes the deployment workflow
 func (d *Deployer) Deploy() (*types.DeploymentResult, error) {
-	ctx := context.Background()
+	// The deployment record is still updated once d.ctx is canceled, to mark it failed
+	ctx := context.WithoutCancel(d.ctx)
 
 	// Generate unique deployment ID
 	deploymentID := uuid.New().String()
+	d.deploymentID = deploymentID
 
 	// Create deployment record with status "running"
 	deployment := &store.Deployment{
@@ -105,8 +212,15 @@ func (d *Deployer) Deploy() (*types.DeploymentResult, error) {
 			return nil, fmt.Errorf("failed to create deployment record: %w", err)
 		}
 
+		// Keep a destroy of the deployment from running while it is being applied
+		owner := store.LockOwner()
+		if err := d.store.Lock(ctx, deploymentID, owner); err != nil {
+			return nil, err
+		}
+		defer func() { _ = d.store.Unlock(ctx, deploymentID, owner) }()
+
 		if d.config.Verbose {
-			fmt.Printf("   Created deployment record: %s\n", deploymentID)
+			fmt.Fprintf(console.Stdout, "   Created deployment record: %s\n", deploymentID)
 		}
 	}
 
@@ -114,47 +228,23 @@ func (d *Deployer) Deploy() (*types.DeploymentResult, error) {
 	tfDir := filepath.Join(d.config.WorkDir, "terraform", deploymentID)
 
 	if d.config.Verbose {
-		fmt.Printf("   Creating Terraform configuration...\n")
+		fmt.Fprintf(console.Stdout, "   Creating Terraform configuration...\n")
 	}
 
-	// Generate Terraform configuration based on strategy
-	generator := terraform.NewGenerator(tfDir, d.config.Verbose)
-
-	tfConfig := &types.TerraformConfig{
-		Strategy:     d.config.Strategy,
-		AppName:      d.extractAppName(),
-		Region:       d.config.AWSRegion,
-		Framework:    d.config.Analysis.Framework,
-		Language:     d.config.Analysis.Language,
-		Port:         d.config.Analysis.Port,
-		RepoURL:      d.config.Analysis.RepoURL,
-		AppDir:       d.config.Analysis.AppDir,
-		StartCommand: d.config.Analysis.StartCommand,
-		EnvVars:      d.config.Analysis.EnvVars,
-
-		// EC2 sizing
-		VolumeSize: d.config.EC2VolumeSize,
-
-		// Lambda sizing
-		LambdaMemory:              d.config.LambdaMemory,
-		LambdaTimeout:             d.config.LambdaTimeout,
-		LambdaReservedConcurrency: d.config.LambdaReservedConcurrency,
+	// Generate Terraform configuration (or Pulumi program) based on strategy
+	generator := NewIacGenerator(d.config.IacEngine, tfDir, d.config.Verbose)
 
-		// EKS sizing
-		EKSNodeType:       d.config.EKSNodeType,
-		EKSMinNodes:       d.config.EKSMinNodes,
-		EKSMaxNodes:       d.config.EKSMaxNodes,
-		EKSDesiredNodes:   d.config.EKSDesiredNodes,
-		EKSNodeVolumeSize: d.config.EKSNodeVolumeSize,
-	}
+	tfConfig := d.terraformConfig(deploymentID)
 
-	// Set EC2 instance type if provided or use LLM suggestion
-	if d.config.EC2InstanceType != "" {
-		tfConfig.InstanceType = d.config.EC2InstanceType
-	} else if d.llmClient != nil {
-		tfConfig.InstanceType = d.llmClient.SuggestInstanceType(d.config.Analysis)
-	} else {
-		tfConfig.InstanceType = "t3.micro" // Default
+	if d.config.SSHGenerateKey && d.config.Strategy == "vm" {
+		publicKey, err := generateSSHKey(deploymentID)
+		if err != nil {
+			if d.store != nil {
+				_ = d.store.UpdateStatus(ctx, deploymentID, store.DeploymentStatusFailed, err.Error())
+			}
+			return nil, err
+		}
+		tfConfig.SSHPublicKey = publicKey
 	}
 
 	if err := generator.Generate(tfConfig); err != nil {
@@ -162,7 +252,7 @@ func (d *Deployer) Deploy() (*types.DeploymentResult, error) {
 		if d.store != nil {
 			_ = d.store.UpdateStatus(ctx, deploymentID, store.DeploymentStatusFailed, err.Error())
 		}
-		return nil, fmt.Errorf("failed to generate Terraform config: %w", err)
+		return nil, fmt.Errorf("failed to generate %s config: %w", d.engine(), err)
 	}
 
 	// Update deployment record with config and terraform directory
@@ -183,34 +273,46 @@ func (d *Deployer) Deploy() (*types.DeploymentResult, error) {
 		return nil, fmt.Errorf("failed to generate backend configuration: %w", err)
 	}
 
-	// Execute Terraform
+	// --plan-out: export the generated Terraform without applying it
+	if d.config.PlanOutDir != "" {
+		return d.exportPlan(ctx, deployment, tfDir)
+	}
+
+	// Execute Terraform (or Pulumi)
+	engine := d.engine()
 	if d.config.Verbose {
-		fmt.Printf("   Running Terraform...\n")
+		fmt.Fprintf(console.Stdout, "   Running %s...\n", engine)
 	}
 
-	executor, err := terraform.NewExecutor(tfDir, d.config.TerraformBin, d.config.Verbose)
+	executor, err := NewIacExecutor(d.config.IacEngine, tfDir, d.iacBin(), deploymentID, d.config.Verbose)
 	if err != nil {
 		// Update deployment status to failed
 		if d.store != nil {
 			_ = d.store.UpdateStatus(ctx, deploymentID, store.DeploymentStatusFailed, err.Error())
 		}
-		return nil, fmt.Errorf("failed to create terraform executor: %w", err)
+		return nil, fmt.Errorf("failed to create %s executor: %w", engine, err)
 	}
+	executor.SetContext(d.ctx)
 
 	if err := executor.Init(); err != nil {
 		// Update deployment status to failed
 		if d.store != nil {
-			_ = d.store.UpdateStatus(ctx, deploymentID, store.DeploymentStatusFailed, fmt.Sprintf("terraform init failed: %v", err))
+			_ = d.store.UpdateStatus(ctx, deploymentID, store.DeploymentStatusFailed, d.failureMessage(fmt.Sprintf("%s init failed: %v", engine, err)))
 		}
-		return nil, fmt.Errorf("terraform init failed: %w", err)
+		return nil, fmt.Errorf("%s init failed: %w", engine, err)
 	}
 
-	if err := executor.Apply(); err != nil {
+	d.addEvent(ctx, store.DeploymentStatusRunning, "Terraform apply started")
+	if err := d.apply(ctx, executor); err != nil {
 		// Update deployment status to failed
 		if d.store != nil {
-			_ = d.store.UpdateStatus(ctx, deploymentID, store.DeploymentStatusFailed, fmt.Sprintf("terraform apply failed: %v", err))
+			_ = d.store.UpdateStatus(ctx, deploymentID, store.DeploymentStatusFailed, d.failureMessage(fmt.Sprintf("%s apply failed: %v", engine, err)))
 		}
-		return nil, fmt.Errorf("terraform apply failed: %w", err)
+		applyErr := fmt.Errorf("%s apply failed: %w", engine, err)
+		if d.config.DestroyOnFailure && d.ctx.Err() == nil {
+			return nil, d.rollback(ctx, executor, applyErr)
+		}
+		return nil, applyErr
 	}
 
 	// Get outputs
@@ -218,40 +320,19 @@ func (d *Deployer) Deploy() (*types.DeploymentResult, error) {
 	if err != nil {
 		// Update deployment status to failed
 		if d.store != nil {
-			_ = d.store.UpdateStatus(ctx, deploymentID, store.DeploymentStatusFailed, fmt.Sprintf("failed to get outputs: %v", err))
+			_ = d.store.UpdateStatus(ctx, deploymentID, store.DeploymentStatusFailed, d.failureMessage(fmt.Sprintf("failed to get outputs: %v", err)))
 		}
-		return nil, fmt.Errorf("failed to get terraform outputs: %w", err)
+		return nil, fmt.Errorf("failed to get %s outputs: %w", engine, err)
 	}
 
 	// For VM strategy, get the actual application URL
 	if d.config.Strategy == "vm" {
-		if asgName, ok := outputs["asg_name"]; ok {
-			if portStr, ok := outputs["application_port"]; ok {
-				port, err := ParsePort(portStr)
-				if err == nil {
-					if d.config.Verbose {
-						fmt.Printf("   Checking application availability...\n")
-					}
-
-					appURL, err := GetApplicationURL(ctx, asgName, d.config.AWSRegion, port, d.config.Verbose)
-					if err != nil {
-						// Log warning but don't fail deployment
-						if d.config.Verbose {
-							fmt.Printf("   Warning: %v\n", err)
-						}
-						outputs["application_url"] = appURL
-						outputs["application_status"] = "Application may still be starting up. Please wait a few minutes."
-					} else {
-						outputs["application_url"] = appURL
-						outputs["application_status"] = "Application is ready!"
-					}
-				}
-			}
-		}
+		AddApplicationURL(d.ctx, outputs, d.config.AWSRegion, d.config.Analysis.HealthCheckPath, d.config.Verbose)
 	}
 
 	// Build deployment result
 	result := &types.DeploymentResult{
+		DeploymentID:  deploymentID,
 		Strategy:      d.config.Strategy,
 		Region:        d.config.AWSRegion,
 		Outputs:       outputs,
@@ -262,7 +343,7 @@ func (d *Deployer) Deploy() (*types.DeploymentResult, error) {
 
 	// Add warnings and optimizations if LLM client available
 	if d.llmClient != nil {
-		result.Warnings = d.llmClient.ValidateDeploymentRequirements(d.config.Analysis, d.config.Strategy)
+		result.Warnings = d.llmClient.ValidateDeploymentRequirements(d.config.Analysis, d.config.Strategy, d.config.EKSFargate)
 		result.Optimizations = d.llmClient.SuggestOptimizations(d.config.Analysis, d.config.Strategy)
 	}
 
@@ -274,7 +355,7 @@ func (d *Deployer) Deploy() (*types.DeploymentResult, error) {
 		if err := d.store.UpdateStatus(ctx, deploymentID, store.DeploymentStatusSucceeded, ""); err != nil {
 			// Log but don't fail deployment
 			if d.config.Verbose {
-				fmt.Printf("   Warning: failed to update deployment status: %v\n", err)
+				fmt.Fprintf(console.Stdout, "   Warning: failed to update deployment status: %v\n", err)
 			}
 		}
 
@@ -282,18 +363,256 @@ func (d *Deployer) Deploy() (*types.DeploymentResult, error) {
 		if err := d.store.Update(ctx, deployment); err != nil {
 			// Log but don't fail deployment
 			if d.config.Verbose {
-				fmt.Printf("   Warning: failed to update deployment record: %v\n", err)
+				fmt.Fprintf(console.Stdout, "   Warning: failed to update deployment record: %v\n", err)
 			}
 		}
 
 		if d.config.Verbose {
-			fmt.Printf("   ✓ Deployment completed successfully: %s\n", deploymentID)
+			fmt.Fprintf(console.Stdout, "   ✓ Deployment completed successfully: %s\n", deploymentID)
 		}
 	}
 
 	return result, nil
 }
 
+// terraformConfig maps the deployment configuration to the generator's variables
+func (d *Deployer) terraformConfig(deploymentID string) *types.TerraformConfig {
+	tfConfig := &types.TerraformConfig{
+		Strategy:     d.config.Strategy,
+		IacEngine:    d.config.IacEngine,
+		AppName:      d.extractAppName(),
+		Region:       d.config.AWSRegion,
+		Framework:    d.config.Analysis.Framework,
+		Language:     d.config.Analysis.Language,
+		Port:         d.config.Analysis.Port,
+		RepoURL:      d.config.Analysis.RepoURL,
+		AppDir:       d.config.Analysis.AppDir,
+		SourcePath:   d.sourcePath(),
+		StartCommand: d.config.Analysis.StartCommand,
+		BuildCommand: d.config.Analysis.BuildCommand,
+		EnvVars:      d.config.Analysis.EnvVars,
+
+		// Container image
+		CommitSHA:     d.config.Analysis.CommitSHA,
+		HasDockerfile: d.config.Analysis.HasDockerfile,
+
+		HealthCheckPath: d.config.Analysis.HealthCheckPath,
+
+		// Resource tagging
+		DeploymentID: deploymentID,
+		Tags:         d.config.Tags,
+
+		// Custom domain
+		Domain:         d.config.Domain,
+		HostedZoneID:   d.config.HostedZoneID,
+		CertificateARN: d.config.CertificateARN,
+
+		// RDS database
+		DatabaseEngine:        d.config.DatabaseEngine,
+		DatabaseInstanceClass: d.config.DatabaseInstanceClass,
+		DatabaseStorage:       d.config.DatabaseStorage,
+
+		// EC2 sizing
+		VolumeSize: d.config.EC2VolumeSize,
+		AMI:        d.config.AMI,
+		UserData:   d.config.UserData,
+		VPCID:      d.config.VPCID,
+		SubnetIDs:  d.config.SubnetIDs,
+		SSHKeyName: d.config.SSHKeyName,
+
+		// Lambda sizing
+		LambdaMemory:              d.config.LambdaMemory,
+		LambdaTimeout:             d.config.LambdaTimeout,
+		LambdaReservedConcurrency: d.config.LambdaReservedConcurrency,
+		LambdaArchitecture:        d.config.LambdaArchitecture,
+		LambdaContainer:           d.config.LambdaContainer,
+
+		// API Gateway
+		CORSAllowOrigins: d.config.CORSAllowOrigins,
+		CORSAllowMethods: d.config.CORSAllowMethods,
+		CORSAllowHeaders: d.config.CORSAllowHeaders,
+		AuthorizerARN:    d.config.AuthorizerARN,
+
+		// EKS sizing
+		EKSNodeType:       d.config.EKSNodeType,
+		EKSMinNodes:       d.config.EKSMinNodes,
+		EKSMaxNodes:       d.config.EKSMaxNodes,
+		EKSDesiredNodes:   d.config.EKSDesiredNodes,
+		EKSNodeVolumeSize: d.config.EKSNodeVolumeSize,
+		EKSFargate:        d.config.EKSFargate,
+		EKSVersion:        d.config.EKSVersion,
+		EKSNATGateway:     d.config.EKSNATGateway,
+		EKSAddons:         d.config.EKSAddons,
+		AppPolicyARNs:     d.config.AppPolicyARNs,
+		EKSAdminARNs:      d.config.EKSAdminARNs,
+		Replicas:          d.config.Replicas,
+		K8sCPURequest:     d.config.K8sResources.CPURequest,
+		K8sCPULimit:       d.config.K8sResources.CPULimit,
+		K8sMemoryRequest:  d.config.K8sResources.MemoryRequest,
+		K8sMemoryLimit:    d.config.K8sResources.MemoryLimit,
+
+		// Autoscaling
+		AutoscaleTargetCPU: d.config.AutoscaleTargetCPU,
+
+		// CloudWatch Logs
+		LogRetentionDays: d.config.LogRetentionDays,
+
+		// AWS identity
+		AWSRoleARN: d.config.AWSRoleARN,
+
+		// Provider versions
+		AWSProviderVersion: d.config.AWSProviderVersion,
+	}
+
+	// Set EC2 instance type if provided or use LLM suggestion
+	if d.config.EC2InstanceType != "" {
+		tfConfig.InstanceType = d.config.EC2InstanceType
+	} else if d.llmClient != nil {
+		tfConfig.InstanceType = d.llmClient.SuggestInstanceType(d.config.Analysis)
+	} else {
+		tfConfig.InstanceType = "t3.micro" // Default
+	}
+
+	return tfConfig
+}
+
+// Generate writes the Terraform configuration to outDir without deploying it: no deployment
+// record, state backend or AWS call (generate command). Terraform keeps its state locally
+// unless the caller adds a backend.
+func (d *Deployer) Generate(outDir string) (*types.TerraformConfig, error) {
+	tfConfig := d.terraformConfig("")
+	if err := NewIacGenerator(d.config.IacEngine, outDir, d.config.Verbose).Generate(tfConfig); err != nil {
+		return nil, fmt.Errorf("failed to generate %s config: %w", d.engine(), err)
+	}
+	return tfConfig, nil
+}
+
+// exportPlan copies the generated Terraform to PlanOutDir and records the deployment as planned
+func (d *Deployer) exportPlan(ctx context.Context, deployment *store.Deployment, tfDir string) (*types.DeploymentResult, error) {
+	planOutDir, err := exportTerraform(tfDir, d.config.PlanOutDir)
+	if err != nil {
+		if d.store != nil {
+			_ = d.store.UpdateStatus(ctx, deployment.ID, store.DeploymentStatusFailed, err.Error())
+		}
+		return nil, fmt.Errorf("failed to export Terraform configuration: %w", err)
+	}
+
+	deployment.Status = store.DeploymentStatusPlanned
+	deployment.PlanOutDir = planOutDir
+	if d.store != nil {
+		if err := d.store.Update(ctx, deployment); err != nil {
+			return nil, fmt.Errorf("failed to update deployment record: %w", err)
+		}
+	}
+
+	d.addEvent(ctx, store.DeploymentStatusPlanned, fmt.Sprintf("Terraform exported to %s", planOutDir))
+
+	if d.config.Verbose {
+		fmt.Fprintf(console.Stdout, "   ✓ Terraform configuration exported to %s\n", planOutDir)
+	}
+
+	return &types.DeploymentResult{
+		DeploymentID:  deployment.ID,
+		Status:        string(store.DeploymentStatusPlanned),
+		Strategy:      d.config.Strategy,
+		Region:        d.config.AWSRegion,
+		Outputs:       map[string]string{},
+		TerraformDir:  tfDir,
+		PlanOutDir:    planOutDir,
+		Warnings:      []string{},
+		Optimizations: []string{},
+	}, nil
+}
+
+// apply runs terraform apply, re-running it after errors known to be transient (e.g. an IAM
+// role not propagated yet): apply is idempotent, so a re-run completes the partial deployment
+func (d *Deployer) apply(ctx context.Context, executor IacExecutor) error {
+	retries := terraform.ApplyRetries(d.config.Strategy, d.config.ApplyRetries)
+
+	for attempt := 1; ; attempt++ {
+		err := executor.Apply()
+		if err == nil {
+			return nil
+		}
+		reason, retryable := terraform.RetryableError(err)
+		if !retryable || attempt > retries || d.ctx.Err() != nil {
+			return err
+		}
+
+		delay := time.Duration(attempt) * applyRetryDelay
+		d.addEvent(ctx, store.DeploymentStatusRunning, fmt.Sprintf("Terraform apply failed (%s), retry %d/%d", reason, attempt, retries))
+		fmt.Fprintf(console.Stdout, "   ⚠️  Terraform apply failed (%s), retrying in %s (%d/%d)...\n", reason, delay, attempt, retries)
+
+		select {
+		case <-d.ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+	}
+}
+
+// rollback destroys the resources of a failed apply (--destroy-on-failure) and returns the
+// apply error, along with the destroy error when the rollback fails too
+func (d *Deployer) rollback(ctx context.Context, executor IacExecutor, applyErr error) error {
+	fmt.Fprintf(console.Stdout, "   Apply failed, destroying the partially created resources (--destroy-on-failure)...\n")
+	d.addEvent(ctx, store.DeploymentStatusFailed, "Rollback started")
+
+	if err := executor.Destroy(); err != nil {
+		d.addEvent(ctx, store.DeploymentStatusFailed, fmt.Sprintf("Rollback failed: %s destroy failed: %v", d.engine(), err))
+		return fmt.Errorf("%w; rollback failed, resources may be left behind (retry with 'scia destroy %s'): %s destroy failed: %w",
+			applyErr, d.deploymentID, d.engine(), err)
+	}
+
+	if d.store != nil {
+		_ = d.store.UpdateStatus(ctx, d.deploymentID, store.DeploymentStatusDestroyed, fmt.Sprintf("rolled back after %v", applyErr))
+	}
+	return fmt.Errorf("%w (rolled back: the partially created resources were destroyed)", applyErr)
+}
+
+// engine returns the name of the infrastructure as code engine, for messages
+func (d *Deployer) engine() string {
+	if d.config.IacEngine == EnginePulumi {
+		return "pulumi"
+	}
+	return "terraform"
+}
+
+// iacBin returns the binary of the infrastructure as code engine
+func (d *Deployer) iacBin() string {
+	if d.config.IacEngine == EnginePulumi {
+		return d.config.PulumiBin
+	}
+	return d.config.TerraformBin
+}
+
+// failureMessage returns the error message recorded for a failed deployment, prefixed with
+// the cancellation cause (e.g. "canceled: interrupt received") when the deployment was canceled
+func (d *Deployer) failureMessage(message string) string {
+	if d.ctx.Err() == nil {
+		return message
+	}
+	return fmt.Sprintf("%v: %s", context.Cause(d.ctx), message)
+}
+
+// addEvent records an event in the deployment's audit log; failures are only reported in verbose mode
+func (d *Deployer) addEvent(ctx context.Context, status store.DeploymentStatus, message string) {
+	if d.store == nil {
+		return
+	}
+	if err := d.store.AddEvent(ctx, d.deploymentID, status, message); err != nil && d.config.Verbose {
+		fmt.Fprintf(console.Stdout, "   Warning: failed to record deployment event: %v\n", err)
+	}
+}
+
+// sourcePath returns the analyzed copy the generated code builds from: zip archives and local
+// directories are not Git repositories it could clone
+func (d *Deployer) sourcePath() string {
+	if !d.config.Analysis.LocalSource {
+		return ""
+	}
+	return d.config.Analysis.RepoPath
+}
+
 // extractAppName extracts application name from repository URL or path
 func (d *Deployer) extractAppName() string {
 	// Extract from repo URL: https://github.com/user/repo-name -> repo-name
@@ -318,15 +637,20 @@ func (d *Deployer) extractAppName() string {
 	return "scia-app"
 }
 
-// generateBackend generates the backend.tf file for S3 state storage
+// generateBackend generates the backend.tf file for S3 state storage (Pulumi keeps its state
+// in the backend of PULUMI_BACKEND_URL)
 func (d *Deployer) generateBackend(tfDir string, deploymentStateKey string) error {
+	if d.config.IacEngine == EnginePulumi {
+		return nil
+	}
+
 	// Read backend configuration from viper
 	backendType := viper.GetString("terraform.backend.type")
 
 	// Only generate backend.tf if S3 backend is configured
 	if backendType != "s3" {
 		if d.config.Verbose {
-			fmt.Printf("   No S3 backend configured, using local state\n")
+			fmt.Fprintf(console.Stdout, "   No S3 backend configured, using local state\n")
 		}
 		return nil
 	}
@@ -337,7 +661,7 @@ func (d *Deployer) generateBackend(tfDir string, deploymentStateKey string) erro
 	// Validate required fields
 	if s3Bucket == "" || s3Region == "" {
 		if d.config.Verbose {
-			fmt.Printf("   S3 backend not fully configured, using local state\n")
+			fmt.Fprintf(console.Stdout, "   S3 backend not fully configured, using local state\n")
 		}
 		return nil
 	}
@@ -346,7 +670,7 @@ func (d *Deployer) generateBackend(tfDir string, deploymentStateKey string) erro
 	s3Key := deploymentStateKey
 
 	if d.config.Verbose {
-		fmt.Printf("   Configuring S3 backend: bucket=%s, region=%s, key=%s\n",
+		fmt.Fprintf(console.Stdout, "   Configuring S3 backend: bucket=%s, region=%s, key=%s\n",
 			s3Bucket, s3Region, s3Key)
 	}
 
@@ -355,6 +679,7 @@ func (d *Deployer) generateBackend(tfDir string, deploymentStateKey string) erro
 		BucketName: s3Bucket,
 		Region:     s3Region,
 		Key:        s3Key,
+		RoleARN:    d.config.AWSRoleARN,
 	}
 
 	backendFile, err := backend.WriteBackendTF(tfDir, backendCfg)
@@ -363,7 +688,7 @@ func (d *Deployer) generateBackend(tfDir string, deploymentStateKey string) erro
 	}
 
 	if d.config.Verbose {
-		fmt.Printf("   ✓ Generated backend.tf at %s\n", backendFile)
+		fmt.Fprintf(console.Stdout, "   ✓ Generated backend.tf at %s\n", backendFile)
 	}
 
 	return nil
@@ -0,0 +1,88 @@
+package deployer
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Static provisioning times of a deploy (terraform init and apply, health check), by strategy
+var strategyBaselines = map[string]time.Duration{
+	"vm":         4 * time.Minute,
+	"serverless": 2 * time.Minute,
+	"kubernetes": 18 * time.Minute,
+}
+
+// Provisioning time added to the baseline by an RDS database and by a custom domain
+// (certificate DNS validation)
+const (
+	databaseBaseline = 8 * time.Minute
+	domainBaseline   = 3 * time.Minute
+)
+
+// MinDurationSamples is the number of past deployments of the same strategy (with or without
+// database) from which their median replaces the static baseline
+const MinDurationSamples = 3
+
+// PastDeploy is the provisioning time of a past successful deployment
+type PastDeploy struct {
+	Strategy string
+	Database bool
+	Duration time.Duration
+}
+
+// DurationEstimate is the estimated provisioning time of a plan
+type DurationEstimate struct {
+	Seconds int `json:"seconds"`
+	Samples int `json:"samples,omitempty"` // Past deployments the estimate is the median of, 0 for the static baseline
+}
+
+// Duration returns the estimate as a duration
+func (e DurationEstimate) Duration() time.Duration {
+	return time.Duration(e.Seconds) * time.Second
+}
+
+// String returns the estimate rounded to the minute (e.g. ~18 min)
+func (e DurationEstimate) String() string {
+	return fmt.Sprintf("~%d min", max(int(e.Duration().Round(time.Minute).Minutes()), 1))
+}
+
+// EstimateDuration estimates how long deploying config takes: the median of the past
+// deployments of the same strategy (with or without database) in config.DeployHistory once
+// there are MinDurationSamples of them, a static baseline otherwise
+func EstimateDuration(config *DeployConfig) DurationEstimate {
+	database := config.DatabaseEngine != ""
+
+	var durations []time.Duration
+	for _, past := range config.DeployHistory {
+		if past.Strategy == config.Strategy && past.Database == database && past.Duration > 0 {
+			durations = append(durations, past.Duration)
+		}
+	}
+	if len(durations) >= MinDurationSamples {
+		return DurationEstimate{Seconds: int(median(durations).Seconds()), Samples: len(durations)}
+	}
+
+	estimate, ok := strategyBaselines[config.Strategy]
+	if !ok {
+		estimate = strategyBaselines["vm"]
+	}
+	if database {
+		estimate += databaseBaseline
+	}
+	if config.Domain != "" {
+		estimate += domainBaseline
+	}
+	return DurationEstimate{Seconds: int(estimate.Seconds())}
+}
+
+// median returns the median of durations (not empty)
+func median(durations []time.Duration) time.Duration {
+	sorted := slices.Clone(durations)
+	slices.Sort(sorted)
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
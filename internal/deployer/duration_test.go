@@ -0,0 +1,43 @@
+package deployer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEstimateDuration(t *testing.T) {
+	history := []PastDeploy{
+		{Strategy: "kubernetes", Duration: 14 * time.Minute},
+		{Strategy: "kubernetes", Duration: 22 * time.Minute},
+		{Strategy: "kubernetes", Duration: 16 * time.Minute},
+		{Strategy: "kubernetes", Duration: 30 * time.Minute, Database: true},
+		{Strategy: "vm", Duration: 5 * time.Minute},
+		{Strategy: "vm", Duration: 7 * time.Minute},
+		{Strategy: "vm", Duration: 0}, // Re-imported by reconcile
+	}
+
+	tests := []struct {
+		name        string
+		config      DeployConfig
+		wantMinutes int
+		wantSamples int
+	}{
+		{"serverless baseline", DeployConfig{Strategy: "serverless"}, 2, 0},
+		{"vm with database and domain baseline", DeployConfig{Strategy: "vm", DatabaseEngine: "postgres", Domain: "app.example.com"}, 15, 0},
+		{"median of past deployments", DeployConfig{Strategy: "kubernetes", DeployHistory: history}, 16, 3},
+		{"too few past deployments with database", DeployConfig{Strategy: "kubernetes", DatabaseEngine: "mysql", DeployHistory: history}, 26, 0},
+		{"zero durations ignored", DeployConfig{Strategy: "vm", DeployHistory: history}, 4, 0},
+		{"even number of samples", DeployConfig{Strategy: "vm", DeployHistory: append(history,
+			PastDeploy{Strategy: "vm", Duration: 9 * time.Minute},
+			PastDeploy{Strategy: "vm", Duration: 11 * time.Minute})}, 8, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EstimateDuration(&tt.config)
+			if got.Duration() != time.Duration(tt.wantMinutes)*time.Minute || got.Samples != tt.wantSamples {
+				t.Errorf("EstimateDuration() = %s from %d samples, want %d min from %d samples", got.Duration(), got.Samples, tt.wantMinutes, tt.wantSamples)
+			}
+		})
+	}
+}
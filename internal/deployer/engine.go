@@ -0,0 +1,73 @@
+package deployer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Smana/scai/internal/pulumi"
+	"github.com/Smana/scai/internal/terraform"
+	"github.com/Smana/scai/internal/types"
+)
+
+// Infrastructure as code engines (iac.engine)
+const (
+	EngineTerraform = "terraform"
+	EnginePulumi    = "pulumi"
+)
+
+// IacGenerator writes the infrastructure code of a deployment to a directory
+type IacGenerator interface {
+	Generate(config *types.TerraformConfig) error
+}
+
+// IacExecutor provisions and destroys the infrastructure code written by an IacGenerator
+type IacExecutor interface {
+	SetContext(ctx context.Context)
+	Init() error
+	Apply() error
+	Destroy() error
+	Outputs() (map[string]string, error)
+}
+
+// ValidateEngine returns an error for an unknown engine
+func ValidateEngine(engine string) error {
+	switch engine {
+	case "", EngineTerraform, EnginePulumi:
+		return nil
+	default:
+		return fmt.Errorf("invalid iac.engine %q: expected terraform or pulumi", engine)
+	}
+}
+
+// NewIacGenerator returns the generator of engine writing to outputDir
+func NewIacGenerator(engine, outputDir string, verbose bool) IacGenerator {
+	if engine == EnginePulumi {
+		return pulumi.NewGenerator(outputDir, verbose)
+	}
+	return terraform.NewGenerator(outputDir, verbose)
+}
+
+// NewIacExecutor returns the executor of engine running bin in workDir; the Pulumi stack is
+// named after deploymentID
+func NewIacExecutor(engine, workDir, bin, deploymentID string, verbose bool) (IacExecutor, error) {
+	if engine == EnginePulumi {
+		return pulumi.NewExecutor(workDir, bin, deploymentID, verbose)
+	}
+	return terraform.NewExecutor(workDir, bin, verbose)
+}
+
+// CheckEngine returns an error when the engine of config cannot deploy it: the Pulumi programs
+// only cover the vm strategy, without database or custom domain
+func CheckEngine(config *DeployConfig) error {
+	if err := ValidateEngine(config.IacEngine); err != nil {
+		return err
+	}
+	if config.IacEngine != EnginePulumi {
+		return nil
+	}
+	return pulumi.Supported(&types.TerraformConfig{
+		Strategy:       config.Strategy,
+		DatabaseEngine: config.DatabaseEngine,
+		Domain:         config.Domain,
+	})
+}
@@ -0,0 +1,60 @@
+package deployer
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// exportTerraform copies the generated files of tfDir (*.tf, backend.tf included, and build
+// files such as Dockerfile.lambda) to outDir, which is created if needed. Files already in
+// outDir with the same names are replaced. It returns the absolute path of outDir.
+func exportTerraform(tfDir, outDir string) (string, error) {
+	absOutDir, err := filepath.Abs(outDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", outDir, err)
+	}
+	if err := os.MkdirAll(absOutDir, 0o750); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", absOutDir, err)
+	}
+
+	entries, err := os.ReadDir(tfDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", tfDir, err)
+	}
+
+	for _, entry := range entries {
+		// Skip directories and hidden files (Terraform working data)
+		if !entry.Type().IsRegular() || entry.Name()[0] == '.' {
+			continue
+		}
+		if err := copyGeneratedFile(filepath.Join(tfDir, entry.Name()), filepath.Join(absOutDir, entry.Name())); err != nil {
+			return "", fmt.Errorf("failed to export %s: %w", entry.Name(), err)
+		}
+	}
+
+	return absOutDir, nil
+}
+
+// copyGeneratedFile copies a generated file, readable by the user's tools
+func copyGeneratedFile(src, dest string) error {
+	// #nosec G304 -- src is a file generated in the deployment's Terraform directory
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+
+	// #nosec G302 G304 -- dest is in the directory given with --plan-out
+	out, err := os.OpenFile(dest, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
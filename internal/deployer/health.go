@@ -4,11 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
 	"os/exec"
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/Smana/scai/internal/console"
+	"github.com/Smana/scai/internal/network"
+	"github.com/Smana/scai/internal/terraform"
 )
 
 // InstanceInfo contains information about an EC2 instance
@@ -22,7 +25,7 @@ type InstanceInfo struct {
 // GetASGInstance retrieves the public IP of the first running instance in an ASG
 func GetASGInstance(ctx context.Context, asgName, region string, verbose bool) (*InstanceInfo, error) {
 	if verbose {
-		fmt.Printf("   Looking up instance in ASG: %s\n", asgName)
+		fmt.Fprintf(console.Stdout, "   Looking up instance in ASG: %s\n", asgName)
 	}
 
 	// Get instance IDs from ASG
@@ -49,7 +52,7 @@ func GetASGInstance(ctx context.Context, asgName, region string, verbose bool) (
 
 	instanceID := instanceIDs[0]
 	if verbose {
-		fmt.Printf("   Found instance: %s\n", instanceID)
+		fmt.Fprintf(console.Stdout, "   Found instance: %s\n", instanceID)
 	}
 
 	// Get instance details
@@ -86,33 +89,31 @@ func GetASGInstance(ctx context.Context, asgName, region string, verbose bool) (
 // WaitForASGInstance waits for an instance to be running in the ASG
 func WaitForASGInstance(ctx context.Context, asgName, region string, timeout time.Duration, verbose bool) (*InstanceInfo, error) {
 	if verbose {
-		fmt.Printf("   Waiting for instance to be ready (timeout: %v)...\n", timeout)
+		fmt.Fprintf(console.Stdout, "   Waiting for instance to be ready (timeout: %v)...\n", timeout)
 	}
 
 	deadline := time.Now().Add(timeout)
-	ticker := time.NewTicker(10 * time.Second)
-	defer ticker.Stop()
+	poll := newBackoff(deadline)
 
 	for {
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		case <-ticker.C:
-			info, err := GetASGInstance(ctx, asgName, region, false)
-			if err == nil && info.State == "running" && info.PublicIP != "" {
-				if verbose {
-					fmt.Printf("   ✓ Instance is running: %s (IP: %s)\n", info.InstanceID, info.PublicIP)
-				}
-				return info, nil
-			}
+		if err := poll.wait(ctx); err != nil {
+			return nil, err
+		}
 
-			if verbose && err != nil {
-				fmt.Printf("   Still waiting for instance... (%v)\n", err)
+		info, err := GetASGInstance(ctx, asgName, region, false)
+		if err == nil && info.State == "running" && info.PublicIP != "" {
+			if verbose {
+				fmt.Fprintf(console.Stdout, "   ✓ Instance is running: %s (IP: %s)\n", info.InstanceID, info.PublicIP)
 			}
+			return info, nil
+		}
 
-			if time.Now().After(deadline) {
-				return nil, fmt.Errorf("timeout waiting for instance to be ready")
-			}
+		if verbose && err != nil {
+			fmt.Fprintf(console.Stdout, "   Still waiting for instance... (%v)\n", err)
+		}
+
+		if !time.Now().Before(deadline) {
+			return nil, fmt.Errorf("timeout waiting for instance to be ready")
 		}
 	}
 }
@@ -120,49 +121,46 @@ func WaitForASGInstance(ctx context.Context, asgName, region string, timeout tim
 // WaitForApplicationReady waits for the application to respond to HTTP requests
 func WaitForApplicationReady(ctx context.Context, url string, timeout time.Duration, verbose bool) error {
 	if verbose {
-		fmt.Printf("   Waiting for application to be ready at %s (timeout: %v)...\n", url, timeout)
+		fmt.Fprintf(console.Stdout, "   Waiting for application to be ready at %s (timeout: %v)...\n", url, timeout)
 	}
 
 	deadline := time.Now().Add(timeout)
-	ticker := time.NewTicker(10 * time.Second)
-	defer ticker.Stop()
+	poll := newBackoff(deadline)
 
-	client := &http.Client{
-		Timeout: 5 * time.Second,
-	}
+	client := network.NewClient(5 * time.Second)
 
 	attempt := 0
 	for {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case <-ticker.C:
-			attempt++
-			resp, err := client.Get(url)
-			if err == nil {
-				_ = resp.Body.Close()
-				if resp.StatusCode < 500 {
-					if verbose {
-						fmt.Printf("   ✓ Application is ready! (HTTP %d)\n", resp.StatusCode)
-					}
-					return nil
-				}
+		if err := poll.wait(ctx); err != nil {
+			return err
+		}
+
+		attempt++
+		resp, err := client.Get(url)
+		if err == nil {
+			_ = resp.Body.Close()
+			if resp.StatusCode < 500 {
 				if verbose {
-					fmt.Printf("   Attempt %d: Received HTTP %d, waiting...\n", attempt, resp.StatusCode)
+					fmt.Fprintf(console.Stdout, "   ✓ Application is ready! (HTTP %d)\n", resp.StatusCode)
 				}
-			} else if verbose {
-				fmt.Printf("   Attempt %d: %v\n", attempt, err)
+				return nil
 			}
-
-			if time.Now().After(deadline) {
-				return fmt.Errorf("timeout waiting for application to be ready")
+			if verbose {
+				fmt.Fprintf(console.Stdout, "   Attempt %d: Received HTTP %d, waiting...\n", attempt, resp.StatusCode)
 			}
+		} else if verbose {
+			fmt.Fprintf(console.Stdout, "   Attempt %d: %v\n", attempt, err)
+		}
+
+		if !time.Now().Before(deadline) {
+			return fmt.Errorf("timeout waiting for application to be ready")
 		}
 	}
 }
 
-// GetApplicationURL constructs the application URL and waits for it to be ready
-func GetApplicationURL(ctx context.Context, asgName, region string, port int, verbose bool) (string, error) {
+// GetApplicationURL constructs the application URL and waits for its health check path
+// ("/" if empty) to be ready
+func GetApplicationURL(ctx context.Context, asgName, region string, port int, healthCheckPath string, verbose bool) (string, error) {
 	// Wait for instance to be running (5 minute timeout)
 	info, err := WaitForASGInstance(ctx, asgName, region, 5*time.Minute, verbose)
 	if err != nil {
@@ -172,8 +170,12 @@ func GetApplicationURL(ctx context.Context, asgName, region string, port int, ve
 	// Construct URL
 	url := fmt.Sprintf("http://%s:%d", info.PublicIP, port)
 
+	if healthCheckPath == "" {
+		healthCheckPath = "/"
+	}
+
 	// Wait for application to be ready (5 minute timeout)
-	if err := WaitForApplicationReady(ctx, url, 5*time.Minute, verbose); err != nil {
+	if err := WaitForApplicationReady(ctx, url+healthCheckPath, 5*time.Minute, verbose); err != nil {
 		// Return URL even if health check fails, with a warning
 		return url, fmt.Errorf("application may not be ready yet: %w (URL: %s)", err, url)
 	}
@@ -181,6 +183,42 @@ func GetApplicationURL(ctx context.Context, asgName, region string, port int, ve
 	return url, nil
 }
 
+// AddApplicationURL adds the URL of the instance of a vm deployment to its Terraform outputs
+// (application_url and application_status, and app_url without a custom domain), from the
+// asg_name and application_port outputs. The instance is not known to Terraform, only to its
+// Auto Scaling Group: failures are only reported in verbose mode.
+func AddApplicationURL(ctx context.Context, outputs map[string]string, region, healthCheckPath string, verbose bool) {
+	asgName, ok := outputs["asg_name"]
+	if !ok {
+		return
+	}
+	port, err := ParsePort(outputs["application_port"])
+	if err != nil {
+		return
+	}
+
+	if verbose {
+		fmt.Fprintf(console.Stdout, "   Checking application availability...\n")
+	}
+	appURL, err := GetApplicationURL(ctx, asgName, region, port, healthCheckPath, verbose)
+	if err != nil {
+		// Log warning but don't fail
+		if verbose {
+			fmt.Fprintf(console.Stdout, "   Warning: %v\n", err)
+		}
+		outputs["application_url"] = appURL
+		outputs["application_status"] = "Application may still be starting up. Please wait a few minutes."
+	} else {
+		outputs["application_url"] = appURL
+		outputs["application_status"] = "Application is ready!"
+	}
+
+	// Without a custom domain, the VM URL is only known once the instance is up
+	if outputs[terraform.AppURLOutput] == "" {
+		outputs[terraform.AppURLOutput] = appURL
+	}
+}
+
 // ParsePort converts a string port to int
 func ParsePort(portStr string) (int, error) {
 	port, err := strconv.Atoi(strings.TrimSpace(portStr))
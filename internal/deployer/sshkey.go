@@ -0,0 +1,54 @@
+package deployer
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// SSHKeyPath returns where the private key generated for a deployment (--generate-key) is saved:
+// ~/.scai/keys/<deployment-id>.pem
+func SSHKeyPath(deploymentID string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".scai", "keys", deploymentID+".pem"), nil
+}
+
+// generateSSHKey creates an ed25519 key for the instances of a deployment, saves the private
+// key to SSHKeyPath (readable by the user only) and returns the public key in authorized_keys format
+func generateSSHKey(deploymentID string) (string, error) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate SSH key: %w", err)
+	}
+
+	block, err := ssh.MarshalPrivateKey(privateKey, "scai deployment "+deploymentID)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode SSH private key: %w", err)
+	}
+	sshPublicKey, err := ssh.NewPublicKey(publicKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode SSH public key: %w", err)
+	}
+
+	keyPath, err := SSHKeyPath(deploymentID)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(keyPath), 0o700); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", filepath.Dir(keyPath), err)
+	}
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(block), 0o600); err != nil {
+		return "", fmt.Errorf("failed to save SSH private key: %w", err)
+	}
+
+	return strings.TrimSpace(string(ssh.MarshalAuthorizedKey(sshPublicKey))), nil
+}
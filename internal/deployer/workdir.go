@@ -0,0 +1,76 @@
+package deployer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Smana/scai/internal/store"
+	"github.com/Smana/scai/internal/types"
+)
+
+// sourceDirs are the directories of the work directory holding the analyzed sources:
+// the cloned repository and the extracted zip archives
+var sourceDirs = []string{"repo", "repos"}
+
+// CleanupWorkDir removes what a successful deployment left in workDir and scai no longer needs:
+// the analyzed sources and, when the Terraform was exported with --plan-out, the Terraform
+// directory. The Terraform directory of an applied deployment is kept, destroy runs in it.
+func CleanupWorkDir(workDir string, result *types.DeploymentResult) ([]string, error) {
+	removed, err := CleanupSources(workDir)
+	if err != nil {
+		return removed, err
+	}
+
+	if result.Status == string(store.DeploymentStatusPlanned) {
+		ok, err := removeTerraformDir(result.TerraformDir, result.DeploymentID)
+		if err != nil {
+			return removed, err
+		}
+		if ok {
+			removed = append(removed, result.TerraformDir)
+		}
+	}
+	return removed, nil
+}
+
+// CleanupSources removes the repositories cloned or extracted in workDir (local directories
+// are analyzed in place and never copied there)
+func CleanupSources(workDir string) ([]string, error) {
+	var removed []string
+	for _, name := range sourceDirs {
+		dir := filepath.Join(workDir, name)
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			continue
+		}
+		if err := os.RemoveAll(dir); err != nil {
+			return removed, fmt.Errorf("failed to remove %s: %w", dir, err)
+		}
+		removed = append(removed, dir)
+	}
+	return removed, nil
+}
+
+// RemoveTerraformDir removes the Terraform directory of a deployment that no longer needs it,
+// destroyed or exported with --plan-out, and reports whether there was one to remove
+func RemoveTerraformDir(deployment *store.Deployment) (bool, error) {
+	if deployment.Status != store.DeploymentStatusDestroyed && deployment.Status != store.DeploymentStatusPlanned {
+		return false, fmt.Errorf("deployment %s is %s: its Terraform directory is needed to destroy it", deployment.ID, deployment.Status)
+	}
+	return removeTerraformDir(deployment.TerraformDir, deployment.ID)
+}
+
+// removeTerraformDir removes dir if it is the Terraform directory Deploy created for the
+// deployment id (named after it), and reports whether it existed
+func removeTerraformDir(dir, id string) (bool, error) {
+	if dir == "" || filepath.Base(dir) != id {
+		return false, nil
+	}
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return false, nil
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return false, fmt.Errorf("failed to remove %s: %w", dir, err)
+	}
+	return true, nil
+}
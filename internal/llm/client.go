@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/Smana/scai/internal/rules"
@@ -39,7 +40,7 @@ func NewClient(baseURL, model string) *Client {
 	}
 
 	// Load deployment rules
-	deploymentRules, err := rules.LoadRules("configs/deployment_rules.yaml")
+	deploymentRules, err := rules.LoadRules(rules.DefaultRulesPath)
 	if err != nil {
 		logger.Printf("Warning: Failed to load deployment rules: %v", err)
 	}
@@ -60,7 +61,7 @@ func NewClientWithConfig(config *ProviderConfig, verbose bool) (*Client, error)
 	}
 
 	// Load deployment rules
-	deploymentRules, err := rules.LoadRules("configs/deployment_rules.yaml")
+	deploymentRules, err := rules.LoadRules(rules.DefaultRulesPath)
 	if err != nil {
 		logger.Printf("Warning: Failed to load deployment rules: %v", err)
 	}
@@ -76,7 +77,7 @@ func NewClientWithConfig(config *ProviderConfig, verbose bool) (*Client, error)
 // This allows reusing a pre-configured ProviderManager (e.g., from initializeLLMProvider)
 func NewClientWithManager(pm *ProviderManager, config *ProviderConfig) *Client {
 	// Load deployment rules
-	deploymentRules, err := rules.LoadRules("configs/deployment_rules.yaml")
+	deploymentRules, err := rules.LoadRules(rules.DefaultRulesPath)
 	if err != nil {
 		logger.Printf("Warning: Failed to load deployment rules: %v", err)
 	}
@@ -88,70 +89,9 @@ func NewClientWithManager(pm *ProviderManager, config *ProviderConfig) *Client {
 	}
 }
 
-// DetermineStrategy uses LLM with comprehensive context to determine deployment strategy
-// Uses 3-tier decision architecture: Rules → LLM → Heuristics
-// Supports multiple providers with automatic fallback
-func (c *Client) DetermineStrategy(userPrompt string, analysis *types.Analysis) (string, error) {
-	// TIER 1: Try rule-based decision FIRST (fast, deterministic)
-	if c.rules != nil {
-		if ruleMatch, matched := rules.EvaluateRules(c.rules, analysis); matched {
-			if analysis.Verbose {
-				logger.Printf("Rule-Based Decision: %s\nRule: %s\nReason: %s\n",
-					ruleMatch.Strategy, ruleMatch.RuleName, ruleMatch.Reason)
-			}
-			return ruleMatch.Strategy, nil
-		}
-	}
-
-	// TIER 2: If no rule matches or LLM is unavailable, try LLM (intelligent)
-	if c.providerManager == nil {
-		if analysis.Verbose {
-			logger.Printf("No LLM providers available, using heuristics")
-		}
-		strategy := c.fallbackStrategy(analysis)
-		return strategy, nil
-	}
-
-	ctx := context.Background()
-
-	// Build comprehensive prompt with knowledge base and examples
-	prompt := c.buildStrategyPrompt(userPrompt, analysis)
-
-	// Create generation request
-	req := &GenerateRequest{
-		Model:       c.config.DefaultModel,
-		Prompt:      prompt,
-		Temperature: 0.7,
-		MaxTokens:   200,
-	}
-
-	// Generate using provider manager (with automatic fallback)
-	resp, err := c.providerManager.Generate(ctx, req)
-	if err != nil {
-		// TIER 3: If all providers fail, fall back to heuristics
-		logger.Printf("All LLM providers failed: %v, using heuristics", err)
-		strategy := c.fallbackStrategy(analysis)
-		return strategy, nil
-	}
-
-	// Parse response
-	strategy, reason := c.parseStrategyResponse(resp.Text)
-
-	if strategy == "" {
-		// TIER 3: Fallback to heuristics if LLM response is unclear
-		strategy = c.fallbackStrategy(analysis)
-		reason = "Fallback heuristic (LLM response unclear)"
-		if analysis.Verbose {
-			logger.Printf("Heuristic Decision: %s\nReason: %s\n", strategy, reason)
-		}
-	} else {
-		// Log the decision
-		if analysis.Verbose {
-			logger.Printf("LLM Decision: %s\nReason: %s\nModel: %s\n", strategy, reason, resp.Model)
-		}
-	}
-
-	return strategy, nil
+// SetRules replaces the deployment rules evaluated before asking the LLM
+func (c *Client) SetRules(deploymentRules *types.DeploymentRules) {
+	c.rules = deploymentRules
 }
 
 // buildStrategyPrompt constructs the full prompt with context
@@ -169,11 +109,13 @@ func (c *Client) buildStrategyPrompt(userPrompt string, analysis *types.Analysis
 	// Add the specific question with analysis
 	// NOTE: Analysis parameters come FIRST, userPrompt is LAST (lower priority)
 	prompt := fmt.Sprintf(DecisionPromptTemplate,
-		analysis.Framework,
+		DescribeFramework(analysis),
 		analysis.Language,
 		len(analysis.Dependencies),
 		analysis.HasDockerfile,
-		analysis.HasDockerCompose,
+		describeCompose(analysis),
+		analysis.RequiresDatabase,
+		analysis.RequiresCache,
 		analysis.Port,
 		analysis.StartCommand,
 		c.estimateMemory(analysis),
@@ -185,6 +127,18 @@ func (c *Client) buildStrategyPrompt(userPrompt string, analysis *types.Analysis
 	return sb.String()
 }
 
+// describeCompose formats docker-compose services like the few-shot examples
+// (e.g. "Yes (4 services: app, redis, postgres, nginx)")
+func describeCompose(analysis *types.Analysis) string {
+	if !analysis.HasDockerCompose {
+		return "No"
+	}
+	if len(analysis.ComposeServices) == 0 {
+		return "Yes"
+	}
+	return fmt.Sprintf("Yes (%d services: %s)", len(analysis.ComposeServices), strings.Join(analysis.ComposeServices, ", "))
+}
+
 // parseStrategyResponse extracts strategy and reason from LLM response
 func (c *Client) parseStrategyResponse(response string) (strategy string, reason string) {
 	response = strings.TrimSpace(response)
@@ -217,34 +171,41 @@ func (c *Client) parseStrategyResponse(response string) (strategy string, reason
 	return strategy, reason
 }
 
-// fallbackStrategy provides heuristic-based fallback when LLM is unclear
-func (c *Client) fallbackStrategy(analysis *types.Analysis) string {
-	// Rule 1: Has docker-compose → Kubernetes
-	if analysis.HasDockerCompose {
-		return "kubernetes"
+// heuristicStrategy provides heuristic-based fallback when no rule matches and the LLM is
+// unavailable or unclear, with the reason of the choice
+func heuristicStrategy(analysis *types.Analysis) (strategy string, reason string) {
+	// Rule 1: Multi-service docker-compose → Kubernetes
+	// (a compose file whose services could not be parsed counts as multi-service)
+	if analysis.HasDockerCompose && len(analysis.ComposeServices) != 1 {
+		return "kubernetes", "docker-compose with several services"
 	}
 
 	// Rule 2: Stateless + minimal deps → Serverless
-	if c.isStateless(analysis) && len(analysis.Dependencies) < 5 {
-		return "serverless"
+	if isStateless(analysis) && len(analysis.Dependencies) < 5 {
+		return "serverless", "stateless app with few dependencies"
 	}
 
 	// Rule 3: High dependency count → Kubernetes
 	if len(analysis.Dependencies) > 20 {
-		return "kubernetes"
+		return "kubernetes", "more than 20 dependencies"
 	}
 
 	// Rule 4: Has Dockerfile but simple → VM
 	if analysis.HasDockerfile && len(analysis.Dependencies) < 15 {
-		return "vm"
+		return "vm", "containerized app with few dependencies"
 	}
 
 	// Default: VM (safest choice)
-	return "vm"
+	return "vm", "default strategy"
 }
 
 // isStateless checks if application is likely stateless
-func (c *Client) isStateless(analysis *types.Analysis) bool {
+func isStateless(analysis *types.Analysis) bool {
+	// Backing services detected by the analyzer make the app stateful
+	if analysis.RequiresDatabase || analysis.RequiresCache {
+		return false
+	}
+
 	// Check framework patterns
 	statelessFrameworks := []string{"fastapi", "express"}
 	for _, fw := range statelessFrameworks {
@@ -253,54 +214,60 @@ func (c *Client) isStateless(analysis *types.Analysis) bool {
 		}
 	}
 
-	// Check for stateful dependencies
-	statefulDeps := []string{"django", "rails", "postgres", "mysql", "mongodb", "redis", "sqlite"}
-	for _, dep := range analysis.Dependencies {
-		depLower := strings.ToLower(dep)
-		for _, stateful := range statefulDeps {
-			if strings.Contains(depLower, stateful) {
-				return false
-			}
-		}
-	}
-
 	return false
 }
 
 // estimateMemory provides a rough memory estimate based on framework
 func (c *Client) estimateMemory(analysis *types.Analysis) string {
+	minMB, maxMB := EstimateMemoryMB(analysis)
+	return formatMemoryMB(minMB) + "-" + formatMemoryMB(maxMB)
+}
+
+// EstimateMemoryMB returns the rough memory range of the application in MB, based on its
+// framework, or its language when the framework is unknown
+func EstimateMemoryMB(analysis *types.Analysis) (minMB, maxMB int) {
 	framework := strings.ToLower(analysis.Framework)
 
-	memoryMap := map[string]string{
-		"flask":     "256MB-512MB",
-		"django":    "512MB-1GB",
-		"fastapi":   "128MB-256MB",
-		"express":   "128MB-256MB",
-		"nextjs":    "256MB-512MB",
-		"go":        "50MB-200MB",
-		"rails":     "512MB-1GB",
-		"streamlit": "256MB-512MB",
+	memoryMap := map[string][2]int{
+		"flask":     {256, 512},
+		"django":    {512, 1024},
+		"fastapi":   {128, 256},
+		"express":   {128, 256},
+		"nextjs":    {256, 512},
+		"go":        {50, 200},
+		"rails":     {512, 1024},
+		"sinatra":   {64, 256},
+		"rack":      {64, 256},
+		"streamlit": {256, 512},
 	}
 
 	if mem, ok := memoryMap[framework]; ok {
-		return mem
+		return mem[0], mem[1]
 	}
 
 	// Default estimate based on language
-	languageMem := map[string]string{
-		"python":     "256MB-512MB",
-		"javascript": "128MB-256MB",
-		"typescript": "128MB-256MB",
-		"go":         "50MB-200MB",
-		"ruby":       "512MB-1GB",
-		"java":       "512MB-2GB",
+	languageMem := map[string][2]int{
+		"python":     {256, 512},
+		"javascript": {128, 256},
+		"typescript": {128, 256},
+		"go":         {50, 200},
+		"ruby":       {512, 1024},
+		"java":       {512, 2048},
 	}
 
 	if mem, ok := languageMem[strings.ToLower(analysis.Language)]; ok {
-		return mem
+		return mem[0], mem[1]
 	}
 
-	return "256MB-512MB" // Conservative default
+	return 256, 512 // Conservative default
+}
+
+// formatMemoryMB formats a memory size like the few-shot examples (e.g. 512MB, 1GB)
+func formatMemoryMB(mb int) string {
+	if mb >= 1024 && mb%1024 == 0 {
+		return fmt.Sprintf("%dGB", mb/1024)
+	}
+	return fmt.Sprintf("%dMB", mb)
 }
 
 // SuggestInstanceType recommends EC2 instance type based on analysis
@@ -308,7 +275,7 @@ func (c *Client) SuggestInstanceType(analysis *types.Analysis) string {
 	framework := strings.ToLower(analysis.Framework)
 
 	// Heavy frameworks
-	heavyFrameworks := []string{"django", "rails", "nextjs"}
+	heavyFrameworks := []string{"django", "rails", "nextjs", "spring-boot"}
 	for _, fw := range heavyFrameworks {
 		if framework == fw || len(analysis.Dependencies) > 20 {
 			return "t3.small" // 2 vCPU, 2GB
@@ -371,8 +338,9 @@ func (c *Client) SuggestOptimizations(analysis *types.Analysis, strategy string)
 	return suggestions
 }
 
-// ValidateDeploymentRequirements checks if deployment is feasible
-func (c *Client) ValidateDeploymentRequirements(analysis *types.Analysis, strategy string) []string {
+// ValidateDeploymentRequirements checks if deployment is feasible.
+// eksFargate reports whether the kubernetes strategy runs on Fargate instead of nodes.
+func (c *Client) ValidateDeploymentRequirements(analysis *types.Analysis, strategy string, eksFargate bool) []string {
 	var warnings []string
 
 	switch strategy {
@@ -382,6 +350,10 @@ func (c *Client) ValidateDeploymentRequirements(analysis *types.Analysis, strate
 			warnings = append(warnings, "⚠️  docker-compose detected but serverless recommended - this may not work")
 		}
 
+		if analysis.RequiresDatabase {
+			warnings = append(warnings, "⚠️  Database dependency detected - serverless needs an external managed database (e.g. RDS)")
+		}
+
 		statefulFrameworks := []string{"django", "rails"}
 		for _, fw := range statefulFrameworks {
 			if strings.ToLower(analysis.Framework) == fw {
@@ -394,12 +366,33 @@ func (c *Client) ValidateDeploymentRequirements(analysis *types.Analysis, strate
 			warnings = append(warnings, "⚠️  Kubernetes recommended but no Dockerfile found - containerization needed")
 		}
 
+		if eksFargate {
+			warnings = append(warnings, "⚠️  Fargate does not run DaemonSets - node agents (logging, monitoring) must run as sidecars")
+			warnings = append(warnings, "⚠️  Fargate sizes each pod from its resource requests (up to 16 vCPU / 120 GB) - set requests to match the app's needs")
+			warnings = append(warnings, "⚠️  Classic ELB cannot target Fargate pods - install the AWS Load Balancer Controller and use IP targets to expose the service")
+		}
+
 	case "vm":
 		if len(analysis.Dependencies) > 30 {
 			warnings = append(warnings, "⚠️  High dependency count - consider Kubernetes for better management")
 		}
 	}
 
+	// Lambda invokes a handler, the listen address only matters to servers
+	if analysis.BindsLocalhost && strategy != "serverless" {
+		warnings = append(warnings, fmt.Sprintf("⚠️  App listens on localhost (127.0.0.1) only - it will be unreachable once deployed: bind to 0.0.0.0 on port %d", analysis.Port))
+	}
+
+	// Only one port is routed to the container: the others of the Dockerfile are unreachable
+	if len(analysis.DockerfilePorts) > 1 && strategy != "serverless" {
+		warnings = append(warnings, fmt.Sprintf("⚠️  Dockerfile exposes several ports (%s) - only port %d is routed to the app", joinPorts(analysis.DockerfilePorts), analysis.Port))
+	}
+
+	// End-of-life framework versions no longer get security fixes
+	if warning := frameworkEOLWarning(analysis); warning != "" {
+		warnings = append(warnings, warning)
+	}
+
 	// Check for unknown frameworks
 	if analysis.Framework == "unknown" {
 		warnings = append(warnings, "⚠️  Unable to detect framework - deployment may require manual configuration")
@@ -412,6 +405,15 @@ func (c *Client) ValidateDeploymentRequirements(analysis *types.Analysis, strate
 	return warnings
 }
 
+// joinPorts formats ports as a comma-separated list
+func joinPorts(ports []int) string {
+	formatted := make([]string, len(ports))
+	for i, port := range ports {
+		formatted[i] = strconv.Itoa(port)
+	}
+	return strings.Join(formatted, ", ")
+}
+
 // Generate provides direct access to LLM generation (for config parsing, etc.)
 func (c *Client) Generate(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error) {
 	if c.providerManager == nil {
@@ -0,0 +1,92 @@
+package llm
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// debugOutput receives every prompt sent to a provider and its raw response (--debug-llm).
+// Kept apart from the verbose logger: the prompts embed the whole repository analysis.
+var debugOutput = struct {
+	sync.Mutex
+	w       io.Writer
+	secrets []string
+}{}
+
+// secretPatterns match credentials that may end up in a prompt (user prompt, environment
+// variables, configuration values) and are redacted before logging
+var secretPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`),           // AWS access key IDs
+	regexp.MustCompile(`\bsk-[A-Za-z0-9_-]{20,}`),               // OpenAI API keys
+	regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`),             // Google API keys
+	regexp.MustCompile(`\bhf_[A-Za-z0-9]{30,}\b`),               // HuggingFace tokens
+	regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36,}\b`),        // GitHub tokens
+	regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9._~+/=-]{8,}`), // Authorization headers
+	regexp.MustCompile(`(?i)-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`),
+}
+
+// secretAssignment matches NAME=value and NAME: value where NAME looks like a credential
+var secretAssignment = regexp.MustCompile(`(?i)\b([A-Z0-9_.-]*(?:password|passwd|secret|token|api[_-]?key|access[_-]?key|credential)[A-Z0-9_.-]*)(\s*[=:]\s*)("[^"\n]*"|'[^'\n]*'|[^\s,;"']+)`)
+
+const redacted = "[REDACTED]"
+
+// SetDebugOutput logs every LLM prompt and raw response to w, nil to disable
+func SetDebugOutput(w io.Writer) {
+	debugOutput.Lock()
+	defer debugOutput.Unlock()
+	debugOutput.w = w
+}
+
+// AddDebugSecrets registers values (API keys, tokens) redacted from the debug output in
+// addition to the credentials matched by pattern
+func AddDebugSecrets(values ...string) {
+	debugOutput.Lock()
+	defer debugOutput.Unlock()
+	for _, value := range values {
+		// Short values would redact ordinary words
+		if len(value) >= 8 {
+			debugOutput.secrets = append(debugOutput.secrets, value)
+		}
+	}
+}
+
+// redactSecrets masks the registered secrets and the credentials matched by pattern in text
+func redactSecrets(text string, secrets []string) string {
+	for _, secret := range secrets {
+		text = strings.ReplaceAll(text, secret, redacted)
+	}
+	for _, pattern := range secretPatterns {
+		text = pattern.ReplaceAllString(text, redacted)
+	}
+	return secretAssignment.ReplaceAllString(text, "${1}${2}"+redacted)
+}
+
+// logExchange writes a prompt sent to provider and its response or error to the debug output
+func logExchange(provider string, req *GenerateRequest, resp *GenerateResponse, err error, elapsed time.Duration) {
+	debugOutput.Lock()
+	defer debugOutput.Unlock()
+	if debugOutput.w == nil {
+		return
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "===== LLM request: %s (model %s, temperature %.2f, max tokens %d) =====\n",
+		provider, req.Model, req.Temperature, req.MaxTokens)
+	if req.System != "" {
+		fmt.Fprintf(&b, "----- system -----\n%s\n", req.System)
+	}
+	fmt.Fprintf(&b, "----- prompt -----\n%s\n", req.Prompt)
+	if err != nil {
+		fmt.Fprintf(&b, "----- error after %s -----\n%v\n", elapsed.Round(time.Millisecond), err)
+	} else {
+		fmt.Fprintf(&b, "----- response after %s (model %s, prompt tokens %d, total tokens %d) -----\n%s\n",
+			elapsed.Round(time.Millisecond), resp.Model, resp.TokensPrompt, resp.TokensTotal, resp.Text)
+	}
+	b.WriteString("===== end of LLM request =====\n\n")
+
+	_, _ = io.WriteString(debugOutput.w, redactSecrets(b.String(), debugOutput.secrets))
+}
@@ -0,0 +1,195 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Smana/scai/internal/rules"
+	"github.com/Smana/scai/internal/types"
+)
+
+// Sources of a strategy decision
+const (
+	DecisionSourceRules      = "rules"
+	DecisionSourceLLM        = "llm"
+	DecisionSourceHeuristics = "heuristics"
+)
+
+// Confidence of the decisions of each source: rules are authored for the case they match,
+// the LLM weighs the whole analysis, heuristics only look at a few signals
+const (
+	rulesConfidence           = 0.9
+	llmConfidence             = 0.7
+	llmUnstructuredConfidence = 0.5 // Strategy only guessed from keywords of the response
+	heuristicsConfidence      = 0.4
+)
+
+// ErrNoStrategyDecision is returned when no source could decide the deployment strategy
+var ErrNoStrategyDecision = errors.New("no deployment strategy could be decided")
+
+// StrategyDecision is a deployment strategy with the source that decided it and why
+type StrategyDecision struct {
+	Strategy   string
+	Source     string  // DecisionSourceRules, DecisionSourceLLM or DecisionSourceHeuristics
+	Confidence float64 // From 0 to 1
+	Reason     string
+	RuleName   string // Matching rule, for rules decisions
+}
+
+// StrategyDecider decides the deployment strategy (vm, kubernetes or serverless) of an
+// analyzed application. A decider that cannot decide returns a nil decision and no error.
+type StrategyDecider interface {
+	DecideStrategy(ctx context.Context, userPrompt string, analysis *types.Analysis) (*StrategyDecision, error)
+}
+
+// CompositeDecider asks its deciders in order and returns the first decision; a failing
+// decider is skipped
+type CompositeDecider struct {
+	deciders []StrategyDecider
+}
+
+// NewCompositeDecider creates a decider asking deciders in order
+func NewCompositeDecider(deciders ...StrategyDecider) *CompositeDecider {
+	return &CompositeDecider{deciders: deciders}
+}
+
+// DecideStrategy returns the decision of the first decider that makes one
+func (d *CompositeDecider) DecideStrategy(ctx context.Context, userPrompt string, analysis *types.Analysis) (*StrategyDecision, error) {
+	for _, decider := range d.deciders {
+		decision, err := decider.DecideStrategy(ctx, userPrompt, analysis)
+		if err != nil {
+			logger.Printf("Strategy decision failed: %v, trying next source", err)
+			continue
+		}
+		if decision != nil {
+			return decision, nil
+		}
+	}
+	return nil, ErrNoStrategyDecision
+}
+
+// StrategyDecider returns the decider of the client: its deployment rules first (fast,
+// deterministic), then the LLM, then heuristics, which always decide
+func (c *Client) StrategyDecider() StrategyDecider {
+	return NewCompositeDecider(NewRulesDecider(c.rules), NewLLMDecider(c), NewHeuristicsDecider())
+}
+
+// RulesDecider decides with the first (or best scored) matching deployment rule
+type RulesDecider struct {
+	rules *types.DeploymentRules
+}
+
+// NewRulesDecider creates a decider evaluating deploymentRules
+func NewRulesDecider(deploymentRules *types.DeploymentRules) *RulesDecider {
+	return &RulesDecider{rules: deploymentRules}
+}
+
+// DecideStrategy returns the recommendation of the matching rule, nil when none matches
+func (d *RulesDecider) DecideStrategy(ctx context.Context, userPrompt string, analysis *types.Analysis) (*StrategyDecision, error) {
+	if d.rules == nil {
+		return nil, nil
+	}
+	ruleMatch, matched := rules.Evaluate(d.rules, analysis)
+	if !matched {
+		return nil, nil
+	}
+
+	if analysis.Verbose {
+		logger.Printf("Rule-Based Decision: %s\nRule: %s\nReason: %s\n",
+			ruleMatch.Strategy, ruleMatch.RuleName, ruleMatch.Reason)
+		if d.rules.Mode == rules.ModeScoring {
+			for _, scored := range rules.ScoreRules(d.rules, analysis) {
+				logger.Printf("  Candidate %s → %s (score %.2f, %d conditions, priority %d)\n",
+					scored.RuleName, scored.Strategy, scored.Score, scored.MatchedConditions, scored.Priority)
+			}
+		}
+	}
+
+	return &StrategyDecision{
+		Strategy:   ruleMatch.Strategy,
+		Source:     DecisionSourceRules,
+		Confidence: rulesConfidence,
+		Reason:     ruleMatch.Reason,
+		RuleName:   ruleMatch.RuleName,
+	}, nil
+}
+
+// LLMDecider asks the LLM providers of a client, with the knowledge base and few-shot examples
+type LLMDecider struct {
+	client *Client
+}
+
+// NewLLMDecider creates a decider asking the providers of client
+func NewLLMDecider(client *Client) *LLMDecider {
+	return &LLMDecider{client: client}
+}
+
+// DecideStrategy returns the strategy the LLM recommends, nil without provider or when the
+// response names no strategy
+func (d *LLMDecider) DecideStrategy(ctx context.Context, userPrompt string, analysis *types.Analysis) (*StrategyDecision, error) {
+	c := d.client
+	if c.providerManager == nil {
+		if analysis.Verbose {
+			logger.Printf("No LLM providers available, using heuristics")
+		}
+		return nil, nil
+	}
+
+	// Generate using provider manager (with automatic fallback)
+	resp, err := c.providerManager.Generate(ctx, &GenerateRequest{
+		Model:       c.config.DefaultModel,
+		Prompt:      c.buildStrategyPrompt(userPrompt, analysis),
+		Temperature: 0.7,
+		MaxTokens:   200,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("all LLM providers failed: %w", err)
+	}
+
+	strategy, reason := c.parseStrategyResponse(resp.Text)
+	if strategy == "" {
+		if analysis.Verbose {
+			logger.Printf("LLM response names no strategy, using heuristics")
+		}
+		return nil, nil
+	}
+
+	if analysis.Verbose {
+		logger.Printf("LLM Decision: %s\nReason: %s\nModel: %s\n", strategy, reason, resp.Model)
+	}
+
+	confidence := llmConfidence
+	if reason == "" {
+		confidence = llmUnstructuredConfidence
+	}
+	return &StrategyDecision{
+		Strategy:   strategy,
+		Source:     DecisionSourceLLM,
+		Confidence: confidence,
+		Reason:     reason,
+	}, nil
+}
+
+// HeuristicsDecider decides from a few signals of the analysis (docker-compose services,
+// dependency count, statelessness); it always decides
+type HeuristicsDecider struct{}
+
+// NewHeuristicsDecider creates a heuristics decider
+func NewHeuristicsDecider() *HeuristicsDecider {
+	return &HeuristicsDecider{}
+}
+
+// DecideStrategy returns the heuristic strategy of analysis
+func (d *HeuristicsDecider) DecideStrategy(ctx context.Context, userPrompt string, analysis *types.Analysis) (*StrategyDecision, error) {
+	strategy, reason := heuristicStrategy(analysis)
+	if analysis.Verbose {
+		logger.Printf("Heuristic Decision: %s\nReason: %s\n", strategy, reason)
+	}
+	return &StrategyDecision{
+		Strategy:   strategy,
+		Source:     DecisionSourceHeuristics,
+		Confidence: heuristicsConfidence,
+		Reason:     reason,
+	}, nil
+}
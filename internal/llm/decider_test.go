@@ -0,0 +1,149 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Smana/scai/internal/types"
+)
+
+// stubDecider returns a fixed decision or error and records whether it was asked
+type stubDecider struct {
+	decision *StrategyDecision
+	err      error
+	asked    bool
+}
+
+func (d *stubDecider) DecideStrategy(ctx context.Context, userPrompt string, analysis *types.Analysis) (*StrategyDecision, error) {
+	d.asked = true
+	return d.decision, d.err
+}
+
+// stubProvider answers every prompt with a fixed response or error
+type stubProvider struct {
+	text string
+	err  error
+}
+
+func (p *stubProvider) Generate(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &GenerateResponse{Text: p.text, Model: "stub"}, nil
+}
+
+func (p *stubProvider) ListModels(ctx context.Context) ([]ModelInfo, error) { return nil, nil }
+func (p *stubProvider) Name() string                                        { return "stub" }
+func (p *stubProvider) IsAvailable(ctx context.Context) bool                { return true }
+
+func TestCompositeDecider(t *testing.T) {
+	ctx := context.Background()
+	analysis := &types.Analysis{}
+
+	failing := &stubDecider{err: errors.New("unavailable")}
+	undecided := &stubDecider{}
+	deciding := &stubDecider{decision: &StrategyDecision{Strategy: "kubernetes", Source: DecisionSourceLLM}}
+	unreached := &stubDecider{decision: &StrategyDecision{Strategy: "vm"}}
+
+	decision, err := NewCompositeDecider(failing, undecided, deciding, unreached).DecideStrategy(ctx, "", analysis)
+	if err != nil {
+		t.Fatalf("DecideStrategy() error = %v", err)
+	}
+	if decision.Strategy != "kubernetes" || !failing.asked || !undecided.asked || unreached.asked {
+		t.Errorf("DecideStrategy() = %+v, want the decision of the first deciding source", decision)
+	}
+
+	if _, err := NewCompositeDecider(&stubDecider{}).DecideStrategy(ctx, "", analysis); !errors.Is(err, ErrNoStrategyDecision) {
+		t.Errorf("DecideStrategy() without decision error = %v, want ErrNoStrategyDecision", err)
+	}
+}
+
+func TestRulesDecider(t *testing.T) {
+	deploymentRules := &types.DeploymentRules{Rules: []types.DeploymentRule{{
+		Name:           "django",
+		Conditions:     types.RuleConditions{Framework: []string{"django"}},
+		Recommendation: "vm",
+		Reason:         "Django runs well on a VM",
+	}}}
+	decider := NewRulesDecider(deploymentRules)
+
+	decision, err := decider.DecideStrategy(context.Background(), "", &types.Analysis{Framework: "django"})
+	if err != nil {
+		t.Fatalf("DecideStrategy() error = %v", err)
+	}
+	if decision == nil || decision.Strategy != "vm" || decision.Source != DecisionSourceRules || decision.RuleName != "django" {
+		t.Errorf("DecideStrategy() = %+v, want the django rule", decision)
+	}
+
+	if decision, _ := decider.DecideStrategy(context.Background(), "", &types.Analysis{Framework: "flask"}); decision != nil {
+		t.Errorf("DecideStrategy() without matching rule = %+v, want nil", decision)
+	}
+}
+
+func TestLLMDecider(t *testing.T) {
+	tests := []struct {
+		name           string
+		provider       *stubProvider
+		wantStrategy   string
+		wantConfidence float64
+		wantErr        bool
+	}{
+		{
+			name:           "structured response",
+			provider:       &stubProvider{text: "STRATEGY: serverless\nREASON: stateless API"},
+			wantStrategy:   "serverless",
+			wantConfidence: llmConfidence,
+		},
+		{
+			name:           "strategy in free text",
+			provider:       &stubProvider{text: "I would use Kubernetes"},
+			wantStrategy:   "kubernetes",
+			wantConfidence: llmUnstructuredConfidence,
+		},
+		{
+			name:     "no strategy",
+			provider: &stubProvider{text: "It depends"},
+		},
+		{
+			name:     "provider failure",
+			provider: &stubProvider{err: errors.New("timeout")},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &Client{
+				providerManager: &ProviderManager{providers: []Provider{tt.provider}, availability: make(map[string]availability)},
+				config:          &ProviderConfig{},
+			}
+
+			decision, err := NewLLMDecider(client).DecideStrategy(context.Background(), "deploy", &types.Analysis{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DecideStrategy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantStrategy == "" {
+				if decision != nil {
+					t.Errorf("DecideStrategy() = %+v, want nil", decision)
+				}
+				return
+			}
+			if decision == nil || decision.Strategy != tt.wantStrategy || decision.Confidence != tt.wantConfidence {
+				t.Errorf("DecideStrategy() = %+v, want %s with confidence %.1f", decision, tt.wantStrategy, tt.wantConfidence)
+			}
+		})
+	}
+}
+
+func TestHeuristicsDecider(t *testing.T) {
+	analysis := &types.Analysis{HasDockerCompose: true, ComposeServices: []string{"app", "redis"}}
+
+	decision, err := NewHeuristicsDecider().DecideStrategy(context.Background(), "", analysis)
+	if err != nil {
+		t.Fatalf("DecideStrategy() error = %v", err)
+	}
+	if decision.Strategy != "kubernetes" || decision.Source != DecisionSourceHeuristics || decision.Reason == "" {
+		t.Errorf("DecideStrategy() = %+v, want kubernetes for a multi-service compose file", decision)
+	}
+}
@@ -3,11 +3,20 @@ package llm
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/exec"
+	"regexp"
+	"slices"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/pterm/pterm"
+
+	"github.com/Smana/scai/internal/console"
+	"github.com/Smana/scai/internal/network"
 )
 
 const (
@@ -43,7 +52,7 @@ func StartOllamaContainer(verbose bool) error {
 	if strings.TrimSpace(string(output)) == OllamaContainerName {
 		// Container exists, just start it
 		if verbose {
-			fmt.Printf("Starting existing Ollama container...\n")
+			fmt.Fprintf(console.Stdout, "Starting existing Ollama container...\n")
 		}
 		cmd := exec.Command("docker", "start", OllamaContainerName)
 		if err := cmd.Run(); err != nil {
@@ -52,7 +61,7 @@ func StartOllamaContainer(verbose bool) error {
 	} else {
 		// Create new container with security options
 		if verbose {
-			fmt.Printf("Creating Ollama container...\n")
+			fmt.Fprintf(console.Stdout, "Creating Ollama container...\n")
 		}
 		cmd := exec.Command("docker", "run", "-d",
 			"--name", OllamaContainerName,
@@ -70,7 +79,12 @@ func StartOllamaContainer(verbose bool) error {
 
 	// Wait for container to be ready
 	if verbose {
-		fmt.Printf("Waiting for Ollama to be ready...\n")
+		fmt.Fprintf(console.Stdout, "Waiting for Ollama to be ready...\n")
+	}
+
+	url, err := OllamaContainerURL()
+	if err != nil {
+		return err
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -81,9 +95,9 @@ func StartOllamaContainer(verbose bool) error {
 		case <-ctx.Done():
 			return fmt.Errorf("timeout waiting for Ollama to start")
 		default:
-			if IsOllamaAccessible(OllamaDockerURL) {
+			if IsOllamaAccessible(url) {
 				if verbose {
-					fmt.Printf("✓ Ollama container is ready\n")
+					fmt.Fprintf(console.Stdout, "✓ Ollama container is ready\n")
 				}
 				return nil
 			}
@@ -92,48 +106,199 @@ func StartOllamaContainer(verbose bool) error {
 	}
 }
 
-// EnsureModelAvailable ensures the specified model is pulled
-func EnsureModelAvailable(model string, verbose bool) error {
-	// Check if model exists
-	checkCmd := exec.Command("docker", "exec", OllamaContainerName, "ollama", "list")
-	output, err := checkCmd.Output()
+// ModelChooser picks the model to use when the configured one is missing from the Ollama
+// container: model to pull it, or one of the available models to reuse it instead
+type ModelChooser func(model string, available []string) (string, error)
+
+// ListContainerModels returns the models pulled in the Ollama container (name:tag)
+func ListContainerModels() ([]string, error) {
+	output, err := exec.Command("docker", "exec", OllamaContainerName, "ollama", "list").Output()
 	if err != nil {
-		return fmt.Errorf("failed to list models: %w", err)
+		return nil, fmt.Errorf("failed to list models: %w", err)
 	}
+	return parseOllamaList(string(output)), nil
+}
 
-	// Check if model is already present
-	if strings.Contains(string(output), model) {
-		if verbose {
-			fmt.Printf("✓ Model %s is already available\n", model)
+// parseOllamaList returns the model names (name:tag) of the output of ollama list
+func parseOllamaList(output string) []string {
+	var models []string
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || fields[0] == "NAME" {
+			continue
 		}
-		return nil
+		models = append(models, fields[0])
 	}
+	return models
+}
 
-	// Pull the model
-	if verbose {
-		fmt.Printf("Pulling model %s (this may take a while)...\n", model)
+// OllamaContainerURL returns the URL of the Ollama API published by the container: an existing
+// container may publish it on another host port than OllamaPort (e.g. created by hand with
+// -p 11500:11434)
+func OllamaContainerURL() (string, error) {
+	output, err := exec.Command("docker", "port", OllamaContainerName, OllamaPort+"/tcp").Output()
+	if err != nil {
+		return "", fmt.Errorf("Ollama container %s does not publish port %s: recreate it (docker rm -f %s) or set llm.ollama.url", OllamaContainerName, OllamaPort, OllamaContainerName)
+	}
+	return parseDockerPort(string(output))
+}
+
+// parseDockerPort returns the URL of the first host address of the output of docker port
+// (e.g. 0.0.0.0:11500), localhost for the wildcard addresses
+func parseDockerPort(output string) (string, error) {
+	line, _, _ := strings.Cut(strings.TrimSpace(output), "\n")
+	host, port, err := net.SplitHostPort(strings.TrimSpace(line))
+	if err != nil || port == "" {
+		return "", fmt.Errorf("unexpected docker port output %q", output)
+	}
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "localhost"
+	}
+	return "http://" + net.JoinHostPort(host, port), nil
+}
+
+// EnsureModelAvailable ensures the specified model is pulled in the Ollama container and returns
+// the model to use. When it is missing and other models are present, choose may pick one of
+// them instead (nil pulls it).
+func EnsureModelAvailable(model string, choose ModelChooser, verbose bool) (string, error) {
+	available, err := ListContainerModels()
+	if err != nil {
+		return "", err
+	}
+
+	// Exact name and tag: qwen2.5-coder:7b is not qwen2.5-coder:1.5b (nor qwen2.5-coder:7b-instruct)
+	want := modelTag(model)
+	for _, name := range available {
+		if modelTag(name) == want {
+			if verbose {
+				fmt.Fprintf(console.Stdout, "✓ Model %s is already available\n", model)
+			}
+			return model, nil
+		}
+	}
+
+	if choose != nil && len(available) > 0 {
+		chosen, err := choose(model, alternativeModels(model, available))
+		if err != nil {
+			return "", err
+		}
+		if chosen != model {
+			if verbose {
+				fmt.Fprintf(console.Stdout, "✓ Using model %s already pulled in the Ollama container\n", chosen)
+			}
+			return chosen, nil
+		}
+	}
+
+	if err := pullContainerModel(model, verbose); err != nil {
+		return "", err
 	}
+	return model, nil
+}
+
+// alternativeModels orders the available models to reuse instead of model: other tags of the
+// same model first
+func alternativeModels(model string, available []string) []string {
+	name, _, _ := strings.Cut(model, ":")
+	alternatives := slices.Clone(available)
+	slices.SortStableFunc(alternatives, func(a, b string) int {
+		aName, _, _ := strings.Cut(a, ":")
+		bName, _, _ := strings.Cut(b, ":")
+		switch {
+		case aName == name && bName != name:
+			return -1
+		case aName != name && bName == name:
+			return 1
+		default:
+			return 0
+		}
+	})
+	return alternatives
+}
 
+// pullContainerModel pulls model in the Ollama container, streaming the ollama output when
+// verbose and showing the download percentage in a spinner otherwise
+func pullContainerModel(model string, verbose bool) error {
 	pullCmd := exec.Command("docker", "exec", OllamaContainerName, "ollama", "pull", model)
 
 	if verbose {
-		// Show progress to user
-		pullCmd.Stdout = os.Stdout
+		fmt.Fprintf(console.Stdout, "Pulling model %s (this may take a while)...\n", model)
+		pullCmd.Stdout = console.Stdout
 		pullCmd.Stderr = os.Stderr
-	} else {
-		// Suppress progress
-		pullCmd.Stdout = nil
-		pullCmd.Stderr = nil
+		if err := pullCmd.Run(); err != nil {
+			return fmt.Errorf("failed to pull model %s: %w", model, err)
+		}
+		fmt.Fprintf(console.Stdout, "✓ Model %s is ready\n", model)
+		return nil
 	}
 
+	text := fmt.Sprintf("Pulling model %s (this may take a while)...", model)
+	spinner, _ := pterm.DefaultSpinner.Start(text)
+	progress := &pullProgress{update: func(percent string) {
+		spinner.UpdateText(fmt.Sprintf("%s %s", text, percent))
+	}}
+	pullCmd.Stdout = progress
+	pullCmd.Stderr = progress
+
 	if err := pullCmd.Run(); err != nil {
-		return fmt.Errorf("failed to pull model %s: %w", model, err)
+		spinner.Fail(fmt.Sprintf("Failed to pull model %s", model))
+		return fmt.Errorf("failed to pull model %s: %w\nOutput: %s", model, err, progress.tail())
 	}
+	spinner.Success(fmt.Sprintf("Model %s is ready", model))
+	return nil
+}
 
-	if verbose {
-		fmt.Printf("✓ Model %s is ready\n", model)
+// pullPercent matches the download percentage in the progress bar of ollama pull
+var pullPercent = regexp.MustCompile(`(\d{1,3})%`)
+
+// ansiEscape matches the terminal control sequences of the ollama progress bars
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;?]*[A-Za-z]`)
+
+// pullProgress reports the last download percentage written by ollama pull and keeps the end
+// of its output for error messages
+type pullProgress struct {
+	mu     sync.Mutex
+	update func(percent string)
+	last   string
+	output []byte
+}
+
+// pullOutputTail is how much of the ollama pull output is kept
+const pullOutputTail = 1024
+
+func (p *pullProgress) Write(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.output = append(p.output, b...)
+	if len(p.output) > pullOutputTail {
+		p.output = p.output[len(p.output)-pullOutputTail:]
 	}
-	return nil
+
+	if matches := pullPercent.FindAllSubmatch(b, -1); len(matches) > 0 {
+		percent := string(matches[len(matches)-1][1]) + "%"
+		if percent != p.last {
+			p.last = percent
+			p.update(percent)
+		}
+	}
+	return len(b), nil
+}
+
+// tail returns the end of the output, without the progress bars redrawn with carriage returns
+func (p *pullProgress) tail() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	output := ansiEscape.ReplaceAllString(string(p.output), "")
+	lines := strings.Split(strings.ReplaceAll(output, "\r", "\n"), "\n")
+	var kept []string
+	for _, line := range lines {
+		if line = strings.TrimSpace(line); line != "" && !pullPercent.MatchString(line) {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n")
 }
 
 // IsOllamaAccessible checks if Ollama is accessible at the given URL
@@ -146,7 +311,7 @@ func IsOllamaAccessible(url string) bool {
 		return false
 	}
 
-	client := &http.Client{Timeout: 5 * time.Second}
+	client := network.NewClient(5 * time.Second)
 	resp, err := client.Do(req)
 	if err != nil {
 		return false
@@ -156,29 +321,39 @@ func IsOllamaAccessible(url string) bool {
 	return resp.StatusCode == http.StatusOK
 }
 
-// SetupOllamaDocker ensures Ollama Docker container is running with the required model
-func SetupOllamaDocker(model string, verbose bool) (string, error) {
+// SetupOllamaDocker ensures Ollama Docker container is running with the required model, and
+// returns its URL and the model to use (another model already pulled when choose picks one)
+func SetupOllamaDocker(model string, choose ModelChooser, verbose bool) (string, string, error) {
 	if !IsDockerAvailable() {
-		return "", fmt.Errorf("Docker is not available")
+		return "", "", fmt.Errorf("Docker is not available")
 	}
 
 	if verbose {
-		fmt.Println("🐳 Setting up Ollama with Docker...")
+		fmt.Fprintln(console.Stdout, "🐳 Setting up Ollama with Docker...")
 	}
 
 	// Check if container is already running
 	if !IsOllamaContainerRunning() {
 		if err := StartOllamaContainer(verbose); err != nil {
-			return "", err
+			return "", "", err
 		}
 	} else if verbose {
-		fmt.Printf("✓ Ollama container is already running\n")
+		fmt.Fprintf(console.Stdout, "✓ Ollama container is already running\n")
+	}
+
+	url, err := OllamaContainerURL()
+	if err != nil {
+		return "", "", err
+	}
+	if url != OllamaDockerURL && verbose {
+		fmt.Fprintf(console.Stdout, "✓ Ollama container published on %s\n", url)
 	}
 
 	// Ensure model is available
-	if err := EnsureModelAvailable(model, verbose); err != nil {
-		return "", err
+	model, err = EnsureModelAvailable(model, choose, verbose)
+	if err != nil {
+		return "", "", err
 	}
 
-	return OllamaDockerURL, nil
+	return url, model, nil
 }
@@ -0,0 +1,74 @@
+package llm
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseOllamaList(t *testing.T) {
+	output := `NAME                       ID              SIZE      MODIFIED
+qwen2.5-coder:7b           2b0496514337    4.7 GB    2 weeks ago
+qwen2.5-coder:7b-instruct  2b0496514337    4.7 GB    2 weeks ago
+llama3.2:latest            a80c4f17acd5    2.0 GB    3 days ago
+`
+	want := []string{"qwen2.5-coder:7b", "qwen2.5-coder:7b-instruct", "llama3.2:latest"}
+	if got := parseOllamaList(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseOllamaList() = %v, want %v", got, want)
+	}
+	if got := parseOllamaList("NAME    ID    SIZE    MODIFIED\n"); len(got) != 0 {
+		t.Errorf("parseOllamaList() without models = %v, want none", got)
+	}
+}
+
+func TestAlternativeModels(t *testing.T) {
+	available := []string{"llama3.2:latest", "qwen2.5-coder:1.5b", "mistral:7b", "qwen2.5-coder:7b-instruct"}
+	want := []string{"qwen2.5-coder:1.5b", "qwen2.5-coder:7b-instruct", "llama3.2:latest", "mistral:7b"}
+	if got := alternativeModels("qwen2.5-coder:7b", available); !reflect.DeepEqual(got, want) {
+		t.Errorf("alternativeModels() = %v, want %v", got, want)
+	}
+}
+
+func TestParseDockerPort(t *testing.T) {
+	tests := []struct {
+		output  string
+		want    string
+		wantErr bool
+	}{
+		{"0.0.0.0:11434\n[::]:11434\n", "http://localhost:11434", false},
+		{"0.0.0.0:11500\n", "http://localhost:11500", false},
+		{"[::]:11500\n", "http://localhost:11500", false},
+		{"127.0.0.1:8080\n", "http://127.0.0.1:8080", false},
+		{"", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseDockerPort(tt.output)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseDockerPort(%q) = %q, %v, want %q (error %v)", tt.output, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestPullProgress(t *testing.T) {
+	var updates []string
+	progress := &pullProgress{update: func(percent string) { updates = append(updates, percent) }}
+
+	for _, chunk := range []string{
+		"pulling manifest\n",
+		"\x1b[?25lpulling 6a0746a1ec1a...   3% ▕█      ▏ 150 MB/4.7 GB\r",
+		"pulling 6a0746a1ec1a...   3% ▕█      ▏ 160 MB/4.7 GB\r",
+		"pulling 6a0746a1ec1a...  45% ▕███    ▏ 2.1 GB/4.7 GB\r",
+		"Error: pull model manifest: file does not exist\n",
+	} {
+		if _, err := progress.Write([]byte(chunk)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if want := []string{"3%", "45%"}; !reflect.DeepEqual(updates, want) {
+		t.Errorf("progress updates = %v, want %v", updates, want)
+	}
+	if want := "pulling manifest\nError: pull model manifest: file does not exist"; progress.tail() != want {
+		t.Errorf("tail() = %q, want %q", progress.tail(), want)
+	}
+}
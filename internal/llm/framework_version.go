@@ -0,0 +1,61 @@
+package llm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Smana/scai/internal/types"
+)
+
+// supportedFrameworkVersions are the oldest framework releases still receiving security fixes
+// (as of 2026): older versions are end-of-life
+var supportedFrameworkVersions = map[string]string{
+	"django": "5.2",
+	"rails":  "7.2",
+	"nextjs": "15",
+}
+
+// DescribeFramework returns the framework of the analysis with its version when known
+// (e.g. "django 4.2.7")
+func DescribeFramework(analysis *types.Analysis) string {
+	if analysis.FrameworkVersion == "" {
+		return analysis.Framework
+	}
+	return analysis.Framework + " " + analysis.FrameworkVersion
+}
+
+// frameworkEOLWarning returns a warning when the framework version of the analysis is
+// end-of-life, empty otherwise
+func frameworkEOLWarning(analysis *types.Analysis) string {
+	minimum, ok := supportedFrameworkVersions[strings.ToLower(analysis.Framework)]
+	if !ok || analysis.FrameworkVersion == "" || !versionOlder(analysis.FrameworkVersion, minimum) {
+		return ""
+	}
+	return fmt.Sprintf("⚠️  %s is end-of-life (no security fixes before %s) - upgrade it before exposing the app",
+		DescribeFramework(analysis), minimum)
+}
+
+// versionOlder reports whether version is older than minimum, comparing their numeric
+// components (4.2.7 < 5.2); a version that cannot be parsed is not considered older
+func versionOlder(version, minimum string) bool {
+	parts := strings.Split(strings.TrimPrefix(version, "v"), ".")
+	for i, component := range strings.Split(minimum, ".") {
+		if i >= len(parts) {
+			return false
+		}
+		// Pre-release suffixes (5.0rc1, 14.0.0-canary) are ignored
+		digits := parts[i]
+		if end := strings.IndexFunc(digits, func(r rune) bool { return r < '0' || r > '9' }); end >= 0 {
+			digits = digits[:end]
+		}
+		got, err := strconv.Atoi(digits)
+		if err != nil {
+			return false
+		}
+		if want, _ := strconv.Atoi(component); got != want {
+			return got < want
+		}
+	}
+	return false
+}
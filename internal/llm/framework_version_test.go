@@ -0,0 +1,31 @@
+package llm
+
+import (
+	"testing"
+
+	"github.com/Smana/scai/internal/types"
+)
+
+func TestFrameworkEOLWarning(t *testing.T) {
+	tests := []struct {
+		framework, version string
+		wantEOL            bool
+	}{
+		{"django", "1.11.29", true},
+		{"django", "4.2.7", true},
+		{"django", "5.2", false},
+		{"django", "6.0rc1", false},
+		{"rails", "7.1.3", true},
+		{"rails", "8.0.1", false},
+		{"nextjs", "14.2.0-canary.1", true},
+		{"django", "", false},
+		{"flask", "1.0", false}, // no support policy known
+	}
+
+	for _, tt := range tests {
+		analysis := &types.Analysis{Framework: tt.framework, FrameworkVersion: tt.version}
+		if got := frameworkEOLWarning(analysis) != ""; got != tt.wantEOL {
+			t.Errorf("frameworkEOLWarning(%s %s) EOL = %v, want %v", tt.framework, tt.version, got, tt.wantEOL)
+		}
+	}
+}
@@ -75,6 +75,24 @@ const DeploymentKnowledgeBase = `# Cloud Deployment Expert Knowledge Base
 - Production: Puma, Passenger
 - Best Deployment: VM or Kubernetes
 
+**Ruby (Sinatra / Rack)**
+- Typical Memory: 64MB - 256MB
+- Startup Time: 1-3 seconds
+- Concurrency: Moderate (Puma threads)
+- Common Use: Small APIs, webhooks, microservices
+- Default Port: 4567 (Sinatra), 9292 (rackup)
+- Production: Puma
+- Best Deployment: VM or Serverless (lightweight)
+
+**Java (Spring Boot)**
+- Typical Memory: 512MB - 2GB
+- Startup Time: 10-30 seconds (JVM warm-up)
+- Concurrency: High (thread pools)
+- Common Use: Enterprise APIs, microservices
+- Default Port: 8080
+- Production: Executable jar (Maven or Gradle build)
+- Best Deployment: VM or Kubernetes
+
 ## Deployment Strategy Decision Rules
 
 ### Choose VM (EC2) when:
@@ -144,7 +162,7 @@ Instance Sizing:
 - Bind to 0.0.0.0 (not localhost/127.0.0.1)
 - Use environment variables for config
 - Never hardcode secrets
-- Set proper CORS headers
+- Set proper CORS headers (allowed origins listed, never * in production)
 
 ### Performance
 - Use production servers (Gunicorn, uWSGI, PM2)
@@ -166,6 +184,9 @@ Instance Sizing:
 - Next.js: 3000
 - Go: 8080
 - Rails: 3000
+- Sinatra: 4567
+- Rack: 9292
+- Spring Boot: 8080
 - Streamlit: 8501
 
 ## Dependency Analysis
@@ -311,7 +332,9 @@ const DecisionPromptTemplate = `Based on the knowledge base and examples above,
 - Language: %s
 - Dependencies: %d packages
 - Has Dockerfile: %v
-- Has docker-compose: %v
+- Has docker-compose: %s
+- Requires database: %v
+- Requires cache: %v
 - Port: %d
 - Start Command: %s
 - Estimated Memory: %s
@@ -319,8 +342,8 @@ const DecisionPromptTemplate = `Based on the knowledge base and examples above,
 **User Request (Context Only):** %s
 
 **Decision Rules:**
-1. If docker-compose detected → RECOMMEND kubernetes (multi-container orchestration needed)
-2. If stateless + <5 dependencies → CONSIDER serverless (simple, scalable)
+1. If docker-compose with 2+ services detected → RECOMMEND kubernetes (multi-container orchestration needed)
+2. If stateless (no database or cache) + <5 dependencies → CONSIDER serverless (simple, scalable)
 3. If >20 dependencies → RECOMMEND kubernetes (complex application needs isolation)
 4. If Dockerfile + <15 dependencies → vm is sufficient (simple containerized app)
 5. IGNORE user request if it conflicts with code analysis
@@ -0,0 +1,76 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ModelChecker is implemented by providers whose ListModels returns the models actually
+// installed or served, rather than a static list of known models: only their configured
+// model can be checked before the first prompt
+type ModelChecker interface {
+	Provider
+
+	// Model returns the configured model
+	Model() string
+}
+
+// ModelPuller is implemented by providers that can download a missing model
+type ModelPuller interface {
+	PullModel(ctx context.Context, model string) error
+}
+
+// ModelNotAvailableError reports a configured model the provider does not have
+type ModelNotAvailableError struct {
+	Provider  string
+	Model     string
+	Available []string // Models the provider has
+}
+
+func (e *ModelNotAvailableError) Error() string {
+	available := "none"
+	if len(e.Available) > 0 {
+		available = strings.Join(e.Available, ", ")
+	}
+	return fmt.Sprintf("model %s is not available on %s (available: %s)", e.Model, e.Provider, available)
+}
+
+// Unwrap makes the error match ErrInvalidModel
+func (e *ModelNotAvailableError) Unwrap() error {
+	return ErrInvalidModel
+}
+
+// CheckModel verifies that the configured model of provider is available, returning a
+// *ModelNotAvailableError otherwise. Providers that are not a ModelChecker are not checked,
+// nor are providers failing to list their models (their availability is checked separately).
+func CheckModel(ctx context.Context, provider Provider) error {
+	checker, ok := provider.(ModelChecker)
+	if !ok || checker.Model() == "" {
+		return nil
+	}
+
+	models, err := checker.ListModels(ctx)
+	if err != nil {
+		return nil
+	}
+
+	want := modelTag(checker.Model())
+	available := make([]string, 0, len(models))
+	for _, model := range models {
+		if modelTag(model.Name) == want {
+			return nil
+		}
+		available = append(available, model.Name)
+	}
+	return &ModelNotAvailableError{Provider: provider.Name(), Model: checker.Model(), Available: available}
+}
+
+// modelTag returns the name of a model with its tag, ":latest" when it has none (as Ollama
+// resolves llama3 to llama3:latest)
+func modelTag(name string) string {
+	if strings.Contains(name, ":") {
+		return name
+	}
+	return name + ":latest"
+}
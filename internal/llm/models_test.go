@@ -0,0 +1,54 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// stubCatalog is a provider listing fixed models, with a configured model
+type stubCatalog struct {
+	stubProvider
+	model   string
+	models  []string
+	listErr error
+}
+
+func (p *stubCatalog) Model() string { return p.model }
+
+func (p *stubCatalog) ListModels(ctx context.Context) ([]ModelInfo, error) {
+	models := make([]ModelInfo, 0, len(p.models))
+	for _, name := range p.models {
+		models = append(models, ModelInfo{Name: name})
+	}
+	return models, p.listErr
+}
+
+func TestCheckModel(t *testing.T) {
+	installed := []string{"qwen2.5-coder:7b", "llama3:latest"}
+
+	tests := []struct {
+		name     string
+		provider Provider
+		wantErr  bool
+	}{
+		{"installed", &stubCatalog{model: "qwen2.5-coder:7b", models: installed}, false},
+		{"implicit latest tag", &stubCatalog{model: "llama3", models: installed}, false},
+		{"not pulled", &stubCatalog{model: "qwen2.5-coder:70b", models: installed}, true},
+		{"listing fails", &stubCatalog{model: "qwen2.5-coder:70b", listErr: errors.New("unreachable")}, false},
+		{"static model list", &stubProvider{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckModel(context.Background(), tt.provider)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckModel() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var notAvailable *ModelNotAvailableError
+			if tt.wantErr && (!errors.As(err, &notAvailable) || !errors.Is(err, ErrInvalidModel) || len(notAvailable.Available) != len(installed)) {
+				t.Errorf("CheckModel() error = %v, want a ModelNotAvailableError listing the installed models", err)
+			}
+		})
+	}
+}
@@ -3,6 +3,8 @@ package llm
 import (
 	"context"
 	"fmt"
+	"sync"
+	"time"
 )
 
 // Provider defines the interface for LLM providers
@@ -62,6 +64,8 @@ type ProviderConfig struct {
 	OllamaURL   string // Default: http://localhost:11434
 	OllamaModel string // Default model for Ollama
 
+	OllamaKeepAlive time.Duration // How long the model stays loaded after a request (negative: indefinitely)
+
 	// Gemini configuration
 	GeminiAPIKey string // Google AI Studio API key
 	GeminiModel  string // Default model (gemini-2.0-pro-exp)
@@ -79,6 +83,10 @@ type ProviderConfig struct {
 	LocalModelPath string // Path to local GGUF model file
 	LocalServerURL string // llama.cpp compatible server URL
 
+	// Provider types tried in order when the configured one is unavailable or fails
+	// (each with its own settings above)
+	Fallback []string
+
 	// General settings
 	DefaultModel string  // Fallback model name
 	Timeout      int     // Request timeout in seconds
@@ -86,61 +94,56 @@ type ProviderConfig struct {
 	Temperature  float64 // Default temperature
 }
 
+// availabilityTTL is how long the availability of a provider is reused before probing it again
+const availabilityTTL = 30 * time.Second
+
+// availability is the cached result of a provider availability check
+type availability struct {
+	available bool
+	checkedAt time.Time
+}
+
 // ProviderManager manages multiple LLM providers with fallback
 type ProviderManager struct {
 	providers []Provider
 	config    *ProviderConfig
 	verbose   bool
+
+	// Availability of the providers by name, so that successive prompts don't probe them each time
+	availabilityMu sync.Mutex
+	availability   map[string]availability
 }
 
 // NewProviderManager creates a manager with configured providers
 func NewProviderManager(config *ProviderConfig, verbose bool) (*ProviderManager, error) {
 	pm := &ProviderManager{
-		config:  config,
-		verbose: verbose,
+		config:       config,
+		verbose:      verbose,
+		availability: make(map[string]availability),
 	}
 
-	// Initialize providers based on config
+	// The configured provider first, then the fallback ones in order
 	var providers []Provider
-
-	// Add Ollama if configured
-	if config.Type == "ollama" || config.Type == "" {
-		ollamaProvider, err := NewOllamaProvider(config.OllamaURL, config.OllamaModel, verbose)
-		if err == nil {
-			providers = append(providers, ollamaProvider)
+	seen := make(map[string]bool)
+	for i, providerType := range append([]string{config.Type}, config.Fallback...) {
+		if seen[providerType] {
+			continue
 		}
-	}
+		seen[providerType] = true
 
-	// Add Gemini if configured
-	if config.Type == "gemini" {
-		geminiProvider, err := NewGeminiProvider(config.GeminiAPIKey, config.GeminiModel, verbose)
+		provider, err := newProvider(providerType, config, verbose)
 		if err != nil {
-			return nil, fmt.Errorf("failed to initialize Gemini provider: %w", err)
-		}
-		providers = append(providers, geminiProvider)
-	}
-
-	// Add OpenAI if configured
-	if config.Type == "openai" {
-		openaiProvider, err := NewOpenAIProvider(config.OpenAIAPIKey, config.OpenAIModel, verbose)
-		if err == nil {
-			providers = append(providers, openaiProvider)
-		}
-	}
-
-	// Add HuggingFace if configured
-	if config.Type == "huggingface" {
-		hfProvider, err := NewHuggingFaceProvider(config.HFToken, config.HFModel, verbose)
-		if err == nil {
-			providers = append(providers, hfProvider)
+			// A misconfigured fallback only removes it from the chain
+			if i == 0 {
+				return nil, err
+			}
+			if verbose {
+				logger.Printf("Fallback provider %s skipped: %v", providerType, err)
+			}
+			continue
 		}
-	}
-
-	// Add local GGUF if configured
-	if config.Type == "local" && config.LocalModelPath != "" {
-		localProvider, err := NewLocalProvider(config.LocalModelPath, config.LocalServerURL, verbose)
-		if err == nil {
-			providers = append(providers, localProvider)
+		if provider != nil {
+			providers = append(providers, provider)
 		}
 	}
 
@@ -152,13 +155,43 @@ func NewProviderManager(config *ProviderConfig, verbose bool) (*ProviderManager,
 	return pm, nil
 }
 
+// newProvider creates the provider of the given type, nil when it is not configured
+// (a local model without path)
+func newProvider(providerType string, config *ProviderConfig, verbose bool) (Provider, error) {
+	switch providerType {
+	case "ollama", "":
+		return NewOllamaProvider(config.OllamaURL, config.OllamaModel, config.OllamaKeepAlive, verbose)
+	case "gemini":
+		provider, err := NewGeminiProvider(config.GeminiAPIKey, config.GeminiModel, verbose)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize Gemini provider: %w", err)
+		}
+		return provider, nil
+	case "openai":
+		provider, err := NewOpenAIProvider(config.OpenAIAPIKey, config.OpenAIModel, verbose)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize OpenAI provider: %w", err)
+		}
+		return provider, nil
+	case "huggingface":
+		return NewHuggingFaceProvider(config.HFToken, config.HFModel, verbose)
+	case "local":
+		if config.LocalModelPath == "" {
+			return nil, nil
+		}
+		return NewLocalProvider(config.LocalModelPath, config.LocalServerURL, verbose)
+	default:
+		return nil, fmt.Errorf("unknown LLM provider %q", providerType)
+	}
+}
+
 // Generate tries providers in order until success
 func (pm *ProviderManager) Generate(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error) {
 	var lastErr error
 
 	for _, provider := range pm.providers {
 		// Check if provider is available
-		if !provider.IsAvailable(ctx) {
+		if !pm.isAvailable(ctx, provider) {
 			if pm.verbose {
 				logger.Printf("Provider %s not available, trying next...", provider.Name())
 			}
@@ -166,11 +199,16 @@ func (pm *ProviderManager) Generate(ctx context.Context, req *GenerateRequest) (
 		}
 
 		// Try generation
+		start := time.Now()
 		resp, err := provider.Generate(ctx, req)
+		logExchange(provider.Name(), req, resp, err, time.Since(start))
 		if err == nil {
 			return resp, nil
 		}
 
+		// The provider may have gone down: probe it again on the next call
+		pm.invalidate(provider)
+
 		lastErr = err
 		if pm.verbose {
 			logger.Printf("Provider %s failed: %v, trying next...", provider.Name(), err)
@@ -189,7 +227,7 @@ func (pm *ProviderManager) ListAllModels(ctx context.Context) ([]ModelInfo, erro
 	var allModels []ModelInfo
 
 	for _, provider := range pm.providers {
-		if !provider.IsAvailable(ctx) {
+		if !pm.isAvailable(ctx, provider) {
 			continue
 		}
 
@@ -204,12 +242,54 @@ func (pm *ProviderManager) ListAllModels(ctx context.Context) ([]ModelInfo, erro
 	return allModels, nil
 }
 
+// preloader is implemented by providers that can load their model ahead of the first request
+type preloader interface {
+	Preload(ctx context.Context) error
+}
+
+// Preload loads the model of the providers that run it locally (Ollama), so that
+// the first prompt doesn't wait for it
+func (pm *ProviderManager) Preload(ctx context.Context) error {
+	for _, provider := range pm.providers {
+		if p, ok := provider.(preloader); ok {
+			if err := p.Preload(ctx); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // GetBestProvider returns the first available provider
 func (pm *ProviderManager) GetBestProvider(ctx context.Context) Provider {
 	for _, provider := range pm.providers {
-		if provider.IsAvailable(ctx) {
+		if pm.isAvailable(ctx, provider) {
 			return provider
 		}
 	}
 	return nil
 }
+
+// isAvailable returns the availability of provider, probed at most once per availabilityTTL
+func (pm *ProviderManager) isAvailable(ctx context.Context, provider Provider) bool {
+	pm.availabilityMu.Lock()
+	cached, ok := pm.availability[provider.Name()]
+	pm.availabilityMu.Unlock()
+	if ok && time.Since(cached.checkedAt) < availabilityTTL {
+		return cached.available
+	}
+
+	available := provider.IsAvailable(ctx)
+
+	pm.availabilityMu.Lock()
+	pm.availability[provider.Name()] = availability{available: available, checkedAt: time.Now()}
+	pm.availabilityMu.Unlock()
+	return available
+}
+
+// invalidate forgets the cached availability of provider
+func (pm *ProviderManager) invalidate(provider Provider) {
+	pm.availabilityMu.Lock()
+	delete(pm.availability, provider.Name())
+	pm.availabilityMu.Unlock()
+}
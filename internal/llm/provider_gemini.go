@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"google.golang.org/genai"
+
+	"github.com/Smana/scai/internal/network"
 )
 
 // GeminiProvider implements Provider for Google Gemini
@@ -28,8 +30,9 @@ func NewGeminiProvider(apiKey, defaultModel string, verbose bool) (*GeminiProvid
 
 	ctx := context.Background()
 	client, err := genai.NewClient(ctx, &genai.ClientConfig{
-		APIKey:  apiKey,
-		Backend: genai.BackendGeminiAPI, // Use Gemini Developer API (not Vertex AI)
+		APIKey:     apiKey,
+		Backend:    genai.BackendGeminiAPI, // Use Gemini Developer API (not Vertex AI)
+		HTTPClient: network.NewClient(0),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Gemini client: %w", err)
@@ -8,6 +8,8 @@ import (
 	"io"
 	"net/http"
 	"time"
+
+	"github.com/Smana/scai/internal/network"
 )
 
 // HuggingFaceProvider implements Provider for HuggingFace Inference API
@@ -29,10 +31,8 @@ func NewHuggingFaceProvider(apiToken, defaultModel string, verbose bool) (*Huggi
 		apiToken:     apiToken,
 		endpoint:     "https://api-inference.huggingface.co/models",
 		defaultModel: defaultModel,
-		httpClient: &http.Client{
-			Timeout: 60 * time.Second,
-		},
-		verbose: verbose,
+		httpClient:   network.NewClient(60 * time.Second),
+		verbose:      verbose,
 	}, nil
 }
 
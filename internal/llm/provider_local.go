@@ -10,6 +10,8 @@ import (
 	"os"
 	"path/filepath"
 	"time"
+
+	"github.com/Smana/scai/internal/network"
 )
 
 // LocalProvider implements Provider for local GGUF models
@@ -35,12 +37,10 @@ func NewLocalProvider(modelPath, serverURL string, verbose bool) (*LocalProvider
 	}
 
 	return &LocalProvider{
-		modelPath: modelPath,
-		serverURL: serverURL,
-		httpClient: &http.Client{
-			Timeout: 120 * time.Second, // Local models can be slow on CPU
-		},
-		verbose: verbose,
+		modelPath:  modelPath,
+		serverURL:  serverURL,
+		httpClient: network.NewClient(120 * time.Second), // Local models can be slow on CPU
+		verbose:    verbose,
 	}, nil
 }
 
@@ -4,12 +4,14 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/ollama/ollama/api"
+
+	"github.com/Smana/scai/internal/network"
 )
 
 var logger = log.Default()
@@ -19,11 +21,15 @@ type OllamaProvider struct {
 	client       *api.Client
 	baseURL      string
 	defaultModel string
+	keepAlive    time.Duration // How long the model stays loaded after a request, 0 for Ollama's default
 	verbose      bool
 }
 
+// DefaultOllamaKeepAlive keeps the model loaded between the prompts of a deployment
+const DefaultOllamaKeepAlive = 5 * time.Minute
+
 // NewOllamaProvider creates a new Ollama provider
-func NewOllamaProvider(baseURL, defaultModel string, verbose bool) (*OllamaProvider, error) {
+func NewOllamaProvider(baseURL, defaultModel string, keepAlive time.Duration, verbose bool) (*OllamaProvider, error) {
 	if baseURL == "" {
 		baseURL = "http://localhost:11434"
 	}
@@ -39,21 +45,78 @@ func NewOllamaProvider(baseURL, defaultModel string, verbose bool) (*OllamaProvi
 	}
 
 	// Create client
-	client := api.NewClient(u, http.DefaultClient)
+	client := api.NewClient(u, network.NewClient(0))
 
 	return &OllamaProvider{
 		client:       client,
 		baseURL:      baseURL,
 		defaultModel: defaultModel,
+		keepAlive:    keepAlive,
 		verbose:      verbose,
 	}, nil
 }
 
+// ParseOllamaKeepAlive parses an Ollama keep_alive: a duration (e.g. 5m, 1h) or a number of
+// seconds, negative to keep the model loaded indefinitely; empty for the default
+func ParseOllamaKeepAlive(value string) (time.Duration, error) {
+	if value == "" {
+		return DefaultOllamaKeepAlive, nil
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, nil
+	}
+	keepAlive, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid keep_alive %q: expected a duration (e.g. 5m, 1h) or a number of seconds", value)
+	}
+	return keepAlive, nil
+}
+
+// keepAliveDuration returns the keep_alive of requests, nil for Ollama's default
+func (p *OllamaProvider) keepAliveDuration() *api.Duration {
+	if p.keepAlive == 0 {
+		return nil
+	}
+	return &api.Duration{Duration: p.keepAlive}
+}
+
+// Preload loads the default model in memory (a request without prompt), so that the
+// first prompt doesn't wait for it
+func (p *OllamaProvider) Preload(ctx context.Context) error {
+	req := &api.GenerateRequest{Model: p.defaultModel, KeepAlive: p.keepAliveDuration()}
+	if err := p.client.Generate(ctx, req, func(api.GenerateResponse) error { return nil }); err != nil {
+		return fmt.Errorf("failed to preload ollama model %s: %w", p.defaultModel, err)
+	}
+	return nil
+}
+
 // Name returns the provider name
 func (p *OllamaProvider) Name() string {
 	return "ollama"
 }
 
+// Model returns the configured model
+func (p *OllamaProvider) Model() string {
+	return p.defaultModel
+}
+
+// PullModel downloads model to the Ollama server (local, remote or in Docker), showing the
+// download progress when verbose
+func (p *OllamaProvider) PullModel(ctx context.Context, model string) error {
+	lastStatus := ""
+	progress := func(resp api.ProgressResponse) error {
+		if p.verbose && resp.Status != lastStatus {
+			logger.Printf("   %s", resp.Status)
+			lastStatus = resp.Status
+		}
+		return nil
+	}
+	if err := p.client.Pull(ctx, &api.PullRequest{Model: model}, progress); err != nil {
+		return fmt.Errorf("failed to pull ollama model %s: %w", model, err)
+	}
+	return nil
+}
+
 // IsAvailable checks if Ollama is accessible
 func (p *OllamaProvider) IsAvailable(ctx context.Context) bool {
 	// Try to list models as a health check
@@ -73,9 +136,10 @@ func (p *OllamaProvider) Generate(ctx context.Context, req *GenerateRequest) (*G
 
 	// Build Ollama request
 	ollamaReq := &api.GenerateRequest{
-		Model:  model,
-		Prompt: req.Prompt,
-		System: req.System,
+		Model:     model,
+		Prompt:    req.Prompt,
+		System:    req.System,
+		KeepAlive: p.keepAliveDuration(),
 		Options: map[string]interface{}{
 			"temperature": req.Temperature,
 			"num_predict": req.MaxTokens,
@@ -0,0 +1,91 @@
+// Package network configures the outbound HTTP connections: the LLM APIs, remote Ollama
+// servers, git clones, downloads and application health checks
+package network
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+var (
+	proxyMu sync.RWMutex
+
+	// proxyFunc returns the proxy of a request URL, nil for a direct connection
+	proxyFunc = httpproxy.FromEnvironment().ProxyFunc()
+)
+
+// SetProxy routes the outbound HTTP connections through proxyURL (e.g. http://proxy.example.com:3128)
+// instead of the HTTP_PROXY and HTTPS_PROXY environment variables, except for the NO_PROXY hosts.
+// The variables are overridden as well, for the AWS SDK and the Terraform processes to use it.
+// An empty proxyURL keeps (and reloads) the environment variables.
+func SetProxy(proxyURL string) error {
+	if proxyURL == "" {
+		proxyMu.Lock()
+		proxyFunc = httpproxy.FromEnvironment().ProxyFunc()
+		proxyMu.Unlock()
+		return nil
+	}
+	u, err := url.Parse(proxyURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("invalid proxy URL %q (e.g. http://proxy.example.com:3128)", proxyURL)
+	}
+
+	for _, env := range []string{"HTTP_PROXY", "HTTPS_PROXY", "http_proxy", "https_proxy"} {
+		if err := os.Setenv(env, proxyURL); err != nil {
+			return fmt.Errorf("failed to set %s: %w", env, err)
+		}
+	}
+
+	config := httpproxy.FromEnvironment()
+	config.HTTPProxy, config.HTTPSProxy = proxyURL, proxyURL
+
+	proxyMu.Lock()
+	proxyFunc = config.ProxyFunc()
+	proxyMu.Unlock()
+	return nil
+}
+
+// Proxy returns the proxy of req, nil for a direct connection; it is the Proxy of the transports
+// returned by NewTransport
+func Proxy(req *http.Request) (*url.URL, error) {
+	proxyMu.RLock()
+	defer proxyMu.RUnlock()
+	return proxyFunc(req.URL)
+}
+
+// ProxyURL returns the proxy of the connections to rawURL, empty for a direct connection
+func ProxyURL(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	proxyMu.RLock()
+	defer proxyMu.RUnlock()
+	proxy, err := proxyFunc(u)
+	if err != nil || proxy == nil {
+		return "", err
+	}
+	return proxy.String(), nil
+}
+
+// NewTransport returns a transport configured as http.DefaultTransport, using Proxy
+func NewTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = Proxy
+	return transport
+}
+
+// NewClient returns an HTTP client using Proxy, with the given timeout (0 for none)
+func NewClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Transport: NewTransport(),
+		Timeout:   timeout,
+	}
+}
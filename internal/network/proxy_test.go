@@ -0,0 +1,34 @@
+package network
+
+import "testing"
+
+func TestSetProxy(t *testing.T) {
+	for _, env := range []string{"HTTP_PROXY", "HTTPS_PROXY", "http_proxy", "https_proxy"} {
+		t.Setenv(env, "")
+	}
+	t.Setenv("NO_PROXY", "internal.example.com")
+	saved := proxyFunc
+	t.Cleanup(func() { proxyFunc = saved })
+
+	if err := SetProxy("proxy.example.com"); err == nil {
+		t.Error("SetProxy() accepted a proxy URL without scheme")
+	}
+	if err := SetProxy("http://proxy.example.com:3128"); err != nil {
+		t.Fatalf("SetProxy() error = %v", err)
+	}
+
+	tests := map[string]string{
+		"https://github.com/user/app":      "http://proxy.example.com:3128",
+		"https://internal.example.com/api": "",
+		"http://localhost:11434":           "",
+	}
+	for rawURL, want := range tests {
+		got, err := ProxyURL(rawURL)
+		if err != nil {
+			t.Fatalf("ProxyURL(%q) error = %v", rawURL, err)
+		}
+		if got != want {
+			t.Errorf("ProxyURL(%q) = %q, want %q", rawURL, got, want)
+		}
+	}
+}
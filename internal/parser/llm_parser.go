@@ -3,12 +3,14 @@ package parser
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"strings"
 
 	"github.com/Smana/scai/internal/deployer"
 	"github.com/Smana/scai/internal/llm"
+	"github.com/Smana/scai/internal/terraform"
 )
 
 const (
@@ -40,11 +42,24 @@ Analyze the request and extract any deployment configuration parameters mentione
 - eks_max_nodes: Maximum number of nodes (integer)
 - eks_desired_nodes: Desired number of nodes (integer)
 - eks_node_volume_size: Node volume size in GB
+- eks_fargate: true to run pods on Fargate instead of managed nodes (boolean)
+- replicas: Number of application pods of the Kubernetes Deployment (integer, NOT the number of nodes)
 
 **Lambda/Serverless Parameters (when strategy=serverless):**
 - lambda_memory: Memory in MB (128-10240)
 - lambda_timeout: Timeout in seconds (1-900)
 
+**Autoscaling Parameters (when strategy=vm or kubernetes):**
+- autoscale_target_cpu: Target average CPU utilization in percent (1-100) to scale on
+
+**Cost Parameters:**
+- budget_usd: Maximum monthly cost in USD (number)
+
+**Qualitative Sizing (when no instance type is named):**
+- sizing_hint: Size and/or workload profile, e.g. "small", "large", "memory-optimized", "large compute-optimized"
+  - Sizes: "tiny", "small", "medium", "large", "xlarge"
+  - Workloads: "general-purpose", "memory-optimized" (high-memory, caches, in-memory data), "compute-optimized" (CPU-intensive, encoding, ML inference)
+
 **Response Format (JSON only):**
 {
   "strategy": "vm",
@@ -56,8 +71,13 @@ Analyze the request and extract any deployment configuration parameters mentione
   "eks_max_nodes": 3,
   "eks_desired_nodes": 2,
   "eks_node_volume_size": 30,
+  "eks_fargate": false,
+  "replicas": 4,
   "lambda_memory": 512,
-  "lambda_timeout": 30
+  "lambda_timeout": 30,
+  "autoscale_target_cpu": 70,
+  "budget_usd": 50,
+  "sizing_hint": "large memory-optimized"
 }
 
 **Important:**
@@ -65,7 +85,12 @@ Analyze the request and extract any deployment configuration parameters mentione
 - Field names MUST match exactly: ec2_instance_type, volume_size, eks_node_type, etc.
 - Instance types: preserve exact format (e.g., "t3.medium", not "T3.Medium" or "t3-medium")
 - If user says "3 nodes", set eks_min_nodes, eks_max_nodes, and eks_desired_nodes all to 3
+- "4 replicas"/"4 pods" → replicas=4 (pods); only "nodes"/"instances" set the eks_*_nodes fields
 - Understand variations: "EKS"/"Kubernetes"/"K8s" → strategy="kubernetes", "VM"/"EC2" → strategy="vm"
+- "Fargate"/"serverless Kubernetes"/"no nodes" → strategy="kubernetes" and eks_fargate=true
+- "scale up at 70%% CPU"/"autoscale at 70%% CPU" → autoscale_target_cpu=70
+- "keep it under $50/month"/"budget of 50 dollars" → budget_usd=50
+- "a small cluster"/"high-memory instance"/"big CPU-heavy VM" → sizing_hint="small"/"memory-optimized"/"large compute-optimized", never invent instance types from them
 - Omit fields that are not mentioned
 
 **Respond with ONLY the JSON object, nothing else.**
@@ -101,11 +126,22 @@ Understand what the user wants to change and provide ONLY the changed parameters
 - eks_max_nodes: Maximum number of nodes
 - eks_desired_nodes: Desired number of nodes
 - eks_node_volume_size: Node volume size in GB
+- eks_fargate: true to run pods on Fargate instead of managed nodes (boolean)
+- replicas: Number of application pods of the Kubernetes Deployment (integer, NOT the number of nodes)
 
 **Lambda/Serverless Parameters (when strategy=serverless):**
 - lambda_memory: Memory in MB (128-10240)
 - lambda_timeout: Timeout in seconds (1-900)
 
+**Autoscaling Parameters (when strategy=vm or kubernetes):**
+- autoscale_target_cpu: Target average CPU utilization in percent (1-100) to scale on
+
+**Cost Parameters:**
+- budget_usd: Maximum monthly cost in USD (number)
+
+**Qualitative Sizing (when no instance type is named):**
+- sizing_hint: Size ("tiny", "small", "medium", "large", "xlarge") and/or workload ("general-purpose", "memory-optimized", "compute-optimized")
+
 **Parameter Extraction Examples:**
 - "instance type t3.medium" → {"ec2_instance_type": "t3.medium"}
 - "t3.large instance" → {"ec2_instance_type": "t3.large"}
@@ -114,6 +150,12 @@ Understand what the user wants to change and provide ONLY the changed parameters
 - "disk to 32GB" → {"volume_size": 32}
 - "50 GB volume" → {"volume_size": 50}
 - "5 nodes" → {"eks_desired_nodes": 5, "eks_min_nodes": 5, "eks_max_nodes": 5}
+- "use Fargate" → {"eks_fargate": true}
+- "run 4 replicas" → {"replicas": 4}
+- "scale out at 60%% CPU" → {"autoscale_target_cpu": 60}
+- "keep it under $40/month" → {"budget_usd": 40}
+- "make it bigger" (current t3.medium) → {"sizing_hint": "large"}
+- "I need more memory" → {"sizing_hint": "memory-optimized"}
 - "region eu-west-1" → {"region": "eu-west-1"}
 - "32GB and t3.medium" → {"volume_size": 32, "ec2_instance_type": "t3.medium"}
 
@@ -134,16 +176,37 @@ Understand what the user wants to change and provide ONLY the changed parameters
 **Respond with ONLY the JSON object of CHANGED parameters, nothing else.**
 `
 
-// ParseConfigFromPrompt uses LLM to extract deployment configuration from natural language
-func ParseConfigFromPrompt(llmClient *llm.Client, userPrompt string) (*DeploymentConfig, error) {
-	if llmClient == nil {
-		return &DeploymentConfig{CleanedPrompt: userPrompt}, nil
+// ParseConfigFromPrompt uses LLM to extract deployment configuration from natural language.
+// A response that cannot be parsed falls back to the deterministic patterns, and values of the
+// prompt missing from the extraction are only logged (see ParseConfigFromPromptStrict).
+func ParseConfigFromPrompt(ctx context.Context, llmClient *llm.Client, userPrompt string) (*DeploymentConfig, error) {
+	config, err := ParseConfigFromPromptStrict(ctx, llmClient, userPrompt)
+	var parseErr *PromptParseError
+	if !errors.As(err, &parseErr) {
+		return config, err
+	}
+	if parseErr.Err != nil {
+		// If parsing fails, return empty config
+		log.Printf("Warning: Failed to parse LLM response as JSON: %v", parseErr.Err)
+		return promptOnlyConfig(userPrompt), nil
 	}
+	return config, nil
+}
 
-	ctx := context.Background()
+// ParseConfigFromPromptStrict is ParseConfigFromPrompt returning a *PromptParseError, along with
+// the extracted configuration when the response was parsed, rather than silently dropping the
+// configuration stated in the prompt: the response is not the expected JSON, or values named in
+// the prompt (instance type, sizes, node count, region) are missing from it. RepairConfigFromPrompt
+// re-prompts the model with the error.
+func ParseConfigFromPromptStrict(ctx context.Context, llmClient *llm.Client, userPrompt string) (*DeploymentConfig, error) {
+	return extractConfig(ctx, llmClient, userPrompt, fmt.Sprintf(ConfigExtractionPrompt, userPrompt))
+}
 
-	// Build the prompt
-	prompt := fmt.Sprintf(ConfigExtractionPrompt, userPrompt)
+// extractConfig extracts the configuration of userPrompt from the LLM response to prompt
+func extractConfig(ctx context.Context, llmClient *llm.Client, userPrompt, prompt string) (*DeploymentConfig, error) {
+	if llmClient == nil {
+		return promptOnlyConfig(userPrompt), nil
+	}
 
 	// Generate using LLM
 	req := &llm.GenerateRequest{
@@ -155,7 +218,7 @@ func ParseConfigFromPrompt(llmClient *llm.Client, userPrompt string) (*Deploymen
 	resp, err := llmClient.Generate(ctx, req)
 	if err != nil {
 		// If LLM fails, return empty config
-		return &DeploymentConfig{CleanedPrompt: userPrompt}, nil
+		return promptOnlyConfig(userPrompt), nil
 	}
 
 	// Validate response size before parsing
@@ -170,19 +233,59 @@ func ParseConfigFromPrompt(llmClient *llm.Client, userPrompt string) (*Deploymen
 	// Parse JSON response
 	config, err := parseConfigJSON(resp.Text)
 	if err != nil {
-		// If parsing fails, return empty config
-		log.Printf("Warning: Failed to parse LLM response as JSON: %v", err)
-		return &DeploymentConfig{CleanedPrompt: userPrompt}, nil
+		return nil, &PromptParseError{Response: resp.Text, Err: err}
 	}
 
+	// The LLM may miss the autoscaling target, the budget or the replicas: fall back to the deterministic patterns
+	if config.AutoscaleTargetCPU == 0 {
+		config.AutoscaleTargetCPU = ExtractAutoscaleTargetCPU(userPrompt)
+	}
+	if config.BudgetUSD == 0 {
+		config.BudgetUSD = ExtractBudgetUSD(userPrompt)
+	}
+	if config.Replicas == 0 {
+		config.Replicas = ExtractReplicas(userPrompt)
+	}
+	if config.SizingHint == "" {
+		config.SizingHint = ExtractSizingHint(userPrompt)
+	}
+	config.resolveSizingHint()
+
 	// Log what was extracted
 	log.Printf("Extracted initial config - EC2 Instance: %s, Volume: %dGB, Strategy: %s, Region: %s",
 		config.EC2InstanceType, config.EC2VolumeSize, config.Strategy, config.Region)
 
 	config.CleanedPrompt = userPrompt // Keep original prompt for context
+
+	// Make misextraction visible: values clearly stated in the prompt the LLM did not return
+	if missed := missedValues(userPrompt, config); len(missed) > 0 {
+		log.Printf("Warning: LLM extraction is missing values stated in the prompt: %s", strings.Join(missed, ", "))
+		return config, &PromptParseError{Response: resp.Text, Missed: missed}
+	}
 	return config, nil
 }
 
+// promptOnlyConfig is the configuration extracted without the LLM (deterministic patterns only)
+func promptOnlyConfig(userPrompt string) *DeploymentConfig {
+	config := &DeploymentConfig{
+		CleanedPrompt:      userPrompt,
+		AutoscaleTargetCPU: ExtractAutoscaleTargetCPU(userPrompt),
+		BudgetUSD:          ExtractBudgetUSD(userPrompt),
+		Replicas:           ExtractReplicas(userPrompt),
+		SizingHint:         ExtractSizingHint(userPrompt),
+	}
+	config.resolveSizingHint()
+	return config
+}
+
+// validPercent returns percent if it is within 1-100, 0 otherwise
+func validPercent(percent int) int {
+	if percent < 1 || percent > 100 {
+		return 0
+	}
+	return percent
+}
+
 // ModifyPlanWithNaturalLanguage uses LLM to understand plan modification requests
 func ModifyPlanWithNaturalLanguage(llmClient *llm.Client, currentConfig *deployer.DeployConfig, userRequest string) (*DeploymentConfig, error) {
 	if llmClient == nil {
@@ -222,6 +325,7 @@ func ModifyPlanWithNaturalLanguage(llmClient *llm.Client, currentConfig *deploye
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse LLM response: %w", err)
 	}
+	config.resolveSizingHint()
 
 	// Log what was extracted
 	log.Printf("Extracted config - EC2 Instance: %s, Volume: %dGB, Strategy: %s, Region: %s",
@@ -244,6 +348,11 @@ func buildCurrentPlanDescription(config *deployer.DeployConfig) string {
 		}
 
 	case "kubernetes":
+		parts = append(parts, fmt.Sprintf("Replicas: %d", terraform.Replicas(config.Replicas)))
+		if config.EKSFargate {
+			parts = append(parts, "Compute: Fargate (no nodes)")
+			break
+		}
 		if config.EKSNodeType != "" {
 			parts = append(parts, fmt.Sprintf("Node Type: %s", config.EKSNodeType))
 		}
@@ -262,26 +371,42 @@ func buildCurrentPlanDescription(config *deployer.DeployConfig) string {
 		}
 	}
 
+	if config.Strategy != "serverless" && config.AutoscaleTargetCPU > 0 {
+		parts = append(parts, fmt.Sprintf("Autoscaling: %d%% CPU", config.AutoscaleTargetCPU))
+	}
+
+	if config.BudgetUSD > 0 {
+		parts = append(parts, fmt.Sprintf("Budget: $%.0f/month", config.BudgetUSD))
+	}
+
 	return strings.Join(parts, ", ")
 }
 
 // parseConfigJSON parses the LLM's JSON response into a DeploymentConfig
 func parseConfigJSON(jsonText string) (*DeploymentConfig, error) {
 	// Extract JSON from response (LLM might add extra text)
-	jsonText = extractJSON(jsonText)
+	jsonText, err := extractJSON(jsonText)
+	if err != nil {
+		return nil, err
+	}
 
 	var rawConfig struct {
-		Strategy          string `json:"strategy"`
-		Region            string `json:"region"`
-		EC2InstanceType   string `json:"ec2_instance_type"`
-		EC2VolumeSize     int    `json:"volume_size"`
-		EKSNodeType       string `json:"eks_node_type"`
-		EKSMinNodes       int    `json:"eks_min_nodes"`
-		EKSMaxNodes       int    `json:"eks_max_nodes"`
-		EKSDesiredNodes   int    `json:"eks_desired_nodes"`
-		EKSNodeVolumeSize int    `json:"eks_node_volume_size"`
-		LambdaMemory      int    `json:"lambda_memory"`
-		LambdaTimeout     int    `json:"lambda_timeout"`
+		Strategy           string  `json:"strategy"`
+		Region             string  `json:"region"`
+		EC2InstanceType    string  `json:"ec2_instance_type"`
+		EC2VolumeSize      int     `json:"volume_size"`
+		EKSNodeType        string  `json:"eks_node_type"`
+		EKSMinNodes        int     `json:"eks_min_nodes"`
+		EKSMaxNodes        int     `json:"eks_max_nodes"`
+		EKSDesiredNodes    int     `json:"eks_desired_nodes"`
+		EKSNodeVolumeSize  int     `json:"eks_node_volume_size"`
+		EKSFargate         bool    `json:"eks_fargate"`
+		Replicas           int     `json:"replicas"`
+		LambdaMemory       int     `json:"lambda_memory"`
+		LambdaTimeout      int     `json:"lambda_timeout"`
+		AutoscaleTargetCPU int     `json:"autoscale_target_cpu"`
+		BudgetUSD          float64 `json:"budget_usd"`
+		SizingHint         string  `json:"sizing_hint"`
 	}
 
 	if err := json.Unmarshal([]byte(jsonText), &rawConfig); err != nil {
@@ -289,30 +414,35 @@ func parseConfigJSON(jsonText string) (*DeploymentConfig, error) {
 	}
 
 	config := &DeploymentConfig{
-		Strategy:          rawConfig.Strategy,
-		Region:            rawConfig.Region,
-		EC2InstanceType:   rawConfig.EC2InstanceType,
-		EC2VolumeSize:     rawConfig.EC2VolumeSize,
-		EKSNodeType:       rawConfig.EKSNodeType,
-		EKSMinNodes:       rawConfig.EKSMinNodes,
-		EKSMaxNodes:       rawConfig.EKSMaxNodes,
-		EKSDesiredNodes:   rawConfig.EKSDesiredNodes,
-		EKSNodeVolumeSize: rawConfig.EKSNodeVolumeSize,
-		LambdaMemory:      rawConfig.LambdaMemory,
-		LambdaTimeout:     rawConfig.LambdaTimeout,
+		Strategy:           rawConfig.Strategy,
+		Region:             rawConfig.Region,
+		EC2InstanceType:    rawConfig.EC2InstanceType,
+		EC2VolumeSize:      rawConfig.EC2VolumeSize,
+		EKSNodeType:        rawConfig.EKSNodeType,
+		EKSMinNodes:        rawConfig.EKSMinNodes,
+		EKSMaxNodes:        rawConfig.EKSMaxNodes,
+		EKSDesiredNodes:    rawConfig.EKSDesiredNodes,
+		EKSNodeVolumeSize:  rawConfig.EKSNodeVolumeSize,
+		EKSFargate:         rawConfig.EKSFargate,
+		Replicas:           max(rawConfig.Replicas, 0),
+		LambdaMemory:       rawConfig.LambdaMemory,
+		LambdaTimeout:      rawConfig.LambdaTimeout,
+		AutoscaleTargetCPU: validPercent(rawConfig.AutoscaleTargetCPU),
+		BudgetUSD:          max(rawConfig.BudgetUSD, 0),
+		SizingHint:         rawConfig.SizingHint,
 	}
 
 	return config, nil
 }
 
 // extractJSON finds and extracts JSON object from text
-func extractJSON(text string) string {
+func extractJSON(text string) (string, error) {
 	// Find first { and last }
 	start := strings.Index(text, "{")
 	end := strings.LastIndex(text, "}")
 
 	if start == -1 || end == -1 || start >= end {
-		return "{}" // Return empty JSON instead of raw text
+		return "", fmt.Errorf("no JSON object in the response")
 	}
 
 	extracted := text[start : end+1]
@@ -320,10 +450,10 @@ func extractJSON(text string) string {
 	// Validate it's parseable JSON
 	var test interface{}
 	if err := json.Unmarshal([]byte(extracted), &test); err != nil {
-		return "{}" // Return empty JSON on parse failure
+		return "", fmt.Errorf("invalid JSON object: %w", err)
 	}
 
-	return extracted
+	return extracted, nil
 }
 
 // ApplyConfig applies parsed configuration to deployer config
@@ -368,6 +498,14 @@ func ApplyConfig(deployConfig *deployer.DeployConfig, parsedConfig *DeploymentCo
 		deployConfig.EKSNodeVolumeSize = parsedConfig.EKSNodeVolumeSize
 	}
 
+	if parsedConfig.EKSFargate || parsedConfig.EKSFargateSet {
+		deployConfig.EKSFargate = parsedConfig.EKSFargate
+	}
+
+	if parsedConfig.Replicas > 0 {
+		deployConfig.Replicas = parsedConfig.Replicas
+	}
+
 	if parsedConfig.LambdaMemory > 0 {
 		deployConfig.LambdaMemory = parsedConfig.LambdaMemory
 	}
@@ -375,4 +513,12 @@ func ApplyConfig(deployConfig *deployer.DeployConfig, parsedConfig *DeploymentCo
 	if parsedConfig.LambdaTimeout > 0 {
 		deployConfig.LambdaTimeout = parsedConfig.LambdaTimeout
 	}
+
+	if parsedConfig.AutoscaleTargetCPU > 0 {
+		deployConfig.AutoscaleTargetCPU = parsedConfig.AutoscaleTargetCPU
+	}
+
+	if parsedConfig.BudgetUSD > 0 {
+		deployConfig.BudgetUSD = parsedConfig.BudgetUSD
+	}
 }
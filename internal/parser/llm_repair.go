@@ -0,0 +1,82 @@
+package parser
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Smana/scai/internal/llm"
+)
+
+// ConfigRepairPrompt is appended to ConfigExtractionPrompt to re-prompt the model after a
+// response that could not be used
+const ConfigRepairPrompt = `
+**Your previous response could not be used:** %s
+
+**Previous response:**
+%s
+
+Fix it: respond with ONLY the JSON object, with the exact field names above, and include every
+parameter stated in the user's request.
+`
+
+// PromptParseError reports an LLM extraction of the prompt configuration that cannot be trusted
+type PromptParseError struct {
+	Response string   // Raw LLM response
+	Err      error    // Why the response could not be parsed, nil when it was
+	Missed   []string // Values stated in the prompt missing from the parsed response (e.g. "instance type t3.large")
+}
+
+func (e *PromptParseError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("the LLM response is not a valid configuration: %v", e.Err)
+	}
+	return fmt.Sprintf("the LLM extraction dropped values stated in the prompt: %s", strings.Join(e.Missed, ", "))
+}
+
+func (e *PromptParseError) Unwrap() error {
+	return e.Err
+}
+
+// RepairConfigFromPrompt re-prompts the model to extract the configuration of userPrompt, with
+// the response of parseErr and what was wrong with it. It returns the same errors as
+// ParseConfigFromPromptStrict.
+func RepairConfigFromPrompt(ctx context.Context, llmClient *llm.Client, userPrompt string, parseErr *PromptParseError) (*DeploymentConfig, error) {
+	prompt := fmt.Sprintf(ConfigExtractionPrompt, userPrompt) +
+		fmt.Sprintf(ConfigRepairPrompt, parseErr.Error(), parseErr.Response)
+	return extractConfig(ctx, llmClient, userPrompt, prompt)
+}
+
+// missedValues returns the values the deterministic patterns find in userPrompt that are absent
+// from config: a stated instance type, volume or memory size, node count or region
+func missedValues(userPrompt string, config *DeploymentConfig) []string {
+	prompt := strings.ToLower(userPrompt)
+	strategy := config.Strategy
+	if strategy == "" {
+		strategy = extractStrategy(prompt)
+	}
+
+	var missed []string
+	if instanceType := extractEC2InstanceType(prompt); instanceType != "" && config.EC2InstanceType == "" && config.EKSNodeType == "" {
+		missed = append(missed, "instance type "+instanceType)
+	}
+
+	sized := config.EC2VolumeSize > 0 || config.EKSNodeVolumeSize > 0 || config.LambdaMemory > 0
+	if memory := extractLambdaMemory(prompt); strategy == "serverless" && memory > 0 && !sized {
+		missed = append(missed, fmt.Sprintf("memory %dMB", memory))
+	} else if volume := extractVolumeSize(prompt); strategy != "serverless" && volume > 0 && !sized {
+		missed = append(missed, fmt.Sprintf("volume size %dGB", volume))
+	}
+
+	if strategy == "kubernetes" && !config.EKSFargate {
+		nodes := config.EKSMinNodes > 0 || config.EKSMaxNodes > 0 || config.EKSDesiredNodes > 0
+		if _, _, desired := extractNodeCounts(prompt); desired > 0 && !nodes {
+			missed = append(missed, fmt.Sprintf("node count %d", desired))
+		}
+	}
+
+	if region := extractRegion(prompt); region != "" && config.Region == "" {
+		missed = append(missed, "region "+region)
+	}
+	return missed
+}
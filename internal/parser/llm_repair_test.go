@@ -0,0 +1,79 @@
+package parser
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseConfigJSONRejectsNonJSON(t *testing.T) {
+	tests := []struct {
+		response string
+		wantErr  bool
+	}{
+		{`{"ec2_instance_type": "t3.large"}`, false},
+		{"Sure! Here it is: {\"region\": \"eu-west-1\"} Hope it helps.", false},
+		{"The user wants a t3.large instance.", true},
+		{`{"ec2_instance_type": "t3.large",}`, true},
+	}
+
+	for _, tt := range tests {
+		_, err := parseConfigJSON(tt.response)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseConfigJSON(%q) error = %v, wantErr %v", tt.response, err, tt.wantErr)
+		}
+	}
+}
+
+func TestMissedValues(t *testing.T) {
+	tests := []struct {
+		name   string
+		prompt string
+		config DeploymentConfig
+		want   []string
+	}{
+		{
+			name:   "instance type dropped",
+			prompt: "deploy on a t3.large in eu-west-1",
+			config: DeploymentConfig{Region: "eu-west-1"},
+			want:   []string{"instance type t3.large"},
+		},
+		{
+			name:   "everything extracted",
+			prompt: "deploy on a t3.large with a 50GB disk",
+			config: DeploymentConfig{EC2InstanceType: "t3.large", EC2VolumeSize: 50},
+		},
+		{
+			name:   "instance type as node type",
+			prompt: "EKS with 3 nodes t3.large",
+			config: DeploymentConfig{Strategy: "kubernetes", EKSNodeType: "t3.large", EKSDesiredNodes: 3},
+		},
+		{
+			name:   "empty extraction",
+			prompt: "EKS cluster with 3 nodes t3.large and 50GB disk in us-east-1",
+			want:   []string{"instance type t3.large", "volume size 50GB", "node count 3", "region us-east-1"},
+		},
+		{
+			name:   "lambda memory",
+			prompt: "serverless function with 512MB",
+			config: DeploymentConfig{Strategy: "serverless"},
+			want:   []string{"memory 512MB"},
+		},
+		{
+			name:   "fargate has no nodes",
+			prompt: "run 3 nodes worth of pods on fargate",
+			config: DeploymentConfig{Strategy: "kubernetes", EKSFargate: true},
+		},
+		{
+			name:   "no sizing terms",
+			prompt: "deploy this Flask app on AWS",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := missedValues(tt.prompt, &tt.config); !slices.Equal(got, tt.want) {
+				t.Errorf("missedValues(%q) = %q, want %q", tt.prompt, got, tt.want)
+			}
+		})
+	}
+}
@@ -8,18 +8,24 @@ import (
 
 // DeploymentConfig holds parsed configuration from natural language
 type DeploymentConfig struct {
-	Strategy          string
-	Region            string
-	EC2InstanceType   string
-	EC2VolumeSize     int
-	LambdaMemory      int
-	LambdaTimeout     int
-	EKSNodeType       string
-	EKSMinNodes       int
-	EKSMaxNodes       int
-	EKSDesiredNodes   int
-	EKSNodeVolumeSize int
-	CleanedPrompt     string // Prompt with config keywords removed
+	Strategy           string
+	Region             string
+	EC2InstanceType    string
+	EC2VolumeSize      int
+	LambdaMemory       int
+	LambdaTimeout      int
+	EKSNodeType        string
+	EKSMinNodes        int
+	EKSMaxNodes        int
+	EKSDesiredNodes    int
+	EKSNodeVolumeSize  int
+	EKSFargate         bool
+	EKSFargateSet      bool    // EKSFargate given explicitly (--set eks_fargate), so that false overrides too
+	Replicas           int     // Kubernetes Deployment replicas (pods, not nodes: "run 4 replicas")
+	AutoscaleTargetCPU int     // Target CPU utilization in percent (e.g. "scale up at 70% CPU")
+	BudgetUSD          float64 // Monthly cost budget in USD (e.g. "keep it under $50/month")
+	SizingHint         string  // Qualitative sizing (e.g. "small", "large memory-optimized"), resolved into the instance types not given
+	CleanedPrompt      string  // Prompt with config keywords removed
 }
 
 // ParsePrompt extracts deployment configuration from natural language prompt
@@ -40,8 +46,12 @@ func ParsePrompt(prompt string) *DeploymentConfig {
 	config.EC2InstanceType = extractEC2InstanceType(promptLower)
 	config.EKSNodeType = extractEKSNodeType(promptLower)
 
-	// Extract node counts
+	// Extract EKS compute type
+	config.EKSFargate = extractEKSFargate(promptLower)
+
+	// Extract node counts, and the replica count of the application pods
 	config.EKSMinNodes, config.EKSMaxNodes, config.EKSDesiredNodes = extractNodeCounts(promptLower)
+	config.Replicas = ExtractReplicas(promptLower)
 
 	// Extract memory/storage
 	config.LambdaMemory = extractLambdaMemory(promptLower)
@@ -51,6 +61,16 @@ func ParsePrompt(prompt string) *DeploymentConfig {
 	// Extract timeout
 	config.LambdaTimeout = extractTimeout(promptLower)
 
+	// Extract autoscaling target
+	config.AutoscaleTargetCPU = ExtractAutoscaleTargetCPU(promptLower)
+
+	// Extract monthly budget
+	config.BudgetUSD = ExtractBudgetUSD(promptLower)
+
+	// Qualitative sizing ("a small cluster", "memory-optimized") for the instance types not named
+	config.SizingHint = ExtractSizingHint(promptLower)
+	config.resolveSizingHint()
+
 	// Clean the prompt (remove extracted config)
 	config.CleanedPrompt = cleanPrompt(prompt, config)
 
@@ -60,7 +80,7 @@ func ParsePrompt(prompt string) *DeploymentConfig {
 // extractStrategy identifies deployment strategy from keywords
 func extractStrategy(prompt string) string {
 	// EKS/Kubernetes patterns
-	if regexp.MustCompile(`\b(eks|kubernetes|k8s)\b`).MatchString(prompt) {
+	if regexp.MustCompile(`\b(eks|kubernetes|k8s|fargate)\b`).MatchString(prompt) {
 		return "kubernetes"
 	}
 
@@ -108,6 +128,11 @@ func extractEKSNodeType(prompt string) string {
 	return match
 }
 
+// extractEKSFargate detects a request for EKS Fargate instead of managed nodes
+func extractEKSFargate(prompt string) bool {
+	return regexp.MustCompile(`\bfargate\b`).MatchString(prompt)
+}
+
 // extractNodeCounts extracts min/max/desired node counts for EKS
 func extractNodeCounts(prompt string) (minNodes, maxNodes, desiredNodes int) {
 	// Pattern: "3 nodes", "5 instances", "between 2 and 5 nodes", "min 1 max 3"
@@ -153,6 +178,25 @@ func extractNodeCounts(prompt string) (minNodes, maxNodes, desiredNodes int) {
 	return minNodes, maxNodes, desiredNodes
 }
 
+// replicaPatterns match a pod replica count: "4 replicas", "6 pods", "replicas: 3", "replica count of 5",
+// "scale to 4 replicas". Nodes and instances are worker counts, handled by extractNodeCounts.
+var replicaPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)\b(\d+)\s+(?:app\s+|application\s+)?(?:replicas?|pods?)\b`),
+	regexp.MustCompile(`(?i)\breplicas?(?:\s+count)?\s*(?:of|to|=|:)?\s*(\d+)\b`),
+}
+
+// ExtractReplicas extracts the number of replicas of the application (0 if none)
+func ExtractReplicas(prompt string) int {
+	for _, re := range replicaPatterns {
+		if matches := re.FindStringSubmatch(prompt); len(matches) > 1 {
+			if replicas, _ := strconv.Atoi(matches[1]); replicas > 0 {
+				return replicas
+			}
+		}
+	}
+	return 0
+}
+
 // extractLambdaMemory extracts Lambda memory in MB
 func extractLambdaMemory(prompt string) int {
 	// Pattern: "512MB", "1GB", "2048 MB", "1 GB"
@@ -221,12 +265,53 @@ func extractTimeout(prompt string) int {
 	return 0
 }
 
+// autoscaleCPUPatterns match a CPU utilization target: "scale up at 70%", "autoscale above 70 %",
+// "70% cpu", "cpu at 70%", "target cpu utilization of 70%" (longest phrases first for cleanPrompt)
+var autoscaleCPUPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)\b(?:auto-?scal\w*|scal(?:e|ing))(?:\s+(?:up|out))?\s+(?:at|above|over|when\s+\w+\s+(?:reaches|exceeds|hits))\s+(\d{1,3})\s*%`),
+	regexp.MustCompile(`(?i)\b(\d{1,3})\s*%\s*(?:of\s+)?cpu\b`),
+	regexp.MustCompile(`(?i)\bcpu(?:\s+(?:target|utili[sz]ation|usage|load))*(?:\s+(?:of|at|to|above|reaches|over))?\s+(\d{1,3})\s*%`),
+}
+
+// ExtractAutoscaleTargetCPU extracts a target CPU utilization in percent (0 if none)
+func ExtractAutoscaleTargetCPU(prompt string) int {
+	for _, re := range autoscaleCPUPatterns {
+		if matches := re.FindStringSubmatch(prompt); len(matches) > 1 {
+			if percent, _ := strconv.Atoi(matches[1]); percent >= 1 && percent <= 100 {
+				return percent
+			}
+		}
+	}
+	return 0
+}
+
+// budgetPatterns match a monthly budget: "under $50/month", "max $100 per month", "$30 a month",
+// "40 usd/mo", "budget of $75", "budget of 75 dollars" (a budget without period is monthly)
+var budgetPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)\b(?:(?:keep\s+it\s+)?(?:under|below|less\s+than|at\s+most|no\s+more\s+than|max(?:imum)?(?:\s+of)?|within|up\s+to|cap(?:ped)?\s+at)|budget(?:\s+(?:of|is))?)\s+\$\s*(\d+(?:\.\d+)?)(?:\s*(?:usd|dollars?))?(?:\s*(?:/|per|a)\s*(?:month|mo)\b)?`),
+	regexp.MustCompile(`(?i)\$\s*(\d+(?:\.\d+)?)\s*(?:usd\s*)?(?:/|per|a)\s*(?:month|mo)\b`),
+	regexp.MustCompile(`(?i)\b(\d+(?:\.\d+)?)\s*(?:\$|usd|dollars?)\s*(?:/|per|a)\s*(?:month|mo)\b`),
+	regexp.MustCompile(`(?i)\bbudget(?:\s+(?:of|is))?\s+(\d+(?:\.\d+)?)\s*(?:\$|usd|dollars?)`),
+}
+
+// ExtractBudgetUSD extracts a monthly cost budget in USD (0 if none)
+func ExtractBudgetUSD(prompt string) float64 {
+	for _, re := range budgetPatterns {
+		if matches := re.FindStringSubmatch(prompt); len(matches) > 1 {
+			if budget, err := strconv.ParseFloat(matches[1], 64); err == nil && budget > 0 {
+				return budget
+			}
+		}
+	}
+	return 0
+}
+
 // cleanPrompt removes extracted configuration keywords from prompt
 func cleanPrompt(originalPrompt string, config *DeploymentConfig) string {
 	cleaned := originalPrompt
 
 	// Remove strategy keywords
-	cleaned = regexp.MustCompile(`\b(?:on\s+)?(?:eks|kubernetes|k8s|lambda|serverless|ec2|vm|virtual machine)\b`).ReplaceAllString(cleaned, "")
+	cleaned = regexp.MustCompile(`\b(?:on\s+)?(?:eks|kubernetes|k8s|fargate|lambda|serverless|ec2|vm|virtual machine)\b`).ReplaceAllString(cleaned, "")
 
 	// Remove region
 	if config.Region != "" {
@@ -249,6 +334,9 @@ func cleanPrompt(originalPrompt string, config *DeploymentConfig) string {
 	cleaned = regexp.MustCompile(`\bbetween\s+\d+\s+and\s+\d+\s+(?:nodes?|instances?)\b`).ReplaceAllString(cleaned, "")
 	cleaned = regexp.MustCompile(`\bmin(?:imum)?\s+\d+\b`).ReplaceAllString(cleaned, "")
 	cleaned = regexp.MustCompile(`\bmax(?:imum)?\s+\d+\b`).ReplaceAllString(cleaned, "")
+	for _, re := range replicaPatterns {
+		cleaned = re.ReplaceAllString(cleaned, "")
+	}
 
 	// Remove memory/storage phrases
 	cleaned = regexp.MustCompile(`\b\d+\s*(?:MB|GB|mb|gb)\b`).ReplaceAllString(cleaned, "")
@@ -257,6 +345,16 @@ func cleanPrompt(originalPrompt string, config *DeploymentConfig) string {
 	// Remove timeout phrases
 	cleaned = regexp.MustCompile(`\b(?:timeout\s+)?\d+\s*(?:seconds?|secs?|minutes?|mins?|s|m)\b`).ReplaceAllString(cleaned, "")
 
+	// Remove autoscaling phrases
+	for _, re := range autoscaleCPUPatterns {
+		cleaned = re.ReplaceAllString(cleaned, "")
+	}
+
+	// Remove budget phrases
+	for _, re := range budgetPatterns {
+		cleaned = re.ReplaceAllString(cleaned, "")
+	}
+
 	// Clean up extra whitespace
 	cleaned = regexp.MustCompile(`\s+`).ReplaceAllString(cleaned, " ")
 	cleaned = strings.TrimSpace(cleaned)
@@ -0,0 +1,70 @@
+package parser
+
+import "testing"
+
+func TestExtractAutoscaleTargetCPU(t *testing.T) {
+	tests := []struct {
+		prompt string
+		want   int
+	}{
+		{"deploy on EKS and scale up at 70%", 70},
+		{"autoscale when cpu exceeds 60 %", 60},
+		{"deploy with a 75% CPU target", 75},
+		{"target CPU utilization of 50%", 50},
+		{"deploy with 150% cpu", 0},
+		{"deploy this Flask app on AWS", 0},
+	}
+
+	for _, tt := range tests {
+		if got := ExtractAutoscaleTargetCPU(tt.prompt); got != tt.want {
+			t.Errorf("ExtractAutoscaleTargetCPU(%q) = %d, want %d", tt.prompt, got, tt.want)
+		}
+	}
+}
+
+func TestExtractBudgetUSD(t *testing.T) {
+	tests := []struct {
+		prompt string
+		want   float64
+	}{
+		{"deploy this API and keep it under $50/month", 50},
+		{"deploy on EKS, max $120 per month", 120},
+		{"it must cost $30 a month at most", 30},
+		{"run it for 40 USD/mo", 40},
+		{"we have a budget of $75.50", 75.5},
+		{"budget is 200 dollars", 200},
+		{"deploy with a 512MB Lambda and a 30 seconds timeout", 0},
+		{"deploy on 3 nodes with max 5", 0},
+		{"deploy this Flask app on AWS", 0},
+	}
+
+	for _, tt := range tests {
+		if got := ExtractBudgetUSD(tt.prompt); got != tt.want {
+			t.Errorf("ExtractBudgetUSD(%q) = %v, want %v", tt.prompt, got, tt.want)
+		}
+	}
+}
+
+func TestParsePromptReplicasAndNodes(t *testing.T) {
+	tests := []struct {
+		prompt       string
+		wantReplicas int
+		wantNodes    int
+	}{
+		{"Deploy on EKS with 4 replicas on 3 nodes", 4, 3},
+		{"deploy on kubernetes, 2 nodes running 6 pods", 6, 2},
+		{"EKS cluster with 5 instances and replicas: 3", 3, 5},
+		{"deploy on EKS with 3 nodes", 0, 3},
+		{"run 4 replicas on Kubernetes", 4, 0},
+	}
+
+	for _, tt := range tests {
+		config := ParsePrompt(tt.prompt)
+		if config.Replicas != tt.wantReplicas {
+			t.Errorf("ParsePrompt(%q).Replicas = %d, want %d", tt.prompt, config.Replicas, tt.wantReplicas)
+		}
+		if config.EKSDesiredNodes != tt.wantNodes {
+			t.Errorf("ParsePrompt(%q).EKSDesiredNodes = %d, want %d", tt.prompt, config.EKSDesiredNodes, tt.wantNodes)
+		}
+	}
+}
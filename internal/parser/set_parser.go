@@ -0,0 +1,107 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// setKeys lists the parameters accepted by --set, matching the JSON fields the LLM extracts
+var setKeys = []string{
+	"strategy", "region",
+	"ec2_instance_type", "volume_size",
+	"eks_node_type", "eks_min_nodes", "eks_max_nodes", "eks_desired_nodes", "eks_node_volume_size", "eks_fargate", "replicas",
+	"lambda_memory", "lambda_timeout",
+	"autoscale_target_cpu",
+	"budget_usd",
+	"sizing_hint",
+}
+
+// ParseSetOverrides parses --set key=value pairs into a DeploymentConfig,
+// without involving the LLM. Apply the result with ApplyConfig.
+func ParseSetOverrides(pairs []string) (*DeploymentConfig, error) {
+	config := &DeploymentConfig{}
+
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		if !ok || key == "" || value == "" {
+			return nil, fmt.Errorf("invalid --set %q: expected key=value", pair)
+		}
+
+		if err := setParameter(config, key, value); err != nil {
+			return nil, fmt.Errorf("invalid --set %q: %w", pair, err)
+		}
+	}
+
+	// An explicit instance type takes precedence over the sizing hint
+	config.resolveSizingHint()
+	return config, nil
+}
+
+// setParameter assigns a single --set value to the matching DeploymentConfig field
+func setParameter(config *DeploymentConfig, key, value string) error {
+	var err error
+	switch key {
+	case "strategy":
+		if value != "vm" && value != "kubernetes" && value != "serverless" {
+			return fmt.Errorf("strategy must be vm, kubernetes or serverless")
+		}
+		config.Strategy = value
+	case "region":
+		config.Region = value
+	case "ec2_instance_type":
+		config.EC2InstanceType = value
+	case "volume_size":
+		config.EC2VolumeSize, err = positiveInt(value)
+	case "eks_node_type":
+		config.EKSNodeType = value
+	case "eks_min_nodes":
+		config.EKSMinNodes, err = positiveInt(value)
+	case "eks_max_nodes":
+		config.EKSMaxNodes, err = positiveInt(value)
+	case "eks_desired_nodes":
+		config.EKSDesiredNodes, err = positiveInt(value)
+	case "eks_node_volume_size":
+		config.EKSNodeVolumeSize, err = positiveInt(value)
+	case "eks_fargate":
+		config.EKSFargate, err = strconv.ParseBool(value)
+		if err != nil {
+			err = fmt.Errorf("expected true or false")
+		}
+		config.EKSFargateSet = err == nil
+	case "replicas":
+		config.Replicas, err = positiveInt(value)
+	case "lambda_memory":
+		config.LambdaMemory, err = positiveInt(value)
+	case "lambda_timeout":
+		config.LambdaTimeout, err = positiveInt(value)
+	case "autoscale_target_cpu":
+		config.AutoscaleTargetCPU, err = positiveInt(value)
+		if err == nil && config.AutoscaleTargetCPU > 100 {
+			err = fmt.Errorf("expected a percentage between 1 and 100")
+		}
+	case "budget_usd":
+		config.BudgetUSD, err = strconv.ParseFloat(strings.TrimPrefix(value, "$"), 64)
+		if err != nil || config.BudgetUSD <= 0 {
+			err = fmt.Errorf("expected a positive amount in USD")
+		}
+	case "sizing_hint":
+		if ResolveSizingHint(value, false) == "" {
+			return fmt.Errorf("expected a size (tiny, small, medium, large, xlarge) and/or a workload (general-purpose, memory-optimized, compute-optimized)")
+		}
+		config.SizingHint = value
+	default:
+		return fmt.Errorf("unknown key (valid keys: %s)", strings.Join(setKeys, ", "))
+	}
+	return err
+}
+
+func positiveInt(value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("expected a positive integer")
+	}
+	return n, nil
+}
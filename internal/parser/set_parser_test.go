@@ -0,0 +1,88 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/Smana/scai/internal/deployer"
+)
+
+func TestParseSetOverrides(t *testing.T) {
+	config, err := ParseSetOverrides([]string{
+		"strategy=kubernetes",
+		"ec2_instance_type=t3.large",
+		"volume_size = 50",
+		"eks_desired_nodes=3",
+		"eks_fargate=true",
+		"autoscale_target_cpu=70",
+	})
+	if err != nil {
+		t.Fatalf("ParseSetOverrides failed: %v", err)
+	}
+
+	if config.Strategy != "kubernetes" {
+		t.Errorf("Expected strategy kubernetes, got %s", config.Strategy)
+	}
+	if config.EC2InstanceType != "t3.large" {
+		t.Errorf("Expected instance type t3.large, got %s", config.EC2InstanceType)
+	}
+	if config.EC2VolumeSize != 50 {
+		t.Errorf("Expected volume size 50, got %d", config.EC2VolumeSize)
+	}
+	if config.EKSDesiredNodes != 3 {
+		t.Errorf("Expected 3 desired nodes, got %d", config.EKSDesiredNodes)
+	}
+	if !config.EKSFargate {
+		t.Error("Expected eks_fargate to be set")
+	}
+	if config.AutoscaleTargetCPU != 70 {
+		t.Errorf("Expected autoscale target 70, got %d", config.AutoscaleTargetCPU)
+	}
+}
+
+func TestApplySetEKSFargateFalse(t *testing.T) {
+	// The prompt asked for Fargate, --set eks_fargate=false overrides it
+	deployConfig := &deployer.DeployConfig{Strategy: "kubernetes"}
+	ApplyConfig(deployConfig, &DeploymentConfig{EKSFargate: true})
+	if !deployConfig.EKSFargate {
+		t.Fatal("Expected the prompt to enable Fargate")
+	}
+
+	config, err := ParseSetOverrides([]string{"eks_fargate=false"})
+	if err != nil {
+		t.Fatalf("ParseSetOverrides failed: %v", err)
+	}
+	ApplyConfig(deployConfig, config)
+	if deployConfig.EKSFargate {
+		t.Error("Expected --set eks_fargate=false to disable Fargate")
+	}
+
+	// Without eks_fargate, --set leaves it unchanged
+	deployConfig.EKSFargate = true
+	config, err = ParseSetOverrides([]string{"replicas=2"})
+	if err != nil {
+		t.Fatalf("ParseSetOverrides failed: %v", err)
+	}
+	ApplyConfig(deployConfig, config)
+	if !deployConfig.EKSFargate {
+		t.Error("Expected --set without eks_fargate to keep Fargate")
+	}
+}
+
+func TestParseSetOverridesInvalid(t *testing.T) {
+	invalid := []string{
+		"ec2_instance_type",
+		"volume_size=",
+		"volume_size=big",
+		"eks_min_nodes=0",
+		"strategy=mainframe",
+		"eks_fargate=maybe",
+		"autoscale_target_cpu=150",
+		"unknown_key=1",
+	}
+
+	for _, pair := range invalid {
+		if _, err := ParseSetOverrides([]string{pair}); err == nil {
+			t.Errorf("Expected error for --set %q", pair)
+		}
+	}
+}
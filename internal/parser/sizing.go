@@ -0,0 +1,116 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Instance sizes and workload profiles of a sizing hint
+const (
+	sizeTiny   = "tiny"
+	sizeSmall  = "small"
+	sizeMedium = "medium"
+	sizeLarge  = "large"
+	sizeXLarge = "xlarge"
+
+	workloadGeneral = "general-purpose"
+	workloadMemory  = "memory-optimized"
+	workloadCompute = "compute-optimized"
+)
+
+// sizeAliases maps the words of a sizing hint to an instance size
+var sizeAliases = map[string]string{
+	"tiny": sizeTiny, "nano": sizeTiny, "micro": sizeTiny, "minimal": sizeTiny,
+	"small":  sizeSmall,
+	"medium": sizeMedium, "moderate": sizeMedium,
+	"large": sizeLarge, "big": sizeLarge,
+	"xlarge": sizeXLarge, "x-large": sizeXLarge, "extra-large": sizeXLarge, "huge": sizeXLarge,
+}
+
+// workloadAliases maps the words of a sizing hint to a workload profile
+var workloadAliases = map[string]string{
+	"general": workloadGeneral, "general-purpose": workloadGeneral, "balanced": workloadGeneral,
+	"memory": workloadMemory, "memory-optimized": workloadMemory, "high-memory": workloadMemory,
+	"memory-intensive": workloadMemory, "ram": workloadMemory,
+	"compute": workloadCompute, "compute-optimized": workloadCompute, "cpu": workloadCompute,
+	"high-cpu": workloadCompute, "cpu-intensive": workloadCompute, "cpu-optimized": workloadCompute,
+}
+
+// sizingInstanceTypes is the instance type of each workload profile and size: burstable t3
+// for general-purpose workloads, r-series for memory and c-series for compute (whose
+// smallest size is large)
+var sizingInstanceTypes = map[string]map[string]string{
+	workloadGeneral: {
+		sizeTiny: "t3.micro", sizeSmall: "t3.small", sizeMedium: "t3.medium", sizeLarge: "t3.large", sizeXLarge: "t3.xlarge",
+	},
+	workloadMemory: {
+		sizeTiny: "r6i.large", sizeSmall: "r6i.large", sizeMedium: "r6i.large", sizeLarge: "r6i.xlarge", sizeXLarge: "r6i.2xlarge",
+	},
+	workloadCompute: {
+		sizeTiny: "c6i.large", sizeSmall: "c6i.large", sizeMedium: "c6i.large", sizeLarge: "c6i.xlarge", sizeXLarge: "c6i.2xlarge",
+	},
+}
+
+// sizingPatterns match qualitative sizing in a prompt: a size qualifying the compute ("a small
+// cluster", "large instance") or a workload profile ("memory-optimized", "high CPU")
+var sizingPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)\b(tiny|small|medium|large|big|huge|micro)\s+(?:(?:high[- ])?(?:memory|compute|cpu)(?:[- ]\w+)?\s+)?(?:eks\s+|k8s\s+|kubernetes\s+|ec2\s+)?(?:cluster|instances?|vms?|servers?|machines?|nodes?)\b`),
+	regexp.MustCompile(`(?i)\b(memory[- ]optimi[sz]ed|high[- ]memory|memory[- ]intensive|compute[- ]optimi[sz]ed|cpu[- ]optimi[sz]ed|high[- ]cpu|cpu[- ]intensive)\b`),
+}
+
+// ExtractSizingHint extracts qualitative sizing from a prompt (e.g. "small memory-optimized"),
+// empty if none
+func ExtractSizingHint(prompt string) string {
+	var parts []string
+	for _, re := range sizingPatterns {
+		if matches := re.FindStringSubmatch(prompt); len(matches) > 1 {
+			parts = append(parts, strings.ToLower(strings.ReplaceAll(matches[1], " ", "-")))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// ResolveSizingHint returns the instance type of a sizing hint such as "small", "large
+// memory-optimized" or "compute-optimized" (medium when only the workload is given), empty
+// when the hint names neither a size nor a workload. Kubernetes nodes are at least small:
+// a micro node has too little memory for the system pods.
+func ResolveSizingHint(hint string, node bool) string {
+	size, workload := "", ""
+	for _, word := range strings.FieldsFunc(strings.ToLower(hint), func(r rune) bool {
+		return r == ' ' || r == ',' || r == '_' || r == '/'
+	}) {
+		if s, ok := sizeAliases[word]; ok {
+			size = s
+		}
+		if w, ok := workloadAliases[word]; ok {
+			workload = w
+		}
+	}
+	if size == "" && workload == "" {
+		return ""
+	}
+
+	if size == "" {
+		size = sizeMedium
+	}
+	if workload == "" {
+		workload = workloadGeneral
+	}
+	if node && size == sizeTiny {
+		size = sizeSmall
+	}
+	return sizingInstanceTypes[workload][size]
+}
+
+// resolveSizingHint sets the instance types that were not given explicitly from the sizing hint
+func (c *DeploymentConfig) resolveSizingHint() {
+	if c.SizingHint == "" {
+		return
+	}
+	if c.EC2InstanceType == "" {
+		c.EC2InstanceType = ResolveSizingHint(c.SizingHint, false)
+	}
+	if c.EKSNodeType == "" {
+		c.EKSNodeType = ResolveSizingHint(c.SizingHint, true)
+	}
+}
@@ -0,0 +1,59 @@
+package parser
+
+import "testing"
+
+func TestResolveSizingHint(t *testing.T) {
+	tests := []struct {
+		hint string
+		node bool
+		want string
+	}{
+		{"small", false, "t3.small"},
+		{"tiny", false, "t3.micro"},
+		{"tiny", true, "t3.small"},
+		{"large memory-optimized", false, "r6i.xlarge"},
+		{"high memory", false, "r6i.large"},
+		{"compute-optimized", false, "c6i.large"},
+		{"huge, cpu-intensive", false, "c6i.2xlarge"},
+		{"fast", false, ""},
+	}
+
+	for _, tt := range tests {
+		if got := ResolveSizingHint(tt.hint, tt.node); got != tt.want {
+			t.Errorf("ResolveSizingHint(%q, %v) = %q, want %q", tt.hint, tt.node, got, tt.want)
+		}
+	}
+}
+
+func TestParsePromptSizingHint(t *testing.T) {
+	tests := []struct {
+		prompt       string
+		wantInstance string
+		wantNode     string
+	}{
+		{"Deploy on EKS with a small cluster", "t3.small", "t3.small"},
+		{"deploy on a memory-optimized instance", "r6i.large", "r6i.large"},
+		{"deploy on a large high CPU vm", "c6i.xlarge", "c6i.xlarge"},
+		{"deploy a small app on a t3.large", "t3.large", "t3.large"},
+		{"deploy this Flask app on AWS", "", ""},
+	}
+
+	for _, tt := range tests {
+		config := ParsePrompt(tt.prompt)
+		if config.EC2InstanceType != tt.wantInstance || config.EKSNodeType != tt.wantNode {
+			t.Errorf("ParsePrompt(%q) instance types = %q, %q, want %q, %q",
+				tt.prompt, config.EC2InstanceType, config.EKSNodeType, tt.wantInstance, tt.wantNode)
+		}
+	}
+
+	config, err := ParseSetOverrides([]string{"sizing_hint=large", "ec2_instance_type=m5.large"})
+	if err != nil {
+		t.Fatalf("ParseSetOverrides() error = %v", err)
+	}
+	if config.EC2InstanceType != "m5.large" || config.EKSNodeType != "t3.large" {
+		t.Errorf("ParseSetOverrides() instance types = %q, %q, want m5.large, t3.large", config.EC2InstanceType, config.EKSNodeType)
+	}
+	if _, err := ParseSetOverrides([]string{"sizing_hint=fast"}); err == nil {
+		t.Error("ParseSetOverrides() accepted an unknown sizing hint")
+	}
+}
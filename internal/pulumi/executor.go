@@ -0,0 +1,162 @@
+package pulumi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Smana/scai/internal/console"
+)
+
+// StopTimeout is how long a canceled pulumi command may take to stop gracefully after being
+// interrupted (saving the state, releasing the stack lock), before it is killed
+const StopTimeout = 5 * time.Minute
+
+// DefaultBackendURL is where the stacks are stored unless PULUMI_BACKEND_URL is set (e.g. to
+// s3:// or https://app.pulumi.com): local state, as Terraform without an S3 backend
+const DefaultBackendURL = "file://~/.scai/pulumi"
+
+// Executor runs the pulumi commands of a deployment's stack
+type Executor struct {
+	workDir string
+	bin     string
+	stack   string
+	verbose bool
+	ctx     context.Context // Cancels the running commands (interrupt, --timeout)
+}
+
+// NewExecutor creates a new Pulumi executor of the stack of deploymentID in workDir
+func NewExecutor(workDir, bin, deploymentID string, verbose bool) (*Executor, error) {
+	if filepath.Base(bin) != "pulumi" {
+		return nil, fmt.Errorf("invalid pulumi binary: binary name must be 'pulumi', got: %s", filepath.Base(bin))
+	}
+	absPath, err := exec.LookPath(bin)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pulumi binary: binary not found in PATH: %w", err)
+	}
+
+	return &Executor{
+		workDir: workDir,
+		bin:     absPath,
+		stack:   StackName(deploymentID),
+		verbose: verbose,
+		ctx:     context.Background(),
+	}, nil
+}
+
+// StackName returns the stack of a deployment
+func StackName(deploymentID string) string {
+	if deploymentID == "" {
+		return "scai"
+	}
+	return "scai-" + deploymentID
+}
+
+// SetContext sets the context of the pulumi commands: once it is canceled, the running
+// command is interrupted and given StopTimeout to stop gracefully
+func (e *Executor) SetContext(ctx context.Context) {
+	e.ctx = ctx
+}
+
+// command returns a command run in the working directory, interrupted when the context of
+// the executor is canceled
+func (e *Executor) command(bin string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(e.ctx, bin, args...)
+	cmd.Dir = e.workDir
+	cmd.Cancel = func() error { return cmd.Process.Signal(os.Interrupt) }
+	cmd.WaitDelay = StopTimeout
+
+	// The generated programs hold no secret: an empty passphrase is enough for the local state
+	env := os.Environ()
+	if os.Getenv("PULUMI_BACKEND_URL") == "" {
+		env = append(env, "PULUMI_BACKEND_URL="+DefaultBackendURL)
+	}
+	if os.Getenv("PULUMI_CONFIG_PASSPHRASE") == "" && os.Getenv("PULUMI_CONFIG_PASSPHRASE_FILE") == "" {
+		env = append(env, "PULUMI_CONFIG_PASSPHRASE=")
+	}
+	env = append(env, "PULUMI_SKIP_UPDATE_CHECK=true")
+	if console.Plain() {
+		env = append(env, "NO_COLOR=1")
+	}
+	cmd.Env = env
+	return cmd
+}
+
+// Init installs the dependencies of the program and selects its stack, created if needed
+func (e *Executor) Init() error {
+	npm, err := exec.LookPath("npm")
+	if err != nil {
+		return fmt.Errorf("npm is required to run the Pulumi TypeScript program: %w", err)
+	}
+	if err := e.run(npm, "install", "--no-audit", "--no-fund"); err != nil {
+		return err
+	}
+	return e.run(e.bin, "stack", "select", e.stack, "--create", "--non-interactive")
+}
+
+// Apply runs pulumi up
+func (e *Executor) Apply() error {
+	return e.run(e.bin, "up", "--stack", e.stack, "--yes", "--skip-preview", "--non-interactive")
+}
+
+// Destroy runs pulumi destroy
+func (e *Executor) Destroy() error {
+	return e.run(e.bin, "destroy", "--stack", e.stack, "--yes", "--skip-preview", "--non-interactive")
+}
+
+// Outputs retrieves the stack outputs as a map; null outputs are omitted
+func (e *Executor) Outputs() (map[string]string, error) {
+	output, err := e.command(e.bin, "stack", "output", "--stack", e.stack, "--json").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get outputs: %w", err)
+	}
+
+	var rawOutputs map[string]any
+	if err := json.Unmarshal(output, &rawOutputs); err != nil {
+		return nil, fmt.Errorf("failed to parse pulumi outputs: %w", err)
+	}
+
+	outputs := make(map[string]string, len(rawOutputs))
+	for key, value := range rawOutputs {
+		switch v := value.(type) {
+		case nil:
+			continue
+		case string:
+			outputs[key] = v
+		case float64:
+			outputs[key] = fmt.Sprintf("%.0f", v)
+		case bool:
+			outputs[key] = fmt.Sprintf("%t", v)
+		default:
+			jsonBytes, _ := json.Marshal(v)
+			outputs[key] = string(jsonBytes)
+		}
+	}
+	return outputs, nil
+}
+
+// run executes a command, streaming its output in verbose mode
+func (e *Executor) run(bin string, args ...string) error {
+	cmd := e.command(bin, args...)
+
+	if e.verbose {
+		fmt.Fprintf(console.Stdout, "   Executing: %s %s\n", bin, strings.Join(args, " "))
+		cmd.Stdout = console.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("command failed: %s %s\nError: %w", bin, strings.Join(args, " "), err)
+		}
+		return nil
+	}
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("command failed: %s %s\nError: %w\nOutput: %s", bin, strings.Join(args, " "), err, string(output))
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+// Package pulumi generates and runs Pulumi TypeScript programs, the alternative to the
+// Terraform configurations selected with iac.engine: pulumi. Only the vm strategy is supported.
+package pulumi
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/Smana/scai/internal/cloud"
+	"github.com/Smana/scai/internal/console"
+	"github.com/Smana/scai/internal/terraform"
+	"github.com/Smana/scai/internal/types"
+)
+
+// SettingsFile is the file the program reads the deployment settings from: the TypeScript
+// program is the same for every deployment, only the settings are generated
+const SettingsFile = "settings.json"
+
+// Generator writes the Pulumi program of a deployment
+type Generator struct {
+	outputDir string
+	verbose   bool
+}
+
+// NewGenerator creates a new Pulumi program generator
+func NewGenerator(outputDir string, verbose bool) *Generator {
+	return &Generator{
+		outputDir: outputDir,
+		verbose:   verbose,
+	}
+}
+
+// settings are the deployment settings of the vm program, read from SettingsFile
+type settings struct {
+	AppName            string            `json:"appName"`
+	Region             string            `json:"region"`
+	Port               int               `json:"port"`
+	InstanceType       string            `json:"instanceType"`
+	VolumeSize         int               `json:"volumeSize"`
+	AMI                string            `json:"ami"`
+	UserData           string            `json:"userData"`
+	VPCID              string            `json:"vpcId"`
+	SubnetIDs          []string          `json:"subnetIds"`
+	SSHKeyName         string            `json:"sshKeyName"`
+	SSHPublicKey       string            `json:"sshPublicKey"`
+	SSHEnabled         bool              `json:"sshEnabled"`
+	MaxSize            int               `json:"maxSize"`
+	AutoscaleTargetCPU int               `json:"autoscaleTargetCpu"`
+	Tags               map[string]string `json:"tags"`
+	RoleARN            string            `json:"roleArn"`
+	RoleSessionName    string            `json:"roleSessionName"`
+}
+
+// Generate writes the Pulumi project (Pulumi.yaml, package.json, tsconfig.json, index.ts and
+// SettingsFile) of config
+func (g *Generator) Generate(config *types.TerraformConfig) error {
+	if err := Supported(config); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(g.outputDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(vmSettings(config), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal Pulumi settings: %w", err)
+	}
+
+	files := map[string]string{
+		"Pulumi.yaml":   fmt.Sprintf(projectTemplate, ProjectName(config.AppName), config.AppName),
+		"package.json":  packageJSON,
+		"tsconfig.json": tsconfigJSON,
+		"index.ts":      vmProgram,
+		SettingsFile:    string(data) + "\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(g.outputDir, name), []byte(content), 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+
+	if g.verbose {
+		fmt.Fprintf(console.Stdout, "   Generated Pulumi program in %s\n", g.outputDir)
+	}
+	return nil
+}
+
+// Supported returns an error when config uses features the Pulumi programs do not cover yet
+func Supported(config *types.TerraformConfig) error {
+	if config.Strategy != "vm" {
+		return fmt.Errorf("the pulumi engine only supports the vm strategy, not %s: set iac.engine to terraform", config.Strategy)
+	}
+
+	var unsupported []string
+	if config.DatabaseEngine != "" {
+		unsupported = append(unsupported, "--with-database")
+	}
+	if config.Domain != "" {
+		unsupported = append(unsupported, "--domain")
+	}
+	if len(unsupported) > 0 {
+		return fmt.Errorf("the pulumi engine does not support %s yet: set iac.engine to terraform", strings.Join(unsupported, " and "))
+	}
+	return nil
+}
+
+// vmSettings maps config to the settings of the vm program
+func vmSettings(config *types.TerraformConfig) settings {
+	subnetIDs := config.SubnetIDs
+	if subnetIDs == nil {
+		subnetIDs = []string{}
+	}
+
+	return settings{
+		AppName:            config.AppName,
+		Region:             config.Region,
+		Port:               config.Port,
+		InstanceType:       config.InstanceType,
+		VolumeSize:         config.VolumeSize,
+		AMI:                config.AMI,
+		UserData:           terraform.UserDataScript(config),
+		VPCID:              config.VPCID,
+		SubnetIDs:          subnetIDs,
+		SSHKeyName:         config.SSHKeyName,
+		SSHPublicKey:       config.SSHPublicKey,
+		SSHEnabled:         terraform.SSHEnabled(config),
+		MaxSize:            terraform.ASGMaxSize(config),
+		AutoscaleTargetCPU: config.AutoscaleTargetCPU,
+		Tags:               terraform.DefaultTags(config),
+		RoleARN:            config.AWSRoleARN,
+		RoleSessionName:    cloud.RoleSessionName,
+	}
+}
+
+// invalidProjectChars matches the characters not allowed in a Pulumi project name
+var invalidProjectChars = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// ProjectName returns the Pulumi project name of an app
+func ProjectName(appName string) string {
+	name := strings.Trim(invalidProjectChars.ReplaceAllString(appName, "-"), "-.")
+	if name == "" {
+		return "scai-app"
+	}
+	return name
+}
@@ -0,0 +1,74 @@
+package pulumi
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Smana/scai/internal/types"
+)
+
+func TestGenerateVM(t *testing.T) {
+	dir := t.TempDir()
+	config := &types.TerraformConfig{
+		Strategy:     "vm",
+		AppName:      "flask app",
+		Region:       "eu-west-3",
+		Port:         5000,
+		InstanceType: "t3.small",
+		VolumeSize:   20,
+		VPCID:        "vpc-0123456789abcdef0",
+		SSHKeyName:   "ops",
+		DeploymentID: "abc",
+		Tags:         map[string]string{"team": "web"},
+	}
+
+	if err := NewGenerator(dir, false).Generate(config); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	for _, name := range []string{"Pulumi.yaml", "package.json", "tsconfig.json", "index.ts", SettingsFile} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("%s not generated: %v", name, err)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, SettingsFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got settings
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("invalid %s: %v", SettingsFile, err)
+	}
+	if got.Port != 5000 || got.VPCID != "vpc-0123456789abcdef0" || !got.SSHEnabled || got.MaxSize != 1 {
+		t.Errorf("settings = %+v", got)
+	}
+	if got.Tags["scia:deployment-id"] != "abc" || got.Tags["team"] != "web" {
+		t.Errorf("settings tags = %v", got.Tags)
+	}
+	if got.UserData == "" {
+		t.Error("settings have no user-data script")
+	}
+	if name := ProjectName(config.AppName); name != "flask-app" {
+		t.Errorf("ProjectName() = %q, want flask-app", name)
+	}
+}
+
+func TestSupported(t *testing.T) {
+	tests := []struct {
+		config  types.TerraformConfig
+		wantErr bool
+	}{
+		{types.TerraformConfig{Strategy: "vm"}, false},
+		{types.TerraformConfig{Strategy: "kubernetes"}, true},
+		{types.TerraformConfig{Strategy: "vm", DatabaseEngine: "postgres"}, true},
+		{types.TerraformConfig{Strategy: "vm", Domain: "app.example.com"}, true},
+	}
+
+	for _, tt := range tests {
+		if err := Supported(&tt.config); (err != nil) != tt.wantErr {
+			t.Errorf("Supported(%+v) error = %v, wantErr %v", tt.config, err, tt.wantErr)
+		}
+	}
+}
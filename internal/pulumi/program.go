@@ -0,0 +1,194 @@
+package pulumi
+
+// projectTemplate is Pulumi.yaml: project name and description
+const projectTemplate = `name: %s
+description: Deployment of %s, generated by SCAI
+runtime:
+  name: nodejs
+  options:
+    typescript: true
+`
+
+// packageJSON declares the Pulumi SDK and the AWS provider (based on the Terraform AWS
+// provider 6, as the Terraform configurations)
+const packageJSON = `{
+  "name": "scai-deployment",
+  "private": true,
+  "main": "index.ts",
+  "devDependencies": {
+    "@types/node": "^20.0.0",
+    "typescript": "^5.0.0"
+  },
+  "dependencies": {
+    "@pulumi/aws": "^7.0.0",
+    "@pulumi/pulumi": "^3.0.0"
+  }
+}
+`
+
+const tsconfigJSON = `{
+  "compilerOptions": {
+    "strict": true,
+    "outDir": "bin",
+    "target": "es2020",
+    "module": "commonjs",
+    "moduleResolution": "node",
+    "sourceMap": true,
+    "experimentalDecorators": true,
+    "pretty": true,
+    "noFallthroughCasesInSwitch": true,
+    "noImplicitReturns": true,
+    "forceConsistentCasingInFileNames": true
+  },
+  "files": ["index.ts"]
+}
+`
+
+// vmProgram is the program of the vm strategy, the counterpart of the Terraform EC2
+// configuration: a single auto-recovered instance in an Auto Scaling Group, with the same
+// outputs (asg_name and application_port give the application URL once the instance is up)
+const vmProgram = `// EC2 Deployment using an Auto Scaling Group
+// Generated by SCAI: the deployment settings are read from settings.json
+
+import * as fs from "fs";
+import * as aws from "@pulumi/aws";
+import * as pulumi from "@pulumi/pulumi";
+
+interface Settings {
+  appName: string;
+  region: string;
+  port: number;
+  instanceType: string;
+  volumeSize: number;
+  ami: string;
+  userData: string;
+  vpcId: string;
+  subnetIds: string[];
+  sshKeyName: string;
+  sshPublicKey: string;
+  sshEnabled: boolean;
+  maxSize: number;
+  autoscaleTargetCpu: number;
+  tags: Record<string, string>;
+  roleArn: string;
+  roleSessionName: string;
+}
+
+const settings: Settings = JSON.parse(fs.readFileSync("settings.json", "utf8"));
+const name = settings.appName;
+
+// Provider with default tags, propagated to every taggable resource, assuming the role of the
+// deploy (--aws-role-arn) if any
+const provider = new aws.Provider("aws", {
+  region: settings.region as aws.Region,
+  defaultTags: { tags: settings.tags },
+  assumeRoles: settings.roleArn !== ""
+    ? [{ roleArn: settings.roleArn, sessionName: settings.roleSessionName }]
+    : undefined,
+});
+const opts = { provider };
+
+// Default VPC, or existing VPC and subnets (--vpc-id)
+const vpcId: pulumi.Input<string> = settings.vpcId !== ""
+  ? settings.vpcId
+  : aws.ec2.getVpcOutput({ default: true }, opts).id;
+const subnetIds: pulumi.Input<string[]> = settings.subnetIds.length > 0
+  ? settings.subnetIds
+  : aws.ec2.getSubnetsOutput({ filters: [{ name: "vpc-id", values: [vpcId] }] }, opts).ids;
+
+// Latest Amazon Linux 2023 or custom AMI
+const image = settings.ami !== ""
+  ? aws.ec2.getAmiOutput({ filters: [{ name: "image-id", values: [settings.ami] }] }, opts)
+  : aws.ec2.getAmiOutput({
+    mostRecent: true,
+    owners: ["amazon"],
+    filters: [
+      { name: "name", values: ["al2023-ami-*-x86_64"] },
+      { name: "virtualization-type", values: ["hvm"] },
+    ],
+  }, opts);
+const rootDevice = settings.ami !== "" ? image.rootDeviceName : "/dev/xvda";
+
+// Security group: application port, and SSH with a key pair only (SSM Session Manager otherwise)
+const ingress: aws.types.input.ec2.SecurityGroupIngress[] = [
+  { protocol: "tcp", fromPort: settings.port, toPort: settings.port, cidrBlocks: ["0.0.0.0/0"], description: "Application port" },
+];
+if (settings.sshEnabled) {
+  ingress.push({ protocol: "tcp", fromPort: 22, toPort: 22, cidrBlocks: ["0.0.0.0/0"], description: "SSH access" });
+}
+const securityGroup = new aws.ec2.SecurityGroup(` + "`${name}-sg`" + `, {
+  description: ` + "`Security group for ${name}`" + `,
+  vpcId: vpcId,
+  ingress: ingress,
+  egress: [{ protocol: "-1", fromPort: 0, toPort: 0, cidrBlocks: ["0.0.0.0/0"], description: "Allow all outbound" }],
+  tags: { Name: ` + "`${name}-sg`" + ` },
+}, opts);
+
+// IAM role for SSM access
+const role = new aws.iam.Role(` + "`${name}-ssm-role`" + `, {
+  assumeRolePolicy: JSON.stringify({
+    Version: "2012-10-17",
+    Statement: [{ Action: "sts:AssumeRole", Effect: "Allow", Principal: { Service: "ec2.amazonaws.com" } }],
+  }),
+  tags: { Name: ` + "`${name}-ssm-role`" + ` },
+}, opts);
+new aws.iam.RolePolicyAttachment(` + "`${name}-ssm-policy`" + `, {
+  role: role.name,
+  policyArn: "arn:aws:iam::aws:policy/AmazonSSMManagedInstanceCore",
+}, opts);
+const instanceProfile = new aws.iam.InstanceProfile(` + "`${name}-ssm-profile`" + `, {
+  role: role.name,
+  tags: { Name: ` + "`${name}-ssm-profile`" + ` },
+}, opts);
+
+// Key pair for SSH access (private key saved locally by SCAI), or an existing key pair
+let keyName: pulumi.Input<string> | undefined = settings.sshKeyName !== "" ? settings.sshKeyName : undefined;
+if (settings.sshPublicKey !== "") {
+  keyName = new aws.ec2.KeyPair(` + "`${name}-ssh`" + `, { publicKey: settings.sshPublicKey }, opts).keyName;
+}
+
+const launchTemplate = new aws.ec2.LaunchTemplate(` + "`${name}-lt`" + `, {
+  imageId: image.id,
+  instanceType: settings.instanceType,
+  keyName: keyName,
+  iamInstanceProfile: { arn: instanceProfile.arn },
+  vpcSecurityGroupIds: [securityGroup.id],
+  userData: Buffer.from(settings.userData).toString("base64"),
+  blockDeviceMappings: [{
+    deviceName: rootDevice,
+    ebs: { volumeSize: settings.volumeSize, volumeType: "gp3", deleteOnTermination: "true", encrypted: "true" },
+  }],
+  monitoring: { enabled: true },
+  metadataOptions: { httpEndpoint: "enabled", httpTokens: "required", httpPutResponseHopLimit: 1 },
+  tagSpecifications: [{ resourceType: "instance", tags: { Name: name, Environment: "production" } }],
+}, opts);
+
+// Auto Scaling Group: a single instance with auto-recovery (maxSize > 1 with an autoscaling policy)
+const asg = new aws.autoscaling.Group(` + "`${name}-asg`" + `, {
+  minSize: 1,
+  maxSize: settings.maxSize,
+  desiredCapacity: 1,
+  vpcZoneIdentifiers: subnetIds,
+  healthCheckType: "EC2",
+  healthCheckGracePeriod: 300,
+  launchTemplate: { id: launchTemplate.id, version: "$Latest" },
+}, opts);
+
+// Target tracking scaling policy: keep the average CPU utilization around the target
+if (settings.autoscaleTargetCpu > 0) {
+  new aws.autoscaling.Policy(` + "`${name}-cpu-target`" + `, {
+    autoscalingGroupName: asg.name,
+    policyType: "TargetTrackingScaling",
+    targetTrackingConfiguration: {
+      predefinedMetricSpecification: { predefinedMetricType: "ASGAverageCPUUtilization" },
+      targetValue: settings.autoscaleTargetCpu,
+    },
+  }, opts);
+}
+
+export const asg_name = asg.name;
+export const asg_id = asg.id;
+export const security_group_id = securityGroup.id;
+export const vpc_id = vpcId;
+export const application_port = String(settings.port);
+`
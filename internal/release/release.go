@@ -0,0 +1,180 @@
+// Package release checks the GitHub releases of scai for a version newer than the running one
+package release
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Smana/scai/internal/network"
+)
+
+// CacheTTL is how long the latest release is cached: unauthenticated GitHub API requests are
+// limited to 60 per hour
+const CacheTTL = time.Hour
+
+// latestReleaseURL is the GitHub API endpoint of the latest (non pre-release) release
+var latestReleaseURL = "https://api.github.com/repos/Smana/scai/releases/latest"
+
+// Release is a published release
+type Release struct {
+	Version   string    `json:"tag_name"` // Tag, e.g. v0.5.1
+	URL       string    `json:"html_url"` // Release page
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// cachePath returns the path of the cached latest release, ~/.scai/release.json
+func cachePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".scai", "release.json"), nil
+}
+
+// Latest returns the latest release, from the cache when it was checked less than CacheTTL ago
+func Latest(ctx context.Context) (*Release, error) {
+	if cached, ok := loadCache(); ok {
+		return cached, nil
+	}
+
+	latest, err := fetchLatest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// The cache only saves API requests: failing to write it is not an error
+	_ = saveCache(latest)
+	return latest, nil
+}
+
+// fetchLatest queries the GitHub API for the latest release
+func fetchLatest(ctx context.Context) (*Release, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, latestReleaseURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := network.NewClient(10 * time.Second).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the latest release: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusForbidden, http.StatusTooManyRequests:
+		return nil, fmt.Errorf("failed to get the latest release: GitHub API rate limit exceeded, retry later")
+	default:
+		return nil, fmt.Errorf("failed to get the latest release: %s", resp.Status)
+	}
+
+	var latest Release
+	if err := json.NewDecoder(resp.Body).Decode(&latest); err != nil {
+		return nil, fmt.Errorf("failed to decode the latest release: %w", err)
+	}
+	if latest.Version == "" {
+		return nil, fmt.Errorf("failed to get the latest release: no tag in the GitHub response")
+	}
+	latest.CheckedAt = time.Now()
+	return &latest, nil
+}
+
+// loadCache returns the cached latest release if it has not expired
+func loadCache() (*Release, bool) {
+	path, err := cachePath()
+	if err != nil {
+		return nil, false
+	}
+
+	data, err := os.ReadFile(path) // #nosec G304 - path is built from the user's home directory
+	if err != nil {
+		return nil, false
+	}
+
+	var cached Release
+	if err := json.Unmarshal(data, &cached); err != nil {
+		return nil, false
+	}
+	if cached.Version == "" || time.Since(cached.CheckedAt) > CacheTTL {
+		return nil, false
+	}
+	return &cached, true
+}
+
+// saveCache writes the latest release to disk
+func saveCache(latest *Release) error {
+	path, err := cachePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(latest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal release cache: %w", err)
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// Newer reports whether version latest is newer than current (semantic versions, with or
+// without the v prefix). A current version that is not a release (dev builds) cannot be
+// compared and returns an error.
+func Newer(current, latest string) (bool, error) {
+	currentVersion, err := parseVersion(current)
+	if err != nil {
+		return false, err
+	}
+	latestVersion, err := parseVersion(latest)
+	if err != nil {
+		return false, err
+	}
+
+	for i := range currentVersion.numbers {
+		if currentVersion.numbers[i] != latestVersion.numbers[i] {
+			return latestVersion.numbers[i] > currentVersion.numbers[i], nil
+		}
+	}
+	// 1.2.0-rc.1 < 1.2.0
+	if currentVersion.preRelease != "" && latestVersion.preRelease == "" {
+		return true, nil
+	}
+	if currentVersion.preRelease != "" && latestVersion.preRelease != "" {
+		return latestVersion.preRelease > currentVersion.preRelease, nil
+	}
+	return false, nil
+}
+
+// semanticVersion is a parsed MAJOR.MINOR.PATCH[-PRERELEASE] version
+type semanticVersion struct {
+	numbers    [3]int
+	preRelease string
+}
+
+// parseVersion parses a semantic version (v0.5.1, 1.2.0-rc.1, 1.2.0+build)
+func parseVersion(version string) (semanticVersion, error) {
+	var parsed semanticVersion
+
+	core, _, _ := strings.Cut(strings.TrimPrefix(strings.TrimSpace(version), "v"), "+")
+	core, parsed.preRelease, _ = strings.Cut(core, "-")
+	parts := strings.Split(core, ".")
+	if len(parts) != 3 {
+		return parsed, fmt.Errorf("%q is not a semantic version", version)
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return parsed, fmt.Errorf("%q is not a semantic version", version)
+		}
+		parsed.numbers[i] = n
+	}
+	return parsed, nil
+}
@@ -0,0 +1,64 @@
+package release
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewer(t *testing.T) {
+	tests := []struct {
+		current string
+		latest  string
+		want    bool
+	}{
+		{"0.5.1", "v0.5.2", true},
+		{"v0.5.1", "v0.5.1", false},
+		{"0.10.0", "v0.9.9", false},
+		{"1.2.0-rc.1", "v1.2.0", true},
+		{"1.2.0", "v1.3.0-rc.1", true},
+		{"1.3.0", "v1.3.0-rc.1", false},
+	}
+
+	for _, tt := range tests {
+		got, err := Newer(tt.current, tt.latest)
+		if err != nil {
+			t.Fatalf("Newer(%q, %q) error = %v", tt.current, tt.latest, err)
+		}
+		if got != tt.want {
+			t.Errorf("Newer(%q, %q) = %v, want %v", tt.current, tt.latest, got, tt.want)
+		}
+	}
+
+	if _, err := Newer("dev", "v0.5.1"); err == nil {
+		t.Error("Newer() compared a dev build")
+	}
+}
+
+func TestLatestCached(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, `{"tag_name": "v0.6.0", "html_url": "https://github.com/Smana/scai/releases/tag/v0.6.0"}`)
+	}))
+	defer server.Close()
+	saved := latestReleaseURL
+	latestReleaseURL = server.URL
+	t.Cleanup(func() { latestReleaseURL = saved })
+
+	for range 2 {
+		latest, err := Latest(context.Background())
+		if err != nil {
+			t.Fatalf("Latest() error = %v", err)
+		}
+		if latest.Version != "v0.6.0" || latest.URL != "https://github.com/Smana/scai/releases/tag/v0.6.0" {
+			t.Errorf("Latest() = %+v", latest)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("GitHub API requests = %d, want 1 (second lookup from the cache)", requests)
+	}
+}
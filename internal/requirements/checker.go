@@ -3,6 +3,7 @@ package requirements
 import (
 	"fmt"
 	"os/exec"
+	"path/filepath"
 	"strings"
 )
 
@@ -21,12 +22,17 @@ type Requirement struct {
 	Description string // What it's used for
 }
 
-// CheckRequirements checks all system requirements
-func CheckRequirements(llmProvider string, useDocker bool) ([]Requirement, error) {
+// CheckRequirements checks all system requirements; tfBin is the configured terraform.bin
+// (a binary name or an absolute path, e.g. a tofu installed to InstallDir)
+func CheckRequirements(llmProvider string, useDocker bool, tfBin string) ([]Requirement, error) {
+	if tfBin == "" {
+		tfBin = "tofu"
+	}
+
 	requirements := []Requirement{
 		{
 			Name:        "OpenTofu/Terraform",
-			Binary:      "tofu", // Check tofu first, can fallback to terraform
+			Binary:      tfBin, // Check the configured binary first, can fallback to tofu or terraform
 			Required:    true,
 			Description: "Infrastructure as Code provisioning",
 		},
@@ -65,13 +71,17 @@ func CheckRequirements(llmProvider string, useDocker bool) ([]Requirement, error
 		requirements[i].Version = version
 
 		// For terraform/tofu, try fallback
-		if requirements[i].Binary == "tofu" && !installed {
-			// Try terraform as fallback
-			installed, version = checkBinary("terraform")
-			if installed {
-				requirements[i].Binary = "terraform"
-				requirements[i].Installed = true
-				requirements[i].Version = version
+		if requirements[i].Binary == tfBin && !installed {
+			for _, fallback := range []string{"tofu", "terraform"} {
+				if fallback == tfBin {
+					continue
+				}
+				if installed, version = checkBinary(fallback); installed {
+					requirements[i].Binary = fallback
+					requirements[i].Installed = true
+					requirements[i].Version = version
+					break
+				}
 			}
 		}
 	}
@@ -105,6 +115,7 @@ func getVersion(binaryName string) string {
 
 	for _, flag := range versionFlags {
 		// #nosec G204 -- binaryName is from a controlled list of system binaries (tofu, aws, docker, ollama)
+		// or the configured terraform.bin
 		cmd := exec.Command(binaryName, flag)
 		output, err := cmd.CombinedOutput()
 		if err == nil && len(output) > 0 {
@@ -113,7 +124,7 @@ func getVersion(binaryName string) string {
 			if len(lines) > 0 {
 				version := strings.TrimSpace(lines[0])
 				// Clean up common prefixes
-				version = strings.TrimPrefix(version, binaryName+" ")
+				version = strings.TrimPrefix(version, filepath.Base(binaryName)+" ")
 				version = strings.TrimPrefix(version, "version ")
 				version = strings.TrimPrefix(version, "Version ")
 				version = strings.TrimPrefix(version, "v")
@@ -0,0 +1,169 @@
+package requirements
+
+import (
+	"archive/zip"
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/Smana/scai/internal/network"
+)
+
+// OpenTofuVersion is the OpenTofu release installed by InstallOpenTofu
+const OpenTofuVersion = "1.10.5"
+
+// openTofuReleaseURL is the download URL of an OpenTofu release (version without the "v")
+const openTofuReleaseURL = "https://github.com/opentofu/opentofu/releases/download/v%s/%s"
+
+// downloadTimeout bounds the download of a release file
+const downloadTimeout = 5 * time.Minute
+
+// InstallDir returns the directory binaries are installed to: ~/.scai/bin
+func InstallDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".scai", "bin"), nil
+}
+
+// InstallOpenTofu downloads the OpenTofu release for the current OS and architecture,
+// verifies it against the release SHA256SUMS file and installs the tofu binary to destDir.
+// It returns the absolute path of the installed binary.
+func InstallOpenTofu(ctx context.Context, destDir string) (string, error) {
+	archive := fmt.Sprintf("tofu_%s_%s_%s.zip", OpenTofuVersion, runtime.GOOS, runtime.GOARCH)
+	sums := fmt.Sprintf("tofu_%s_SHA256SUMS", OpenTofuVersion)
+
+	expected, err := releaseChecksum(ctx, sums, archive)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(destDir, 0o750); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", destDir, err)
+	}
+
+	zipFile, err := os.CreateTemp(destDir, "tofu-*.zip")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer func() {
+		_ = zipFile.Close()
+		_ = os.Remove(zipFile.Name())
+	}()
+
+	hash := sha256.New()
+	if err := download(ctx, archive, io.MultiWriter(zipFile, hash)); err != nil {
+		return "", err
+	}
+	if actual := hex.EncodeToString(hash.Sum(nil)); actual != expected {
+		return "", fmt.Errorf("checksum mismatch for %s: expected %s, got %s", archive, expected, actual)
+	}
+
+	binary := "tofu"
+	if runtime.GOOS == "windows" {
+		binary += ".exe"
+	}
+	binPath, err := filepath.Abs(filepath.Join(destDir, binary))
+	if err != nil {
+		return "", err
+	}
+
+	if err := extractBinary(zipFile.Name(), binary, binPath); err != nil {
+		return "", fmt.Errorf("failed to extract %s from %s: %w", binary, archive, err)
+	}
+	return binPath, nil
+}
+
+// releaseChecksum returns the SHA-256 of archive listed in the sums file of the release
+func releaseChecksum(ctx context.Context, sums, archive string) (string, error) {
+	var content strings.Builder
+	if err := download(ctx, sums, &content); err != nil {
+		return "", err
+	}
+
+	// Lines are "<sha256>  <file name>"
+	scanner := bufio.NewScanner(strings.NewReader(content.String()))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[1] == archive {
+			return fields[0], nil
+		}
+	}
+	return "", fmt.Errorf("OpenTofu %s is not available for %s/%s", OpenTofuVersion, runtime.GOOS, runtime.GOARCH)
+}
+
+// download writes a file of the OpenTofu release to w
+func download(ctx context.Context, name string, w io.Writer) error {
+	ctx, cancel := context.WithTimeout(ctx, downloadTimeout)
+	defer cancel()
+
+	url := fmt.Sprintf(openTofuReleaseURL, OpenTofuVersion, name)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := network.NewClient(0).Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to download %s: %s", url, resp.Status)
+	}
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	return nil
+}
+
+// extractBinary extracts the binary file of a zip archive to dest, replacing it atomically
+func extractBinary(zipPath, binary, dest string) error {
+	reader, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = reader.Close() }()
+
+	for _, file := range reader.File {
+		if file.Name != binary {
+			continue
+		}
+
+		in, err := file.Open()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = in.Close() }()
+
+		tmp := dest + ".tmp"
+		// #nosec G302 G304 -- the binary must be executable; tmp is inside the install directory
+		out, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o755)
+		if err != nil {
+			return err
+		}
+		// #nosec G110 -- the archive checksum was verified against the release SHA256SUMS
+		if _, err := io.Copy(out, in); err != nil {
+			_ = out.Close()
+			_ = os.Remove(tmp)
+			return err
+		}
+		if err := out.Close(); err != nil {
+			_ = os.Remove(tmp)
+			return err
+		}
+		return os.Rename(tmp, dest)
+	}
+	return fmt.Errorf("%s not found in the archive", binary)
+}
@@ -0,0 +1,54 @@
+package requirements
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExtractBinary(t *testing.T) {
+	dir := t.TempDir()
+	zipPath := filepath.Join(dir, "tofu.zip")
+
+	f, err := os.Create(zipPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writer := zip.NewWriter(f)
+	for name, content := range map[string]string{"LICENSE": "MPL-2.0", "tofu": "#!/bin/sh\necho tofu\n"} {
+		w, err := writer.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	dest := filepath.Join(dir, "bin", "tofu")
+	if err := os.MkdirAll(filepath.Dir(dest), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := extractBinary(zipPath, "tofu", dest); err != nil {
+		t.Fatalf("extractBinary() error = %v", err)
+	}
+
+	info, err := os.Stat(dest)
+	if err != nil {
+		t.Fatalf("binary not extracted: %v", err)
+	}
+	if info.Mode().Perm()&0o100 == 0 {
+		t.Errorf("binary is not executable: %v", info.Mode())
+	}
+
+	if err := extractBinary(zipPath, "terraform", dest); err == nil {
+		t.Error("extractBinary() of a missing file succeeded")
+	}
+}
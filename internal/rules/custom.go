@@ -0,0 +1,84 @@
+package rules
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/Smana/scai/internal/types"
+)
+
+// DefaultRulesPath is the built-in rules file, relative to the working directory
+const DefaultRulesPath = "configs/deployment_rules.yaml"
+
+// LoadCustomRules loads a user-authored rules file and fails on any lint error, so a
+// mistake never silently changes deployment decisions. The custom rules replace the
+// default ones, unless merge is set: they are then added to the defaults, replacing
+// the default rules with the same name.
+func LoadCustomRules(path, defaultPath string, merge bool) (*types.DeploymentRules, error) {
+	custom, err := LoadRules(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rules file %s: %w", path, err)
+	}
+
+	var errs []string
+	for _, issue := range Lint(custom) {
+		if issue.Severity != SeverityError {
+			continue
+		}
+		if issue.Rule != "" {
+			errs = append(errs, fmt.Sprintf("rule %s: %s", issue.Rule, issue.Message))
+		} else {
+			errs = append(errs, issue.Message)
+		}
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid rules file %s: %s (run 'scia rules validate %s' for details)", path, strings.Join(errs, "; "), path)
+	}
+
+	if !merge {
+		return custom, nil
+	}
+
+	defaults, err := LoadRules(defaultPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the default rules to merge with: %w", err)
+	}
+	return MergeRules(defaults, custom), nil
+}
+
+// MergeRules returns the default rules extended with the custom ones: custom rules
+// replace the default rules with the same name, and the custom mode, instance types
+// and optimizations take precedence
+func MergeRules(defaults, custom *types.DeploymentRules) *types.DeploymentRules {
+	merged := *defaults
+	if custom.Version != "" {
+		merged.Version = custom.Version
+	}
+	if custom.Mode != "" {
+		merged.Mode = custom.Mode
+	}
+
+	merged.Rules = slices.DeleteFunc(slices.Clone(defaults.Rules), func(rule types.DeploymentRule) bool {
+		return slices.ContainsFunc(custom.Rules, func(c types.DeploymentRule) bool { return c.Name == rule.Name })
+	})
+	merged.Rules = append(merged.Rules, custom.Rules...)
+	slices.SortFunc(merged.Rules, func(a, b types.DeploymentRule) int {
+		return b.Priority - a.Priority
+	})
+
+	merged.InstanceTypes = maps.Clone(defaults.InstanceTypes)
+	if merged.InstanceTypes == nil {
+		merged.InstanceTypes = make(map[string]types.InstanceTypeInfo)
+	}
+	maps.Copy(merged.InstanceTypes, custom.InstanceTypes)
+
+	merged.Optimizations = maps.Clone(defaults.Optimizations)
+	if merged.Optimizations == nil {
+		merged.Optimizations = make(map[string]types.FrameworkOptimization)
+	}
+	maps.Copy(merged.Optimizations, custom.Optimizations)
+
+	return &merged
+}
@@ -72,7 +72,9 @@ func matchesConditions(conditions types.RuleConditions, analysis *types.Analysis
 		matchesLanguage(conditions, analysis) &&
 		matchesDependencies(conditions, analysis) &&
 		matchesDockerfile(conditions, analysis) &&
-		matchesDockerCompose(conditions, analysis)
+		matchesDockerCompose(conditions, analysis) &&
+		matchesServices(conditions, analysis) &&
+		matchesRequiresDatabase(conditions, analysis)
 }
 
 // matchesFramework checks if framework condition matches
@@ -126,3 +128,19 @@ func matchesDockerCompose(conditions types.RuleConditions, analysis *types.Analy
 	}
 	return *conditions.HasDockerCompose == analysis.HasDockerCompose
 }
+
+// matchesServices checks if docker-compose service count condition matches
+func matchesServices(conditions types.RuleConditions, analysis *types.Analysis) bool {
+	if conditions.MinServices == 0 {
+		return true
+	}
+	return len(analysis.ComposeServices) >= conditions.MinServices
+}
+
+// matchesRequiresDatabase checks if database requirement condition matches
+func matchesRequiresDatabase(conditions types.RuleConditions, analysis *types.Analysis) bool {
+	if conditions.RequiresDatabase == nil {
+		return true
+	}
+	return *conditions.RequiresDatabase == analysis.RequiresDatabase
+}
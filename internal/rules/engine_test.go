@@ -339,3 +339,236 @@ func TestMatchesConditionsLanguage(t *testing.T) {
 		t.Error("Expected language condition NOT to match")
 	}
 }
+
+func scoringTestRules() *types.DeploymentRules {
+	return &types.DeploymentRules{
+		Rules: []types.DeploymentRule{
+			{
+				Name:           "broad_python",
+				Priority:       90,
+				Conditions:     types.RuleConditions{Language: "python"},
+				Recommendation: "vm",
+			},
+			{
+				Name:     "specific_fastapi",
+				Priority: 50,
+				Conditions: types.RuleConditions{
+					Framework:       []string{"fastapi"},
+					Language:        "python",
+					MaxDependencies: 5,
+				},
+				Recommendation: "serverless",
+			},
+		},
+	}
+}
+
+func TestScoreRulesPrefersSpecificRule(t *testing.T) {
+	analysis := &types.Analysis{
+		Framework:    "fastapi",
+		Language:     "python",
+		Dependencies: []string{"fastapi", "uvicorn"},
+	}
+
+	matches := ScoreRules(scoringTestRules(), analysis)
+	if len(matches) != 2 {
+		t.Fatalf("Expected 2 scored matches, got %d", len(matches))
+	}
+
+	if matches[0].RuleName != "specific_fastapi" {
+		t.Errorf("Expected 'specific_fastapi' to score highest, got %s", matches[0].RuleName)
+	}
+
+	if matches[0].MatchedConditions != 3 {
+		t.Errorf("Expected 3 matched conditions, got %d", matches[0].MatchedConditions)
+	}
+
+	if matches[0].Score <= matches[1].Score {
+		t.Errorf("Expected specific rule score %.2f > broad rule score %.2f", matches[0].Score, matches[1].Score)
+	}
+}
+
+func TestEvaluateModes(t *testing.T) {
+	analysis := &types.Analysis{
+		Framework:    "fastapi",
+		Language:     "python",
+		Dependencies: []string{"fastapi"},
+	}
+
+	// Default mode keeps first-match by priority
+	rules := scoringTestRules()
+	match, matched := Evaluate(rules, analysis)
+	if !matched || match.RuleName != "broad_python" {
+		t.Errorf("Expected first-match to pick 'broad_python', got %+v", match)
+	}
+
+	// Scoring mode picks the best-fitting rule
+	rules.Mode = ModeScoring
+	match, matched = Evaluate(rules, analysis)
+	if !matched || match.RuleName != "specific_fastapi" {
+		t.Errorf("Expected scoring to pick 'specific_fastapi', got %+v", match)
+	}
+}
+
+func TestScoreRulesTieBrokenByPriority(t *testing.T) {
+	rules := &types.DeploymentRules{
+		Rules: []types.DeploymentRule{
+			{Name: "low", Priority: 10, Conditions: types.RuleConditions{Language: "go"}, Recommendation: "vm"},
+			{Name: "high", Priority: 20, Conditions: types.RuleConditions{Language: "go"}, Recommendation: "kubernetes"},
+		},
+	}
+
+	matches := ScoreRules(rules, &types.Analysis{Language: "go"})
+	if len(matches) != 2 || matches[0].RuleName != "high" {
+		t.Errorf("Expected tie to be broken by priority, got %+v", matches)
+	}
+}
+
+func TestEvaluateRulesRequiresDatabase(t *testing.T) {
+	requiresDatabase := false
+	rules := &types.DeploymentRules{
+		Rules: []types.DeploymentRule{
+			{
+				Name:     "simple_stateless_api",
+				Priority: 80,
+				Conditions: types.RuleConditions{
+					Framework:        []string{"fastapi"},
+					RequiresDatabase: &requiresDatabase,
+				},
+				Recommendation: "serverless",
+			},
+		},
+	}
+
+	analysis := &types.Analysis{Framework: "fastapi", RequiresDatabase: true}
+	if _, matched := EvaluateRules(rules, analysis); matched {
+		t.Error("Expected stateless rule NOT to match an app that requires a database")
+	}
+
+	analysis.RequiresDatabase = false
+	if _, matched := EvaluateRules(rules, analysis); !matched {
+		t.Error("Expected stateless rule to match an app without a database")
+	}
+}
+
+func TestLoadRulesConditions(t *testing.T) {
+	rules, err := LoadRules("../../configs/deployment_rules.yaml")
+	if err != nil {
+		t.Fatalf("LoadRules failed: %v", err)
+	}
+
+	// Snake-case YAML keys must decode into rule conditions
+	top := rules.Rules[0]
+	if top.Conditions.HasDockerCompose == nil || !*top.Conditions.HasDockerCompose {
+		t.Errorf("Expected rule %s to require docker-compose, got %+v", top.Name, top.Conditions)
+	}
+
+	// A plain Flask app must not hit the docker-compose rule
+	match, matched := EvaluateRules(rules, &types.Analysis{Framework: "flask", Language: "python", Dependencies: []string{"flask"}})
+	if !matched || match.RuleName != "simple_web_app" {
+		t.Errorf("Expected 'simple_web_app' for a plain Flask app, got %+v", match)
+	}
+}
+
+func TestLint(t *testing.T) {
+	yes, no := true, false
+	rules := &types.DeploymentRules{Rules: []types.DeploymentRule{
+		{Name: "any_python", Priority: 50, Recommendation: "vm", Conditions: types.RuleConditions{Language: "python"}},
+		{Name: "compose", Priority: 50, Recommendation: "kubernetes", Conditions: types.RuleConditions{HasDockerCompose: &yes, MinServices: 2}},
+		{Name: "django", Priority: 40, Recommendation: "vm", Conditions: types.RuleConditions{Language: "python", Framework: []string{"django"}}},
+		{Name: "django", Priority: 30, Recommendation: "container", Conditions: types.RuleConditions{MinDependencies: 10, MaxDependencies: 5}},
+		{Name: "no_compose", Priority: 20, Recommendation: "vm", Conditions: types.RuleConditions{HasDockerCompose: &no, MinServices: 2}},
+	}}
+
+	want := map[string][]string{
+		"compose":    {SeverityWarning}, // same priority as any_python
+		"django":     {SeverityWarning, SeverityError, SeverityError, SeverityError},
+		"no_compose": {SeverityError},
+	}
+
+	got := make(map[string][]string)
+	for _, issue := range Lint(rules) {
+		got[issue.Rule] = append(got[issue.Rule], issue.Severity)
+	}
+	for name, severities := range want {
+		if len(got[name]) != len(severities) {
+			t.Errorf("rule %s: got issues %v, want %v", name, got[name], severities)
+		}
+	}
+	if issues := got["any_python"]; len(issues) != 0 {
+		t.Errorf("rule any_python: got issues %v, want none", issues)
+	}
+}
+
+func TestLintBundledRules(t *testing.T) {
+	rules, err := LoadRules("../../configs/deployment_rules.yaml")
+	if err != nil {
+		t.Fatalf("LoadRules failed: %v", err)
+	}
+	if issues := Lint(rules); len(issues) != 0 {
+		t.Errorf("bundled rules have issues: %+v", issues)
+	}
+}
+
+func TestLoadCustomRules(t *testing.T) {
+	tmpDir := t.TempDir()
+	defaultsFile := filepath.Join(tmpDir, "defaults.yaml")
+	customFile := filepath.Join(tmpDir, "custom.yaml")
+	invalidFile := filepath.Join(tmpDir, "invalid.yaml")
+
+	files := map[string]string{
+		defaultsFile: `rules:
+  - name: compose
+    priority: 100
+    conditions:
+      has_docker_compose: true
+    recommendation: kubernetes
+  - name: python
+    priority: 10
+    conditions:
+      language: python
+    recommendation: vm
+`,
+		customFile: `mode: scoring
+rules:
+  - name: python
+    priority: 20
+    conditions:
+      language: python
+    recommendation: serverless
+`,
+		invalidFile: `rules:
+  - name: python
+    priority: 20
+    recommendation: container
+`,
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0o640); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+
+	replaced, err := LoadCustomRules(customFile, defaultsFile, false)
+	if err != nil {
+		t.Fatalf("LoadCustomRules failed: %v", err)
+	}
+	if len(replaced.Rules) != 1 || replaced.Rules[0].Recommendation != "serverless" {
+		t.Errorf("Expected the custom rules only, got %+v", replaced.Rules)
+	}
+
+	merged, err := LoadCustomRules(customFile, defaultsFile, true)
+	if err != nil {
+		t.Fatalf("LoadCustomRules with merge failed: %v", err)
+	}
+	if len(merged.Rules) != 2 || merged.Rules[0].Name != "compose" || merged.Rules[1].Recommendation != "serverless" {
+		t.Errorf("Expected compose and the custom python rule, got %+v", merged.Rules)
+	}
+	if merged.Mode != ModeScoring {
+		t.Errorf("Expected the custom mode %s, got %q", ModeScoring, merged.Mode)
+	}
+
+	if _, err := LoadCustomRules(invalidFile, defaultsFile, false); err == nil {
+		t.Error("Expected an error for an unknown recommendation")
+	}
+}
@@ -0,0 +1,149 @@
+package rules
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/Smana/scai/internal/types"
+)
+
+// Issue severities
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// recommendations are the deployment strategies a rule can recommend
+var recommendations = []string{"vm", "kubernetes", "serverless"}
+
+// Issue is a problem found in a rules file
+type Issue struct {
+	Severity string
+	Rule     string // Rule name, empty for file-level issues
+	Message  string
+}
+
+// Lint checks rules for the misconfigurations LoadRules accepts silently: unknown
+// recommendations, duplicate names, conflicting priorities and conditions that can never match.
+// Rules are expected in priority order, as returned by LoadRules.
+func Lint(rules *types.DeploymentRules) []Issue {
+	var issues []Issue
+
+	if rules.Mode != "" && rules.Mode != ModeFirstMatch && rules.Mode != ModeScoring {
+		issues = append(issues, Issue{Severity: SeverityError, Message: fmt.Sprintf("unknown mode %q (expected %s or %s)", rules.Mode, ModeFirstMatch, ModeScoring)})
+	}
+	if len(rules.Rules) == 0 {
+		issues = append(issues, Issue{Severity: SeverityWarning, Message: "no rules: every strategy decision falls back to the LLM"})
+	}
+
+	seen := make(map[string]bool)
+	for i := range rules.Rules {
+		rule := &rules.Rules[i]
+
+		if rule.Name == "" {
+			issues = append(issues, Issue{Severity: SeverityError, Rule: fmt.Sprintf("#%d", i+1), Message: "missing name"})
+		} else if seen[rule.Name] {
+			issues = append(issues, Issue{Severity: SeverityError, Rule: rule.Name, Message: "duplicate name"})
+		}
+		seen[rule.Name] = true
+
+		issues = append(issues, lintRule(rule)...)
+	}
+
+	// In first-match mode, the order of the rules decides: flag ambiguous and unreachable rules
+	if rules.Mode != ModeScoring {
+		issues = append(issues, lintOrder(rules.Rules)...)
+	}
+
+	return issues
+}
+
+// lintRule checks the recommendation and the conditions of a single rule
+func lintRule(rule *types.DeploymentRule) []Issue {
+	var issues []Issue
+	add := func(severity, format string, args ...any) {
+		issues = append(issues, Issue{Severity: severity, Rule: rule.Name, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if !slices.Contains(recommendations, rule.Recommendation) {
+		add(SeverityError, "unknown recommendation %q (expected vm, kubernetes or serverless)", rule.Recommendation)
+	}
+	if rule.InstanceType != "" && rule.Recommendation != "vm" {
+		add(SeverityWarning, "instance_type %s is ignored for %s deployments", rule.InstanceType, rule.Recommendation)
+	}
+
+	c := rule.Conditions
+	if c.MinDependencies < 0 || c.MaxDependencies < 0 || c.MinServices < 0 {
+		add(SeverityError, "negative min_dependencies, max_dependencies or min_services")
+	}
+	if c.MaxDependencies > 0 && c.MinDependencies > c.MaxDependencies {
+		add(SeverityError, "never matches: min_dependencies %d is above max_dependencies %d", c.MinDependencies, c.MaxDependencies)
+	}
+	if c.MinServices > 0 && c.HasDockerCompose != nil && !*c.HasDockerCompose {
+		add(SeverityError, "never matches: min_services %d requires a docker-compose file, but has_docker_compose is false", c.MinServices)
+	}
+	if slices.Contains(c.Framework, "") {
+		add(SeverityWarning, "empty framework entry only matches apps without a detected framework")
+	}
+
+	return issues
+}
+
+// lintOrder flags rules whose first-match order is ambiguous (same priority, different
+// recommendations) or that are never reached (a higher-priority rule matches whenever they do)
+func lintOrder(rules []types.DeploymentRule) []Issue {
+	var issues []Issue
+
+	for i := range rules {
+		rule := &rules[i]
+		for j := range rules[:i] {
+			other := &rules[j]
+
+			if other.Priority == rule.Priority && other.Recommendation != rule.Recommendation {
+				issues = append(issues, Issue{Severity: SeverityWarning, Rule: rule.Name, Message: fmt.Sprintf(
+					"same priority %d as %q, which recommends %s: which one applies is undefined", rule.Priority, other.Name, other.Recommendation)})
+			}
+			if other.Priority > rule.Priority && covers(other.Conditions, rule.Conditions) {
+				issues = append(issues, Issue{Severity: SeverityWarning, Rule: rule.Name, Message: fmt.Sprintf(
+					"never matches: %q (priority %d) matches whenever it does", other.Name, other.Priority)})
+				break
+			}
+		}
+	}
+
+	return issues
+}
+
+// covers reports whether every app matching the conditions b also matches the conditions a
+func covers(a, b types.RuleConditions) bool {
+	if len(a.Framework) > 0 && (len(b.Framework) == 0 || !isSubset(b.Framework, a.Framework)) {
+		return false
+	}
+	if a.Language != "" && a.Language != b.Language {
+		return false
+	}
+	if a.MinDependencies > b.MinDependencies || a.MinServices > b.MinServices {
+		return false
+	}
+	if a.MaxDependencies > 0 && (b.MaxDependencies == 0 || b.MaxDependencies > a.MaxDependencies) {
+		return false
+	}
+	return coversBool(a.HasDockerfile, b.HasDockerfile) &&
+		coversBool(a.HasDockerCompose, b.HasDockerCompose) &&
+		coversBool(a.RequiresDatabase, b.RequiresDatabase)
+}
+
+// coversBool reports whether the optional boolean condition b implies a
+func coversBool(a, b *bool) bool {
+	return a == nil || (b != nil && *a == *b)
+}
+
+// isSubset reports whether every value of subset is in set
+func isSubset(subset, set []string) bool {
+	for _, value := range subset {
+		if !slices.Contains(set, value) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,137 @@
+package rules
+
+import (
+	"slices"
+
+	"github.com/Smana/scai/internal/types"
+)
+
+// Evaluation modes for DeploymentRules.Mode
+const (
+	ModeFirstMatch = "first-match"
+	ModeScoring    = "scoring"
+)
+
+// Condition weights used by ScoreRules. Conditions that narrow the match
+// more (framework, language, docker-compose) weigh more than broad ones.
+const (
+	weightFramework     = 3.0
+	weightLanguage      = 2.0
+	weightDockerCompose = 2.0
+	weightServices      = 2.0
+	weightDatabase      = 2.0
+	weightDockerfile    = 1.5
+	weightDependencies  = 1.0
+)
+
+// ScoredMatch is a matching rule with its confidence score
+type ScoredMatch struct {
+	RuleMatch
+	Score             float64
+	Priority          int
+	MatchedConditions int
+}
+
+// Evaluate evaluates rules using the mode configured in the rules file.
+// First-match is used unless the mode is "scoring".
+func Evaluate(rules *types.DeploymentRules, analysis *types.Analysis) (*RuleMatch, bool) {
+	if rules != nil && rules.Mode == ModeScoring {
+		return EvaluateRulesScored(rules, analysis)
+	}
+	return EvaluateRules(rules, analysis)
+}
+
+// EvaluateRulesScored returns the highest-scoring matching rule, ties broken by priority
+func EvaluateRulesScored(rules *types.DeploymentRules, analysis *types.Analysis) (*RuleMatch, bool) {
+	matches := ScoreRules(rules, analysis)
+	if len(matches) == 0 {
+		return nil, false
+	}
+
+	best := matches[0].RuleMatch
+	return &best, true
+}
+
+// ScoreRules returns all matching rules with their scores,
+// sorted by score (highest first) and then by priority
+func ScoreRules(rules *types.DeploymentRules, analysis *types.Analysis) []ScoredMatch {
+	if rules == nil {
+		return nil
+	}
+
+	var matches []ScoredMatch
+	for i := range rules.Rules {
+		rule := &rules.Rules[i]
+		if !matchesConditions(rule.Conditions, analysis) {
+			continue
+		}
+
+		score, matched := scoreConditions(rule.Conditions)
+		matches = append(matches, ScoredMatch{
+			RuleMatch: RuleMatch{
+				Strategy:     rule.Recommendation,
+				Reason:       rule.Reason,
+				InstanceType: rule.InstanceType,
+				RuleName:     rule.Name,
+			},
+			Score:             score,
+			Priority:          rule.Priority,
+			MatchedConditions: matched,
+		})
+	}
+
+	slices.SortStableFunc(matches, func(a, b ScoredMatch) int {
+		if a.Score != b.Score {
+			if a.Score > b.Score {
+				return -1
+			}
+			return 1
+		}
+		return b.Priority - a.Priority
+	})
+
+	return matches
+}
+
+// scoreConditions returns the score of a matching rule and the number of conditions it set.
+// Only conditions the rule actually constrains count; a framework list is
+// more specific (and scores higher) the fewer frameworks it names.
+func scoreConditions(conditions types.RuleConditions) (float64, int) {
+	score := 0.0
+	matched := 0
+
+	if n := len(conditions.Framework); n > 0 {
+		score += weightFramework / float64(n)
+		matched++
+	}
+	if conditions.Language != "" {
+		score += weightLanguage
+		matched++
+	}
+	if conditions.MinDependencies > 0 {
+		score += weightDependencies
+		matched++
+	}
+	if conditions.MaxDependencies > 0 {
+		score += weightDependencies
+		matched++
+	}
+	if conditions.HasDockerfile != nil {
+		score += weightDockerfile
+		matched++
+	}
+	if conditions.HasDockerCompose != nil {
+		score += weightDockerCompose
+		matched++
+	}
+	if conditions.MinServices > 0 {
+		score += weightServices
+		matched++
+	}
+	if conditions.RequiresDatabase != nil {
+		score += weightDatabase
+		matched++
+	}
+
+	return score, matched
+}
@@ -0,0 +1,106 @@
+package store
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/Smana/scai/internal/types"
+)
+
+// FieldDiff is a field whose value differs between two deployments
+type FieldDiff struct {
+	Field string // Dotted field name, e.g. Config.EKSMinNodes
+	Old   string
+	New   string
+}
+
+// diffIgnoredFields are local or per-run values that always differ between deployments
+var diffIgnoredFields = map[string]bool{
+	"Analysis.RepoPath":   true,
+	"Analysis.Verbose":    true,
+	"Config.Path":         true,
+	"Config.Directory":    true,
+	"Config.DeploymentID": true,
+}
+
+// Diff compares the strategy, region, repository, analysis and configuration of two
+// deployments field by field, in declaration order
+func Diff(from, to *Deployment) []FieldDiff {
+	oldFields := deploymentFields(from)
+	newFields := deploymentFields(to)
+
+	var diffs []FieldDiff
+	for i, field := range oldFields {
+		if field.value != newFields[i].value {
+			diffs = append(diffs, FieldDiff{Field: field.name, Old: field.value, New: newFields[i].value})
+		}
+	}
+	return diffs
+}
+
+type namedValue struct {
+	name  string
+	value string
+}
+
+// deploymentFields flattens the compared fields of a deployment (missing analysis or
+// configuration yields zero values, so both sides always have the same fields)
+func deploymentFields(d *Deployment) []namedValue {
+	fields := []namedValue{
+		{"Strategy", d.Strategy},
+		{"Region", d.Region},
+		{"RepoURL", d.RepoURL},
+		{"RepoCommitSHA", d.RepoCommitSHA},
+		{"LLMProvider", d.LLMProvider},
+		{"LLMModel", d.LLMModel},
+	}
+
+	analysis := d.Analysis
+	if analysis == nil {
+		analysis = &types.Analysis{}
+	}
+	config := d.Config
+	if config == nil {
+		config = &types.TerraformConfig{}
+	}
+
+	fields = appendStructFields(fields, "Analysis", reflect.ValueOf(*analysis))
+	return appendStructFields(fields, "Config", reflect.ValueOf(*config))
+}
+
+// appendStructFields appends the exported fields of a struct as formatted strings
+func appendStructFields(fields []namedValue, prefix string, v reflect.Value) []namedValue {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		name := prefix + "." + field.Name
+		if !field.IsExported() || diffIgnoredFields[name] {
+			continue
+		}
+		fields = append(fields, namedValue{name, formatValue(v.Field(i))})
+	}
+	return fields
+}
+
+// formatValue renders a field value; maps are sorted by key so the output is stable
+func formatValue(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.Slice:
+		items := make([]string, v.Len())
+		for i := range items {
+			items[i] = fmt.Sprint(v.Index(i).Interface())
+		}
+		return strings.Join(items, ", ")
+	case reflect.Map:
+		items := make([]string, 0, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			items = append(items, fmt.Sprintf("%v=%v", iter.Key().Interface(), iter.Value().Interface()))
+		}
+		sort.Strings(items)
+		return strings.Join(items, ", ")
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
@@ -0,0 +1,36 @@
+package store
+
+import "testing"
+
+func TestDiff(t *testing.T) {
+	from := testDeployment()
+	to := testDeployment()
+	to.ID = "0f1e2d3c-b4a5-6789-0abc-def123456789"
+	to.Region = "us-east-1"
+	to.Analysis.Port = 8000
+	to.Analysis.RepoPath = "/tmp/scai/other-clone"
+	to.Config.Tags = map[string]string{"team": "web", "env": "prod"}
+	to.Config.DeploymentID = to.ID
+
+	diffs := Diff(from, to)
+
+	want := []FieldDiff{
+		{Field: "Region", Old: "eu-west-3", New: "us-east-1"},
+		{Field: "Analysis.Port", Old: "5000", New: "8000"},
+		{Field: "Config.Tags", Old: "team=web", New: "env=prod, team=web"},
+	}
+	if len(diffs) != len(want) {
+		t.Fatalf("Expected %d differences, got %d: %+v", len(want), len(diffs), diffs)
+	}
+	for i := range want {
+		if diffs[i] != want[i] {
+			t.Errorf("Expected %+v, got %+v", want[i], diffs[i])
+		}
+	}
+
+	// A missing configuration compares as zero values
+	to.Config = nil
+	if len(Diff(from, from)) != 0 || len(Diff(from, to)) == 0 {
+		t.Error("Expected no differences with itself and differences with a missing config")
+	}
+}
@@ -0,0 +1,207 @@
+package store
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ExportVersion is the format version written to export files
+const ExportVersion = 1
+
+// ExportFile is the JSON document written by WriteJSON
+type ExportFile struct {
+	Version     int           `json:"version"`
+	ExportedAt  time.Time     `json:"exported_at"`
+	Deployments []*Deployment `json:"deployments"`
+}
+
+// csvHeader lists the CSV columns; nested fields are stored as JSON strings. Columns added
+// later are appended, so files written before them can still be read.
+var csvHeader = []string{
+	"id", "app_name", "user_prompt", "repo_url", "repo_commit_sha",
+	"strategy", "region", "status", "terraform_state_key", "terraform_dir",
+	"llm_provider", "llm_model",
+	"analysis_json", "config_json", "outputs_json", "warnings_json", "optimizations_json",
+	"error_message", "created_at", "updated_at", "deployed_at", "destroyed_at",
+	"plan_out_dir",
+}
+
+// csvMinColumns is the column count of files written before plan_out_dir was added
+const csvMinColumns = 22
+
+// WriteJSON writes deployments as a versioned JSON document
+func WriteJSON(w io.Writer, deployments []*Deployment) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(ExportFile{
+		Version:     ExportVersion,
+		ExportedAt:  time.Now(),
+		Deployments: deployments,
+	}); err != nil {
+		return fmt.Errorf("failed to encode deployments: %w", err)
+	}
+
+	return nil
+}
+
+// ReadJSON reads deployments written by WriteJSON
+func ReadJSON(r io.Reader) ([]*Deployment, error) {
+	var file ExportFile
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return nil, fmt.Errorf("failed to decode deployments: %w", err)
+	}
+
+	if file.Version > ExportVersion {
+		return nil, fmt.Errorf("unsupported export version %d (max %d)", file.Version, ExportVersion)
+	}
+
+	return file.Deployments, nil
+}
+
+// WriteCSV writes deployments as CSV with one row per deployment
+func WriteCSV(w io.Writer, deployments []*Deployment) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write(csvHeader); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	for _, deployment := range deployments {
+		row, err := deploymentToRecord(deployment)
+		if err != nil {
+			return fmt.Errorf("failed to encode deployment %s: %w", deployment.ID, err)
+		}
+		if err := writer.Write(row); err != nil {
+			return fmt.Errorf("failed to write CSV row: %w", err)
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// ReadCSV reads deployments written by WriteCSV
+func ReadCSV(r io.Reader) ([]*Deployment, error) {
+	reader := csv.NewReader(r)
+	// All records have the header column count (checked by the reader once the header is read)
+	reader.FieldsPerRecord = 0
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	if columns := len(records[0]); columns < csvMinColumns || columns > len(csvHeader) {
+		return nil, fmt.Errorf("failed to read CSV: expected %d columns, got %d", len(csvHeader), columns)
+	}
+
+	deployments := make([]*Deployment, 0, len(records)-1)
+	for i, record := range records[1:] {
+		deployment, err := recordToDeployment(record)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV row %d: %w", i+2, err)
+		}
+		deployments = append(deployments, deployment)
+	}
+
+	return deployments, nil
+}
+
+// deploymentToRecord converts a deployment to a CSV record
+func deploymentToRecord(d *Deployment) ([]string, error) {
+	jsonFields := []any{d.Analysis, d.Config, d.Outputs, d.Warnings, d.Optimizations}
+	encoded := make([]string, len(jsonFields))
+	for i, field := range jsonFields {
+		data, err := json.Marshal(field)
+		if err != nil {
+			return nil, err
+		}
+		encoded[i] = string(data)
+	}
+
+	return []string{
+		d.ID, d.AppName, d.UserPrompt, d.RepoURL, d.RepoCommitSHA,
+		d.Strategy, d.Region, string(d.Status), d.TerraformStateKey, d.TerraformDir,
+		d.LLMProvider, d.LLMModel,
+		encoded[0], encoded[1], encoded[2], encoded[3], encoded[4],
+		d.ErrorMessage, formatTime(&d.CreatedAt), formatTime(&d.UpdatedAt), formatTime(d.DeployedAt), formatTime(d.DestroyedAt),
+		d.PlanOutDir,
+	}, nil
+}
+
+// recordToDeployment converts a CSV record back to a deployment
+func recordToDeployment(record []string) (*Deployment, error) {
+	d := &Deployment{
+		ID:                record[0],
+		AppName:           record[1],
+		UserPrompt:        record[2],
+		RepoURL:           record[3],
+		RepoCommitSHA:     record[4],
+		Strategy:          record[5],
+		Region:            record[6],
+		Status:            DeploymentStatus(record[7]),
+		TerraformStateKey: record[8],
+		TerraformDir:      record[9],
+		LLMProvider:       record[10],
+		LLMModel:          record[11],
+		ErrorMessage:      record[17],
+	}
+	if len(record) > csvMinColumns {
+		d.PlanOutDir = record[22]
+	}
+
+	jsonFields := []any{&d.Analysis, &d.Config, &d.Outputs, &d.Warnings, &d.Optimizations}
+	for i, field := range jsonFields {
+		if err := json.Unmarshal([]byte(record[12+i]), field); err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", csvHeader[12+i], err)
+		}
+	}
+
+	createdAt, err := parseTime(record[18])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse created_at: %w", err)
+	}
+	updatedAt, err := parseTime(record[19])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse updated_at: %w", err)
+	}
+	if d.DeployedAt, err = parseTime(record[20]); err != nil {
+		return nil, fmt.Errorf("failed to parse deployed_at: %w", err)
+	}
+	if d.DestroyedAt, err = parseTime(record[21]); err != nil {
+		return nil, fmt.Errorf("failed to parse destroyed_at: %w", err)
+	}
+
+	if createdAt != nil {
+		d.CreatedAt = *createdAt
+	}
+	if updatedAt != nil {
+		d.UpdatedAt = *updatedAt
+	}
+
+	return d, nil
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+func parseTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
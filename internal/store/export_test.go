@@ -0,0 +1,122 @@
+package store
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"github.com/Smana/scai/internal/types"
+)
+
+func testDeployment() *Deployment {
+	created := time.Date(2025, 10, 18, 14, 18, 0, 0, time.UTC)
+	deployed := created.Add(5 * time.Minute)
+
+	return &Deployment{
+		ID:            "b2c0091f-af3f-46a4-9b13-213f607b1e1b",
+		AppName:       "hello-world",
+		UserPrompt:    "Deploy this Flask app, with \"quotes\" and a\nnewline",
+		Strategy:      "vm",
+		Region:        "eu-west-3",
+		Status:        DeploymentStatusSucceeded,
+		PlanOutDir:    "/home/user/infra/hello-world",
+		Analysis:      &types.Analysis{Framework: "flask", Port: 5000},
+		Config:        &types.TerraformConfig{AppName: "hello-world", Tags: map[string]string{"team": "web"}},
+		Outputs:       map[string]string{"url": "http://example.com"},
+		Warnings:      []string{"warning"},
+		Optimizations: []string{},
+		CreatedAt:     created,
+		UpdatedAt:     deployed,
+		DeployedAt:    &deployed,
+	}
+}
+
+func assertRoundTrip(t *testing.T, got []*Deployment) {
+	t.Helper()
+
+	want := testDeployment()
+	if len(got) != 1 {
+		t.Fatalf("Expected 1 deployment, got %d", len(got))
+	}
+	d := got[0]
+
+	if d.ID != want.ID || d.UserPrompt != want.UserPrompt || d.Status != want.Status || d.PlanOutDir != want.PlanOutDir {
+		t.Errorf("Basic fields not preserved: %+v", d)
+	}
+	if d.Analysis == nil || d.Analysis.Port != 5000 {
+		t.Errorf("Analysis not preserved: %+v", d.Analysis)
+	}
+	if d.Config == nil || d.Config.Tags["team"] != "web" {
+		t.Errorf("Config not preserved: %+v", d.Config)
+	}
+	if d.Outputs["url"] != "http://example.com" {
+		t.Errorf("Outputs not preserved: %v", d.Outputs)
+	}
+	if !d.CreatedAt.Equal(want.CreatedAt) {
+		t.Errorf("Expected created_at %v, got %v", want.CreatedAt, d.CreatedAt)
+	}
+	if d.DeployedAt == nil || !d.DeployedAt.Equal(*want.DeployedAt) {
+		t.Errorf("Expected deployed_at %v, got %v", want.DeployedAt, d.DeployedAt)
+	}
+	if d.DestroyedAt != nil {
+		t.Errorf("Expected nil destroyed_at, got %v", d.DestroyedAt)
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, []*Deployment{testDeployment()}); err != nil {
+		t.Fatalf("WriteJSON failed: %v", err)
+	}
+
+	got, err := ReadJSON(&buf)
+	if err != nil {
+		t.Fatalf("ReadJSON failed: %v", err)
+	}
+
+	assertRoundTrip(t, got)
+}
+
+func TestCSVRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, []*Deployment{testDeployment()}); err != nil {
+		t.Fatalf("WriteCSV failed: %v", err)
+	}
+
+	got, err := ReadCSV(&buf)
+	if err != nil {
+		t.Fatalf("ReadCSV failed: %v", err)
+	}
+
+	assertRoundTrip(t, got)
+}
+
+func TestReadCSVWithoutPlanOutDir(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, []*Deployment{testDeployment()}); err != nil {
+		t.Fatalf("WriteCSV failed: %v", err)
+	}
+
+	// Drop the plan_out_dir column, as in files exported before it was added
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var old bytes.Buffer
+	writer := csv.NewWriter(&old)
+	for _, record := range records {
+		if err := writer.Write(record[:csvMinColumns]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writer.Flush()
+
+	got, err := ReadCSV(&old)
+	if err != nil {
+		t.Fatalf("ReadCSV failed: %v", err)
+	}
+	if len(got) != 1 || got[0].ID != testDeployment().ID || got[0].PlanOutDir != "" {
+		t.Errorf("Unexpected deployments: %+v", got)
+	}
+}
@@ -2,7 +2,7 @@ package store
 
 const (
 	// SchemaVersion is the current database schema version
-	SchemaVersion = 1
+	SchemaVersion = 4
 
 	// InitialSchema creates the deployments table
 	InitialSchema = `
@@ -43,9 +43,35 @@ CREATE TABLE IF NOT EXISTS schema_version (
 );
 `
 
+	// AddPlanOutDir records where the Terraform files of a deployment were exported (--plan-out)
+	AddPlanOutDir = `
+ALTER TABLE deployments ADD COLUMN plan_out_dir TEXT;
+`
+
+	// AddDeploymentEvents creates the audit log of deployment status transitions and actions
+	AddDeploymentEvents = `
+CREATE TABLE IF NOT EXISTS deployment_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    deployment_id TEXT NOT NULL,
+    status TEXT NOT NULL,
+    message TEXT,
+    created_at DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_deployment_events_deployment_id ON deployment_events(deployment_id, id);
+`
+
+	// AddDeploymentLocks records the process running a deploy or destroy of a deployment
+	AddDeploymentLocks = `
+ALTER TABLE deployments ADD COLUMN locked_by TEXT;
+ALTER TABLE deployments ADD COLUMN locked_at DATETIME;
+`
 )
 
 // Migrations is a list of schema migrations to apply in order
 var Migrations = []string{
 	InitialSchema,
+	AddPlanOutDir,
+	AddDeploymentEvents,
+	AddDeploymentLocks,
 }
@@ -4,12 +4,21 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"time"
 
-	_ "github.com/mattn/go-sqlite3" // SQLite driver
+	"github.com/mattn/go-sqlite3" // SQLite driver
+)
+
+// Concurrent scia processes (e.g. a deploy and a list) share the database: SQLite waits up
+// to busyTimeout for a lock, and statements still failing with SQLITE_BUSY are retried
+const (
+	busyTimeout    = 5 * time.Second
+	busyRetries    = 3
+	busyRetryDelay = 200 * time.Millisecond
 )
 
 // SQLiteStore implements the Store interface using SQLite
@@ -27,13 +36,21 @@ func NewSQLiteStore(dbPath string) (*SQLiteStore, error) {
 	}
 
 	// Open database
-	db, err := sql.Open("sqlite3", dbPath+"?_foreign_keys=on&_journal_mode=WAL")
+	dsn := fmt.Sprintf("%s?_foreign_keys=on&_journal_mode=WAL&_busy_timeout=%d", dbPath, busyTimeout.Milliseconds())
+	db, err := sql.Open("sqlite3", dsn)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 
+	// SQLite allows a single writer: one connection serializes the statements of this
+	// process instead of having its own connections wait for each other's locks
+	db.SetMaxOpenConns(1)
+	db.SetMaxIdleConns(1)
+	db.SetConnMaxLifetime(0)
+
 	// Test connection
 	if err := db.Ping(); err != nil {
+		_ = db.Close()
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
@@ -84,25 +101,18 @@ func (s *SQLiteStore) getSchemaVersion(ctx context.Context) (int, error) {
 
 // applyMigration applies a single migration
 func (s *SQLiteStore) applyMigration(ctx context.Context, version int, migration string) error {
-	tx, err := s.db.BeginTx(ctx, nil)
-	if err != nil {
-		return err
-	}
-	defer tx.Rollback() //nolint:errcheck // Rollback is safe to ignore on defer
-
-	// Execute migration
-	if _, err := tx.ExecContext(ctx, migration); err != nil {
-		return err
-	}
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		// Execute migration
+		if _, err := tx.ExecContext(ctx, migration); err != nil {
+			return err
+		}
 
-	// Record migration
-	if _, err := tx.ExecContext(ctx, `
-		INSERT INTO schema_version (version, applied_at) VALUES (?, ?)
-	`, version+1, time.Now()); err != nil {
+		// Record migration
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO schema_version (version, applied_at) VALUES (?, ?)
+		`, version+1, time.Now())
 		return err
-	}
-
-	return tx.Commit()
+	})
 }
 
 // Close closes the database connection
@@ -113,6 +123,54 @@ func (s *SQLiteStore) Close() error {
 	return nil
 }
 
+// exec runs a statement, retried when the database is busy
+func (s *SQLiteStore) exec(ctx context.Context, query string, args ...any) error {
+	return withBusyRetry(ctx, func() error {
+		_, err := s.db.ExecContext(ctx, query, args...)
+		return err
+	})
+}
+
+// inTx runs fn in a transaction, retried when the database is busy
+func (s *SQLiteStore) inTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	return withBusyRetry(ctx, func() error {
+		tx, err := s.db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback() //nolint:errcheck // Rollback is safe to ignore on defer
+
+		if err := fn(tx); err != nil {
+			return err
+		}
+		return tx.Commit()
+	})
+}
+
+// withBusyRetry calls fn until it no longer fails with SQLITE_BUSY or SQLITE_LOCKED,
+// at most busyRetries more times with an increasing delay
+func withBusyRetry(ctx context.Context, fn func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if !isBusy(err) || attempt > busyRetries {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(time.Duration(attempt) * busyRetryDelay):
+		}
+	}
+}
+
+// isBusy reports whether err is a SQLite lock error
+func isBusy(err error) bool {
+	var sqliteErr sqlite3.Error
+	return errors.As(err, &sqliteErr) &&
+		(sqliteErr.Code == sqlite3.ErrBusy || sqliteErr.Code == sqlite3.ErrLocked)
+}
+
 // Create creates a new deployment record
 func (s *SQLiteStore) Create(ctx context.Context, deployment *Deployment) error {
 	// Serialize JSON fields
@@ -141,39 +199,46 @@ func (s *SQLiteStore) Create(ctx context.Context, deployment *Deployment) error
 		return fmt.Errorf("failed to marshal optimizations: %w", err)
 	}
 
-	// Insert deployment
-	_, err = s.db.ExecContext(ctx, `
-		INSERT INTO deployments (
-			id, app_name, user_prompt, repo_url, repo_commit_sha,
-			strategy, region, status, terraform_state_key, terraform_dir,
-			llm_provider, llm_model,
-			analysis_json, config_json, outputs_json, warnings_json, optimizations_json,
-			error_message, created_at, updated_at, deployed_at, destroyed_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`,
-		deployment.ID,
-		deployment.AppName,
-		deployment.UserPrompt,
-		deployment.RepoURL,
-		deployment.RepoCommitSHA,
-		deployment.Strategy,
-		deployment.Region,
-		deployment.Status,
-		deployment.TerraformStateKey,
-		deployment.TerraformDir,
-		deployment.LLMProvider,
-		deployment.LLMModel,
-		analysisJSON,
-		configJSON,
-		outputsJSON,
-		warningsJSON,
-		optimizationsJSON,
-		deployment.ErrorMessage,
-		deployment.CreatedAt,
-		deployment.UpdatedAt,
-		deployment.DeployedAt,
-		deployment.DestroyedAt,
-	)
+	// Insert deployment and its first event
+	err = s.inTx(ctx, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO deployments (
+				id, app_name, user_prompt, repo_url, repo_commit_sha,
+				strategy, region, status, terraform_state_key, terraform_dir, plan_out_dir,
+				llm_provider, llm_model,
+				analysis_json, config_json, outputs_json, warnings_json, optimizations_json,
+				error_message, created_at, updated_at, deployed_at, destroyed_at
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`,
+			deployment.ID,
+			deployment.AppName,
+			deployment.UserPrompt,
+			deployment.RepoURL,
+			deployment.RepoCommitSHA,
+			deployment.Strategy,
+			deployment.Region,
+			deployment.Status,
+			deployment.TerraformStateKey,
+			deployment.TerraformDir,
+			deployment.PlanOutDir,
+			deployment.LLMProvider,
+			deployment.LLMModel,
+			analysisJSON,
+			configJSON,
+			outputsJSON,
+			warningsJSON,
+			optimizationsJSON,
+			deployment.ErrorMessage,
+			deployment.CreatedAt,
+			deployment.UpdatedAt,
+			deployment.DeployedAt,
+			deployment.DestroyedAt,
+		)
+		if err != nil {
+			return err
+		}
+		return insertEvent(ctx, tx, deployment.ID, deployment.Status, "Deployment created", deployment.CreatedAt)
+	})
 	if err != nil {
 		return fmt.Errorf("failed to insert deployment: %w", err)
 	}
@@ -185,12 +250,12 @@ func (s *SQLiteStore) Create(ctx context.Context, deployment *Deployment) error
 func (s *SQLiteStore) Get(ctx context.Context, id string) (*Deployment, error) {
 	var deployment Deployment
 	var analysisJSON, configJSON, outputsJSON, warningsJSON, optimizationsJSON []byte
-	var llmProvider, llmModel sql.NullString
+	var planOutDir, llmProvider, llmModel sql.NullString
 
 	err := s.db.QueryRowContext(ctx, `
 		SELECT
 			id, app_name, user_prompt, repo_url, repo_commit_sha,
-			strategy, region, status, terraform_state_key, terraform_dir,
+			strategy, region, status, terraform_state_key, terraform_dir, plan_out_dir,
 			llm_provider, llm_model,
 			analysis_json, config_json, outputs_json, warnings_json, optimizations_json,
 			error_message, created_at, updated_at, deployed_at, destroyed_at
@@ -207,6 +272,7 @@ func (s *SQLiteStore) Get(ctx context.Context, id string) (*Deployment, error) {
 		&deployment.Status,
 		&deployment.TerraformStateKey,
 		&deployment.TerraformDir,
+		&planOutDir,
 		&llmProvider,
 		&llmModel,
 		&analysisJSON,
@@ -222,6 +288,9 @@ func (s *SQLiteStore) Get(ctx context.Context, id string) (*Deployment, error) {
 	)
 
 	// Convert sql.NullString to string
+	if planOutDir.Valid {
+		deployment.PlanOutDir = planOutDir.String
+	}
 	if llmProvider.Valid {
 		deployment.LLMProvider = llmProvider.String
 	}
@@ -266,7 +335,7 @@ func buildListQuery(filter *DeploymentFilter) (query string, args []interface{})
 	query = `
 		SELECT
 			id, app_name, user_prompt, repo_url, repo_commit_sha,
-			strategy, region, status, terraform_state_key, terraform_dir,
+			strategy, region, status, terraform_state_key, terraform_dir, plan_out_dir,
 			llm_provider, llm_model,
 			analysis_json, config_json, outputs_json, warnings_json, optimizations_json,
 			error_message, created_at, updated_at, deployed_at, destroyed_at
@@ -331,7 +400,7 @@ func (s *SQLiteStore) List(ctx context.Context, filter *DeploymentFilter) ([]*De
 func (s *SQLiteStore) scanDeployment(rows *sql.Rows) (*Deployment, error) {
 	var deployment Deployment
 	var analysisJSON, configJSON, outputsJSON, warningsJSON, optimizationsJSON []byte
-	var llmProvider, llmModel sql.NullString
+	var planOutDir, llmProvider, llmModel sql.NullString
 
 	err := rows.Scan(
 		&deployment.ID,
@@ -344,6 +413,7 @@ func (s *SQLiteStore) scanDeployment(rows *sql.Rows) (*Deployment, error) {
 		&deployment.Status,
 		&deployment.TerraformStateKey,
 		&deployment.TerraformDir,
+		&planOutDir,
 		&llmProvider,
 		&llmModel,
 		&analysisJSON,
@@ -362,6 +432,9 @@ func (s *SQLiteStore) scanDeployment(rows *sql.Rows) (*Deployment, error) {
 	}
 
 	// Convert sql.NullString to string
+	if planOutDir.Valid {
+		deployment.PlanOutDir = planOutDir.String
+	}
 	if llmProvider.Valid {
 		deployment.LLMProvider = llmProvider.String
 	}
@@ -427,7 +500,7 @@ func (s *SQLiteStore) Update(ctx context.Context, deployment *Deployment) error
 		return fmt.Errorf("failed to marshal optimizations: %w", err)
 	}
 
-	_, err = s.db.ExecContext(ctx, `
+	err = s.exec(ctx, `
 		UPDATE deployments SET
 			app_name = ?,
 			user_prompt = ?,
@@ -438,6 +511,7 @@ func (s *SQLiteStore) Update(ctx context.Context, deployment *Deployment) error
 			status = ?,
 			terraform_state_key = ?,
 			terraform_dir = ?,
+			plan_out_dir = ?,
 			llm_provider = ?,
 			llm_model = ?,
 			analysis_json = ?,
@@ -460,6 +534,7 @@ func (s *SQLiteStore) Update(ctx context.Context, deployment *Deployment) error
 		deployment.Status,
 		deployment.TerraformStateKey,
 		deployment.TerraformDir,
+		deployment.PlanOutDir,
 		deployment.LLMProvider,
 		deployment.LLMModel,
 		analysisJSON,
@@ -492,15 +567,21 @@ func (s *SQLiteStore) UpdateStatus(ctx context.Context, id string, status Deploy
 		destroyedAt = &now
 	}
 
-	_, err := s.db.ExecContext(ctx, `
-		UPDATE deployments SET
-			status = ?,
-			error_message = ?,
-			updated_at = ?,
-			deployed_at = COALESCE(deployed_at, ?),
-			destroyed_at = COALESCE(destroyed_at, ?)
-		WHERE id = ?
-	`, status, errorMessage, now, deployedAt, destroyedAt, id)
+	// Update the status and record the transition
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE deployments SET
+				status = ?,
+				error_message = ?,
+				updated_at = ?,
+				deployed_at = COALESCE(deployed_at, ?),
+				destroyed_at = COALESCE(destroyed_at, ?)
+			WHERE id = ?
+		`, status, errorMessage, now, deployedAt, destroyedAt, id); err != nil {
+			return err
+		}
+		return insertEvent(ctx, tx, id, status, errorMessage, now)
+	})
 	if err != nil {
 		return fmt.Errorf("failed to update deployment status: %w", err)
 	}
@@ -508,11 +589,103 @@ func (s *SQLiteStore) UpdateStatus(ctx context.Context, id string, status Deploy
 	return nil
 }
 
-// Delete removes a deployment record
+// Delete removes a deployment record and its events
 func (s *SQLiteStore) Delete(ctx context.Context, id string) error {
-	_, err := s.db.ExecContext(ctx, "DELETE FROM deployments WHERE id = ?", id)
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM deployment_events WHERE deployment_id = ?", id); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, "DELETE FROM deployments WHERE id = ?", id)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("failed to delete deployment: %w", err)
 	}
 	return nil
 }
+
+// AddEvent records an event of a deployment
+func (s *SQLiteStore) AddEvent(ctx context.Context, id string, status DeploymentStatus, message string) error {
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		return insertEvent(ctx, tx, id, status, message, time.Now())
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add deployment event: %w", err)
+	}
+	return nil
+}
+
+// ListEvents retrieves the events of a deployment, oldest first
+func (s *SQLiteStore) ListEvents(ctx context.Context, id string) ([]*DeploymentEvent, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, deployment_id, status, message, created_at
+		FROM deployment_events
+		WHERE deployment_id = ?
+		ORDER BY id
+	`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployment events: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	events := []*DeploymentEvent{}
+	for rows.Next() {
+		var event DeploymentEvent
+		var message sql.NullString
+		if err := rows.Scan(&event.ID, &event.DeploymentID, &event.Status, &message, &event.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan deployment event: %w", err)
+		}
+		event.Message = message.String
+		events = append(events, &event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating deployment events: %w", err)
+	}
+
+	return events, nil
+}
+
+// insertEvent records a deployment event in a transaction
+func insertEvent(ctx context.Context, tx *sql.Tx, id string, status DeploymentStatus, message string, at time.Time) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO deployment_events (deployment_id, status, message, created_at) VALUES (?, ?, ?, ?)
+	`, id, status, message, at)
+	return err
+}
+
+// Lock takes the advisory lock of a deployment for owner
+func (s *SQLiteStore) Lock(ctx context.Context, id, owner string) error {
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		var lockedBy sql.NullString
+		var lockedAt sql.NullTime
+		err := tx.QueryRowContext(ctx, "SELECT locked_by, locked_at FROM deployments WHERE id = ?", id).Scan(&lockedBy, &lockedAt)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("deployment not found: %s", id)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to lock deployment: %w", err)
+		}
+
+		if lockedBy.String != "" && lockedBy.String != owner && lockedAt.Valid && time.Since(lockedAt.Time) < LockTTL {
+			return fmt.Errorf("%w: %s is locked by process %s since %s (the lock expires after %s)",
+				ErrDeploymentBusy, id, lockedBy.String, lockedAt.Time.Format(time.RFC3339), LockTTL)
+		}
+
+		if _, err := tx.ExecContext(ctx, "UPDATE deployments SET locked_by = ?, locked_at = ? WHERE id = ?", owner, time.Now(), id); err != nil {
+			return fmt.Errorf("failed to lock deployment: %w", err)
+		}
+		return nil
+	})
+}
+
+// Unlock releases the lock of a deployment if owner holds it
+func (s *SQLiteStore) Unlock(ctx context.Context, id, owner string) error {
+	err := s.exec(ctx, "UPDATE deployments SET locked_by = NULL, locked_at = NULL WHERE id = ? AND locked_by = ?", id, owner)
+	if err != nil {
+		return fmt.Errorf("failed to unlock deployment: %w", err)
+	}
+	return nil
+}
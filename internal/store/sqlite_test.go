@@ -0,0 +1,163 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestConcurrentStores writes from two stores on the same database, as two scia processes would
+func TestConcurrentStores(t *testing.T) {
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "deployments.db")
+
+	stores := make([]*SQLiteStore, 2)
+	for i := range stores {
+		s, err := NewSQLiteStore(dbPath)
+		if err != nil {
+			t.Fatalf("NewSQLiteStore() error = %v", err)
+		}
+		if err := s.Initialize(ctx); err != nil {
+			t.Fatalf("Initialize() error = %v", err)
+		}
+		t.Cleanup(func() { _ = s.Close() })
+		stores[i] = s
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 40)
+	for i := range 40 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			deployment := testDeployment()
+			deployment.ID = fmt.Sprintf("deployment-%d", i)
+			errs <- stores[i%2].Create(ctx, deployment)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("Create() error = %v", err)
+		}
+	}
+
+	deployments, err := stores[0].List(ctx, nil)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(deployments) != 40 {
+		t.Errorf("List() returned %d deployments, want 40", len(deployments))
+	}
+}
+
+func TestDeploymentEvents(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewSQLiteStore(filepath.Join(t.TempDir(), "deployments.db"))
+	if err != nil {
+		t.Fatalf("NewSQLiteStore() error = %v", err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+	if err := s.Initialize(ctx); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	deployment := testDeployment()
+	deployment.Status = DeploymentStatusRunning
+	if err := s.Create(ctx, deployment); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if err := s.UpdateStatus(ctx, deployment.ID, DeploymentStatusFailed, "terraform apply failed"); err != nil {
+		t.Fatalf("UpdateStatus() error = %v", err)
+	}
+	if err := s.AddEvent(ctx, deployment.ID, DeploymentStatusFailed, "Destroy started"); err != nil {
+		t.Fatalf("AddEvent() error = %v", err)
+	}
+
+	events, err := s.ListEvents(ctx, deployment.ID)
+	if err != nil {
+		t.Fatalf("ListEvents() error = %v", err)
+	}
+	want := []struct {
+		status  DeploymentStatus
+		message string
+	}{
+		{DeploymentStatusRunning, "Deployment created"},
+		{DeploymentStatusFailed, "terraform apply failed"},
+		{DeploymentStatusFailed, "Destroy started"},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("ListEvents() returned %d events, want %d", len(events), len(want))
+	}
+	for i, event := range events {
+		if event.Status != want[i].status || event.Message != want[i].message {
+			t.Errorf("event %d = %s %q, want %s %q", i, event.Status, event.Message, want[i].status, want[i].message)
+		}
+	}
+
+	if err := s.Delete(ctx, deployment.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if events, _ := s.ListEvents(ctx, deployment.ID); len(events) != 0 {
+		t.Errorf("Delete() kept %d events", len(events))
+	}
+}
+
+func TestDeploymentLock(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewSQLiteStore(filepath.Join(t.TempDir(), "deployments.db"))
+	if err != nil {
+		t.Fatalf("NewSQLiteStore() error = %v", err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+	if err := s.Initialize(ctx); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	deployment := testDeployment()
+	if err := s.Create(ctx, deployment); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	if err := s.Lock(ctx, deployment.ID, "host:1"); err != nil {
+		t.Fatalf("Lock() error = %v", err)
+	}
+	if err := s.Lock(ctx, deployment.ID, "host:1"); err != nil {
+		t.Errorf("Lock() by the owner error = %v", err)
+	}
+	if err := s.Lock(ctx, deployment.ID, "host:2"); !errors.Is(err, ErrDeploymentBusy) {
+		t.Errorf("Lock() by another owner error = %v, want ErrDeploymentBusy", err)
+	}
+
+	// Only the owner releases the lock
+	if err := s.Unlock(ctx, deployment.ID, "host:2"); err != nil {
+		t.Fatalf("Unlock() error = %v", err)
+	}
+	if err := s.Lock(ctx, deployment.ID, "host:2"); !errors.Is(err, ErrDeploymentBusy) {
+		t.Errorf("Lock() after Unlock() by another owner error = %v, want ErrDeploymentBusy", err)
+	}
+	if err := s.Unlock(ctx, deployment.ID, "host:1"); err != nil {
+		t.Fatalf("Unlock() error = %v", err)
+	}
+	if err := s.Lock(ctx, deployment.ID, "host:2"); err != nil {
+		t.Errorf("Lock() after Unlock() error = %v", err)
+	}
+
+	// A stale lock is taken over
+	if _, err := s.db.ExecContext(ctx, "UPDATE deployments SET locked_at = ? WHERE id = ?", time.Now().Add(-LockTTL-time.Minute), deployment.ID); err != nil {
+		t.Fatalf("failed to age the lock: %v", err)
+	}
+	if err := s.Lock(ctx, deployment.ID, "host:3"); err != nil {
+		t.Errorf("Lock() of a stale lock error = %v", err)
+	}
+
+	if err := s.Lock(ctx, "missing", "host:1"); err == nil {
+		t.Error("Lock() of a missing deployment succeeded")
+	}
+}
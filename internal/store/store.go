@@ -2,6 +2,9 @@ package store
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"os"
 	"time"
 
 	"github.com/Smana/scai/internal/types"
@@ -16,8 +19,27 @@ const (
 	DeploymentStatusSucceeded DeploymentStatus = "succeeded"
 	DeploymentStatusFailed    DeploymentStatus = "failed"
 	DeploymentStatusDestroyed DeploymentStatus = "destroyed"
+
+	// DeploymentStatusPlanned is a deployment whose Terraform was exported with --plan-out, not applied
+	DeploymentStatusPlanned DeploymentStatus = "planned"
 )
 
+// LockTTL is the age after which the lock of a deployment is considered stale (left by a
+// process that crashed) and can be taken over: longer than any deploy or destroy
+const LockTTL = 2 * time.Hour
+
+// ErrDeploymentBusy is returned by Lock when another process holds the lock of the deployment
+var ErrDeploymentBusy = errors.New("deployment is busy")
+
+// LockOwner identifies the current process as the owner of deployment locks (host:pid)
+func LockOwner() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "localhost"
+	}
+	return fmt.Sprintf("%s:%d", host, os.Getpid())
+}
+
 // Deployment represents a tracked deployment in the database
 type Deployment struct {
 	ID                string
@@ -30,6 +52,7 @@ type Deployment struct {
 	Status            DeploymentStatus
 	TerraformStateKey string
 	TerraformDir      string
+	PlanOutDir        string // Where the Terraform files were exported with --plan-out
 
 	// LLM information
 	LLMProvider string
@@ -50,6 +73,16 @@ type Deployment struct {
 	DestroyedAt *time.Time
 }
 
+// DeploymentEvent is an entry of a deployment's audit log: a status transition or an action
+// (e.g. a destroy being started), with the status of the deployment at that point
+type DeploymentEvent struct {
+	ID           int64
+	DeploymentID string
+	Status       DeploymentStatus
+	Message      string
+	CreatedAt    time.Time
+}
+
 // DeploymentFilter represents query filters for deployments
 type DeploymentFilter struct {
 	Region   string
@@ -81,6 +114,21 @@ type Store interface {
 	// UpdateStatus updates only the status and error message
 	UpdateStatus(ctx context.Context, id string, status DeploymentStatus, errorMessage string) error
 
-	// Delete removes a deployment record
+	// Delete removes a deployment record and its events
 	Delete(ctx context.Context, id string) error
+
+	// AddEvent records an event of a deployment (status transitions are recorded by Create
+	// and UpdateStatus)
+	AddEvent(ctx context.Context, id string, status DeploymentStatus, message string) error
+
+	// ListEvents retrieves the events of a deployment, oldest first
+	ListEvents(ctx context.Context, id string) ([]*DeploymentEvent, error)
+
+	// Lock takes the advisory lock of a deployment for owner (see LockOwner) before an
+	// operation on its infrastructure. It fails with ErrDeploymentBusy while another owner
+	// holds it, unless that lock is older than LockTTL.
+	Lock(ctx context.Context, id, owner string) error
+
+	// Unlock releases the lock of a deployment if owner holds it
+	Unlock(ctx context.Context, id, owner string) error
 }
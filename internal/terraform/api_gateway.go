@@ -0,0 +1,140 @@
+package terraform
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/Smana/scai/internal/types"
+)
+
+// CORSAny allows any origin, method or header (the CORS default, for development)
+const CORSAny = "*"
+
+// corsMethods are the HTTP methods API Gateway accepts in a CORS configuration
+var corsMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS", CORSAny}
+
+// corsOriginPattern matches a CORS origin: scheme and host, with an optional port and no path
+var corsOriginPattern = regexp.MustCompile(`^https?://[a-zA-Z0-9*.-]+(:\d{1,5})?$`)
+
+// lambdaARNPattern matches the ARN of a Lambda function, optionally qualified by a version or alias
+var lambdaARNPattern = regexp.MustCompile(`^arn:aws[\w-]*:lambda:[a-z0-9-]+:\d{12}:function:[\w-]+(:[\w$-]+)?$`)
+
+// ValidateCORSOrigin checks that origin is "*" or an origin like https://app.example.com
+func ValidateCORSOrigin(origin string) error {
+	if origin != CORSAny && !corsOriginPattern.MatchString(origin) {
+		return fmt.Errorf("invalid CORS origin %q: expected * or scheme://host[:port], e.g. https://app.example.com", origin)
+	}
+	return nil
+}
+
+// ValidateCORSMethod checks that method is "*" or an HTTP method
+func ValidateCORSMethod(method string) error {
+	if !slices.Contains(corsMethods, strings.ToUpper(method)) {
+		return fmt.Errorf("invalid CORS method %q: expected one of %s", method, strings.Join(corsMethods, ", "))
+	}
+	return nil
+}
+
+// ValidateLambdaARN checks that arn is the ARN of a Lambda function
+// (e.g. arn:aws:lambda:eu-west-3:123456789012:function:authorizer)
+func ValidateLambdaARN(arn string) error {
+	if !lambdaARNPattern.MatchString(arn) {
+		return fmt.Errorf("invalid Lambda function ARN %q: expected e.g. arn:aws:lambda:eu-west-3:123456789012:function:authorizer", arn)
+	}
+	return nil
+}
+
+// CORSAllOrigins reports whether the CORS origins allow any origin (none configured
+// defaults to all)
+func CORSAllOrigins(origins []string) bool {
+	return len(origins) == 0 || slices.Contains(origins, CORSAny)
+}
+
+// LambdaFunctionName returns the function name of a Lambda ARN
+func LambdaFunctionName(arn string) string {
+	parts := strings.Split(arn, ":")
+	if len(parts) < 7 {
+		return arn
+	}
+	return parts[6]
+}
+
+// generateCORSConfiguration generates the cors_configuration of the API Gateway, allowing
+// everything for the lists left empty
+func (g *Generator) generateCORSConfiguration(config *types.TerraformConfig) string {
+	list := func(values []string, upper bool) string {
+		if len(values) == 0 {
+			values = []string{CORSAny}
+		}
+		quoted := make([]string, 0, len(values))
+		for _, value := range values {
+			if upper {
+				value = strings.ToUpper(value)
+			}
+			quoted = append(quoted, hclString(value))
+		}
+		return "[" + strings.Join(quoted, ", ") + "]"
+	}
+
+	headers := list(config.CORSAllowHeaders, false)
+	methods := list(config.CORSAllowMethods, true)
+	origins := list(config.CORSAllowOrigins, false)
+	return fmt.Sprintf(`  cors_configuration = {
+    allow_headers = %s
+    allow_methods = %s
+    allow_origins = %s
+  }`, headers, methods, origins)
+}
+
+// generateAuthorizer generates the Lambda (REQUEST) authorizer of the API Gateway, checking
+// the Authorization header, empty without config.AuthorizerARN
+func (g *Generator) generateAuthorizer(config *types.TerraformConfig) string {
+	if config.AuthorizerARN == "" {
+		return ""
+	}
+
+	return fmt.Sprintf(`
+  # Lambda authorizer of the routes
+  authorizers = {
+    custom = {
+      name                              = "%s-authorizer"
+      authorizer_type                   = "REQUEST"
+      authorizer_uri                    = "arn:aws:apigateway:%s:lambda:path/2015-03-31/functions/%s/invocations"
+      authorizer_payload_format_version = "2.0"
+      enable_simple_responses           = true
+      identity_sources                  = ["$request.header.Authorization"]
+    }
+  }
+`, config.AppName, config.Region, config.AuthorizerARN)
+}
+
+// generateRouteAuthorization returns the authorization arguments of the API Gateway routes,
+// empty without authorizer
+func (g *Generator) generateRouteAuthorization(config *types.TerraformConfig) string {
+	if config.AuthorizerARN == "" {
+		return ""
+	}
+	return "      authorization_type = \"CUSTOM\"\n      authorizer_key     = \"custom\"\n\n"
+}
+
+// generateAuthorizerPermission allows the API Gateway to invoke the authorizer function,
+// empty without authorizer
+func (g *Generator) generateAuthorizerPermission(config *types.TerraformConfig) string {
+	if config.AuthorizerARN == "" {
+		return ""
+	}
+
+	return fmt.Sprintf(`
+# Lambda permission for the API Gateway authorizer (per API: the authorizer function may be
+# shared by several deployments)
+resource "aws_lambda_permission" "api_gw_authorizer" {
+  statement_id  = "AllowAuthorizerFromAPIGateway-${module.api_gateway.api_id}"
+  action        = "lambda:InvokeFunction"
+  function_name = "%s"
+  principal     = "apigateway.amazonaws.com"
+  source_arn    = "${module.api_gateway.api_execution_arn}/authorizers/*"
+}
+`, config.AuthorizerARN)
+}
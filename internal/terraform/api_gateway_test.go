@@ -0,0 +1,80 @@
+package terraform
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Smana/scai/internal/types"
+)
+
+func TestValidateCORS(t *testing.T) {
+	for _, origin := range []string{"*", "https://app.example.com", "http://localhost:3000"} {
+		if err := ValidateCORSOrigin(origin); err != nil {
+			t.Errorf("ValidateCORSOrigin(%q) error = %v", origin, err)
+		}
+	}
+	for _, origin := range []string{"app.example.com", "https://app.example.com/", "https://app.example.com/path"} {
+		if err := ValidateCORSOrigin(origin); err == nil {
+			t.Errorf("ValidateCORSOrigin(%q) accepted an invalid origin", origin)
+		}
+	}
+
+	if err := ValidateCORSMethod("get"); err != nil {
+		t.Errorf("ValidateCORSMethod(get) error = %v", err)
+	}
+	if err := ValidateCORSMethod("FETCH"); err == nil {
+		t.Error("ValidateCORSMethod(FETCH) accepted an invalid method")
+	}
+}
+
+func TestValidateLambdaARN(t *testing.T) {
+	tests := []struct {
+		arn     string
+		wantErr bool
+	}{
+		{"arn:aws:lambda:eu-west-3:123456789012:function:authorizer", false},
+		{"arn:aws:lambda:eu-west-3:123456789012:function:authorizer:live", false},
+		{"arn:aws:iam::123456789012:role/authorizer", true},
+		{"authorizer", true},
+	}
+
+	for _, tt := range tests {
+		if err := ValidateLambdaARN(tt.arn); (err != nil) != tt.wantErr {
+			t.Errorf("ValidateLambdaARN(%q) error = %v, wantErr %v", tt.arn, err, tt.wantErr)
+		}
+	}
+}
+
+func TestAPIGatewaySecurity(t *testing.T) {
+	g := NewGenerator(t.TempDir(), false)
+
+	permissive := &types.TerraformConfig{AppName: "api", Region: "eu-west-3"}
+	if got := g.generateCORSConfiguration(permissive); !strings.Contains(got, `allow_origins = ["*"]`) {
+		t.Errorf("generateCORSConfiguration() = %q, want all origins by default", got)
+	}
+	if got := g.generateAuthorizer(permissive) + g.generateRouteAuthorization(permissive); got != "" {
+		t.Errorf("authorizer without ARN = %q, want none", got)
+	}
+
+	restricted := &types.TerraformConfig{
+		AppName:          "api",
+		Region:           "eu-west-3",
+		CORSAllowOrigins: []string{"https://app.example.com"},
+		CORSAllowMethods: []string{"get", "post"},
+		AuthorizerARN:    "arn:aws:lambda:eu-west-3:123456789012:function:authorizer",
+	}
+	cors := g.generateCORSConfiguration(restricted)
+	if !strings.Contains(cors, `allow_origins = ["https://app.example.com"]`) || !strings.Contains(cors, `allow_methods = ["GET", "POST"]`) {
+		t.Errorf("generateCORSConfiguration() = %q, want the configured origins and methods", cors)
+	}
+	if got := g.generateAuthorizer(restricted); !strings.Contains(got, "functions/arn:aws:lambda:eu-west-3:123456789012:function:authorizer/invocations") {
+		t.Errorf("generateAuthorizer() = %q, want the authorizer invocation URI", got)
+	}
+	if got := g.generateRouteAuthorization(restricted); !strings.Contains(got, `authorization_type = "CUSTOM"`) {
+		t.Errorf("generateRouteAuthorization() = %q, want CUSTOM authorization", got)
+	}
+
+	if name := LambdaFunctionName(restricted.AuthorizerARN); name != "authorizer" {
+		t.Errorf("LambdaFunctionName() = %q, want authorizer", name)
+	}
+}
@@ -0,0 +1,141 @@
+package terraform
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Smana/scai/internal/types"
+)
+
+// appNamespace is the Kubernetes namespace the application is deployed to
+const appNamespace = "default"
+
+// policyARNPattern matches IAM policy ARNs, AWS managed or of an account
+var policyARNPattern = regexp.MustCompile(`^arn:aws[\w-]*:iam::(aws|\d{12}):policy/[\w+=,.@/-]+$`)
+
+// ValidatePolicyARN checks that arn is an IAM policy ARN
+// (e.g. arn:aws:iam::aws:policy/AmazonS3ReadOnlyAccess)
+func ValidatePolicyARN(arn string) error {
+	if !policyARNPattern.MatchString(arn) {
+		return fmt.Errorf("invalid IAM policy ARN %q: expected e.g. arn:aws:iam::aws:policy/AmazonS3ReadOnlyAccess", arn)
+	}
+	return nil
+}
+
+// AppPodIdentity reports whether the application authenticates to AWS with EKS Pod Identity:
+// it has policies and runs on nodes (Fargate pods cannot reach the Pod Identity agent, they
+// use IRSA instead)
+func AppPodIdentity(policyARNs []string, fargate bool) bool {
+	return len(policyARNs) > 0 && !fargate
+}
+
+// generateAppServiceAccountName returns the service_account_name of the application pods,
+// empty without application policies. With Pod Identity, the name is read from the
+// association so that pods only start once their credentials can be injected.
+func (g *Generator) generateAppServiceAccountName(config *types.TerraformConfig) string {
+	switch {
+	case len(config.AppPolicyARNs) == 0:
+		return ""
+	case AppPodIdentity(config.AppPolicyARNs, config.EKSFargate):
+		return "        service_account_name = aws_eks_pod_identity_association.app.service_account\n\n"
+	default:
+		return "        service_account_name = kubernetes_service_account.app.metadata[0].name\n\n"
+	}
+}
+
+// generateAppIdentity generates the IAM role of the application with the policies of
+// config.AppPolicyARNs, and the Kubernetes service account bound to it: through an EKS Pod
+// Identity association on nodes, through IRSA (role ARN annotation) on Fargate. Empty without
+// application policies.
+func (g *Generator) generateAppIdentity(config *types.TerraformConfig, k8sAppName string) string {
+	if len(config.AppPolicyARNs) == 0 {
+		return ""
+	}
+
+	policyARNs := make([]string, 0, len(config.AppPolicyARNs))
+	for _, arn := range config.AppPolicyARNs {
+		policyARNs = append(policyARNs, hclString(arn))
+	}
+
+	// Who may assume the role, and how the service account is bound to it
+	var trust, serviceAccountAnnotations, association string
+	if AppPodIdentity(config.AppPolicyARNs, config.EKSFargate) {
+		trust = `{
+      Effect    = "Allow"
+      Principal = { Service = "pods.eks.amazonaws.com" }
+      Action    = ["sts:AssumeRole", "sts:TagSession"]
+    }`
+		association = fmt.Sprintf(`
+# Pod Identity association of the application service account with its IAM role
+resource "aws_eks_pod_identity_association" "app" {
+  depends_on = [aws_iam_role_policy_attachment.app]
+
+  cluster_name    = module.eks.cluster_name
+  namespace       = "%s"
+  service_account = kubernetes_service_account.app.metadata[0].name
+  role_arn        = aws_iam_role.app.arn
+}
+`, appNamespace)
+	} else {
+		trust = fmt.Sprintf(`{
+      Effect    = "Allow"
+      Principal = { Federated = module.eks.oidc_provider_arn }
+      Action    = "sts:AssumeRoleWithWebIdentity"
+      Condition = {
+        StringEquals = {
+          "${module.eks.oidc_provider}:sub" = "system:serviceaccount:%s:%s"
+          "${module.eks.oidc_provider}:aud" = "sts.amazonaws.com"
+        }
+      }
+    }`, appNamespace, k8sAppName)
+		serviceAccountAnnotations = `
+    annotations = {
+      "eks.amazonaws.com/role-arn" = aws_iam_role.app.arn
+    }`
+	}
+
+	return fmt.Sprintf(`
+# IAM role of the application, for its AWS access (S3, SQS, ...)
+resource "aws_iam_role" "app" {
+  name = "%s-app"
+
+  assume_role_policy = jsonencode({
+    Version   = "2012-10-17"
+    Statement = [%s]
+  })
+
+  tags = {
+    Name        = "%s-app"
+    Environment = "production"
+    ManagedBy   = "SCAI"
+  }
+}
+
+resource "aws_iam_role_policy_attachment" "app" {
+  for_each = toset([%s])
+
+  role       = aws_iam_role.app.name
+  policy_arn = each.value
+}
+
+# Kubernetes service account of the application pods
+resource "kubernetes_service_account" "app" {
+  depends_on = [module.eks]
+
+  metadata {
+    name      = "%s"
+    namespace = "%s"%s
+  }
+}
+%s`,
+		k8sAppName, // role name
+		trust,
+		k8sAppName, // role tags
+		strings.Join(policyARNs, ", "),
+		k8sAppName, // service account name
+		appNamespace,
+		serviceAccountAnnotations,
+		association,
+	)
+}
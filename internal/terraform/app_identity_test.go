@@ -0,0 +1,72 @@
+package terraform
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Smana/scai/internal/types"
+)
+
+func TestValidatePolicyARN(t *testing.T) {
+	tests := []struct {
+		arn     string
+		wantErr bool
+	}{
+		{"arn:aws:iam::aws:policy/AmazonS3ReadOnlyAccess", false},
+		{"arn:aws:iam::123456789012:policy/app/queue-access", false},
+		{"arn:aws-cn:iam::aws:policy/AmazonSQSFullAccess", false},
+		{"arn:aws:iam::123456789012:role/app", true},
+		{"AmazonS3ReadOnlyAccess", true},
+		{"", true},
+	}
+
+	for _, tt := range tests {
+		if err := ValidatePolicyARN(tt.arn); (err != nil) != tt.wantErr {
+			t.Errorf("ValidatePolicyARN(%q) error = %v, wantErr %v", tt.arn, err, tt.wantErr)
+		}
+	}
+}
+
+func TestAppIdentity(t *testing.T) {
+	policy := "arn:aws:iam::aws:policy/AmazonS3ReadOnlyAccess"
+	tests := []struct {
+		name    string
+		config  *types.TerraformConfig
+		want    []string
+		notWant []string
+	}{
+		{"no policies", &types.TerraformConfig{AppName: "web"}, nil, []string{"aws_iam_role"}},
+		{
+			"pod identity",
+			&types.TerraformConfig{AppName: "web", AppPolicyARNs: []string{policy}},
+			[]string{`"` + policy + `"`, "pods.eks.amazonaws.com", `resource "aws_eks_pod_identity_association" "app"`},
+			[]string{"eks.amazonaws.com/role-arn"},
+		},
+		{
+			"irsa on fargate",
+			&types.TerraformConfig{AppName: "web", AppPolicyARNs: []string{policy}, EKSFargate: true},
+			[]string{"system:serviceaccount:default:web", "eks.amazonaws.com/role-arn"},
+			[]string{"aws_eks_pod_identity_association"},
+		},
+	}
+
+	g := NewGenerator(t.TempDir(), false)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := g.generateAppIdentity(tt.config, "web")
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("generateAppIdentity() missing %q", want)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(got, notWant) {
+					t.Errorf("generateAppIdentity() contains %q", notWant)
+				}
+			}
+			if serviceAccount := g.generateAppServiceAccountName(tt.config); (serviceAccount != "") != (len(tt.config.AppPolicyARNs) > 0) {
+				t.Errorf("generateAppServiceAccountName() = %q", serviceAccount)
+			}
+		})
+	}
+}
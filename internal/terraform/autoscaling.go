@@ -0,0 +1,153 @@
+package terraform
+
+import (
+	"fmt"
+
+	"github.com/Smana/scai/internal/types"
+)
+
+// Autoscaling bounds used when a target CPU utilization is set
+const (
+	// vmAutoscaleMaxSize is the ASG max size (the ASG runs a single instance otherwise)
+	vmAutoscaleMaxSize = 3
+
+	// hpaMaxReplicas is the HorizontalPodAutoscaler max replicas (at least the configured replicas)
+	hpaMaxReplicas = 10
+)
+
+// DefaultReplicas is the number of replicas of the Kubernetes Deployment when none is configured
+const DefaultReplicas = 2
+
+// Replicas returns the Kubernetes Deployment replicas: replicas, or DefaultReplicas when unset
+func Replicas(replicas int) int {
+	if replicas > 0 {
+		return replicas
+	}
+	return DefaultReplicas
+}
+
+// HPAReplicas returns the HorizontalPodAutoscaler bounds: from the Deployment replicas up to
+// hpaMaxReplicas (or the replicas, when more are configured)
+func HPAReplicas(replicas int) (minReplicas, maxReplicas int) {
+	minReplicas = Replicas(replicas)
+	return minReplicas, max(minReplicas, hpaMaxReplicas)
+}
+
+// ASGMaxSize returns the ASG max size: room to scale out with an autoscaling policy, a single
+// auto-recovered instance otherwise
+func ASGMaxSize(config *types.TerraformConfig) int {
+	if config.AutoscaleTargetCPU > 0 {
+		return vmAutoscaleMaxSize
+	}
+	return 1
+}
+
+// generateASGScalingPolicy generates a target tracking policy on the ASG average CPU
+// utilization (empty without AutoscaleTargetCPU)
+func (g *Generator) generateASGScalingPolicy(config *types.TerraformConfig) string {
+	if config.AutoscaleTargetCPU == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf(`
+# Target tracking scaling policy: keep the average CPU utilization around the target
+# (the app URL points to the first instance: use --domain to load balance scaled-out instances)
+resource "aws_autoscaling_policy" "cpu_target" {
+  name                   = "%s-cpu-target"
+  autoscaling_group_name = module.asg.autoscaling_group_name
+  policy_type            = "TargetTrackingScaling"
+
+  target_tracking_configuration {
+    predefined_metric_specification {
+      predefined_metric_type = "ASGAverageCPUUtilization"
+    }
+    target_value = %d
+  }
+}
+`,
+		config.AppName,            // policy name
+		config.AutoscaleTargetCPU, // target CPU utilization
+	)
+}
+
+// generateDeploymentLifecycle keeps Terraform from resetting the replicas scaled by the
+// HorizontalPodAutoscaler (empty without AutoscaleTargetCPU)
+func (g *Generator) generateDeploymentLifecycle(config *types.TerraformConfig) string {
+	if config.AutoscaleTargetCPU == 0 {
+		return ""
+	}
+
+	return `
+  # Replicas are managed by the HorizontalPodAutoscaler
+  lifecycle {
+    ignore_changes = [spec[0].replicas]
+  }
+`
+}
+
+// generateHPA generates a HorizontalPodAutoscaler on CPU utilization and the metrics-server
+// add-on it reads metrics from (empty without AutoscaleTargetCPU). Nodes are not scaled:
+// managed node groups are tagged for Cluster Autoscaler auto-discovery, which has to be
+// installed separately.
+func (g *Generator) generateHPA(config *types.TerraformConfig, k8sAppName string) string {
+	if config.AutoscaleTargetCPU == 0 {
+		return ""
+	}
+
+	minReplicas, maxReplicas := HPAReplicas(config.Replicas)
+	nodesHint := "Pods run on Fargate: new pods get their own capacity, no node autoscaler is needed"
+	if !config.EKSFargate {
+		nodesHint = fmt.Sprintf("Install Cluster Autoscaler to scale the node group between %d and %d nodes (the managed node group is tagged for auto-discovery)",
+			config.EKSMinNodes, config.EKSMaxNodes)
+	}
+
+	return fmt.Sprintf(`
+# metrics-server provides the pod CPU metrics used by the HorizontalPodAutoscaler
+resource "aws_eks_addon" "metrics_server" {
+  cluster_name = module.eks.cluster_name
+  addon_name   = "metrics-server"
+}
+
+# HorizontalPodAutoscaler: keep the average CPU utilization (of the requests) around the target
+resource "kubernetes_horizontal_pod_autoscaler_v2" "app" {
+  depends_on = [kubernetes_deployment.app, aws_eks_addon.metrics_server]
+
+  metadata {
+    name = "%s-hpa"
+  }
+
+  spec {
+    min_replicas = %d
+    max_replicas = %d
+
+    scale_target_ref {
+      api_version = "apps/v1"
+      kind        = "Deployment"
+      name        = kubernetes_deployment.app.metadata[0].name
+    }
+
+    metric {
+      type = "Resource"
+      resource {
+        name = "cpu"
+        target {
+          type                = "Utilization"
+          average_utilization = %d
+        }
+      }
+    }
+  }
+}
+
+output "autoscaling_hint" {
+  description = "How the cluster scales with the pods"
+  value       = "%s"
+}
+`,
+		k8sAppName,                // HPA name
+		minReplicas,               // min replicas
+		maxReplicas,               // max replicas
+		config.AutoscaleTargetCPU, // target CPU utilization
+		nodesHint,                 // node scaling hint
+	)
+}
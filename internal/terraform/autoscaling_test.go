@@ -0,0 +1,21 @@
+package terraform
+
+import "testing"
+
+func TestHPAReplicas(t *testing.T) {
+	tests := []struct {
+		replicas int
+		wantMin  int
+		wantMax  int
+	}{
+		{0, DefaultReplicas, hpaMaxReplicas},
+		{4, 4, hpaMaxReplicas},
+		{12, 12, 12},
+	}
+
+	for _, tt := range tests {
+		if gotMin, gotMax := HPAReplicas(tt.replicas); gotMin != tt.wantMin || gotMax != tt.wantMax {
+			t.Errorf("HPAReplicas(%d) = %d/%d, want %d/%d", tt.replicas, gotMin, gotMax, tt.wantMin, tt.wantMax)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package terraform
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Smana/scai/internal/types"
+)
+
+// AppDockerfile is the Dockerfile generated for the EKS image of a repository without one
+const AppDockerfile = "Dockerfile.app"
+
+// imageTagLength is the length of the commit SHA prefix tagging the pushed images
+const imageTagLength = 12
+
+// ImageTag returns the tag of the container image pushed to ECR: the short commit SHA of the
+// repository, "latest" when it is not a Git checkout
+func ImageTag(commitSHA string) string {
+	if commitSHA == "" {
+		return "latest"
+	}
+	return commitSHA[:min(len(commitSHA), imageTagLength)]
+}
+
+// ECRRepositoryName builds the ECR repository name of the container image of an app deployed
+// with strategy (kubernetes, or serverless with a container image): lowercase letters, digits
+// and hyphens
+func ECRRepositoryName(appName, strategy string) string {
+	name := strings.Trim(strings.ToLower(invalidLBNameChars.ReplaceAllString(appName, "-")), "-")
+	if name == "" {
+		name = "app"
+	}
+	if strategy == "serverless" {
+		return name + "-lambda"
+	}
+	return name + "-eks"
+}
+
+// sourceDir returns the directory holding the sources built by the resources named name: the
+// analyzed copy of a zip archive or local directory, or else the Git clone of the repository
+func sourceDir(config *types.TerraformConfig, name string) string {
+	if config.SourcePath != "" {
+		return config.SourcePath
+	}
+	return name + "_build/app"
+}
+
+// generateImageBuild returns the ECR repository of the container image of the application and
+// the resources building it from dockerfile and pushing it with its tag (the commit SHA, which
+// is checked out). name is the Terraform name of the resources (lambda or app). Git repositories
+// are cloned; zip archives and local directories are built from their analyzed copy, working
+// tree changes included.
+func (g *Generator) generateImageBuild(config *types.TerraformConfig, name, dockerfile, platform string) string {
+	tag := ImageTag(config.CommitSHA)
+	repository := ECRRepositoryName(config.AppName, config.Strategy)
+	buildContext := filepath.Join(sourceDir(config, name), config.AppDir)
+
+	var fetch string
+	trigger := fmt.Sprintf(`image_tag = "%s"`, tag)
+	if config.SourcePath != "" {
+		fetch = fmt.Sprintf(`[ -d "%[1]s" ] || { echo "Sources not found: %[1]s (deploy again to analyze them)"; exit 1; }`+"\n", config.SourcePath)
+		// The commit does not identify uncommitted changes: rebuild on every apply
+		trigger = "always_run = timestamp()"
+	} else {
+		fetch = fmt.Sprintf("rm -rf %[1]s_build && mkdir -p %[1]s_build\n      git clone %[2]s %[1]s_build/app || exit 1\n", name, config.RepoURL)
+		if config.CommitSHA != "" {
+			fetch += fmt.Sprintf("      git -C %s_build/app checkout -q %s || exit 1\n", name, config.CommitSHA)
+		} else {
+			// Nothing identifies the sources: rebuild on every apply
+			trigger = "always_run = timestamp()"
+		}
+	}
+
+	return fmt.Sprintf(`# ECR repository for the container image
+resource "aws_ecr_repository" "%[1]s" {
+  name                 = "%[2]s"
+  image_tag_mutability = "MUTABLE"
+  force_delete         = true
+
+  image_scanning_configuration {
+    scan_on_push = true
+  }
+}
+
+# Build and push the container image
+resource "null_resource" "%[1]s_image" {
+  provisioner "local-exec" {
+    command = <<-EOT
+      echo "Building container image %[3]s..."
+      %[4]s      %[5]s
+
+      aws ecr get-login-password --region %[6]s | docker login --username AWS --password-stdin ${split("/", aws_ecr_repository.%[1]s.repository_url)[0]} || exit 1
+      docker buildx build --platform %[7]s --provenance=false \
+        -f "$DOCKERFILE" \
+        -t ${aws_ecr_repository.%[1]s.repository_url}:%[3]s \
+        --push "%[8]s" || exit 1
+
+      echo "Container image pushed"
+    EOT
+  }
+
+  triggers = {
+    %[9]s
+  }
+}
+
+data "aws_ecr_image" "%[1]s" {
+  depends_on = [null_resource.%[1]s_image]
+
+  repository_name = aws_ecr_repository.%[1]s.name
+  image_tag       = "%[3]s"
+}
+
+output "ecr_repository_url" {
+  description = "ECR repository of the container image"
+  value       = aws_ecr_repository.%[1]s.repository_url
+}
+
+output "image_tag" {
+  description = "Tag of the deployed container image"
+  value       = "%[3]s"
+}
+`,
+		name,          // resource name
+		repository,    // repository name
+		tag,           // image tag
+		fetch,         // sources of the build
+		dockerfile,    // DOCKERFILE assignment
+		config.Region, // ECR login region
+		platform,      // build platform
+		buildContext,  // build context
+		trigger,       // rebuild trigger
+	)
+}
+
+// imageURI returns the reference of the image pushed by generateImageBuild, by digest for the
+// deployment to roll out a rebuilt image
+func imageURI(name string) string {
+	return fmt.Sprintf("${aws_ecr_repository.%s.repository_url}@${data.aws_ecr_image.%s.image_digest}", name, name)
+}
+
+// generateAppImage returns the resources building the EKS image of the application: from the
+// Dockerfile of the repository (application directory first, then root), or from AppDockerfile
+func (g *Generator) generateAppImage(config *types.TerraformConfig) (string, error) {
+	dockerfile := "DOCKERFILE=${path.module}/" + AppDockerfile
+	if config.HasDockerfile {
+		sources := sourceDir(config, "app")
+		appDockerfile := filepath.Join(sources, config.AppDir, "Dockerfile")
+		dockerfile = fmt.Sprintf(`DOCKERFILE="%s"; [ -f "$DOCKERFILE" ] || DOCKERFILE="%s"`, appDockerfile, filepath.Join(sources, "Dockerfile"))
+	} else if err := g.generateAppDockerfile(config); err != nil {
+		return "", err
+	}
+	return g.generateImageBuild(config, "app", dockerfile, "linux/amd64"), nil
+}
+
+// generateAppDockerfile writes AppDockerfile, which installs the dependencies of the application
+// on the base image of its language, builds it and runs its start command
+func (g *Generator) generateAppDockerfile(config *types.TerraformConfig) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "FROM %s\nWORKDIR /app\nCOPY . .\n", g.detectContainerImage(config.Language, config.Framework))
+
+	switch config.Language {
+	case langPython:
+		b.WriteString("RUN if [ -f requirements.txt ]; then pip install --no-cache-dir -r requirements.txt; fi\n")
+	case langJavaScript, langTypeScript:
+		b.WriteString("RUN if [ -f package.json ]; then npm install; fi\n")
+	case "go":
+		b.WriteString("RUN if [ -f go.mod ]; then go mod download; fi\n")
+	}
+	if config.BuildCommand != "" {
+		fmt.Fprintf(&b, "RUN %s\n", config.BuildCommand)
+	}
+
+	fmt.Fprintf(&b, "ENV PORT=%d\nEXPOSE %d\n", config.Port, config.Port)
+	if config.StartCommand != "" {
+		cmd, err := json.Marshal([]string{"sh", "-c", config.StartCommand})
+		if err != nil {
+			return fmt.Errorf("failed to encode start command: %w", err)
+		}
+		fmt.Fprintf(&b, "CMD %s\n", cmd)
+	}
+
+	return os.WriteFile(filepath.Join(g.outputDir, AppDockerfile), []byte(b.String()), 0o644)
+}
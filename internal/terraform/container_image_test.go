@@ -0,0 +1,177 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Smana/scai/internal/types"
+)
+
+func TestImageTag(t *testing.T) {
+	tests := []struct {
+		commitSHA string
+		want      string
+	}{
+		{"3f2a9c1e8b7d6a5f4e3d2c1b0a9f8e7d6c5b4a39", "3f2a9c1e8b7d"},
+		{"3f2a9c1", "3f2a9c1"},
+		{"", "latest"},
+	}
+
+	for _, tt := range tests {
+		if got := ImageTag(tt.commitSHA); got != tt.want {
+			t.Errorf("ImageTag(%q) = %q, want %q", tt.commitSHA, got, tt.want)
+		}
+	}
+}
+
+func TestECRRepositoryName(t *testing.T) {
+	tests := []struct {
+		appName, strategy string
+		want              string
+	}{
+		{"web", "kubernetes", "web-eks"},
+		{"My_App", "serverless", "my-app-lambda"},
+		{"__", "kubernetes", "app-eks"},
+	}
+
+	for _, tt := range tests {
+		if got := ECRRepositoryName(tt.appName, tt.strategy); got != tt.want {
+			t.Errorf("ECRRepositoryName(%q, %q) = %q, want %q", tt.appName, tt.strategy, got, tt.want)
+		}
+	}
+}
+
+func TestEKSAppImage(t *testing.T) {
+	tests := []struct {
+		name           string
+		hasDockerfile  bool
+		wantDockerfile string
+	}{
+		{"generated Dockerfile", false, "DOCKERFILE=${path.module}/" + AppDockerfile},
+		{"repository Dockerfile", true, `DOCKERFILE="app_build/app/api/Dockerfile"; [ -f "$DOCKERFILE" ] || DOCKERFILE="app_build/app/Dockerfile"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			config := &types.TerraformConfig{
+				Strategy:          "kubernetes",
+				AppName:           "web",
+				Region:            "eu-west-3",
+				Language:          "python",
+				Port:              8080,
+				RepoURL:           "https://github.com/example/web",
+				AppDir:            "api",
+				StartCommand:      "gunicorn app:app --bind 0.0.0.0:8080",
+				CommitSHA:         "3f2a9c1e8b7d6a5f4e3d2c1b0a9f8e7d6c5b4a39",
+				HasDockerfile:     tt.hasDockerfile,
+				EKSNodeType:       "t3.medium",
+				EKSMinNodes:       1,
+				EKSMaxNodes:       3,
+				EKSDesiredNodes:   2,
+				EKSNodeVolumeSize: 20,
+			}
+			if err := NewGenerator(dir, false).Generate(config); err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			mainTF, err := os.ReadFile(filepath.Join(dir, "main.tf"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range []string{
+				`resource "aws_ecr_repository" "app"`,
+				`name                 = "web-eks"`,
+				"git -C app_build/app checkout -q 3f2a9c1e8b7d6a5f4e3d2c1b0a9f8e7d6c5b4a39",
+				tt.wantDockerfile,
+				"-t ${aws_ecr_repository.app.repository_url}:3f2a9c1e8b7d",
+				`--push "app_build/app/api"`,
+				`image = "${aws_ecr_repository.app.repository_url}@${data.aws_ecr_image.app.image_digest}"`,
+			} {
+				if !strings.Contains(string(mainTF), want) {
+					t.Errorf("main.tf missing %q", want)
+				}
+			}
+
+			dockerfile, err := os.ReadFile(filepath.Join(dir, AppDockerfile))
+			if tt.hasDockerfile {
+				if err == nil {
+					t.Errorf("%s generated for a repository with a Dockerfile", AppDockerfile)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range []string{
+				"FROM " + imagePython,
+				"pip install --no-cache-dir -r requirements.txt",
+				"EXPOSE 8080",
+				`CMD ["sh","-c","gunicorn app:app --bind 0.0.0.0:8080"]`,
+			} {
+				if !strings.Contains(string(dockerfile), want) {
+					t.Errorf("%s missing %q", AppDockerfile, want)
+				}
+			}
+		})
+	}
+}
+
+func TestEKSAppImageFromLocalSources(t *testing.T) {
+	tests := []struct {
+		name      string
+		repoURL   string
+		commitSHA string
+	}{
+		{"zip archive", "/home/dev/web.zip", ""},
+		{"local directory", "/home/dev/monorepo/web", "3f2a9c1e8b7d6a5f4e3d2c1b0a9f8e7d6c5b4a39"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			config := &types.TerraformConfig{
+				Strategy:          "kubernetes",
+				AppName:           "web",
+				Region:            "eu-west-3",
+				Language:          "python",
+				Port:              8080,
+				RepoURL:           tt.repoURL,
+				SourcePath:        "/tmp/scai/repos/web",
+				AppDir:            "api",
+				CommitSHA:         tt.commitSHA,
+				HasDockerfile:     true,
+				EKSNodeType:       "t3.medium",
+				EKSMinNodes:       1,
+				EKSMaxNodes:       3,
+				EKSDesiredNodes:   2,
+				EKSNodeVolumeSize: 20,
+			}
+			if err := NewGenerator(dir, false).Generate(config); err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			mainTF, err := os.ReadFile(filepath.Join(dir, "main.tf"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// The analyzed copy is built as is, working tree changes included
+			for _, want := range []string{
+				`[ -d "/tmp/scai/repos/web" ] || {`,
+				`DOCKERFILE="/tmp/scai/repos/web/api/Dockerfile"; [ -f "$DOCKERFILE" ] || DOCKERFILE="/tmp/scai/repos/web/Dockerfile"`,
+				`--push "/tmp/scai/repos/web/api"`,
+				"always_run = timestamp()",
+			} {
+				if !strings.Contains(string(mainTF), want) {
+					t.Errorf("main.tf missing %q", want)
+				}
+			}
+			for _, unwanted := range []string{"git clone", "git -C", tt.repoURL} {
+				if strings.Contains(string(mainTF), unwanted) {
+					t.Errorf("main.tf contains %q", unwanted)
+				}
+			}
+		})
+	}
+}
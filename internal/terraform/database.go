@@ -0,0 +1,205 @@
+package terraform
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Smana/scai/internal/types"
+)
+
+// maxDBIdentifierLength keeps "<app>-db-" plus the 26-character suffix Terraform adds
+// to identifier_prefix within the 63-character RDS limit
+const maxDBIdentifierLength = 30
+
+// databaseEngine holds the RDS settings of a supported engine
+type databaseEngine struct {
+	Version   string
+	Port      int
+	URLScheme string
+}
+
+// databaseEngines lists the engines supported by --with-database
+var databaseEngines = map[string]databaseEngine{
+	"postgres": {Version: "16", Port: 5432, URLScheme: "postgres"},
+	"mysql":    {Version: "8.0", Port: 3306, URLScheme: "mysql"},
+}
+
+// generateDatabaseConfig writes database.tf: an RDS instance in the deployment's VPC,
+// reachable from inside the VPC only, and local.database_url for the application
+func (g *Generator) generateDatabaseConfig(config *types.TerraformConfig) error {
+	engine, ok := databaseEngines[config.DatabaseEngine]
+	if !ok {
+		return fmt.Errorf("unsupported database engine: %s", config.DatabaseEngine)
+	}
+
+	// The database lives in the network the application runs in
+	if config.Strategy != "vm" && config.Strategy != "kubernetes" {
+		return fmt.Errorf("databases are not supported for strategy: %s", config.Strategy)
+	}
+	network := networkReferences(config)
+	vpcID, subnetIDs, vpcCIDR := network.VPCID, network.SubnetIDs, network.VPCCIDR
+
+	dbName := dbIdentifier(config.AppName)
+
+	databaseTF := fmt.Sprintf(`# RDS %s database for %s
+# Generated by SCAI
+
+resource "random_password" "database" {
+  length  = 32
+  special = false
+}
+
+resource "aws_db_subnet_group" "database" {
+  name_prefix = "%s-db-"
+  subnet_ids  = %s
+
+  tags = {
+    Name      = "%s-db"
+    ManagedBy = "SCAI"
+  }
+}
+
+resource "aws_security_group" "database" {
+  name_prefix = "%s-db-"
+  description = "Database access for %s"
+  vpc_id      = %s
+
+  ingress {
+    from_port   = %d
+    to_port     = %d
+    protocol    = "tcp"
+    cidr_blocks = [%s]
+    description = "Database access from the VPC"
+  }
+
+  tags = {
+    Name      = "%s-db"
+    ManagedBy = "SCAI"
+  }
+}
+
+resource "aws_db_instance" "database" {
+  identifier_prefix = "%s-db-"
+
+  engine            = "%s"
+  engine_version    = "%s"
+  instance_class    = "%s"
+  allocated_storage = %d
+  storage_type      = "gp3"
+  storage_encrypted = true
+
+  db_name  = "app"
+  username = "app"
+  password = random_password.database.result
+
+  db_subnet_group_name   = aws_db_subnet_group.database.name
+  vpc_security_group_ids = [aws_security_group.database.id]
+  publicly_accessible    = false
+
+  backup_retention_period = 7
+  skip_final_snapshot     = true
+
+  tags = {
+    Name        = "%s-db"
+    Environment = "production"
+    ManagedBy   = "SCAI"
+  }
+}
+
+locals {
+  database_url = "%s://app:${random_password.database.result}@${aws_db_instance.database.endpoint}/app"
+}
+%s
+output "database_endpoint" {
+  description = "RDS database endpoint"
+  value       = aws_db_instance.database.endpoint
+}
+`,
+		config.DatabaseEngine, config.AppName, // Comment
+		dbName,                   // subnet group name prefix
+		subnetIDs,                // subnets
+		dbName,                   // subnet group tag
+		dbName,                   // SG name prefix
+		config.AppName,           // SG description
+		vpcID,                    // SG VPC
+		engine.Port, engine.Port, // ingress ports
+		vpcCIDR,                          // ingress CIDR
+		dbName,                           // SG tag
+		dbName,                           // identifier prefix
+		config.DatabaseEngine,            // engine
+		engine.Version,                   // engine version
+		config.DatabaseInstanceClass,     // instance class
+		config.DatabaseStorage,           // allocated storage
+		dbName,                           // instance tag
+		engine.URLScheme,                 // connection string scheme
+		g.generateDatabaseSecret(config), // Kubernetes secret (EKS only)
+	)
+
+	return os.WriteFile(filepath.Join(g.outputDir, "database.tf"), []byte(databaseTF), 0o644)
+}
+
+// dbIdentifier builds the RDS identifier prefix for an app: lowercase letters, digits
+// and hyphens, starting with a letter and short enough for the generated suffix
+func dbIdentifier(appName string) string {
+	name := strings.ToLower(strings.Trim(invalidLBNameChars.ReplaceAllString(appName, "-"), "-"))
+	if name == "" || name[0] < 'a' || name[0] > 'z' {
+		name = "app-" + name
+	}
+	if len(name) > maxDBIdentifierLength {
+		name = strings.TrimRight(name[:maxDBIdentifierLength], "-")
+	}
+	return name
+}
+
+// generateDatabaseSecret stores the connection string in a Kubernetes secret (EKS only)
+func (g *Generator) generateDatabaseSecret(config *types.TerraformConfig) string {
+	if config.Strategy != "kubernetes" {
+		return ""
+	}
+
+	return fmt.Sprintf(`
+resource "kubernetes_secret" "database" {
+  depends_on = [module.eks]
+
+  metadata {
+    name = "%s-database"
+  }
+
+  data = {
+    DATABASE_URL = local.database_url
+  }
+}
+`, strings.ReplaceAll(config.AppName, "_", "-"))
+}
+
+// generateDatabaseEnv returns the DATABASE_URL env block of the Kubernetes container
+// (empty without a database)
+func (g *Generator) generateDatabaseEnv(config *types.TerraformConfig) string {
+	if config.DatabaseEngine == "" {
+		return ""
+	}
+
+	return `
+          env {
+            name = "DATABASE_URL"
+            value_from {
+              secret_key_ref {
+                name = kubernetes_secret.database.metadata[0].name
+                key  = "DATABASE_URL"
+              }
+            }
+          }
+`
+}
+
+// generateDatabaseExport returns the user-data line exporting DATABASE_URL before the
+// application starts (empty without a database). The value is interpolated by Terraform.
+func (g *Generator) generateDatabaseExport(config *types.TerraformConfig) string {
+	if config.DatabaseEngine == "" {
+		return ""
+	}
+
+	return "# Database connection string (RDS)\nexport DATABASE_URL=\"${local.database_url}\"\n\n"
+}
@@ -0,0 +1,317 @@
+package terraform
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/Smana/scai/internal/types"
+)
+
+// maxLBNameLength is the AWS limit for load balancer and target group names
+const maxLBNameLength = 32
+
+var invalidLBNameChars = regexp.MustCompile(`[^a-zA-Z0-9-]+`)
+
+// generateDomainConfig writes domain.tf: the ACM certificate, the HTTPS entry point
+// for the strategy (ALB, API Gateway custom domain or ELB listener) and the DNS record
+func (g *Generator) generateDomainConfig(config *types.TerraformConfig) error {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, `# Custom domain for %s
+# Generated by SCAI
+
+data "aws_route53_zone" "domain" {
+  zone_id = %s
+}
+`, config.AppName, hclString(config.HostedZoneID))
+
+	sb.WriteString(g.generateCertificate(config))
+
+	switch config.Strategy {
+	case "vm":
+		sb.WriteString(g.generateALB(config))
+	case "serverless":
+		sb.WriteString(g.generateAPIGatewayDomain(config))
+	case "kubernetes":
+		sb.WriteString(g.generateELBRecord(config))
+	default:
+		return fmt.Errorf("custom domains are not supported for strategy: %s", config.Strategy)
+	}
+
+	fmt.Fprintf(&sb, `
+output "https_url" {
+  description = "Application HTTPS URL"
+  value       = %s
+}
+`, hclString("https://"+config.Domain+"/"))
+
+	return os.WriteFile(filepath.Join(g.outputDir, "domain.tf"), []byte(sb.String()), 0o644)
+}
+
+// generateCertificate reuses an existing ACM certificate or requests a DNS-validated one.
+// Either way the ARN is exposed as local.certificate_arn.
+func (g *Generator) generateCertificate(config *types.TerraformConfig) string {
+	if config.CertificateARN != "" {
+		return fmt.Sprintf(`
+# Existing ACM certificate
+locals {
+  certificate_arn = %s
+}
+`, hclString(config.CertificateARN))
+	}
+
+	return fmt.Sprintf(`
+# ACM certificate (DNS validated)
+resource "aws_acm_certificate" "domain" {
+  domain_name       = %s
+  validation_method = "DNS"
+
+  lifecycle {
+    create_before_destroy = true
+  }
+}
+
+resource "aws_route53_record" "certificate_validation" {
+  for_each = {
+    for dvo in aws_acm_certificate.domain.domain_validation_options : dvo.domain_name => {
+      name   = dvo.resource_record_name
+      record = dvo.resource_record_value
+      type   = dvo.resource_record_type
+    }
+  }
+
+  allow_overwrite = true
+  name            = each.value.name
+  records         = [each.value.record]
+  ttl             = 60
+  type            = each.value.type
+  zone_id         = data.aws_route53_zone.domain.zone_id
+}
+
+resource "aws_acm_certificate_validation" "domain" {
+  certificate_arn         = aws_acm_certificate.domain.arn
+  validation_record_fqdns = [for record in aws_route53_record.certificate_validation : record.fqdn]
+}
+
+locals {
+  certificate_arn = aws_acm_certificate_validation.domain.certificate_arn
+}
+`, hclString(config.Domain))
+}
+
+// generateALB puts an Application Load Balancer with an HTTPS listener in front of the ASG
+func (g *Generator) generateALB(config *types.TerraformConfig) string {
+	network := networkReferences(config)
+
+	return fmt.Sprintf(`
+# Application Load Balancer (HTTPS termination)
+resource "aws_security_group" "alb" {
+  name_prefix = "%s-alb-"
+  description = "HTTPS load balancer for %s"
+  vpc_id      = %s
+
+  ingress {
+    from_port   = 443
+    to_port     = 443
+    protocol    = "tcp"
+    cidr_blocks = ["0.0.0.0/0"]
+    description = "HTTPS"
+  }
+
+  ingress {
+    from_port   = 80
+    to_port     = 80
+    protocol    = "tcp"
+    cidr_blocks = ["0.0.0.0/0"]
+    description = "HTTP (redirected to HTTPS)"
+  }
+
+  egress {
+    from_port   = 0
+    to_port     = 0
+    protocol    = "-1"
+    cidr_blocks = ["0.0.0.0/0"]
+    description = "Allow all outbound"
+  }
+}
+
+resource "aws_lb" "app" {
+  name               = "%s"
+  load_balancer_type = "application"
+  security_groups    = [aws_security_group.alb.id]
+  subnets            = %s
+}
+
+resource "aws_lb_target_group" "app" {
+  name     = "%s"
+  port     = %d
+  protocol = "HTTP"
+  vpc_id   = %s
+
+  health_check {
+    path    = %s
+    matcher = "200-399"
+  }
+}
+
+resource "aws_autoscaling_attachment" "app" {
+  autoscaling_group_name = module.asg.autoscaling_group_name
+  lb_target_group_arn    = aws_lb_target_group.app.arn
+}
+
+resource "aws_lb_listener" "https" {
+  load_balancer_arn = aws_lb.app.arn
+  port              = 443
+  protocol          = "HTTPS"
+  ssl_policy        = "ELBSecurityPolicy-TLS13-1-2-2021-06"
+  certificate_arn   = local.certificate_arn
+
+  default_action {
+    type             = "forward"
+    target_group_arn = aws_lb_target_group.app.arn
+  }
+}
+
+resource "aws_lb_listener" "http" {
+  load_balancer_arn = aws_lb.app.arn
+  port              = 80
+  protocol          = "HTTP"
+
+  default_action {
+    type = "redirect"
+
+    redirect {
+      port        = "443"
+      protocol    = "HTTPS"
+      status_code = "HTTP_301"
+    }
+  }
+}
+
+resource "aws_route53_record" "domain" {
+  zone_id = data.aws_route53_zone.domain.zone_id
+  name    = %s
+  type    = "A"
+
+  alias {
+    name                   = aws_lb.app.dns_name
+    zone_id                = aws_lb.app.zone_id
+    evaluate_target_health = true
+  }
+}
+`,
+		config.AppName,                     // SG name prefix
+		config.AppName,                     // SG description
+		network.VPCID,                      // SG VPC
+		lbName(config.AppName, "alb"),      // ALB name
+		network.SubnetIDs,                  // ALB subnets
+		lbName(config.AppName, "tg"),       // target group name
+		config.Port,                        // target group port
+		network.VPCID,                      // target group VPC
+		hclString(healthCheckPath(config)), // health check path
+		hclString(config.Domain),           // DNS record name
+	)
+}
+
+// generateAPIGatewayDomain maps a regional API Gateway custom domain to the HTTP API
+func (g *Generator) generateAPIGatewayDomain(config *types.TerraformConfig) string {
+	return fmt.Sprintf(`
+# API Gateway custom domain
+resource "aws_apigatewayv2_domain_name" "domain" {
+  domain_name = %s
+
+  domain_name_configuration {
+    certificate_arn = local.certificate_arn
+    endpoint_type   = "REGIONAL"
+    security_policy = "TLS_1_2"
+  }
+}
+
+resource "aws_apigatewayv2_api_mapping" "domain" {
+  api_id      = module.api_gateway.api_id
+  domain_name = aws_apigatewayv2_domain_name.domain.id
+  stage       = module.api_gateway.stage_id
+}
+
+resource "aws_route53_record" "domain" {
+  zone_id = data.aws_route53_zone.domain.zone_id
+  name    = aws_apigatewayv2_domain_name.domain.domain_name
+  type    = "A"
+
+  alias {
+    name                   = aws_apigatewayv2_domain_name.domain.domain_name_configuration[0].target_domain_name
+    zone_id                = aws_apigatewayv2_domain_name.domain.domain_name_configuration[0].hosted_zone_id
+    evaluate_target_health = false
+  }
+}
+`, hclString(config.Domain))
+}
+
+// generateELBRecord points the domain at the Kubernetes service load balancer: a Classic ELB,
+// or an NLB with the AWS Load Balancer Controller. TLS is terminated by the load balancer (see
+// generateServiceAnnotations).
+func (g *Generator) generateELBRecord(config *types.TerraformConfig) string {
+	zone := `
+# Hosted zone of the Classic ELB created for the Kubernetes service
+data "aws_elb_hosted_zone_id" "main" {}
+`
+	if UsesLBController(config) {
+		zone = `
+# Hosted zone of the NLB created for the Kubernetes service
+data "aws_elb_hosted_zone_id" "main" {
+  load_balancer_type = "network"
+}
+`
+	}
+
+	return zone + fmt.Sprintf(`
+resource "aws_route53_record" "domain" {
+  zone_id = data.aws_route53_zone.domain.zone_id
+  name    = %s
+  type    = "A"
+
+  alias {
+    name                   = kubernetes_service.app.status.0.load_balancer.0.ingress.0.hostname
+    zone_id                = data.aws_elb_hosted_zone_id.main.id
+    evaluate_target_health = true
+  }
+}
+`, hclString(config.Domain))
+}
+
+// generateServiceTLSPort returns the HTTPS port of the Kubernetes service (empty without a custom domain)
+func (g *Generator) generateServiceTLSPort(config *types.TerraformConfig) string {
+	if config.Domain == "" {
+		return ""
+	}
+
+	return fmt.Sprintf(`
+    port {
+      name        = "https"
+      port        = 443
+      target_port = %d
+      protocol    = "TCP"
+    }
+`, config.Port)
+}
+
+// healthCheckPath returns the load balancer health check path, "/" when none was detected
+func healthCheckPath(config *types.TerraformConfig) string {
+	if config.HealthCheckPath == "" {
+		return "/"
+	}
+	return config.HealthCheckPath
+}
+
+// lbName builds a valid load balancer or target group name ("<app>-<suffix>", max 32 characters)
+func lbName(appName, suffix string) string {
+	name := strings.Trim(invalidLBNameChars.ReplaceAllString(appName, "-"), "-")
+	maxApp := maxLBNameLength - len(suffix) - 1
+	if len(name) > maxApp {
+		name = strings.TrimRight(name[:maxApp], "-")
+	}
+	return name + "-" + suffix
+}
@@ -0,0 +1,150 @@
+package terraform
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/Smana/scai/internal/types"
+)
+
+// UserDataFile is the file the custom user-data of the instances is written to, next to main.tf
+const UserDataFile = "user-data.sh"
+
+// MaxUserDataSize is the EC2 limit on the size of the user-data (before base64 encoding)
+const MaxUserDataSize = 16 * 1024
+
+// amiIDPattern matches an AMI ID
+var amiIDPattern = regexp.MustCompile(`^ami-[0-9a-f]{8}([0-9a-f]{9})?$`)
+
+// ValidateAMIID checks that id looks like an AMI ID (e.g. ami-0abcdef1234567890)
+func ValidateAMIID(id string) error {
+	if !amiIDPattern.MatchString(id) {
+		return fmt.Errorf("invalid AMI ID %q: expected e.g. ami-0abcdef1234567890", id)
+	}
+	return nil
+}
+
+// ValidateUserData checks that a custom user-data script fits in the EC2 limit
+func ValidateUserData(userData string) error {
+	if userData == "" {
+		return fmt.Errorf("user-data is empty")
+	}
+	if len(userData) > MaxUserDataSize {
+		return fmt.Errorf("user-data is %d bytes, EC2 accepts at most %d", len(userData), MaxUserDataSize)
+	}
+	return nil
+}
+
+// generateAMIData generates the data source of the AMI of the instances: the latest Amazon
+// Linux 2023, or config.AMI (its root device name differs between distributions)
+func (g *Generator) generateAMIData(config *types.TerraformConfig) string {
+	if config.AMI != "" {
+		return fmt.Sprintf(`# Custom AMI
+data "aws_ami" "custom" {
+  filter {
+    name   = "image-id"
+    values = [%s]
+  }
+}`, hclString(config.AMI))
+	}
+
+	return `# Get latest Amazon Linux 2023 AMI
+data "aws_ami" "amazon_linux_2023" {
+  most_recent = true
+  owners      = ["amazon"]
+
+  filter {
+    name   = "name"
+    values = ["al2023-ami-*-x86_64"]
+  }
+
+  filter {
+    name   = "virtualization-type"
+    values = ["hvm"]
+  }
+}`
+}
+
+// amiReferences returns the HCL expressions of the AMI ID and of its root device name
+func amiReferences(config *types.TerraformConfig) (imageID, rootDevice string) {
+	if config.AMI != "" {
+		return "data.aws_ami.custom.id", "data.aws_ami.custom.root_device_name"
+	}
+	return "data.aws_ami.amazon_linux_2023.id", `"/dev/xvda"`
+}
+
+// UserDataScript returns the user-data script of the EC2 instances: config.UserData, or the
+// script generated for the app
+func UserDataScript(config *types.TerraformConfig) string {
+	if config.UserData != "" {
+		return config.UserData
+	}
+	return (&Generator{}).generateUserData(config)
+}
+
+// generateUserDataArgument returns the user_data argument of the launch template: the script
+// generated for the app, or config.UserData, written to UserDataFile (read with file(), so
+// that the script is not interpolated by Terraform)
+func (g *Generator) generateUserDataArgument(config *types.TerraformConfig) string {
+	if config.UserData != "" {
+		return fmt.Sprintf(`  # Custom user-data (--user-data)
+  user_data = base64encode(file("${path.module}/%s"))`, UserDataFile)
+	}
+
+	return fmt.Sprintf(`  user_data = base64encode(<<-EOF
+%s
+  EOF
+  )`, g.generateUserData(config))
+}
+
+// generateDependencyInstall returns the user-data lines installing the language runtime and the
+// dependencies of the app. A custom AMI is expected to provide git and the runtime: only the
+// dependencies of the app are installed, without the Amazon Linux packages.
+func (g *Generator) generateDependencyInstall(config *types.TerraformConfig) string {
+	if config.AMI != "" {
+		return fmt.Sprintf(`# Install dependencies based on language (git and the runtime come with the custom AMI)
+case "%s" in
+  python|Python)
+    pip3 install -r requirements.txt || echo "No requirements.txt found"
+    ;;
+  javascript|node*)
+    npm install || echo "No package.json found"
+    ;;
+  go|Go)
+    go mod download || echo "No go.mod found"
+    ;;
+esac`, config.Language)
+	}
+
+	return fmt.Sprintf(`# Install dependencies based on language
+case "%s" in
+  python|Python)
+    yum install -y python3 python3-pip
+    pip3 install -r requirements.txt || echo "No requirements.txt found"
+    ;;
+  javascript|node*)
+    curl -fsSL https://rpm.nodesource.com/setup_18.x | bash -
+    yum install -y nodejs
+    npm install || echo "No package.json found"
+    ;;
+  go|Go)
+    yum install -y golang
+    go mod download || echo "No go.mod found"
+    ;;
+  java|Java)
+    yum install -y java-21-amazon-corretto-devel
+    if [ -f pom.xml ] && [ ! -f mvnw ]; then
+      yum install -y maven
+    fi
+    ;;
+esac`, config.Language)
+}
+
+// userDataHome returns the directory the app is cloned into: the home of ec2-user on Amazon
+// Linux, /opt/scai on a custom AMI, which may not have that user
+func userDataHome(config *types.TerraformConfig) string {
+	if config.AMI != "" {
+		return "/opt/scai"
+	}
+	return "/home/ec2-user"
+}
@@ -0,0 +1,89 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Smana/scai/internal/types"
+)
+
+func TestEC2Image(t *testing.T) {
+	tests := []struct {
+		name         string
+		config       *types.TerraformConfig
+		wantImage    string
+		wantDevice   string
+		wantUserData string
+		wantYum      bool
+	}{
+		{
+			name:         "amazon linux",
+			config:       &types.TerraformConfig{AppName: "app", Language: "python"},
+			wantImage:    "image_id          = data.aws_ami.amazon_linux_2023.id",
+			wantDevice:   `device_name = "/dev/xvda"`,
+			wantUserData: "cd /home/ec2-user",
+			wantYum:      true,
+		},
+		{
+			name:         "custom AMI",
+			config:       &types.TerraformConfig{AppName: "app", Language: "python", AMI: "ami-0abcdef1234567890"},
+			wantImage:    "image_id          = data.aws_ami.custom.id",
+			wantDevice:   "device_name = data.aws_ami.custom.root_device_name",
+			wantUserData: "cd /opt/scai",
+		},
+		{
+			name:         "custom user-data",
+			config:       &types.TerraformConfig{AppName: "app", UserData: "#!/bin/bash\necho ${HOME}\n"},
+			wantImage:    "image_id          = data.aws_ami.amazon_linux_2023.id",
+			wantDevice:   `device_name = "/dev/xvda"`,
+			wantUserData: `user_data = base64encode(file("${path.module}/user-data.sh"))`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := NewGenerator(dir, false).generateEC2Config(tt.config); err != nil {
+				t.Fatalf("generateEC2Config() error = %v", err)
+			}
+			content, err := os.ReadFile(filepath.Join(dir, "main.tf"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			mainTF := string(content)
+
+			for _, want := range []string{tt.wantImage, tt.wantDevice, tt.wantUserData} {
+				if !strings.Contains(mainTF, want) {
+					t.Errorf("main.tf does not contain %q", want)
+				}
+			}
+			if got := strings.Contains(mainTF, "yum install"); got != tt.wantYum {
+				t.Errorf("main.tf installs Amazon Linux packages = %v, want %v", got, tt.wantYum)
+			}
+
+			userData, err := os.ReadFile(filepath.Join(dir, UserDataFile))
+			if tt.config.UserData == "" {
+				if err == nil {
+					t.Errorf("%s written without custom user-data", UserDataFile)
+				}
+			} else if string(userData) != tt.config.UserData {
+				t.Errorf("%s = %q, want %q", UserDataFile, userData, tt.config.UserData)
+			}
+		})
+	}
+}
+
+func TestValidateAMIID(t *testing.T) {
+	for _, id := range []string{"ami-12345678", "ami-0abcdef1234567890"} {
+		if err := ValidateAMIID(id); err != nil {
+			t.Errorf("ValidateAMIID(%q) error = %v", id, err)
+		}
+	}
+	for _, id := range []string{"", "ami-123", "ami-0ABCDEF1234567890", "i-0abcdef1234567890"} {
+		if err := ValidateAMIID(id); err == nil {
+			t.Errorf("ValidateAMIID(%q) succeeded", id)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package terraform
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Smana/scai/internal/types"
+)
+
+// EKSClusterAdminPolicy is the access policy granted to the additional cluster administrators
+const EKSClusterAdminPolicy = "arn:aws:eks::aws:cluster-access-policy/AmazonEKSClusterAdminPolicy"
+
+// principalARNPattern matches IAM user and role ARNs
+var principalARNPattern = regexp.MustCompile(`^arn:aws[\w-]*:iam::\d{12}:(user|role)/[\w+=,.@/-]+$`)
+
+// ValidatePrincipalARN checks that arn is an IAM user or role ARN
+// (e.g. arn:aws:iam::123456789012:role/platform-team)
+func ValidatePrincipalARN(arn string) error {
+	if !principalARNPattern.MatchString(arn) {
+		return fmt.Errorf("invalid IAM principal ARN %q: expected a user or role, e.g. arn:aws:iam::123456789012:role/platform-team", arn)
+	}
+	return nil
+}
+
+// generateEKSAccessEntries generates the access entries of the EKS module granting cluster
+// admin to config.EKSAdminARNs, besides the identity creating the cluster. Empty without
+// additional administrators.
+func (g *Generator) generateEKSAccessEntries(config *types.TerraformConfig) string {
+	if len(config.EKSAdminARNs) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("\n  # Additional cluster administrators (--eks-admin-arn)\n  access_entries = {\n")
+	for i, arn := range config.EKSAdminARNs {
+		fmt.Fprintf(&sb, `    admin_%d = {
+      principal_arn = %s
+
+      policy_associations = {
+        admin = {
+          policy_arn   = "%s"
+          access_scope = {
+            type = "cluster"
+          }
+        }
+      }
+    }
+`, i, hclString(arn), EKSClusterAdminPolicy)
+	}
+	sb.WriteString("  }\n")
+	return sb.String()
+}
@@ -0,0 +1,76 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Smana/scai/internal/types"
+)
+
+func TestValidatePrincipalARN(t *testing.T) {
+	tests := []struct {
+		arn     string
+		wantErr bool
+	}{
+		{"arn:aws:iam::123456789012:role/platform-team", false},
+		{"arn:aws:iam::123456789012:role/teams/sre", false},
+		{"arn:aws:iam::123456789012:user/alice", false},
+		{"arn:aws-us-gov:iam::123456789012:role/admin", false},
+		{"arn:aws:iam::aws:policy/AdministratorAccess", true},
+		{"arn:aws:iam::123456789012:group/admins", true},
+		{"arn:aws:sts::123456789012:assumed-role/admin/session", true},
+		{"", true},
+	}
+
+	for _, tt := range tests {
+		if err := ValidatePrincipalARN(tt.arn); (err != nil) != tt.wantErr {
+			t.Errorf("ValidatePrincipalARN(%q) error = %v, wantErr %v", tt.arn, err, tt.wantErr)
+		}
+	}
+}
+
+func TestEKSAccessEntries(t *testing.T) {
+	g := NewGenerator(t.TempDir(), false)
+	if got := g.generateEKSAccessEntries(&types.TerraformConfig{AppName: "web"}); got != "" {
+		t.Errorf("generateEKSAccessEntries() without admins = %q, want none", got)
+	}
+
+	dir := t.TempDir()
+	config := &types.TerraformConfig{
+		Strategy:          "kubernetes",
+		AppName:           "web",
+		Region:            "eu-west-3",
+		Language:          "python",
+		Port:              8080,
+		EKSNodeType:       "t3.medium",
+		EKSMinNodes:       1,
+		EKSMaxNodes:       3,
+		EKSDesiredNodes:   2,
+		EKSNodeVolumeSize: 20,
+		EKSAdminARNs:      []string{"arn:aws:iam::123456789012:role/platform-team", "arn:aws:iam::123456789012:user/alice"},
+	}
+	if err := NewGenerator(dir, false).Generate(config); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	mainTF, err := os.ReadFile(filepath.Join(dir, "main.tf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"enable_cluster_creator_admin_permissions = true",
+		"access_entries = {",
+		`principal_arn = "arn:aws:iam::123456789012:role/platform-team"`,
+		`principal_arn = "arn:aws:iam::123456789012:user/alice"`,
+		`policy_arn   = "` + EKSClusterAdminPolicy + `"`,
+		`type = "cluster"`,
+	} {
+		if !strings.Contains(string(mainTF), want) {
+			t.Errorf("main.tf missing %q", want)
+		}
+	}
+	if got := strings.Count(string(mainTF), "principal_arn"); got != 2 {
+		t.Errorf("main.tf has %d access entries, want 2", got)
+	}
+}
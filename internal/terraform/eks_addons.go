@@ -0,0 +1,149 @@
+package terraform
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Smana/scai/internal/types"
+)
+
+// EKS managed add-ons supported by the generator
+const (
+	addonVPCCNI           = "vpc-cni"
+	addonCoreDNS          = "coredns"
+	addonKubeProxy        = "kube-proxy"
+	EBSCSIDriverAddon     = "aws-ebs-csi-driver" // Provisions the EBS volumes of PersistentVolumeClaims
+	addonPodIdentityAgent = "eks-pod-identity-agent"
+)
+
+// DefaultEKSAddons are the managed add-ons installed when none are configured
+var DefaultEKSAddons = []string{addonVPCCNI, addonCoreDNS, addonKubeProxy, EBSCSIDriverAddon}
+
+// knownEKSAddons lists the add-ons that can be configured
+var knownEKSAddons = []string{addonVPCCNI, addonCoreDNS, addonKubeProxy, EBSCSIDriverAddon, addonPodIdentityAgent}
+
+// nodeOnlyAddons run as DaemonSets on nodes, which Fargate does not have
+var nodeOnlyAddons = map[string]bool{EBSCSIDriverAddon: true, addonPodIdentityAgent: true}
+
+// ValidateEKSAddon checks that name is a supported EKS managed add-on
+func ValidateEKSAddon(name string) error {
+	for _, known := range knownEKSAddons {
+		if name == known {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported EKS add-on %q (supported: %s)", name, strings.Join(knownEKSAddons, ", "))
+}
+
+// EKSAddons returns the names of the add-ons installed on the cluster, sorted: the configured
+// add-ons (DefaultEKSAddons when addons is nil), with the Pod Identity agent the EBS CSI driver
+// and, when appPodIdentity, the application authenticate with, and without the add-ons that
+// need nodes on Fargate
+func EKSAddons(addons map[string]string, fargate, appPodIdentity bool) []string {
+	names := make(map[string]bool)
+	if addons == nil {
+		for _, name := range DefaultEKSAddons {
+			names[name] = true
+		}
+	}
+	for name := range addons {
+		names[name] = true
+	}
+	if names[EBSCSIDriverAddon] || appPodIdentity {
+		names[addonPodIdentityAgent] = true
+	}
+
+	result := make([]string, 0, len(names))
+	for name := range names {
+		if fargate && nodeOnlyAddons[name] {
+			continue
+		}
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// generateEKSAddons generates the addons block of the EKS module: each add-on is pinned to
+// its configured version or tracks the most recent one
+func (g *Generator) generateEKSAddons(config *types.TerraformConfig) string {
+	names := EKSAddons(config.EKSAddons, config.EKSFargate, AppPodIdentity(config.AppPolicyARNs, config.EKSFargate))
+	if len(names) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("  # EKS managed add-ons\n  addons = {\n")
+	for _, name := range names {
+		settings := [][2]string{{"most_recent", "true"}}
+		if version := config.EKSAddons[name]; version != "" {
+			settings = [][2]string{{"addon_version", fmt.Sprintf("%q", version)}}
+		}
+
+		switch name {
+		case addonVPCCNI:
+			// Nodes need the CNI to become ready
+			settings = append(settings, [2]string{"before_compute", "true"})
+		case addonCoreDNS:
+			if config.EKSFargate {
+				// CoreDNS must be scheduled on Fargate as there are no nodes
+				settings = append(settings, [2]string{"configuration_values", `jsonencode({ computeType = "fargate" })`})
+			}
+		}
+
+		// Align the values like terraform fmt
+		width := 0
+		for _, setting := range settings {
+			width = max(width, len(setting[0]))
+		}
+		fmt.Fprintf(&b, "    %s = {\n", name)
+		for _, setting := range settings {
+			fmt.Fprintf(&b, "      %-*s = %s\n", width, setting[0], setting[1])
+		}
+		b.WriteString("    }\n")
+	}
+	b.WriteString("  }\n\n")
+	return b.String()
+}
+
+// generateEBSCSIPodIdentity generates the IAM role of the EBS CSI controller, associated with
+// its service account through EKS Pod Identity (empty when the driver is not installed)
+func (g *Generator) generateEBSCSIPodIdentity(config *types.TerraformConfig, k8sAppName string) string {
+	installed := false
+	for _, name := range EKSAddons(config.EKSAddons, config.EKSFargate, AppPodIdentity(config.AppPolicyARNs, config.EKSFargate)) {
+		installed = installed || name == EBSCSIDriverAddon
+	}
+	if !installed {
+		return ""
+	}
+
+	return fmt.Sprintf(`
+# IAM role of the EBS CSI driver, which provisions the EBS volumes of PersistentVolumeClaims
+module "ebs_csi_pod_identity" {
+  source  = "terraform-aws-modules/eks-pod-identity/aws"
+  version = "~> 2.0"
+
+  name = "%s-ebs-csi"
+
+  attach_aws_ebs_csi_policy = true
+
+  associations = {
+    ebs_csi = {
+      cluster_name    = module.eks.cluster_name
+      namespace       = "kube-system"
+      service_account = "ebs-csi-controller-sa"
+    }
+  }
+
+  tags = {
+    Name        = "%s-ebs-csi"
+    Environment = "production"
+    ManagedBy   = "SCAI"
+  }
+}
+`,
+		k8sAppName, // role name
+		k8sAppName, // role tags
+	)
+}
@@ -0,0 +1,41 @@
+package terraform
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestEKSAddons(t *testing.T) {
+	tests := []struct {
+		name    string
+		addons  map[string]string
+		fargate bool
+		app     bool
+		want    []string
+	}{
+		{"defaults", nil, false, false, []string{"aws-ebs-csi-driver", "coredns", "eks-pod-identity-agent", "kube-proxy", "vpc-cni"}},
+		{"defaults on fargate", nil, true, false, []string{"coredns", "kube-proxy", "vpc-cni"}},
+		{"without ebs csi driver", map[string]string{"vpc-cni": "", "coredns": "v1.12.1-eksbuild.2"}, false, false, []string{"coredns", "vpc-cni"}},
+		{"app pod identity", map[string]string{"vpc-cni": ""}, false, true, []string{"eks-pod-identity-agent", "vpc-cni"}},
+		{"none", map[string]string{}, false, false, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EKSAddons(tt.addons, tt.fargate, tt.app); !slices.Equal(got, tt.want) {
+				t.Errorf("EKSAddons() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateEKSAddon(t *testing.T) {
+	for _, name := range DefaultEKSAddons {
+		if err := ValidateEKSAddon(name); err != nil {
+			t.Errorf("ValidateEKSAddon(%q) error = %v", name, err)
+		}
+	}
+	if err := ValidateEKSAddon("aws-ebs-csi"); err == nil {
+		t.Error("ValidateEKSAddon() expected an error for an unknown add-on")
+	}
+}
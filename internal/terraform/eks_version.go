@@ -0,0 +1,45 @@
+package terraform
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// EKS Kubernetes versions: the default for new clusters and the supported range
+// (standard and extended support). Bump these as AWS releases and retires versions.
+const (
+	DefaultEKSVersion = "1.33"
+	MinEKSVersion     = "1.30"
+	MaxEKSVersion     = "1.34"
+)
+
+// ValidateEKSVersion checks that version is a Kubernetes minor version (e.g. 1.33)
+// within the supported EKS range
+func ValidateEKSVersion(version string) error {
+	minor, err := eksMinorVersion(version)
+	if err != nil {
+		return err
+	}
+
+	lowest, _ := eksMinorVersion(MinEKSVersion)
+	highest, _ := eksMinorVersion(MaxEKSVersion)
+	if minor < lowest || minor > highest {
+		return fmt.Errorf("EKS version %s is not supported (supported: %s to %s)", version, MinEKSVersion, MaxEKSVersion)
+	}
+	return nil
+}
+
+// eksMinorVersion returns the minor version of a "1.NN" Kubernetes version
+func eksMinorVersion(version string) (int, error) {
+	major, minor, ok := strings.Cut(version, ".")
+	if !ok || major != "1" {
+		return 0, fmt.Errorf("invalid EKS version %q: expected a Kubernetes version such as %s", version, DefaultEKSVersion)
+	}
+
+	n, err := strconv.Atoi(minor)
+	if err != nil {
+		return 0, fmt.Errorf("invalid EKS version %q: expected a Kubernetes version such as %s", version, DefaultEKSVersion)
+	}
+	return n, nil
+}
@@ -1,20 +1,30 @@
 package terraform
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
+
+	"github.com/Smana/scai/internal/console"
 )
 
+// StopTimeout is how long a canceled terraform command may take to stop gracefully after
+// being interrupted (releasing the state lock, saving the state), before it is killed
+const StopTimeout = 5 * time.Minute
+
 // Executor handles Terraform/OpenTofu command execution
 type Executor struct {
 	workDir string
 	tfBin   string
 	verbose bool
+	ctx     context.Context // Cancels the running commands (interrupt, --timeout)
 }
 
 // NewExecutor creates a new Terraform executor with path validation
@@ -29,9 +39,31 @@ func NewExecutor(workDir, tfBin string, verbose bool) (*Executor, error) {
 		workDir: workDir,
 		tfBin:   validatedBin,
 		verbose: verbose,
+		ctx:     context.Background(),
 	}, nil
 }
 
+// SetContext sets the context of the terraform commands: once it is canceled, the running
+// command is interrupted and given StopTimeout to stop gracefully
+func (e *Executor) SetContext(ctx context.Context) {
+	e.ctx = ctx
+}
+
+// command returns a terraform command run in the working directory, interrupted when the
+// context of the executor is canceled. With a cancelable context, it runs in its own process
+// group, so that a Ctrl-C in the terminal only reaches terraform through the cancellation (a
+// second interrupt makes terraform exit immediately, without releasing its state lock).
+func (e *Executor) command(args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(e.ctx, e.tfBin, args...)
+	cmd.Dir = e.workDir
+	cmd.Cancel = func() error { return interruptProcess(cmd.Process) }
+	cmd.WaitDelay = StopTimeout
+	if e.ctx.Done() != nil {
+		setProcessGroup(cmd)
+	}
+	return cmd
+}
+
 // validateTerraformBinary ensures the binary is safe to execute
 func validateTerraformBinary(bin string) (string, error) {
 	// Allow only specific binary names
@@ -74,6 +106,12 @@ func (e *Executor) Init() error {
 	return e.runCommand(args...)
 }
 
+// InitMigrateState re-initializes Terraform on a changed backend configuration, copying the
+// state of the previous backend to the new one without prompting
+func (e *Executor) InitMigrateState() error {
+	return e.runCommand("init", "-migrate-state", "-force-copy", "-input=false")
+}
+
 // Plan runs terraform plan
 func (e *Executor) Plan() error {
 	args := []string{"plan", "-input=false"}
@@ -104,10 +142,10 @@ func (e *Executor) Destroy() error {
 	return e.runCommand(args...)
 }
 
-// Outputs retrieves terraform outputs as a map
+// Outputs retrieves terraform outputs as a map. Generated configurations always define
+// AppURLOutput; null outputs (e.g. the VM URL, only known once the instance is up) are omitted.
 func (e *Executor) Outputs() (map[string]string, error) {
-	cmd := exec.Command(e.tfBin, "output", "-json")
-	cmd.Dir = e.workDir
+	cmd := e.command("output", "-json")
 
 	output, err := cmd.CombinedOutput()
 	if err != nil {
@@ -134,6 +172,8 @@ func (e *Executor) Outputs() (map[string]string, error) {
 	for key, val := range rawOutputs {
 		// Convert value to string
 		switch v := val.Value.(type) {
+		case nil:
+			continue
 		case string:
 			outputs[key] = v
 		case float64:
@@ -152,19 +192,22 @@ func (e *Executor) Outputs() (map[string]string, error) {
 
 // runCommand executes a terraform command
 func (e *Executor) runCommand(args ...string) error {
-	cmd := exec.Command(e.tfBin, args...)
-	cmd.Dir = e.workDir
+	cmd := e.command(args...)
+	if console.Plain() {
+		cmd.Env = append(os.Environ(), "TF_CLI_ARGS="+strings.TrimSpace(os.Getenv("TF_CLI_ARGS")+" -no-color"))
+	}
 
 	if e.verbose {
-		fmt.Printf("   Executing: %s %s\n", e.tfBin, strings.Join(args, " "))
-		// Stream output in real-time to stdout/stderr
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
+		fmt.Fprintf(console.Stdout, "   Executing: %s %s\n", e.tfBin, strings.Join(args, " "))
+		// Stream output in real-time to stdout/stderr, keeping the errors for RetryableError
+		var stderr bytes.Buffer
+		cmd.Stdout = console.Stdout
+		cmd.Stderr = io.MultiWriter(os.Stderr, &stderr)
 
 		// Run command with live output
 		if err := cmd.Run(); err != nil {
-			return fmt.Errorf("command failed: %s %s\nError: %w",
-				e.tfBin, strings.Join(args, " "), err)
+			return &commandError{err: fmt.Errorf("command failed: %s %s\nError: %w",
+				e.tfBin, strings.Join(args, " "), err), output: stderr.String()}
 		}
 		return nil
 	}
@@ -172,8 +215,8 @@ func (e *Executor) runCommand(args ...string) error {
 	// Non-verbose mode: capture output
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return fmt.Errorf("command failed: %s %s\nError: %w\nOutput: %s",
-			e.tfBin, strings.Join(args, " "), err, string(output))
+		return &commandError{err: fmt.Errorf("command failed: %s %s\nError: %w\nOutput: %s",
+			e.tfBin, strings.Join(args, " "), err, string(output)), output: string(output)}
 	}
 
 	return nil
@@ -191,8 +234,7 @@ func (e *Executor) Validate() error {
 
 // GetState retrieves the current terraform state
 func (e *Executor) GetState() (string, error) {
-	cmd := exec.Command(e.tfBin, "show", "-json")
-	cmd.Dir = e.workDir
+	cmd := e.command("show", "-json")
 
 	output, err := cmd.CombinedOutput()
 	if err != nil {
@@ -4,8 +4,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 
+	"github.com/Smana/scai/internal/cloud"
 	"github.com/Smana/scai/internal/types"
 )
 
@@ -16,6 +19,7 @@ const (
 	runtimePython    = "python3.12"
 	imageNode        = "node:20-alpine"
 	imagePython      = "python:3.12-slim"
+	imageJava        = "eclipse-temurin:21-jdk"
 	frameworkFlask   = "flask"
 	frameworkDjango  = "django"
 	frameworkFastAPI = "fastapi"
@@ -50,16 +54,36 @@ func (g *Generator) Generate(config *types.TerraformConfig) error {
 	}
 
 	// Generate strategy-specific configuration
+	var err error
 	switch config.Strategy {
 	case "vm":
-		return g.generateEC2Config(config)
+		err = g.generateEC2Config(config)
 	case "kubernetes":
-		return g.generateEKSConfig(config)
+		err = g.generateEKSConfig(config)
 	case "serverless":
-		return g.generateLambdaConfig(config)
+		err = g.generateLambdaConfig(config)
 	default:
 		return fmt.Errorf("unknown deployment strategy: %s", config.Strategy)
 	}
+	if err != nil {
+		return err
+	}
+
+	// Provider version constraints, pinned per deployment
+	if err := g.generateVersionsConfig(config); err != nil {
+		return err
+	}
+
+	// Optional resources go in their own files next to main.tf
+	if config.DatabaseEngine != "" {
+		if err := g.generateDatabaseConfig(config); err != nil {
+			return err
+		}
+	}
+	if config.Domain != "" {
+		return g.generateDomainConfig(config)
+	}
+	return nil
 }
 
 // copyModules copies OpenTofu modules to the work directory
@@ -69,56 +93,119 @@ func (g *Generator) copyModules() error {
 	return nil
 }
 
-// generateEC2Config generates EC2 configuration using terraform-aws-modules/autoscaling
-func (g *Generator) generateEC2Config(config *types.TerraformConfig) error {
-	// Generate user-data script
-	userData := g.generateUserData(config)
+// DefaultTags returns the tags applied to every resource of a deployment: the SCAI tracking
+// tags and the user-supplied ones
+func DefaultTags(config *types.TerraformConfig) map[string]string {
+	tags := map[string]string{
+		"ManagedBy": "SCAI",
+		"scia:app":  config.AppName,
+	}
+	if config.DeploymentID != "" {
+		tags["scia:deployment-id"] = config.DeploymentID
+	}
 
-	mainTF := fmt.Sprintf(`# EC2 Deployment for %s using terraform-aws-modules/autoscaling
-# Generated by SCAI
+	// User-supplied tags may add keys but never override the SCAI tracking tags
+	for key, value := range config.Tags {
+		if _, reserved := tags[key]; reserved {
+			continue
+		}
+		tags[key] = value
+	}
+	return tags
+}
 
-terraform {
-  required_version = ">= 1.0"
-  required_providers {
-    aws = {
-      source  = "hashicorp/aws"
-      version = "~> 6.0"
-    }
-  }
+// generateAWSProvider renders the AWS provider block with default tags.
+// default_tags propagates to every taggable resource, so deployed infrastructure
+// can be traced back to its SCAI deployment even without the local database.
+func (g *Generator) generateAWSProvider(config *types.TerraformConfig) string {
+	tags := DefaultTags(config)
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys) // Deterministic output
+
+	var sb strings.Builder
+	sb.WriteString("provider \"aws\" {\n")
+	fmt.Fprintf(&sb, "  region = %s\n\n", hclString(config.Region))
+	if config.AWSRoleARN != "" {
+		// The same identity as the AWS clients of the deploy
+		sb.WriteString("  assume_role {\n")
+		fmt.Fprintf(&sb, "    role_arn     = %s\n", hclString(config.AWSRoleARN))
+		fmt.Fprintf(&sb, "    session_name = %s\n", hclString(cloud.RoleSessionName))
+		sb.WriteString("  }\n\n")
+	}
+	sb.WriteString("  default_tags {\n    tags = {\n")
+	for _, key := range keys {
+		fmt.Fprintf(&sb, "      %s = %s\n", hclString(key), hclString(tags[key]))
+	}
+	sb.WriteString("    }\n  }\n}\n")
+
+	return sb.String()
 }
 
-provider "aws" {
-  region = "%s"
+// hclString quotes a value as an HCL string literal, escaping template sequences
+func hclString(value string) string {
+	quoted := strconv.Quote(value)
+	quoted = strings.ReplaceAll(quoted, "${", "$${")
+	return strings.ReplaceAll(quoted, "%{", "%%{")
 }
 
-# Get latest Amazon Linux 2023 AMI
-data "aws_ami" "amazon_linux_2023" {
-  most_recent = true
-  owners      = ["amazon"]
+// AppURLOutput is the canonical output holding the application URL, whatever the strategy.
+// Strategy-specific outputs (service_url, api_invoke_url, ...) are kept alongside it.
+const AppURLOutput = "app_url"
 
-  filter {
-    name   = "name"
-    values = ["al2023-ami-*-x86_64"]
-  }
+// generateAppURLOutput returns the app_url output: the HTTPS URL of the custom domain when
+// there is one, the strategy's URL expression otherwise (null when only known after apply)
+func (g *Generator) generateAppURLOutput(config *types.TerraformConfig, value string) string {
+	if config.Domain != "" {
+		value = hclString("https://" + config.Domain + "/")
+	}
 
-  filter {
-    name   = "virtualization-type"
-    values = ["hvm"]
-  }
+	return fmt.Sprintf(`
+output "%s" {
+  description = "Application URL (canonical output for every strategy)"
+  value       = %s
 }
-
-# Get default VPC
-data "aws_vpc" "default" {
-  default = true
+`, AppURLOutput, value)
 }
 
-# Get default subnets
-data "aws_subnets" "default" {
-  filter {
-    name   = "vpc-id"
-    values = [data.aws_vpc.default.id]
-  }
-}
+// generateEC2Config generates EC2 configuration using terraform-aws-modules/autoscaling
+func (g *Generator) generateEC2Config(config *types.TerraformConfig) error {
+	// Generated or custom user-data script
+	userData := g.generateUserDataArgument(config)
+	if config.UserData != "" {
+		if err := os.WriteFile(filepath.Join(g.outputDir, UserDataFile), []byte(config.UserData), 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", UserDataFile, err)
+		}
+	}
+
+	// Latest Amazon Linux 2023 or custom AMI
+	amiData := g.generateAMIData(config)
+	imageID, rootDevice := amiReferences(config)
+
+	// The instance URL is only known once it is up: scai sets app_url after apply
+	appURLOutput := g.generateAppURLOutput(config, "null")
+
+	// Target tracking policy on CPU (scales out up to ASGMaxSize)
+	scalingPolicy := g.generateASGScalingPolicy(config)
+
+	// SSH access only with a key pair (SSM Session Manager otherwise)
+	sshIngress := g.generateSSHIngress(config)
+	keyPair := g.generateKeyPair(config)
+	keyName := g.generateKeyName(config)
+
+	// Default VPC, or existing VPC and subnets (--vpc-id)
+	network := networkReferences(config)
+
+	mainTF := fmt.Sprintf(`# EC2 Deployment for %s using terraform-aws-modules/autoscaling
+# Generated by SCAI
+
+%s
+
+%s
+
+%s
 
 # Security Group Module
 module "security_group" {
@@ -127,7 +214,7 @@ module "security_group" {
 
   name        = "%s-sg"
   description = "Security group for %s"
-  vpc_id      = data.aws_vpc.default.id
+  vpc_id      = %s
 
   ingress_with_cidr_blocks = [
     {
@@ -136,14 +223,7 @@ module "security_group" {
       protocol    = "tcp"
       cidr_blocks = "0.0.0.0/0"
       description = "Application port"
-    },
-    {
-      from_port   = 22
-      to_port     = 22
-      protocol    = "tcp"
-      cidr_blocks = "0.0.0.0/0"
-      description = "SSH access"
-    }
+    }%s
   ]
 
   egress_with_cidr_blocks = [
@@ -200,7 +280,7 @@ resource "aws_iam_instance_profile" "ssm_profile" {
     ManagedBy = "SCAI"
   }
 }
-
+%s
 # Auto Scaling Group Module - Single instance with auto-recovery
 module "asg" {
   source  = "terraform-aws-modules/autoscaling/aws"
@@ -208,28 +288,28 @@ module "asg" {
 
   name = "%s-asg"
 
-  # Single instance configuration for auto-recovery
+  # Single instance configuration for auto-recovery (max_size > 1 with an autoscaling policy)
   min_size         = 1
-  max_size         = 1
+  max_size         = %d
   desired_capacity = 1
 
-  vpc_zone_identifier = data.aws_subnets.default.ids
+  vpc_zone_identifier = %s
 
   # Health check configuration
   health_check_type         = "EC2"
   health_check_grace_period = 300
 
   # Launch template configuration
-  image_id          = data.aws_ami.amazon_linux_2023.id
+  image_id          = %s
   instance_type     = "%s"
-  iam_instance_profile_arn = aws_iam_instance_profile.ssm_profile.arn
+  iam_instance_profile_arn = aws_iam_instance_profile.ssm_profile.arn%s
 
   security_groups = [module.security_group.security_group_id]
 
   # Root volume configuration
   block_device_mappings = [
     {
-      device_name = "/dev/xvda"
+      device_name = %s
       ebs = {
         volume_size           = %d
         volume_type           = "gp3"
@@ -239,10 +319,7 @@ module "asg" {
     }
   ]
 
-  user_data = base64encode(<<-EOF
 %s
-  EOF
-  )
 
   # Enable detailed monitoring
   enable_monitoring = true
@@ -260,7 +337,7 @@ module "asg" {
     ManagedBy   = "SCAI"
   }
 }
-
+%s
 output "asg_name" {
   description = "Auto Scaling Group name"
   value       = module.asg.autoscaling_group_name
@@ -280,23 +357,35 @@ output "application_port" {
   description = "Application port number"
   value       = "%d"
 }
-`,
-		config.AppName,           // Line 1: Comment
-		config.Region,            // provider region
-		config.AppName,           // SG name
-		config.AppName,           // SG description
-		config.Port, config.Port, // ingress ports
+%s`,
+		config.AppName,                  // Line 1: Comment
+		g.generateAWSProvider(config),   // provider block with default tags
+		amiData,                         // AMI data source
+		g.generateVMNetworkData(config), // default or existing VPC
+		config.AppName,                  // SG name
+		config.AppName,                  // SG description
+		network.VPCID,                   // SG VPC
+		config.Port, config.Port,        // ingress ports
+		sshIngress,          // SSH ingress, with a key pair only
 		config.AppName,      // SG tag
 		config.AppName,      // IAM role name prefix
 		config.AppName,      // IAM role tag
 		config.AppName,      // Instance profile name prefix
 		config.AppName,      // Instance profile tag
+		keyPair,             // generated key pair
 		config.AppName,      // ASG name
+		ASGMaxSize(config),  // ASG max size
+		network.SubnetIDs,   // ASG subnets
+		imageID,             // AMI
 		config.InstanceType, // instance type
+		keyName,             // key pair of the instances
+		rootDevice,          // root device of the AMI
 		config.VolumeSize,   // volume size
-		userData,            // user-data script
+		userData,            // user-data argument
 		config.AppName,      // instance tag
+		scalingPolicy,       // autoscaling policy
 		config.Port,         // application_port output
+		appURLOutput,        // app_url output
 	)
 
 	return os.WriteFile(filepath.Join(g.outputDir, "main.tf"), []byte(mainTF), 0o644)
@@ -312,6 +401,13 @@ func (g *Generator) generateUserData(config *types.TerraformConfig) string {
 		appDir = "/" + appDir
 	}
 
+	// A custom AMI is expected to provide git
+	home := userDataHome(config)
+	gitInstall := "\n# Install git\nyum install -y git\n"
+	if config.AMI != "" {
+		gitInstall = ""
+	}
+
 	return fmt.Sprintf(`#!/bin/bash
 set -e
 
@@ -321,38 +417,21 @@ exec 2>&1
 
 echo "Starting deployment for %s"
 echo "Framework: %s, Language: %s, AppDir: %s"
-
-# Install git
-yum install -y git
-
+%s
 # Clone repository
-cd /home/ec2-user
+mkdir -p %s
+cd %s
 git clone %s app || echo "Clone failed, continuing..."
 cd app%s || exit 1
 
-# Install dependencies based on language
-case "%s" in
-  python|Python)
-    yum install -y python3 python3-pip
-    pip3 install -r requirements.txt || echo "No requirements.txt found"
-    ;;
-  javascript|node*)
-    curl -fsSL https://rpm.nodesource.com/setup_18.x | bash -
-    yum install -y nodejs
-    npm install || echo "No package.json found"
-    ;;
-  go|Go)
-    yum install -y golang
-    go mod download || echo "No go.mod found"
-    ;;
-esac
-
+%s
+%s
 echo "Dependencies installed. Starting application..."
 
 # Create a simple script to run the app with proper host binding
-cat > /home/ec2-user/start_app.sh << 'SCRIPT'
+cat > %s/start_app.sh << 'SCRIPT'
 #!/bin/bash
-cd /home/ec2-user/app%s
+cd %s/app%s
 
 # Modify Python files to bind to 0.0.0.0 instead of 127.0.0.1
 if [ "%s" = "python" ] || [ "%s" = "Python" ]; then
@@ -361,95 +440,88 @@ if [ "%s" = "python" ] || [ "%s" = "Python" ]; then
   find . -name "*.py" -type f -exec sed -i "s/host='127\.0\.0\.1'/host='0.0.0.0'/g" {} \;
 fi
 
-# Run the application
+%s# Run the application
 %s
 SCRIPT
 
-chmod +x /home/ec2-user/start_app.sh
+chmod +x %s/start_app.sh
 
 # Run the application in the background
-nohup /home/ec2-user/start_app.sh > /var/log/app.log 2>&1 &
+nohup %s/start_app.sh > /var/log/app.log 2>&1 &
 
 echo "Application started on port %d. Check /var/log/app.log for details."
 `,
 		config.AppName,
 		config.Framework, config.Language, config.AppDir,
+		gitInstall,
+		home, home,
 		config.RepoURL,
 		appDir,
-		config.Language,
-		appDir,
+		g.generateDependencyInstall(config),
+		g.generateBuildStep(config),
+		home,
+		home, appDir,
 		config.Language, config.Language,
+		g.generateDatabaseExport(config),
 		config.StartCommand,
+		home, home,
 		config.Port,
 	)
 }
 
+// generateBuildStep returns the user-data lines building the application once its dependencies
+// are installed (e.g. npm run build for Next.js or TypeScript), empty when it needs no build
+func (g *Generator) generateBuildStep(config *types.TerraformConfig) string {
+	if config.BuildCommand == "" {
+		return ""
+	}
+	return fmt.Sprintf("\n# Build the application\necho \"Building application...\"\n%s\n", config.BuildCommand)
+}
+
 // generateEKSConfig generates EKS configuration using terraform-aws-modules/eks
 func (g *Generator) generateEKSConfig(config *types.TerraformConfig) error {
-	// Determine container image based on language
-	containerImage := g.detectContainerImage(config.Language, config.Framework)
+	// Application image built from the repository and pushed to ECR
+	appImage, err := g.generateAppImage(config)
+	if err != nil {
+		return err
+	}
 
 	// Sanitize app name for Kubernetes (replace underscores with hyphens)
 	k8sAppName := strings.ReplaceAll(config.AppName, "_", "-")
 
-	mainTF := fmt.Sprintf(`# EKS Deployment for %s using terraform-aws-modules/eks
-# Generated by SCAI
+	eksVersion := config.EKSVersion
+	if eksVersion == "" {
+		eksVersion = DefaultEKSVersion
+	}
 
-terraform {
-  required_version = ">= 1.0"
-  required_providers {
-    aws = {
-      source  = "hashicorp/aws"
-      version = "~> 6.0"
-    }
-    kubernetes = {
-      source  = "hashicorp/kubernetes"
-      version = "~> 2.20"
-    }
-  }
-}
+	// Service load balancer hostname
+	appURLOutput := g.generateAppURLOutput(config, `"http://${kubernetes_service.app.status.0.load_balancer.0.ingress.0.hostname}"`)
 
-provider "aws" {
-  region = "%s"
-}
+	// HorizontalPodAutoscaler on CPU
+	hpa := g.generateHPA(config, k8sAppName)
 
-# Get available AZs
-data "aws_availability_zones" "available" {
-  state = "available"
-}
+	// Container requests and limits
+	resources := k8sResources(config)
 
-# VPC Module
-module "vpc" {
-  source  = "terraform-aws-modules/vpc/aws"
-  version = "~> 6.0"
+	// IAM role of the EBS CSI driver add-on
+	ebsCSIPodIdentity := g.generateEBSCSIPodIdentity(config, k8sAppName)
 
-  name = "%s-vpc"
-  cidr = "10.0.0.0/16"
+	// AWS Load Balancer Controller exposing the service on Fargate
+	lbController := g.generateLBController(config, k8sAppName)
 
-  azs             = slice(data.aws_availability_zones.available.names, 0, 2)
-  private_subnets = ["10.0.1.0/24", "10.0.2.0/24"]
-  public_subnets  = ["10.0.101.0/24", "10.0.102.0/24"]
+	// IAM role and service account of the application (AWS access of the pods)
+	appIdentity := g.generateAppIdentity(config, k8sAppName)
+	serviceAccount := g.generateAppServiceAccountName(config)
 
-  enable_nat_gateway   = true
-  single_nat_gateway   = true
-  enable_dns_hostnames = true
-  enable_dns_support   = true
+	// New VPC, or existing VPC and subnets (--vpc-id)
+	network := networkReferences(config)
 
-  # EKS requires specific tags on subnets
-  public_subnet_tags = {
-    "kubernetes.io/role/elb" = "1"
-  }
+	mainTF := fmt.Sprintf(`# EKS Deployment for %s using terraform-aws-modules/eks
+# Generated by SCAI
 
-  private_subnet_tags = {
-    "kubernetes.io/role/internal-elb" = "1"
-  }
+%s
 
-  tags = {
-    Name        = "%s-vpc"
-    Environment = "production"
-    ManagedBy   = "SCAI"
-  }
-}
+%s
 
 # EKS Module
 module "eks" {
@@ -457,7 +529,7 @@ module "eks" {
   version = "~> 21.0"
 
   name               = "%s-eks"
-  kubernetes_version = "1.31"
+  kubernetes_version = "%s"
 
   # Cluster endpoint access
   cluster_endpoint_config = {
@@ -466,51 +538,23 @@ module "eks" {
 
   # Enable cluster creator admin permissions
   enable_cluster_creator_admin_permissions = true
-
+%s
   # VPC and subnet configuration
-  vpc_id                   = module.vpc.vpc_id
-  subnet_ids               = module.vpc.private_subnets
-  control_plane_subnet_ids = module.vpc.private_subnets
+  vpc_id                   = %s
+  subnet_ids               = %s
+  control_plane_subnet_ids = %s
 
-  # EKS Managed Node Group
-  eks_managed_node_groups = {
-    default = {
-      name = "%s-node-group"
-
-      instance_types = ["%s"]
-      capacity_type  = "ON_DEMAND"
-
-      min_size     = %d
-      max_size     = %d
-      desired_size = %d
-
-      block_device_mappings = {
-        xvda = {
-          device_name = "/dev/xvda"
-          ebs = {
-            volume_size           = %d
-            volume_type           = "gp3"
-            delete_on_termination = true
-            encrypted             = true
-          }
-        }
-      }
-
-      tags = {
-        Name        = "%s-node"
-        Environment = "production"
-        ManagedBy   = "SCAI"
-      }
-    }
-  }
+  # Control plane logs (api, audit, authenticator)
+  cloudwatch_log_group_retention_in_days = %d
 
+%s%s
   tags = {
     Name        = "%s-eks"
     Environment = "production"
     ManagedBy   = "SCAI"
   }
 }
-
+%s
 # Configure Kubernetes provider
 provider "kubernetes" {
   host                   = module.eks.cluster_endpoint
@@ -523,6 +567,7 @@ provider "kubernetes" {
   }
 }
 
+%s
 # Kubernetes Deployment
 resource "kubernetes_deployment" "app" {
   depends_on = [module.eks]
@@ -535,7 +580,7 @@ resource "kubernetes_deployment" "app" {
   }
 
   spec {
-    replicas = 2
+    replicas = %d
 
     selector {
       match_labels = {
@@ -551,7 +596,7 @@ resource "kubernetes_deployment" "app" {
       }
 
       spec {
-        container {
+%s        container {
           name  = "%s"
           image = "%s"
 
@@ -568,33 +613,33 @@ resource "kubernetes_deployment" "app" {
             name  = "REGION"
             value = "%s"
           }
-
+%s
           resources {
             requests = {
-              cpu    = "100m"
-              memory = "128Mi"
+              cpu    = "%s"
+              memory = "%s"
             }
             limits = {
-              cpu    = "500m"
-              memory = "512Mi"
+              cpu    = "%s"
+              memory = "%s"
             }
           }
         }
       }
     }
   }
-}
+%s}
 
 # Kubernetes Service (LoadBalancer)
 resource "kubernetes_service" "app" {
-  depends_on = [kubernetes_deployment.app]
+  depends_on = %s
 
   metadata {
     name = "%s-service"
     labels = {
       app = "%s"
     }
-  }
+%s  }
 
   spec {
     type = "LoadBalancer"
@@ -604,13 +649,14 @@ resource "kubernetes_service" "app" {
     }
 
     port {
+      name        = "http"
       port        = 80
       target_port = %d
       protocol    = "TCP"
     }
-  }
+%s  }
 }
-
+%s%s
 # Outputs
 output "cluster_name" {
   description = "EKS cluster name"
@@ -629,7 +675,7 @@ output "cluster_security_group_id" {
 
 output "vpc_id" {
   description = "VPC ID"
-  value       = module.vpc.vpc_id
+  value       = %s
 }
 
 output "service_url" {
@@ -641,12 +687,117 @@ output "kubeconfig_command" {
   description = "Command to configure kubectl"
   value       = "aws eks update-kubeconfig --region %s --name ${module.eks.cluster_name}"
 }
+%s`,
+		config.AppName,                           // Comment
+		g.generateAWSProvider(config),            // provider block with default tags
+		g.generateEKSNetwork(config, k8sAppName), // VPC module or existing VPC
+		k8sAppName,                               // cluster name
+		eksVersion,                               // Kubernetes version
+		g.generateEKSAccessEntries(config),       // additional cluster administrators
+		network.VPCID,                            // cluster VPC
+		eksNodeSubnets(config),                   // node subnets
+		eksControlPlaneSubnets(config),           // control plane subnets
+		LogRetentionDays(config.Strategy, config.LogRetentionDays), // control plane log group retention
+		g.generateEKSAddons(config),                                // managed add-ons
+		g.generateEKSCompute(config),                               // node group or Fargate profile
+		k8sAppName,                                                 // eks tags
+		ebsCSIPodIdentity+lbController,                             // EBS CSI driver IAM role, load balancer controller
+		config.Region,                                              // kubectl region
+		appImage,                                                   // ECR repository and image build
+		k8sAppName,                                                 // deployment name
+		k8sAppName,                                                 // deployment label
+		Replicas(config.Replicas),                                  // deployment replicas
+		k8sAppName,                                                 // selector label
+		k8sAppName,                                                 // template label
+		serviceAccount,                                             // pods service account (application IAM role)
+		k8sAppName,                                                 // container name
+		imageURI("app"),                                            // container image (ECR)
+		config.Port,                                                // container port
+		config.AppName,                                             // env APP_NAME (keep original for env var)
+		config.Region,                                              // env REGION
+		g.generateDatabaseEnv(config),                              // env DATABASE_URL (RDS database)
+		resources.CPURequest,                                       // CPU request
+		resources.MemoryRequest,                                    // memory request
+		resources.CPULimit,                                         // CPU limit
+		resources.MemoryLimit,                                      // memory limit
+		g.generateDeploymentLifecycle(config),                      // replicas managed by the HPA
+		g.generateServiceDependsOn(config),                         // service dependencies
+		k8sAppName,                                                 // service name
+		k8sAppName,                                                 // service label
+		g.generateServiceAnnotations(config),                       // NLB (Fargate) and TLS (custom domain) annotations
+		k8sAppName,                                                 // service selector
+		config.Port,                                                // target port
+		g.generateServiceTLSPort(config),                           // HTTPS port (custom domain)
+		hpa,                                                        // HorizontalPodAutoscaler
+		appIdentity,                                                // application IAM role and service account
+		network.VPCID,                                              // vpc_id output
+		config.Region,                                              // kubeconfig command region
+		appURLOutput,                                               // app_url output
+	)
+
+	return os.WriteFile(filepath.Join(g.outputDir, "main.tf"), []byte(mainTF), 0o644)
+}
+
+// generateEKSCompute generates the EKS compute block: a managed node group,
+// or a Fargate profile when EKSFargate is set
+func (g *Generator) generateEKSCompute(config *types.TerraformConfig) string {
+	k8sAppName := strings.ReplaceAll(config.AppName, "_", "-")
+
+	if config.EKSFargate {
+		return fmt.Sprintf(`  # EKS Fargate Profile (pods run on serverless compute, no nodes to manage)
+  fargate_profiles = {
+    default = {
+      name = "%s-fargate"
+      selectors = [
+        { namespace = "default" },
+        { namespace = "kube-system" }
+      ]
+
+      tags = {
+        Name        = "%s-fargate"
+        Environment = "production"
+        ManagedBy   = "SCAI"
+      }
+    }
+  }
+`,
+			k8sAppName, // profile name
+			k8sAppName, // profile tags
+		)
+	}
+
+	return fmt.Sprintf(`  # EKS Managed Node Group
+  eks_managed_node_groups = {
+    default = {
+      name = "%s-node-group"
+
+      instance_types = ["%s"]
+      capacity_type  = "ON_DEMAND"
+
+      min_size     = %d
+      max_size     = %d
+      desired_size = %d
+
+      block_device_mappings = {
+        xvda = {
+          device_name = "/dev/xvda"
+          ebs = {
+            volume_size           = %d
+            volume_type           = "gp3"
+            delete_on_termination = true
+            encrypted             = true
+          }
+        }
+      }
+
+      tags = {
+        Name        = "%s-node"
+        Environment = "production"
+        ManagedBy   = "SCAI"
+      }
+    }
+  }
 `,
-		config.AppName,           // Comment
-		config.Region,            // provider region
-		k8sAppName,               // VPC name
-		k8sAppName,               // VPC tags
-		k8sAppName,               // cluster name
 		k8sAppName,               // node group name
 		config.EKSNodeType,       // instance type
 		config.EKSMinNodes,       // min size
@@ -654,55 +805,43 @@ output "kubeconfig_command" {
 		config.EKSDesiredNodes,   // desired size
 		config.EKSNodeVolumeSize, // volume size
 		k8sAppName,               // node tags
-		k8sAppName,               // eks tags
-		config.Region,            // kubectl region
-		k8sAppName,               // deployment name
-		k8sAppName,               // deployment label
-		k8sAppName,               // selector label
-		k8sAppName,               // template label
-		k8sAppName,               // container name
-		containerImage,           // container image
-		config.Port,              // container port
-		config.AppName,           // env APP_NAME (keep original for env var)
-		config.Region,            // env REGION
-		k8sAppName,               // service name
-		k8sAppName,               // service label
-		k8sAppName,               // service selector
-		config.Port,              // target port
-		config.Region,            // kubeconfig command region
 	)
-
-	return os.WriteFile(filepath.Join(g.outputDir, "main.tf"), []byte(mainTF), 0o644)
 }
 
 // generateLambdaConfig generates Lambda configuration using terraform-aws-modules/lambda
 func (g *Generator) generateLambdaConfig(config *types.TerraformConfig) error {
-	// Determine runtime
-	runtime := g.detectRuntime(config.Language, config.Framework)
+	// Determine runtime and architecture
+	runtime, architecture := g.detectRuntime(config.Language, config.Framework, config.LambdaArchitecture)
 	handler := g.detectHandler(config.Framework)
 
+	// Container-image functions are built from a generated Dockerfile
+	if config.LambdaContainer {
+		if err := g.generateLambdaDockerfile(runtime, handler); err != nil {
+			return err
+		}
+	}
+
 	// Build reserved concurrency configuration if specified
 	reservedConcurrency := ""
 	if config.LambdaReservedConcurrency > 0 {
 		reservedConcurrency = fmt.Sprintf("\n  reserved_concurrent_executions = %d", config.LambdaReservedConcurrency)
 	}
 
+	// Package arguments and build resources (zip or container image)
+	lambdaPackage := g.generateLambdaPackage(config, runtime, handler, architecture)
+	lambdaBuild := g.generateLambdaBuild(config, architecture)
+	appURLOutput := g.generateAppURLOutput(config, `"${module.api_gateway.api_endpoint}/"`)
+
+	// API Gateway CORS and authorizer
+	cors := g.generateCORSConfiguration(config)
+	authorizer := g.generateAuthorizer(config)
+	routeAuth := g.generateRouteAuthorization(config)
+	authorizerPermission := g.generateAuthorizerPermission(config)
+
 	mainTF := fmt.Sprintf(`# Lambda Deployment for %s using terraform-aws-modules/lambda
 # Generated by SCAI
 
-terraform {
-  required_version = ">= 1.0"
-  required_providers {
-    aws = {
-      source  = "hashicorp/aws"
-      version = "~> 6.0"
-    }
-  }
-}
-
-provider "aws" {
-  region = "%s"
-}
+%s
 
 # Lambda Function Module
 module "lambda_function" {
@@ -711,13 +850,7 @@ module "lambda_function" {
 
   function_name = "%s"
   description   = "Lambda function for %s deployed by SCAI"
-  handler       = "%s"
-  runtime       = "%s"
-
-  # Package configuration - using Docker for build
-  create_package      = false
-  local_existing_package = "${path.module}/lambda.zip"
-
+%s
   # Function configuration
   timeout     = %d
   memory_size = %d%s
@@ -729,7 +862,7 @@ module "lambda_function" {
   }
 
   # CloudWatch Logs
-  cloudwatch_logs_retention_in_days = 7
+  cloudwatch_logs_retention_in_days = %d
 
   # Enable X-Ray tracing
   tracing_mode = "Active"
@@ -756,16 +889,12 @@ module "api_gateway" {
   create_domain_records = false
 
   # CORS configuration
-  cors_configuration = {
-    allow_headers = ["*"]
-    allow_methods = ["*"]
-    allow_origins = ["*"]
-  }
-
+%s
+%s
   # Routes
   routes = {
     "ANY /{proxy+}" = {
-      integration = {
+%s      integration = {
         uri                    = module.lambda_function.lambda_function_arn
         payload_format_version = "2.0"
         timeout_milliseconds   = 30000
@@ -773,7 +902,7 @@ module "api_gateway" {
     }
 
     "ANY /" = {
-      integration = {
+%s      integration = {
         uri                    = module.lambda_function.lambda_function_arn
         payload_format_version = "2.0"
         timeout_milliseconds   = 30000
@@ -795,41 +924,8 @@ resource "aws_lambda_permission" "api_gw" {
   principal     = "apigateway.amazonaws.com"
   source_arn    = "${module.api_gateway.api_execution_arn}/*/*"
 }
-
-# Null resource to prepare Lambda package
-resource "null_resource" "lambda_package" {
-  provisioner "local-exec" {
-    command = <<-EOT
-      echo "Preparing Lambda package..."
-      mkdir -p lambda_build
-
-      # Clone repository
-      cd lambda_build
-      git clone %s app || exit 1
-      cd app
-
-      # Install dependencies based on language
-      case "%s" in
-        python|Python)
-          pip3 install -r requirements.txt -t . 2>/dev/null || echo "No requirements"
-          ;;
-        javascript|node*)
-          npm install 2>/dev/null || echo "No package.json"
-          ;;
-      esac
-
-      # Create deployment package
-      zip -r ../../lambda.zip . -x "*.git*" "*.pyc" "__pycache__/*"
-
-      echo "Lambda package created: lambda.zip"
-    EOT
-  }
-
-  triggers = {
-    always_run = timestamp()
-  }
-}
-
+%s
+%s
 output "function_name" {
   description = "Lambda function name"
   value       = module.lambda_function.lambda_function_name
@@ -849,41 +945,49 @@ output "api_invoke_url" {
   description = "API Gateway invoke URL"
   value       = "${module.api_gateway.api_endpoint}/"
 }
-`,
-		config.AppName,       // Comment
-		config.Region,        // provider region
-		config.AppName,       // function_name
-		config.AppName,       // description
-		handler,              // handler
-		runtime,              // runtime
-		config.LambdaTimeout, // timeout
-		config.LambdaMemory,  // memory_size
-		reservedConcurrency,  // reserved_concurrent_executions (optional)
-		config.AppName,       // env var APP_NAME
-		config.Region,        // env var REGION
+%s`,
+		config.AppName,                // Comment
+		g.generateAWSProvider(config), // provider block with default tags
+		config.AppName,                // function_name
+		config.AppName,                // description
+		lambdaPackage,                 // handler, runtime and package
+		config.LambdaTimeout,          // timeout
+		config.LambdaMemory,           // memory_size
+		reservedConcurrency,           // reserved_concurrent_executions (optional)
+		config.AppName,                // env var APP_NAME
+		config.Region,                 // env var REGION
+		LogRetentionDays(config.Strategy, config.LogRetentionDays), // log group retention
 		config.AppName,       // tags Name
 		config.AppName,       // API GW name
 		config.AppName,       // API GW description
+		cors,                 // cors_configuration
+		authorizer,           // Lambda authorizer (optional)
+		routeAuth,            // ANY /{proxy+} authorization (optional)
+		routeAuth,            // ANY / authorization (optional)
 		config.AppName,       // API GW tags
-		config.RepoURL,       // git clone
-		config.Language,      // case statement
+		authorizerPermission, // authorizer invoke permission (optional)
+		lambdaBuild,          // package build (zip or container image)
+		appURLOutput,         // app_url output
 	)
 
 	return os.WriteFile(filepath.Join(g.outputDir, "main.tf"), []byte(mainTF), 0o644)
 }
 
-// detectRuntime determines the Lambda runtime from language and framework
-func (g *Generator) detectRuntime(language, framework string) string {
+// detectRuntime determines the Lambda runtime from language and framework, and the
+// architecture to run it on (the requested one when the runtime supports it)
+func (g *Generator) detectRuntime(language, framework, architecture string) (string, string) {
+	var runtime string
 	switch language {
 	case langPython:
-		return runtimePython
+		runtime = runtimePython
 	case langJavaScript, langTypeScript:
-		return "nodejs20.x"
+		runtime = "nodejs20.x"
 	case "go":
-		return "provided.al2023"
+		runtime = "provided.al2023"
 	default:
-		return runtimePython // Default fallback
+		runtime = runtimePython // Default fallback
 	}
+	return runtime, lambdaArchitecture(runtime, architecture)
 }
 
 // detectHandler determines the Lambda handler from framework
@@ -923,6 +1027,9 @@ func (g *Generator) detectContainerImage(language, framework string) string {
 		return imageNode
 	case "go":
 		return "golang:1.23-alpine"
+	case "java":
+		// JDK, as the start command builds the jar
+		return imageJava
 	default:
 		// Generic fallback
 		return "nginx:alpine"
@@ -0,0 +1,160 @@
+package terraform
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/Smana/scai/internal/types"
+)
+
+// Container resources of the Kubernetes Deployment when not configured nor estimated
+const (
+	defaultK8sCPURequest    = "100m"
+	defaultK8sCPULimit      = "500m"
+	defaultK8sMemoryRequest = "128Mi"
+	defaultK8sMemoryLimit   = "512Mi"
+)
+
+// Bounds of the memory estimated from the framework: small enough estimates (e.g. Go)
+// still get room for the runtime and a traffic spike
+const (
+	minK8sMemoryRequestMB = 64
+	minK8sMemoryLimitMB   = 256
+
+	// heavyAppMemoryMB is the estimated memory from which an app gets more CPU (e.g. Django, Rails)
+	heavyAppMemoryMB = 1024
+)
+
+// K8sResources are the CPU and memory requests and limits of the application container,
+// as Kubernetes quantities (e.g. 250m, 512Mi)
+type K8sResources struct {
+	CPURequest    string
+	CPULimit      string
+	MemoryRequest string
+	MemoryLimit   string
+}
+
+// WithDefaults returns r with the unset values replaced by the default ones
+func (r K8sResources) WithDefaults() K8sResources {
+	if r.CPURequest == "" {
+		r.CPURequest = defaultK8sCPURequest
+	}
+	if r.CPULimit == "" {
+		r.CPULimit = defaultK8sCPULimit
+	}
+	if r.MemoryRequest == "" {
+		r.MemoryRequest = defaultK8sMemoryRequest
+	}
+	if r.MemoryLimit == "" {
+		r.MemoryLimit = defaultK8sMemoryLimit
+	}
+	return r
+}
+
+// K8sResourcesForMemory returns the resources of an app estimated to use minMB to maxMB:
+// the low estimate is requested, the high one is the limit
+func K8sResourcesForMemory(minMB, maxMB int) K8sResources {
+	requestMB := max(minMB, minK8sMemoryRequestMB)
+	limitMB := max(maxMB, minK8sMemoryLimitMB, requestMB)
+
+	r := K8sResources{
+		CPURequest:    defaultK8sCPURequest,
+		CPULimit:      defaultK8sCPULimit,
+		MemoryRequest: formatMebibytes(requestMB),
+		MemoryLimit:   formatMebibytes(limitMB),
+	}
+	if maxMB >= heavyAppMemoryMB {
+		r.CPURequest, r.CPULimit = "250m", "1"
+	}
+	return r
+}
+
+// formatMebibytes formats a memory quantity in Mi, or Gi when it is a whole number of Gi
+func formatMebibytes(mb int) string {
+	if mb%1024 == 0 {
+		return fmt.Sprintf("%dGi", mb/1024)
+	}
+	return fmt.Sprintf("%dMi", mb)
+}
+
+var (
+	cpuQuantityPattern    = regexp.MustCompile(`^(\d+(?:\.\d+)?)(m?)$`)
+	memoryQuantityPattern = regexp.MustCompile(`^(\d+(?:\.\d+)?)(Ki|Mi|Gi|Ti|k|M|G|T)?$`)
+)
+
+// memoryUnits are the byte multipliers of the memory quantity suffixes
+var memoryUnits = map[string]float64{
+	"": 1, "k": 1e3, "M": 1e6, "G": 1e9, "T": 1e12,
+	"Ki": 1 << 10, "Mi": 1 << 20, "Gi": 1 << 30, "Ti": 1 << 40,
+}
+
+// ValidateK8sResources checks the quantities of r (unset values are ignored) and that the
+// requests do not exceed the limits, which Kubernetes rejects
+func ValidateK8sResources(r K8sResources) error {
+	r = r.WithDefaults()
+
+	cpuRequest, err := parseCPUQuantity(r.CPURequest)
+	if err != nil {
+		return fmt.Errorf("invalid CPU request: %w", err)
+	}
+	cpuLimit, err := parseCPUQuantity(r.CPULimit)
+	if err != nil {
+		return fmt.Errorf("invalid CPU limit: %w", err)
+	}
+	memoryRequest, err := parseMemoryQuantity(r.MemoryRequest)
+	if err != nil {
+		return fmt.Errorf("invalid memory request: %w", err)
+	}
+	memoryLimit, err := parseMemoryQuantity(r.MemoryLimit)
+	if err != nil {
+		return fmt.Errorf("invalid memory limit: %w", err)
+	}
+
+	if cpuRequest > cpuLimit {
+		return fmt.Errorf("CPU request %s exceeds the CPU limit %s", r.CPURequest, r.CPULimit)
+	}
+	if memoryRequest > memoryLimit {
+		return fmt.Errorf("memory request %s exceeds the memory limit %s", r.MemoryRequest, r.MemoryLimit)
+	}
+	return nil
+}
+
+// parseCPUQuantity returns a CPU quantity (e.g. 250m, 0.5, 1) in millicores
+func parseCPUQuantity(quantity string) (float64, error) {
+	matches := cpuQuantityPattern.FindStringSubmatch(quantity)
+	if matches == nil {
+		return 0, fmt.Errorf("%q is not a CPU quantity (e.g. 250m, 0.5, 1)", quantity)
+	}
+	value, _ := strconv.ParseFloat(matches[1], 64)
+	if value == 0 {
+		return 0, fmt.Errorf("%q must be positive", quantity)
+	}
+	if matches[2] == "m" {
+		return value, nil
+	}
+	return value * 1000, nil
+}
+
+// parseMemoryQuantity returns a memory quantity (e.g. 512Mi, 1Gi) in bytes
+func parseMemoryQuantity(quantity string) (float64, error) {
+	matches := memoryQuantityPattern.FindStringSubmatch(quantity)
+	if matches == nil {
+		return 0, fmt.Errorf("%q is not a memory quantity (e.g. 256Mi, 1Gi)", quantity)
+	}
+	value, _ := strconv.ParseFloat(matches[1], 64)
+	if value == 0 {
+		return 0, fmt.Errorf("%q must be positive", quantity)
+	}
+	return value * memoryUnits[matches[2]], nil
+}
+
+// k8sResources returns the container resources of the Deployment, the defaults for unset ones
+func k8sResources(config *types.TerraformConfig) K8sResources {
+	return K8sResources{
+		CPURequest:    config.K8sCPURequest,
+		CPULimit:      config.K8sCPULimit,
+		MemoryRequest: config.K8sMemoryRequest,
+		MemoryLimit:   config.K8sMemoryLimit,
+	}.WithDefaults()
+}
@@ -0,0 +1,50 @@
+package terraform
+
+import "testing"
+
+func TestK8sResourcesForMemory(t *testing.T) {
+	tests := []struct {
+		minMB, maxMB int
+		want         K8sResources
+	}{
+		{50, 200, K8sResources{"100m", "500m", "64Mi", "256Mi"}},   // Go
+		{256, 512, K8sResources{"100m", "500m", "256Mi", "512Mi"}}, // Flask
+		{512, 1024, K8sResources{"250m", "1", "512Mi", "1Gi"}},     // Django, Rails
+		{512, 2048, K8sResources{"250m", "1", "512Mi", "2Gi"}},     // Java
+	}
+
+	for _, tt := range tests {
+		got := K8sResourcesForMemory(tt.minMB, tt.maxMB)
+		if got != tt.want {
+			t.Errorf("K8sResourcesForMemory(%d, %d) = %+v, want %+v", tt.minMB, tt.maxMB, got, tt.want)
+		}
+		if err := ValidateK8sResources(got); err != nil {
+			t.Errorf("ValidateK8sResources(%+v) = %v", got, err)
+		}
+	}
+}
+
+func TestValidateK8sResources(t *testing.T) {
+	tests := []struct {
+		name      string
+		resources K8sResources
+		wantErr   bool
+	}{
+		{"defaults", K8sResources{}, false},
+		{"cores and gibibytes", K8sResources{"0.5", "2", "1Gi", "1.5Gi"}, false},
+		{"decimal memory units", K8sResources{MemoryRequest: "100M", MemoryLimit: "1G"}, false},
+		{"invalid CPU", K8sResources{CPURequest: "half"}, true},
+		{"invalid memory unit", K8sResources{MemoryLimit: "512MB"}, true},
+		{"zero CPU", K8sResources{CPULimit: "0m"}, true},
+		{"CPU request above limit", K8sResources{CPURequest: "1", CPULimit: "500m"}, true},
+		{"memory request above limit", K8sResources{MemoryRequest: "1Gi"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateK8sResources(tt.resources); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateK8sResources(%+v) error = %v, wantErr %v", tt.resources, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package terraform
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Smana/scai/internal/types"
+)
+
+// Lambda instruction set architectures
+const (
+	archX86 = "x86_64"
+	archARM = "arm64"
+)
+
+// Lambda sizing limits enforced by AWS
+const (
+	MinLambdaMemory  = 128   // MB
+	MaxLambdaMemory  = 10240 // MB
+	MinLambdaTimeout = 1     // Seconds
+	MaxLambdaTimeout = 900   // Seconds
+)
+
+// ValidateLambdaMemory checks that memory (MB) is within the AWS Lambda limits
+func ValidateLambdaMemory(memory int) error {
+	if memory < MinLambdaMemory || memory > MaxLambdaMemory {
+		return fmt.Errorf("lambda memory %d MB is out of range (AWS Lambda accepts %d to %d MB)", memory, MinLambdaMemory, MaxLambdaMemory)
+	}
+	return nil
+}
+
+// ValidateLambdaTimeout checks that timeout (seconds) is within the AWS Lambda limits
+func ValidateLambdaTimeout(timeout int) error {
+	if timeout < MinLambdaTimeout || timeout > MaxLambdaTimeout {
+		return fmt.Errorf("lambda timeout %d s is out of range (AWS Lambda accepts %d to %d seconds)", timeout, MinLambdaTimeout, MaxLambdaTimeout)
+	}
+	return nil
+}
+
+// ValidateLambdaSizing checks the memory (MB) and timeout (seconds) of a function
+func ValidateLambdaSizing(memory, timeout int) error {
+	if err := ValidateLambdaMemory(memory); err != nil {
+		return err
+	}
+	return ValidateLambdaTimeout(timeout)
+}
+
+// arm64Runtimes lists the detected Lambda runtimes that are available on Graviton (arm64)
+var arm64Runtimes = map[string]bool{
+	runtimePython:     true,
+	"nodejs20.x":      true,
+	"provided.al2023": true,
+}
+
+// lambdaBaseImages maps Lambda runtimes to the AWS base image of container-image functions
+var lambdaBaseImages = map[string]string{
+	runtimePython:     "public.ecr.aws/lambda/python:3.12",
+	"nodejs20.x":      "public.ecr.aws/lambda/nodejs:20",
+	"provided.al2023": "public.ecr.aws/lambda/provided:al2023",
+}
+
+// lambdaArchitecture returns the architecture to deploy a runtime on: the requested one,
+// or x86_64 when the runtime is not available on arm64
+func lambdaArchitecture(runtime, requested string) string {
+	if requested == archARM && arm64Runtimes[runtime] {
+		return archARM
+	}
+	return archX86
+}
+
+// dockerPlatform returns the Docker build platform of a Lambda architecture
+func dockerPlatform(architecture string) string {
+	if architecture == archARM {
+		return "linux/arm64"
+	}
+	return "linux/amd64"
+}
+
+// pipPlatform returns the pip wheel platform of a Lambda architecture
+func pipPlatform(architecture string) string {
+	if architecture == archARM {
+		return "manylinux2014_aarch64"
+	}
+	return "manylinux2014_x86_64"
+}
+
+// generateLambdaPackage returns the package arguments of the Lambda module: a zip built
+// from the repository, or a container image pushed to ECR
+func (g *Generator) generateLambdaPackage(config *types.TerraformConfig, runtime, handler, architecture string) string {
+	if config.LambdaContainer {
+		return fmt.Sprintf(`  architectures = ["%s"]
+
+  # Package configuration - container image built and pushed to ECR
+  create_package = false
+  package_type   = "Image"
+  image_uri      = "%s"
+`, architecture, imageURI("lambda"))
+	}
+
+	return fmt.Sprintf(`  handler       = "%s"
+  runtime       = "%s"
+  architectures = ["%s"]
+
+  # Package configuration - using Docker for build
+  create_package      = false
+  local_existing_package = "${path.module}/lambda.zip"
+`, handler, runtime, architecture)
+}
+
+// generateLambdaBuild returns the resources building the Lambda package: lambda.zip,
+// or the ECR repository and the container image (see generateLambdaDockerfile)
+func (g *Generator) generateLambdaBuild(config *types.TerraformConfig, architecture string) string {
+	if config.LambdaContainer {
+		return g.generateImageBuild(config, "lambda", "DOCKERFILE=${path.module}/Dockerfile.lambda", dockerPlatform(architecture))
+	}
+
+	// Git repositories are cloned, zip archives and local directories packaged from their analyzed copy
+	fetch := fmt.Sprintf("git clone %s app || exit 1", config.RepoURL)
+	if config.SourcePath != "" {
+		fetch = fmt.Sprintf(`cp -R "%s" app || { echo "Sources not found: %s (deploy again to analyze them)"; exit 1; }`, config.SourcePath, config.SourcePath)
+	}
+
+	return fmt.Sprintf(`# Null resource to prepare Lambda package
+resource "null_resource" "lambda_package" {
+  provisioner "local-exec" {
+    command = <<-EOT
+      echo "Preparing Lambda package..."
+      rm -rf lambda_build && mkdir -p lambda_build
+
+      # Fetch the sources
+      cd lambda_build
+      %s
+      cd app
+
+      # Install dependencies based on language (wheels for the function's architecture)
+      case "%s" in
+        python|Python)
+          pip3 install -r requirements.txt -t . --platform %s --only-binary=:all: 2>/dev/null || \
+            pip3 install -r requirements.txt -t . 2>/dev/null || echo "No requirements"
+          ;;
+        javascript|node*)
+          npm install 2>/dev/null || echo "No package.json"
+          ;;
+      esac
+
+      # Create deployment package
+      zip -r ../../lambda.zip . -x "*.git*" "*.pyc" "__pycache__/*"
+
+      echo "Lambda package created: lambda.zip"
+    EOT
+  }
+
+  triggers = {
+    always_run = timestamp()
+  }
+}
+`,
+		fetch,                     // sources of the package
+		config.Language,           // case statement
+		pipPlatform(architecture), // pip wheel platform
+	)
+}
+
+// generateLambdaDockerfile writes Dockerfile.lambda, which builds the application on the
+// AWS Lambda base image of its runtime (repository Dockerfiles lack the runtime interface)
+func (g *Generator) generateLambdaDockerfile(runtime, handler string) error {
+	baseImage := lambdaBaseImages[runtime]
+	if baseImage == "" {
+		return fmt.Errorf("no Lambda base image for runtime: %s", runtime)
+	}
+
+	var dockerfile string
+	switch {
+	case strings.HasPrefix(runtime, "python"):
+		dockerfile = fmt.Sprintf(`FROM %s
+COPY . ${LAMBDA_TASK_ROOT}
+RUN if [ -f requirements.txt ]; then pip install --no-cache-dir -r requirements.txt -t ${LAMBDA_TASK_ROOT}; fi
+CMD ["%s"]
+`, baseImage, handler)
+	case strings.HasPrefix(runtime, "nodejs"):
+		dockerfile = fmt.Sprintf(`FROM %s
+COPY . ${LAMBDA_TASK_ROOT}
+RUN if [ -f package.json ]; then npm install --omit=dev; fi
+CMD ["%s"]
+`, baseImage, handler)
+	default:
+		// Custom runtime: cross-compile a Go bootstrap binary for the target architecture
+		dockerfile = fmt.Sprintf(`FROM --platform=$BUILDPLATFORM public.ecr.aws/docker/library/golang:1.23 AS build
+ARG TARGETARCH
+WORKDIR /src
+COPY . .
+RUN CGO_ENABLED=0 GOOS=linux GOARCH=$TARGETARCH go build -tags lambda.norpc -o /bootstrap .
+
+FROM %s
+COPY --from=build /bootstrap ${LAMBDA_RUNTIME_DIR}/bootstrap
+CMD ["bootstrap"]
+`, baseImage)
+	}
+
+	return os.WriteFile(filepath.Join(g.outputDir, "Dockerfile.lambda"), []byte(dockerfile), 0o644)
+}
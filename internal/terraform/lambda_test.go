@@ -0,0 +1,63 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Smana/scai/internal/types"
+)
+
+func TestValidateLambdaSizing(t *testing.T) {
+	tests := []struct {
+		name            string
+		memory, timeout int
+		wantErr         bool
+	}{
+		{"defaults", 512, 30, false},
+		{"minimum", MinLambdaMemory, MinLambdaTimeout, false},
+		{"maximum", MaxLambdaMemory, MaxLambdaTimeout, false},
+		{"memory below minimum", MinLambdaMemory - 1, 30, true},
+		{"memory over limit", MaxLambdaMemory + 1, 30, true},
+		{"memory from 20GB prompt", 20 * 1024, 30, true},
+		{"zero timeout", 512, 0, true},
+		{"timeout over limit", 512, MaxLambdaTimeout + 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateLambdaSizing(tt.memory, tt.timeout)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateLambdaSizing(%d, %d) error = %v, wantErr %v", tt.memory, tt.timeout, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLambdaPackageFromLocalSource(t *testing.T) {
+	dir := t.TempDir()
+	config := &types.TerraformConfig{
+		Strategy:      "serverless",
+		AppName:       "api",
+		Region:        "eu-west-3",
+		Language:      "python",
+		RepoURL:       "/home/dev/api",
+		SourcePath:    "/tmp/scai/repos/api",
+		LambdaMemory:  512,
+		LambdaTimeout: 30,
+	}
+	if err := NewGenerator(dir, false).Generate(config); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	mainTF, err := os.ReadFile(filepath.Join(dir, "main.tf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(mainTF), `cp -R "/tmp/scai/repos/api" app`) {
+		t.Error("main.tf does not package the analyzed copy of the local directory")
+	}
+	if strings.Contains(string(mainTF), "git clone") {
+		t.Error("main.tf clones a local directory")
+	}
+}
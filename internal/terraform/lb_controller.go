@@ -0,0 +1,150 @@
+package terraform
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Smana/scai/internal/types"
+)
+
+// helmProviderVersion is the Helm provider constraint of EKS deployments on Fargate, which
+// install the AWS Load Balancer Controller chart
+const helmProviderVersion = "~> 2.17"
+
+// lbControllerChartVersion is the version of the AWS Load Balancer Controller chart
+const lbControllerChartVersion = "1.13.0"
+
+// lbControllerServiceAccount is the service account of the AWS Load Balancer Controller
+const lbControllerServiceAccount = "aws-load-balancer-controller"
+
+// UsesLBController reports whether the Kubernetes service is exposed through the AWS Load
+// Balancer Controller: Fargate has no nodes for the Classic ELB of the in-tree controller to
+// target, so an NLB targets the pod IPs instead
+func UsesLBController(config *types.TerraformConfig) bool {
+	return config.EKSFargate
+}
+
+// generateLBController generates the AWS Load Balancer Controller (Helm release) and its IAM
+// role, bound to its service account through IRSA as Fargate pods cannot reach the Pod Identity
+// agent. Empty when the service uses the in-tree controller.
+func (g *Generator) generateLBController(config *types.TerraformConfig, k8sAppName string) string {
+	if !UsesLBController(config) {
+		return ""
+	}
+
+	return fmt.Sprintf(`
+# IAM role of the AWS Load Balancer Controller (IRSA)
+module "lb_controller_irsa" {
+  source  = "terraform-aws-modules/iam/aws//modules/iam-role-for-service-accounts"
+  version = "~> 6.0"
+
+  name            = "%[1]s-lb-controller"
+  use_name_prefix = false
+
+  attach_load_balancer_controller_policy = true
+
+  oidc_providers = {
+    main = {
+      provider_arn               = module.eks.oidc_provider_arn
+      namespace_service_accounts = ["kube-system:%[2]s"]
+    }
+  }
+
+  tags = {
+    Name        = "%[1]s-lb-controller"
+    Environment = "production"
+    ManagedBy   = "SCAI"
+  }
+}
+
+# Configure Helm provider
+provider "helm" {
+  kubernetes {
+    host                   = module.eks.cluster_endpoint
+    cluster_ca_certificate = base64decode(module.eks.cluster_certificate_authority_data)
+
+    exec {
+      api_version = "client.authentication.k8s.io/v1beta1"
+      command     = "aws"
+      args        = ["eks", "get-token", "--cluster-name", module.eks.cluster_name, "--region", "%[3]s"]
+    }
+  }
+}
+
+# AWS Load Balancer Controller: provisions the NLB of the service, targeting the pod IPs
+resource "helm_release" "aws_load_balancer_controller" {
+  depends_on = [module.eks]
+
+  name       = "aws-load-balancer-controller"
+  repository = "https://aws.github.io/eks-charts"
+  chart      = "aws-load-balancer-controller"
+  version    = "%[4]s"
+  namespace  = "kube-system"
+
+  # Fargate pods have no instance metadata: the region and VPC are set explicitly
+  values = [yamlencode({
+    clusterName = module.eks.cluster_name
+    region      = "%[3]s"
+    vpcId       = %[5]s
+    serviceAccount = {
+      name = "%[2]s"
+      annotations = {
+        "eks.amazonaws.com/role-arn" = module.lb_controller_irsa.arn
+      }
+    }
+  })]
+}
+`,
+		k8sAppName,                      // role name
+		lbControllerServiceAccount,      // controller service account
+		config.Region,                   // kubectl and controller region
+		lbControllerChartVersion,        // chart version
+		networkReferences(config).VPCID, // controller VPC
+	)
+}
+
+// generateServiceDependsOn returns the depends_on of the Kubernetes service: the controller
+// must run first, its webhook assigns the service to it
+func (g *Generator) generateServiceDependsOn(config *types.TerraformConfig) string {
+	if !UsesLBController(config) {
+		return "[kubernetes_deployment.app]"
+	}
+	return "[kubernetes_deployment.app, helm_release.aws_load_balancer_controller]"
+}
+
+// generateServiceAnnotations returns the annotations block of the Kubernetes service: an
+// internet-facing NLB with IP targets through the AWS Load Balancer Controller (Fargate), and
+// TLS termination on port 443 with a custom domain. Empty when there are none.
+func (g *Generator) generateServiceAnnotations(config *types.TerraformConfig) string {
+	var annotations [][2]string
+	if UsesLBController(config) {
+		annotations = append(annotations,
+			[2]string{"service.beta.kubernetes.io/aws-load-balancer-type", `"external"`},
+			[2]string{"service.beta.kubernetes.io/aws-load-balancer-nlb-target-type", `"ip"`},
+			[2]string{"service.beta.kubernetes.io/aws-load-balancer-scheme", `"internet-facing"`},
+		)
+	}
+	if config.Domain != "" {
+		annotations = append(annotations,
+			[2]string{"service.beta.kubernetes.io/aws-load-balancer-ssl-cert", "local.certificate_arn"},
+			[2]string{"service.beta.kubernetes.io/aws-load-balancer-ssl-ports", `"443"`},
+			[2]string{"service.beta.kubernetes.io/aws-load-balancer-backend-protocol", `"http"`},
+		)
+	}
+	if len(annotations) == 0 {
+		return ""
+	}
+
+	// Align the values like terraform fmt
+	width := 0
+	for _, annotation := range annotations {
+		width = max(width, len(annotation[0])+2)
+	}
+	var b strings.Builder
+	b.WriteString("    annotations = {\n")
+	for _, annotation := range annotations {
+		fmt.Fprintf(&b, "      %-*s = %s\n", width, `"`+annotation[0]+`"`, annotation[1])
+	}
+	b.WriteString("    }\n")
+	return b.String()
+}
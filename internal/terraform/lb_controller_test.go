@@ -0,0 +1,97 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Smana/scai/internal/types"
+)
+
+func TestEKSServiceLoadBalancer(t *testing.T) {
+	tests := []struct {
+		name     string
+		fargate  bool
+		want     []string
+		unwanted []string
+		wantHelm bool
+	}{
+		{
+			name:    "node group uses a Classic ELB",
+			fargate: false,
+			want: []string{
+				"depends_on = [kubernetes_deployment.app]\n",
+				`data "aws_elb_hosted_zone_id" "main" {}`,
+			},
+			unwanted: []string{"helm_release", "aws-load-balancer-nlb-target-type", `load_balancer_type = "network"`},
+		},
+		{
+			name:    "Fargate uses an NLB with IP targets",
+			fargate: true,
+			want: []string{
+				`resource "helm_release" "aws_load_balancer_controller"`,
+				`namespace_service_accounts = ["kube-system:aws-load-balancer-controller"]`,
+				"depends_on = [kubernetes_deployment.app, helm_release.aws_load_balancer_controller]",
+				`"service.beta.kubernetes.io/aws-load-balancer-type"             = "external"`,
+				`"service.beta.kubernetes.io/aws-load-balancer-nlb-target-type"  = "ip"`,
+				`"service.beta.kubernetes.io/aws-load-balancer-scheme"           = "internet-facing"`,
+				`"service.beta.kubernetes.io/aws-load-balancer-ssl-cert"         = local.certificate_arn`,
+				`load_balancer_type = "network"`,
+			},
+			wantHelm: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			config := &types.TerraformConfig{
+				Strategy:          "kubernetes",
+				AppName:           "web",
+				Region:            "eu-west-3",
+				Language:          "python",
+				Port:              8080,
+				RepoURL:           "https://github.com/example/web",
+				Domain:            "app.example.com",
+				HostedZoneID:      "Z0123456789ABCDEFGHIJ",
+				EKSFargate:        tt.fargate,
+				EKSNodeType:       "t3.medium",
+				EKSMinNodes:       1,
+				EKSMaxNodes:       3,
+				EKSDesiredNodes:   2,
+				EKSNodeVolumeSize: 20,
+			}
+			if err := NewGenerator(dir, false).Generate(config); err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+
+			var generated strings.Builder
+			for _, name := range []string{"main.tf", "domain.tf"} {
+				content, err := os.ReadFile(filepath.Join(dir, name))
+				if err != nil && !os.IsNotExist(err) {
+					t.Fatal(err)
+				}
+				generated.Write(content)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(generated.String(), want) {
+					t.Errorf("generated Terraform missing %q", want)
+				}
+			}
+			for _, unwanted := range tt.unwanted {
+				if strings.Contains(generated.String(), unwanted) {
+					t.Errorf("generated Terraform contains %q", unwanted)
+				}
+			}
+
+			versions, err := os.ReadFile(filepath.Join(dir, "versions.tf"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.Contains(string(versions), `source  = "hashicorp/helm"`); got != tt.wantHelm {
+				t.Errorf("versions.tf requires the Helm provider = %v, want %v", got, tt.wantHelm)
+			}
+		})
+	}
+}
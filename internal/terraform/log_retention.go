@@ -0,0 +1,43 @@
+package terraform
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Retention of the CloudWatch log groups when none is configured: the Lambda function logs
+// (serverless) and the EKS control plane logs (kubernetes, the EKS module default)
+const (
+	DefaultLambdaLogRetentionDays = 7
+	DefaultEKSLogRetentionDays    = 90
+)
+
+// LogRetentionPeriods lists the retention periods (days) CloudWatch Logs accepts
+var LogRetentionPeriods = []int{1, 3, 5, 7, 14, 30, 60, 90, 120, 150, 180, 365, 400, 545, 731, 1096, 1827, 2192, 2557, 2922, 3288, 3653}
+
+// ValidateLogRetentionDays checks that days is a retention period CloudWatch Logs accepts
+func ValidateLogRetentionDays(days int) error {
+	if slices.Contains(LogRetentionPeriods, days) {
+		return nil
+	}
+
+	periods := make([]string, len(LogRetentionPeriods))
+	for i, period := range LogRetentionPeriods {
+		periods[i] = strconv.Itoa(period)
+	}
+	return fmt.Errorf("log retention of %d days is not accepted by CloudWatch Logs (one of %s)", days, strings.Join(periods, ", "))
+}
+
+// LogRetentionDays returns the retention of the CloudWatch log groups of a strategy: days,
+// or the strategy default when 0
+func LogRetentionDays(strategy string, days int) int {
+	if days > 0 {
+		return days
+	}
+	if strategy == "kubernetes" {
+		return DefaultEKSLogRetentionDays
+	}
+	return DefaultLambdaLogRetentionDays
+}
@@ -0,0 +1,51 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Smana/scai/internal/types"
+)
+
+func TestLogRetentionDays(t *testing.T) {
+	for _, days := range []int{1, 30, 90, 3653} {
+		if err := ValidateLogRetentionDays(days); err != nil {
+			t.Errorf("ValidateLogRetentionDays(%d) error = %v", days, err)
+		}
+	}
+	for _, days := range []int{0, 2, 10, 4000} {
+		if err := ValidateLogRetentionDays(days); err == nil {
+			t.Errorf("ValidateLogRetentionDays(%d) accepted a retention CloudWatch rejects", days)
+		}
+	}
+
+	if got := LogRetentionDays("serverless", 0); got != DefaultLambdaLogRetentionDays {
+		t.Errorf("LogRetentionDays(serverless, 0) = %d, want %d", got, DefaultLambdaLogRetentionDays)
+	}
+	if got := LogRetentionDays("kubernetes", 0); got != DefaultEKSLogRetentionDays {
+		t.Errorf("LogRetentionDays(kubernetes, 0) = %d, want %d", got, DefaultEKSLogRetentionDays)
+	}
+
+	dir := t.TempDir()
+	config := &types.TerraformConfig{
+		Strategy:         "serverless",
+		AppName:          "api",
+		Region:           "eu-west-3",
+		Language:         "python",
+		LambdaMemory:     512,
+		LambdaTimeout:    30,
+		LogRetentionDays: 30,
+	}
+	if err := NewGenerator(dir, false).Generate(config); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	mainTF, err := os.ReadFile(filepath.Join(dir, "main.tf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(mainTF), "cloudwatch_logs_retention_in_days = 30") {
+		t.Error("main.tf does not set the configured log retention")
+	}
+}
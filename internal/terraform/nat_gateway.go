@@ -0,0 +1,64 @@
+package terraform
+
+import (
+	"fmt"
+
+	"github.com/Smana/scai/internal/types"
+)
+
+// NAT gateway modes of the EKS VPC
+const (
+	NATGatewaySingle = "single" // One NAT gateway shared by all AZs (cheapest, single point of failure)
+	NATGatewayPerAZ  = "per-az" // One NAT gateway per AZ
+	NATGatewayNone   = "none"   // No NAT gateway: nodes run in the public subnets
+)
+
+// ValidateNATGateway checks that mode is a NAT gateway mode (empty means NATGatewaySingle)
+func ValidateNATGateway(mode string) error {
+	switch mode {
+	case "", NATGatewaySingle, NATGatewayPerAZ, NATGatewayNone:
+		return nil
+	default:
+		return fmt.Errorf("invalid NAT gateway mode %q: expected %s, %s or %s", mode, NATGatewaySingle, NATGatewayPerAZ, NATGatewayNone)
+	}
+}
+
+// generateNATGateway generates the NAT gateway settings of the VPC module
+func (g *Generator) generateNATGateway(config *types.TerraformConfig) string {
+	switch config.EKSNATGateway {
+	case NATGatewayPerAZ:
+		return `  # One NAT gateway per availability zone (no single point of failure)
+  enable_nat_gateway     = true
+  one_nat_gateway_per_az = true
+`
+	case NATGatewayNone:
+		return `  # No NAT gateway: private subnets have no outbound internet access,
+  # nodes run in the public subnets with public IPs
+  enable_nat_gateway      = false
+  map_public_ip_on_launch = true
+`
+	default:
+		return `  # Single NAT gateway shared by all availability zones
+  enable_nat_gateway = true
+  single_nat_gateway = true
+`
+	}
+}
+
+// eksNodeSubnets returns the subnets EKS nodes and pods run in: the existing subnets, or
+// the public subnets when there is no NAT gateway to reach the internet from the private ones
+func eksNodeSubnets(config *types.TerraformConfig) string {
+	switch {
+	case config.VPCID != "":
+		return existingNetworkRefs.SubnetIDs
+	case config.EKSNATGateway == NATGatewayNone:
+		return "module.vpc.public_subnets"
+	default:
+		return "module.vpc.private_subnets"
+	}
+}
+
+// eksControlPlaneSubnets returns the subnets of the EKS control plane network interfaces
+func eksControlPlaneSubnets(config *types.TerraformConfig) string {
+	return networkReferences(config).SubnetIDs
+}
@@ -0,0 +1,166 @@
+package terraform
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Smana/scai/internal/types"
+)
+
+var (
+	// vpcIDPattern matches a VPC ID
+	vpcIDPattern = regexp.MustCompile(`^vpc-[0-9a-f]{8}([0-9a-f]{9})?$`)
+
+	// subnetIDPattern matches a subnet ID
+	subnetIDPattern = regexp.MustCompile(`^subnet-[0-9a-f]{8}([0-9a-f]{9})?$`)
+)
+
+// ValidateVPCID checks that id looks like a VPC ID (e.g. vpc-0abcdef1234567890)
+func ValidateVPCID(id string) error {
+	if !vpcIDPattern.MatchString(id) {
+		return fmt.Errorf("invalid VPC ID %q: expected e.g. vpc-0abcdef1234567890", id)
+	}
+	return nil
+}
+
+// ValidateSubnetID checks that id looks like a subnet ID (e.g. subnet-0abcdef1234567890)
+func ValidateSubnetID(id string) error {
+	if !subnetIDPattern.MatchString(id) {
+		return fmt.Errorf("invalid subnet ID %q: expected e.g. subnet-0abcdef1234567890", id)
+	}
+	return nil
+}
+
+// networkRefs are the HCL expressions of the network a deployment runs in
+type networkRefs struct {
+	VPCID     string // VPC ID
+	SubnetIDs string // Subnets of the instances, nodes and database
+	VPCCIDR   string // VPC CIDR block, for the ingress rules of resources reachable from the VPC only
+}
+
+// existingNetworkRefs references the existing VPC and subnets of config.VPCID
+var existingNetworkRefs = networkRefs{
+	VPCID:     "data.aws_vpc.existing.id",
+	SubnetIDs: "data.aws_subnets.existing.ids",
+	VPCCIDR:   "data.aws_vpc.existing.cidr_block",
+}
+
+// networkReferences returns the network of a deployment: the existing VPC of config.VPCID,
+// otherwise the default VPC (vm) or the private subnets of the VPC module (kubernetes)
+func networkReferences(config *types.TerraformConfig) networkRefs {
+	switch {
+	case config.VPCID != "":
+		return existingNetworkRefs
+	case config.Strategy == "kubernetes":
+		return networkRefs{
+			VPCID:     "module.vpc.vpc_id",
+			SubnetIDs: "module.vpc.private_subnets",
+			VPCCIDR:   "module.vpc.vpc_cidr_block",
+		}
+	default:
+		return networkRefs{
+			VPCID:     "data.aws_vpc.default.id",
+			SubnetIDs: "data.aws_subnets.default.ids",
+			VPCCIDR:   "data.aws_vpc.default.cidr_block",
+		}
+	}
+}
+
+// generateExistingNetworkData generates the data sources of the existing VPC and of its
+// subnets (config.SubnetIDs, all of them when empty): Terraform fails at plan time when a
+// subnet does not belong to the VPC
+func (g *Generator) generateExistingNetworkData(config *types.TerraformConfig) string {
+	subnetFilter := ""
+	if len(config.SubnetIDs) > 0 {
+		ids := make([]string, len(config.SubnetIDs))
+		for i, id := range config.SubnetIDs {
+			ids[i] = hclString(id)
+		}
+		subnetFilter = fmt.Sprintf(`
+
+  filter {
+    name   = "subnet-id"
+    values = [%s]
+  }`, strings.Join(ids, ", "))
+	}
+
+	return fmt.Sprintf(`# Existing VPC (--vpc-id): not managed by this deployment
+data "aws_vpc" "existing" {
+  id = %s
+}
+
+# Existing subnets
+data "aws_subnets" "existing" {
+  filter {
+    name   = "vpc-id"
+    values = [data.aws_vpc.existing.id]
+  }%s
+}`, hclString(config.VPCID), subnetFilter)
+}
+
+// generateVMNetworkData generates the data sources of the network of the instances: the
+// existing VPC of config.VPCID, or the default VPC
+func (g *Generator) generateVMNetworkData(config *types.TerraformConfig) string {
+	if config.VPCID != "" {
+		return g.generateExistingNetworkData(config)
+	}
+
+	return `# Get default VPC
+data "aws_vpc" "default" {
+  default = true
+}
+
+# Get default subnets
+data "aws_subnets" "default" {
+  filter {
+    name   = "vpc-id"
+    values = [data.aws_vpc.default.id]
+  }
+}`
+}
+
+// generateEKSNetwork generates the network of the cluster: the data sources of the existing
+// VPC of config.VPCID, or a new VPC with public and private subnets in two AZs
+func (g *Generator) generateEKSNetwork(config *types.TerraformConfig, k8sAppName string) string {
+	if config.VPCID != "" {
+		return g.generateExistingNetworkData(config)
+	}
+
+	return fmt.Sprintf(`# Get available AZs
+data "aws_availability_zones" "available" {
+  state = "available"
+}
+
+# VPC Module
+module "vpc" {
+  source  = "terraform-aws-modules/vpc/aws"
+  version = "~> 6.0"
+
+  name = "%s-vpc"
+  cidr = "10.0.0.0/16"
+
+  azs             = slice(data.aws_availability_zones.available.names, 0, 2)
+  private_subnets = ["10.0.1.0/24", "10.0.2.0/24"]
+  public_subnets  = ["10.0.101.0/24", "10.0.102.0/24"]
+
+%s
+  enable_dns_hostnames = true
+  enable_dns_support   = true
+
+  # EKS requires specific tags on subnets
+  public_subnet_tags = {
+    "kubernetes.io/role/elb" = "1"
+  }
+
+  private_subnet_tags = {
+    "kubernetes.io/role/internal-elb" = "1"
+  }
+
+  tags = {
+    Name        = "%s-vpc"
+    Environment = "production"
+    ManagedBy   = "SCAI"
+  }
+}`, k8sAppName, g.generateNATGateway(config), k8sAppName)
+}
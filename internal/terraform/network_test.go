@@ -0,0 +1,94 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Smana/scai/internal/types"
+)
+
+func TestExistingNetwork(t *testing.T) {
+	tests := []struct {
+		name     string
+		generate func(g *Generator, config *types.TerraformConfig) error
+		config   *types.TerraformConfig
+		want     []string
+		notWant  []string
+	}{
+		{
+			name:     "vm default VPC",
+			generate: (*Generator).generateEC2Config,
+			config:   &types.TerraformConfig{AppName: "app", Strategy: "vm"},
+			want:     []string{"default = true", "vpc_zone_identifier = data.aws_subnets.default.ids"},
+			notWant:  []string{"data.aws_vpc.existing"},
+		},
+		{
+			name:     "vm existing VPC",
+			generate: (*Generator).generateEC2Config,
+			config:   &types.TerraformConfig{AppName: "app", Strategy: "vm", VPCID: "vpc-0abcdef1234567890"},
+			want: []string{
+				`id = "vpc-0abcdef1234567890"`,
+				"vpc_id      = data.aws_vpc.existing.id",
+				"vpc_zone_identifier = data.aws_subnets.existing.ids",
+			},
+			notWant: []string{"default = true", `name   = "subnet-id"`},
+		},
+		{
+			name:     "kubernetes existing subnets",
+			generate: (*Generator).generateEKSConfig,
+			config: &types.TerraformConfig{
+				AppName: "app", Strategy: "kubernetes", VPCID: "vpc-0abcdef1234567890",
+				SubnetIDs: []string{"subnet-0abcdef1234567890", "subnet-1234567890abcdef0"},
+			},
+			want: []string{
+				`values = ["subnet-0abcdef1234567890", "subnet-1234567890abcdef0"]`,
+				"vpc_id                   = data.aws_vpc.existing.id",
+				"subnet_ids               = data.aws_subnets.existing.ids",
+				"control_plane_subnet_ids = data.aws_subnets.existing.ids",
+			},
+			notWant: []string{`module "vpc"`, "module.vpc."},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := tt.generate(NewGenerator(dir, false), tt.config); err != nil {
+				t.Fatalf("generate error = %v", err)
+			}
+			content, err := os.ReadFile(filepath.Join(dir, "main.tf"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			mainTF := string(content)
+
+			for _, want := range tt.want {
+				if !strings.Contains(mainTF, want) {
+					t.Errorf("main.tf does not contain %q", want)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(mainTF, notWant) {
+					t.Errorf("main.tf contains %q", notWant)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateNetworkIDs(t *testing.T) {
+	if err := ValidateVPCID("vpc-0abcdef1234567890"); err != nil {
+		t.Errorf("ValidateVPCID() error = %v", err)
+	}
+	if err := ValidateVPCID("subnet-0abcdef1234567890"); err == nil {
+		t.Error("ValidateVPCID() accepted a subnet ID")
+	}
+	if err := ValidateSubnetID("subnet-12345678"); err != nil {
+		t.Errorf("ValidateSubnetID() error = %v", err)
+	}
+	if err := ValidateSubnetID("subnet-XYZ"); err == nil {
+		t.Error("ValidateSubnetID() accepted an invalid ID")
+	}
+}
@@ -0,0 +1,90 @@
+package terraform
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// planFile is the plan written by PlanDestroy in the working directory
+const planFile = "scai-destroy.tfplan"
+
+// ResourceChange is a resource a Terraform plan changes
+type ResourceChange struct {
+	Address string   // e.g. module.eks.aws_eks_cluster.this[0]
+	Type    string   // e.g. aws_eks_cluster
+	Actions []string // e.g. ["delete"], ["create"], ["delete", "create"] for a replacement
+}
+
+// Deleted reports whether the change deletes the resource (a replacement included)
+func (c ResourceChange) Deleted() bool {
+	return slices.Contains(c.Actions, "delete")
+}
+
+// ParsePlanChanges returns the resource changes of a plan in the JSON format of
+// 'terraform show -json <plan>'; resources the plan leaves as they are are omitted
+func ParsePlanChanges(planJSON []byte) ([]ResourceChange, error) {
+	var plan struct {
+		ResourceChanges []struct {
+			Address string `json:"address"`
+			Mode    string `json:"mode"`
+			Type    string `json:"type"`
+			Change  struct {
+				Actions []string `json:"actions"`
+			} `json:"change"`
+		} `json:"resource_changes"`
+	}
+	if err := json.Unmarshal(planJSON, &plan); err != nil {
+		return nil, fmt.Errorf("failed to parse terraform plan: %w", err)
+	}
+
+	var changes []ResourceChange
+	for _, rc := range plan.ResourceChanges {
+		// Data sources are read, never created nor deleted
+		if rc.Mode == "data" || slices.Equal(rc.Change.Actions, []string{"no-op"}) || slices.Equal(rc.Change.Actions, []string{"read"}) {
+			continue
+		}
+		changes = append(changes, ResourceChange{Address: rc.Address, Type: rc.Type, Actions: rc.Change.Actions})
+	}
+	return changes, nil
+}
+
+// PlanDestroy runs terraform plan -destroy and returns the resources it would delete,
+// without changing anything
+func (e *Executor) PlanDestroy() ([]ResourceChange, error) {
+	planPath := filepath.Join(e.workDir, planFile)
+	defer func() { _ = os.Remove(planPath) }()
+
+	if err := e.runCommand("plan", "-destroy", "-input=false", "-no-color", "-out="+planFile); err != nil {
+		return nil, err
+	}
+
+	cmd := e.command("show", "-json", planFile)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the destroy plan: %w", err)
+	}
+
+	changes, err := ParsePlanChanges(output)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(changes, func(c ResourceChange) bool { return !c.Deleted() }), nil
+}
+
+// CountByType returns the number of changes per resource type, as "type (count)" sorted by type
+func CountByType(changes []ResourceChange) []string {
+	counts := make(map[string]int)
+	for _, c := range changes {
+		counts[c.Type]++
+	}
+
+	summary := make([]string, 0, len(counts))
+	for resourceType, count := range counts {
+		summary = append(summary, fmt.Sprintf("%s (%d)", resourceType, count))
+	}
+	slices.Sort(summary)
+	return summary
+}
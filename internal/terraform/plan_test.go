@@ -0,0 +1,42 @@
+package terraform
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParsePlanChanges(t *testing.T) {
+	planJSON := []byte(`{
+  "format_version": "1.2",
+  "resource_changes": [
+    {"address": "data.aws_ami.ubuntu", "mode": "data", "type": "aws_ami", "change": {"actions": ["read"]}},
+    {"address": "module.eks.aws_eks_cluster.this[0]", "mode": "managed", "type": "aws_eks_cluster", "change": {"actions": ["delete"]}},
+    {"address": "module.vpc.aws_subnet.private[0]", "mode": "managed", "type": "aws_subnet", "change": {"actions": ["delete"]}},
+    {"address": "module.vpc.aws_subnet.private[1]", "mode": "managed", "type": "aws_subnet", "change": {"actions": ["delete"]}},
+    {"address": "aws_security_group.app", "mode": "managed", "type": "aws_security_group", "change": {"actions": ["no-op"]}},
+    {"address": "aws_instance.app", "mode": "managed", "type": "aws_instance", "change": {"actions": ["create", "delete"]}}
+  ]
+}`)
+
+	changes, err := ParsePlanChanges(planJSON)
+	if err != nil {
+		t.Fatalf("ParsePlanChanges failed: %v", err)
+	}
+	if len(changes) != 4 {
+		t.Fatalf("Expected 4 changes (data sources and no-ops omitted), got %+v", changes)
+	}
+	for _, c := range changes {
+		if !c.Deleted() {
+			t.Errorf("Expected %s to be deleted", c.Address)
+		}
+	}
+
+	want := []string{"aws_eks_cluster (1)", "aws_instance (1)", "aws_subnet (2)"}
+	if got := CountByType(changes); !slices.Equal(got, want) {
+		t.Errorf("CountByType() = %v, want %v", got, want)
+	}
+
+	if _, err := ParsePlanChanges([]byte("not json")); err == nil {
+		t.Error("Expected an error for an invalid plan")
+	}
+}
@@ -0,0 +1,19 @@
+//go:build !windows
+
+package terraform
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroup runs cmd in a process group of its own, out of reach of the terminal signals
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// interruptProcess asks a terraform process to stop gracefully
+func interruptProcess(process *os.Process) error {
+	return process.Signal(os.Interrupt)
+}
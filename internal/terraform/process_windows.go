@@ -0,0 +1,16 @@
+//go:build windows
+
+package terraform
+
+import (
+	"os"
+	"os/exec"
+)
+
+// setProcessGroup is a no-op on Windows
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// interruptProcess kills a terraform process: Windows has no interrupt signal to send
+func interruptProcess(process *os.Process) error {
+	return process.Kill()
+}
@@ -0,0 +1,69 @@
+package terraform
+
+import (
+	"errors"
+	"strings"
+)
+
+// Apply retries by default: EKS applies hit IAM and cluster eventual consistency more often
+const (
+	DefaultApplyRetries    = 1
+	DefaultEKSApplyRetries = 2
+)
+
+// retryableErrors are the terraform errors caused by AWS eventual consistency or throttling,
+// which a second apply gets past, with the reason they are recorded with
+var retryableErrors = []struct {
+	pattern string
+	reason  string
+}{
+	{"cannot be assumed", "IAM role not propagated yet"},
+	{"Invalid IAM Instance Profile", "IAM instance profile not propagated yet"},
+	{"InvalidParameterException: The role with name", "IAM role not propagated yet"},
+	{"Kubernetes cluster unreachable", "EKS cluster endpoint not ready yet"},
+	{"RequestLimitExceeded", "AWS API throttling"},
+	{"ThrottlingException", "AWS API throttling"},
+	{"TooManyRequestsException", "AWS API throttling"},
+	{"timeout while waiting for state", "AWS resource slow to become ready"},
+}
+
+// commandError is a failed terraform command, with the output it printed
+type commandError struct {
+	err    error
+	output string
+}
+
+func (e *commandError) Error() string {
+	return e.err.Error()
+}
+
+func (e *commandError) Unwrap() error {
+	return e.err
+}
+
+// ApplyRetries returns the number of times a failed apply is re-run for a strategy;
+// a negative configured value selects the strategy default
+func ApplyRetries(strategy string, configured int) int {
+	if configured >= 0 {
+		return configured
+	}
+	if strategy == "kubernetes" {
+		return DefaultEKSApplyRetries
+	}
+	return DefaultApplyRetries
+}
+
+// RetryableError reports whether a failed terraform command is likely to succeed when
+// re-run (e.g. an IAM role not yet propagated), and why
+func RetryableError(err error) (string, bool) {
+	var cmdErr *commandError
+	if !errors.As(err, &cmdErr) {
+		return "", false
+	}
+	for _, retryable := range retryableErrors {
+		if strings.Contains(cmdErr.output, retryable.pattern) {
+			return retryable.reason, true
+		}
+	}
+	return "", false
+}
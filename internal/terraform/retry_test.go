@@ -0,0 +1,45 @@
+package terraform
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestRetryableError(t *testing.T) {
+	tests := []struct {
+		name          string
+		err           error
+		wantRetryable bool
+	}{
+		{"IAM role", &commandError{err: errors.New("command failed"), output: "Error: creating Lambda Function: InvalidParameterValueException: The role defined for the function cannot be assumed by Lambda."}, true},
+		{"wrapped", fmt.Errorf("terraform apply failed: %w", &commandError{err: errors.New("command failed"), output: "Error: Kubernetes cluster unreachable: connection refused"}), true},
+		{"invalid configuration", &commandError{err: errors.New("command failed"), output: "Error: Unsupported argument"}, false},
+		{"not a command error", errors.New("RequestLimitExceeded"), false},
+	}
+
+	for _, tt := range tests {
+		if reason, retryable := RetryableError(tt.err); retryable != tt.wantRetryable || (retryable && reason == "") {
+			t.Errorf("%s: RetryableError() = %q, %t, want retryable %t", tt.name, reason, retryable, tt.wantRetryable)
+		}
+	}
+}
+
+func TestApplyRetries(t *testing.T) {
+	tests := []struct {
+		strategy   string
+		configured int
+		want       int
+	}{
+		{"kubernetes", -1, DefaultEKSApplyRetries},
+		{"vm", -1, DefaultApplyRetries},
+		{"kubernetes", 0, 0},
+		{"serverless", 3, 3},
+	}
+
+	for _, tt := range tests {
+		if got := ApplyRetries(tt.strategy, tt.configured); got != tt.want {
+			t.Errorf("ApplyRetries(%s, %d) = %d, want %d", tt.strategy, tt.configured, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package terraform
+
+import (
+	"fmt"
+
+	"github.com/Smana/scai/internal/types"
+)
+
+// SSHEnabled reports whether the EC2 instances of config accept SSH connections: without key
+// pair, they are only reachable with SSM Session Manager
+func SSHEnabled(config *types.TerraformConfig) bool {
+	return config.SSHKeyName != "" || config.SSHPublicKey != ""
+}
+
+// generateSSHIngress returns the security group rule opening port 22, empty without key pair
+func (g *Generator) generateSSHIngress(config *types.TerraformConfig) string {
+	if !SSHEnabled(config) {
+		return ""
+	}
+	return `,
+    {
+      from_port   = 22
+      to_port     = 22
+      protocol    = "tcp"
+      cidr_blocks = "0.0.0.0/0"
+      description = "SSH access"
+    }`
+}
+
+// generateKeyPair returns the EC2 key pair of a key generated for the deployment, empty
+// otherwise (the private key never goes through Terraform, it is only saved locally)
+func (g *Generator) generateKeyPair(config *types.TerraformConfig) string {
+	if config.SSHPublicKey == "" {
+		return ""
+	}
+	return fmt.Sprintf(`
+# Key pair for SSH access (private key saved locally by SCAI)
+resource "aws_key_pair" "ssh" {
+  key_name_prefix = "%s-"
+  public_key      = %s
+}
+`, config.AppName, hclString(config.SSHPublicKey))
+}
+
+// generateKeyName returns the key_name argument of the launch template, empty without key pair
+func (g *Generator) generateKeyName(config *types.TerraformConfig) string {
+	switch {
+	case config.SSHPublicKey != "":
+		return "\n  key_name = aws_key_pair.ssh.key_name"
+	case config.SSHKeyName != "":
+		return "\n  key_name = " + hclString(config.SSHKeyName)
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,41 @@
+package terraform
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Smana/scai/internal/types"
+)
+
+func TestSSHAccess(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      *types.TerraformConfig
+		wantIngress bool
+		wantKeyName string
+		wantKeyPair bool
+	}{
+		{"disabled", &types.TerraformConfig{AppName: "app"}, false, "", false},
+		{"existing key pair", &types.TerraformConfig{AppName: "app", SSHKeyName: "ops"}, true, `key_name = "ops"`, false},
+		{"generated key", &types.TerraformConfig{AppName: "app", SSHPublicKey: "ssh-ed25519 AAAA"}, true, "key_name = aws_key_pair.ssh.key_name", true},
+	}
+
+	g := NewGenerator(t.TempDir(), false)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := strings.Contains(g.generateSSHIngress(tt.config), "from_port   = 22"); got != tt.wantIngress {
+				t.Errorf("generateSSHIngress() opens port 22 = %v, want %v", got, tt.wantIngress)
+			}
+			if got := strings.TrimSpace(g.generateKeyName(tt.config)); got != tt.wantKeyName {
+				t.Errorf("generateKeyName() = %q, want %q", got, tt.wantKeyName)
+			}
+			keyPair := g.generateKeyPair(tt.config)
+			if (keyPair != "") != tt.wantKeyPair {
+				t.Errorf("generateKeyPair() = %q, want key pair %v", keyPair, tt.wantKeyPair)
+			}
+			if tt.wantKeyPair && !strings.Contains(keyPair, `"ssh-ed25519 AAAA"`) {
+				t.Errorf("generateKeyPair() = %q, want the public key", keyPair)
+			}
+		})
+	}
+}
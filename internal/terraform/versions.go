@@ -0,0 +1,75 @@
+package terraform
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/Smana/scai/internal/types"
+)
+
+// DefaultAWSProviderVersion is the AWS provider constraint the generated configurations are
+// tested with. Bump it with the modules the generator uses.
+const DefaultAWSProviderVersion = "~> 6.0"
+
+// kubernetesProviderVersion is the Kubernetes provider constraint of EKS deployments
+const kubernetesProviderVersion = "~> 2.20"
+
+// versionConstraintClause matches one clause of a Terraform version constraint (e.g. ~> 6.0, >= 5.80)
+var versionConstraintClause = regexp.MustCompile(`^(?:=|!=|>=|<=|>|<|~>)?\s*\d+(?:\.\d+){0,2}(?:-[0-9A-Za-z.]+)?$`)
+
+// ValidateProviderVersion checks that constraint is a Terraform version constraint: comma
+// separated clauses such as "~> 6.0" or ">= 5.80, < 7.0"
+func ValidateProviderVersion(constraint string) error {
+	for _, clause := range strings.Split(constraint, ",") {
+		if !versionConstraintClause.MatchString(strings.TrimSpace(clause)) {
+			return fmt.Errorf("invalid provider version constraint %q: expected e.g. %q or \">= 6.0, < 7.0\"", constraint, DefaultAWSProviderVersion)
+		}
+	}
+	return nil
+}
+
+// generateVersionsConfig writes versions.tf: the required Terraform version and provider
+// constraints, the AWS provider pinned to config.AWSProviderVersion (DefaultAWSProviderVersion
+// if empty) so redeploys do not pull an incompatible provider
+func (g *Generator) generateVersionsConfig(config *types.TerraformConfig) error {
+	awsVersion := config.AWSProviderVersion
+	if awsVersion == "" {
+		awsVersion = DefaultAWSProviderVersion
+	}
+
+	var providers strings.Builder
+	fmt.Fprintf(&providers, `    aws = {
+      source  = "hashicorp/aws"
+      version = %s
+    }
+`, hclString(awsVersion))
+	if config.Strategy == "kubernetes" {
+		fmt.Fprintf(&providers, `    kubernetes = {
+      source  = "hashicorp/kubernetes"
+      version = %s
+    }
+`, hclString(kubernetesProviderVersion))
+	}
+	if config.Strategy == "kubernetes" && UsesLBController(config) {
+		fmt.Fprintf(&providers, `    helm = {
+      source  = "hashicorp/helm"
+      version = %s
+    }
+`, hclString(helmProviderVersion))
+	}
+
+	versionsTF := fmt.Sprintf(`# Provider versions for %s
+# Generated by SCAI
+
+terraform {
+  required_version = ">= 1.0"
+  required_providers {
+%s  }
+}
+`, config.AppName, providers.String())
+
+	return os.WriteFile(filepath.Join(g.outputDir, "versions.tf"), []byte(versionsTF), 0o644)
+}
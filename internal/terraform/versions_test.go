@@ -0,0 +1,62 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Smana/scai/internal/types"
+)
+
+func TestValidateProviderVersion(t *testing.T) {
+	tests := []struct {
+		constraint string
+		wantErr    bool
+	}{
+		{DefaultAWSProviderVersion, false},
+		{"6.12.0", false},
+		{">= 5.80, < 7.0", false},
+		{"= 6.0.0-beta1", false},
+		{"latest", true},
+		{"~> 6.x", true},
+		{"", true},
+	}
+
+	for _, tt := range tests {
+		if err := ValidateProviderVersion(tt.constraint); (err != nil) != tt.wantErr {
+			t.Errorf("ValidateProviderVersion(%q) error = %v, wantErr %v", tt.constraint, err, tt.wantErr)
+		}
+	}
+}
+
+func TestGenerateVersionsConfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		config     *types.TerraformConfig
+		want       string
+		kubernetes bool
+	}{
+		{"default", &types.TerraformConfig{AppName: "app", Strategy: "vm"}, `version = "~> 6.0"`, false},
+		{"pinned", &types.TerraformConfig{AppName: "app", Strategy: "kubernetes", AWSProviderVersion: "6.12.0"}, `version = "6.12.0"`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := NewGenerator(dir, false).generateVersionsConfig(tt.config); err != nil {
+				t.Fatalf("generateVersionsConfig() error = %v", err)
+			}
+			content, err := os.ReadFile(filepath.Join(dir, "versions.tf"))
+			if err != nil {
+				t.Fatalf("versions.tf not written: %v", err)
+			}
+			if !strings.Contains(string(content), tt.want) {
+				t.Errorf("versions.tf = %s, want %s", content, tt.want)
+			}
+			if got := strings.Contains(string(content), "hashicorp/kubernetes"); got != tt.kubernetes {
+				t.Errorf("versions.tf requires the kubernetes provider = %v, want %v", got, tt.kubernetes)
+			}
+		})
+	}
+}
@@ -6,16 +6,29 @@ type Analysis struct {
 	RepoPath         string
 	AppDir           string // Subdirectory containing the main application code (relative to RepoPath)
 	CommitSHA        string // Git commit SHA (if cloned from Git)
+	LocalSource      bool   // Analyzed from a zip archive or a local directory: RepoURL is a path, RepoPath the copy to build
 	Framework        string
+	FrameworkVersion string // Locked framework version (Go version for go), empty if unknown
 	Language         string
 	PackageManager   string // Package manager: "pip", "poetry", "uv", "pipenv", "npm", "yarn", etc.
 	Dependencies     []string
 	StartCommand     string
+	BuildCommand     string // Build step run before the start command (e.g. npm run build), empty if none
+	EntryPoint       string // Python entry module, with its app object when found (e.g. myapp.wsgi:app)
 	Port             int
+	PortDetected     bool   // Port found in the code or the Dockerfile, rather than the framework default
+	HealthCheckPath  string // Health endpoint found in route definitions, "/" if none
+	BindsLocalhost   bool   // App listens on 127.0.0.1/localhost only, unreachable once deployed
 	EnvVars          map[string]string
 	HasDockerfile    bool
+	DockerfilePorts  []int // Container ports of the Dockerfile EXPOSE instructions, in order
 	HasDockerCompose bool
-	Verbose          bool // For detailed logging
+	ComposeServices  []string // docker-compose service names
+	ComposeImages    []string // docker-compose image names (without registry or tag)
+	ComposePorts     []int    // Container ports exposed by docker-compose services
+	RequiresDatabase bool     // Database driver or database service detected (postgres, mysql, mongodb, ...)
+	RequiresCache    bool     // Cache client or cache service detected (redis, memcached)
+	Verbose          bool     // For detailed logging
 }
 
 // TerraformConfig represents generated Terraform configuration
@@ -23,6 +36,7 @@ type TerraformConfig struct {
 	Path         string
 	Directory    string
 	Strategy     string
+	IacEngine    string // Infrastructure as code engine: "terraform" (empty) or "pulumi"
 	AppName      string
 	Region       string
 	Framework    string
@@ -30,17 +44,62 @@ type TerraformConfig struct {
 	Port         int
 	RepoURL      string
 	AppDir       string // Subdirectory containing the main application code
+	SourcePath   string // Analyzed copy of a zip archive or local directory, built instead of cloning RepoURL
 	StartCommand string
+	BuildCommand string // Run once after installing the dependencies, empty if none
 	EnvVars      map[string]string
 
+	// Container image (kubernetes, container Lambda) built and pushed to ECR
+	CommitSHA     string // Commit checked out for the build, its short SHA tagging the image (latest when empty)
+	HasDockerfile bool   // Build the EKS image from the repository Dockerfile rather than a generated one
+
+	HealthCheckPath string // Load balancer health check path ("/" if empty)
+
+	// Resource tagging
+	DeploymentID string            // SCAI deployment ID (tagged as scia:deployment-id)
+	Tags         map[string]string // Additional user-supplied tags applied to all resources
+
+	// Custom domain (HTTPS)
+	Domain         string // Fully qualified domain name, empty to use the AWS hostname
+	HostedZoneID   string // Route53 hosted zone containing Domain
+	CertificateARN string // Existing ACM certificate, empty to request a new one
+
+	// RDS database
+	DatabaseEngine        string // "postgres" or "mysql", empty for no database
+	DatabaseInstanceClass string
+	DatabaseStorage       int // Allocated storage in GB
+
 	// EC2 sizing
 	InstanceType string
 	VolumeSize   int
 
+	// EC2 image and bootstrap
+	AMI      string // Custom AMI, empty for the latest Amazon Linux 2023
+	UserData string // Custom user-data script replacing the generated one, empty for none
+
+	// Existing network (vm and kubernetes): the default VPC (vm) or a new VPC (kubernetes) when empty
+	VPCID     string
+	SubnetIDs []string // Subnets of VPCID, empty for all of them
+
+	// AWS provider version constraint, pinned for reproducible redeploys (default if empty)
+	AWSProviderVersion string
+
+	// SSH access to the EC2 instances, none without key pair
+	SSHKeyName   string // Existing EC2 key pair
+	SSHPublicKey string // Public key of a key pair created with the deployment (authorized_keys format)
+
 	// Lambda sizing
 	LambdaMemory              int
 	LambdaTimeout             int
 	LambdaReservedConcurrency int
+	LambdaArchitecture        string // "x86_64" (default) or "arm64"
+	LambdaContainer           bool   // Container image in ECR instead of a zip package
+
+	// API Gateway (serverless): CORS lists default to "*" when empty
+	CORSAllowOrigins []string
+	CORSAllowMethods []string
+	CORSAllowHeaders []string
+	AuthorizerARN    string // Lambda authorizer of the routes, empty for none
 
 	// EKS sizing
 	EKSNodeType       string
@@ -48,15 +107,35 @@ type TerraformConfig struct {
 	EKSMaxNodes       int
 	EKSDesiredNodes   int
 	EKSNodeVolumeSize int
+	EKSFargate        bool              // Fargate profile instead of a managed node group
+	EKSVersion        string            // Kubernetes version (e.g. 1.33), empty for the default
+	EKSNATGateway     string            // VPC NAT gateways: "single" (default), "per-az" or "none"
+	EKSAddons         map[string]string // Managed add-on name -> version ("" for the most recent)
+	AppPolicyARNs     []string          // IAM policies of the application role (Pod Identity, IRSA on Fargate), nil for the defaults
+	EKSAdminARNs      []string          // IAM users and roles granted cluster admin besides the creator (access entries)
+	Replicas          int               // Kubernetes Deployment replicas, 0 for DefaultReplicas
+	K8sCPURequest     string            // Container CPU request (e.g. 250m), empty for the default
+	K8sCPULimit       string            // Container CPU limit (e.g. 1), empty for the default
+	K8sMemoryRequest  string            // Container memory request (e.g. 256Mi), empty for the default
+	K8sMemoryLimit    string            // Container memory limit (e.g. 1Gi), empty for the default
+
+	// Autoscaling (vm and kubernetes)
+	AutoscaleTargetCPU int // Target average CPU utilization in percent, 0 for no autoscaling policy
+
+	LogRetentionDays int // Retention of the CloudWatch log groups, 0 for the strategy default
+
+	AWSRoleARN string // IAM role assumed by the AWS provider (--aws-role-arn), empty for none
 }
 
 // DeploymentResult represents deployment outcome
 type DeploymentResult struct {
+	DeploymentID  string
 	Status        string
 	Strategy      string
 	Region        string
 	Outputs       map[string]string
 	TerraformDir  string
+	PlanOutDir    string // Set when the Terraform was exported with --plan-out instead of applied
 	Logs          []string
 	Warnings      []string
 	Optimizations []string
@@ -64,46 +143,49 @@ type DeploymentResult struct {
 
 // DeploymentRule represents a heuristic decision rule
 type DeploymentRule struct {
-	Name           string
-	Priority       int
-	Description    string
-	Conditions     RuleConditions
-	Recommendation string
-	InstanceType   string
-	Reason         string
+	Name           string         `yaml:"name"`
+	Priority       int            `yaml:"priority"`
+	Description    string         `yaml:"description"`
+	Conditions     RuleConditions `yaml:"conditions"`
+	Recommendation string         `yaml:"recommendation"`
+	InstanceType   string         `yaml:"instance_type"`
+	Reason         string         `yaml:"reason"`
 }
 
 // RuleConditions defines conditions for a deployment rule
 type RuleConditions struct {
-	Framework        []string
-	Language         string
-	MinDependencies  int
-	MaxDependencies  int
-	HasDockerfile    *bool
-	HasDockerCompose *bool
+	Framework        []string `yaml:"framework"`
+	Language         string   `yaml:"language"`
+	MinDependencies  int      `yaml:"min_dependencies"`
+	MaxDependencies  int      `yaml:"max_dependencies"`
+	HasDockerfile    *bool    `yaml:"has_dockerfile"`
+	HasDockerCompose *bool    `yaml:"has_docker_compose"`
+	MinServices      int      `yaml:"min_services"` // Minimum number of docker-compose services
+	RequiresDatabase *bool    `yaml:"requires_database"`
 }
 
 // DeploymentRules contains all deployment decision rules
 type DeploymentRules struct {
-	Version       string
-	Rules         []DeploymentRule
-	InstanceTypes map[string]InstanceTypeInfo
-	Optimizations map[string]FrameworkOptimization
+	Version       string                           `yaml:"version"`
+	Mode          string                           `yaml:"mode"` // Evaluation mode: "first-match" (default) or "scoring"
+	Rules         []DeploymentRule                 `yaml:"rules"`
+	InstanceTypes map[string]InstanceTypeInfo      `yaml:"instance_types"`
+	Optimizations map[string]FrameworkOptimization `yaml:"optimizations"`
 }
 
 // InstanceTypeInfo contains EC2 instance type details
 type InstanceTypeInfo struct {
-	VCPU        int
-	MemoryGB    int
-	CostPerHour float64
-	UseCases    []string
+	VCPU        int      `yaml:"vcpu"`
+	MemoryGB    int      `yaml:"memory_gb"`
+	CostPerHour float64  `yaml:"cost_per_hour"`
+	UseCases    []string `yaml:"use_cases"`
 }
 
 // FrameworkOptimization contains framework-specific deployment optimizations
 type FrameworkOptimization struct {
-	ProductionServer   string
-	Workers            string
-	RecommendedPorts   []int
-	AdditionalPackages []string
-	Notes              []string
+	ProductionServer   string   `yaml:"production_server"`
+	Workers            string   `yaml:"workers"`
+	RecommendedPorts   []int    `yaml:"recommended_ports"`
+	AdditionalPackages []string `yaml:"additional_packages"`
+	Notes              []string `yaml:"notes"`
 }
@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pterm/pterm"
+
+	"github.com/Smana/scai/internal/llm"
+	"github.com/Smana/scai/internal/types"
+)
+
+// analysisField is a detected property of the application, suspect when it looks wrong
+type analysisField struct {
+	Name    string
+	Value   string
+	Suspect bool
+}
+
+// analysisFields returns the detected properties worth checking before deploying
+func analysisFields(analysis *types.Analysis) []analysisField {
+	unknown := func(value string) bool { return value == "" || value == "unknown" }
+	orNone := func(value string) string {
+		if value == "" {
+			return "none detected"
+		}
+		return value
+	}
+
+	port := fmt.Sprintf("%d", analysis.Port)
+	if !analysis.PortDetected {
+		port += " (framework default, not found in the code)"
+	}
+	dockerfile := "no"
+	if analysis.HasDockerfile {
+		dockerfile = "yes"
+	}
+
+	fields := []analysisField{
+		{"Framework", orNone(llm.DescribeFramework(analysis)), unknown(analysis.Framework)},
+		{"Language", orNone(analysis.Language), unknown(analysis.Language)},
+		{"Package manager", orNone(analysis.PackageManager), false},
+		{"Port", port, !analysis.PortDetected},
+		{"Start command", orNone(analysis.StartCommand), analysis.StartCommand == ""},
+	}
+	if analysis.BuildCommand != "" {
+		fields = append(fields, analysisField{"Build command", analysis.BuildCommand, false})
+	}
+	if analysis.EntryPoint != "" {
+		fields = append(fields, analysisField{"Entry point", analysis.EntryPoint, false})
+	}
+	return append(fields, analysisField{"Dockerfile", dockerfile, false})
+}
+
+// DisplayAnalysisSummary renders what was detected about the application, highlighting
+// the detections that look wrong so they can be fixed before provisioning
+func DisplayAnalysisSummary(analysis *types.Analysis) {
+	fields := analysisFields(analysis)
+
+	var lines []string
+	suspect := false
+	for _, field := range fields {
+		value := field.Value
+		if field.Suspect {
+			value = pterm.Yellow("⚠ " + value)
+			suspect = true
+		}
+		lines = append(lines, fmt.Sprintf("%s %s", pterm.LightCyan(field.Name+":"), value))
+	}
+
+	pterm.DefaultBox.WithTitle("Detected Application").Println(strings.Join(lines, "\n"))
+	if suspect {
+		pterm.Warning.Println("Check the highlighted detections: if one is wrong, cancel and fix the repository (or use --app-dir) before provisioning")
+	}
+	pterm.Println()
+}
@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pterm/pterm"
+
+	"github.com/Smana/scai/internal/analyzer"
+)
+
+// SelectApp prompts the user to choose one of the applications detected in a monorepo
+// Returns: selected app directory (relative to the repository root), error
+func SelectApp(candidates []analyzer.AppCandidate) (string, error) {
+	options := make([]string, 0, len(candidates))
+	dirs := make(map[string]string, len(candidates))
+	for _, candidate := range candidates {
+		option := fmt.Sprintf("%s (%s)", candidate.Dir, candidate.Framework)
+		options = append(options, option)
+		dirs[option] = candidate.Dir
+	}
+
+	pterm.Info.Printf("Found %d applications in this repository (use --app-dir to skip this prompt)\n", len(candidates))
+
+	selected, err := pterm.DefaultInteractiveSelect.
+		WithDefaultText("Select the application to deploy").
+		WithOptions(options).
+		Show()
+	if err != nil {
+		return "", fmt.Errorf("app selection prompt failed: %w", err)
+	}
+
+	return dirs[selected], nil
+}
+
+// SelectOllamaModel prompts the user to pull the configured Ollama model, missing from the
+// container, or to reuse one of the models already pulled
+// Returns: model to use (model itself to pull it), error
+func SelectOllamaModel(model string, available []string) (string, error) {
+	pullOption := fmt.Sprintf("Pull %s", model)
+	options := []string{pullOption}
+	for _, name := range available {
+		options = append(options, "Use "+name)
+	}
+
+	pterm.Info.Printf("Model %s is not pulled in the Ollama container, %d other model(s) are\n", model, len(available))
+
+	selected, err := pterm.DefaultInteractiveSelect.
+		WithDefaultText("Select the model to use").
+		WithOptions(options).
+		WithDefaultOption(pullOption).
+		Show()
+	if err != nil {
+		return "", fmt.Errorf("model selection prompt failed: %w", err)
+	}
+
+	if selected == pullOption {
+		return model, nil
+	}
+	return strings.TrimPrefix(selected, "Use "), nil
+}
@@ -1,7 +1,10 @@
 package ui
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"strings"
 
 	"github.com/pterm/pterm"
 )
@@ -36,6 +39,17 @@ func ConfirmDeployment(plan *DeploymentPlan, skipConfirmation bool) (bool, error
 	return result, nil
 }
 
+// WritePlanJSON writes the plan as indented JSON, for automations asserting on it
+// (--plan-format json)
+func WritePlanJSON(w io.Writer, plan *DeploymentPlan) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(plan); err != nil {
+		return fmt.Errorf("failed to encode plan: %w", err)
+	}
+	return nil
+}
+
 // DisplayPlanTable renders a beautiful table showing the deployment plan
 func DisplayPlanTable(plan *DeploymentPlan) error {
 	// Display header
@@ -127,5 +141,45 @@ func DisplayPlanTable(plan *DeploymentPlan) error {
 		pterm.Warning.Println("⚠️  EKS clusters incur charges (~$0.10/hour for control plane + node costs)")
 	}
 
+	// Display pre-flight warnings
+	for _, warning := range plan.Warnings {
+		pterm.Warning.Println(warning)
+	}
+
+	displayCost(plan)
+	displayDuration(plan)
+
 	return nil
 }
+
+// displayDuration shows the estimated provisioning time and where it comes from
+func displayDuration(plan *DeploymentPlan) {
+	source := "typical " + plan.Strategy + " deployment"
+	if plan.Duration.Samples > 0 {
+		source = fmt.Sprintf("median of %d past %s deployments", plan.Duration.Samples, plan.Strategy)
+	}
+	pterm.Info.Printf("⏱️  Estimated deploy time: %s (%s)\n", plan.Duration, source)
+}
+
+// displayCost shows the estimated monthly cost and, over budget, cheaper sizing options
+func displayCost(plan *DeploymentPlan) {
+	if plan.Cost.UsageBased() {
+		pterm.Info.Println("💰 Estimated cost: billed per request (no fixed monthly charges)")
+	} else {
+		pterm.Info.Printf("💰 Estimated cost: ~$%.0f/month (us-east-1 on-demand prices, excluding traffic and data transfer)\n", plan.Cost.MonthlyUSD())
+	}
+	if len(plan.Cost.Unpriced) > 0 {
+		pterm.Warning.Printf("No price known for %s: not included in the estimate\n", strings.Join(plan.Cost.Unpriced, ", "))
+	}
+
+	if !plan.OverBudget() {
+		return
+	}
+	pterm.Warning.Printf("Estimated cost ~$%.0f/month exceeds the budget of $%.0f/month\n", plan.Cost.MonthlyUSD(), plan.BudgetUSD)
+	if len(plan.Cheaper) > 0 {
+		pterm.Println("  Cheaper options:")
+		for _, option := range plan.Cheaper {
+			pterm.Printf("  • %s\n", option)
+		}
+	}
+}
@@ -8,12 +8,14 @@ import (
 	"github.com/Smana/scai/internal/deployer"
 	"github.com/Smana/scai/internal/llm"
 	"github.com/Smana/scai/internal/parser"
+	"github.com/Smana/scai/internal/terraform"
 	"github.com/Smana/scai/internal/types"
 )
 
 // ConfirmOrModify shows the plan and allows confirmation or modification
 func ConfirmOrModify(plan *DeploymentPlan, analysis *types.Analysis, config *deployer.DeployConfig, llmClient *llm.Client, autoApprove bool) (bool, *deployer.DeployConfig, error) {
-	// Display the plan
+	// Display what was detected, then the plan
+	DisplayAnalysisSummary(analysis)
 	if err := DisplayPlanTable(plan); err != nil {
 		return false, config, fmt.Errorf("failed to display plan: %w", err)
 	}
@@ -67,12 +69,50 @@ func ConfirmOrModify(plan *DeploymentPlan, analysis *types.Analysis, config *dep
 		}
 
 		// Apply modifications to config
+		previousConfig := *config
+		previousRegion := config.AWSRegion
 		parser.ApplyConfig(config, modifiedConfig)
 
+		// Never plan a Lambda sizing AWS would reject
+		if config.Strategy == "serverless" {
+			if err := terraform.ValidateLambdaSizing(config.LambdaMemory, config.LambdaTimeout); err != nil {
+				*config = previousConfig
+				pterm.Warning.Printf("Modification ignored: %v\n", err)
+				pterm.Println()
+				continue
+			}
+		}
+
+		// ACM certificates are regional: request a new one after a region change
+		if config.AWSRegion != previousRegion {
+			config.CertificateARN = ""
+		}
+
+		// RDS is provisioned in the app's VPC, which Lambda deployments don't have
+		if config.Strategy == "serverless" && config.DatabaseEngine != "" {
+			pterm.Warning.Println("Database removed: --with-database is not supported for serverless deployments")
+			config.DatabaseEngine = ""
+		}
+
 		// Rebuild plan with modified config
 		appName := plan.AppName
+		previous := plan
 		plan = BuildDeploymentPlan(config.Strategy, config.AWSRegion, appName, analysis, config)
 
+		// Quota warnings only apply to the strategy and region they were checked for
+		if plan.Strategy == previous.Strategy && plan.Region == previous.Region {
+			plan.Warnings = previous.Warnings
+		}
+
+		// --strict-budget: never plan above the budget
+		if config.StrictBudget && plan.OverBudget() {
+			pterm.Warning.Printf("Modification ignored: estimated cost ~$%.0f/month exceeds the budget of $%.0f/month\n", plan.Cost.MonthlyUSD(), plan.BudgetUSD)
+			pterm.Println()
+			*config = previousConfig
+			plan = previous
+			continue
+		}
+
 		// Show updated plan
 		pterm.Println()
 		pterm.Success.Println("✓ Plan updated based on your request")
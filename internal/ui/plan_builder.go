@@ -2,8 +2,12 @@ package ui
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 
+	"github.com/Smana/scai/internal/cost"
 	"github.com/Smana/scai/internal/deployer"
+	"github.com/Smana/scai/internal/terraform"
 	"github.com/Smana/scai/internal/types"
 )
 
@@ -28,23 +32,94 @@ func BuildDeploymentPlan(strategy, region, appName string, analysis *types.Analy
 		plan.Resources = buildEC2Resources(appName, region, analysis, config)
 	}
 
+	if config.DatabaseEngine != "" {
+		plan.Resources = append(plan.Resources, buildDatabaseResource(appName, config))
+	}
+
+	if config.Domain != "" {
+		plan.Resources = append(plan.Resources, buildDomainResource(strategy, config))
+	}
+
+	plan.Cost = cost.EstimateMonthly(config)
+	plan.BudgetUSD = config.BudgetUSD
+	if plan.OverBudget() {
+		plan.Cheaper = cost.Suggestions(config, config.BudgetUSD)
+	}
+	plan.Duration = deployer.EstimateDuration(config)
+
 	return plan
 }
 
+// buildDatabaseResource builds the RDS database entry
+func buildDatabaseResource(appName string, config *deployer.DeployConfig) ResourceConfig {
+	dbResource := ResourceConfig{
+		Type:       "RDS Database",
+		Name:       fmt.Sprintf("%s-db", appName),
+		Parameters: make(map[string]string),
+		Important:  true,
+	}
+	dbResource.AddParameter("Engine", config.DatabaseEngine)
+	dbResource.AddParameter("Instance Class", config.DatabaseInstanceClass)
+	dbResource.AddParameter("Storage", fmt.Sprintf("%d GB (GP3, encrypted)", config.DatabaseStorage))
+	dbResource.AddParameter("Access", "Private (VPC only)")
+	dbResource.AddParameter("Connection", "DATABASE_URL environment variable")
+	dbResource.AddParameter("Backups", "7 days")
+	if config.DatabaseInstanceClass == "db.t3.micro" {
+		dbResource.AddParameter("Cost Note", "~$15/month + storage (free tier eligible)")
+	} else {
+		dbResource.AddParameter("Cost Note", "Billed hourly by instance class + storage")
+	}
+
+	return dbResource
+}
+
+// buildDomainResource builds the custom domain entry (certificate, DNS record, HTTPS entry point)
+func buildDomainResource(strategy string, config *deployer.DeployConfig) ResourceConfig {
+	domainResource := ResourceConfig{
+		Type:       "Custom Domain (HTTPS)",
+		Name:       config.Domain,
+		Parameters: make(map[string]string),
+		Important:  true,
+	}
+	domainResource.AddParameter("Hosted Zone", config.HostedZoneID)
+	if config.CertificateARN != "" {
+		domainResource.AddParameter("Certificate", "Existing ACM certificate (issued)")
+	} else {
+		domainResource.AddParameter("Certificate", "New ACM certificate (DNS validation)")
+	}
+
+	switch strategy {
+	case "vm":
+		domainResource.AddParameter("HTTPS Endpoint", "Application Load Balancer (HTTP redirected)")
+	case "serverless":
+		domainResource.AddParameter("HTTPS Endpoint", "API Gateway regional custom domain")
+	case "kubernetes":
+		domainResource.AddParameter("HTTPS Endpoint", "Service load balancer (TLS on port 443)")
+	}
+	domainResource.AddParameter("DNS Record", "Route53 alias (A)")
+	domainResource.AddParameter("URL", fmt.Sprintf("https://%s/", config.Domain))
+
+	return domainResource
+}
+
 // buildEC2Resources builds resource list for EC2/VM deployment
 func buildEC2Resources(appName, region string, analysis *types.Analysis, config *deployer.DeployConfig) []ResourceConfig {
 	resources := []ResourceConfig{}
 
 	// VPC
-	vpcResource := ResourceConfig{
-		Type:       "VPC",
-		Name:       "Default VPC",
-		Parameters: make(map[string]string),
-		Important:  false,
+	if config.VPCID != "" {
+		resources = append(resources, buildExistingVPCResource(region, config))
+	} else {
+		vpcResource := ResourceConfig{
+			Type:       "VPC",
+			Name:       "Default VPC",
+			Parameters: make(map[string]string),
+			Important:  false,
+		}
+		vpcResource.AddParameter("Type", "Default VPC")
+		vpcResource.AddParameter("Region", region)
+		resources = append(resources, vpcResource)
 	}
-	vpcResource.AddParameter("Type", "Default VPC")
-	vpcResource.AddParameter("Region", region)
-	resources = append(resources, vpcResource)
 
 	// Security Group
 	sgResource := ResourceConfig{
@@ -53,7 +128,11 @@ func buildEC2Resources(appName, region string, analysis *types.Analysis, config
 		Parameters: make(map[string]string),
 		Important:  true,
 	}
-	sgResource.AddParameter("Ingress Ports", fmt.Sprintf("22 (SSH), %d (App)", analysis.Port))
+	if sshEnabled(config) {
+		sgResource.AddParameter("Ingress Ports", fmt.Sprintf("22 (SSH), %d (App)", analysis.Port))
+	} else {
+		sgResource.AddParameter("Ingress Ports", fmt.Sprintf("%d (App)", analysis.Port))
+	}
 	sgResource.AddParameter("Egress", "All traffic")
 	sgResource.AddParameter("CIDR", "0.0.0.0/0")
 	resources = append(resources, sgResource)
@@ -65,7 +144,12 @@ func buildEC2Resources(appName, region string, analysis *types.Analysis, config
 		Parameters: make(map[string]string),
 		Important:  true,
 	}
-	asgResource.AddParameter("Min/Max/Desired", "1/1/1")
+	if config.AutoscaleTargetCPU > 0 {
+		asgResource.AddParameter("Min/Max/Desired", "1/3/1")
+		asgResource.AddParameter("Autoscaling", fmt.Sprintf("Target tracking (%d%% CPU)", config.AutoscaleTargetCPU))
+	} else {
+		asgResource.AddParameter("Min/Max/Desired", "1/1/1")
+	}
 	asgResource.AddParameter("Health Check Type", "EC2")
 	asgResource.AddParameter("Health Check Grace Period", "300s")
 	resources = append(resources, asgResource)
@@ -83,15 +167,58 @@ func buildEC2Resources(appName, region string, analysis *types.Analysis, config
 		Important:  true,
 	}
 	ec2Resource.AddParameter("Instance Type", instanceType)
-	ec2Resource.AddParameter("AMI", "Amazon Linux 2023 (latest)")
+	switch {
+	case config.AMI != "" && config.AMIName != "":
+		ec2Resource.AddParameter("AMI", fmt.Sprintf("%s (%s)", config.AMI, config.AMIName))
+	case config.AMI != "":
+		ec2Resource.AddParameter("AMI", config.AMI)
+	default:
+		ec2Resource.AddParameter("AMI", "Amazon Linux 2023 (latest)")
+	}
+	if config.UserDataPath != "" {
+		ec2Resource.AddParameter("User Data", fmt.Sprintf("Custom (%s)", config.UserDataPath))
+	}
 	ec2Resource.AddParameter("Volume Size", fmt.Sprintf("%d GB", config.EC2VolumeSize))
 	ec2Resource.AddParameter("Volume Type", "GP3 (encrypted)")
 	ec2Resource.AddParameter("Monitoring", "Enabled")
+	switch {
+	case config.SSHGenerateKey:
+		ec2Resource.AddParameter("SSH Access", "Enabled (new key pair, private key saved to ~/.scai/keys)")
+	case config.SSHKeyName != "":
+		ec2Resource.AddParameter("SSH Access", fmt.Sprintf("Enabled (key pair %s)", config.SSHKeyName))
+	default:
+		ec2Resource.AddParameter("SSH Access", "Disabled: no key pair, not SSH-accessible (SSM Session Manager only)")
+	}
 	resources = append(resources, ec2Resource)
 
 	return resources
 }
 
+// buildExistingVPCResource builds the entry of the existing VPC and subnets (--vpc-id), which
+// the deployment uses without managing them
+func buildExistingVPCResource(region string, config *deployer.DeployConfig) ResourceConfig {
+	vpcResource := ResourceConfig{
+		Type:       "VPC",
+		Name:       config.VPCID,
+		Parameters: make(map[string]string),
+		Important:  true,
+	}
+	vpcResource.AddParameter("Type", fmt.Sprintf("Using existing VPC %s (not created or destroyed)", config.VPCID))
+	vpcResource.AddParameter("Region", region)
+	if len(config.SubnetIDs) > 0 {
+		vpcResource.AddParameter("Subnets", strings.Join(config.SubnetIDs, ", "))
+	} else {
+		vpcResource.AddParameter("Subnets", "All subnets of the VPC")
+	}
+
+	return vpcResource
+}
+
+// sshEnabled reports whether the instances of config get a key pair for SSH access
+func sshEnabled(config *deployer.DeployConfig) bool {
+	return config.SSHKeyName != "" || config.SSHGenerateKey
+}
+
 // buildLambdaResources builds resource list for Lambda deployment
 func buildLambdaResources(appName, region string, analysis *types.Analysis, config *deployer.DeployConfig) []ResourceConfig {
 	resources := []ResourceConfig{}
@@ -108,7 +235,7 @@ func buildLambdaResources(appName, region string, analysis *types.Analysis, conf
 	resources = append(resources, iamResource)
 
 	// Lambda Function
-	runtime := detectRuntime(analysis.Language, analysis.Framework)
+	runtime, architecture := detectRuntime(analysis.Language, analysis.Framework, config.LambdaArchitecture)
 	lambdaResource := ResourceConfig{
 		Type:       "Lambda Function",
 		Name:       appName,
@@ -116,6 +243,12 @@ func buildLambdaResources(appName, region string, analysis *types.Analysis, conf
 		Important:  true,
 	}
 	lambdaResource.AddParameter("Runtime", runtime)
+	lambdaResource.AddParameter("Architecture", architecture)
+	if config.LambdaContainer {
+		lambdaResource.AddParameter("Package Type", "Container image (ECR)")
+	} else {
+		lambdaResource.AddParameter("Package Type", "Zip")
+	}
 	lambdaResource.AddParameter("Memory", fmt.Sprintf("%d MB", config.LambdaMemory))
 	lambdaResource.AddParameter("Timeout", fmt.Sprintf("%d seconds", config.LambdaTimeout))
 	if config.LambdaReservedConcurrency > 0 {
@@ -126,6 +259,24 @@ func buildLambdaResources(appName, region string, analysis *types.Analysis, conf
 	lambdaResource.AddParameter("Tracing", "X-Ray Active")
 	resources = append(resources, lambdaResource)
 
+	// ECR repository for the container image
+	if config.LambdaContainer {
+		ecrResource := ResourceConfig{
+			Type:       "ECR Repository",
+			Name:       terraform.ECRRepositoryName(appName, "serverless"),
+			Parameters: make(map[string]string),
+			Important:  false,
+		}
+		ecrResource.AddParameter("Image Tag", terraform.ImageTag(analysis.CommitSHA))
+		if architecture == "arm64" {
+			ecrResource.AddParameter("Image Platform", "linux/arm64")
+		} else {
+			ecrResource.AddParameter("Image Platform", "linux/amd64")
+		}
+		ecrResource.AddParameter("Scan on Push", "Enabled")
+		resources = append(resources, ecrResource)
+	}
+
 	// CloudWatch Log Group
 	logResource := ResourceConfig{
 		Type:       "CloudWatch Logs",
@@ -133,7 +284,7 @@ func buildLambdaResources(appName, region string, analysis *types.Analysis, conf
 		Parameters: make(map[string]string),
 		Important:  false,
 	}
-	logResource.AddParameter("Retention", "7 days")
+	logResource.AddParameter("Retention", fmt.Sprintf("%d days", terraform.LogRetentionDays("serverless", config.LogRetentionDays)))
 	resources = append(resources, logResource)
 
 	// API Gateway
@@ -145,18 +296,31 @@ func buildLambdaResources(appName, region string, analysis *types.Analysis, conf
 	}
 	apiResource.AddParameter("Protocol", "HTTP")
 	apiResource.AddParameter("Routes", "ANY / and ANY /{proxy+}")
-	apiResource.AddParameter("CORS", "Enabled (all origins)")
+	if terraform.CORSAllOrigins(config.CORSAllowOrigins) {
+		apiResource.AddParameter("CORS Origins", "All origins (development only)")
+	} else {
+		apiResource.AddParameter("CORS Origins", strings.Join(config.CORSAllowOrigins, ", "))
+	}
+	apiResource.AddParameter("CORS Methods", corsList(config.CORSAllowMethods))
+	apiResource.AddParameter("CORS Headers", corsList(config.CORSAllowHeaders))
+	if config.AuthorizerARN != "" {
+		apiResource.AddParameter("Authorizer", fmt.Sprintf("Lambda (%s)", terraform.LambdaFunctionName(config.AuthorizerARN)))
+	} else {
+		apiResource.AddParameter("Authorizer", "None (public)")
+	}
 	apiResource.AddParameter("Integration", "Lambda proxy")
 	resources = append(resources, apiResource)
 
 	return resources
 }
 
-// buildEKSResources builds resource list for EKS deployment
-func buildEKSResources(appName, region string, analysis *types.Analysis, config *deployer.DeployConfig) []ResourceConfig {
-	resources := []ResourceConfig{}
+// buildEKSVPCResource builds the entry of the VPC of the cluster: the existing VPC, or a new
+// one with public and private subnets
+func buildEKSVPCResource(appName, region string, config *deployer.DeployConfig) ResourceConfig {
+	if config.VPCID != "" {
+		return buildExistingVPCResource(region, config)
+	}
 
-	// VPC
 	vpcResource := ResourceConfig{
 		Type:       "VPC",
 		Name:       fmt.Sprintf("%s-vpc", appName),
@@ -167,8 +331,34 @@ func buildEKSResources(appName, region string, analysis *types.Analysis, config
 	vpcResource.AddParameter("Availability Zones", "2")
 	vpcResource.AddParameter("Private Subnets", "10.0.1.0/24, 10.0.2.0/24")
 	vpcResource.AddParameter("Public Subnets", "10.0.101.0/24, 10.0.102.0/24")
-	vpcResource.AddParameter("NAT Gateway", "Single (in public subnet)")
-	resources = append(resources, vpcResource)
+	switch config.EKSNATGateway {
+	case terraform.NATGatewayPerAZ:
+		vpcResource.AddParameter("NAT Gateway", "One per AZ (2, in public subnets)")
+	case terraform.NATGatewayNone:
+		vpcResource.AddParameter("NAT Gateway", "None (nodes in public subnets)")
+	default:
+		vpcResource.AddParameter("NAT Gateway", "Single (in public subnet)")
+	}
+
+	return vpcResource
+}
+
+// formatEKSAdmins lists the principals granted cluster admin: the identity deploying (as the
+// cluster creator) and the access entries of --eks-admin-arn
+func formatEKSAdmins(config *deployer.DeployConfig) string {
+	creator := "Deploying identity"
+	if config.AWSRoleARN != "" {
+		creator = config.AWSRoleARN
+	}
+	return strings.Join(append([]string{creator}, config.EKSAdminARNs...), ", ")
+}
+
+// buildEKSResources builds resource list for EKS deployment
+func buildEKSResources(appName, region string, analysis *types.Analysis, config *deployer.DeployConfig) []ResourceConfig {
+	resources := []ResourceConfig{}
+
+	// VPC
+	resources = append(resources, buildEKSVPCResource(appName, region, config))
 
 	// EKS Cluster
 	eksResource := ResourceConfig{
@@ -177,28 +367,108 @@ func buildEKSResources(appName, region string, analysis *types.Analysis, config
 		Parameters: make(map[string]string),
 		Important:  true,
 	}
-	eksResource.AddParameter("Kubernetes Version", "1.31")
+	eksResource.AddParameter("Kubernetes Version", config.EKSVersion)
 	eksResource.AddParameter("Endpoint Access", "Public")
 	eksResource.AddParameter("Cluster Logging", "API, Audit, Authenticator")
+	eksResource.AddParameter("Log Retention", fmt.Sprintf("%d days", terraform.LogRetentionDays("kubernetes", config.LogRetentionDays)))
 	eksResource.AddParameter("Encryption", "Secrets encrypted with KMS")
 	eksResource.AddParameter("Pod Identity", "Enabled")
+	eksResource.AddParameter("Add-ons", formatEKSAddons(config))
+	eksResource.AddParameter("Cluster Admins", formatEKSAdmins(config))
 	resources = append(resources, eksResource)
 
-	// EKS Node Group
-	nodeResource := ResourceConfig{
-		Type:       "EKS Managed Node Group",
-		Name:       fmt.Sprintf("%s-node-group", appName),
+	if config.EKSFargate {
+		// EKS Fargate Profile (no nodes to size)
+		fargateResource := ResourceConfig{
+			Type:       "EKS Fargate Profile",
+			Name:       fmt.Sprintf("%s-fargate", appName),
+			Parameters: make(map[string]string),
+			Important:  true,
+		}
+		fargateResource.AddParameter("Namespaces", "default, kube-system")
+		fargateResource.AddParameter("Subnets", "Private")
+		fargateResource.AddParameter("CoreDNS", "Runs on Fargate")
+		fargateResource.AddParameter("Billing", "Per pod vCPU and memory")
+		resources = append(resources, fargateResource)
+
+		// AWS Load Balancer Controller: the in-tree controller cannot target Fargate pods
+		lbControllerResource := ResourceConfig{
+			Type:       "Helm Release",
+			Name:       "aws-load-balancer-controller",
+			Parameters: make(map[string]string),
+			Important:  false,
+		}
+		lbControllerResource.AddParameter("Namespace", "kube-system")
+		lbControllerResource.AddParameter("IAM Role", fmt.Sprintf("%s-lb-controller (IRSA)", appName))
+		lbControllerResource.AddParameter("Used For", "NLB of the service, targeting the pod IPs")
+		resources = append(resources, lbControllerResource)
+	} else {
+		// EKS Node Group
+		nodeResource := ResourceConfig{
+			Type:       "EKS Managed Node Group",
+			Name:       fmt.Sprintf("%s-node-group", appName),
+			Parameters: make(map[string]string),
+			Important:  true,
+		}
+		nodeResource.AddParameter("Instance Type", config.EKSNodeType)
+		nodeResource.AddParameter("Min Nodes", fmt.Sprintf("%d", config.EKSMinNodes))
+		nodeResource.AddParameter("Max Nodes", fmt.Sprintf("%d", config.EKSMaxNodes))
+		nodeResource.AddParameter("Desired Nodes", fmt.Sprintf("%d", config.EKSDesiredNodes))
+		nodeResource.AddParameter("Volume Size", fmt.Sprintf("%d GB", config.EKSNodeVolumeSize))
+		nodeResource.AddParameter("Volume Type", "GP3 (encrypted)")
+		nodeResource.AddParameter("Capacity Type", "ON_DEMAND")
+		resources = append(resources, nodeResource)
+	}
+
+	// EBS CSI driver IAM role (Pod Identity)
+	if slices.Contains(terraform.EKSAddons(config.EKSAddons, config.EKSFargate, terraform.AppPodIdentity(config.AppPolicyARNs, config.EKSFargate)), terraform.EBSCSIDriverAddon) {
+		csiResource := ResourceConfig{
+			Type:       "IAM Role",
+			Name:       fmt.Sprintf("%s-ebs-csi", appName),
+			Parameters: make(map[string]string),
+			Important:  false,
+		}
+		csiResource.AddParameter("Service Account", "kube-system/ebs-csi-controller-sa")
+		csiResource.AddParameter("Policy", "AmazonEBSCSIDriverPolicy")
+		csiResource.AddParameter("Used For", "PersistentVolumeClaims (EBS volumes)")
+		resources = append(resources, csiResource)
+	}
+
+	// Application IAM role, bound to the pods service account
+	if len(config.AppPolicyARNs) > 0 {
+		appRoleResource := ResourceConfig{
+			Type:       "IAM Role",
+			Name:       fmt.Sprintf("%s-app", appName),
+			Parameters: make(map[string]string),
+			Important:  true,
+		}
+		appRoleResource.AddParameter("Service Account", fmt.Sprintf("default/%s", appName))
+		appRoleResource.AddParameter("Policies", strings.Join(config.AppPolicyARNs, ", "))
+		if terraform.AppPodIdentity(config.AppPolicyARNs, config.EKSFargate) {
+			appRoleResource.AddParameter("Binding", "EKS Pod Identity association")
+		} else {
+			appRoleResource.AddParameter("Binding", "IRSA (Fargate pods)")
+		}
+		resources = append(resources, appRoleResource)
+	}
+
+	// ECR repository of the application image, built from the repository
+	repository := terraform.ECRRepositoryName(appName, "kubernetes")
+	ecrResource := ResourceConfig{
+		Type:       "ECR Repository",
+		Name:       repository,
 		Parameters: make(map[string]string),
 		Important:  true,
 	}
-	nodeResource.AddParameter("Instance Type", config.EKSNodeType)
-	nodeResource.AddParameter("Min Nodes", fmt.Sprintf("%d", config.EKSMinNodes))
-	nodeResource.AddParameter("Max Nodes", fmt.Sprintf("%d", config.EKSMaxNodes))
-	nodeResource.AddParameter("Desired Nodes", fmt.Sprintf("%d", config.EKSDesiredNodes))
-	nodeResource.AddParameter("Volume Size", fmt.Sprintf("%d GB", config.EKSNodeVolumeSize))
-	nodeResource.AddParameter("Volume Type", "GP3 (encrypted)")
-	nodeResource.AddParameter("Capacity Type", "ON_DEMAND")
-	resources = append(resources, nodeResource)
+	ecrResource.AddParameter("Image Tag", terraform.ImageTag(analysis.CommitSHA))
+	if analysis.HasDockerfile {
+		ecrResource.AddParameter("Dockerfile", "Repository")
+	} else {
+		ecrResource.AddParameter("Dockerfile", fmt.Sprintf("Generated (%s base)", detectContainerImage(analysis.Language, analysis.Framework)))
+	}
+	ecrResource.AddParameter("Image Platform", "linux/amd64")
+	ecrResource.AddParameter("Scan on Push", "Enabled")
+	resources = append(resources, ecrResource)
 
 	// Kubernetes Deployment
 	deployResource := ResourceConfig{
@@ -207,13 +477,14 @@ func buildEKSResources(appName, region string, analysis *types.Analysis, config
 		Parameters: make(map[string]string),
 		Important:  true,
 	}
-	deployResource.AddParameter("Replicas", "2")
-	deployResource.AddParameter("Container Image", detectContainerImage(analysis.Language, analysis.Framework))
+	deployResource.AddParameter("Replicas", fmt.Sprintf("%d", terraform.Replicas(config.Replicas)))
+	deployResource.AddParameter("Container Image", fmt.Sprintf("%s:%s", repository, terraform.ImageTag(analysis.CommitSHA)))
 	deployResource.AddParameter("Container Port", fmt.Sprintf("%d", analysis.Port))
-	deployResource.AddParameter("CPU Request", "100m")
-	deployResource.AddParameter("Memory Request", "128Mi")
-	deployResource.AddParameter("CPU Limit", "500m")
-	deployResource.AddParameter("Memory Limit", "512Mi")
+	containerResources := config.K8sResources.WithDefaults()
+	deployResource.AddParameter("CPU Request", containerResources.CPURequest)
+	deployResource.AddParameter("Memory Request", containerResources.MemoryRequest)
+	deployResource.AddParameter("CPU Limit", containerResources.CPULimit)
+	deployResource.AddParameter("Memory Limit", containerResources.MemoryLimit)
 	resources = append(resources, deployResource)
 
 	// Kubernetes Service
@@ -226,24 +497,53 @@ func buildEKSResources(appName, region string, analysis *types.Analysis, config
 	svcResource.AddParameter("Type", "LoadBalancer")
 	svcResource.AddParameter("Port Mapping", fmt.Sprintf("80 → %d", analysis.Port))
 	svcResource.AddParameter("Protocol", "TCP")
-	svcResource.AddParameter("AWS Load Balancer", "Classic ELB (auto-created)")
+	if config.EKSFargate {
+		svcResource.AddParameter("AWS Load Balancer", "NLB with pod IP targets (AWS Load Balancer Controller)")
+	} else {
+		svcResource.AddParameter("AWS Load Balancer", "Classic ELB (auto-created)")
+	}
 	resources = append(resources, svcResource)
 
+	// HorizontalPodAutoscaler
+	if config.AutoscaleTargetCPU > 0 {
+		hpaResource := ResourceConfig{
+			Type:       "HorizontalPodAutoscaler",
+			Name:       fmt.Sprintf("%s-hpa", appName),
+			Parameters: make(map[string]string),
+			Important:  false,
+		}
+		minReplicas, maxReplicas := terraform.HPAReplicas(config.Replicas)
+		hpaResource.AddParameter("Min/Max Replicas", fmt.Sprintf("%d/%d", minReplicas, maxReplicas))
+		hpaResource.AddParameter("Target CPU", fmt.Sprintf("%d%%", config.AutoscaleTargetCPU))
+		hpaResource.AddParameter("Metrics", "metrics-server add-on")
+		resources = append(resources, hpaResource)
+	}
+
 	return resources
 }
 
-// detectRuntime determines the Lambda runtime from language and framework
-func detectRuntime(language, framework string) string {
+// detectRuntime determines the Lambda runtime from language and framework, and the
+// architecture to run it on (arm64 only when requested and supported by the runtime)
+func detectRuntime(language, framework, architecture string) (string, string) {
+	var runtime string
 	switch language {
 	case "python":
-		return "python3.12"
+		runtime = "python3.12"
 	case "javascript", "typescript":
-		return "nodejs20.x"
+		runtime = "nodejs20.x"
 	case "go":
-		return "provided.al2023"
+		runtime = "provided.al2023"
 	default:
-		return "python3.12"
+		runtime = "python3.12"
 	}
+
+	switch runtime {
+	case "python3.12", "nodejs20.x", "provided.al2023":
+		if architecture == "arm64" {
+			return runtime, "arm64"
+		}
+	}
+	return runtime, "x86_64"
 }
 
 // detectContainerImage determines the container image for EKS
@@ -255,7 +555,34 @@ func detectContainerImage(language, framework string) string {
 		return "node:20-alpine"
 	case "go":
 		return "golang:1.23-alpine"
+	case "java":
+		return "eclipse-temurin:21-jdk"
 	default:
 		return "nginx:alpine"
 	}
 }
+
+// corsList formats CORS methods or headers, "All" when unrestricted
+func corsList(values []string) string {
+	if len(values) == 0 || slices.Contains(values, terraform.CORSAny) {
+		return "All"
+	}
+	return strings.Join(values, ", ")
+}
+
+// formatEKSAddons lists the EKS managed add-ons with their pinned versions
+func formatEKSAddons(config *deployer.DeployConfig) string {
+	names := terraform.EKSAddons(config.EKSAddons, config.EKSFargate, terraform.AppPodIdentity(config.AppPolicyARNs, config.EKSFargate))
+	if len(names) == 0 {
+		return "None"
+	}
+
+	addons := make([]string, 0, len(names))
+	for _, name := range names {
+		if version := config.EKSAddons[name]; version != "" {
+			name += " (" + version + ")"
+		}
+		addons = append(addons, name)
+	}
+	return strings.Join(addons, ", ")
+}
@@ -1,19 +1,41 @@
 package ui
 
+import (
+	"github.com/Smana/scai/internal/cost"
+	"github.com/Smana/scai/internal/deployer"
+)
+
+// Plan formats accepted by --plan-format
+const (
+	PlanFormatTable = "table"
+	PlanFormatJSON  = "json"
+)
+
 // DeploymentPlan represents the complete deployment plan
 type DeploymentPlan struct {
-	Strategy  string
-	Region    string
-	AppName   string
-	Resources []ResourceConfig
+	Strategy  string           `json:"strategy"`
+	Region    string           `json:"region"`
+	AppName   string           `json:"app_name"`
+	Resources []ResourceConfig `json:"resources"`
+	Warnings  []string         `json:"warnings,omitempty"`   // Pre-flight warnings (e.g. service quotas near their limit)
+	Cost      cost.Estimate    `json:"cost"`                 // Estimated fixed monthly cost
+	BudgetUSD float64          `json:"budget_usd,omitempty"` // Monthly budget from the prompt (0 for none)
+	Cheaper   []string         `json:"cheaper,omitempty"`    // Cheaper sizing options when over budget
+
+	Duration deployer.DurationEstimate `json:"duration"` // Estimated provisioning time
+}
+
+// OverBudget reports whether the estimated monthly cost exceeds the budget
+func (p *DeploymentPlan) OverBudget() bool {
+	return p.BudgetUSD > 0 && p.Cost.MonthlyUSD() > p.BudgetUSD
 }
 
 // ResourceConfig represents a single resource to be created
 type ResourceConfig struct {
-	Type       string            // Resource type (e.g., "VPC", "EC2 Instance", "EKS Cluster")
-	Name       string            // Resource name
-	Parameters map[string]string // Configuration parameters
-	Important  bool              // Highlight important resources
+	Type       string            `json:"type"`       // Resource type (e.g., "VPC", "EC2 Instance", "EKS Cluster")
+	Name       string            `json:"name"`       // Resource name
+	Parameters map[string]string `json:"parameters"` // Configuration parameters
+	Important  bool              `json:"important"`  // Highlight important resources
 }
 
 // Add a parameter to a resource